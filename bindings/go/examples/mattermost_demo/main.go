@@ -57,7 +57,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create platform: %v", err)
 	}
-	defer platform.Destroy()
+	defer platform.Close()
 	fmt.Println("   ✓ Platform created\n")
 
 	// ========================================================================
@@ -4,4 +4,4 @@ go 1.25.1
 
 replace libcommunicator => ../../libcommunicator
 
-require libcommunicator v0.0.0-00010101000000-000000000000 // indirect
+require libcommunicator v0.0.0-00010101000000-000000000000
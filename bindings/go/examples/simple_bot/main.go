@@ -15,26 +15,46 @@ import (
 
 func main() {
 	// Parse command-line arguments
+	configPath := flag.String("config", "", "JSON configuration file; replaces the flags below")
 	serverURL := flag.String("server", "", "Mattermost server URL")
 	token := flag.String("token", "", "Authentication token")
 	loginID := flag.String("login", "", "Login ID (email/username)")
 	password := flag.String("password", "", "Password")
 	teamID := flag.String("team", "", "Team ID")
+	proxyURL := flag.String("proxy", "", "Proxy URL (http://, socks5:// or socks5h://)")
+	caCert := flag.String("ca-cert", "", "Extra CA certificate to trust (PEM file)")
+	insecure := flag.Bool("insecure", false, "Skip server certificate verification (test servers only)")
 	flag.Parse()
 
-	if *serverURL == "" || *teamID == "" {
-		fmt.Println("Usage: simple_bot -server <url> -team <team_id> [-token <token> | -login <login> -password <password>]")
+	config := comm.NewPlatformConfig(*serverURL).WithTeamID(*teamID).WithProxy(*proxyURL, "").WithCACert(*caCert)
+	if *insecure {
+		config.WithInsecureSkipVerify()
+	}
+	if *configPath != "" {
+		file, err := comm.LoadConfig(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to load configuration: %v", err)
+		}
+		config = &file.Platform
+	} else if *token != "" {
+		config.WithToken(*token)
+	} else {
+		config.WithPassword(*loginID, *password)
+	}
+
+	if config.Server == "" || config.TeamID == "" {
+		fmt.Println("Usage: simple_bot -config <file> | -server <url> -team <team_id> [-token <token> | -login <login> -password <password>]")
 		os.Exit(1)
 	}
 
-	if *token == "" && (*loginID == "" || *password == "") {
+	if config.Credentials["token"] == "" && (config.Credentials["login_id"] == "" || config.Credentials["password"] == "") {
 		fmt.Println("Error: Must provide either -token or both -login and -password")
 		os.Exit(1)
 	}
 
 	fmt.Println("=== Simple Bot Demo ===")
-	fmt.Printf("Server: %s\n", *serverURL)
-	fmt.Printf("Team ID: %s\n\n", *teamID)
+	fmt.Printf("Server: %s\n", config.Server)
+	fmt.Printf("Team ID: %s\n\n", config.TeamID)
 
 	// Initialize the library
 	if err := comm.Init(); err != nil {
@@ -46,20 +66,13 @@ func main() {
 	fmt.Printf("Library version: %s\n", version.Full)
 
 	// Create platform
-	platform, err := comm.NewMattermostPlatform(*serverURL)
+	platform, err := comm.NewMattermostPlatform(config.Server)
 	if err != nil {
 		log.Fatalf("Failed to create platform: %v", err)
 	}
-	defer platform.Destroy()
+	defer platform.Close()
 
 	// Connect
-	config := comm.NewPlatformConfig(*serverURL).WithTeamID(*teamID)
-	if *token != "" {
-		config.WithToken(*token)
-	} else {
-		config.WithPassword(*loginID, *password)
-	}
-
 	if err := platform.Connect(config); err != nil {
 		log.Fatalf("Failed to connect: %v", err)
 	}
@@ -98,60 +111,55 @@ func main() {
 	router := comm.NewEventRouter()
 
 	// Handle message posted events
-	router.OnMessagePosted(func(event *comm.Event) {
-		// Parse the message from event data
-		if data, ok := event.Data.(map[string]interface{}); ok {
-			senderID, _ := data["sender_id"].(string) // Changed from user_id to sender_id
-			channelID, _ := data["channel_id"].(string)
-			text, _ := data["text"].(string)
-
-			// Ignore messages from the bot itself
-			if senderID == currentUser.ID {
-				return
-			}
+	router.OnMessage(func(event comm.MessagePostedEvent) {
+		msg := event.Message
 
-			fmt.Printf("[MESSAGE] Channel: %s, User: %s, Text: %s\n", channelID, senderID, text)
-
-			// Simple echo bot: respond to messages that start with "!echo"
-			if strings.HasPrefix(text, "!echo ") {
-				response := strings.TrimPrefix(text, "!echo ")
-				_, err := platform.SendMessage(channelID, response)
-				if err != nil {
-					log.Printf("Failed to send message: %v", err)
-				} else {
-					fmt.Printf("[BOT] Echoed: %s\n", response)
-				}
+		// Ignore messages from the bot itself
+		if msg.SenderID == currentUser.ID {
+			return
+		}
+
+		fmt.Printf("[MESSAGE] Channel: %s, User: %s, Text: %s\n", msg.ChannelID, msg.SenderID, msg.Text)
+
+		// Simple echo bot: respond to messages that start with "!echo"
+		if strings.HasPrefix(msg.Text, "!echo ") {
+			response := strings.TrimPrefix(msg.Text, "!echo ")
+			_, err := platform.SendMessage(msg.ChannelID, response)
+			if err != nil {
+				log.Printf("Failed to send message: %v", err)
+			} else {
+				fmt.Printf("[BOT] Echoed: %s\n", response)
 			}
+		}
 
-			// Respond to "!hello"
-			if strings.TrimSpace(text) == "!hello" {
-				_, err := platform.SendMessage(channelID, fmt.Sprintf("Hello! I'm @%s, a bot powered by libcommunicator!", currentUser.Username))
-				if err != nil {
-					log.Printf("Failed to send message: %v", err)
-				}
+		// Respond to "!hello"
+		if strings.TrimSpace(msg.Text) == "!hello" {
+			_, err := platform.SendMessage(msg.ChannelID, fmt.Sprintf("Hello! I'm @%s, a bot powered by libcommunicator!", currentUser.Username))
+			if err != nil {
+				log.Printf("Failed to send message: %v", err)
 			}
+		}
 
-			// Respond to "!help"
-			if strings.TrimSpace(text) == "!help" {
-				helpText := `Available commands:
+		// Respond to "!help"
+		if strings.TrimSpace(msg.Text) == "!help" {
+			helpText := `Available commands:
 - !hello - Say hello
 - !echo <text> - Echo the text back
 - !help - Show this help message`
-				_, err := platform.SendMessage(channelID, helpText)
-				if err != nil {
-					log.Printf("Failed to send message: %v", err)
-				}
+			_, err := platform.SendMessage(msg.ChannelID, helpText)
+			if err != nil {
+				log.Printf("Failed to send message: %v", err)
 			}
 		}
 	})
 
 	// Handle user typing events
-	router.OnUserTyping(func(event *comm.Event) {
+	router.OnTyping(func(event comm.TypingEvent) {
 		fmt.Printf("[TYPING] User: %s, Channel: %s\n", event.UserID, event.ChannelID)
 	})
 
 	// Handle user status changes
-	router.OnUserStatusChanged(func(event *comm.Event) {
+	router.OnStatus(func(event comm.StatusEvent) {
 		fmt.Printf("[STATUS] User: %s, Status: %s\n", event.UserID, event.Status)
 	})
 
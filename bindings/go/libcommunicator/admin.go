@@ -0,0 +1,141 @@
+package libcommunicator
+
+/*
+#include <communicator.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"errors"
+)
+
+// SystemStats holds usage statistics of a whole server
+type SystemStats struct {
+	UserCount            int64 `json:"user_count"` // active accounts
+	InactiveUserCount    int64 `json:"inactive_user_count"`
+	TeamCount            int64 `json:"team_count"`
+	PublicChannelCount   int64 `json:"public_channel_count"`
+	PrivateChannelCount  int64 `json:"private_channel_count"`
+	PostCount            int64 `json:"post_count"`
+	DailyActiveUsers     int64 `json:"daily_active_users"`
+	MonthlyActiveUsers   int64 `json:"monthly_active_users"`
+	WebSocketConnections int64 `json:"websocket_connections"`
+	// Other holds platform-specific statistics not modeled above
+	Other map[string]int64 `json:"other"`
+}
+
+// AdminAPI groups the system console operations of a platform
+// Every call requires a system administrator account.
+type AdminAPI struct {
+	p *Platform
+}
+
+// Admin returns the system console operations for server operators
+func (p *Platform) Admin() *AdminAPI {
+	return &AdminAPI{p: p}
+}
+
+// GetConfig retrieves the server configuration, with secrets masked
+// The document is platform-specific; on Mattermost it is the config.json layout.
+func (a *AdminAPI) GetConfig() (map[string]interface{}, error) {
+	if !a.p.acquire() {
+		return nil, ErrClosed
+	}
+	defer a.p.release()
+
+	cstr := C.communicator_platform_admin_get_config(a.p.handle)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(C.GoString(cstr)), &config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// PatchConfig changes part of the server configuration and returns the full updated configuration
+// The patch is nested as in the configuration, e.g.
+// {"ServiceSettings": {"EnableCommands": true}}; settings left out keep their values.
+func (a *AdminAPI) PatchConfig(patch map[string]interface{}) (_ map[string]interface{}, err error) {
+	p := a.p
+	defer p.audit("AdminPatchConfig")(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("AdminPatchConfig"); err != nil {
+		return nil, err
+	}
+	if len(patch) == 0 {
+		return nil, errors.New("admin: empty configuration patch")
+	}
+
+	jsonBytes, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	cJSON, freeJSON := cStringFree(string(jsonBytes))
+	defer freeJSON()
+
+	cstr := C.communicator_platform_admin_patch_config(p.handle, cJSON)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(C.GoString(cstr)), &config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// GetSystemStats retrieves usage statistics of the whole server
+func (a *AdminAPI) GetSystemStats() (*SystemStats, error) {
+	if !a.p.acquire() {
+		return nil, ErrClosed
+	}
+	defer a.p.release()
+
+	cstr := C.communicator_platform_admin_get_system_stats(a.p.handle)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var stats SystemStats
+	if err := json.Unmarshal([]byte(C.GoString(cstr)), &stats); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// GetLogs retrieves a page of the server log, oldest first
+// Lines are returned raw; on Mattermost each line is a JSON object.
+func (a *AdminAPI) GetLogs(page, perPage uint32) ([]string, error) {
+	if !a.p.acquire() {
+		return nil, ErrClosed
+	}
+	defer a.p.release()
+
+	cstr := C.communicator_platform_admin_get_logs(a.p.handle, C.uint32_t(page), C.uint32_t(perPage))
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var lines []string
+	if err := json.Unmarshal([]byte(C.GoString(cstr)), &lines); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,69 @@
+package libcommunicator
+
+import "time"
+
+// AuditEntry describes one mutating call made through a Platform
+type AuditEntry struct {
+	// Operation is the Platform method that was called, e.g. "SendMessage"
+	Operation string
+	// Targets holds the IDs the call acted on, keyed like "channel_id" or "message_id"
+	Targets map[string]string
+	// Actor is the ID of the user the call was made as; empty before login
+	Actor    string
+	Time     time.Time
+	Duration time.Duration
+	// Err is the call's error, or nil if it succeeded. Calls rejected by
+	// read-only mode are reported with a ReadOnlyError.
+	Err error
+}
+
+// AuditHook receives an AuditEntry after every mutating call
+// It runs synchronously on the calling goroutine, so it should not block.
+// It runs once the call has finished with the platform and may call it.
+type AuditHook func(entry AuditEntry)
+
+// SetAuditHook installs a hook invoked for every mutating call (sends,
+// edits, deletions, reactions, acknowledgements, membership, channel and
+// team changes, uploads, slash commands, webhooks, bots, statuses,
+// preferences and read markers), so applications can keep their own audit
+// log of what the automation did. Pass nil to remove it.
+func (p *Platform) SetAuditHook(hook AuditHook) {
+	if hook == nil {
+		p.auditHook.Store(nil)
+		return
+	}
+	p.auditHook.Store(&hook)
+}
+
+// audit starts an audit entry for operation; targets are key/value pairs
+// The returned function completes the entry with the call's final error:
+//
+//	defer p.audit("DeleteMessage", "message_id", messageID)(&err)
+func (p *Platform) audit(operation string, targets ...string) func(*error) {
+	hook := p.auditHook.Load()
+	if hook == nil {
+		return func(*error) {}
+	}
+
+	entry := AuditEntry{
+		Operation: operation,
+		Targets:   make(map[string]string, len(targets)/2),
+		Time:      time.Now(),
+	}
+	for i := 0; i+1 < len(targets); i += 2 {
+		if targets[i+1] != "" {
+			entry.Targets[targets[i]] = targets[i+1]
+		}
+	}
+	// Resolve the actor before the call, since a call such as
+	// RevokeAllSessions can end the session it was made with
+	if info, err := p.GetConnectionInfo(); err == nil {
+		entry.Actor = info.UserID
+	}
+
+	return func(err *error) {
+		entry.Duration = time.Since(entry.Time)
+		entry.Err = *err
+		(*hook)(entry)
+	}
+}
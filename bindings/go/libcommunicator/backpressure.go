@@ -0,0 +1,244 @@
+package libcommunicator
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// BackpressurePolicy says what an EventStream does with an event while the
+// Events channel is full
+type BackpressurePolicy int
+
+const (
+	// BackpressureBlock stops polling until the consumer catches up (default)
+	// Events keep queueing in the library, which drops them once its own queue is full.
+	BackpressureBlock BackpressurePolicy = iota
+	// BackpressureDropOldest discards the oldest buffered event to make room
+	BackpressureDropOldest
+	// BackpressureDropNewest discards the new event
+	BackpressureDropNewest
+	// BackpressureSpill writes events to a temporary file and delivers them
+	// from it, in order, as the consumer catches up
+	BackpressureSpill
+)
+
+// EventStreamStats counts what an EventStream did under backpressure,
+// deduplication and prioritization
+type EventStreamStats struct {
+	// Dropped is the number of events discarded by a drop policy, or after
+	// a spill file failure
+	Dropped uint64
+	// Spilled is the number of events written to the spill file
+	Spilled uint64
+	// SpillPending is the number of spilled events not yet delivered
+	SpillPending int
+	// Duplicates is the number of events dropped by EventStreamOptions.DedupWindow
+	Duplicates uint64
+	// Prioritized is the number of events delivered on the priority lane
+	Prioritized uint64
+}
+
+// Stats returns the stream's counters
+// After Close it returns ErrClosed.
+func (s *EventStream) Stats() (EventStreamStats, error) {
+	if s.closed() {
+		return EventStreamStats{}, ErrClosed
+	}
+	stats := EventStreamStats{Dropped: s.dropped.Load(), Spilled: s.spilled.Load(), Duplicates: s.duplicates.Load(), Prioritized: s.prioritized.Load()}
+	s.spillMu.Lock()
+	if s.spill != nil {
+		stats.SpillPending = s.spill.pending
+	}
+	s.spillMu.Unlock()
+	return stats, nil
+}
+
+// deliver sends an event to the Events channel according to the stream's
+// backpressure policy; it returns false once the stream should stop
+func (s *EventStream) deliver(ctx context.Context, event *Event) bool {
+	switch s.opts.Backpressure {
+	case BackpressureDropNewest:
+		select {
+		case s.events <- event:
+		default:
+			s.dropped.Add(1)
+		}
+		return true
+
+	case BackpressureDropOldest:
+		for {
+			select {
+			case s.events <- event:
+				return true
+			default:
+			}
+			select {
+			case <-s.events:
+				s.dropped.Add(1)
+			default:
+				// The consumer took one meanwhile; try again
+			}
+		}
+
+	case BackpressureSpill:
+		s.drainSpill()
+		if s.spillPending() == 0 {
+			select {
+			case s.events <- event:
+				return true
+			default:
+			}
+		}
+		if err := s.spillEvent(event); err != nil {
+			s.dropped.Add(1)
+			s.reportError(err)
+		}
+		return true
+	}
+
+	select {
+	case s.events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-s.done:
+		return false
+	}
+}
+
+// reportError passes an error to the Errors channel, dropping it when full
+func (s *EventStream) reportError(err error) {
+	select {
+	case s.errors <- err:
+	default:
+	}
+}
+
+// eventSpill is the temporary file holding events the consumer had no room
+// for, one journal record per line so journaled events keep their ID, with
+// separate handles for appending and reading
+type eventSpill struct {
+	writer  *os.File
+	reader  *os.File
+	lines   *bufio.Reader
+	pending int
+}
+
+func (s *EventStream) spillPending() int {
+	s.spillMu.Lock()
+	defer s.spillMu.Unlock()
+	if s.spill == nil {
+		return 0
+	}
+	return s.spill.pending
+}
+
+// spillEvent appends an event to the spill file, creating it on first use
+func (s *EventStream) spillEvent(event *Event) error {
+	line, err := json.Marshal(journalRecord{ID: event.JournalID, Event: event})
+	if err != nil {
+		return fmt.Errorf("event stream: spill: %w", err)
+	}
+
+	s.spillMu.Lock()
+	defer s.spillMu.Unlock()
+
+	if s.spill == nil {
+		spill, err := openEventSpill(s.opts.SpillDir)
+		if err != nil {
+			return fmt.Errorf("event stream: spill: %w", err)
+		}
+		s.spill = spill
+	}
+	if _, err := s.spill.writer.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("event stream: spill: %w", err)
+	}
+	s.spill.pending++
+	s.spilled.Add(1)
+	return nil
+}
+
+// drainSpill moves spilled events to the Events channel while it has room
+func (s *EventStream) drainSpill() {
+	s.spillMu.Lock()
+	defer s.spillMu.Unlock()
+
+	spill := s.spill
+	for spill != nil && spill.pending > 0 && len(s.events) < cap(s.events) {
+		line, err := spill.lines.ReadBytes('\n')
+		if err != nil {
+			s.dropped.Add(uint64(spill.pending))
+			s.reportError(fmt.Errorf("event stream: spill: %w", err))
+			spill.pending = 0
+			break
+		}
+		spill.pending--
+
+		var record journalRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			s.dropped.Add(1)
+			s.reportError(fmt.Errorf("event stream: spill: %w", err))
+			continue
+		}
+		if record.Event == nil {
+			s.dropped.Add(1)
+			continue
+		}
+		record.Event.JournalID = record.ID
+		s.events <- record.Event
+	}
+
+	// Start over once everything was delivered, so the file doesn't grow forever
+	if spill != nil && spill.pending == 0 {
+		if err := spill.rewind(); err != nil {
+			s.reportError(fmt.Errorf("event stream: spill: %w", err))
+		}
+	}
+}
+
+func openEventSpill(dir string) (*eventSpill, error) {
+	writer, err := os.CreateTemp(dir, "libcommunicator-events-*.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	reader, err := os.Open(writer.Name())
+	if err != nil {
+		writer.Close()
+		os.Remove(writer.Name())
+		return nil, err
+	}
+	return &eventSpill{writer: writer, reader: reader, lines: bufio.NewReader(reader)}, nil
+}
+
+// rewind empties the file once every spilled event was read
+func (sp *eventSpill) rewind() error {
+	if err := sp.writer.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := sp.writer.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := sp.reader.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	sp.lines.Reset(sp.reader)
+	return nil
+}
+
+// closeSpill removes the spill file; undelivered events are discarded
+func (s *EventStream) closeSpill() {
+	s.spillMu.Lock()
+	defer s.spillMu.Unlock()
+
+	if s.spill == nil {
+		return
+	}
+	s.spill.reader.Close()
+	s.spill.writer.Close()
+	os.Remove(s.spill.writer.Name())
+	s.spill = nil
+}
@@ -0,0 +1,136 @@
+package libcommunicator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newBackpressureStream(t *testing.T, policy BackpressurePolicy, bufferSize int) *EventStream {
+	t.Helper()
+	return &EventStream{
+		platform: &Platform{},
+		events:   make(chan *Event, bufferSize),
+		errors:   make(chan error, 10),
+		done:     make(chan struct{}),
+		opts:     EventStreamOptions{BufferSize: bufferSize, Backpressure: policy, SpillDir: t.TempDir()},
+	}
+}
+
+func numberedEvent(n int) *Event {
+	return &Event{Type: EventMessagePosted, ChannelID: "c1", Seq: int64(n), JournalID: uint64(100 + n)}
+}
+
+// receive takes the buffered events off the stream's channel
+func receive(s *EventStream) []int64 {
+	var seqs []int64
+	for len(s.events) > 0 {
+		seqs = append(seqs, (<-s.events).Seq)
+	}
+	return seqs
+}
+
+func TestBackpressureSpillKeepsOrder(t *testing.T) {
+	s := newBackpressureStream(t, BackpressureSpill, 2)
+	ctx := context.Background()
+
+	for n := 1; n <= 5; n++ {
+		s.deliver(ctx, numberedEvent(n))
+	}
+	stats, _ := s.Stats()
+	if stats.Spilled != 3 || stats.SpillPending != 3 || stats.Dropped != 0 {
+		t.Fatalf("stats after overflowing = %+v, want 3 spilled and pending", stats)
+	}
+	spillFiles, _ := filepath.Glob(filepath.Join(s.opts.SpillDir, "libcommunicator-events-*"))
+	if len(spillFiles) != 1 {
+		t.Fatalf("spill files = %v, want one", spillFiles)
+	}
+
+	// New events queue behind the spilled ones as the consumer catches up
+	var got []int64
+	for n := 6; n <= 7; n++ {
+		got = append(got, receive(s)...)
+		s.deliver(ctx, numberedEvent(n))
+	}
+	for s.spillPending() > 0 {
+		got = append(got, receive(s)...)
+		s.drainSpill()
+	}
+	var journalIDs []uint64
+	for len(s.events) > 0 {
+		event := <-s.events
+		got = append(got, event.Seq)
+		journalIDs = append(journalIDs, event.JournalID)
+	}
+
+	want := []int64{1, 2, 3, 4, 5, 6, 7}
+	if len(got) != len(want) {
+		t.Fatalf("delivered %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("delivered %v, want %v", got, want)
+		}
+	}
+	// Spilled events keep their journal IDs for Ack
+	if journalIDs[len(journalIDs)-1] != 107 {
+		t.Fatalf("journal IDs = %v, want the last to be 107", journalIDs)
+	}
+
+	// Once drained the file starts over, and Close removes it
+	if info, err := os.Stat(spillFiles[0]); err != nil || info.Size() != 0 {
+		t.Fatalf("drained spill file: %v, %v", info, err)
+	}
+	stats, _ = s.Stats()
+	if stats.Spilled != 5 || stats.SpillPending != 0 {
+		t.Fatalf("stats after draining = %+v", stats)
+	}
+	s.closeSpill()
+	if _, err := os.Stat(spillFiles[0]); !os.IsNotExist(err) {
+		t.Fatalf("spill file left after closing: %v", err)
+	}
+}
+
+func TestBackpressureSpillFailureDrops(t *testing.T) {
+	s := newBackpressureStream(t, BackpressureSpill, 1)
+	s.opts.SpillDir = filepath.Join(s.opts.SpillDir, "missing")
+
+	s.deliver(context.Background(), numberedEvent(1))
+	s.deliver(context.Background(), numberedEvent(2))
+
+	if stats, _ := s.Stats(); stats.Dropped != 1 || stats.Spilled != 0 {
+		t.Fatalf("stats = %+v, want the event that couldn't spill dropped", stats)
+	}
+	select {
+	case err := <-s.Errors():
+		if err == nil {
+			t.Fatal("nil spill error")
+		}
+	default:
+		t.Fatal("the spill failure wasn't reported")
+	}
+}
+
+func TestBackpressureDropPolicies(t *testing.T) {
+	tests := []struct {
+		policy BackpressurePolicy
+		want   []int64
+	}{
+		{BackpressureDropNewest, []int64{1, 2}},
+		{BackpressureDropOldest, []int64{3, 4}},
+	}
+	for _, tt := range tests {
+		s := newBackpressureStream(t, tt.policy, 2)
+		for n := 1; n <= 4; n++ {
+			s.deliver(context.Background(), numberedEvent(n))
+		}
+		got := receive(s)
+		if len(got) != 2 || got[0] != tt.want[0] || got[1] != tt.want[1] {
+			t.Errorf("policy %d delivered %v, want %v", tt.policy, got, tt.want)
+		}
+		if stats, _ := s.Stats(); stats.Dropped != 2 {
+			t.Errorf("policy %d dropped %d events, want 2", tt.policy, stats.Dropped)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package libcommunicator
+
+/*
+#include <communicator.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"time"
+)
+
+// BookmarkType represents the kind of a channel bookmark
+type BookmarkType string
+
+const (
+	BookmarkTypeLink BookmarkType = "link"
+	BookmarkTypeFile BookmarkType = "file"
+)
+
+// ChannelBookmark represents a link or file bookmarked in a channel
+type ChannelBookmark struct {
+	ID          string       `json:"id"`
+	ChannelID   string       `json:"channel_id"`
+	OwnerID     string       `json:"owner_id"`
+	DisplayName string       `json:"display_name"`
+	Type        BookmarkType `json:"type"`
+	LinkURL     *string      `json:"link_url,omitempty"`
+	ImageURL    *string      `json:"image_url,omitempty"`
+	Emoji       *string      `json:"emoji,omitempty"`
+	FileID      *string      `json:"file_id,omitempty"`
+	File        *Attachment  `json:"file,omitempty"`
+	SortOrder   int64        `json:"sort_order"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// NewChannelBookmark holds the parameters for creating a channel bookmark
+type NewChannelBookmark struct {
+	DisplayName string       `json:"display_name"`
+	Type        BookmarkType `json:"type"`
+	LinkURL     string       `json:"link_url,omitempty"`
+	FileID      string       `json:"file_id,omitempty"`
+	Emoji       string       `json:"emoji,omitempty"`
+	ImageURL    string       `json:"image_url,omitempty"`
+}
+
+// NewLinkBookmark creates the parameters for a link bookmark
+func NewLinkBookmark(displayName, url string) NewChannelBookmark {
+	return NewChannelBookmark{DisplayName: displayName, Type: BookmarkTypeLink, LinkURL: url}
+}
+
+// NewFileBookmark creates the parameters for a bookmark to an uploaded file
+func NewFileBookmark(displayName, fileID string) NewChannelBookmark {
+	return NewChannelBookmark{DisplayName: displayName, Type: BookmarkTypeFile, FileID: fileID}
+}
+
+// ListChannelBookmarks retrieves the bookmarks of a channel in display order
+func (p *Platform) ListChannelBookmarks(channelID string) ([]ChannelBookmark, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	cstr := C.communicator_platform_get_channel_bookmarks(p.handle, cChannelID)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var bookmarks []ChannelBookmark
+	if err := p.decode([]byte(C.GoString(cstr)), &bookmarks); err != nil {
+		return nil, err
+	}
+
+	return bookmarks, nil
+}
+
+// CreateChannelBookmark creates a link or file bookmark in a channel
+func (p *Platform) CreateChannelBookmark(channelID string, bookmark NewChannelBookmark) (_ *ChannelBookmark, err error) {
+	defer p.audit("CreateChannelBookmark", "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("CreateChannelBookmark"); err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.Marshal(bookmark)
+	if err != nil {
+		return nil, err
+	}
+
+	cChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	cJSON, freeJSON := cStringFree(string(jsonBytes))
+	defer freeJSON()
+
+	cstr := C.communicator_platform_create_channel_bookmark(p.handle, cChannelID, cJSON)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var created ChannelBookmark
+	if err := p.decode([]byte(C.GoString(cstr)), &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// DeleteChannelBookmark deletes a bookmark from a channel
+func (p *Platform) DeleteChannelBookmark(channelID, bookmarkID string) (err error) {
+	defer p.audit("DeleteChannelBookmark", "channel_id", channelID, "bookmark_id", bookmarkID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("DeleteChannelBookmark"); err != nil {
+		return err
+	}
+
+	cChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	cBookmarkID, freeBookmarkID := cStringFree(bookmarkID)
+	defer freeBookmarkID()
+
+	code := C.communicator_platform_delete_channel_bookmark(p.handle, cChannelID, cBookmarkID)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package libcommunicator
+
+/*
+#include <communicator.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"time"
+)
+
+// Bot is a bot account; UserID is the ID it posts and joins channels as
+type Bot struct {
+	UserID      string    `json:"user_id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	Description string    `json:"description"`
+	OwnerID     string    `json:"owner_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	IsDisabled  bool      `json:"is_disabled"`
+}
+
+// NewBot holds the settings for CreateBot
+type NewBot struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// BotPatch is a partial bot update; nil fields are left unchanged
+type BotPatch struct {
+	Username    *string `json:"username,omitempty"`
+	DisplayName *string `json:"display_name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// decodeBot decodes a bot returned by the native library and frees the string
+func decodeBot(cstr *C.char) (*Bot, error) {
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var bot Bot
+	if err := json.Unmarshal([]byte(C.GoString(cstr)), &bot); err != nil {
+		return nil, err
+	}
+	return &bot, nil
+}
+
+// CreateBot creates a bot account owned by the current user
+func (p *Platform) CreateBot(bot *NewBot) (_ *Bot, err error) {
+	var username string
+	if bot != nil {
+		username = bot.Username
+	}
+	defer p.audit("CreateBot", "username", username)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("CreateBot"); err != nil {
+		return nil, err
+	}
+
+	botJSON, err := json.Marshal(bot)
+	if err != nil {
+		return nil, err
+	}
+
+	csBot, freeBot := cStringFree(string(botJSON))
+	defer freeBot()
+
+	return decodeBot(C.communicator_platform_create_bot(p.handle, csBot))
+}
+
+// PatchBot partially updates a bot account
+func (p *Platform) PatchBot(botUserID string, patch *BotPatch) (_ *Bot, err error) {
+	defer p.audit("PatchBot", "bot_user_id", botUserID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("PatchBot"); err != nil {
+		return nil, err
+	}
+
+	patchJSON, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	csBotUserID, freeBotUserID := cStringFree(botUserID)
+	defer freeBotUserID()
+
+	csPatch, freePatch := cStringFree(string(patchJSON))
+	defer freePatch()
+
+	return decodeBot(C.communicator_platform_patch_bot(p.handle, csBotUserID, csPatch))
+}
+
+// DisableBot disables a bot account, deactivating its user
+func (p *Platform) DisableBot(botUserID string) (_ *Bot, err error) {
+	defer p.audit("DisableBot", "bot_user_id", botUserID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("DisableBot"); err != nil {
+		return nil, err
+	}
+
+	csBotUserID, freeBotUserID := cStringFree(botUserID)
+	defer freeBotUserID()
+
+	return decodeBot(C.communicator_platform_disable_bot(p.handle, csBotUserID))
+}
+
+// AssignBot transfers ownership of a bot account to another user
+func (p *Platform) AssignBot(botUserID, userID string) (_ *Bot, err error) {
+	defer p.audit("AssignBot", "bot_user_id", botUserID, "user_id", userID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("AssignBot"); err != nil {
+		return nil, err
+	}
+
+	csBotUserID, freeBotUserID := cStringFree(botUserID)
+	defer freeBotUserID()
+
+	csUserID, freeUserID := cStringFree(userID)
+	defer freeUserID()
+
+	return decodeBot(C.communicator_platform_assign_bot(p.handle, csBotUserID, csUserID))
+}
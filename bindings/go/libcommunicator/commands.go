@@ -0,0 +1,105 @@
+package libcommunicator
+
+/*
+#include <communicator.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+)
+
+// CommandResponseType says where a command response is shown
+type CommandResponseType string
+
+const (
+	CommandResponseEphemeral CommandResponseType = "ephemeral"
+	CommandResponseInChannel CommandResponseType = "in_channel"
+)
+
+// CommandResponse is the result of executing a slash command
+type CommandResponse struct {
+	ResponseType   CommandResponseType        `json:"response_type"`
+	Text           string                     `json:"text"`
+	ChannelID      string                     `json:"channel_id,omitempty"`
+	Username       string                     `json:"username,omitempty"`
+	IconURL        string                     `json:"icon_url,omitempty"`
+	GotoLocation   string                     `json:"goto_location,omitempty"`
+	TriggerID      string                     `json:"trigger_id,omitempty"`
+	Props          map[string]json.RawMessage `json:"props,omitempty"`
+	Attachments    []json.RawMessage          `json:"attachments,omitempty"`
+	ExtraResponses []CommandResponse          `json:"extra_responses,omitempty"`
+}
+
+// SlashCommand is a slash command the user can run, as offered by AutocompleteCommands
+type SlashCommand struct {
+	Trigger     string `json:"trigger"` // without the slash, e.g. "away"
+	DisplayName string `json:"display_name"`
+	Description string `json:"description"`
+	Hint        string `json:"hint"` // the arguments, e.g. "[message]"
+	// TeamID is the team the command is registered in; empty for built-in and plugin commands
+	TeamID   string `json:"team_id,omitempty"`
+	PluginID string `json:"plugin_id,omitempty"`
+}
+
+// ExecuteCommand runs a slash command such as "/away" or "/giphy cats" in a channel
+// Ephemeral responses are only returned here; in-channel ones are also posted
+func (p *Platform) ExecuteCommand(channelID, command string) (_ *CommandResponse, err error) {
+	defer p.audit("ExecuteCommand", "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("ExecuteCommand"); err != nil {
+		return nil, err
+	}
+
+	csChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	csCommand, freeCommand := cStringFree(command)
+	defer freeCommand()
+
+	cstr := C.communicator_platform_execute_command(p.handle, csChannelID, csCommand)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var response CommandResponse
+	if err := p.decode([]byte(C.GoString(cstr)), &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// AutocompleteCommands lists the slash commands available in a channel whose
+// trigger starts with prefix, sorted by trigger
+// The prefix may include the slash; "" lists every command. Built-in, custom
+// and plugin commands are included, for composers to suggest as the user types.
+func (p *Platform) AutocompleteCommands(channelID, prefix string) ([]SlashCommand, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	csChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	csPrefix, freePrefix := cStringFree(prefix)
+	defer freePrefix()
+
+	cstr := C.communicator_platform_autocomplete_commands(p.handle, csChannelID, csPrefix)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var commands []SlashCommand
+	if err := p.decode([]byte(C.GoString(cstr)), &commands); err != nil {
+		return nil, err
+	}
+
+	return commands, nil
+}
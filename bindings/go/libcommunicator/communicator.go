@@ -39,6 +39,8 @@ const (
 	ErrorInvalidState ErrorCode = 11
 	ErrorUnsupported  ErrorCode = 12
 	ErrorRateLimited  ErrorCode = 13
+	// ErrorUnsupportedByServer means the connected server's version lacks the feature
+	ErrorUnsupportedByServer ErrorCode = 14
 )
 
 var initialized bool
@@ -49,6 +51,7 @@ func Init() error {
 	if initialized {
 		return nil
 	}
+	defer pinThread()()
 
 	code := C.communicator_init()
 	if code != C.COMMUNICATOR_SUCCESS {
@@ -80,7 +83,8 @@ func GetVersion() Version {
 	}
 }
 
-// getLastError retrieves the last error from the library
+// getLastError retrieves the last error from the library as a *PlatformError,
+// which matches the sentinel error of its code with errors.Is
 func getLastError() error {
 	code := C.communicator_last_error_code()
 	if code == C.COMMUNICATOR_SUCCESS {
@@ -90,11 +94,20 @@ func getLastError() error {
 	msg := C.communicator_last_error_message()
 	if msg == nil {
 		codeStr := C.communicator_error_code_string(code)
-		return fmt.Errorf("libcommunicator error %d: %s", code, C.GoString(codeStr))
+		return &PlatformError{
+			Code:    ErrorCode(code),
+			Message: fmt.Sprintf("libcommunicator error %d: %s", code, C.GoString(codeStr)),
+		}
 	}
 
 	defer C.communicator_free_string(msg)
-	return fmt.Errorf("libcommunicator error %d: %s", code, C.GoString(msg))
+	if ErrorCode(code) == ErrorUnsupportedByServer {
+		return &UnsupportedByServerError{Message: C.GoString(msg)}
+	}
+	return &PlatformError{
+		Code:    ErrorCode(code),
+		Message: fmt.Sprintf("libcommunicator error %d: %s", code, C.GoString(msg)),
+	}
 }
 
 // clearError clears the last error
@@ -0,0 +1,35 @@
+package libcommunicator
+
+import "errors"
+
+// ErrUnsupportedByServer matches every UnsupportedByServerError, e.g.
+// errors.Is(err, ErrUnsupportedByServer)
+var ErrUnsupportedByServer = &PlatformError{Code: ErrorUnsupportedByServer, Message: "not supported by this server"}
+
+// UnsupportedByServerError is returned when the connected server is too old
+// for an endpoint (channel bookmarks, post acknowledgements, ...)
+// Tools that work across server versions can branch on it instead of
+// treating the call as failed.
+type UnsupportedByServerError struct {
+	Message string
+}
+
+func (e *UnsupportedByServerError) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is ErrUnsupportedByServer
+func (e *UnsupportedByServerError) Is(target error) bool {
+	return target == ErrUnsupportedByServer
+}
+
+// Unwrap returns the error as a *PlatformError, so errors.As gives its code
+// and message like for any other error from the library
+func (e *UnsupportedByServerError) Unwrap() error {
+	return &PlatformError{Code: ErrorUnsupportedByServer, Message: e.Message}
+}
+
+// IsUnsupportedByServer reports whether err means the server lacks the feature
+func IsUnsupportedByServer(err error) bool {
+	return errors.Is(err, ErrUnsupportedByServer)
+}
@@ -0,0 +1,48 @@
+package libcommunicator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestUnsupportedByServerErrorMatching(t *testing.T) {
+	unsupported := &UnsupportedByServerError{Message: "channel bookmarks need Mattermost 9.5"}
+	tests := []struct {
+		name        string
+		err         error
+		unsupported bool
+	}{
+		{"unsupported", unsupported, true},
+		{"wrapped", fmt.Errorf("bookmarks: %w", unsupported), true},
+		{"platform error with the code", &PlatformError{Code: ErrorUnsupportedByServer, Message: "x"}, true},
+		{"other platform error", &PlatformError{Code: ErrorNotFound, Message: "x"}, false},
+		{"other error", errors.New("x"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUnsupportedByServer(tt.err); got != tt.unsupported {
+				t.Fatalf("IsUnsupportedByServer = %v, want %v", got, tt.unsupported)
+			}
+			if tt.unsupported && errors.Is(tt.err, ErrNotFound) {
+				t.Fatal("matched ErrNotFound")
+			}
+
+			var platformErr *PlatformError
+			if !errors.As(tt.err, &platformErr) {
+				if tt.unsupported {
+					t.Fatal("errors.As found no *PlatformError")
+				}
+				return
+			}
+			if tt.unsupported && platformErr.Code != ErrorUnsupportedByServer {
+				t.Fatalf("code = %v, want ErrorUnsupportedByServer", platformErr.Code)
+			}
+		})
+	}
+
+	var platformErr *PlatformError
+	if errors.As(unsupported, &platformErr) && platformErr.Message != unsupported.Message {
+		t.Fatalf("message = %q, want %q", platformErr.Message, unsupported.Message)
+	}
+}
@@ -0,0 +1,271 @@
+package libcommunicator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FileConfig is the configuration read by LoadConfig
+//
+// In JSON the layout is:
+//
+//	{
+//	  "platform": {
+//	    "server": "https://chat.example.com",
+//	    "team_id": "abc123",
+//	    "credentials": {"token": "${MM_TOKEN}"},
+//	    "headers": {"CF-Access-Client-Id": "${CF_CLIENT_ID}"},
+//	    "user_agent": "my-bot/1.0"
+//	  },
+//	  "context": {"log_level": "debug"},
+//	  "proxy": "http://proxy.internal:3128",
+//	  "no_proxy": "localhost,.internal",
+//	  "tls": {"ca_cert": "/etc/ssl/private-ca.pem"},
+//	  "timeouts": {"connect": "10s", "request": "30s", "handshake": "10s", "idle": "2m"},
+//	  "rate_limit": {"max_retries": 3, "max_wait": "1m"},
+//	  "retry": {"max_attempts": 3, "initial_backoff": "200ms", "retry_on": "network,server_error"}
+//	}
+//
+// The tls object takes ca_cert, client_cert, client_key and
+// insecure_skip_verify. The retry object takes max_attempts,
+// initial_backoff, max_backoff, multiplier, jitter and retry_on, a
+// comma-separated list of RetryClass values.
+type FileConfig struct {
+	// Platform includes the proxy, no_proxy, tls, timeouts, rate_limit and
+	// retry settings
+	Platform PlatformConfig
+	// Context holds context settings; see ApplyContext
+	Context map[string]string
+}
+
+// fileConfigJSON is the file layout of FileConfig
+type fileConfigJSON struct {
+	Platform struct {
+		Server      string            `json:"server"`
+		TeamID      string            `json:"team_id"`
+		Credentials map[string]string `json:"credentials"`
+		Headers     map[string]string `json:"headers"`
+		UserAgent   string            `json:"user_agent"`
+	} `json:"platform"`
+	Context map[string]string `json:"context"`
+	Proxy   string            `json:"proxy"`
+	NoProxy string            `json:"no_proxy"`
+	TLS     struct {
+		CACert             string `json:"ca_cert"`
+		ClientCert         string `json:"client_cert"`
+		ClientKey          string `json:"client_key"`
+		InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+	} `json:"tls"`
+	Timeouts struct {
+		Connect   string `json:"connect"`
+		Request   string `json:"request"`
+		Handshake string `json:"handshake"`
+		Idle      string `json:"idle"`
+	} `json:"timeouts"`
+	RateLimit struct {
+		MaxRetries int    `json:"max_retries"`
+		MaxWait    string `json:"max_wait"`
+	} `json:"rate_limit"`
+	Retry struct {
+		MaxAttempts    int     `json:"max_attempts"`
+		InitialBackoff string  `json:"initial_backoff"`
+		MaxBackoff     string  `json:"max_backoff"`
+		Multiplier     float64 `json:"multiplier"`
+		Jitter         float64 `json:"jitter"`
+		RetryOn        string  `json:"retry_on"`
+	} `json:"retry"`
+}
+
+// LoadConfig reads a JSON configuration file, named with a .json extension
+// String values may reference environment variables as ${NAME}, or
+// ${NAME:-default} to fall back when NAME is unset; "$$" is a literal "$".
+// Keeping secrets in the environment this way keeps them out of the file.
+// Unknown keys are an error, to catch typos.
+func LoadConfig(path string) (*FileConfig, error) {
+	if ext := strings.ToLower(filepath.Ext(path)); ext != ".json" {
+		return nil, fmt.Errorf("config: unsupported format %q; configuration files are JSON", ext)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+
+	expanded, err := expandConfigEnv(tree)
+	if err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	raw, err := json.Marshal(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	var file fileConfigJSON
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+
+	cfg := &FileConfig{
+		Platform: PlatformConfig{
+			Server:             file.Platform.Server,
+			TeamID:             file.Platform.TeamID,
+			Credentials:        file.Platform.Credentials,
+			Headers:            file.Platform.Headers,
+			UserAgent:          file.Platform.UserAgent,
+			ProxyURL:           file.Proxy,
+			NoProxy:            file.NoProxy,
+			CACert:             file.TLS.CACert,
+			ClientCert:         file.TLS.ClientCert,
+			ClientKey:          file.TLS.ClientKey,
+			InsecureSkipVerify: file.TLS.InsecureSkipVerify,
+			RateLimit:          RateLimitOptions{MaxRetries: file.RateLimit.MaxRetries},
+			Retry: RetryPolicy{
+				MaxAttempts: file.Retry.MaxAttempts,
+				Multiplier:  file.Retry.Multiplier,
+				Jitter:      file.Retry.Jitter,
+			},
+		},
+		Context: file.Context,
+	}
+	if cfg.Platform.Credentials == nil {
+		cfg.Platform.Credentials = make(map[string]string)
+	}
+	durations := []struct {
+		key   string
+		value string
+		dst   *time.Duration
+	}{
+		{"timeouts.connect", file.Timeouts.Connect, &cfg.Platform.Timeouts.Connect},
+		{"timeouts.request", file.Timeouts.Request, &cfg.Platform.Timeouts.Request},
+		{"timeouts.handshake", file.Timeouts.Handshake, &cfg.Platform.Timeouts.Handshake},
+		{"timeouts.idle", file.Timeouts.Idle, &cfg.Platform.Timeouts.Idle},
+		{"rate_limit.max_wait", file.RateLimit.MaxWait, &cfg.Platform.RateLimit.MaxWait},
+		{"retry.initial_backoff", file.Retry.InitialBackoff, &cfg.Platform.Retry.InitialBackoff},
+		{"retry.max_backoff", file.Retry.MaxBackoff, &cfg.Platform.Retry.MaxBackoff},
+	}
+	for _, d := range durations {
+		if *d.dst, err = parseConfigDuration(d.key, d.value); err != nil {
+			return nil, fmt.Errorf("config: %s: %w", path, err)
+		}
+	}
+	for _, class := range strings.Split(file.Retry.RetryOn, ",") {
+		switch class := RetryClass(strings.TrimSpace(class)); class {
+		case "":
+		case RetryNetwork, RetryTimeout, RetryServerError:
+			cfg.Platform.Retry.RetryOn = append(cfg.Platform.Retry.RetryOn, class)
+		default:
+			return nil, fmt.Errorf("config: %s: retry.retry_on: unknown class %q", path, class)
+		}
+	}
+	return cfg, nil
+}
+
+// ApplyContext stores the file's context settings on ctx with SetConfig
+func (c *FileConfig) ApplyContext(ctx *Context) error {
+	keys := make([]string, 0, len(c.Context))
+	for key := range c.Context {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := ctx.SetConfig(key, c.Context[key]); err != nil {
+			return fmt.Errorf("config: context setting %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func parseConfigDuration(key, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s: must not be negative", key)
+	}
+	return d, nil
+}
+
+// expandConfigEnv replaces environment variable references in every string of a parsed file
+func expandConfigEnv(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return expandEnv(v)
+	case map[string]interface{}:
+		for key, item := range v {
+			expanded, err := expandConfigEnv(item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = expanded
+		}
+		return v, nil
+	case []interface{}:
+		for i, item := range v {
+			expanded, err := expandConfigEnv(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = expanded
+		}
+		return v, nil
+	}
+	return value, nil
+}
+
+// expandEnv replaces ${NAME} and ${NAME:-default} in s; "$$" stands for "$"
+// Unlike os.ExpandEnv, an unset variable without a default is an error, so
+// a missing secret doesn't silently become an empty credential.
+func expandEnv(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '$' {
+			b.WriteByte('$')
+			i++
+			continue
+		}
+		if i+1 >= len(s) || s[i+1] != '{' {
+			b.WriteByte('$')
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated ${ in %q", s)
+		}
+		ref := s[i+2 : i+end]
+		name, fallback, hasFallback := strings.Cut(ref, ":-")
+		if name == "" {
+			return "", fmt.Errorf("empty variable name in %q", s)
+		}
+		value, ok := os.LookupEnv(name)
+		switch {
+		case ok && value != "":
+		case hasFallback:
+			value = fallback
+		case !ok:
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		b.WriteString(value)
+		i += end
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,137 @@
+package libcommunicator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("MM_TOKEN", "secret")
+	path := writeConfig(t, "bot.json", `{
+	  "platform": {
+	    "server": "https://chat.example.com",
+	    "team_id": "team",
+	    "credentials": {"token": "${MM_TOKEN}"},
+	    "headers": {"X-Price": "$$5"}
+	  },
+	  "context": {"log_level": "debug"},
+	  "proxy": "${UNSET_PROXY_FOR_TEST:-http://proxy:3128}",
+	  "tls": {"ca_cert": "/etc/ssl/ca.pem", "insecure_skip_verify": true},
+	  "timeouts": {"connect": "10s", "idle": "2m"},
+	  "rate_limit": {"max_retries": 5, "max_wait": "30s"},
+	  "retry": {"max_attempts": 4, "multiplier": 1.5, "retry_on": "network, server_error"}
+	}`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := cfg.Platform
+	if p.Server != "https://chat.example.com" || p.TeamID != "team" || p.Credentials["token"] != "secret" {
+		t.Fatalf("platform = %+v", p)
+	}
+	if p.Headers["X-Price"] != "$5" || p.ProxyURL != "http://proxy:3128" {
+		t.Fatalf("headers = %v, proxy = %q", p.Headers, p.ProxyURL)
+	}
+	if p.CACert != "/etc/ssl/ca.pem" || !p.InsecureSkipVerify {
+		t.Fatalf("tls = %q, %v", p.CACert, p.InsecureSkipVerify)
+	}
+	if p.Timeouts.Connect != 10*time.Second || p.Timeouts.Idle != 2*time.Minute || p.Timeouts.Request != 0 {
+		t.Fatalf("timeouts = %+v", p.Timeouts)
+	}
+	if p.RateLimit.MaxRetries != 5 || p.RateLimit.MaxWait != 30*time.Second {
+		t.Fatalf("rate limit = %+v", p.RateLimit)
+	}
+	if p.Retry.MaxAttempts != 4 || p.Retry.Multiplier != 1.5 ||
+		!reflect.DeepEqual(p.Retry.RetryOn, []RetryClass{RetryNetwork, RetryServerError}) {
+		t.Fatalf("retry = %+v", p.Retry)
+	}
+	if cfg.Context["log_level"] != "debug" {
+		t.Fatalf("context = %v", cfg.Context)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+		err  string
+	}{
+		{name: "yaml", file: "bot.yaml", data: "platform:\n", err: `unsupported format ".yaml"`},
+		{name: "toml", file: "bot.toml", data: "[platform]\n", err: `unsupported format ".toml"`},
+		{name: "invalid JSON", file: "bot.json", data: `{"platform":`, err: "unexpected end of JSON input"},
+		{name: "unknown key", file: "bot.json", data: `{"platfrom":{}}`, err: `unknown field "platfrom"`},
+		{name: "unset variable", file: "bot.json", data: `{"proxy":"${UNSET_PROXY_FOR_TEST}"}`, err: "UNSET_PROXY_FOR_TEST is not set"},
+		{name: "wrong type", file: "bot.json", data: `{"retry":{"max_attempts":"3"}}`, err: "max_attempts"},
+		{name: "bad duration", file: "bot.json", data: `{"timeouts":{"request":"soon"}}`, err: "timeouts.request"},
+		{name: "negative duration", file: "bot.json", data: `{"rate_limit":{"max_wait":"-1s"}}`, err: "rate_limit.max_wait: must not be negative"},
+		{name: "unknown retry class", file: "bot.json", data: `{"retry":{"retry_on":"network,always"}}`, err: `unknown class "always"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, tt.file, tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestLoadConfigWithoutCredentials(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "bot.json", `{"platform":{"server":"https://chat.example.com"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Platform.Credentials == nil || len(cfg.Platform.Credentials) != 0 {
+		t.Fatalf("credentials = %#v, want an empty map", cfg.Platform.Credentials)
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("EXPAND_SET", "value")
+	t.Setenv("EXPAND_EMPTY", "")
+
+	tests := []struct {
+		in   string
+		want string
+		err  string
+	}{
+		{in: "plain $HOME text", want: "plain $HOME text"},
+		{in: "${EXPAND_SET}", want: "value"},
+		{in: "a-${EXPAND_SET}-b", want: "a-value-b"},
+		{in: "${EXPAND_EMPTY:-fallback}", want: "fallback"},
+		{in: "${EXPAND_EMPTY}", want: ""},
+		{in: "${EXPAND_UNSET:-}", want: ""},
+		{in: "$${EXPAND_SET}", want: "${EXPAND_SET}"},
+		{in: "trailing $", want: "trailing $"},
+		{in: "${EXPAND_UNSET}", err: "EXPAND_UNSET is not set"},
+		{in: "${EXPAND_SET", err: "unterminated"},
+		{in: "${}", err: "empty variable name"},
+	}
+	for _, tt := range tests {
+		got, err := expandEnv(tt.in)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expandEnv(%q) error = %v, want one containing %q", tt.in, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("expandEnv(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
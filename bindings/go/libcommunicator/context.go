@@ -16,6 +16,10 @@ import (
 // Contexts provide isolated configuration and logging environments
 type Context struct {
 	handle C.CommunicatorContext
+	// guard keeps Close from destroying the handle under a running call
+	guard handleGuard
+	// logSlot holds the ID of the log callback, in C memory the library can keep
+	logSlot *C.uint64_t
 }
 
 // LogLevel represents the severity level of a log message
@@ -50,6 +54,7 @@ type LogCallback func(level LogLevel, message string)
 // NewContext creates a new context with the given ID
 // The ID should be unique and is used for identification purposes
 func NewContext(id string) (*Context, error) {
+	defer pinThread()()
 	cID := C.CString(id)
 	defer C.free(unsafe.Pointer(cID))
 
@@ -59,16 +64,17 @@ func NewContext(id string) (*Context, error) {
 	}
 
 	ctx := &Context{handle: handle}
-	runtime.SetFinalizer(ctx, (*Context).Destroy)
+	runtime.SetFinalizer(ctx, (*Context).Close)
 	return ctx, nil
 }
 
 // Initialize initializes the context
 // Must be called before using the context
 func (c *Context) Initialize() error {
-	if c.handle == nil {
+	if !c.acquire() {
 		return ErrInvalidContext
 	}
+	defer c.release()
 
 	code := C.communicator_context_initialize(c.handle)
 	if code != C.COMMUNICATOR_SUCCESS {
@@ -80,9 +86,10 @@ func (c *Context) Initialize() error {
 
 // IsInitialized checks if the context is initialized
 func (c *Context) IsInitialized() (bool, error) {
-	if c.handle == nil {
+	if !c.acquire() {
 		return false, ErrInvalidContext
 	}
+	defer c.release()
 
 	result := C.communicator_context_is_initialized(c.handle)
 	if result < 0 {
@@ -94,9 +101,10 @@ func (c *Context) IsInitialized() (bool, error) {
 
 // SetConfig sets a configuration value
 func (c *Context) SetConfig(key, value string) error {
-	if c.handle == nil {
+	if !c.acquire() {
 		return ErrInvalidContext
 	}
+	defer c.release()
 
 	cKey := C.CString(key)
 	defer C.free(unsafe.Pointer(cKey))
@@ -115,9 +123,10 @@ func (c *Context) SetConfig(key, value string) error {
 // GetConfig retrieves a configuration value
 // Returns an empty string if the key doesn't exist
 func (c *Context) GetConfig(key string) (string, error) {
-	if c.handle == nil {
+	if !c.acquire() {
 		return "", ErrInvalidContext
 	}
+	defer c.release()
 
 	cKey := C.CString(key)
 	defer C.free(unsafe.Pointer(cKey))
@@ -138,9 +147,10 @@ func (c *Context) GetConfig(key string) (string, error) {
 // Shutdown shuts down the context
 // Should be called before destroying the context
 func (c *Context) Shutdown() error {
-	if c.handle == nil {
+	if !c.acquire() {
 		return ErrInvalidContext
 	}
+	defer c.release()
 
 	code := C.communicator_context_shutdown(c.handle)
 	if code != C.COMMUNICATOR_SUCCESS {
@@ -150,44 +160,42 @@ func (c *Context) Shutdown() error {
 	return nil
 }
 
-// Destroy destroys the context and frees its memory
-// After calling this, the context must not be used
-func (c *Context) Destroy() {
-	if c.handle != nil {
-		C.communicator_context_destroy(c.handle)
-		c.handle = nil
-	}
-}
-
-// SetLogCallback sets a callback function to receive log messages
-// The callback will be called from the library's thread context
-// Note: Due to cgo restrictions, this is not yet fully implemented
-func (c *Context) SetLogCallback(callback LogCallback) error {
-	if c.handle == nil {
-		return ErrInvalidContext
+// Close destroys the context and frees its memory, implementing io.Closer
+// It waits for calls in progress. Later calls fail with ErrInvalidContext,
+// which matches ErrClosed. Closing a closed context does nothing.
+func (c *Context) Close() error {
+	if !c.guard.hold(true, func() bool { return c.handle != nil }) {
+		return nil
 	}
+	defer c.guard.drop(true)
 
-	// TODO: Implement callback bridging
-	// This requires storing the Go callback and creating a C callback wrapper
-	// that calls into Go. This is complex due to cgo callback restrictions.
-	return ErrUnsupported
+	runtime.SetFinalizer(c, nil)
+	C.communicator_context_destroy(c.handle)
+	c.handle = nil
+	c.releaseLogCallback()
+	return nil
 }
 
-// ClearLogCallback clears any previously set log callback
-func (c *Context) ClearLogCallback() error {
-	if c.handle == nil {
-		return ErrInvalidContext
-	}
+// acquire takes the handle for a call, keeping Close out until release
+// It returns false once the context is closed; see Platform.acquire.
+func (c *Context) acquire() bool {
+	return c.guard.hold(false, func() bool { return c.handle != nil })
+}
 
-	code := C.communicator_context_clear_log_callback(c.handle)
-	if code != C.COMMUNICATOR_SUCCESS {
-		return getLastError()
-	}
+// release gives back the handle taken by acquire
+func (c *Context) release() {
+	c.guard.drop(false)
+}
 
-	return nil
+// Destroy destroys the context and frees its memory
+// It is Close without the error, kept for existing callers.
+func (c *Context) Destroy() {
+	c.Close()
 }
 
-// ErrInvalidContext is returned when operations are attempted on a nil or destroyed context
+// ErrInvalidContext is returned by calls on a closed Context
+// It matches ErrClosed with errors.Is, like the errors of a closed Platform
+// or EventStream.
 var ErrInvalidContext = newError(ErrorInvalidState, "invalid context handle")
 
 // ErrUnsupported is returned for unsupported operations
@@ -207,3 +215,14 @@ type LibError struct {
 func (e *LibError) Error() string {
 	return e.Message
 }
+
+// Is reports whether target is the sentinel error for e's code, such as ErrTimeout
+// ErrInvalidContext also matches ErrClosed.
+func (e *LibError) Is(target error) bool {
+	return codeMatches(e.Code, target) || (e == ErrInvalidContext && target == ErrClosed)
+}
+
+// Retryable reports whether trying the call again later may succeed
+func (e *LibError) Retryable() bool {
+	return e.Code.Retryable()
+}
@@ -0,0 +1,42 @@
+package libcommunicator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClosedContext(t *testing.T) {
+	var c Context
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close of a closed context = %v", err)
+	}
+
+	checks := map[string]error{
+		"Initialize":       c.Initialize(),
+		"SetConfig":        c.SetConfig("k", "v"),
+		"Shutdown":         c.Shutdown(),
+		"SetLogCallback":   c.SetLogCallback(func(LogLevel, string) {}),
+		"ClearLogCallback": c.ClearLogCallback(),
+	}
+	_, checks["IsInitialized"] = c.IsInitialized()
+	_, checks["GetConfig"] = c.GetConfig("k")
+
+	for call, err := range checks {
+		// Existing callers compare with ErrInvalidContext directly
+		if err != ErrInvalidContext {
+			t.Errorf("%s = %v, want ErrInvalidContext", call, err)
+		}
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("%s error doesn't match ErrClosed", call)
+		}
+	}
+	if len(c.guard.holders) != 0 {
+		t.Fatalf("calls on a closed context left holds: %v", c.guard.holders)
+	}
+}
+
+func TestLibErrorMatchesClosedOnlyForContext(t *testing.T) {
+	if errors.Is(newError(ErrorInvalidState, "other"), ErrClosed) {
+		t.Fatal("an unrelated invalid-state error matches ErrClosed")
+	}
+}
@@ -0,0 +1,236 @@
+package libcommunicator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// EventErrorHandler is a handler that reports failure by returning an error
+// Failed events are retried and, once retries run out, sent to a dead-letter sink
+type EventErrorHandler func(*Event) error
+
+// DeadLetter is an event whose handler kept failing, with the attempts made
+type DeadLetter struct {
+	Event         *Event    `json:"event"`
+	Attempts      int       `json:"attempts"`
+	Errors        []string  `json:"errors"`
+	FirstFailedAt time.Time `json:"first_failed_at"`
+	LastFailedAt  time.Time `json:"last_failed_at"`
+}
+
+// DeadLetterSink receives events that could not be handled
+type DeadLetterSink interface {
+	Put(letter *DeadLetter) error
+}
+
+// DeadLetterFunc adapts a function to a DeadLetterSink
+type DeadLetterFunc func(letter *DeadLetter) error
+
+// Put calls f(letter)
+func (f DeadLetterFunc) Put(letter *DeadLetter) error {
+	return f(letter)
+}
+
+// FileDeadLetterSink appends dead letters to a file, one JSON object per line
+type FileDeadLetterSink struct {
+	mu     sync.Mutex
+	path   string
+	cipher FileCipher
+}
+
+// NewFileDeadLetterSink creates a sink that appends to path, creating it on first use
+func NewFileDeadLetterSink(path string) *FileDeadLetterSink {
+	return &FileDeadLetterSink{path: path}
+}
+
+// NewEncryptedFileDeadLetterSink creates a sink whose lines are encrypted with cipher
+// Read the file back with ReadEncryptedDeadLetters
+func NewEncryptedFileDeadLetterSink(path string, cipher FileCipher) *FileDeadLetterSink {
+	return &FileDeadLetterSink{path: path, cipher: cipher}
+}
+
+// Put appends a dead letter to the file
+func (s *FileDeadLetterSink) Put(letter *DeadLetter) error {
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return err
+	}
+	if s.cipher != nil {
+		sealed, err := s.cipher.Encrypt(line)
+		if err != nil {
+			return err
+		}
+		line = []byte(base64.StdEncoding.EncodeToString(sealed))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadDeadLetters loads the dead letters written by a FileDeadLetterSink
+// Replay them by passing each letter's Event back to EventRouter.Handle
+func ReadDeadLetters(path string) ([]DeadLetter, error) {
+	return ReadEncryptedDeadLetters(path, nil)
+}
+
+// ReadEncryptedDeadLetters loads dead letters written by an encrypted FileDeadLetterSink
+// Plaintext lines, e.g. from before encryption was enabled, fail with
+// ErrNotEncrypted unless cipher is a NewMigratingCipher.
+func ReadEncryptedDeadLetters(path string, cipher FileCipher) ([]DeadLetter, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var letters []DeadLetter
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		data := scanner.Bytes()
+		if len(data) == 0 {
+			continue
+		}
+		if cipher != nil {
+			sealed := data
+			if !bytes.HasPrefix(data, []byte("{")) {
+				if sealed, err = base64.StdEncoding.DecodeString(string(data)); err != nil {
+					return letters, fmt.Errorf("dead letters %s:%d: %w", path, line, err)
+				}
+			}
+			if data, err = cipher.Decrypt(sealed); err != nil {
+				return letters, fmt.Errorf("dead letters %s:%d: %w", path, line, err)
+			}
+		}
+		var letter DeadLetter
+		if err := json.Unmarshal(data, &letter); err != nil {
+			return letters, fmt.Errorf("dead letters %s:%d: %w", path, line, err)
+		}
+		letters = append(letters, letter)
+	}
+	return letters, scanner.Err()
+}
+
+// RetryOptions controls how an EventErrorHandler is retried
+type RetryOptions struct {
+	// MaxAttempts is the number of times the handler is called per event (default 3)
+	MaxAttempts int
+	// Backoff is the wait before the first retry; it doubles after each attempt (default 1s)
+	Backoff time.Duration
+	// DeadLetter receives events that still fail after MaxAttempts; when nil they are dropped
+	DeadLetter DeadLetterSink
+	// OnError, if set, receives every handler failure and any dead-letter sink failure
+	OnError func(error)
+	// Pool configures the worker pool OnWithRetry runs the handler on
+	Pool HandlerOptions
+}
+
+// WithRetry wraps an error-returning handler with retries and dead-lettering
+// The result can be registered with On or OnWithOptions; since retries wait
+// between attempts, a worker pool keeps them from stalling other handlers.
+// A handler that panics is not retried: the panic is reported as a
+// *HandlerError and the event dead-lettered. Prefer the EventRouter method,
+// whose waits end when the router is closed.
+func WithRetry(handler EventErrorHandler, opts RetryOptions) EventHandler {
+	return withRetry(handler, opts, nil)
+}
+
+// WithRetry wraps an error-returning handler like the WithRetry function,
+// except that Close ends the wait for a retry and dead-letters the event
+func (r *EventRouter) WithRetry(handler EventErrorHandler, opts RetryOptions) EventHandler {
+	return withRetry(handler, opts, r.done)
+}
+
+// withRetry implements WithRetry; closing done, if not nil, stops retrying
+func withRetry(handler EventErrorHandler, opts RetryOptions, done <-chan struct{}) EventHandler {
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 3
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = time.Second
+	}
+
+	return func(event *Event) {
+		var letter *DeadLetter
+		backoff := opts.Backoff
+
+	attempts:
+		for attempt := 1; attempt <= opts.MaxAttempts; attempt++ {
+			err := callRetried(handler, event)
+			if err == nil {
+				return
+			}
+			var handlerErr *HandlerError
+			panicked := errors.As(err, &handlerErr) && handlerErr.Panic != nil
+			if opts.OnError != nil {
+				opts.OnError(fmt.Errorf("%s event: attempt %d/%d: %w", event.Type, attempt, opts.MaxAttempts, err))
+			}
+
+			now := time.Now().UTC()
+			if letter == nil {
+				letter = &DeadLetter{Event: event, FirstFailedAt: now}
+			}
+			letter.Attempts = attempt
+			letter.Errors = append(letter.Errors, err.Error())
+			letter.LastFailedAt = now
+
+			if panicked || attempt == opts.MaxAttempts {
+				break
+			}
+			timer := time.NewTimer(backoff)
+			select {
+			case <-timer.C:
+			case <-done:
+				timer.Stop()
+				break attempts
+			}
+			backoff *= 2
+		}
+
+		if opts.DeadLetter == nil {
+			return
+		}
+		if err := opts.DeadLetter.Put(letter); err != nil && opts.OnError != nil {
+			opts.OnError(fmt.Errorf("%s event: dead letter: %w", event.Type, err))
+		}
+	}
+}
+
+// callRetried calls a retried handler, returning a panic as a *HandlerError
+func callRetried(handler EventErrorHandler, event *Event) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &HandlerError{Event: event, Panic: v, Stack: debug.Stack()}
+		}
+	}()
+	return handler(event)
+}
+
+// OnWithRetry registers an error-returning handler with retries and dead-lettering
+// The handler runs on a worker pool configured by opts.Pool, as with
+// OnWithOptions, so waiting to retry an event doesn't hold up the handlers
+// of the events behind it.
+func (r *EventRouter) OnWithRetry(eventType string, handler EventErrorHandler, opts RetryOptions) *Subscription {
+	return r.OnWithOptions(eventType, r.WithRetry(handler, opts), opts.Pool)
+}
@@ -0,0 +1,107 @@
+package libcommunicator
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// deadLetters collects dead letters in memory
+type deadLetters struct {
+	mu      sync.Mutex
+	letters []*DeadLetter
+}
+
+func (d *deadLetters) Put(letter *DeadLetter) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.letters = append(d.letters, letter)
+	return nil
+}
+
+func (d *deadLetters) get() []*DeadLetter {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]*DeadLetter(nil), d.letters...)
+}
+
+func TestWithRetryDeadLettersAPanic(t *testing.T) {
+	sink := &deadLetters{}
+	calls := 0
+	handler := WithRetry(func(*Event) error {
+		calls++
+		panic("boom")
+	}, RetryOptions{MaxAttempts: 3, Backoff: time.Hour, DeadLetter: sink})
+
+	handler(&Event{Type: EventMessagePosted})
+
+	if calls != 1 {
+		t.Fatalf("a panicking handler ran %d times, want 1", calls)
+	}
+	letters := sink.get()
+	if len(letters) != 1 || letters[0].Attempts != 1 || !strings.Contains(letters[0].Errors[0], "panicked: boom") {
+		t.Fatalf("dead letters = %+v", letters)
+	}
+}
+
+func TestOnWithRetryDoesNotStallDispatch(t *testing.T) {
+	r := NewEventRouter()
+	defer r.Close()
+
+	failed := make(chan struct{}, 1)
+	r.OnWithRetry(EventMessagePosted, func(*Event) error {
+		select {
+		case failed <- struct{}{}:
+		default:
+		}
+		return errors.New("unavailable")
+	}, RetryOptions{MaxAttempts: 2, Backoff: time.Hour})
+	typing := make(chan struct{}, 1)
+	r.On(EventUserTyping, func(*Event) { typing <- struct{}{} })
+
+	r.Handle(&Event{Type: EventMessagePosted})
+	<-failed
+
+	// The retry waits on its worker while other events are dispatched
+	handled := make(chan struct{})
+	go func() {
+		r.Handle(&Event{Type: EventMessagePosted})
+		r.Handle(&Event{Type: EventUserTyping})
+		close(handled)
+	}()
+	select {
+	case <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Handle waited for the retry backoff")
+	}
+	<-typing
+}
+
+func TestRouterCloseEndsRetryBackoff(t *testing.T) {
+	r := NewEventRouter()
+	sink := &deadLetters{}
+	failed := make(chan struct{}, 1)
+	r.OnWithRetry(EventMessagePosted, func(*Event) error {
+		failed <- struct{}{}
+		return errors.New("unavailable")
+	}, RetryOptions{MaxAttempts: 5, Backoff: time.Hour, DeadLetter: sink})
+
+	handled := make(chan struct{})
+	go func() {
+		r.Handle(&Event{Type: EventMessagePosted})
+		close(handled)
+	}()
+	<-failed
+	r.Close()
+
+	select {
+	case <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the handler kept waiting to retry after Close")
+	}
+	if letters := sink.get(); len(letters) != 1 || letters[0].Attempts != 1 {
+		t.Fatalf("dead letters = %+v, want the event after one attempt", letters)
+	}
+}
@@ -0,0 +1,273 @@
+package libcommunicator
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DecodeMode controls how unknown JSON fields are handled when a platform
+// decodes Message, MessageMetadata, Channel and User values
+type DecodeMode int32
+
+const (
+	// DecodeLenient captures unknown fields into the Extras map (default)
+	DecodeLenient DecodeMode = iota
+	// DecodeStrict rejects payloads containing unknown fields with an *UnknownFieldsError
+	DecodeStrict
+)
+
+// SetDecodeMode sets how this platform handles unknown JSON fields
+// Strict mode suits tests that should notice when the server or the native
+// library starts sending fields the Go types don't model. It applies to the
+// values the platform's methods return; event Data is left as decoded JSON.
+func (p *Platform) SetDecodeMode(mode DecodeMode) {
+	p.decodeMode.Store(int32(mode))
+}
+
+// DecodeMode returns how this platform handles unknown JSON fields
+func (p *Platform) DecodeMode() DecodeMode {
+	return DecodeMode(p.decodeMode.Load())
+}
+
+// decode unmarshals a result of the native library into v, rejecting unknown
+// fields if the platform decodes strictly
+func (p *Platform) decode(data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	if p.DecodeMode() == DecodeStrict {
+		return checkUnknownFields(v)
+	}
+	return nil
+}
+
+// UnknownFieldsError is returned in DecodeStrict mode when a payload contains
+// fields the Go type does not model
+type UnknownFieldsError struct {
+	Type   string
+	Fields []string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return fmt.Sprintf("unknown fields in %s: %s", e.Type, strings.Join(e.Fields, ", "))
+}
+
+// extrasHolder is implemented by the types that capture unknown fields
+type extrasHolder interface {
+	extras() (typeName string, fields map[string]json.RawMessage)
+}
+
+// checkUnknownFields returns an *UnknownFieldsError for the first value in v,
+// at any depth, that captured unknown fields
+func checkUnknownFields(v interface{}) error {
+	if err := findUnknownFields(reflect.ValueOf(v)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func findUnknownFields(v reflect.Value) *UnknownFieldsError {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			return findUnknownFields(v.Elem())
+		}
+	case reflect.Struct:
+		if holder, ok := v.Interface().(extrasHolder); ok {
+			if typeName, extras := holder.extras(); len(extras) > 0 {
+				fields := make([]string, 0, len(extras))
+				for key := range extras {
+					fields = append(fields, key)
+				}
+				sort.Strings(fields)
+				return &UnknownFieldsError{Type: typeName, Fields: fields}
+			}
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if err := findUnknownFields(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := findUnknownFields(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := findUnknownFields(iter.Value()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// knownFieldsCache maps a reflect.Type to the set of JSON keys it decodes
+var knownFieldsCache sync.Map
+
+// knownFields returns the JSON keys decoded by struct type t
+func knownFields(t reflect.Type) map[string]bool {
+	if cached, ok := knownFieldsCache.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+
+	fields := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = true
+	}
+
+	knownFieldsCache.Store(t, fields)
+	return fields
+}
+
+// decodeWithExtras decodes data into v (a pointer to a struct) and returns the
+// fields v does not model
+func decodeWithExtras(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	// encoding/json matches keys case-insensitively, so compare lowercased
+	known := knownFields(reflect.TypeOf(v).Elem())
+	var extras map[string]json.RawMessage
+	for key, value := range raw {
+		if known[strings.ToLower(key)] {
+			continue
+		}
+		if extras == nil {
+			extras = make(map[string]json.RawMessage)
+		}
+		extras[key] = value
+	}
+	return extras, nil
+}
+
+// encodeWithExtras encodes v (a struct) with the extras added back, so values
+// pass through Go without losing the fields it doesn't model
+func encodeWithExtras(v interface{}, extras map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extras) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range extras {
+		if _, ok := fields[key]; !ok {
+			fields[key] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes a Message, capturing unknown fields in Extras
+func (m *Message) UnmarshalJSON(data []byte) error {
+	type plain Message
+	extras, err := decodeWithExtras(data, (*plain)(m))
+	if err != nil {
+		return err
+	}
+	m.Extras = extras
+	return nil
+}
+
+// MarshalJSON encodes a Message, including the fields in Extras
+func (m Message) MarshalJSON() ([]byte, error) {
+	type plain Message
+	return encodeWithExtras(plain(m), m.Extras)
+}
+
+func (m Message) extras() (string, map[string]json.RawMessage) {
+	return "Message", m.Extras
+}
+
+// UnmarshalJSON decodes MessageMetadata, capturing unknown fields in Extras
+func (m *MessageMetadata) UnmarshalJSON(data []byte) error {
+	type plain MessageMetadata
+	extras, err := decodeWithExtras(data, (*plain)(m))
+	if err != nil {
+		return err
+	}
+	m.Extras = extras
+	return nil
+}
+
+// MarshalJSON encodes MessageMetadata, including the fields in Extras
+func (m MessageMetadata) MarshalJSON() ([]byte, error) {
+	type plain MessageMetadata
+	return encodeWithExtras(plain(m), m.Extras)
+}
+
+func (m MessageMetadata) extras() (string, map[string]json.RawMessage) {
+	return "MessageMetadata", m.Extras
+}
+
+// UnmarshalJSON decodes a Channel, capturing unknown fields in Extras
+func (c *Channel) UnmarshalJSON(data []byte) error {
+	type plain Channel
+	extras, err := decodeWithExtras(data, (*plain)(c))
+	if err != nil {
+		return err
+	}
+	c.Extras = extras
+	return nil
+}
+
+// MarshalJSON encodes a Channel, including the fields in Extras
+func (c Channel) MarshalJSON() ([]byte, error) {
+	type plain Channel
+	return encodeWithExtras(plain(c), c.Extras)
+}
+
+func (c Channel) extras() (string, map[string]json.RawMessage) {
+	return "Channel", c.Extras
+}
+
+// UnmarshalJSON decodes a User, capturing unknown fields in Extras
+func (u *User) UnmarshalJSON(data []byte) error {
+	type plain User
+	extras, err := decodeWithExtras(data, (*plain)(u))
+	if err != nil {
+		return err
+	}
+	u.Extras = extras
+	return nil
+}
+
+// MarshalJSON encodes a User, including the fields in Extras
+func (u User) MarshalJSON() ([]byte, error) {
+	type plain User
+	return encodeWithExtras(plain(u), u.Extras)
+}
+
+func (u User) extras() (string, map[string]json.RawMessage) {
+	return "User", u.Extras
+}
@@ -0,0 +1,143 @@
+package libcommunicator
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const messageWithExtras = `{"id":"p1","channel_id":"c1","sender_id":"u1","text":"hi",` +
+	`"created_at":"2024-01-01T00:00:00Z","remote_id":"r1",` +
+	`"metadata":{"is_pinned":true,"new_metadata":{"a":1}}}`
+
+func TestDecodeKeepsExtrasOnReencode(t *testing.T) {
+	var msg Message
+	if err := (&Platform{}).decode([]byte(messageWithExtras), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if string(msg.Extras["remote_id"]) != `"r1"` {
+		t.Fatalf("message extras = %v", msg.Extras)
+	}
+	if string(msg.Metadata.Extras["new_metadata"]) != `{"a":1}` {
+		t.Fatalf("metadata extras = %v", msg.Metadata.Extras)
+	}
+
+	encoded, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["remote_id"] != "r1" || fields["text"] != "hi" {
+		t.Fatalf("re-encoded message = %s", encoded)
+	}
+	if !strings.Contains(string(encoded), `"new_metadata":{"a":1}`) {
+		t.Fatalf("re-encoded message lost the metadata extras: %s", encoded)
+	}
+
+	var again Message
+	if err := json.Unmarshal(encoded, &again); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, msg) {
+		t.Fatalf("round-trip changed the message:\n%+v\n%+v", again, msg)
+	}
+}
+
+func TestStrictDecodeRejectsUnknownFields(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		value      func() interface{}
+		wantType   string
+		wantFields []string
+	}{
+		{
+			name:  "known fields only",
+			data:  `{"id":"p1","text":"hi","metadata":{"is_pinned":true}}`,
+			value: func() interface{} { return &Message{} },
+		},
+		{
+			name:       "message",
+			data:       `{"id":"p1","zeta":1,"remote_id":"r1"}`,
+			value:      func() interface{} { return &Message{} },
+			wantType:   "Message",
+			wantFields: []string{"remote_id", "zeta"},
+		},
+		{
+			name:       "nested metadata",
+			data:       `{"id":"p1","metadata":{"new_metadata":{}}}`,
+			value:      func() interface{} { return &Message{} },
+			wantType:   "MessageMetadata",
+			wantFields: []string{"new_metadata"},
+		},
+		{
+			name:       "message in a list",
+			data:       `[{"id":"p1"},{"id":"p2","remote_id":"r1"}]`,
+			value:      func() interface{} { return &[]Message{} },
+			wantType:   "Message",
+			wantFields: []string{"remote_id"},
+		},
+		{
+			name:       "thread root",
+			data:       `{"root":{"id":"p1","remote_id":"r1"}}`,
+			value:      func() interface{} { return &Thread{} },
+			wantType:   "Message",
+			wantFields: []string{"remote_id"},
+		},
+		{
+			name:       "channel",
+			data:       `{"id":"c1","type":"public","shared":true}`,
+			value:      func() interface{} { return &Channel{} },
+			wantType:   "Channel",
+			wantFields: []string{"shared"},
+		},
+		{
+			name:       "user",
+			data:       `{"id":"u1","username":"alice","metadata":{"locale":"en"}}`,
+			value:      func() interface{} { return &User{} },
+			wantType:   "User",
+			wantFields: []string{"metadata"},
+		},
+	}
+
+	p := &Platform{}
+	p.SetDecodeMode(DecodeStrict)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.decode([]byte(tt.data), tt.value())
+			if tt.wantType == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var unknown *UnknownFieldsError
+			if !errors.As(err, &unknown) {
+				t.Fatalf("error = %v, want an *UnknownFieldsError", err)
+			}
+			if unknown.Type != tt.wantType || !reflect.DeepEqual(unknown.Fields, tt.wantFields) {
+				t.Fatalf("unknown fields = %s %v, want %s %v", unknown.Type, unknown.Fields, tt.wantType, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestDecodeModeIsPerPlatform(t *testing.T) {
+	strict, lenient := &Platform{}, &Platform{}
+	strict.SetDecodeMode(DecodeStrict)
+
+	if err := strict.decode([]byte(messageWithExtras), &Message{}); err == nil {
+		t.Fatal("the strict platform accepted unknown fields")
+	}
+	if lenient.DecodeMode() != DecodeLenient {
+		t.Fatalf("another platform's mode is %v, want lenient", lenient.DecodeMode())
+	}
+	if err := lenient.decode([]byte(messageWithExtras), &Message{}); err != nil {
+		t.Fatalf("the lenient platform rejected unknown fields: %v", err)
+	}
+}
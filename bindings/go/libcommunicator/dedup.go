@@ -0,0 +1,85 @@
+package libcommunicator
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// seqKeyPrefix starts the dedup keys of events identified by their sequence number
+const seqKeyPrefix = "seq:"
+
+// eventDedup remembers the events delivered within a window so replays of
+// them are dropped
+// It is only used by the poll goroutine and needs no lock.
+type eventDedup struct {
+	window time.Duration
+	seen   map[string]time.Time
+	// order holds the keys oldest first, for expiry
+	order []dedupEntry
+}
+
+type dedupEntry struct {
+	key string
+	at  time.Time
+}
+
+func newEventDedup(window time.Duration) *eventDedup {
+	return &eventDedup{window: window, seen: make(map[string]time.Time)}
+}
+
+// duplicate reports whether the event was already seen within the window,
+// and remembers it if not
+func (d *eventDedup) duplicate(event *Event, now time.Time) bool {
+	d.expire(now)
+
+	// A fresh connection numbers its events from the start again
+	if event.Type == EventGapDetected && event.ReceivedSeq < event.ExpectedSeq {
+		for key := range d.seen {
+			if strings.HasPrefix(key, seqKeyPrefix) {
+				delete(d.seen, key)
+			}
+		}
+	}
+
+	key := dedupKey(event)
+	if key == "" {
+		return false
+	}
+	if _, ok := d.seen[key]; ok {
+		return true
+	}
+	d.seen[key] = now
+	d.order = append(d.order, dedupEntry{key: key, at: now})
+	return false
+}
+
+// expire forgets the keys seen before the window
+func (d *eventDedup) expire(now time.Time) {
+	i := 0
+	for ; i < len(d.order) && now.Sub(d.order[i].at) > d.window; i++ {
+		entry := d.order[i]
+		// The key may have been forgotten already, or seen again since
+		if at, ok := d.seen[entry.key]; ok && at.Equal(entry.at) {
+			delete(d.seen, entry.key)
+		}
+	}
+	d.order = d.order[i:]
+}
+
+// dedupKey identifies an event across replays, or returns "" for events
+// that can't be told apart from a repeat
+// Posts and deletions are keyed on the message ID, so recovered posts
+// match the live event; other server events on their sequence number.
+func dedupKey(event *Event) string {
+	switch event.Type {
+	case EventMessagePosted, EventMessageDeleted:
+		if id := eventMessageID(event); id != "" {
+			return event.Type + ":" + id
+		}
+	}
+	if event.Seq > 0 {
+		return seqKeyPrefix + strconv.FormatInt(event.Seq, 10)
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package libcommunicator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDedupKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		event *Event
+		want  string
+	}{
+		{"post by message ID", &Event{Type: EventMessagePosted, Seq: 7, Data: map[string]interface{}{"id": "p1"}}, "message_posted:p1"},
+		{"deletion", &Event{Type: EventMessageDeleted, MessageID: "p1", Seq: 8}, "message_deleted:p1"},
+		{"post without an ID", &Event{Type: EventMessagePosted, Seq: 9}, "seq:9"},
+		{"other event", &Event{Type: EventUserTyping, Seq: 10}, "seq:10"},
+		{"edit by sequence", &Event{Type: EventMessageUpdated, MessageID: "p1", Seq: 11}, "seq:11"},
+		{"unnumbered", &Event{Type: EventUserTyping}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dedupKey(tt.event); got != tt.want {
+				t.Fatalf("dedupKey = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventDedup(t *testing.T) {
+	posted := func(id string, seq int64) *Event {
+		return &Event{Type: EventMessagePosted, Seq: seq, Data: map[string]interface{}{"id": id}}
+	}
+	typing := func(seq int64) *Event {
+		return &Event{Type: EventUserTyping, Seq: seq}
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		name  string
+		event *Event
+		after time.Duration
+		want  bool
+	}{
+		{"first post", posted("p1", 1), 0, false},
+		{"same post replayed with another seq", posted("p1", 5), time.Second, true},
+		{"recovered post without a seq", posted("p1", 0), 2 * time.Second, true},
+		{"numbered event", typing(2), 2 * time.Second, false},
+		{"same seq again", typing(2), 3 * time.Second, true},
+		{"unnumbered events are never dropped", &Event{Type: EventUserTyping}, 3 * time.Second, false},
+		{"unnumbered again", &Event{Type: EventUserTyping}, 3 * time.Second, false},
+		{"reconnect restarts numbering", &Event{Type: EventGapDetected, ExpectedSeq: 3, ReceivedSeq: 1}, 4 * time.Second, false},
+		{"seq reused by the new connection", typing(2), 4 * time.Second, false},
+		{"posts are still remembered", posted("p1", 2), 4 * time.Second, true},
+		{"past the window", posted("p1", 3), 11 * time.Second, false},
+	}
+
+	d := newEventDedup(10 * time.Second)
+	for _, step := range steps {
+		if got := d.duplicate(step.event, start.Add(step.after)); got != step.want {
+			t.Fatalf("%s: duplicate = %v, want %v", step.name, got, step.want)
+		}
+	}
+	d.expire(start.Add(time.Minute))
+	if len(d.seen) != 0 || len(d.order) != 0 {
+		t.Fatalf("%d keys remembered and %d queued after the window", len(d.seen), len(d.order))
+	}
+}
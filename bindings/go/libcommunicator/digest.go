@@ -0,0 +1,343 @@
+package libcommunicator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DigestOptions controls what a Digest collects and how often it posts
+type DigestOptions struct {
+	// Window is how far back each digest looks (default 24h)
+	Window time.Duration
+	// Interval is how often digests are posted (default Window)
+	Interval time.Duration
+	// PostTo is the channel digests are posted to; empty posts each digest in the channel it covers
+	PostTo string
+	// TopThreads is the number of busiest threads listed (default 5)
+	TopThreads int
+	// MaxLinks is the number of shared links listed (default 10)
+	MaxLinks int
+	// ReactionLeaders is the number of most-reacted messages listed (default 3)
+	ReactionLeaders int
+	// MaxMessages caps how many messages are read per channel (default 1000)
+	MaxMessages int
+	// PostEmpty posts a digest even when a channel had no activity
+	PostEmpty bool
+	// Locale renders the digest window in a reader's timezone and date format (default UTC)
+	Locale *Locale
+}
+
+func (o DigestOptions) withDefaults() DigestOptions {
+	if o.Window <= 0 {
+		o.Window = 24 * time.Hour
+	}
+	if o.Interval <= 0 {
+		o.Interval = o.Window
+	}
+	if o.TopThreads <= 0 {
+		o.TopThreads = 5
+	}
+	if o.MaxLinks <= 0 {
+		o.MaxLinks = 10
+	}
+	if o.ReactionLeaders <= 0 {
+		o.ReactionLeaders = 3
+	}
+	if o.MaxMessages <= 0 {
+		o.MaxMessages = 1000
+	}
+	return o
+}
+
+// DigestThread is a thread that was active during a digest window
+type DigestThread struct {
+	RootID    string
+	Preview   string
+	Replies   int
+	Permalink string
+}
+
+// DigestMessage is a message ranked by the reactions it received
+type DigestMessage struct {
+	MessageID string
+	Preview   string
+	Reactions int
+	Permalink string
+}
+
+// DigestSummary is the activity of one channel over a digest window
+type DigestSummary struct {
+	ChannelID       string
+	ChannelName     string
+	From            time.Time
+	To              time.Time
+	MessageCount    int
+	Participants    int
+	TopThreads      []DigestThread
+	Links           []string
+	ReactionLeaders []DigestMessage
+}
+
+// Digest periodically posts a summary of activity in a set of channels
+type Digest struct {
+	platform *Platform
+	channels []string
+	opts     DigestOptions
+
+	// OnError, if set, receives collection and posting failures
+	OnError func(error)
+}
+
+// digestPageSize is how many messages are fetched per history request
+const digestPageSize = 200
+
+// NewDigest creates a digest for the given channels
+func NewDigest(p *Platform, channelIDs []string, opts DigestOptions) *Digest {
+	return &Digest{
+		platform: p,
+		channels: append([]string(nil), channelIDs...),
+		opts:     opts.withDefaults(),
+	}
+}
+
+// Run posts a digest for every channel each Interval until the context is cancelled
+func (d *Digest) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			d.PostAll(now)
+		}
+	}
+}
+
+// PostAll collects and posts the digest of every channel for the window ending at now
+func (d *Digest) PostAll(now time.Time) {
+	for _, channelID := range d.channels {
+		summary, err := d.Collect(channelID, now.Add(-d.opts.Window), now)
+		if err != nil {
+			d.reportError(fmt.Errorf("digest %s: %w", channelID, err))
+			continue
+		}
+		if summary.MessageCount == 0 && !d.opts.PostEmpty {
+			continue
+		}
+
+		target := d.opts.PostTo
+		if target == "" {
+			target = channelID
+		}
+		if _, err := d.platform.SendMessage(target, summary.MarkdownIn(d.opts.Locale)); err != nil {
+			d.reportError(fmt.Errorf("digest %s: %w", channelID, err))
+		}
+	}
+}
+
+// Collect reads a channel's history and summarizes the messages posted in [from, to)
+func (d *Digest) Collect(channelID string, from, to time.Time) (*DigestSummary, error) {
+	messages, err := d.history(channelID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := summarizeDigest(channelID, messages, from, to, d.opts)
+	if channel, err := d.platform.GetChannel(channelID); err == nil {
+		summary.ChannelName = channel.DisplayName
+	}
+	for i := range summary.TopThreads {
+		summary.TopThreads[i].Permalink, _ = d.platform.GetPermalink(summary.TopThreads[i].RootID)
+	}
+	for i := range summary.ReactionLeaders {
+		summary.ReactionLeaders[i].Permalink, _ = d.platform.GetPermalink(summary.ReactionLeaders[i].MessageID)
+	}
+	return summary, nil
+}
+
+// summarizeDigest ranks the threads, links and reactions of the messages posted in [from, to)
+func summarizeDigest(channelID string, messages []Message, from, to time.Time, opts DigestOptions) *DigestSummary {
+	summary := &DigestSummary{ChannelID: channelID, From: from, To: to}
+	participants := make(map[string]bool)
+	threads := make(map[string]*DigestThread)
+	byID := make(map[string]*Message)
+	seenLinks := make(map[string]bool)
+	var reacted []DigestMessage
+
+	for i := range messages {
+		msg := &messages[i]
+		if msg.CreatedAt.Before(from) || !msg.CreatedAt.Before(to) {
+			continue
+		}
+		byID[msg.ID] = msg
+		summary.MessageCount++
+		participants[msg.SenderID] = true
+
+		if rootID := threadRootID(msg); rootID != msg.ID {
+			thread := threads[rootID]
+			if thread == nil {
+				thread = &DigestThread{RootID: rootID, Preview: previewText(msg.Text)}
+				threads[rootID] = thread
+			}
+			thread.Replies++
+		}
+
+		for _, link := range ExtractLinks(msg.Text, 0) {
+			if !seenLinks[link] && len(summary.Links) < opts.MaxLinks {
+				seenLinks[link] = true
+				summary.Links = append(summary.Links, link)
+			}
+		}
+
+		if count := reactionCount(msg); count > 0 {
+			reacted = append(reacted, DigestMessage{MessageID: msg.ID, Preview: previewText(msg.Text), Reactions: count})
+		}
+	}
+	summary.Participants = len(participants)
+
+	for _, thread := range threads {
+		// Prefer the root's text when the root itself falls inside the window
+		if root, ok := byID[thread.RootID]; ok {
+			thread.Preview = previewText(root.Text)
+		}
+		summary.TopThreads = append(summary.TopThreads, *thread)
+	}
+	sort.Slice(summary.TopThreads, func(i, j int) bool {
+		a, b := summary.TopThreads[i], summary.TopThreads[j]
+		if a.Replies != b.Replies {
+			return a.Replies > b.Replies
+		}
+		return a.RootID < b.RootID
+	})
+	if len(summary.TopThreads) > opts.TopThreads {
+		summary.TopThreads = summary.TopThreads[:opts.TopThreads]
+	}
+
+	sort.SliceStable(reacted, func(i, j int) bool { return reacted[i].Reactions > reacted[j].Reactions })
+	if len(reacted) > opts.ReactionLeaders {
+		reacted = reacted[:opts.ReactionLeaders]
+	}
+	summary.ReactionLeaders = reacted
+
+	return summary
+}
+
+// history pages backwards through a channel until it reaches messages older than since
+// Pages are fetched unfiltered so a page of system messages doesn't end the walk early.
+func (d *Digest) history(channelID string, since time.Time) ([]Message, error) {
+	page, err := d.platform.getMessages(channelID, digestPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []Message
+	for len(page) > 0 {
+		SortMessages(page)
+		messages = append(page, messages...)
+
+		oldest := page[0]
+		if oldest.CreatedAt.Before(since) || len(messages) >= d.opts.MaxMessages || len(page) < digestPageSize {
+			break
+		}
+
+		page, err = d.platform.getMessagesBefore(channelID, oldest.ID, digestPageSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return d.platform.filterMessages(messages), nil
+}
+
+// Markdown renders the summary as a chat message
+func (s *DigestSummary) Markdown() string {
+	return s.MarkdownIn(nil)
+}
+
+// MarkdownIn renders the summary as a chat message with the window in the given locale
+// A nil locale renders the window in UTC.
+func (s *DigestSummary) MarkdownIn(locale *Locale) string {
+	var b strings.Builder
+
+	name := s.ChannelName
+	if name == "" {
+		name = s.ChannelID
+	}
+	fmt.Fprintf(&b, "#### Digest for %s\n", escapeMarkdown(name))
+	var from, to string
+	if locale != nil {
+		from, to = locale.FormatDate(s.From)+" "+locale.FormatClock(s.From), locale.FormatTimestamp(s.To)
+	} else {
+		from, to = s.From.UTC().Format("Jan 2 15:04"), s.To.UTC().Format("Jan 2 15:04 MST")
+	}
+	fmt.Fprintf(&b, "%s – %s · %d messages from %d people\n", from, to, s.MessageCount, s.Participants)
+
+	if len(s.TopThreads) > 0 {
+		b.WriteString("\n**Top threads**\n")
+		for _, t := range s.TopThreads {
+			fmt.Fprintf(&b, "- %s (%d %s)\n", linkedPreview(t.Preview, t.Permalink), t.Replies, plural(t.Replies, "reply", "replies"))
+		}
+	}
+
+	if len(s.ReactionLeaders) > 0 {
+		b.WriteString("\n**Most reactions**\n")
+		for _, m := range s.ReactionLeaders {
+			fmt.Fprintf(&b, "- %s (%d %s)\n", linkedPreview(m.Preview, m.Permalink), m.Reactions, plural(m.Reactions, "reaction", "reactions"))
+		}
+	}
+
+	if len(s.Links) > 0 {
+		b.WriteString("\n**Links shared**\n")
+		for _, link := range s.Links {
+			fmt.Fprintf(&b, "- %s\n", link)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (d *Digest) reportError(err error) {
+	if d.OnError != nil {
+		d.OnError(err)
+	}
+}
+
+// reactionCount returns the number of reactions recorded in a message's metadata
+func reactionCount(msg *Message) int {
+	if msg.Metadata == nil {
+		return 0
+	}
+	return len(msg.Metadata.Reactions)
+}
+
+// previewText shortens a message to a single line suitable for a list item
+func previewText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	const maxRunes = 80
+	if runes := []rune(text); len(runes) > maxRunes {
+		text = string(runes[:maxRunes-1]) + "…"
+	}
+	if text == "" {
+		text = "(no text)"
+	}
+	return text
+}
+
+func linkedPreview(preview, permalink string) string {
+	if permalink == "" {
+		return escapeMarkdown(preview)
+	}
+	return fmt.Sprintf("[%s](%s)", escapeMarkdown(preview), permalink)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
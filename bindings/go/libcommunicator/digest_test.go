@@ -0,0 +1,146 @@
+package libcommunicator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func digestMessage(id, sender string, at time.Time, text string, reactions int) Message {
+	msg := Message{ID: id, SenderID: sender, CreatedAt: at, Text: text}
+	if reactions > 0 {
+		msg.Metadata = &MessageMetadata{Reactions: make([]Reaction, reactions)}
+	}
+	return msg
+}
+
+func TestSummarizeDigest(t *testing.T) {
+	from := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	at := func(minutes int) time.Time { return from.Add(time.Duration(minutes) * time.Minute) }
+	reply := func(msg Message, rootID string) Message {
+		msg.RootID = rootID
+		return msg
+	}
+
+	messages := []Message{
+		digestMessage("old", "u1", at(-30), "before https://old.example", 5),
+		digestMessage("r1", "u1", at(5), "deploy   thread", 1),
+		reply(digestMessage("a", "u2", at(10), "first", 0), "r1"),
+		reply(digestMessage("b", "u3", at(15), "second", 0), "r1"),
+		reply(digestMessage("c", "u2", at(20), "reply to an old thread", 0), "old"),
+		digestMessage("e", "u4", at(30), "see https://a.example and https://a.example https://b.example", 3),
+		digestMessage("f", "u1", at(40), "f", 2),
+		digestMessage("g", "u1", at(45), "g", 2),
+		digestMessage("late", "u5", to, "at the end of the window https://late.example", 9),
+	}
+	// A reply identified by its metadata, tied with the reply to "old"
+	metaReply := digestMessage("d", "u2", at(25), "other thread", 0)
+	metaReply.Metadata = &MessageMetadata{RootID: "r2"}
+	messages = append(messages, metaReply)
+
+	tests := []struct {
+		name    string
+		opts    DigestOptions
+		threads []DigestThread
+		leaders []string
+		links   []string
+	}{
+		{
+			name: "defaults",
+			threads: []DigestThread{
+				{RootID: "r1", Preview: "deploy thread", Replies: 2},
+				{RootID: "old", Preview: "reply to an old thread", Replies: 1},
+				{RootID: "r2", Preview: "other thread", Replies: 1},
+			},
+			leaders: []string{"e", "f", "g"},
+			links:   []string{"https://a.example", "https://b.example"},
+		},
+		{
+			name:    "limits",
+			opts:    DigestOptions{TopThreads: 2, ReactionLeaders: 4, MaxLinks: 1},
+			threads: []DigestThread{{RootID: "r1", Preview: "deploy thread", Replies: 2}, {RootID: "old", Preview: "reply to an old thread", Replies: 1}},
+			leaders: []string{"e", "f", "g", "r1"},
+			links:   []string{"https://a.example"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := summarizeDigest("c1", messages, from, to, tt.opts.withDefaults())
+			if s.MessageCount != 8 || s.Participants != 4 {
+				t.Fatalf("%d messages from %d people, want 8 from 4", s.MessageCount, s.Participants)
+			}
+			if !reflect.DeepEqual(s.TopThreads, tt.threads) {
+				t.Fatalf("top threads = %+v, want %+v", s.TopThreads, tt.threads)
+			}
+			var leaders []string
+			for _, m := range s.ReactionLeaders {
+				leaders = append(leaders, m.MessageID)
+			}
+			if !reflect.DeepEqual(leaders, tt.leaders) {
+				t.Fatalf("reaction leaders = %v, want %v", leaders, tt.leaders)
+			}
+			if s.ReactionLeaders[0].Reactions != 3 {
+				t.Fatalf("leader has %d reactions, want 3", s.ReactionLeaders[0].Reactions)
+			}
+			if !reflect.DeepEqual(s.Links, tt.links) {
+				t.Fatalf("links = %v, want %v", s.Links, tt.links)
+			}
+		})
+	}
+}
+
+func TestDigestSummaryMarkdown(t *testing.T) {
+	s := &DigestSummary{
+		ChannelID:       "c1",
+		ChannelName:     "Town Square",
+		From:            time.Date(2024, 3, 4, 14, 0, 0, 0, time.UTC),
+		To:              time.Date(2024, 3, 5, 14, 0, 0, 0, time.UTC),
+		MessageCount:    12,
+		Participants:    3,
+		TopThreads:      []DigestThread{{RootID: "r1", Preview: "release plan", Replies: 1, Permalink: "https://chat.example.com/pl/r1"}},
+		ReactionLeaders: []DigestMessage{{MessageID: "m1", Preview: "shipped", Reactions: 4}},
+		Links:           []string{"https://example.com"},
+	}
+
+	want := strings.Join([]string{
+		"#### Digest for Town Square",
+		"Mar 4 14:00 – Mar 5 14:00 UTC · 12 messages from 3 people",
+		"",
+		"**Top threads**",
+		"- [release plan](https://chat.example.com/pl/r1) (1 reply)",
+		"",
+		"**Most reactions**",
+		"- shipped (4 reactions)",
+		"",
+		"**Links shared**",
+		"- https://example.com",
+	}, "\n")
+	if got := s.Markdown(); got != want {
+		t.Fatalf("Markdown =\n%s\nwant\n%s", got, want)
+	}
+
+	locale := &Locale{Language: "de", Location: time.FixedZone("CET", 3600)}
+	if got, _, _ := strings.Cut(s.MarkdownIn(locale), " ·"); !strings.HasSuffix(got, "04.03.2024 15:00 – 05.03.2024 15:00 CET") {
+		t.Fatalf("window in a locale = %q", got)
+	}
+}
+
+func TestPreviewText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"  several\n lines\tof text ", "several lines of text"},
+		{"", "(no text)"},
+		{" \n", "(no text)"},
+		{strings.Repeat("é", 80), strings.Repeat("é", 80)},
+		{strings.Repeat("é", 81), strings.Repeat("é", 79) + "…"},
+	}
+	for _, tt := range tests {
+		if got := previewText(tt.text); got != tt.want {
+			t.Errorf("previewText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package libcommunicator
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// FileCipher encrypts the files written by the library's file-backed stores
+// (onboarding progress, dead letters and saved session state)
+type FileCipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(data []byte) ([]byte, error)
+}
+
+// ErrDecrypt is returned when an encrypted file cannot be decrypted with the given key
+var ErrDecrypt = errors.New("libcommunicator: cannot decrypt file (wrong key or corrupted data)")
+
+// ErrNotEncrypted is returned when a cipher is given data that was never
+// encrypted; see NewMigratingCipher for reading stores written before
+// encryption was enabled
+var ErrNotEncrypted = errors.New("libcommunicator: file is not encrypted")
+
+// encryptedMagic prefixes every encrypted file, followed by the salt length,
+// the salt, the nonce and the AES-GCM sealed data
+var encryptedMagic = []byte("LCE1")
+
+// pbkdf2Iterations follows the current OWASP recommendation for PBKDF2-HMAC-SHA256
+const pbkdf2Iterations = 600000
+
+type aesFileCipher struct {
+	salt   []byte // written with new files; empty for raw keys
+	key    []byte // key for salt
+	derive func(salt []byte) ([]byte, error)
+
+	mu   sync.Mutex
+	keys map[string][]byte // derived keys by salt, for files written with another salt
+}
+
+// NewKeyCipher encrypts with AES-GCM using a raw 16, 24 or 32 byte key
+func NewKeyCipher(key []byte) (FileCipher, error) {
+	if _, err := aes.NewCipher(key); err != nil {
+		return nil, err
+	}
+	return &aesFileCipher{key: append([]byte(nil), key...)}, nil
+}
+
+// NewPassphraseCipher encrypts with AES-256-GCM using a key derived from a passphrase
+func NewPassphraseCipher(passphrase string) (FileCipher, error) {
+	if passphrase == "" {
+		return nil, errors.New("libcommunicator: empty passphrase")
+	}
+
+	derive := func(salt []byte) ([]byte, error) {
+		return pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, 32)
+	}
+
+	// Derive once per cipher rather than once per write; each file records
+	// its salt so files written by other processes still decrypt
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := derive(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &aesFileCipher{salt: salt, key: key, derive: derive, keys: make(map[string][]byte)}, nil
+}
+
+// Encrypt seals plaintext with a fresh random nonce
+func (c *aesFileCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(c.key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(encryptedMagic)+1+len(c.salt)+len(nonce)+len(plaintext)+gcm.Overhead())
+	out = append(out, encryptedMagic...)
+	out = append(out, byte(len(c.salt)))
+	out = append(out, c.salt...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plaintext, encryptedMagic), nil
+}
+
+// Decrypt opens data written by Encrypt
+// Data without the encryption header fails with ErrNotEncrypted: anyone
+// able to write the file could otherwise substitute their own plaintext.
+func (c *aesFileCipher) Decrypt(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, encryptedMagic) {
+		return nil, ErrNotEncrypted
+	}
+	rest := data[len(encryptedMagic):]
+	if len(rest) < 1 || len(rest) < 1+int(rest[0]) {
+		return nil, ErrDecrypt
+	}
+	salt := rest[1 : 1+int(rest[0])]
+	rest = rest[1+len(salt):]
+
+	key, err := c.keyFor(salt)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < gcm.NonceSize() {
+		return nil, ErrDecrypt
+	}
+
+	plaintext, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], encryptedMagic)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+func (c *aesFileCipher) keyFor(salt []byte) ([]byte, error) {
+	if bytes.Equal(salt, c.salt) {
+		return c.key, nil
+	}
+	if c.derive == nil {
+		return nil, ErrDecrypt
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if key, ok := c.keys[string(salt)]; ok {
+		return key, nil
+	}
+	key, err := c.derive(salt)
+	if err != nil {
+		return nil, err
+	}
+	c.keys[string(salt)] = key
+	return key, nil
+}
+
+// NewMigratingCipher wraps c to also read data written before encryption
+// was enabled: data c rejects with ErrNotEncrypted is returned unchanged
+// Stores re-encrypt it on their next write. Use it only while migrating,
+// since it accepts plaintext from anyone able to write the files.
+func NewMigratingCipher(c FileCipher) FileCipher {
+	return migratingCipher{c}
+}
+
+type migratingCipher struct {
+	FileCipher
+}
+
+func (c migratingCipher) Decrypt(data []byte) ([]byte, error) {
+	plaintext, err := c.FileCipher.Decrypt(data)
+	if errors.Is(err, ErrNotEncrypted) {
+		return data, nil
+	}
+	return plaintext, err
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// NewKeychainCipher encrypts with a random AES-256 key kept in the OS keychain
+// The key is created on first use. macOS uses the login keychain via
+// security(1); Linux uses the Secret Service via secret-tool(1).
+func NewKeychainCipher(service, account string) (FileCipher, error) {
+	key, err := keychainLookup(service, account)
+	if err != nil {
+		return nil, err
+	}
+
+	if key == nil {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		if err := keychainStore(service, account, key); err != nil {
+			return nil, err
+		}
+		// Read it back: security -i doesn't report failed commands in its
+		// exit status
+		if key, err = keychainLookup(service, account); err != nil {
+			return nil, err
+		}
+		if key == nil {
+			return nil, fmt.Errorf("libcommunicator: keychain didn't keep the key for %s/%s", service, account)
+		}
+	}
+
+	return NewKeyCipher(key)
+}
+
+// keychainLookup returns the stored key, or nil if none exists yet
+// Any other failure, such as a locked keychain, denied access or no D-Bus
+// session, is an error: taking it for a missing key would replace the key
+// and leave every file encrypted with it unreadable.
+func keychainLookup(service, account string) ([]byte, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	default:
+		return nil, fmt.Errorf("libcommunicator: no keychain support on %s", runtime.GOOS)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && keychainItemMissing(runtime.GOOS, exitErr.ExitCode(), stderr.Bytes()) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("libcommunicator: keychain lookup: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	encoded := strings.TrimSpace(string(out))
+	if encoded == "" {
+		return nil, nil
+	}
+	key, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("libcommunicator: keychain item %s/%s is not a key: %w", service, account, err)
+	}
+	return key, nil
+}
+
+// keychainItemMissing reports whether a lookup tool's exit status means the
+// item doesn't exist: security(1) exits with errSecItemNotFound (44), and
+// secret-tool(1) exits with 1 without printing anything, as opposed to the
+// message it prints when it fails
+func keychainItemMissing(goos string, code int, stderr []byte) bool {
+	switch goos {
+	case "darwin":
+		return code == 44
+	case "linux":
+		return code == 1 && len(bytes.TrimSpace(stderr)) == 0
+	}
+	return false
+}
+
+func keychainStore(service, account string, key []byte) error {
+	encoded := hex.EncodeToString(key)
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// security -i reads the command from stdin, keeping the key out of
+		// the process list, where any local user could read it
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -s %s -a %s -w %s\n",
+			securityQuote(service), securityQuote(account), encoded))
+	case "linux":
+		// secret-tool reads the secret from stdin, keeping it off the command line
+		cmd = exec.Command("secret-tool", "store", "--label="+service, "service", service, "account", account)
+		cmd.Stdin = strings.NewReader(encoded)
+	default:
+		return fmt.Errorf("libcommunicator: no keychain support on %s", runtime.GOOS)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("libcommunicator: keychain store: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// securityQuote quotes an argument for the command line security -i reads
+func securityQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,195 @@
+package libcommunicator
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeyCipherRoundTrip(t *testing.T) {
+	c, err := NewKeyCipher(bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := []byte(`{"token":"secret"}`)
+	sealed, err := c.Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sealed, plaintext) {
+		t.Fatal("sealed data contains the plaintext")
+	}
+	again, err := c.Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(sealed, again) {
+		t.Fatal("two encryptions of the same data are identical; nonce reused")
+	}
+
+	got, err := c.Decrypt(sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("Decrypt = %q, want %q", got, plaintext)
+	}
+}
+
+func TestPassphraseCipherReadsOtherSalts(t *testing.T) {
+	writer, err := NewPassphraseCipher("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := NewPassphraseCipher("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := writer.Encrypt([]byte("state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reader.Decrypt(sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "state" {
+		t.Fatalf("Decrypt = %q, want %q", got, "state")
+	}
+
+	wrong, err := NewPassphraseCipher("wrong horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wrong.Decrypt(sealed); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("Decrypt with the wrong passphrase = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestCipherDetectsTampering(t *testing.T) {
+	c, err := NewKeyCipher(bytes.Repeat([]byte{1}, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := c.Encrypt([]byte("hello, world"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range sealed {
+		tampered := bytes.Clone(sealed)
+		tampered[i] ^= 0x01
+		if _, err := c.Decrypt(tampered); err == nil {
+			t.Fatalf("flipping byte %d went unnoticed", i)
+		}
+	}
+	for n := range len(sealed) {
+		if _, err := c.Decrypt(sealed[:n]); err == nil {
+			t.Fatalf("truncating to %d bytes went unnoticed", n)
+		}
+	}
+}
+
+func TestCipherRejectsPlaintext(t *testing.T) {
+	c, err := NewKeyCipher(bytes.Repeat([]byte{2}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := []byte(`{"token":"attacker"}`)
+
+	if _, err := c.Decrypt(forged); !errors.Is(err, ErrNotEncrypted) {
+		t.Fatalf("Decrypt(plaintext) = %v, want ErrNotEncrypted", err)
+	}
+
+	migrating := NewMigratingCipher(c)
+	got, err := migrating.Decrypt(forged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, forged) {
+		t.Fatalf("migrating Decrypt = %q, want the data unchanged", got)
+	}
+
+	sealed, err := migrating.Encrypt([]byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c.Decrypt(sealed); err != nil || string(got) != "new" {
+		t.Fatalf("migrating cipher doesn't encrypt: %q, %v", got, err)
+	}
+	sealed[len(sealed)-1] ^= 0x01
+	if _, err := migrating.Decrypt(sealed); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("migrating Decrypt(tampered) = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestEncryptedDeadLettersRejectPlaintextLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead.jsonl")
+	c, err := NewKeyCipher(bytes.Repeat([]byte{3}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewEncryptedFileDeadLetterSink(path, c).Put(&DeadLetter{Errors: []string{"sealed"}}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"errors":["forged"]}` + "\n")
+	f.Close()
+
+	if _, err := ReadEncryptedDeadLetters(path, c); !errors.Is(err, ErrNotEncrypted) {
+		t.Fatalf("ReadEncryptedDeadLetters = %v, want ErrNotEncrypted", err)
+	}
+	letters, err := ReadEncryptedDeadLetters(path, NewMigratingCipher(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(letters) != 2 || letters[0].Errors[0] != "sealed" || letters[1].Errors[0] != "forged" {
+		t.Fatalf("letters = %+v", letters)
+	}
+}
+
+func TestKeychainItemMissing(t *testing.T) {
+	tests := []struct {
+		goos   string
+		code   int
+		stderr string
+		want   bool
+	}{
+		{"darwin", 44, "security: SecKeychainSearchCopyNext: The specified item could not be found in the keychain.", true},
+		{"darwin", 36, "security: SecKeychainSearchCopyNext: User interaction is not allowed.", false},
+		{"darwin", 51, "", false},
+		{"linux", 1, "", true},
+		{"linux", 1, "secret-tool: Cannot autolaunch D-Bus without X11 $DISPLAY", false},
+		{"linux", 1, "secret-tool: Cannot create an item in a locked collection", false},
+		{"linux", 2, "", false},
+		{"windows", 1, "", false},
+	}
+	for _, tt := range tests {
+		if got := keychainItemMissing(tt.goos, tt.code, []byte(tt.stderr)); got != tt.want {
+			t.Errorf("keychainItemMissing(%s, %d, %q) = %v, want %v", tt.goos, tt.code, tt.stderr, got, tt.want)
+		}
+	}
+}
+
+func TestSecurityQuote(t *testing.T) {
+	tests := map[string]string{
+		"bot":            `"bot"`,
+		"my bot":         `"my bot"`,
+		`say "hi"`:       `"say \"hi\""`,
+		`C:\keys`:        `"C:\\keys"`,
+		`trailing\`:      `"trailing\\"`,
+		`-w injected -U`: `"-w injected -U"`,
+	}
+	for in, want := range tests {
+		if got := securityQuote(in); got != want {
+			t.Errorf("securityQuote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package libcommunicator
+
+import "errors"
+
+// Sentinel errors for the error kinds callers commonly branch on
+// Errors from the library match them with errors.Is by error code, e.g.
+//
+//	if errors.Is(err, ErrNotFound) { ... }
+//
+// errors.As with a *PlatformError gives the code and message.
+var (
+	// ErrNotFound means the requested object doesn't exist or isn't visible
+	ErrNotFound = &PlatformError{Code: ErrorNotFound, Message: "not found"}
+	// ErrAuthFailed means the credentials or session token were rejected
+	ErrAuthFailed = &PlatformError{Code: ErrorAuthFailed, Message: "authentication failed"}
+	// ErrPermissionDenied means the account may not perform the operation
+	ErrPermissionDenied = &PlatformError{Code: ErrorPermDenied, Message: "permission denied"}
+	// ErrRateLimited means the server kept rejecting the request with HTTP 429
+	// after the retries PlatformConfig.RateLimit allows
+	ErrRateLimited = &PlatformError{Code: ErrorRateLimited, Message: "rate limited"}
+	// ErrTimeout means a request ran into one of the configured Timeouts
+	ErrTimeout = &PlatformError{Code: ErrorTimeout, Message: "timed out"}
+	// ErrNetwork means the server could not be reached or the connection broke off
+	ErrNetwork = &PlatformError{Code: ErrorNetwork, Message: "network error"}
+)
+
+// ErrClosed is returned by calls on a Platform, Context or EventStream
+// after it has been closed
+var ErrClosed = &PlatformError{Code: ErrorInvalidState, Message: "use of closed handle"}
+
+// codeSentinels maps error codes to the sentinel errors they match
+var codeSentinels = map[ErrorCode]error{
+	ErrorNotFound:            ErrNotFound,
+	ErrorAuthFailed:          ErrAuthFailed,
+	ErrorPermDenied:          ErrPermissionDenied,
+	ErrorRateLimited:         ErrRateLimited,
+	ErrorTimeout:             ErrTimeout,
+	ErrorNetwork:             ErrNetwork,
+	ErrorUnsupportedByServer: ErrUnsupportedByServer,
+}
+
+// codeMatches reports whether target is the sentinel error for code
+func codeMatches(code ErrorCode, target error) bool {
+	sentinel, ok := codeSentinels[code]
+	return ok && target == sentinel
+}
+
+// Retryable reports whether trying the call again later may succeed:
+// network errors, timeouts and rate limiting are transient, other errors
+// will fail the same way
+func (c ErrorCode) Retryable() bool {
+	switch c {
+	case ErrorNetwork, ErrorTimeout, ErrorRateLimited:
+		return true
+	}
+	return false
+}
+
+// IsRetryable reports whether err, or an error it wraps, is retryable
+func IsRetryable(err error) bool {
+	var retryable interface{ Retryable() bool }
+	return errors.As(err, &retryable) && retryable.Retryable()
+}
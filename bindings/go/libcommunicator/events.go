@@ -2,19 +2,89 @@ package libcommunicator
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// SchemaMismatchError reports an event payload that did not match its expected schema
+// It is only produced when strict event validation is enabled
+type SchemaMismatchError struct {
+	EventType string
+	Issues    []SchemaIssue
+}
+
+func (e *SchemaMismatchError) Error() string {
+	problems := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		problems[i] = issue.Path + ": " + issue.Problem
+	}
+	return fmt.Sprintf("schema mismatch in %q event: %s", e.EventType, strings.Join(problems, "; "))
+}
+
 // EventStream provides a Go-idiomatic way to consume platform events
 type EventStream struct {
-	platform     *Platform
-	events       chan *Event
-	errors       chan error
-	done         chan struct{}
-	pollInterval time.Duration
-	wg           sync.WaitGroup
-	once         sync.Once
+	platform *Platform
+	events   chan *Event
+	errors   chan error
+	done     chan struct{}
+	opts     EventStreamOptions
+	wg       sync.WaitGroup
+	once     sync.Once
+
+	// history is a ring of the last HistorySize events; next is the slot
+	// the next event goes to
+	historyMu   sync.Mutex
+	history     []*Event
+	historyNext int
+
+	dropped atomic.Uint64
+	spilled atomic.Uint64
+	spillMu sync.Mutex
+	spill   *eventSpill
+
+	dedup      *eventDedup
+	duplicates atomic.Uint64
+
+	lane        *priorityLane
+	prioritized atomic.Uint64
+}
+
+// EventStreamOptions tunes how an EventStream polls, trading latency for CPU
+type EventStreamOptions struct {
+	// BufferSize is the capacity of the Events channel
+	BufferSize int
+	// PollInterval is the time between polls (default 100ms)
+	PollInterval time.Duration
+	// MaxIdleInterval enables adaptive backoff: every poll that finds no
+	// event doubles the interval, up to MaxIdleInterval, and the next event
+	// resets it to PollInterval; 0 keeps the interval fixed
+	MaxIdleInterval time.Duration
+	// MaxBurst is the number of events drained per poll without waiting
+	// for the next one (default 1); raise it for high-throughput consumers
+	MaxBurst int
+	// Filter scopes the subscription; see SubscribeEventsFiltered
+	Filter EventFilter
+	// HistorySize is the number of recent events kept for History; 0 keeps none
+	HistorySize int
+	// Backpressure is what happens to events while the Events channel is full
+	Backpressure BackpressurePolicy
+	// SpillDir is where BackpressureSpill keeps its file (default os.TempDir())
+	SpillDir string
+	// DedupWindow drops events already delivered within the window, such as
+	// those replayed after a reconnect, so each is seen once; 0 keeps them
+	DedupWindow time.Duration
+	// Journal, if set, records events before delivering them and replays
+	// those left unacknowledged by a previous run first; see EventStream.Ack
+	// The stream doesn't close it.
+	Journal *EventJournal
+	// PriorityLane delivers direct messages and messages mentioning the
+	// current user on Priority instead of Events, ahead of channel chatter
+	PriorityLane bool
 }
 
 // NewEventStream creates a new event stream for the platform
@@ -22,21 +92,44 @@ type EventStream struct {
 // pollInterval specifies how frequently to poll for events (e.g., 100*time.Millisecond)
 // If pollInterval is 0, a default of 100ms is used
 func (p *Platform) NewEventStream(ctx context.Context, bufferSize int, pollInterval time.Duration) (*EventStream, error) {
-	if err := p.SubscribeEvents(); err != nil {
+	return p.NewEventStreamWithOptions(ctx, EventStreamOptions{BufferSize: bufferSize, PollInterval: pollInterval})
+}
+
+// NewEventStreamWithOptions creates a new event stream polling as opts describe
+func (p *Platform) NewEventStreamWithOptions(ctx context.Context, opts EventStreamOptions) (*EventStream, error) {
+	if opts.PollInterval < 0 || opts.MaxIdleInterval < 0 || opts.MaxBurst < 0 || opts.BufferSize < 0 || opts.HistorySize < 0 || opts.DedupWindow < 0 {
+		return nil, errors.New("event stream: options must not be negative")
+	}
+	if opts.BufferSize == 0 && (opts.Backpressure == BackpressureDropOldest || opts.Backpressure == BackpressureSpill) {
+		return nil, errors.New("event stream: the backpressure policy needs a BufferSize")
+	}
+	if err := p.SubscribeEventsFiltered(opts.Filter); err != nil {
 		return nil, err
 	}
 
 	// Use default poll interval if not specified
-	if pollInterval == 0 {
-		pollInterval = 100 * time.Millisecond
+	if opts.PollInterval == 0 {
+		opts.PollInterval = 100 * time.Millisecond
+	}
+	if opts.MaxBurst == 0 {
+		opts.MaxBurst = 1
 	}
 
 	stream := &EventStream{
-		platform:     p,
-		events:       make(chan *Event, bufferSize),
-		errors:       make(chan error, 10),
-		done:         make(chan struct{}),
-		pollInterval: pollInterval,
+		platform: p,
+		events:   make(chan *Event, opts.BufferSize),
+		errors:   make(chan error, 10),
+		done:     make(chan struct{}),
+		opts:     opts,
+	}
+	if opts.HistorySize > 0 {
+		stream.history = make([]*Event, 0, opts.HistorySize)
+	}
+	if opts.DedupWindow > 0 {
+		stream.dedup = newEventDedup(opts.DedupWindow)
+	}
+	if opts.PriorityLane {
+		stream.lane = newPriorityLane(opts.BufferSize)
 	}
 
 	stream.wg.Add(1)
@@ -55,14 +148,71 @@ func (s *EventStream) Errors() <-chan error {
 	return s.errors
 }
 
+// History returns the last EventStreamOptions.HistorySize events, oldest
+// first, whether or not they have been read from Events
+// It lets a consumer attaching late, such as a debug view, catch up. The
+// events are shared with the Events channel; don't modify them. After Close
+// it returns ErrClosed.
+func (s *EventStream) History() ([]*Event, error) {
+	if s.closed() {
+		return nil, ErrClosed
+	}
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+
+	if len(s.history) < cap(s.history) {
+		return slices.Clone(s.history), nil
+	}
+	return append(slices.Clone(s.history[s.historyNext:]), s.history[:s.historyNext]...), nil
+}
+
+// closed reports whether Close has been called
+func (s *EventStream) closed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// remember adds an event to the history ring, replacing the oldest when full
+func (s *EventStream) remember(event *Event) {
+	if s.opts.HistorySize == 0 {
+		return
+	}
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+
+	if len(s.history) < cap(s.history) {
+		s.history = append(s.history, event)
+		return
+	}
+	s.history[s.historyNext] = event
+	s.historyNext = (s.historyNext + 1) % len(s.history)
+}
+
 // poll continuously polls for events in the background
 func (s *EventStream) poll(ctx context.Context) {
 	defer s.wg.Done()
 	defer close(s.events)
 	defer close(s.errors)
+	defer s.closeSpill()
+	if s.lane != nil {
+		defer close(s.lane.events)
+	}
+
+	if s.opts.Journal != nil {
+		for _, event := range s.opts.Journal.Pending() {
+			if !s.deliver(ctx, event) {
+				return
+			}
+		}
+	}
 
-	ticker := time.NewTicker(s.pollInterval)
-	defer ticker.Stop()
+	interval := s.opts.PollInterval
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
 
 	for {
 		select {
@@ -70,39 +220,91 @@ func (s *EventStream) poll(ctx context.Context) {
 			return
 		case <-s.done:
 			return
-		case <-ticker.C:
-			// Poll for events
-			event, err := s.platform.PollEvent()
-			if err != nil {
-				select {
-				case s.errors <- err:
-				default:
-					// Error channel is full, drop the error
-					// Consider logging this in production use
-				}
-				continue
-			}
+		case <-timer.C:
+		}
 
-			if event != nil {
-				select {
-				case s.events <- event:
-				case <-ctx.Done():
-					return
-				case <-s.done:
-					return
-				}
+		if s.opts.Backpressure == BackpressureSpill {
+			s.drainSpill()
+		}
+
+		active := false
+		for range s.opts.MaxBurst {
+			got, ok := s.pollOnce(ctx)
+			if !ok {
+				return
 			}
+			if !got {
+				break
+			}
+			active = true
+		}
+
+		// Back off while idle, snap back on activity
+		if active {
+			interval = s.opts.PollInterval
+		} else if interval < s.opts.MaxIdleInterval {
+			interval = min(2*interval, s.opts.MaxIdleInterval)
+		}
+		timer.Reset(interval)
+	}
+}
+
+// pollOnce polls for one event and delivers it
+// got reports whether an event arrived; ok is false once the stream should stop.
+func (s *EventStream) pollOnce(ctx context.Context) (got, ok bool) {
+	event, err := s.platform.PollEvent()
+	if err != nil {
+		select {
+		case s.errors <- err:
+		default:
+			// Error channel is full, drop the error
+			// Consider logging this in production use
+		}
+		// A closed platform has no more events; report that once and stop
+		return false, !errors.Is(err, ErrClosed)
+	}
+	if event == nil {
+		return false, true
+	}
+
+	if event.Type == EventSchemaMismatch {
+		s.reportError(&SchemaMismatchError{EventType: event.EventType, Issues: event.Issues})
+		return true, true
+	}
+	if s.dedup != nil && s.dedup.duplicate(event, time.Now()) {
+		s.duplicates.Add(1)
+		return true, true
+	}
+
+	s.remember(event)
+	if s.opts.Journal != nil && journaled(event) {
+		if err := s.opts.Journal.Append(event); err != nil {
+			s.reportError(err)
 		}
 	}
+	if s.lane != nil && s.isPriority(event) {
+		return true, s.deliverPriority(ctx, event)
+	}
+	return true, s.deliver(ctx, event)
 }
 
-// Close closes the event stream
+// Close closes the event stream and unsubscribes from events, implementing io.Closer
+// The Events, Errors and Priority channels are closed once polling stops;
+// events still buffered in them can be read. Ack, History and Stats return
+// ErrClosed afterwards, so a journal replays the events not acknowledged
+// before Close. Closing a closed stream does nothing, and closing a stream
+// after its platform doesn't fail.
+//
+// Polling also stops on its own once the platform is closed, after
+// reporting ErrClosed on Errors; the stream still needs closing.
 func (s *EventStream) Close() error {
 	var err error
 	s.once.Do(func() {
 		close(s.done)
 		s.wg.Wait()
-		err = s.platform.UnsubscribeEvents()
+		if err = s.platform.UnsubscribeEvents(); errors.Is(err, ErrClosed) {
+			err = nil
+		}
 	})
 	return err
 }
@@ -112,116 +314,339 @@ type EventHandler func(*Event)
 
 // EventRouter routes events to handlers based on event type
 type EventRouter struct {
-	handlers map[string][]EventHandler
-	mu       sync.RWMutex
+	handlers  map[string][]routedHandler
+	any       []routedHandler
+	unhandled []routedHandler
+	nextID    uint64
+	pools     []*handlerPool
+	onError   func(error)
+	errors    chan error
+	mu        sync.RWMutex
+	// done is closed by Close, ending the backoff of retried handlers
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// routedHandler is a registered handler with the ID its Subscription removes it by
+type routedHandler struct {
+	id      uint64
+	handler EventHandler
+	pool    *handlerPool // set for OnWithOptions handlers, which run on it
+}
+
+// Subscription is a handler registration on an EventRouter
+type Subscription struct {
+	router *EventRouter
+	ids    []uint64
+	once   sync.Once
+}
+
+// Unsubscribe removes the handler from the router; calling it again does nothing
+// An event already being dispatched may still reach the handler.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.router.remove(s.ids...)
+	})
 }
 
 // NewEventRouter creates a new event router
 func NewEventRouter() *EventRouter {
 	return &EventRouter{
-		handlers: make(map[string][]EventHandler),
+		handlers: make(map[string][]routedHandler),
+		errors:   make(chan error, routerErrorBuffer),
+		done:     make(chan struct{}),
 	}
 }
 
 // On registers a handler for a specific event type
-func (r *EventRouter) On(eventType string, handler EventHandler) {
+func (r *EventRouter) On(eventType string, handler EventHandler) *Subscription {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.handlers[eventType] = append(r.handlers[eventType], handler)
+	h := r.newHandler(handler)
+	r.handlers[eventType] = append(r.handlers[eventType], h)
+	return &Subscription{router: r, ids: []uint64{h.id}}
+}
+
+// OnAny registers a handler for every event, e.g. for logging
+// It runs before the handlers registered for the event's type.
+func (r *EventRouter) OnAny(handler EventHandler) *Subscription {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h := r.newHandler(handler)
+	r.any = append(r.any, h)
+	return &Subscription{router: r, ids: []uint64{h.id}}
+}
+
+// OnUnhandled registers a handler for events of types with no handler of
+// their own, including types this package has no constant for
+func (r *EventRouter) OnUnhandled(handler EventHandler) *Subscription {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h := r.newHandler(handler)
+	r.unhandled = append(r.unhandled, h)
+	return &Subscription{router: r, ids: []uint64{h.id}}
+}
+
+// RemoveAll removes every handler registered for an event type
+// OnAny and OnUnhandled handlers are kept; events of the type fall through
+// to the OnUnhandled handlers from now on. The workers of OnWithOptions
+// handlers stop once the events already queued for them are handled.
+func (r *EventRouter) RemoveAll(eventType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, h := range r.handlers[eventType] {
+		if h.pool != nil {
+			// Not waited for, as in remove
+			go r.stopPool(h.pool)
+		}
+	}
+	delete(r.handlers, eventType)
+}
+
+// newHandler assigns a handler its ID; r.mu must be held
+func (r *EventRouter) newHandler(handler EventHandler) routedHandler {
+	r.nextID++
+	return routedHandler{id: r.nextID, handler: handler}
+}
+
+// remove unregisters the handlers with the given IDs and stops their pools
+func (r *EventRouter) remove(ids ...uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, handlers := range r.handlers {
+		for _, h := range handlers {
+			if h.pool == nil || !slices.Contains(ids, h.id) {
+				continue
+			}
+			// Not waited for: the handler itself may be unsubscribing, and a
+			// dispatch blocked on its full queue holds up stop until it drains
+			go r.stopPool(h.pool)
+		}
+	}
+	for eventType, handlers := range r.handlers {
+		if handlers = withoutHandlers(handlers, ids); len(handlers) == 0 {
+			delete(r.handlers, eventType)
+		} else {
+			r.handlers[eventType] = handlers
+		}
+	}
+	r.any = withoutHandlers(r.any, ids)
+	r.unhandled = withoutHandlers(r.unhandled, ids)
+}
+
+// withoutHandlers returns handlers without the ones with the given IDs
+// The result is a copy, as Handle may be iterating over the original.
+func withoutHandlers(handlers []routedHandler, ids []uint64) []routedHandler {
+	kept := make([]routedHandler, 0, len(handlers))
+	for _, h := range handlers {
+		if !slices.Contains(ids, h.id) {
+			kept = append(kept, h)
+		}
+	}
+	return kept
+}
+
+// joinSubscriptions combines registrations so they are removed together
+func (r *EventRouter) joinSubscriptions(subs ...*Subscription) *Subscription {
+	joined := &Subscription{router: r}
+	for _, sub := range subs {
+		joined.ids = append(joined.ids, sub.ids...)
+	}
+	return joined
 }
 
 // OnMessagePosted registers a handler for message posted events
-func (r *EventRouter) OnMessagePosted(handler EventHandler) {
-	r.On(EventMessagePosted, handler)
+func (r *EventRouter) OnMessagePosted(handler EventHandler) *Subscription {
+	return r.On(EventMessagePosted, handler)
 }
 
 // OnMessageUpdated registers a handler for message updated events
-func (r *EventRouter) OnMessageUpdated(handler EventHandler) {
-	r.On(EventMessageUpdated, handler)
+func (r *EventRouter) OnMessageUpdated(handler EventHandler) *Subscription {
+	return r.On(EventMessageUpdated, handler)
 }
 
 // OnMessageDeleted registers a handler for message deleted events
-func (r *EventRouter) OnMessageDeleted(handler EventHandler) {
-	r.On(EventMessageDeleted, handler)
+func (r *EventRouter) OnMessageDeleted(handler EventHandler) *Subscription {
+	return r.On(EventMessageDeleted, handler)
+}
+
+// OnPostPinned registers a handler for post pinned events
+func (r *EventRouter) OnPostPinned(handler EventHandler) *Subscription {
+	return r.On(EventPostPinned, handler)
+}
+
+// OnPostUnpinned registers a handler for post unpinned events
+func (r *EventRouter) OnPostUnpinned(handler EventHandler) *Subscription {
+	return r.On(EventPostUnpinned, handler)
 }
 
 // OnUserStatusChanged registers a handler for user status changed events
-func (r *EventRouter) OnUserStatusChanged(handler EventHandler) {
-	r.On(EventUserStatusChanged, handler)
+func (r *EventRouter) OnUserStatusChanged(handler EventHandler) *Subscription {
+	return r.On(EventUserStatusChanged, handler)
 }
 
 // OnUserTyping registers a handler for user typing events
-func (r *EventRouter) OnUserTyping(handler EventHandler) {
-	r.On(EventUserTyping, handler)
+func (r *EventRouter) OnUserTyping(handler EventHandler) *Subscription {
+	return r.On(EventUserTyping, handler)
 }
 
 // OnChannelCreated registers a handler for channel created events
-func (r *EventRouter) OnChannelCreated(handler EventHandler) {
-	r.On(EventChannelCreated, handler)
+func (r *EventRouter) OnChannelCreated(handler EventHandler) *Subscription {
+	return r.On(EventChannelCreated, handler)
 }
 
 // OnChannelUpdated registers a handler for channel updated events
-func (r *EventRouter) OnChannelUpdated(handler EventHandler) {
-	r.On(EventChannelUpdated, handler)
+// event.Changes holds the old and new display name, topic and purpose when known
+func (r *EventRouter) OnChannelUpdated(handler EventHandler) *Subscription {
+	return r.On(EventChannelUpdated, handler)
 }
 
 // OnChannelDeleted registers a handler for channel deleted events
-func (r *EventRouter) OnChannelDeleted(handler EventHandler) {
-	r.On(EventChannelDeleted, handler)
+func (r *EventRouter) OnChannelDeleted(handler EventHandler) *Subscription {
+	return r.On(EventChannelDeleted, handler)
 }
 
 // OnUserJoinedChannel registers a handler for user joined channel events
-func (r *EventRouter) OnUserJoinedChannel(handler EventHandler) {
-	r.On(EventUserJoinedChannel, handler)
+func (r *EventRouter) OnUserJoinedChannel(handler EventHandler) *Subscription {
+	return r.On(EventUserJoinedChannel, handler)
 }
 
 // OnUserLeftChannel registers a handler for user left channel events
-func (r *EventRouter) OnUserLeftChannel(handler EventHandler) {
-	r.On(EventUserLeftChannel, handler)
+func (r *EventRouter) OnUserLeftChannel(handler EventHandler) *Subscription {
+	return r.On(EventUserLeftChannel, handler)
 }
 
 // OnConnectionStateChanged registers a handler for connection state changed events
-func (r *EventRouter) OnConnectionStateChanged(handler EventHandler) {
-	r.On(EventConnectionStateChange, handler)
+func (r *EventRouter) OnConnectionStateChanged(handler EventHandler) *Subscription {
+	return r.On(EventConnectionStateChange, handler)
+}
+
+// OnRateLimited registers a handler for requests the server rate limited
+// See Event.Endpoint, Event.Attempt and Event.NextRetry.
+func (r *EventRouter) OnRateLimited(handler EventHandler) *Subscription {
+	return r.On(EventRateLimited, handler)
+}
+
+// OnGapDetected registers a handler for events lost to a sequence gap
+// Refetch what the application tracks, e.g. recent messages of open channels.
+func (r *EventRouter) OnGapDetected(handler EventHandler) *Subscription {
+	return r.On(EventGapDetected, handler)
 }
 
 // Handle dispatches an event to all registered handlers
+// OnAny handlers run first, then those for the event's type, or the OnUnhandled
+// handlers if there are none. A panicking handler is reported as a
+// *HandlerError and doesn't stop the others.
 func (r *EventRouter) Handle(event *Event) {
+	r.dispatch(event, nil)
+}
+
+// dispatch is Handle calling done, if not nil, once every handler has
+// finished with the event, including those running in a pool
+func (r *EventRouter) dispatch(event *Event, done func()) {
 	r.mu.RLock()
-	handlers, ok := r.handlers[event.Type]
+	anyHandlers := r.any
+	handlers := r.handlers[event.Type]
+	if len(handlers) == 0 {
+		handlers = r.unhandled
+	}
 	r.mu.RUnlock()
 
-	if !ok {
-		return
+	var finished func()
+	if done != nil {
+		// One count for each pooled handler, plus one for this call
+		remaining := new(atomic.Int32)
+		remaining.Store(1)
+		finished = func() {
+			if remaining.Add(-1) == 0 {
+				done()
+			}
+		}
+		defer finished()
+		for _, h := range slices.Concat(anyHandlers, handlers) {
+			if h.pool != nil {
+				remaining.Add(1)
+			}
+		}
 	}
 
-	for _, handler := range handlers {
-		handler(event)
+	for _, h := range slices.Concat(anyHandlers, handlers) {
+		if h.pool != nil {
+			h.pool.dispatch(event, finished)
+		} else {
+			r.call(h.handler, event)
+		}
 	}
 }
 
+// acker acknowledges events once the router is done with them, and waits
+// for those still being handled
+type acker struct {
+	router   *EventRouter
+	ack      func(*Event) error
+	inFlight sync.WaitGroup
+}
+
+// handle dispatches an event and acknowledges it after its last handler
+func (a *acker) handle(event *Event) {
+	a.inFlight.Add(1)
+	a.router.dispatch(event, func() {
+		defer a.inFlight.Done()
+		if err := a.ack(event); err != nil {
+			a.router.reportError(err)
+		}
+	})
+}
+
 // Run starts the event router with an event stream
-// It will block until the context is cancelled
+// It will block until the context is cancelled. Events of a journaled
+// stream are acknowledged once every handler has finished with them,
+// including handlers running in a pool; on return, Run waits for those
+// before closing the stream.
 func (r *EventRouter) Run(ctx context.Context, stream *EventStream) error {
+	acks := &acker{router: r, ack: stream.Ack}
 	defer stream.Close()
+	defer acks.inFlight.Wait()
+	dispatch := acks.handle
 
+	priority := stream.Priority()
 	for {
+		// Drain the priority lane before anything else
+		select {
+		case event, ok := <-priority:
+			if !ok {
+				priority = nil
+				continue
+			}
+			dispatch(event)
+			continue
+		default:
+		}
+
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
+		case event, ok := <-priority:
+			if !ok {
+				priority = nil
+				continue
+			}
+			dispatch(event)
 		case event, ok := <-stream.Events():
 			if !ok {
 				return nil
 			}
-			r.Handle(event)
+			dispatch(event)
 		case err, ok := <-stream.Errors():
 			if !ok {
 				return nil
 			}
-			// Log or handle errors appropriately
-			// For now, we just continue
-			_ = err
+			r.reportError(err)
 		}
 	}
 }
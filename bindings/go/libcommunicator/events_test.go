@@ -0,0 +1,75 @@
+package libcommunicator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEventStreamAfterClose(t *testing.T) {
+	stream := &EventStream{
+		platform: &Platform{},
+		events:   make(chan *Event, 1),
+		errors:   make(chan error, 10),
+		done:     make(chan struct{}),
+		opts:     EventStreamOptions{HistorySize: 2},
+	}
+	if _, err := stream.History(); err != nil {
+		t.Fatalf("History before Close = %v", err)
+	}
+	if _, err := stream.Stats(); err != nil {
+		t.Fatalf("Stats before Close = %v", err)
+	}
+
+	// The platform is already closed, which Close must not report
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+	if err := stream.Ack(&Event{JournalID: 1}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Ack after Close = %v, want ErrClosed", err)
+	}
+	if _, err := stream.History(); !errors.Is(err, ErrClosed) {
+		t.Errorf("History after Close = %v, want ErrClosed", err)
+	}
+	if _, err := stream.Stats(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Stats after Close = %v, want ErrClosed", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Errorf("second Close = %v", err)
+	}
+}
+
+func TestEventStreamStopsWhenPlatformCloses(t *testing.T) {
+	stream := &EventStream{
+		platform: &Platform{},
+		events:   make(chan *Event, 1),
+		errors:   make(chan error, 10),
+		done:     make(chan struct{}),
+		opts:     EventStreamOptions{PollInterval: time.Millisecond, MaxBurst: 1},
+	}
+	stream.wg.Add(1)
+	go stream.poll(context.Background())
+
+	var errs []error
+	timeout := time.After(time.Second)
+	for done := false; !done; {
+		select {
+		case err, ok := <-stream.Errors():
+			if !ok {
+				done = true
+				break
+			}
+			errs = append(errs, err)
+		case <-timeout:
+			t.Fatal("polling a closed platform didn't stop")
+		}
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrClosed) {
+		t.Fatalf("errors = %v, want a single ErrClosed", errs)
+	}
+	if _, ok := <-stream.Events(); ok {
+		t.Fatal("Events left open after polling stopped")
+	}
+	stream.Close()
+}
@@ -8,15 +8,31 @@ package libcommunicator
 */
 import "C"
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 	"unsafe"
 )
 
 // UploadFile uploads a file to a channel
 // Returns the file ID on success
-func (p *Platform) UploadFile(channelID, filePath string) (string, error) {
+// If a FileScanner is installed the file is scanned first
+func (p *Platform) UploadFile(channelID, filePath string) (_ string, err error) {
+	defer p.audit("UploadFile", "channel_id", channelID)(&err)
+	if err := p.checkWritable("UploadFile"); err != nil {
+		return "", err
+	}
+	// Scan before taking the handle: the scanner may take a while, and a
+	// quarantine note is sent through the platform
+	if err := p.scanPath(channelID, filePath); err != nil {
+		return "", err
+	}
+	if !p.acquire() {
+		return "", ErrClosed
+	}
+	defer p.release()
+
 	cChannelID := C.CString(channelID)
 	defer C.free(unsafe.Pointer(cChannelID))
 
@@ -34,7 +50,27 @@ func (p *Platform) UploadFile(channelID, filePath string) (string, error) {
 
 // DownloadFile downloads a file by its ID
 // Returns the file contents as bytes
+// If a FileScanner is installed the contents are scanned before being returned
 func (p *Platform) DownloadFile(fileID string) ([]byte, error) {
+	data, err := p.downloadFile(fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scanned after the handle is given back, for the same reasons as in UploadFile
+	if err := p.scanBytes(fileID, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (p *Platform) downloadFile(fileID string) ([]byte, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
 	cFileID := C.CString(fileID)
 	defer C.free(unsafe.Pointer(cFileID))
 
@@ -56,8 +92,44 @@ func (p *Platform) DownloadFile(fileID string) ([]byte, error) {
 	return goData, nil
 }
 
+// DownloadFileRange downloads length bytes of a file starting at offset
+// A length of 0 reads to the end of the file; fewer bytes are returned when
+// the file ends first. Use it to resume interrupted downloads or to seek in
+// media. Ranges are not passed to a FileScanner, since a fragment can't be
+// scanned reliably; scan the reassembled file instead.
+func (p *Platform) DownloadFileRange(fileID string, offset, length int64) ([]byte, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if offset < 0 || length < 0 {
+		return nil, fmt.Errorf("invalid range: offset %d, length %d", offset, length)
+	}
+
+	cFileID := C.CString(fileID)
+	defer C.free(unsafe.Pointer(cFileID))
+
+	var data *C.uint8_t
+	var size C.size_t
+
+	code := C.communicator_platform_download_file_range(p.handle, cFileID, C.uint64_t(offset), C.uint64_t(length), &data, &size)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return nil, getLastError()
+	}
+
+	goData := C.GoBytes(unsafe.Pointer(data), C.int(size))
+	C.communicator_free_file_data(data, size)
+
+	return goData, nil
+}
+
 // GetFileMetadata retrieves file metadata without downloading the file
 func (p *Platform) GetFileMetadata(fileID string) (*Attachment, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
 	cFileID := C.CString(fileID)
 	defer C.free(unsafe.Pointer(cFileID))
 
@@ -70,7 +142,7 @@ func (p *Platform) GetFileMetadata(fileID string) (*Attachment, error) {
 	jsonStr := C.GoString(result)
 
 	var metadata Attachment
-	if err := json.Unmarshal([]byte(jsonStr), &metadata); err != nil {
+	if err := p.decode([]byte(jsonStr), &metadata); err != nil {
 		return nil, fmt.Errorf("failed to parse file metadata: %w", err)
 	}
 
@@ -80,6 +152,11 @@ func (p *Platform) GetFileMetadata(fileID string) (*Attachment, error) {
 // GetFileThumbnail downloads a file thumbnail by its ID
 // Returns the thumbnail image as bytes
 func (p *Platform) GetFileThumbnail(fileID string) ([]byte, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
 	cFileID := C.CString(fileID)
 	defer C.free(unsafe.Pointer(cFileID))
 
@@ -105,6 +182,11 @@ func (p *Platform) GetFileThumbnail(fileID string) ([]byte, error) {
 // This is similar to DownloadFile but may return an optimized preview version
 // Returns the preview image/file as bytes
 func (p *Platform) GetFilePreview(fileID string) ([]byte, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
 	cFileID := C.CString(fileID)
 	defer C.free(unsafe.Pointer(cFileID))
 
@@ -128,7 +210,13 @@ func (p *Platform) GetFilePreview(fileID string) ([]byte, error) {
 
 // GetFileLink generates a public URL for accessing a file
 // Returns the public URL as a string
+// The link is remembered for ListPublicLinks.
 func (p *Platform) GetFileLink(fileID string) (string, error) {
+	if !p.acquire() {
+		return "", ErrClosed
+	}
+	defer p.release()
+
 	cFileID := C.CString(fileID)
 	defer C.free(unsafe.Pointer(cFileID))
 
@@ -138,7 +226,133 @@ func (p *Platform) GetFileLink(fileID string) (string, error) {
 	}
 
 	defer C.communicator_free_string(result)
-	return C.GoString(result), nil
+	link := C.GoString(result)
+	p.trackPublicLink(fileID, link)
+	return link, nil
+}
+
+// PublicLinksEnabled reports whether the server allows public file links
+// Administrators can disable them at any time; GetFileLink returns an error
+// matching ErrUnsupportedByServer while they are disabled.
+func (p *Platform) PublicLinksEnabled() (bool, error) {
+	if !p.acquire() {
+		return false, ErrClosed
+	}
+	defer p.release()
+
+	result := C.communicator_platform_public_links_enabled(p.handle)
+	if result < 0 {
+		return false, getLastError()
+	}
+
+	return result == 1, nil
+}
+
+// EnablePublicLink enables public file links server-wide (administrators only)
+func (p *Platform) EnablePublicLink() (err error) {
+	defer p.audit("EnablePublicLink")(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("EnablePublicLink"); err != nil {
+		return err
+	}
+
+	code := C.communicator_platform_enable_public_links(p.handle)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
+// RevokePublicLink disables public file links server-wide and invalidates every
+// link issued so far, even if links are enabled again later (administrators only)
+func (p *Platform) RevokePublicLink() (err error) {
+	defer p.audit("RevokePublicLink")(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("RevokePublicLink"); err != nil {
+		return err
+	}
+
+	code := C.communicator_platform_revoke_public_links(p.handle)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	p.linksMu.Lock()
+	p.publicLinks = nil
+	p.linksMu.Unlock()
+	return nil
+}
+
+// PublicLink is a public file link issued by GetFileLink
+type PublicLink struct {
+	FileID   string
+	URL      string
+	IssuedAt time.Time
+}
+
+// RevokeFileLink invalidates the public link of a single file; other links keep working
+// Returns ErrUnsupported on Mattermost, which signs every link with one
+// server-wide salt; use RevokePublicLink there.
+func (p *Platform) RevokeFileLink(fileID string) (err error) {
+	defer p.audit("RevokeFileLink", "file_id", fileID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("RevokeFileLink"); err != nil {
+		return err
+	}
+
+	cFileID, free := cStringFree(fileID)
+	defer free()
+
+	code := C.communicator_platform_revoke_file_link(p.handle, cFileID)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	p.linksMu.Lock()
+	delete(p.publicLinks, fileID)
+	p.linksMu.Unlock()
+	return nil
+}
+
+// ListPublicLinks returns the public links issued by GetFileLink on this
+// Platform and not revoked since, oldest first
+// Servers keep no record of issued links, so links issued by other
+// processes or before a restart are not listed.
+func (p *Platform) ListPublicLinks() []PublicLink {
+	p.linksMu.Lock()
+	links := make([]PublicLink, 0, len(p.publicLinks))
+	for _, link := range p.publicLinks {
+		links = append(links, link)
+	}
+	p.linksMu.Unlock()
+
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].IssuedAt.Before(links[j].IssuedAt)
+	})
+	return links
+}
+
+func (p *Platform) trackPublicLink(fileID, url string) {
+	p.linksMu.Lock()
+	defer p.linksMu.Unlock()
+	if p.publicLinks == nil {
+		p.publicLinks = make(map[string]PublicLink)
+	}
+	// Links are stable per file; keep the first issue time
+	if existing, ok := p.publicLinks[fileID]; ok && existing.URL == url {
+		return
+	}
+	p.publicLinks[fileID] = PublicLink{FileID: fileID, URL: url, IssuedAt: time.Now()}
 }
 
 // WriteFile is a convenience function that writes file data to disk
@@ -0,0 +1,131 @@
+package libcommunicator
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// Native fuzz targets. Without -fuzz, go test runs them on their seed corpus
+// only; to fuzz one:
+//
+//	go test -run '^$' -fuzz '^FuzzEvent$' -fuzztime 1m
+//
+// The native library must be linkable, as for any other test.
+
+var fuzzMessageSeeds = []string{
+	messageWithExtras,
+	`{"id":"p1","text":"hi","created_at":"2024-01-01T00:00:00Z","edited_at":"2023-12-31T00:00:00Z"}`,
+	`{"id":"p1","attachments":[{"id":"f1","size":-1}],"metadata":{"reactions":[{"emoji_name":"+1"}]}}`,
+	`{"created_at":"not a time"}`,
+	`{}`,
+	`null`,
+}
+
+// FuzzEvent feeds arbitrary JSON to the event decoder used by PollEvent,
+// including the message and channel payloads carried in Event.Data
+func FuzzEvent(f *testing.F) {
+	f.Add([]byte(`{"type":"message_posted","channel_id":"c1","data":` + messageWithExtras + `}`))
+	f.Add([]byte(`{"type":"post_pinned","data":{"id":"p1","metadata":{"is_pinned":true}}}`))
+	f.Add([]byte(`{"type":"channel_created","data":{"id":"c1","type":"private","shared":true}}`))
+	f.Add([]byte(`{"type":"message_updated","data":"not an object","seq":-1}`))
+	f.Add([]byte(`{"type":"user_typing","user_id":"u1","channel_id":"c1"}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			return
+		}
+
+		switch event.Type {
+		case EventMessagePosted, EventMessageUpdated, EventPostPinned, EventPostUnpinned:
+			var msg Message
+			fuzzDecodeData(t, event.Data, &msg)
+			msg.Normalize()
+		case EventChannelCreated, EventChannelUpdated:
+			var channel Channel
+			fuzzDecodeData(t, event.Data, &channel)
+		}
+
+		fuzzReencode(t, &event, new(Event))
+	})
+}
+
+// FuzzMessage feeds arbitrary JSON to the Message decoder in both decode modes
+func FuzzMessage(f *testing.F) {
+	for _, seed := range fuzzMessageSeeds {
+		f.Add([]byte(seed))
+	}
+
+	strictPlatform := &Platform{}
+	strictPlatform.SetDecodeMode(DecodeStrict)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var strict Message
+		strictErr := strictPlatform.decode(data, &strict)
+
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			if strictErr == nil {
+				t.Fatalf("strict decode accepted input lenient decode rejected: %v", err)
+			}
+			return
+		}
+
+		msg.Normalize()
+		if msg.EditedAt != nil && msg.EditedAt.Before(msg.CreatedAt) {
+			t.Fatal("Normalize left EditedAt before CreatedAt")
+		}
+		_ = msg.CreatedAtMillis()
+		_ = msg.EditedAtMillis()
+		SortMessages([]Message{msg, strict})
+
+		fuzzReencode(t, &msg, new(Message))
+	})
+}
+
+// FuzzCString passes arbitrary strings across the cgo boundary into native
+// functions that need no platform handle
+func FuzzCString(f *testing.F) {
+	f.Add("https://chat.example.com/team/pl/p1")
+	f.Add("chat.example.com/team/channels/town-square")
+	f.Add("message")
+	f.Add("before\x00after")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		// Errors are expected; crashes and undecodable successes are not
+		if link, err := ParseMessageLink(s); err == nil && link == nil {
+			t.Fatal("ParseMessageLink returned neither a link nor an error")
+		}
+		if sample, err := nativeSample(s); err == nil && !json.Valid(sample) {
+			t.Fatalf("nativeSample(%q) returned invalid JSON", s)
+		}
+		for typeName := range schemaGoTypes {
+			if out, err := nativeRoundTrip(typeName, []byte(s)); err == nil && !json.Valid(out) {
+				t.Fatalf("native round-trip of %s returned invalid JSON", typeName)
+			}
+		}
+	})
+}
+
+// fuzzDecodeData decodes an event's generic Data payload into v the way
+// callers do, by re-encoding it; decode failures are fine
+func fuzzDecodeData(t *testing.T, data interface{}, v interface{}) {
+	t.Helper()
+	raw, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("decoded event data does not re-encode: %v", err)
+	}
+	_ = json.Unmarshal(raw, v)
+}
+
+// fuzzReencode checks that a decoded value encodes and decodes again
+func fuzzReencode(t *testing.T, v interface{}, fresh interface{}) {
+	t.Helper()
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("decoded %T does not re-encode: %v", v, err)
+	}
+	if err := json.Unmarshal(out, fresh); err != nil {
+		t.Fatalf("re-encoded %T does not decode: %v\n%s", v, err, out)
+	}
+}
@@ -0,0 +1,356 @@
+package libcommunicator
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Golden-file checks of the Go types against the native schema. Every type
+// the native library has a sample for is round-tripped through its Go mirror
+// and back through the native type, and the samples are compared with the
+// golden files in testdata/golden. After an intended schema change,
+// regenerate the goldens with:
+//
+//	go test -run TestGoldenFiles -update-golden
+
+var updateGolden = flag.Bool("update-golden", false, "rewrite testdata/golden from the native samples")
+
+const goldenDir = "testdata/golden"
+
+// schemaGoTypes maps native schema type names to the Go types mirroring them
+var schemaGoTypes = map[string]func() interface{}{
+	"message":                 func() interface{} { return &Message{} },
+	"channel":                 func() interface{} { return &Channel{} },
+	"user":                    func() interface{} { return &User{} },
+	"team":                    func() interface{} { return &Team{} },
+	"connection_info":         func() interface{} { return &ConnectionInfo{} },
+	"emoji":                   func() interface{} { return &Emoji{} },
+	"session":                 func() interface{} { return &Session{} },
+	"session_state":           func() interface{} { return &SessionState{} },
+	"sidebar_category":        func() interface{} { return &SidebarCategory{} },
+	"channel_bookmark":        func() interface{} { return &ChannelBookmark{} },
+	"user_notify_props":       func() interface{} { return &UserNotifyProps{} },
+	"member_sync_result":      func() interface{} { return &MemberSyncResult{} },
+	"command_response":        func() interface{} { return &CommandResponse{} },
+	"incoming_webhook":        func() interface{} { return &IncomingWebhook{} },
+	"bot":                     func() interface{} { return &Bot{} },
+	"message_priority":        func() interface{} { return &MessagePriority{} },
+	"message_acknowledgement": func() interface{} { return &MessageAcknowledgement{} },
+	"thread_list":             func() interface{} { return &ThreadList{} },
+	"user_group":              func() interface{} { return &UserGroup{} },
+	"user_status_info":        func() interface{} { return &Status{} },
+	"team_stats":              func() interface{} { return &TeamStats{} },
+	"link_metadata":           func() interface{} { return &LinkMetadata{} },
+	"unread_totals":           func() interface{} { return &UnreadTotals{} },
+	"system_stats":            func() interface{} { return &SystemStats{} },
+	"slash_command":           func() interface{} { return &SlashCommand{} },
+	"connection_health":       func() interface{} { return &ConnectionHealth{} },
+}
+
+const schemaEventPrefix = "event."
+
+// schemaDrift lists the differences between a native type and its Go mirror
+// Paths use dots for object keys and [i] for array elements, e.g. "attachments[0].url"
+type schemaDrift struct {
+	Type string
+	// MissingInGo holds fields the native JSON has that are lost decoding into the Go struct
+	MissingInGo []string
+	// UnknownToNative holds fields the Go struct encodes that the native type drops
+	UnknownToNative []string
+	// Changed holds fields whose value is altered by a Go decode/encode round-trip
+	Changed []string
+	// GoldenMismatch holds fields where the native sample no longer matches the golden file
+	GoldenMismatch []string
+}
+
+func (d *schemaDrift) hasDrift() bool {
+	return len(d.MissingInGo) > 0 || len(d.UnknownToNative) > 0 ||
+		len(d.Changed) > 0 || len(d.GoldenMismatch) > 0
+}
+
+func (d *schemaDrift) String() string {
+	if !d.hasDrift() {
+		return d.Type + ": ok"
+	}
+	var parts []string
+	add := func(label string, paths []string) {
+		if len(paths) > 0 {
+			parts = append(parts, label+" "+strings.Join(paths, ", "))
+		}
+	}
+	add("missing in Go:", d.MissingInGo)
+	add("unknown to native:", d.UnknownToNative)
+	add("changed:", d.Changed)
+	add("golden mismatch:", d.GoldenMismatch)
+	return d.Type + ": " + strings.Join(parts, "; ")
+}
+
+// newSchemaValue returns a pointer to the Go type mirroring a native type
+func newSchemaValue(typeName string) (interface{}, error) {
+	if strings.HasPrefix(typeName, schemaEventPrefix) {
+		return &Event{}, nil
+	}
+	newValue, ok := schemaGoTypes[typeName]
+	if !ok {
+		return nil, fmt.Errorf("no Go type registered for schema type %q", typeName)
+	}
+	return newValue(), nil
+}
+
+// checkSchemaJSON round-trips data through the Go type and back through the native type
+func checkSchemaJSON(typeName string, data []byte) (*schemaDrift, error) {
+	value, err := newSchemaValue(typeName)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return nil, fmt.Errorf("decode %s sample: %w", typeName, err)
+	}
+	goJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", typeName, err)
+	}
+
+	want, err := flattenJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	got, err := flattenJSON(goJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := &schemaDrift{Type: typeName}
+	drift.MissingInGo, drift.Changed = diffPaths(want, got)
+
+	// Events are output-only, the native side cannot decode them
+	if !strings.HasPrefix(typeName, schemaEventPrefix) {
+		native, err := nativeRoundTrip(typeName, goJSON)
+		if err != nil {
+			return nil, err
+		}
+		back, err := flattenJSON(native)
+		if err != nil {
+			return nil, err
+		}
+		drift.UnknownToNative, _ = diffPaths(got, back)
+	}
+
+	return drift, nil
+}
+
+// flattenJSON maps every leaf path of a JSON document to its encoded value
+// Empty objects and arrays are leaves, null values are skipped
+func flattenJSON(data []byte) (map[string]string, error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	leaves := make(map[string]string)
+	flattenValue("", doc, leaves)
+	return leaves, nil
+}
+
+func flattenValue(path string, v interface{}, leaves map[string]string) {
+	switch value := v.(type) {
+	case nil:
+		return
+	case map[string]interface{}:
+		if len(value) > 0 {
+			for key, child := range value {
+				childPath := key
+				if path != "" {
+					childPath = path + "." + key
+				}
+				flattenValue(childPath, child, leaves)
+			}
+			return
+		}
+	case []interface{}:
+		if len(value) > 0 {
+			for i, child := range value {
+				flattenValue(path+"["+strconv.Itoa(i)+"]", child, leaves)
+			}
+			return
+		}
+	}
+	encoded, _ := json.Marshal(v)
+	leaves[path] = string(encoded)
+}
+
+// diffPaths returns the sorted paths of want absent from got, and those present with another value
+func diffPaths(want, got map[string]string) (missing, changed []string) {
+	for path, value := range want {
+		other, ok := got[path]
+		switch {
+		case !ok:
+			missing = append(missing, path)
+		case other != value:
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(changed)
+	return missing, changed
+}
+
+func TestSchemaSamplesMatchGoTypes(t *testing.T) {
+	names, err := schemaTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		sample, err := nativeSample(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		drift, err := checkSchemaJSON(name, sample)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if drift.hasDrift() {
+			t.Error(drift)
+		}
+	}
+}
+
+func TestGoldenFiles(t *testing.T) {
+	names, err := schemaTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *updateGolden {
+		if err := os.MkdirAll(goldenDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range names {
+			sample, err := nativeSample(name)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, sample, "", "  "); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			buf.WriteByte('\n')
+			if err := os.WriteFile(filepath.Join(goldenDir, name+".json"), buf.Bytes(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return
+	}
+	if _, err := os.Stat(goldenDir); os.IsNotExist(err) {
+		t.Skip("no golden files, generate them with -update-golden")
+	}
+
+	for _, name := range names {
+		golden, err := os.ReadFile(filepath.Join(goldenDir, name+".json"))
+		if os.IsNotExist(err) {
+			t.Errorf("%s: no golden file, run with -update-golden", name)
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The golden file must still round-trip through both sides
+		drift, err := checkSchemaJSON(name, golden)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		// and match the current native sample, or the schema changed
+		// without the goldens being regenerated
+		sample, err := nativeSample(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		want, err := flattenJSON(golden)
+		if err != nil {
+			t.Fatalf("golden file %s: %v", name, err)
+		}
+		got, err := flattenJSON(sample)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		missing, changed := diffPaths(want, got)
+		added, _ := diffPaths(got, want)
+		drift.GoldenMismatch = append(append(missing, changed...), added...)
+		sort.Strings(drift.GoldenMismatch)
+
+		if drift.hasDrift() {
+			t.Error(drift)
+		}
+	}
+}
+
+func TestDiffPaths(t *testing.T) {
+	tests := []struct {
+		name      string
+		want, got string
+		missing   []string
+		changed   []string
+	}{
+		{
+			name: "equal",
+			want: `{"id":"p1","metadata":{"is_pinned":true}}`,
+			got:  `{"metadata":{"is_pinned":true},"id":"p1"}`,
+		},
+		{
+			name:    "missing nested field",
+			want:    `{"id":"p1","metadata":{"is_pinned":true,"priority":"urgent"}}`,
+			got:     `{"id":"p1","metadata":{"is_pinned":true}}`,
+			missing: []string{"metadata.priority"},
+		},
+		{
+			name:    "changed array element",
+			want:    `{"attachments":[{"url":"a"},{"url":"b"}]}`,
+			got:     `{"attachments":[{"url":"a"},{"url":"c"}]}`,
+			changed: []string{"attachments[1].url"},
+		},
+		{
+			name:    "empty containers are leaves",
+			want:    `{"props":{},"reactions":[]}`,
+			got:     `{}`,
+			missing: []string{"props", "reactions"},
+		},
+		{
+			name: "null values are skipped",
+			want: `{"id":"p1","edited_at":null}`,
+			got:  `{"id":"p1"}`,
+		},
+		{
+			name:    "number formatting",
+			want:    `{"size":1.0}`,
+			got:     `{"size":"1"}`,
+			changed: []string{"size"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := flattenJSON([]byte(tt.want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := flattenJSON([]byte(tt.got))
+			if err != nil {
+				t.Fatal(err)
+			}
+			missing, changed := diffPaths(want, got)
+			if !reflect.DeepEqual(missing, tt.missing) || !reflect.DeepEqual(changed, tt.changed) {
+				t.Fatalf("diffPaths = %v, %v, want %v, %v", missing, changed, tt.missing, tt.changed)
+			}
+		})
+	}
+}
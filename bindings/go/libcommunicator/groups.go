@@ -0,0 +1,187 @@
+package libcommunicator
+
+/*
+#include <communicator.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"time"
+)
+
+// User group sources
+const (
+	UserGroupSourceCustom = "custom"
+	UserGroupSourceLDAP   = "ldap"
+)
+
+// UserGroup is a group of users that can be @-mentioned together
+type UserGroup struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"` // mention name, without the leading @
+	DisplayName    string    `json:"display_name"`
+	Description    string    `json:"description"`
+	Source         string    `json:"source"` // UserGroupSourceCustom or a directory source
+	AllowReference bool      `json:"allow_reference"`
+	MemberCount    *int64    `json:"member_count,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// NewUserGroup holds the parameters for creating a custom user group
+type NewUserGroup struct {
+	Name        string   `json:"name"` // mention name; a leading @ is ignored
+	DisplayName string   `json:"display_name"`
+	Description string   `json:"description,omitempty"`
+	UserIDs     []string `json:"user_ids"`
+}
+
+// Mention returns the text that mentions the group, e.g. "@backend"
+func (g *UserGroup) Mention() string {
+	return "@" + g.Name
+}
+
+// CreateUserGroup creates a custom user group with the given members
+// Returns an error matching ErrUnsupportedByServer on servers without custom groups
+func (p *Platform) CreateUserGroup(group NewUserGroup) (_ *UserGroup, err error) {
+	defer p.audit("CreateUserGroup", "name", group.Name)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("CreateUserGroup"); err != nil {
+		return nil, err
+	}
+
+	if group.UserIDs == nil {
+		group.UserIDs = []string{}
+	}
+	jsonBytes, err := json.Marshal(group)
+	if err != nil {
+		return nil, err
+	}
+
+	cJSON, freeJSON := cStringFree(string(jsonBytes))
+	defer freeJSON()
+
+	cstr := C.communicator_platform_create_user_group(p.handle, cJSON)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var created UserGroup
+	if err := p.decode([]byte(C.GoString(cstr)), &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// AddUserGroupMembers adds users to a custom user group
+func (p *Platform) AddUserGroupMembers(groupID string, userIDs []string) (err error) {
+	defer p.audit("AddUserGroupMembers", "group_id", groupID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("AddUserGroupMembers"); err != nil {
+		return err
+	}
+
+	return p.changeUserGroupMembers(groupID, userIDs, true)
+}
+
+// RemoveUserGroupMembers removes users from a custom user group
+func (p *Platform) RemoveUserGroupMembers(groupID string, userIDs []string) (err error) {
+	defer p.audit("RemoveUserGroupMembers", "group_id", groupID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("RemoveUserGroupMembers"); err != nil {
+		return err
+	}
+
+	return p.changeUserGroupMembers(groupID, userIDs, false)
+}
+
+func (p *Platform) changeUserGroupMembers(groupID string, userIDs []string, add bool) error {
+	if userIDs == nil {
+		userIDs = []string{}
+	}
+	jsonBytes, err := json.Marshal(userIDs)
+	if err != nil {
+		return err
+	}
+
+	cGroupID, freeGroupID := cStringFree(groupID)
+	defer freeGroupID()
+
+	cJSON, freeJSON := cStringFree(string(jsonBytes))
+	defer freeJSON()
+
+	var code C.CommunicatorErrorCode
+	if add {
+		code = C.communicator_platform_add_user_group_members(p.handle, cGroupID, cJSON)
+	} else {
+		code = C.communicator_platform_remove_user_group_members(p.handle, cGroupID, cJSON)
+	}
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
+// ListTeamUserGroups retrieves the user groups that can be @-mentioned in a team
+func (p *Platform) ListTeamUserGroups(teamID string, page, perPage uint32) ([]UserGroup, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cTeamID, freeTeamID := cStringFree(teamID)
+	defer freeTeamID()
+
+	cstr := C.communicator_platform_get_team_user_groups(p.handle, cTeamID, C.uint32_t(page), C.uint32_t(perPage))
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var groups []UserGroup
+	if err := p.decode([]byte(C.GoString(cstr)), &groups); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// AutocompleteUserGroups suggests user groups for a partially typed @-mention in a team
+func (p *Platform) AutocompleteUserGroups(teamID, prefix string) ([]UserGroup, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cTeamID, freeTeamID := cStringFree(teamID)
+	defer freeTeamID()
+
+	cPrefix, freePrefix := cStringFree(prefix)
+	defer freePrefix()
+
+	cstr := C.communicator_platform_autocomplete_user_groups(p.handle, cTeamID, cPrefix)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var groups []UserGroup
+	if err := p.decode([]byte(C.GoString(cstr)), &groups); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,197 @@
+package libcommunicator
+
+/*
+#ifdef _WIN32
+#include <windows.h>
+static uintptr_t lc_thread_id(void) { return (uintptr_t)GetCurrentThreadId(); }
+#else
+#include <pthread.h>
+#include <stdint.h>
+static uintptr_t lc_thread_id(void) { return (uintptr_t)pthread_self(); }
+#endif
+*/
+import "C"
+
+import (
+	"runtime"
+	"sync"
+)
+
+// handleGuard coordinates the calls made on a platform's native handle, and
+// likewise a context's
+//
+// Most calls share the handle and run concurrently. Calls the library
+// needs the handle to itself for (Connect, ConnectWithMFA, RestoreState,
+// Disconnect, SubscribeEvents, SubscribeEventsFiltered and UnsubscribeEvents)
+// and Close hold it exclusively: they wait for the shared calls in progress,
+// and new shared calls wait behind them, so a steady stream of requests
+// can't hold them off indefinitely.
+//
+// Holders are identified by OS thread, which acquire pins the goroutine to.
+// A shared hold taken by a thread that already holds the handle is granted
+// at once rather than queued behind a waiting exclusive call, which could
+// never get it. An exclusive hold requested from inside a call on the handle
+// can never be granted either, so it panics instead of deadlocking. The same
+// goes for one requested from a library callback: the library may run it on
+// a thread of its own while a call on another thread waits for it, so
+// callbacks are told apart by the threads they run on rather than by holds.
+type handleGuard struct {
+	mu        sync.Mutex
+	changed   sync.Cond
+	shared    int
+	exclusive bool
+	waiting   int // exclusive calls waiting for the handle
+
+	holders map[uintptr]int // holds by thread
+}
+
+func (g *handleGuard) lock(thread uintptr, exclusive bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.changed.L == nil {
+		g.changed.L = &g.mu
+		g.holders = make(map[uintptr]int)
+	}
+
+	nested := g.holders[thread] > 0
+	switch {
+	case exclusive && (nested || inCallback(thread)):
+		panic("libcommunicator: Connect, Disconnect, Close or an event subscription call made from inside another call on the same Platform, such as a hook or callback; it would deadlock")
+	case exclusive:
+		g.waiting++
+		for g.exclusive || g.shared > 0 {
+			g.changed.Wait()
+		}
+		g.waiting--
+		g.exclusive = true
+	case !nested:
+		for g.exclusive || g.waiting > 0 {
+			g.changed.Wait()
+		}
+		fallthrough
+	default:
+		g.shared++
+	}
+	g.holders[thread]++
+}
+
+func (g *handleGuard) unlock(thread uintptr, exclusive bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if exclusive {
+		g.exclusive = false
+	} else {
+		g.shared--
+	}
+	if g.holders[thread]--; g.holders[thread] == 0 {
+		delete(g.holders, thread)
+	}
+	g.changed.Broadcast()
+}
+
+// callbackThreads counts the library callbacks running on each OS thread
+var (
+	callbackThreadsMu sync.Mutex
+	callbackThreads   = make(map[uintptr]int)
+)
+
+// enterCallback records that a library callback is running on thread, which
+// the goroutine stays on for the callback's duration
+func enterCallback(thread uintptr) {
+	callbackThreadsMu.Lock()
+	defer callbackThreadsMu.Unlock()
+	callbackThreads[thread]++
+}
+
+// leaveCallback undoes enterCallback once the callback returns
+func leaveCallback(thread uintptr) {
+	callbackThreadsMu.Lock()
+	defer callbackThreadsMu.Unlock()
+	if callbackThreads[thread]--; callbackThreads[thread] == 0 {
+		delete(callbackThreads, thread)
+	}
+}
+
+func inCallback(thread uintptr) bool {
+	callbackThreadsMu.Lock()
+	defer callbackThreadsMu.Unlock()
+	return callbackThreads[thread] > 0
+}
+
+// acquire takes the handle for a call that may run alongside others
+// It returns false once the platform is closed. Until release, the
+// goroutine stays on its OS thread, where the library keeps the last error,
+// so getLastError reports this call's failure rather than another's.
+func (p *Platform) acquire() bool {
+	return p.acquireHandle(false)
+}
+
+// release gives back the handle taken by acquire
+func (p *Platform) release() {
+	p.releaseHandle(false)
+}
+
+// acquireExclusive takes the handle for a call that must run alone; see handleGuard
+func (p *Platform) acquireExclusive() bool {
+	return p.acquireHandle(true)
+}
+
+// releaseExclusive gives back the handle taken by acquireExclusive
+func (p *Platform) releaseExclusive() {
+	p.releaseHandle(true)
+}
+
+func (p *Platform) acquireHandle(exclusive bool) bool {
+	return p.guard.hold(exclusive, func() bool { return p.handle != nil })
+}
+
+func (p *Platform) releaseHandle(exclusive bool) {
+	p.guard.drop(exclusive)
+}
+
+// hold pins the goroutine to its OS thread and takes the guard, keeping both
+// only if open reports the handle is still there
+func (g *handleGuard) hold(exclusive bool, open func() bool) bool {
+	runtime.LockOSThread()
+	thread := currentThread()
+	g.lock(thread, exclusive)
+	if !open() {
+		g.unlock(thread, exclusive)
+		runtime.UnlockOSThread()
+		return false
+	}
+	return true
+}
+
+// drop gives back what hold took
+func (g *handleGuard) drop(exclusive bool) {
+	g.unlock(currentThread(), exclusive)
+	runtime.UnlockOSThread()
+}
+
+// currentThread identifies the OS thread the goroutine runs on; it is only
+// stable while the goroutine is locked to it
+func currentThread() uintptr {
+	return uintptr(C.lc_thread_id())
+}
+
+// destroyed reports whether the platform has been closed, for methods that make
+// their native calls through other methods and must not hold the handle
+// while they wait
+func (p *Platform) destroyed() bool {
+	if !p.acquire() {
+		return true
+	}
+	p.release()
+	return false
+}
+
+// pinThread keeps the goroutine on its OS thread until the returned function
+// is called, for native calls made without a platform handle: the library
+// keeps the last error per thread, so getLastError must run on the thread
+// of the failing call
+func pinThread() (unpin func()) {
+	runtime.LockOSThread()
+	return runtime.UnlockOSThread
+}
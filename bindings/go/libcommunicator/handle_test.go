@@ -0,0 +1,219 @@
+package libcommunicator
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds, failing the test after a second
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (g *handleGuard) waitingExclusive() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.waiting
+}
+
+func TestHandleGuardExclusion(t *testing.T) {
+	var g handleGuard
+	var shared, exclusive, violations atomic.Int32
+
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func(thread uintptr, writer bool) {
+			defer wg.Done()
+			for range 200 {
+				g.lock(thread, writer)
+				if writer {
+					if exclusive.Add(1) != 1 || shared.Load() != 0 {
+						violations.Add(1)
+					}
+					exclusive.Add(-1)
+				} else {
+					shared.Add(1)
+					if exclusive.Load() != 0 {
+						violations.Add(1)
+					}
+					shared.Add(-1)
+				}
+				g.unlock(thread, writer)
+			}
+		}(uintptr(i+1), i%4 == 0)
+	}
+	wg.Wait()
+
+	if n := violations.Load(); n != 0 {
+		t.Fatalf("%d calls ran alongside an exclusive one", n)
+	}
+}
+
+func TestHandleGuardWaitingExclusiveHoldsOffShared(t *testing.T) {
+	var g handleGuard
+	g.lock(1, false)
+
+	var order []string
+	var mu sync.Mutex
+	record := func(s string) {
+		mu.Lock()
+		order = append(order, s)
+		mu.Unlock()
+	}
+
+	exclusiveDone := make(chan struct{})
+	go func() {
+		g.lock(2, true)
+		record("exclusive")
+		g.unlock(2, true)
+		close(exclusiveDone)
+	}()
+	waitFor(t, "the exclusive call to queue", func() bool { return g.waitingExclusive() == 1 })
+
+	sharedDone := make(chan struct{})
+	go func() {
+		g.lock(3, false)
+		record("shared")
+		g.unlock(3, false)
+		close(sharedDone)
+	}()
+
+	select {
+	case <-sharedDone:
+		t.Fatal("a new shared call went ahead of the waiting exclusive one")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	g.unlock(1, false)
+	<-exclusiveDone
+	<-sharedDone
+	if order[0] != "exclusive" || order[1] != "shared" {
+		t.Fatalf("order = %v, want [exclusive shared]", order)
+	}
+}
+
+func TestHandleGuardExclusiveNotStarved(t *testing.T) {
+	var g handleGuard
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func(thread uintptr) {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				g.lock(thread, false)
+				time.Sleep(100 * time.Microsecond)
+				g.unlock(thread, false)
+			}
+		}(uintptr(i + 1))
+	}
+	defer func() {
+		close(stop)
+		wg.Wait()
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	acquired := make(chan struct{})
+	go func() {
+		g.lock(100, true)
+		g.unlock(100, true)
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+	case <-time.After(2 * time.Second):
+		t.Fatal("exclusive call starved by a steady stream of shared calls")
+	}
+}
+
+func TestHandleGuardNestedShared(t *testing.T) {
+	var g handleGuard
+	g.lock(1, false)
+
+	go g.lock(2, true)
+	waitFor(t, "the exclusive call to queue", func() bool { return g.waitingExclusive() == 1 })
+
+	// The holder's own nested call must not queue behind the exclusive one,
+	// which waits for the holder
+	nested := make(chan struct{})
+	go func() {
+		g.lock(1, false)
+		g.unlock(1, false)
+		close(nested)
+	}()
+	select {
+	case <-nested:
+	case <-time.After(time.Second):
+		t.Fatal("nested shared hold deadlocked behind a waiting exclusive call")
+	}
+	g.unlock(1, false)
+	waitFor(t, "the exclusive call to run", func() bool { return g.waitingExclusive() == 0 })
+}
+
+func TestHandleGuardNestedExclusivePanics(t *testing.T) {
+	var g handleGuard
+	g.lock(1, false)
+	defer g.unlock(1, false)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("an exclusive hold from inside a held call didn't panic")
+		}
+	}()
+	g.lock(1, true)
+}
+
+func TestHandleGuardExclusiveFromCallbackPanics(t *testing.T) {
+	var g handleGuard
+
+	// A call on one thread waits for a callback the library runs on another
+	g.lock(1, false)
+	defer g.unlock(1, false)
+	enterCallback(2)
+
+	// Shared holds from the callback are still granted
+	g.lock(2, false)
+	g.unlock(2, false)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("an exclusive hold from inside a library callback didn't panic")
+			}
+		}()
+		g.lock(2, true)
+	}()
+	if g.waitingExclusive() != 0 {
+		t.Fatal("the refused exclusive hold was left waiting")
+	}
+
+	// Once the callback returns the thread may wait for the handle again
+	leaveCallback(2)
+	go g.lock(2, true)
+	waitFor(t, "the exclusive call to queue", func() bool { return g.waitingExclusive() == 1 })
+}
+
+func TestAcquireClosedPlatform(t *testing.T) {
+	var p Platform
+	if p.acquire() {
+		t.Fatal("acquire succeeded on a platform without a handle")
+	}
+	if p.guard.shared != 0 || len(p.guard.holders) != 0 {
+		t.Fatalf("failed acquire left a hold: shared=%d holders=%v", p.guard.shared, p.guard.holders)
+	}
+}
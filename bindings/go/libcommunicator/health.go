@@ -0,0 +1,114 @@
+package libcommunicator
+
+/*
+#include <communicator.h>
+*/
+import "C"
+import (
+	"context"
+	"time"
+)
+
+// ConnectionHealth describes the quality of the real-time connection
+// A connection can look open long after the network dropped it (half-open);
+// unanswered pings and a stale LastPongAt reveal it before the socket fails.
+type ConnectionHealth struct {
+	State ConnectionState `json:"state"`
+	// RTTMillis is the round-trip time of the last answered ping; 0 until one is answered
+	RTTMillis   int64      `json:"rtt_ms,omitempty"`
+	LastPongAt  *time.Time `json:"last_pong_at,omitempty"`
+	LastEventAt *time.Time `json:"last_event_at,omitempty"`
+	// UnansweredPings counts pings sent on the current connection that got no pong yet
+	UnansweredPings int   `json:"unanswered_pings"`
+	PingsSent       int64 `json:"pings_sent"` // including keep-alive pings
+	PongsReceived   int64 `json:"pongs_received"`
+}
+
+// RTT returns the round-trip time of the last answered ping, or 0 if none was answered
+func (h *ConnectionHealth) RTT() time.Duration {
+	return time.Duration(h.RTTMillis) * time.Millisecond
+}
+
+// SinceLastEvent returns how long ago the last event arrived, or 0 if none has
+func (h *ConnectionHealth) SinceLastEvent() time.Duration {
+	if h.LastEventAt == nil {
+		return 0
+	}
+	return time.Since(*h.LastEventAt)
+}
+
+// GetConnectionHealth returns ping round trips and the time of the last event
+// Requires an active WebSocket connection (call SubscribeEvents first).
+func (p *Platform) GetConnectionHealth() (*ConnectionHealth, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cstr := C.communicator_platform_get_connection_health(p.handle)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var health ConnectionHealth
+	if err := p.decode([]byte(C.GoString(cstr)), &health); err != nil {
+		return nil, err
+	}
+
+	return &health, nil
+}
+
+// defaultPingTimeout bounds Ping when ctx has no deadline
+const defaultPingTimeout = 10 * time.Second
+
+// Ping pings the server over the real-time connection and returns the round-trip time
+// It waits until ctx's deadline, or 10 seconds without one. A timeout on a
+// connection that still reports itself connected means it is half-open.
+// Requires an active WebSocket connection (call SubscribeEvents first).
+func (p *Platform) Ping(ctx context.Context) (time.Duration, error) {
+	if p.destroyed() {
+		return 0, ErrClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	timeout := defaultPingTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+		if timeout < time.Millisecond {
+			return 0, context.DeadlineExceeded
+		}
+	}
+
+	type result struct {
+		rtt time.Duration
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		// Holds the handle until the native call returns, even if ctx is
+		// done first, so Close waits for it
+		if !p.acquire() {
+			done <- result{err: ErrClosed}
+			return
+		}
+		defer p.release()
+
+		rtt := C.communicator_platform_ping(p.handle, C.uint64_t(timeout.Milliseconds()))
+		if rtt == -1 {
+			done <- result{err: getLastError()}
+			return
+		}
+		done <- result{rtt: time.Duration(rtt) * time.Millisecond}
+	}()
+
+	// The native call gives up on its own once the timeout elapses
+	select {
+	case r := <-done:
+		return r.rtt, r.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
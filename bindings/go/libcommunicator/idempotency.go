@@ -0,0 +1,271 @@
+package libcommunicator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IdempotencyStore records which units of work have already been claimed
+// Replicas that must not repeat each other's work need to share one store.
+type IdempotencyStore interface {
+	// Claim marks key as taken for ttl. It returns false if the key is already
+	// held and has not expired. Claims must be atomic across all store users.
+	Claim(key string, ttl time.Duration) (bool, error)
+	// Release frees a key so the work can be attempted again
+	Release(key string) error
+}
+
+// MemoryIdempotencyStore keeps claims in memory; it only deduplicates within one process
+type MemoryIdempotencyStore struct {
+	mu     sync.Mutex
+	claims map[string]time.Time
+}
+
+// NewMemoryIdempotencyStore creates an empty in-memory store
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{claims: make(map[string]time.Time)}
+}
+
+// Claim marks key as taken for ttl
+func (s *MemoryIdempotencyStore) Claim(key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if expires, ok := s.claims[key]; ok && now.Before(expires) {
+		return false, nil
+	}
+
+	// Drop expired claims so the map doesn't grow without bound
+	for k, expires := range s.claims {
+		if !now.Before(expires) {
+			delete(s.claims, k)
+		}
+	}
+
+	s.claims[key] = now.Add(ttl)
+	return true, nil
+}
+
+// Release frees a key
+func (s *MemoryIdempotencyStore) Release(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.claims, key)
+	return nil
+}
+
+// FileIdempotencyStore keeps claims as files in a directory
+// Claims are created atomically, so replicas sharing the directory (e.g. on
+// a common volume) never both claim the same key. An expired claim isn't
+// removed but superseded by a claim file of the next generation, which only
+// one replica can create; a claim file stays until the directory is cleaned.
+type FileIdempotencyStore struct {
+	dir string
+
+	mu    sync.Mutex
+	owned map[string]int // generations of the claims made through this store, by key
+}
+
+// NewFileIdempotencyStore creates a store in dir, creating the directory if needed
+func NewFileIdempotencyStore(dir string) (*FileIdempotencyStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileIdempotencyStore{dir: dir, owned: make(map[string]int)}, nil
+}
+
+// path returns the claim file of the given generation for key
+func (s *FileIdempotencyStore) path(key string, generation int) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	if generation > 0 {
+		name += "." + strconv.Itoa(generation)
+	}
+	return filepath.Join(s.dir, name)
+}
+
+// Claim marks key as taken for ttl
+func (s *FileIdempotencyStore) Claim(key string, ttl time.Duration) (bool, error) {
+	expires := strconv.FormatInt(time.Now().Add(ttl).UnixNano(), 10)
+
+	// Write the claim aside and link it into place: linking fails if the
+	// name exists, and readers never see a partly written claim
+	tmp, err := s.writeTemp(expires)
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp)
+
+	for generation := 0; ; generation++ {
+		path := s.path(key, generation)
+		err := os.Link(tmp, path)
+		if err == nil {
+			s.mu.Lock()
+			s.owned[key] = generation
+			s.mu.Unlock()
+			return true, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return false, err
+		}
+
+		// The generation is taken; move on to the next only if its claim has expired
+		live, err := claimLive(path)
+		if errors.Is(err, os.ErrNotExist) {
+			// Removed by someone cleaning the directory; try it again
+			generation--
+			continue
+		}
+		if err != nil || live {
+			return false, err
+		}
+	}
+}
+
+// Release frees a key
+// It frees the latest claim on key, or with a claim made through this
+// store, that claim unless it has expired and been taken over since.
+func (s *FileIdempotencyStore) Release(key string) error {
+	s.mu.Lock()
+	generation, ok := s.owned[key]
+	delete(s.owned, key)
+	s.mu.Unlock()
+
+	if !ok {
+		if _, err := os.Stat(s.path(key, 0)); errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		for {
+			if _, err := os.Stat(s.path(key, generation+1)); err != nil {
+				break
+			}
+			generation++
+		}
+	} else if _, err := os.Stat(s.path(key, generation+1)); err == nil {
+		// Taken over; the key belongs to the later claim now
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	// Expire the claim rather than removing it, which would let a new
+	// claim take an earlier generation than the current one
+	tmp, err := s.writeTemp("0")
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path(key, generation)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func (s *FileIdempotencyStore) writeTemp(content string) (string, error) {
+	f, err := os.CreateTemp(s.dir, ".claim-")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.WriteString(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// claimLive reports whether the claim in path is still held
+func claimLive(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	until, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		// Claims written by earlier versions were created empty and filled
+		// in; one left unreadable by a crashed writer expires after a
+		// grace period
+		info, statErr := os.Stat(path)
+		return statErr != nil || time.Since(info.ModTime()) < time.Minute, nil
+	}
+	return time.Now().UnixNano() < until, nil
+}
+
+// Idempotency runs each handler at most once per message
+// Work is keyed on message ID and handler name, so several handlers can still
+// act on the same message and several replicas can share a store safely.
+type Idempotency struct {
+	store IdempotencyStore
+
+	// TTL is how long a completed message is remembered (default 24h)
+	TTL time.Duration
+}
+
+// NewIdempotency creates a helper backed by store; nil uses an in-memory store
+func NewIdempotency(store IdempotencyStore) *Idempotency {
+	if store == nil {
+		store = NewMemoryIdempotencyStore()
+	}
+	return &Idempotency{store: store, TTL: 24 * time.Hour}
+}
+
+// Do runs fn unless handlerName already ran (or is running) for messageID
+// If fn fails the claim is released so the work can be retried. ran reports
+// whether fn was called.
+func (i *Idempotency) Do(messageID, handlerName string, fn func() error) (ran bool, err error) {
+	key := handlerName + ":" + messageID
+
+	claimed, err := i.store.Claim(key, i.TTL)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	if err := fn(); err != nil {
+		if releaseErr := i.store.Release(key); releaseErr != nil {
+			return true, errors.Join(err, releaseErr)
+		}
+		return true, err
+	}
+	return true, nil
+}
+
+// Handler wraps an error-returning handler so it runs once per message
+// Events that carry no message ID are passed through unchanged. Combine
+// with WithRetry to retry failures without risking double execution.
+func (i *Idempotency) Handler(name string, handler EventErrorHandler) EventErrorHandler {
+	return func(event *Event) error {
+		messageID := eventMessageID(event)
+		if messageID == "" {
+			return handler(event)
+		}
+		_, err := i.Do(messageID, name, func() error { return handler(event) })
+		return err
+	}
+}
+
+// eventMessageID returns the ID of the message an event refers to
+func eventMessageID(event *Event) string {
+	if event.MessageID != "" {
+		return event.MessageID
+	}
+	if event.Type != EventMessagePosted {
+		return ""
+	}
+	if data, ok := event.Data.(map[string]interface{}); ok {
+		if id, ok := data["id"].(string); ok {
+			return id
+		}
+	}
+	return ""
+}
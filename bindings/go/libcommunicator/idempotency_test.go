@@ -0,0 +1,161 @@
+package libcommunicator
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileIdempotencyStoreClaim(t *testing.T) {
+	store, err := NewFileIdempotencyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := store.Claim("k", time.Hour); err != nil || !ok {
+		t.Fatalf("first Claim = %v, %v; want true", ok, err)
+	}
+	if ok, err := store.Claim("k", time.Hour); err != nil || ok {
+		t.Fatalf("second Claim = %v, %v; want false", ok, err)
+	}
+	if ok, err := store.Claim("other", time.Hour); err != nil || !ok {
+		t.Fatalf("Claim of another key = %v, %v; want true", ok, err)
+	}
+
+	if err := store.Release("k"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := store.Claim("k", time.Hour); err != nil || !ok {
+		t.Fatalf("Claim after Release = %v, %v; want true", ok, err)
+	}
+}
+
+func TestFileIdempotencyStoreTakeover(t *testing.T) {
+	dir := t.TempDir()
+	a, _ := NewFileIdempotencyStore(dir)
+	b, _ := NewFileIdempotencyStore(dir)
+
+	if ok, _ := a.Claim("k", time.Millisecond); !ok {
+		t.Fatal("Claim failed")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if ok, err := b.Claim("k", time.Hour); err != nil || !ok {
+		t.Fatalf("taking over an expired claim = %v, %v; want true", ok, err)
+	}
+
+	// a's claim expired and was taken over; releasing it must not free b's
+	if err := a.Release("k"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := a.Claim("k", time.Hour); ok {
+		t.Fatal("a stale owner's Release freed the claim that took over")
+	}
+
+	// Another store releasing the key frees the latest claim
+	if err := a.Release("k"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := a.Claim("k", time.Hour); !ok {
+		t.Fatal("Release through another store didn't free the key")
+	}
+}
+
+func TestFileIdempotencyStoreReadsLegacyClaims(t *testing.T) {
+	store, _ := NewFileIdempotencyStore(t.TempDir())
+
+	// Earlier versions kept one file per key holding the expiry
+	future := strconv.FormatInt(time.Now().Add(time.Hour).UnixNano(), 10)
+	if err := os.WriteFile(store.path("k", 0), []byte(future), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := store.Claim("k", time.Hour); ok {
+		t.Fatal("claimed a key held by a legacy claim")
+	}
+
+	// and created it empty before writing it
+	if err := os.WriteFile(store.path("fresh", 0), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := store.Claim("fresh", time.Hour); ok {
+		t.Fatal("claimed a key whose legacy claim was being written")
+	}
+}
+
+// Replicas racing to take over the same expired claim, each through its own
+// store as separate processes would, must let exactly one of them win
+func TestFileIdempotencyStoreConcurrentTakeover(t *testing.T) {
+	dir := t.TempDir()
+	const replicas = 8
+
+	for round := range 50 {
+		key := "msg:" + strconv.Itoa(round)
+		first, _ := NewFileIdempotencyStore(dir)
+		if ok, err := first.Claim(key, time.Millisecond); err != nil || !ok {
+			t.Fatalf("round %d: initial Claim = %v, %v", round, ok, err)
+		}
+		time.Sleep(2 * time.Millisecond)
+
+		var winners atomic.Int32
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		errs := make(chan error, replicas)
+		for range replicas {
+			store, _ := NewFileIdempotencyStore(dir)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				ok, err := store.Claim(key, time.Hour)
+				if err != nil {
+					errs <- err
+				}
+				if ok {
+					winners.Add(1)
+				}
+			}()
+		}
+		close(start)
+		wg.Wait()
+		close(errs)
+
+		if err := errors.Join(collect(errs)...); err != nil {
+			t.Fatalf("round %d: %v", round, err)
+		}
+		if n := winners.Load(); n != 1 {
+			t.Fatalf("round %d: %d replicas took over the expired claim, want 1", round, n)
+		}
+	}
+}
+
+func collect(errs <-chan error) []error {
+	var all []error
+	for err := range errs {
+		all = append(all, err)
+	}
+	return all
+}
+
+func TestIdempotencyDoReleasesOnFailure(t *testing.T) {
+	idem := NewIdempotency(nil)
+	fail := errors.New("boom")
+
+	ran, err := idem.Do("m1", "h", func() error { return fail })
+	if !ran || !errors.Is(err, fail) {
+		t.Fatalf("Do = %v, %v; want true, boom", ran, err)
+	}
+	ran, err = idem.Do("m1", "h", func() error { return nil })
+	if !ran || err != nil {
+		t.Fatalf("Do after a failure = %v, %v; want a retry", ran, err)
+	}
+	ran, _ = idem.Do("m1", "h", func() error { return nil })
+	if ran {
+		t.Fatal("Do ran a completed handler again")
+	}
+	if ran, _ = idem.Do("m1", "other", func() error { return nil }); !ran {
+		t.Fatal("Do skipped a different handler for the same message")
+	}
+}
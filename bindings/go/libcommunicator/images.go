@@ -0,0 +1,438 @@
+package libcommunicator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	_ "image/gif" // register decoder for thumbnail generation
+	"image/jpeg"
+	_ "image/png" // register decoder for thumbnail generation
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultThumbnailSize is the longest edge, in pixels, of generated thumbnails
+const DefaultThumbnailSize = 400
+
+// maxThumbnailPixels caps the images MakeThumbnail decodes, checked from the
+// header before decoding: a small file can declare dimensions that would take
+// gigabytes to decode. 64 megapixels covers camera images at about 256 MiB.
+const maxThumbnailPixels = 64 << 20
+
+// UploadOptions controls client-side processing applied before a file is uploaded
+type UploadOptions struct {
+	// StripMetadata removes EXIF, GPS, XMP and text metadata from JPEG and PNG images
+	StripMetadata bool
+	// GenerateThumbnail produces a JPEG thumbnail of image files and uploads
+	// it alongside the file
+	GenerateThumbnail bool
+	// ThumbnailSize is the longest edge of the thumbnail; DefaultThumbnailSize when zero
+	ThumbnailSize int
+}
+
+// UploadResult is the outcome of UploadFileWithOptions
+type UploadResult struct {
+	FileID string
+	// Thumbnail is a JPEG thumbnail, set when requested and the file is a decodable image
+	Thumbnail []byte
+	// ThumbnailFileID is the uploaded thumbnail, set along with Thumbnail
+	ThumbnailFileID string
+}
+
+// FileIDs returns the uploaded files, thumbnail included, for attaching them
+// to a message with SendMessageOpts.FileIDs
+func (r *UploadResult) FileIDs() []string {
+	ids := []string{r.FileID}
+	if r.ThumbnailFileID != "" {
+		ids = append(ids, r.ThumbnailFileID)
+	}
+	return ids
+}
+
+var (
+	// ErrCorruptImage is returned when image metadata cannot be stripped safely
+	ErrCorruptImage = errors.New("image is truncated or malformed")
+	// ErrImageTooLarge is returned by MakeThumbnail for images too large to decode safely
+	ErrImageTooLarge = errors.New("image dimensions are too large for a thumbnail")
+)
+
+// UploadFileWithOptions uploads a file to a channel after applying client-side processing
+// The original file on disk is never modified. A thumbnail is uploaded as a
+// second file named after the original, e.g. photo_thumb.jpg; attach both
+// with SendMessageOpts{FileIDs: result.FileIDs()}.
+func (p *Platform) UploadFileWithOptions(channelID, filePath string, opts UploadOptions) (*UploadResult, error) {
+	if !opts.StripMetadata && !opts.GenerateThumbnail {
+		fileID, err := p.UploadFile(channelID, filePath)
+		if err != nil {
+			return nil, err
+		}
+		return &UploadResult{FileID: fileID}, nil
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Processed files are written under their own names, since the server
+	// uses the name for the attachment
+	var dir string
+	defer func() {
+		if dir != "" {
+			os.RemoveAll(dir)
+		}
+	}()
+	writeTemp := func(name string, data []byte) (string, error) {
+		if dir == "" {
+			var err error
+			if dir, err = os.MkdirTemp("", "libcommunicator-upload-"); err != nil {
+				return "", err
+			}
+		}
+		path := filepath.Join(dir, name)
+		return path, os.WriteFile(path, data, 0o600)
+	}
+
+	result := &UploadResult{}
+	if opts.GenerateThumbnail {
+		// Not every upload is an image; a failed decode just means no thumbnail
+		result.Thumbnail, _ = MakeThumbnail(data, opts.ThumbnailSize)
+	}
+
+	uploadPath := filePath
+	if opts.StripMetadata {
+		stripped, err := StripImageMetadata(data)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(stripped, data) {
+			if uploadPath, err = writeTemp(filepath.Base(filePath), stripped); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result.FileID, err = p.UploadFile(channelID, uploadPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Thumbnail != nil {
+		thumbPath, err := writeTemp(thumbnailName(filePath), result.Thumbnail)
+		if err != nil {
+			return nil, err
+		}
+		if result.ThumbnailFileID, err = p.UploadFile(channelID, thumbPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// thumbnailName names the thumbnail of a file, e.g. photo_thumb.jpg for photo.png
+func thumbnailName(filePath string) string {
+	base := filepath.Base(filePath)
+	return strings.TrimSuffix(base, filepath.Ext(base)) + "_thumb.jpg"
+}
+
+// StripImageMetadata removes privacy-sensitive metadata from JPEG and PNG data
+// Pixel data is copied untouched; other formats are returned unchanged
+func StripImageMetadata(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8}):
+		return stripJPEGMetadata(data)
+	case bytes.HasPrefix(data, pngSignature):
+		return stripPNGMetadata(data)
+	default:
+		return data, nil
+	}
+}
+
+// stripJPEGMetadata drops APP1 (EXIF/XMP) and APP13 (IPTC) segments
+// The EXIF Orientation is kept in a minimal EXIF segment of its own, since
+// viewers need it to display the image the right way up.
+func stripJPEGMetadata(data []byte) ([]byte, error) {
+	out, _, err := scanJPEG(data)
+	return out, err
+}
+
+// scanJPEG strips the metadata of JPEG data as stripJPEGMetadata does and
+// returns its EXIF orientation, 1 when it has none
+func scanJPEG(data []byte) (_ []byte, orientation int, err error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	orientation = 1
+
+	pos := 2
+	for pos < len(data) {
+		if data[pos] != 0xFF {
+			return nil, 0, ErrCorruptImage
+		}
+		// Markers may be preceded by any number of 0xFF fill bytes
+		for pos < len(data) && data[pos] == 0xFF {
+			pos++
+		}
+		if pos >= len(data) {
+			return nil, 0, ErrCorruptImage
+		}
+		marker := data[pos]
+		pos++
+
+		// Standalone markers carry no length
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out = append(out, 0xFF, marker)
+			continue
+		}
+		if marker == 0xD9 {
+			out = append(out, 0xFF, marker)
+			return out, orientation, nil
+		}
+
+		if pos+2 > len(data) {
+			return nil, 0, ErrCorruptImage
+		}
+		end := pos + int(binary.BigEndian.Uint16(data[pos:]))
+		if end > len(data) || end < pos+2 {
+			return nil, 0, ErrCorruptImage
+		}
+
+		// Start of scan: the entropy-coded image data follows, copy the rest as-is
+		if marker == 0xDA {
+			out = append(out, 0xFF, marker)
+			return append(out, data[pos:]...), orientation, nil
+		}
+
+		switch marker {
+		case 0xE1: // EXIF or XMP
+			if o := exifOrientation(data[pos+2 : end]); o > 1 && orientation == 1 {
+				orientation = o
+				out = append(out, orientationSegment(o)...)
+			}
+		case 0xED: // IPTC
+		default:
+			out = append(out, 0xFF, marker)
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+
+	return out, orientation, nil
+}
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// exifOrientation returns the Orientation tag of an APP1 segment's payload,
+// or 0 if it isn't EXIF or has no valid orientation
+func exifOrientation(payload []byte) int {
+	tiff, ok := bytes.CutPrefix(payload, exifHeader)
+	if !ok || len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	// The orientation is in the first IFD, as a single SHORT
+	ifd := int64(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > int64(len(tiff)) {
+		return 0
+	}
+	entries := int64(order.Uint16(tiff[ifd:]))
+	for i := range entries {
+		entry := ifd + 2 + 12*i
+		if entry+12 > int64(len(tiff)) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) != 0x0112 {
+			continue
+		}
+		if order.Uint16(tiff[entry+2:]) != 3 {
+			return 0
+		}
+		if o := int(order.Uint16(tiff[entry+8:])); o >= 1 && o <= 8 {
+			return o
+		}
+		return 0
+	}
+	return 0
+}
+
+// orientationSegment builds an APP1 segment whose EXIF data holds nothing
+// but the orientation
+func orientationSegment(orientation int) []byte {
+	seg := []byte{0xFF, 0xE1, 0, 34}
+	seg = append(seg, exifHeader...)
+	return append(seg,
+		'M', 'M', 0, 42, 0, 0, 0, 8, // big-endian TIFF header, first IFD at 8
+		0, 1, // one entry
+		0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, byte(orientation), 0, 0, // Orientation, SHORT, 1 value
+		0, 0, 0, 0, // no next IFD
+	)
+}
+
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}
+
+// pngMetadataChunks are the ancillary chunks that can carry EXIF or free-form text
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+// stripPNGMetadata drops metadata chunks, leaving image chunks and their CRCs intact
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+
+	pos := len(pngSignature)
+	for pos < len(data) {
+		if pos+8 > len(data) {
+			return nil, ErrCorruptImage
+		}
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		chunkType := string(data[pos+4 : pos+8])
+		end := pos + 12 + length // length + type + data + CRC
+		if length < 0 || end > len(data) {
+			return nil, ErrCorruptImage
+		}
+
+		if !pngMetadataChunks[chunkType] {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+
+		if chunkType == "IEND" {
+			break
+		}
+	}
+
+	return out, nil
+}
+
+// MakeThumbnail decodes a JPEG, PNG or GIF image and returns a JPEG thumbnail
+// whose longest edge is at most maxSize pixels (DefaultThumbnailSize when zero)
+// A JPEG's EXIF orientation is applied to the thumbnail, which carries no
+// metadata. Images over 64 megapixels are rejected with ErrImageTooLarge
+// before being decoded.
+func MakeThumbnail(data []byte, maxSize int) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultThumbnailSize
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, ErrCorruptImage
+	}
+	if int64(config.Width)*int64(config.Height) > maxThumbnailPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return nil, ErrCorruptImage
+	}
+
+	tw, th := w, h
+	if w > maxSize || h > maxSize {
+		if w >= h {
+			tw, th = maxSize, max(1, h*maxSize/w)
+		} else {
+			tw, th = max(1, w*maxSize/h), maxSize
+		}
+	}
+
+	// Box filter: average every source pixel that maps onto a thumbnail pixel
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for ty := 0; ty < th; ty++ {
+		y0 := bounds.Min.Y + ty*h/th
+		y1 := max(y0+1, bounds.Min.Y+(ty+1)*h/th)
+		for tx := 0; tx < tw; tx++ {
+			x0 := bounds.Min.X + tx*w/tw
+			x1 := max(x0+1, bounds.Min.X+(tx+1)*w/tw)
+
+			var r, g, b, a, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					pr, pg, pb, pa := src.At(x, y).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+
+			i := dst.PixOffset(tx, ty)
+			dst.Pix[i+0] = uint8(r / n >> 8)
+			dst.Pix[i+1] = uint8(g / n >> 8)
+			dst.Pix[i+2] = uint8(b / n >> 8)
+			dst.Pix[i+3] = uint8(a / n >> 8)
+		}
+	}
+
+	orientation := 1
+	if bytes.HasPrefix(data, []byte{0xFF, 0xD8}) {
+		_, orientation, _ = scanJPEG(data)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, orient(dst, orientation), &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// orient turns an image stored with the given EXIF orientation the right way up
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	ow, oh := w, h
+	if orientation >= 5 { // 5 to 8 swap the axes
+		ow, oh = h, w
+	}
+	out := image.NewRGBA(image.Rect(0, 0, ow, oh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored
+				dx, dy = w-1-x, y
+			case 3: // upside down
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirrored upside down
+				dx, dy = x, h-1-y
+			case 5: // transposed
+				dx, dy = y, x
+			case 6: // needs turning clockwise
+				dx, dy = h-1-y, x
+			case 7: // transversed
+				dx, dy = h-1-y, w-1-x
+			case 8: // needs turning counterclockwise
+				dx, dy = y, w-1-x
+			}
+			copy(out.Pix[out.PixOffset(dx, dy):][:4], img.Pix[img.PixOffset(x, y):][:4])
+		}
+	}
+	return out
+}
@@ -0,0 +1,208 @@
+package libcommunicator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// testImage is w×h with a red left half, so orientation changes are visible
+func testImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			c := color.RGBA{0, 0, 255, 255}
+			if x < w/2 {
+				c = color.RGBA{255, 0, 0, 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// exifSegment builds an APP1 EXIF segment with an orientation and a
+// description, in little-endian order as most cameras write it
+func exifSegment(orientation uint16, description string) []byte {
+	le := binary.LittleEndian
+	tiff := []byte{'I', 'I', 42, 0, 8, 0, 0, 0}
+	tiff = le.AppendUint16(tiff, 2)
+	tiff = le.AppendUint16(tiff, 0x0112)
+	tiff = le.AppendUint16(tiff, 3)
+	tiff = le.AppendUint32(tiff, 1)
+	tiff = le.AppendUint16(tiff, orientation)
+	tiff = le.AppendUint16(tiff, 0)
+	tiff = le.AppendUint16(tiff, 0x010E)
+	tiff = le.AppendUint16(tiff, 2)
+	tiff = le.AppendUint32(tiff, uint32(len(description)+1))
+	tiff = le.AppendUint32(tiff, 8+2+2*12+4)
+	tiff = le.AppendUint32(tiff, 0)
+	tiff = append(append(tiff, description...), 0)
+
+	return appSegment(0xE1, append(bytes.Clone(exifHeader), tiff...))
+}
+
+// appSegment builds a JPEG segment with its length
+func appSegment(marker byte, payload []byte) []byte {
+	seg := []byte{0xFF, marker}
+	seg = binary.BigEndian.AppendUint16(seg, uint16(len(payload)+2))
+	return append(seg, payload...)
+}
+
+// jpegWith encodes img and inserts segments right after the start of image
+func jpegWith(t *testing.T, img image.Image, segments ...[]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	out := append([]byte{}, data[:2]...)
+	for _, seg := range segments {
+		out = append(out, seg...)
+	}
+	return append(out, data[2:]...)
+}
+
+func TestStripJPEGMetadata(t *testing.T) {
+	xmp := appSegment(0xE1, []byte("http://ns.adobe.com/xap/1.0/\x00GPSLAT"))
+	iptc := appSegment(0xED, []byte("Photoshop 3.0\x00"))
+	data := jpegWith(t, testImage(8, 4), exifSegment(6, "GPS 51.5N"), xmp, iptc)
+
+	stripped, err := StripImageMetadata(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"GPS 51.5N", "GPSLAT", "Photoshop"} {
+		if bytes.Contains(stripped, []byte(secret)) {
+			t.Errorf("stripped JPEG still contains %q", secret)
+		}
+	}
+	if _, orientation, err := scanJPEG(stripped); err != nil || orientation != 6 {
+		t.Fatalf("orientation after stripping = %d, %v; want 6", orientation, err)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(stripped)); err != nil {
+		t.Fatalf("stripped JPEG doesn't decode: %v", err)
+	}
+
+	// Without an orientation to keep, no EXIF is left at all
+	plain := jpegWith(t, testImage(8, 4), exifSegment(1, "secret"))
+	stripped, err = StripImageMetadata(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(stripped, exifHeader) {
+		t.Fatal("stripped JPEG kept an EXIF segment with nothing to keep")
+	}
+
+	if _, err := StripImageMetadata(data[:30]); !errors.Is(err, ErrCorruptImage) {
+		t.Fatalf("truncated JPEG = %v, want ErrCorruptImage", err)
+	}
+}
+
+// pngChunk encodes a chunk with its CRC
+func pngChunk(chunkType string, data []byte) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	chunk = append(chunk, chunkType...)
+	chunk = append(chunk, data...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+func TestStripPNGMetadata(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(4, 4)); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+	iend := len(encoded) - 12
+
+	// Metadata chunks go between the image data and the end
+	var data []byte
+	data = append(data, encoded[:iend]...)
+	data = append(data, pngChunk("tEXt", []byte("Author\x00Jane Doe"))...)
+	data = append(data, pngChunk("eXIf", []byte("MM\x00*GPS"))...)
+	data = append(data, pngChunk("tIME", []byte{7, 233, 1, 1, 0, 0, 0})...)
+	data = append(data, encoded[iend:]...)
+
+	stripped, err := StripImageMetadata(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(stripped, encoded) {
+		t.Fatal("stripping didn't leave exactly the image chunks")
+	}
+	if _, err := png.Decode(bytes.NewReader(stripped)); err != nil {
+		t.Fatalf("stripped PNG doesn't decode: %v", err)
+	}
+
+	if _, err := StripImageMetadata(data[:len(data)-20]); !errors.Is(err, ErrCorruptImage) {
+		t.Fatalf("truncated PNG = %v, want ErrCorruptImage", err)
+	}
+}
+
+func TestMakeThumbnail(t *testing.T) {
+	var buf bytes.Buffer
+	png.Encode(&buf, testImage(800, 200))
+	thumb, err := MakeThumbnail(buf.Bytes(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := jpeg.DecodeConfig(bytes.NewReader(thumb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Width != 100 || config.Height != 25 {
+		t.Fatalf("thumbnail is %dx%d, want 100x25", config.Width, config.Height)
+	}
+}
+
+func TestMakeThumbnailAppliesOrientation(t *testing.T) {
+	// Stored sideways: turned clockwise, the red left half ends up on top
+	data := jpegWith(t, testImage(80, 40), exifSegment(6, ""))
+	thumb, err := MakeThumbnail(data, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(thumb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 40 || b.Dy() != 80 {
+		t.Fatalf("thumbnail is %dx%d, want 40x80", b.Dx(), b.Dy())
+	}
+	r, _, bl, _ := img.At(20, 10).RGBA()
+	if r < bl {
+		t.Fatal("the top of the thumbnail isn't the red half")
+	}
+}
+
+func TestMakeThumbnailRejectsHugeDimensions(t *testing.T) {
+	// A header declaring 100000×100000 pixels, with no image data behind it
+	ihdr := binary.BigEndian.AppendUint32(nil, 100000)
+	ihdr = binary.BigEndian.AppendUint32(ihdr, 100000)
+	ihdr = append(ihdr, 8, 6, 0, 0, 0)
+	data := append(bytes.Clone(pngSignature), pngChunk("IHDR", ihdr)...)
+
+	if _, err := MakeThumbnail(data, 0); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("MakeThumbnail = %v, want ErrImageTooLarge", err)
+	}
+}
+
+func TestThumbnailName(t *testing.T) {
+	tests := map[string]string{
+		"/tmp/photo.png":  "photo_thumb.jpg",
+		"scan.final.jpeg": "scan.final_thumb.jpg",
+		"noext":           "noext_thumb.jpg",
+	}
+	for in, want := range tests {
+		if got := thumbnailName(in); got != want {
+			t.Errorf("thumbnailName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package libcommunicator
+
+/*
+#include <communicator.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Action types
+const (
+	ActionTypeButton = "button"
+	ActionTypeSelect = "select"
+)
+
+// SelectOption is an option of a select menu or dialog element
+type SelectOption struct {
+	Text  string `json:"text"`
+	Value string `json:"value"`
+}
+
+// ActionIntegration says where the callback of an action is posted
+type ActionIntegration struct {
+	URL     string                 `json:"url"`
+	Context map[string]interface{} `json:"context,omitempty"` // echoed back in the callback
+}
+
+// MessageAction is a button or menu attached to a message
+type MessageAction struct {
+	ID          string            `json:"id,omitempty"` // alphanumeric; assigned by the server if empty
+	Name        string            `json:"name"`
+	Type        string            `json:"type,omitempty"`        // ActionTypeButton (default) or ActionTypeSelect
+	Style       string            `json:"style,omitempty"`       // "primary", "danger", ... or a hex color
+	DataSource  string            `json:"data_source,omitempty"` // "users" or "channels" for dynamic menus
+	Options     []SelectOption    `json:"options,omitempty"`
+	Integration ActionIntegration `json:"integration"`
+}
+
+// AttachmentField is a short field displayed in a table inside a message attachment
+type AttachmentField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short,omitempty"`
+}
+
+// MessageAttachment is a rich message attachment, optionally carrying actions
+type MessageAttachment struct {
+	Fallback  string            `json:"fallback,omitempty"`
+	Color     string            `json:"color,omitempty"`
+	Pretext   string            `json:"pretext,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	TitleLink string            `json:"title_link,omitempty"`
+	Text      string            `json:"text,omitempty"`
+	Fields    []AttachmentField `json:"fields,omitempty"`
+	Actions   []MessageAction   `json:"actions,omitempty"`
+}
+
+// DialogElement is an input element of an interactive dialog
+type DialogElement struct {
+	DisplayName string         `json:"display_name"`
+	Name        string         `json:"name"`
+	Type        string         `json:"type"` // "text", "textarea", "select", "bool" or "radio"
+	SubType     string         `json:"subtype,omitempty"`
+	Default     string         `json:"default,omitempty"`
+	Placeholder string         `json:"placeholder,omitempty"`
+	HelpText    string         `json:"help_text,omitempty"`
+	Optional    bool           `json:"optional,omitempty"`
+	MinLength   *uint32        `json:"min_length,omitempty"`
+	MaxLength   *uint32        `json:"max_length,omitempty"`
+	DataSource  string         `json:"data_source,omitempty"`
+	Options     []SelectOption `json:"options,omitempty"`
+}
+
+// InteractiveDialog is a modal form shown to the user who triggered an action
+type InteractiveDialog struct {
+	CallbackID       string          `json:"callback_id,omitempty"`
+	Title            string          `json:"title"`
+	IntroductionText string          `json:"introduction_text,omitempty"`
+	IconURL          string          `json:"icon_url,omitempty"`
+	Elements         []DialogElement `json:"elements"`
+	SubmitLabel      string          `json:"submit_label,omitempty"`
+	NotifyOnCancel   bool            `json:"notify_on_cancel,omitempty"`
+	State            string          `json:"state,omitempty"`
+}
+
+// ActionCallback is the payload the server posts to an action's integration URL
+type ActionCallback struct {
+	UserID      string                 `json:"user_id"`
+	UserName    string                 `json:"user_name,omitempty"`
+	ChannelID   string                 `json:"channel_id"`
+	ChannelName string                 `json:"channel_name,omitempty"`
+	TeamID      string                 `json:"team_id,omitempty"`
+	TeamDomain  string                 `json:"team_domain,omitempty"`
+	PostID      string                 `json:"post_id"`
+	TriggerID   string                 `json:"trigger_id,omitempty"` // for OpenInteractiveDialog
+	Type        string                 `json:"type,omitempty"`
+	DataSource  string                 `json:"data_source,omitempty"`
+	Context     map[string]interface{} `json:"context,omitempty"`
+}
+
+// SelectedOption returns the value chosen in a select menu, or "" for buttons
+func (c *ActionCallback) SelectedOption() string {
+	value, _ := c.Context["selected_option"].(string)
+	return value
+}
+
+// ActionPostUpdate replaces the message and props of the post an action belongs to
+type ActionPostUpdate struct {
+	Message string                 `json:"message,omitempty"`
+	Props   map[string]interface{} `json:"props,omitempty"`
+}
+
+// ActionResponse is the JSON body an integration returns from an action callback
+type ActionResponse struct {
+	Update        *ActionPostUpdate `json:"update,omitempty"`
+	EphemeralText string            `json:"ephemeral_text,omitempty"`
+	GotoLocation  string            `json:"goto_location,omitempty"`
+}
+
+// DialogSubmission is the payload the server posts when a dialog is submitted or cancelled
+type DialogSubmission struct {
+	Type       string                 `json:"type"`
+	CallbackID string                 `json:"callback_id,omitempty"`
+	State      string                 `json:"state,omitempty"`
+	UserID     string                 `json:"user_id"`
+	ChannelID  string                 `json:"channel_id"`
+	TeamID     string                 `json:"team_id,omitempty"`
+	Submission map[string]interface{} `json:"submission,omitempty"` // keyed by DialogElement.Name
+	Cancelled  bool                   `json:"cancelled,omitempty"`
+}
+
+// DialogResponse is the JSON body an integration returns from a dialog submission
+// Per-element errors keep the dialog open; an empty response closes it
+type DialogResponse struct {
+	Error  string            `json:"error,omitempty"`
+	Errors map[string]string `json:"errors,omitempty"` // keyed by DialogElement.Name
+}
+
+// ParseActionCallback decodes the body of an action callback request
+func ParseActionCallback(body []byte) (*ActionCallback, error) {
+	var callback ActionCallback
+	if err := json.Unmarshal(body, &callback); err != nil {
+		return nil, fmt.Errorf("invalid action callback: %w", err)
+	}
+	if callback.UserID == "" || callback.PostID == "" {
+		return nil, fmt.Errorf("invalid action callback: missing user_id or post_id")
+	}
+	return &callback, nil
+}
+
+// ParseDialogSubmission decodes the body of a dialog submission request
+func ParseDialogSubmission(body []byte) (*DialogSubmission, error) {
+	var submission DialogSubmission
+	if err := json.Unmarshal(body, &submission); err != nil {
+		return nil, fmt.Errorf("invalid dialog submission: %w", err)
+	}
+	if submission.UserID == "" {
+		return nil, fmt.Errorf("invalid dialog submission: missing user_id")
+	}
+	return &submission, nil
+}
+
+// SendMessageWithAttachments sends a message carrying rich attachments, such as action buttons and menus
+func (p *Platform) SendMessageWithAttachments(channelID, text string, attachments []MessageAttachment) (_ *Message, err error) {
+	defer p.audit("SendMessageWithAttachments", "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("SendMessageWithAttachments"); err != nil {
+		return nil, err
+	}
+
+	if attachments == nil {
+		attachments = []MessageAttachment{}
+	}
+	attachmentsJSON, err := json.Marshal(attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	csChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	csText, freeText := cStringFree(text)
+	defer freeText()
+
+	csAttachments, freeAttachments := cStringFree(string(attachmentsJSON))
+	defer freeAttachments()
+
+	cstr := C.communicator_platform_send_message_with_attachments(p.handle, csChannelID, csText, csAttachments)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var msg Message
+	if err := p.decode([]byte(C.GoString(cstr)), &msg); err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+// OpenInteractiveDialog opens a dialog for the user who triggered an action or slash command
+// Trigger IDs expire a few seconds after they are issued, so call this while handling the callback
+func (p *Platform) OpenInteractiveDialog(triggerID, url string, dialog *InteractiveDialog) (err error) {
+	defer p.audit("OpenInteractiveDialog", "url", url)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("OpenInteractiveDialog"); err != nil {
+		return err
+	}
+
+	dialogJSON, err := json.Marshal(dialog)
+	if err != nil {
+		return err
+	}
+
+	csTriggerID, freeTriggerID := cStringFree(triggerID)
+	defer freeTriggerID()
+
+	csURL, freeURL := cStringFree(url)
+	defer freeURL()
+
+	csDialog, freeDialog := cStringFree(string(dialogJSON))
+	defer freeDialog()
+
+	code := C.communicator_platform_open_interactive_dialog(p.handle, csTriggerID, csURL, csDialog)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+// Command soak keeps a connection open, or replays a scenario, for hours while
+// sampling resource usage, and exits non-zero if anything grows past its limit
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	comm "libcommunicator"
+	"libcommunicator/internal/testkit"
+)
+
+func main() {
+	// Parse command-line arguments
+	serverURL := flag.String("server", "", "Mattermost server URL (omit to soak a scenario)")
+	token := flag.String("token", "", "Authentication token")
+	teamID := flag.String("team", "", "Team ID")
+	scenarioPath := flag.String("scenario", "", "Scenario file to replay instead of connecting to a server")
+	probeChannel := flag.String("probe-channel", "", "Channel ID to post lag probes to")
+	duration := flag.Duration("duration", time.Hour, "How long to run")
+	interval := flag.Duration("interval", time.Minute, "How often to sample resource usage")
+	probeInterval := flag.Duration("probe-interval", 5*time.Minute, "How often to send a lag probe")
+	maxHeapGrowth := flag.Uint64("max-heap-growth", 64<<20, "Allowed heap growth in bytes (0 disables)")
+	maxGoroutineGrowth := flag.Int("max-goroutine-growth", 10, "Allowed goroutine growth (0 disables)")
+	maxFDGrowth := flag.Int("max-fd-growth", 10, "Allowed file descriptor growth (0 disables)")
+	maxLag := flag.Duration("max-lag", 10*time.Second, "Allowed probe lag (0 disables)")
+	flag.Parse()
+
+	if *serverURL == "" && *scenarioPath == "" {
+		fmt.Println("Usage: soak -server <url> -token <token> -team <team_id> [-probe-channel <channel_id>]")
+		fmt.Println("       soak -scenario <file>")
+		os.Exit(1)
+	}
+
+	// Initialize the library
+	if err := comm.Init(); err != nil {
+		log.Fatalf("Failed to initialize: %v", err)
+	}
+	defer comm.Cleanup()
+
+	// Stop early on Ctrl+C; the report so far is still printed
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	opts := testkit.SoakOptions{
+		Duration:       *duration,
+		SampleInterval: *interval,
+		ProbeChannelID: *probeChannel,
+		ProbeInterval:  *probeInterval,
+		OnSample: func(s testkit.SoakSample) {
+			fmt.Printf("[%8s] heap=%dKiB objects=%d goroutines=%d fds=%d cgo=%d events=%d errors=%d lag=%s\n",
+				s.Elapsed.Truncate(time.Second), s.HeapAlloc>>10, s.HeapObjects, s.Goroutines,
+				s.OpenFDs, s.CgoCalls, s.Events, s.Errors, s.MaxLag)
+		},
+	}
+
+	var report *testkit.SoakReport
+	if *scenarioPath != "" {
+		scenario, err := testkit.LoadScenario(*scenarioPath)
+		if err != nil {
+			log.Fatalf("Failed to load scenario: %v", err)
+		}
+		fmt.Printf("Soaking scenario %s for %s\n", *scenarioPath, *duration)
+		report, err = testkit.SoakScenario(ctx, scenario, opts)
+		if err != nil {
+			log.Fatalf("Soak failed: %v", err)
+		}
+	} else {
+		platform, err := comm.NewMattermostPlatform(*serverURL)
+		if err != nil {
+			log.Fatalf("Failed to create platform: %v", err)
+		}
+		defer platform.Close()
+
+		config := comm.NewPlatformConfig(*serverURL).WithToken(*token).WithTeamID(*teamID)
+		if err := platform.Connect(config); err != nil {
+			log.Fatalf("Failed to connect: %v", err)
+		}
+		defer platform.Disconnect()
+
+		fmt.Printf("Soaking %s for %s\n", *serverURL, *duration)
+		report, err = testkit.Soak(ctx, platform, opts)
+		if err != nil {
+			log.Fatalf("Soak failed: %v", err)
+		}
+	}
+
+	fmt.Printf("\nEvents: %d, errors: %d, probes: %d sent, %d lost\n",
+		report.Events, report.Errors, report.ProbesSent, report.ProbesLost)
+	if len(report.Lags) > 0 {
+		fmt.Printf("Probe lag: p50=%s p95=%s max=%s\n",
+			report.LagPercentile(0.5), report.LagPercentile(0.95), report.LagPercentile(1))
+	}
+
+	problems := report.Check(testkit.SoakLimits{
+		HeapGrowth:      *maxHeapGrowth,
+		GoroutineGrowth: *maxGoroutineGrowth,
+		FDGrowth:        *maxFDGrowth,
+		MaxLag:          *maxLag,
+	})
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Printf("FAIL: %s\n", problem)
+		}
+		os.Exit(1)
+	}
+	fmt.Println("PASS")
+}
@@ -0,0 +1,423 @@
+package testkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	comm "libcommunicator"
+)
+
+// Scenario is a scripted timeline of platform events for testing handlers
+// without a server. Build one in Go or load it from a JSON file, then replay
+// it into an EventRouter:
+//
+//	s := NewScenario().
+//		AddUser(comm.User{ID: "u1", Username: "alice"}).
+//		AddChannel(comm.Channel{ID: "c1", Name: "town-square"}).
+//		Post(0, "u1", "c1", "m1", "hello").
+//		Edit(500*time.Millisecond, "m1", "hello world").
+//		Delete(2*time.Second, "m1")
+//	err := s.Replay(ctx, router.Handle, ReplayOptions{})
+//
+// Events are shaped exactly like those returned by PollEvent.
+type Scenario struct {
+	// Start is the wall-clock time of offset zero, used for message timestamps
+	Start time.Time
+
+	users    map[string]comm.User
+	channels map[string]comm.Channel
+	messages map[string]comm.Message
+	steps    []ScenarioStep
+	err      error
+}
+
+// ScenarioStep is one event of a scenario and its offset from the start
+type ScenarioStep struct {
+	At    time.Duration
+	Event *comm.Event
+}
+
+// ReplayOptions controls how a scenario is replayed
+type ReplayOptions struct {
+	// Speed scales the delays between steps: 1 replays in real time, 2 twice
+	// as fast. Zero delivers every event immediately, in order.
+	Speed float64
+}
+
+// NewScenario creates an empty scenario starting at 2024-01-01 00:00 UTC
+func NewScenario() *Scenario {
+	return &Scenario{
+		Start:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		users:    make(map[string]comm.User),
+		channels: make(map[string]comm.Channel),
+		messages: make(map[string]comm.Message),
+	}
+}
+
+// AddUser declares a user that steps may refer to
+func (s *Scenario) AddUser(user comm.User) *Scenario {
+	s.users[user.ID] = user
+	return s
+}
+
+// AddChannel declares a channel that steps may refer to
+func (s *Scenario) AddChannel(channel comm.Channel) *Scenario {
+	s.channels[channel.ID] = channel
+	return s
+}
+
+// Users returns the declared users, e.g. to back a stubbed user lookup
+func (s *Scenario) Users() []comm.User {
+	users := make([]comm.User, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users
+}
+
+// Channels returns the declared channels
+func (s *Scenario) Channels() []comm.Channel {
+	channels := make([]comm.Channel, 0, len(s.channels))
+	for _, channel := range s.channels {
+		channels = append(channels, channel)
+	}
+	sort.Slice(channels, func(i, j int) bool { return channels[i].ID < channels[j].ID })
+	return channels
+}
+
+// Post adds a message_posted event at the given offset
+func (s *Scenario) Post(at time.Duration, userID, channelID, messageID, text string) *Scenario {
+	if !s.checkUser(userID) || !s.checkChannel(channelID) {
+		return s
+	}
+	if _, exists := s.messages[messageID]; exists {
+		return s.fail("message %q posted twice", messageID)
+	}
+
+	msg := comm.Message{
+		ID:        messageID,
+		ChannelID: channelID,
+		SenderID:  userID,
+		Text:      text,
+		CreatedAt: s.Start.Add(at),
+	}
+	s.messages[messageID] = msg
+	return s.addMessageEvent(at, comm.EventMessagePosted, msg)
+}
+
+// Edit adds a message_updated event replacing a posted message's text
+func (s *Scenario) Edit(at time.Duration, messageID, text string) *Scenario {
+	msg, ok := s.message(messageID)
+	if !ok {
+		return s
+	}
+
+	edited := s.Start.Add(at)
+	msg.Text = text
+	msg.EditedAt = &edited
+	s.messages[messageID] = msg
+	return s.addMessageEvent(at, comm.EventMessageUpdated, msg)
+}
+
+// Delete adds a message_deleted event for a posted message
+func (s *Scenario) Delete(at time.Duration, messageID string) *Scenario {
+	msg, ok := s.message(messageID)
+	if !ok {
+		return s
+	}
+	return s.add(at, comm.Event{Type: comm.EventMessageDeleted, MessageID: messageID, ChannelID: msg.ChannelID})
+}
+
+// Pin adds a post_pinned event for a posted message
+func (s *Scenario) Pin(at time.Duration, messageID string) *Scenario {
+	msg, ok := s.message(messageID)
+	if !ok {
+		return s
+	}
+	return s.addMessageEvent(at, comm.EventPostPinned, msg)
+}
+
+// Unpin adds a post_unpinned event for a posted message
+func (s *Scenario) Unpin(at time.Duration, messageID string) *Scenario {
+	msg, ok := s.message(messageID)
+	if !ok {
+		return s
+	}
+	return s.addMessageEvent(at, comm.EventPostUnpinned, msg)
+}
+
+// React adds a reaction_added event
+func (s *Scenario) React(at time.Duration, userID, messageID, emojiName string) *Scenario {
+	return s.reaction(at, comm.EventReactionAdded, userID, messageID, emojiName)
+}
+
+// Unreact adds a reaction_removed event
+func (s *Scenario) Unreact(at time.Duration, userID, messageID, emojiName string) *Scenario {
+	return s.reaction(at, comm.EventReactionRemoved, userID, messageID, emojiName)
+}
+
+// Typing adds a user_typing event
+func (s *Scenario) Typing(at time.Duration, userID, channelID string) *Scenario {
+	if !s.checkUser(userID) || !s.checkChannel(channelID) {
+		return s
+	}
+	return s.add(at, comm.Event{Type: comm.EventUserTyping, UserID: userID, ChannelID: channelID})
+}
+
+// Join adds a user_joined_channel event
+func (s *Scenario) Join(at time.Duration, userID, channelID string) *Scenario {
+	if !s.checkUser(userID) || !s.checkChannel(channelID) {
+		return s
+	}
+	return s.add(at, comm.Event{Type: comm.EventUserJoinedChannel, UserID: userID, ChannelID: channelID})
+}
+
+// Leave adds a user_left_channel event
+func (s *Scenario) Leave(at time.Duration, userID, channelID string) *Scenario {
+	if !s.checkUser(userID) || !s.checkChannel(channelID) {
+		return s
+	}
+	return s.add(at, comm.Event{Type: comm.EventUserLeftChannel, UserID: userID, ChannelID: channelID})
+}
+
+// SetStatus adds a user_status_changed event, e.g. status "away"
+func (s *Scenario) SetStatus(at time.Duration, userID, status string) *Scenario {
+	if !s.checkUser(userID) {
+		return s
+	}
+	return s.add(at, comm.Event{Type: comm.EventUserStatusChanged, UserID: userID, Status: status})
+}
+
+// Emit adds an arbitrary event, for event types without a builder method
+func (s *Scenario) Emit(at time.Duration, event *comm.Event) *Scenario {
+	if event == nil {
+		return s.fail("nil event at %s", at)
+	}
+	return s.add(at, *event)
+}
+
+// Err returns the first error made while building the scenario
+func (s *Scenario) Err() error {
+	return s.err
+}
+
+// Steps returns the scenario's events ordered by offset
+// Steps at the same offset keep the order they were added in.
+func (s *Scenario) Steps() ([]ScenarioStep, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	steps := append([]ScenarioStep(nil), s.steps...)
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].At < steps[j].At })
+	return steps, nil
+}
+
+// Replay delivers the scenario's events to handle in order
+// It returns early with the context's error if ctx is cancelled.
+func (s *Scenario) Replay(ctx context.Context, handle comm.EventHandler, opts ReplayOptions) error {
+	steps, err := s.Steps()
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	for _, step := range steps {
+		if opts.Speed > 0 {
+			due := start.Add(time.Duration(float64(step.At) / opts.Speed))
+			if wait := time.Until(due); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				case <-timer.C:
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		handle(step.Event)
+	}
+	return nil
+}
+
+func (s *Scenario) add(at time.Duration, event comm.Event) *Scenario {
+	if s.err != nil {
+		return s
+	}
+	if at < 0 {
+		return s.fail("negative offset %s for %s event", at, event.Type)
+	}
+
+	// Round-trip through JSON so handlers see the same shapes as from PollEvent
+	data, err := json.Marshal(event)
+	if err != nil {
+		return s.fail("%s event: %v", event.Type, err)
+	}
+	var decoded comm.Event
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return s.fail("%s event: %v", event.Type, err)
+	}
+
+	s.steps = append(s.steps, ScenarioStep{At: at, Event: &decoded})
+	return s
+}
+
+func (s *Scenario) addMessageEvent(at time.Duration, eventType string, msg comm.Message) *Scenario {
+	return s.add(at, comm.Event{Type: eventType, Data: msg})
+}
+
+func (s *Scenario) reaction(at time.Duration, eventType, userID, messageID, emojiName string) *Scenario {
+	msg, ok := s.message(messageID)
+	if !ok || !s.checkUser(userID) {
+		return s
+	}
+	return s.add(at, comm.Event{
+		Type:      eventType,
+		MessageID: messageID,
+		UserID:    userID,
+		EmojiName: emojiName,
+		ChannelID: msg.ChannelID,
+	})
+}
+
+func (s *Scenario) message(messageID string) (comm.Message, bool) {
+	msg, ok := s.messages[messageID]
+	if !ok {
+		s.fail("unknown message %q", messageID)
+	}
+	return msg, ok
+}
+
+func (s *Scenario) checkUser(userID string) bool {
+	if _, ok := s.users[userID]; !ok {
+		s.fail("unknown user %q", userID)
+		return false
+	}
+	return true
+}
+
+func (s *Scenario) checkChannel(channelID string) bool {
+	if _, ok := s.channels[channelID]; !ok {
+		s.fail("unknown channel %q", channelID)
+		return false
+	}
+	return true
+}
+
+func (s *Scenario) fail(format string, args ...interface{}) *Scenario {
+	if s.err == nil {
+		s.err = fmt.Errorf("scenario: "+format, args...)
+	}
+	return s
+}
+
+// scenarioFile is the JSON form of a scenario:
+//
+//	{
+//	  "users": [{"id": "u1", "username": "alice"}],
+//	  "channels": [{"id": "c1", "name": "town-square", "type": "public"}],
+//	  "timeline": [
+//	    {"at": "0s", "action": "post", "user_id": "u1", "channel_id": "c1", "message_id": "m1", "text": "hi"},
+//	    {"at": "1.5s", "action": "edit", "message_id": "m1", "text": "hello"},
+//	    {"at": "2s", "action": "delete", "message_id": "m1"}
+//	  ]
+//	}
+type scenarioFile struct {
+	Start    *time.Time      `json:"start,omitempty"`
+	Users    []comm.User     `json:"users"`
+	Channels []comm.Channel  `json:"channels"`
+	Timeline []scenarioEntry `json:"timeline"`
+}
+
+type scenarioEntry struct {
+	At        string `json:"at"`
+	Action    string `json:"action"`
+	UserID    string `json:"user_id,omitempty"`
+	ChannelID string `json:"channel_id,omitempty"`
+	MessageID string `json:"message_id,omitempty"`
+	Text      string `json:"text,omitempty"`
+	EmojiName string `json:"emoji_name,omitempty"`
+	Status    string `json:"status,omitempty"`
+	// Event is the event emitted by the "emit" action
+	Event *comm.Event `json:"event,omitempty"`
+}
+
+// ParseScenario builds a scenario from its JSON form
+// Actions are post, edit, delete, pin, unpin, react, unreact, typing, join,
+// leave, status and emit; offsets use time.ParseDuration syntax. Entries
+// are applied in file order, so a message must be posted before it is edited.
+func ParseScenario(data []byte) (*Scenario, error) {
+	var file scenarioFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("scenario: %w", err)
+	}
+
+	s := NewScenario()
+	if file.Start != nil {
+		s.Start = *file.Start
+	}
+	for _, user := range file.Users {
+		s.AddUser(user)
+	}
+	for _, channel := range file.Channels {
+		s.AddChannel(channel)
+	}
+
+	for i, entry := range file.Timeline {
+		at, err := time.ParseDuration(entry.At)
+		if err != nil {
+			return nil, fmt.Errorf("scenario: timeline[%d]: %w", i, err)
+		}
+
+		switch entry.Action {
+		case "post":
+			s.Post(at, entry.UserID, entry.ChannelID, entry.MessageID, entry.Text)
+		case "edit":
+			s.Edit(at, entry.MessageID, entry.Text)
+		case "delete":
+			s.Delete(at, entry.MessageID)
+		case "pin":
+			s.Pin(at, entry.MessageID)
+		case "unpin":
+			s.Unpin(at, entry.MessageID)
+		case "react":
+			s.React(at, entry.UserID, entry.MessageID, entry.EmojiName)
+		case "unreact":
+			s.Unreact(at, entry.UserID, entry.MessageID, entry.EmojiName)
+		case "typing":
+			s.Typing(at, entry.UserID, entry.ChannelID)
+		case "join":
+			s.Join(at, entry.UserID, entry.ChannelID)
+		case "leave":
+			s.Leave(at, entry.UserID, entry.ChannelID)
+		case "status":
+			s.SetStatus(at, entry.UserID, entry.Status)
+		case "emit":
+			s.Emit(at, entry.Event)
+		default:
+			return nil, fmt.Errorf("scenario: timeline[%d]: unknown action %q", i, entry.Action)
+		}
+
+		if err := s.Err(); err != nil {
+			return nil, fmt.Errorf("timeline[%d]: %w", i, err)
+		}
+	}
+
+	return s, nil
+}
+
+// LoadScenario reads a scenario from a JSON file (see ParseScenario)
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScenario(data)
+}
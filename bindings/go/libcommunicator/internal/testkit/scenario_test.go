@@ -0,0 +1,151 @@
+package testkit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	comm "libcommunicator"
+)
+
+const sampleScenario = `{"users":[{"id":"u1","username":"alice"}],"channels":[{"id":"c1","name":"town-square"}],` +
+	`"timeline":[{"at":"0s","action":"post","user_id":"u1","channel_id":"c1","message_id":"p1","text":"hi"},` +
+	`{"at":"2s","action":"delete","message_id":"p1"},` +
+	`{"at":"1s","action":"react","user_id":"u1","message_id":"p1","emoji_name":"+1"}]}`
+
+func TestParseScenario(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "steps ordered by offset",
+			data: sampleScenario,
+			want: []string{comm.EventMessagePosted, comm.EventReactionAdded, comm.EventMessageDeleted},
+		},
+		{
+			name: "emit",
+			data: `{"timeline":[{"at":"1m","action":"emit","event":{"type":"hello"}}]}`,
+			want: []string{"hello"},
+		},
+		{
+			name:    "unknown user",
+			data:    `{"channels":[{"id":"c1"}],"timeline":[{"at":"0s","action":"typing","user_id":"u1","channel_id":"c1"}]}`,
+			wantErr: `timeline[0]: scenario: unknown user "u1"`,
+		},
+		{
+			name:    "edit before post",
+			data:    `{"timeline":[{"at":"0s","action":"edit","message_id":"p1","text":"hi"}]}`,
+			wantErr: `unknown message "p1"`,
+		},
+		{
+			name:    "negative offset",
+			data:    `{"timeline":[{"at":"-1s","action":"emit","event":{"type":"hello"}}]}`,
+			wantErr: "negative offset -1s",
+		},
+		{
+			name:    "bad offset",
+			data:    `{"timeline":[{"at":"soon","action":"post"}]}`,
+			wantErr: "timeline[0]: time: invalid duration",
+		},
+		{
+			name:    "unknown action",
+			data:    `{"timeline":[{"at":"0s","action":"shout"}]}`,
+			wantErr: `unknown action "shout"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseScenario([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			steps, err := s.Steps()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, step := range steps {
+				got = append(got, step.Event.Type)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("steps = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScenarioEventsMatchPollEvent(t *testing.T) {
+	s := NewScenario().
+		AddUser(comm.User{ID: "u1"}).
+		AddChannel(comm.Channel{ID: "c1"}).
+		Post(time.Second, "u1", "c1", "p1", "hi")
+	steps, err := s.Steps()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Message payloads arrive decoded from JSON, as from PollEvent
+	data, ok := steps[0].Event.Data.(map[string]interface{})
+	if !ok {
+		t.Fatalf("event data is a %T, want a JSON object", steps[0].Event.Data)
+	}
+	if data["text"] != "hi" || data["created_at"] != "2024-01-01T00:00:01Z" {
+		t.Fatalf("event data = %v", data)
+	}
+}
+
+func TestScenarioReplay(t *testing.T) {
+	s, err := ParseScenario([]byte(sampleScenario))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var handled []string
+	if err := s.Replay(context.Background(), func(event *comm.Event) {
+		handled = append(handled, event.Type)
+	}, ReplayOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(handled) != 3 {
+		t.Fatalf("handled %v, want all three events", handled)
+	}
+
+	// In real time the delete is two seconds out, past the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	handled = nil
+	if err := s.Replay(ctx, func(event *comm.Event) {
+		handled = append(handled, event.Type)
+	}, ReplayOptions{Speed: 1}); err != context.DeadlineExceeded {
+		t.Fatalf("Replay = %v, want the context's error", err)
+	}
+	if len(handled) != 1 {
+		t.Fatalf("handled %v before the deadline, want the post", handled)
+	}
+}
+
+// FuzzScenario feeds arbitrary JSON to the scenario file parser
+func FuzzScenario(f *testing.F) {
+	f.Add([]byte(sampleScenario))
+	f.Add([]byte(`{"timeline":[{"at":"-1s","action":"typing"},{"at":"1h","action":"emit","event":{"type":"hello"}}]}`))
+	f.Add([]byte(`{"timeline":[{"at":"soon","action":"post"}]}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		scenario, err := ParseScenario(data)
+		if err != nil {
+			return
+		}
+		_, _ = scenario.Steps()
+	})
+}
@@ -0,0 +1,333 @@
+// Package testkit holds test and development tooling for libcommunicator:
+// soak runs that watch a process for leaks, and scripted scenarios that
+// replay a timeline of events without a server. It is internal so none of
+// it becomes part of the public API.
+package testkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	comm "libcommunicator"
+)
+
+// SoakOptions controls a soak run
+type SoakOptions struct {
+	// Duration is how long to run; defaults to one hour
+	Duration time.Duration
+	// SampleInterval is how often resource usage is sampled; defaults to one minute
+	SampleInterval time.Duration
+	// PollInterval is the event stream poll interval; defaults to 100ms
+	PollInterval time.Duration
+	// ProbeChannelID enables lag probes against a real server: a message is
+	// posted there every ProbeInterval and timed until its event arrives
+	ProbeChannelID string
+	// ProbeInterval defaults to five minutes
+	ProbeInterval time.Duration
+	// OnSample is called with every sample as it is taken
+	OnSample func(SoakSample)
+}
+
+// SoakSample is a snapshot of resource usage during a soak run
+type SoakSample struct {
+	Elapsed     time.Duration
+	HeapAlloc   uint64 // bytes of live Go heap
+	HeapObjects uint64
+	Sys         uint64 // bytes obtained from the OS by the Go runtime
+	Goroutines  int
+	OpenFDs     int   // -1 where /proc/self/fd and /dev/fd are unavailable
+	CgoCalls    int64 // cumulative
+	Events      uint64
+	Errors      uint64
+	// MaxLag is the worst probe lag observed since the previous sample
+	MaxLag time.Duration
+}
+
+// SoakReport is the outcome of a soak run
+type SoakReport struct {
+	Samples    []SoakSample
+	Events     uint64
+	Errors     uint64
+	ProbesSent int
+	ProbesLost int // probes whose event never arrived
+	// Lags holds the delivery lag of every probe that arrived, in order
+	Lags []time.Duration
+}
+
+// SoakLimits are the growth thresholds Check enforces; zero disables a check
+type SoakLimits struct {
+	HeapGrowth      uint64 // bytes
+	GoroutineGrowth int
+	FDGrowth        int
+	MaxLag          time.Duration
+}
+
+// LagPercentile returns the probe lag at quantile q (0..1), or 0 without probes
+func (r *SoakReport) LagPercentile(q float64) time.Duration {
+	if len(r.Lags) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), r.Lags...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	i := int(q * float64(len(sorted)-1))
+	if i < 0 {
+		i = 0
+	} else if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	return sorted[i]
+}
+
+// Check compares the last sample against the first one after warm-up and
+// returns a description of every limit exceeded
+// The first sample is skipped as warm-up when there are at least three, since
+// caches and connection pools fill during the first interval.
+func (r *SoakReport) Check(limits SoakLimits) []string {
+	var problems []string
+	if len(r.Samples) >= 2 {
+		base := r.Samples[0]
+		if len(r.Samples) >= 3 {
+			base = r.Samples[1]
+		}
+		last := r.Samples[len(r.Samples)-1]
+
+		if limits.HeapGrowth > 0 && last.HeapAlloc > base.HeapAlloc+limits.HeapGrowth {
+			problems = append(problems, fmt.Sprintf("heap grew by %d bytes (limit %d)",
+				last.HeapAlloc-base.HeapAlloc, limits.HeapGrowth))
+		}
+		if limits.GoroutineGrowth > 0 && last.Goroutines > base.Goroutines+limits.GoroutineGrowth {
+			problems = append(problems, fmt.Sprintf("goroutines grew by %d (limit %d)",
+				last.Goroutines-base.Goroutines, limits.GoroutineGrowth))
+		}
+		if limits.FDGrowth > 0 && base.OpenFDs >= 0 && last.OpenFDs > base.OpenFDs+limits.FDGrowth {
+			problems = append(problems, fmt.Sprintf("open file descriptors grew by %d (limit %d)",
+				last.OpenFDs-base.OpenFDs, limits.FDGrowth))
+		}
+	}
+	if limits.MaxLag > 0 {
+		if worst := r.LagPercentile(1); worst > limits.MaxLag {
+			problems = append(problems, fmt.Sprintf("event lag reached %s (limit %s)", worst, limits.MaxLag))
+		}
+	}
+	if r.ProbesLost > 0 {
+		problems = append(problems, fmt.Sprintf("%d of %d probes never arrived", r.ProbesLost, r.ProbesSent))
+	}
+	return problems
+}
+
+// Soak keeps the platform's event stream open for opts.Duration, sampling
+// memory, goroutines, file descriptors and cgo calls, to catch leaks that
+// only show up in long-running processes
+// The platform must be connected. Cancelling ctx ends the run early and
+// still returns the report collected so far.
+func Soak(ctx context.Context, p *comm.Platform, opts SoakOptions) (*SoakReport, error) {
+	opts = opts.withDefaults()
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	stream, err := p.NewEventStream(ctx, 1000, opts.PollInterval)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	run := newSoakRun(opts)
+
+	var probeTick <-chan time.Time
+	if opts.ProbeChannelID != "" {
+		ticker := time.NewTicker(opts.ProbeInterval)
+		defer ticker.Stop()
+		probeTick = ticker.C
+	}
+
+	pending := make(map[string]time.Time)
+	for {
+		select {
+		case <-ctx.Done():
+			run.report.ProbesLost = len(pending)
+			return run.finish(), nil
+		case <-run.sampleTick.C:
+			run.sample()
+		case <-probeTick:
+			text := "soak probe " + strconv.FormatInt(time.Now().UnixNano(), 10)
+			pending[text] = time.Now()
+			run.report.ProbesSent++
+			if _, err := p.SendMessage(opts.ProbeChannelID, text); err != nil {
+				delete(pending, text)
+				run.errors++
+			}
+		case err, ok := <-stream.Errors():
+			if ok && err != nil {
+				run.errors++
+			}
+		case event, ok := <-stream.Events():
+			if !ok {
+				run.report.ProbesLost = len(pending)
+				return run.finish(), nil
+			}
+			run.events++
+			if event.Type == comm.EventMessagePosted && len(pending) > 0 {
+				text := soakEventText(event)
+				if sent, ok := pending[text]; ok {
+					delete(pending, text)
+					run.observeLag(time.Since(sent))
+				}
+			}
+		}
+	}
+}
+
+// SoakScenario replays a scenario in a loop for opts.Duration, decoding every
+// event the way PollEvent does, so binding-side leaks can be soak-tested
+// without a server
+// Probe options are ignored; there is no delivery lag to measure.
+func SoakScenario(ctx context.Context, s *Scenario, opts SoakOptions) (*SoakReport, error) {
+	steps, err := s.Steps()
+	if err != nil {
+		return nil, err
+	}
+	opts = opts.withDefaults()
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	run := newSoakRun(opts)
+	for {
+		for _, step := range steps {
+			select {
+			case <-ctx.Done():
+				return run.finish(), nil
+			case <-run.sampleTick.C:
+				run.sample()
+			default:
+			}
+
+			data, err := json.Marshal(step.Event)
+			if err != nil {
+				run.errors++
+				continue
+			}
+			var event comm.Event
+			if err := json.Unmarshal(data, &event); err != nil {
+				run.errors++
+				continue
+			}
+			run.events++
+		}
+		if len(steps) == 0 {
+			// Nothing to replay; just sample until the run ends
+			select {
+			case <-ctx.Done():
+				return run.finish(), nil
+			case <-run.sampleTick.C:
+				run.sample()
+			}
+		}
+	}
+}
+
+func (o SoakOptions) withDefaults() SoakOptions {
+	if o.Duration <= 0 {
+		o.Duration = time.Hour
+	}
+	if o.SampleInterval <= 0 {
+		o.SampleInterval = time.Minute
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = 100 * time.Millisecond
+	}
+	if o.ProbeInterval <= 0 {
+		o.ProbeInterval = 5 * time.Minute
+	}
+	return o
+}
+
+// soakRun collects samples for a single soak run
+type soakRun struct {
+	opts       SoakOptions
+	start      time.Time
+	sampleTick *time.Ticker
+	events     uint64
+	errors     uint64
+	windowLag  time.Duration
+	report     SoakReport
+}
+
+func newSoakRun(opts SoakOptions) *soakRun {
+	run := &soakRun{
+		opts:       opts,
+		start:      time.Now(),
+		sampleTick: time.NewTicker(opts.SampleInterval),
+	}
+	run.sample()
+	return run
+}
+
+func (r *soakRun) observeLag(lag time.Duration) {
+	r.report.Lags = append(r.report.Lags, lag)
+	if lag > r.windowLag {
+		r.windowLag = lag
+	}
+}
+
+func (r *soakRun) sample() {
+	// Collect first so HeapAlloc reflects live memory, not garbage
+	runtime.GC()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	s := SoakSample{
+		Elapsed:     time.Since(r.start),
+		HeapAlloc:   mem.HeapAlloc,
+		HeapObjects: mem.HeapObjects,
+		Sys:         mem.Sys,
+		Goroutines:  runtime.NumGoroutine(),
+		OpenFDs:     countOpenFDs(),
+		CgoCalls:    runtime.NumCgoCall(),
+		Events:      r.events,
+		Errors:      r.errors,
+		MaxLag:      r.windowLag,
+	}
+	r.windowLag = 0
+	r.report.Samples = append(r.report.Samples, s)
+	if r.opts.OnSample != nil {
+		r.opts.OnSample(s)
+	}
+}
+
+func (r *soakRun) finish() *SoakReport {
+	r.sampleTick.Stop()
+	r.sample()
+	r.report.Events = r.events
+	r.report.Errors = r.errors
+	return &r.report
+}
+
+// countOpenFDs returns the number of open file descriptors, or -1 if unknown
+func countOpenFDs() int {
+	for _, dir := range []string{"/proc/self/fd", "/dev/fd"} {
+		if entries, err := os.ReadDir(dir); err == nil {
+			// The directory handle used for reading is itself listed
+			return len(entries) - 1
+		}
+	}
+	return -1
+}
+
+// soakEventText returns the text of a message event's payload
+func soakEventText(event *comm.Event) string {
+	data, ok := event.Data.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	text, _ := data["text"].(string)
+	return strings.TrimSpace(text)
+}
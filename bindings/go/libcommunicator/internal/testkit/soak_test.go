@@ -0,0 +1,63 @@
+package testkit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSoakReportCheck(t *testing.T) {
+	samples := []SoakSample{
+		{HeapAlloc: 1 << 20, Goroutines: 5, OpenFDs: 10},
+		{HeapAlloc: 4 << 20, Goroutines: 8, OpenFDs: 12}, // warm-up baseline
+		{HeapAlloc: 5 << 20, Goroutines: 9, OpenFDs: 13},
+	}
+	limits := SoakLimits{HeapGrowth: 2 << 20, GoroutineGrowth: 2, FDGrowth: 2, MaxLag: time.Second}
+
+	tests := []struct {
+		name   string
+		report SoakReport
+		want   []string
+	}{
+		{
+			name:   "within limits after warm-up",
+			report: SoakReport{Samples: samples, Lags: []time.Duration{time.Millisecond}},
+		},
+		{
+			name:   "no warm-up with two samples",
+			report: SoakReport{Samples: samples[:2]},
+			want:   []string{"heap grew by 3145728 bytes (limit 2097152)", "goroutines grew by 3 (limit 2)"},
+		},
+		{
+			name: "unknown descriptor count",
+			report: SoakReport{Samples: []SoakSample{
+				{OpenFDs: -1}, {OpenFDs: -1}, {OpenFDs: 50},
+			}},
+		},
+		{
+			name:   "lag and lost probes",
+			report: SoakReport{ProbesSent: 4, ProbesLost: 1, Lags: []time.Duration{time.Millisecond, 2 * time.Second, time.Second}},
+			want:   []string{"event lag reached 2s (limit 1s)", "1 of 4 probes never arrived"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.Check(limits); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Check = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSoakReportLagPercentile(t *testing.T) {
+	report := SoakReport{Lags: []time.Duration{4, 1, 3, 2, 5}}
+	for q, want := range map[float64]time.Duration{0: 1, 0.5: 3, 1: 5, 2: 5} {
+		if got := report.LagPercentile(q); got != want {
+			t.Errorf("LagPercentile(%v) = %v, want %v", q, got, want)
+		}
+	}
+	if got := (&SoakReport{}).LagPercentile(0.5); got != 0 {
+		t.Errorf("LagPercentile without probes = %v, want 0", got)
+	}
+}
@@ -0,0 +1,395 @@
+package libcommunicator
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Built-in job kinds
+const (
+	// JobChannelTranscript writes a plain text transcript of ChannelIDs[0]
+	JobChannelTranscript = "channel_transcript"
+	// JobComplianceExport writes the messages of ChannelIDs as CSV
+	JobComplianceExport = "compliance_export"
+	// JobUserDataExport writes a JSON document with UserID's profile,
+	// preferences and the messages they sent in ChannelIDs
+	JobUserDataExport = "user_data_export"
+)
+
+// JobState is the lifecycle state of a job
+type JobState string
+
+const (
+	JobRunning   JobState = "running"
+	JobSucceeded JobState = "succeeded"
+	JobFailed    JobState = "failed"
+	JobCancelled JobState = "cancelled"
+)
+
+// Job event types
+const (
+	JobEventProgress  = "job_progress"
+	JobEventCompleted = "job_completed"
+)
+
+// JobEvent reports the progress or completion of a job
+type JobEvent struct {
+	Type  string // JobEventProgress or JobEventCompleted
+	JobID string
+	Kind  string
+	State JobState
+	Done  int64
+	Total int64 // 0 while unknown
+	// Err is set on completion when the job failed or was cancelled
+	Err error
+}
+
+// JobFunc does the work of a job, writing its result to w
+// It should return promptly once ctx is done, and may call progress as often as it likes.
+type JobFunc func(ctx context.Context, w io.Writer, progress func(done, total int64)) error
+
+// JobSpec describes a job for StartJob
+type JobSpec struct {
+	// Kind is one of the built-in job kinds, or a free-form label when Run is set
+	Kind string
+	// ChannelIDs are the channels exported by the built-in jobs
+	ChannelIDs []string
+	// UserID is the user whose data JobUserDataExport exports
+	UserID string
+	// Since and Until bound the exported messages; zero values leave the window open
+	Since time.Time
+	Until time.Time
+	// Run, if set, is the work of a custom job
+	Run JobFunc
+}
+
+// Job is a handle to a running job
+type Job struct {
+	id     string
+	kind   string
+	cancel context.CancelFunc
+	events chan JobEvent
+	done   chan struct{}
+
+	progressDone  atomic.Int64
+	progressTotal atomic.Int64
+
+	mu    sync.Mutex
+	state JobState
+	err   error
+}
+
+var jobCounter atomic.Uint64
+
+// StartJob starts a long-running job that streams its result to w
+// Progress and completion arrive on Job.Events; cancelling ctx or calling
+// Job.Cancel stops the job.
+func (p *Platform) StartJob(ctx context.Context, spec JobSpec, w io.Writer) (*Job, error) {
+	if p.destroyed() {
+		return nil, ErrClosed
+	}
+	if w == nil {
+		return nil, errors.New("job: nil writer")
+	}
+
+	run := spec.Run
+	if run == nil {
+		var err error
+		if run, err = p.builtinJob(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	job := &Job{
+		id:     "job-" + strconv.FormatUint(jobCounter.Add(1), 10),
+		kind:   spec.Kind,
+		cancel: cancel,
+		events: make(chan JobEvent, 64),
+		done:   make(chan struct{}),
+		state:  JobRunning,
+	}
+
+	go func() {
+		defer cancel()
+		job.finish(ctx, run(ctx, w, job.report))
+	}()
+
+	return job, nil
+}
+
+// ID returns the job's identifier, unique within the process
+func (j *Job) ID() string {
+	return j.id
+}
+
+// Kind returns the job's kind
+func (j *Job) Kind() string {
+	return j.kind
+}
+
+// Events returns the job's progress and completion events
+// The channel is closed after the completion event. Progress events are
+// dropped when the channel is full; the completion event never is.
+func (j *Job) Events() <-chan JobEvent {
+	return j.events
+}
+
+// Cancel stops the job; Wait returns once it has stopped
+func (j *Job) Cancel() {
+	j.cancel()
+}
+
+// Done returns a channel that is closed when the job has finished
+func (j *Job) Done() <-chan struct{} {
+	return j.done
+}
+
+// Wait blocks until the job finishes and returns its error
+func (j *Job) Wait() error {
+	<-j.done
+	return j.Err()
+}
+
+// State returns the job's current state
+func (j *Job) State() JobState {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.state
+}
+
+// Err returns the error the job failed or was cancelled with
+func (j *Job) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
+
+// Progress returns the last reported progress; total is 0 while unknown
+func (j *Job) Progress() (done, total int64) {
+	return j.progressDone.Load(), j.progressTotal.Load()
+}
+
+func (j *Job) report(done, total int64) {
+	j.progressDone.Store(done)
+	j.progressTotal.Store(total)
+
+	// Keep the last slot free for the completion event
+	if len(j.events) >= cap(j.events)-1 {
+		return
+	}
+	select {
+	case j.events <- JobEvent{Type: JobEventProgress, JobID: j.id, Kind: j.kind, State: JobRunning, Done: done, Total: total}:
+	default:
+	}
+}
+
+func (j *Job) finish(ctx context.Context, err error) {
+	state := JobSucceeded
+	switch {
+	case err == nil:
+	case ctx.Err() != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)):
+		state = JobCancelled
+	default:
+		state = JobFailed
+	}
+
+	j.mu.Lock()
+	j.state = state
+	j.err = err
+	j.mu.Unlock()
+
+	done, total := j.Progress()
+	j.events <- JobEvent{Type: JobEventCompleted, JobID: j.id, Kind: j.kind, State: state, Done: done, Total: total, Err: err}
+	close(j.events)
+	close(j.done)
+}
+
+// builtinJob returns the work of a built-in job kind
+func (p *Platform) builtinJob(spec JobSpec) (JobFunc, error) {
+	switch spec.Kind {
+	case JobChannelTranscript:
+		if len(spec.ChannelIDs) != 1 {
+			return nil, fmt.Errorf("job %s: exactly one channel ID is required", spec.Kind)
+		}
+		return func(ctx context.Context, w io.Writer, progress func(done, total int64)) error {
+			return p.exportTranscript(ctx, w, spec, progress)
+		}, nil
+	case JobComplianceExport:
+		if len(spec.ChannelIDs) == 0 {
+			return nil, fmt.Errorf("job %s: at least one channel ID is required", spec.Kind)
+		}
+		return func(ctx context.Context, w io.Writer, progress func(done, total int64)) error {
+			return p.exportCompliance(ctx, w, spec, progress)
+		}, nil
+	case JobUserDataExport:
+		if spec.UserID == "" {
+			return nil, fmt.Errorf("job %s: a user ID is required", spec.Kind)
+		}
+		return func(ctx context.Context, w io.Writer, progress func(done, total int64)) error {
+			return p.exportUserData(ctx, w, spec, progress)
+		}, nil
+	case "":
+		return nil, errors.New("job: a kind or Run is required")
+	}
+	return nil, fmt.Errorf("job: unknown kind %q", spec.Kind)
+}
+
+// jobPageSize is the number of messages fetched per request by the built-in jobs
+const jobPageSize = 200
+
+// jobHistory returns a channel's messages within the spec's window, oldest first
+// Pages are fetched unfiltered so a page of system messages doesn't end the walk early.
+func (p *Platform) jobHistory(ctx context.Context, channelID string, spec JobSpec) ([]Message, error) {
+	page, err := p.getMessages(channelID, jobPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []Message
+	for len(page) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		SortMessages(page)
+		messages = append(page, messages...)
+
+		oldest := page[0]
+		if (!spec.Since.IsZero() && oldest.CreatedAt.Before(spec.Since)) || len(page) < jobPageSize {
+			break
+		}
+
+		page, err = p.getMessagesBefore(channelID, oldest.ID, jobPageSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	inWindow := messages[:0]
+	for _, msg := range p.filterMessages(messages) {
+		if !spec.Since.IsZero() && msg.CreatedAt.Before(spec.Since) {
+			continue
+		}
+		if !spec.Until.IsZero() && !msg.CreatedAt.Before(spec.Until) {
+			continue
+		}
+		inWindow = append(inWindow, msg)
+	}
+	return inWindow, nil
+}
+
+func (p *Platform) exportTranscript(ctx context.Context, w io.Writer, spec JobSpec, progress func(done, total int64)) error {
+	channelID := spec.ChannelIDs[0]
+	messages, err := p.jobHistory(ctx, channelID, spec)
+	if err != nil {
+		return err
+	}
+
+	names := make(map[string]string)
+	total := int64(len(messages))
+	for i, msg := range messages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		name, ok := names[msg.SenderID]
+		if !ok {
+			name = msg.SenderID
+			if user, err := p.GetUser(msg.SenderID); err == nil && user.Username != "" {
+				name = user.Username
+			}
+			names[msg.SenderID] = name
+		}
+		if _, err := fmt.Fprintf(w, "[%s] %s: %s\n", msg.CreatedAt.UTC().Format("2006-01-02 15:04:05"), name, msg.Text); err != nil {
+			return err
+		}
+		progress(int64(i+1), total)
+	}
+	return nil
+}
+
+func (p *Platform) exportCompliance(ctx context.Context, w io.Writer, spec JobSpec, progress func(done, total int64)) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"created_at", "channel_id", "message_id", "sender_id", "root_id", "edited_at", "text"}); err != nil {
+		return err
+	}
+
+	total := int64(len(spec.ChannelIDs))
+	for i, channelID := range spec.ChannelIDs {
+		messages, err := p.jobHistory(ctx, channelID, spec)
+		if err != nil {
+			return fmt.Errorf("channel %s: %w", channelID, err)
+		}
+		for _, msg := range messages {
+			var editedAt string
+			if msg.EditedAt != nil {
+				editedAt = msg.EditedAt.UTC().Format(time.RFC3339)
+			}
+			record := []string{msg.CreatedAt.UTC().Format(time.RFC3339), msg.ChannelID, msg.ID, msg.SenderID, msg.RootID, editedAt, msg.Text}
+			if err := out.Write(record); err != nil {
+				return err
+			}
+		}
+		out.Flush()
+		if err := out.Error(); err != nil {
+			return err
+		}
+		progress(int64(i+1), total)
+	}
+	return nil
+}
+
+// UserDataExport is the document written by JobUserDataExport
+type UserDataExport struct {
+	ExportedAt  time.Time                  `json:"exported_at"`
+	User        *User                      `json:"user"`
+	Profile     map[string]json.RawMessage `json:"profile,omitempty"` // platform-specific profile fields
+	Preferences []UserPreference           `json:"preferences"`
+	Messages    []Message                  `json:"messages"`
+}
+
+func (p *Platform) exportUserData(ctx context.Context, w io.Writer, spec JobSpec, progress func(done, total int64)) error {
+	// The profile and preferences count as one step, then one per channel
+	total := int64(1 + len(spec.ChannelIDs))
+
+	user, err := p.GetUser(spec.UserID)
+	if err != nil {
+		return err
+	}
+	prefs, err := p.GetUserPreferences(spec.UserID)
+	if err != nil {
+		return err
+	}
+	export := UserDataExport{
+		ExportedAt:  time.Now().UTC(),
+		User:        user,
+		Profile:     user.Extras,
+		Preferences: prefs,
+		Messages:    []Message{},
+	}
+	progress(1, total)
+
+	for i, channelID := range spec.ChannelIDs {
+		messages, err := p.jobHistory(ctx, channelID, spec)
+		if err != nil {
+			return fmt.Errorf("channel %s: %w", channelID, err)
+		}
+		for _, msg := range messages {
+			if msg.SenderID == spec.UserID {
+				export.Messages = append(export.Messages, msg)
+			}
+		}
+		progress(int64(i+2), total)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&export)
+}
@@ -0,0 +1,303 @@
+package libcommunicator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+)
+
+// journalCompactAfter is the number of records written before the journal
+// file is truncated, once every event in it has been acknowledged
+const journalCompactAfter = 1000
+
+// journalMaxRecord bounds the size of one journal line
+const journalMaxRecord = 16 << 20
+
+// EventJournal is an append-only file of received events that survives
+// restarts, giving an EventStream at-least-once delivery
+// An event is written before it is delivered and stays pending until it is
+// acknowledged with Ack. A stream using the journal first replays the events
+// left pending by a previous run, so a bot that crashed mid-event handles
+// it again; handlers should tolerate seeing an event twice.
+type EventJournal struct {
+	mu      sync.Mutex
+	path    string
+	cipher  FileCipher
+	file    *os.File
+	nextID  uint64
+	pending map[uint64]*Event
+	written int
+}
+
+// journalRecord is one line of the journal: an event, or the acknowledgement of one
+type journalRecord struct {
+	ID    uint64 `json:"id"`
+	Ack   bool   `json:"ack,omitempty"`
+	Event *Event `json:"event,omitempty"`
+}
+
+// OpenEventJournal opens the journal at path, creating it if needed
+// Acknowledged events are compacted away. Every write is synced to disk
+// before the event is delivered.
+func OpenEventJournal(path string) (*EventJournal, error) {
+	return OpenEncryptedEventJournal(path, nil)
+}
+
+// OpenEncryptedEventJournal opens a journal whose records are encrypted
+// with cipher, since events carry message content; a nil cipher writes
+// plaintext JSON
+// Plaintext records, e.g. from before encryption was enabled, fail with
+// ErrNotEncrypted unless cipher is a NewMigratingCipher.
+func OpenEncryptedEventJournal(path string, cipher FileCipher) (*EventJournal, error) {
+	j := &EventJournal{path: path, cipher: cipher, nextID: 1, pending: make(map[uint64]*Event)}
+	if err := j.load(); err != nil {
+		return nil, fmt.Errorf("event journal: %w", err)
+	}
+	if err := j.compact(); err != nil {
+		return nil, fmt.Errorf("event journal: %w", err)
+	}
+	return j, nil
+}
+
+// load reads the pending events of an existing journal
+func (j *EventJournal) load() error {
+	f, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for n := 1; ; n++ {
+		line, err := r.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if len(line) == 0 {
+			return nil
+		}
+		if len(line) > journalMaxRecord {
+			return fmt.Errorf("%s:%d: record too large", j.path, n)
+		}
+
+		record, decodeErr := j.decode(bytes.TrimSuffix(line, []byte("\n")))
+		if decodeErr != nil {
+			if err != nil {
+				// A crash can leave the last line half written, without its
+				// newline; nothing follows it
+				return nil
+			}
+			return fmt.Errorf("%s:%d: %w", j.path, n, decodeErr)
+		}
+		if record.ID >= j.nextID {
+			j.nextID = record.ID + 1
+		}
+		if record.Ack {
+			delete(j.pending, record.ID)
+		} else if record.Event != nil {
+			record.Event.JournalID = record.ID
+			j.pending[record.ID] = record.Event
+		}
+	}
+}
+
+// encode turns a record into a journal line, without the newline
+func (j *EventJournal) encode(record journalRecord) ([]byte, error) {
+	line, err := json.Marshal(record)
+	if err != nil || j.cipher == nil {
+		return line, err
+	}
+	sealed, err := j.cipher.Encrypt(line)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+// decode reads a record from a journal line
+func (j *EventJournal) decode(line []byte) (record journalRecord, err error) {
+	if j.cipher != nil {
+		sealed := line
+		if !bytes.HasPrefix(line, []byte("{")) {
+			if sealed, err = base64.StdEncoding.DecodeString(string(line)); err != nil {
+				return record, err
+			}
+		}
+		if line, err = j.cipher.Decrypt(sealed); err != nil {
+			return record, err
+		}
+	}
+	err = json.Unmarshal(line, &record)
+	return record, err
+}
+
+// compact rewrites the journal with only the pending events and opens it for appending
+func (j *EventJournal) compact() error {
+	tmp := j.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, event := range j.pendingLocked() {
+		line, err := j.encode(journalRecord{ID: event.JournalID, Event: event})
+		if err != nil {
+			f.Close()
+			return err
+		}
+		w.Write(append(line, '\n'))
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		return err
+	}
+
+	j.file, err = os.OpenFile(j.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	j.written = len(j.pending)
+	return err
+}
+
+// Pending returns the events not yet acknowledged, oldest first
+func (j *EventJournal) Pending() []*Event {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.pendingLocked()
+}
+
+func (j *EventJournal) pendingLocked() []*Event {
+	ids := make([]uint64, 0, len(j.pending))
+	for id := range j.pending {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	events := make([]*Event, len(ids))
+	for i, id := range ids {
+		events[i] = j.pending[id]
+	}
+	return events
+}
+
+// Append writes an event to the journal and assigns its JournalID
+func (j *EventJournal) Append(event *Event) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return errors.New("event journal: closed")
+	}
+
+	id := j.nextID
+	if err := j.write(journalRecord{ID: id, Event: event}); err != nil {
+		return err
+	}
+	j.nextID++
+	event.JournalID = id
+	j.pending[id] = event
+	return nil
+}
+
+// Ack marks an event as handled so it is not replayed
+// Events that didn't come through the journal are ignored.
+func (j *EventJournal) Ack(event *Event) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, ok := j.pending[event.JournalID]; !ok {
+		return nil
+	}
+	if j.file == nil {
+		return errors.New("event journal: closed")
+	}
+
+	if err := j.write(journalRecord{ID: event.JournalID, Ack: true}); err != nil {
+		return err
+	}
+	delete(j.pending, event.JournalID)
+
+	// Start over once everything written so far is acknowledged
+	if len(j.pending) == 0 && j.written >= journalCompactAfter {
+		if err := j.file.Truncate(0); err != nil {
+			return fmt.Errorf("event journal: %w", err)
+		}
+		j.written = 0
+	}
+	return nil
+}
+
+// write appends a record and syncs it to disk; j.mu must be held
+func (j *EventJournal) write(record journalRecord) error {
+	line, err := j.encode(record)
+	if err != nil {
+		return fmt.Errorf("event journal: %w", err)
+	}
+	info, err := j.file.Stat()
+	if err != nil {
+		return fmt.Errorf("event journal: %w", err)
+	}
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		// Cut off what was written, so the next record starts on a line of its own
+		j.file.Truncate(info.Size())
+		return fmt.Errorf("event journal: %w", err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return fmt.Errorf("event journal: %w", err)
+	}
+	j.written++
+	return nil
+}
+
+// Close closes the journal file; pending events are kept for the next open
+func (j *EventJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// journaled reports whether an event is worth replaying after a restart;
+// events about the previous connection itself are not
+func journaled(event *Event) bool {
+	switch event.Type {
+	case EventConnectionStateChange, EventGapDetected, EventRateLimited, EventResponse:
+		return false
+	}
+	return true
+}
+
+// Ack acknowledges an event received from the stream, so its journal
+// doesn't replay it after a restart; without a journal it does nothing
+// Call it once the event has been fully handled. EventRouter.Run and RunMux
+// do so once every handler, including those running in a pool, has finished.
+// After Close it returns ErrClosed.
+func (s *EventStream) Ack(event *Event) error {
+	if s.closed() {
+		return ErrClosed
+	}
+	if s.opts.Journal == nil {
+		return nil
+	}
+	return s.opts.Journal.Ack(event)
+}
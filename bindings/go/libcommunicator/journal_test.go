@@ -0,0 +1,253 @@
+package libcommunicator
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func journalEvent(text string) *Event {
+	return &Event{Type: EventMessagePosted, ChannelID: "c1", Data: map[string]interface{}{"message": text}}
+}
+
+func pendingTexts(j *EventJournal) []string {
+	var texts []string
+	for _, event := range j.Pending() {
+		texts = append(texts, event.Data.(map[string]interface{})["message"].(string))
+	}
+	return texts
+}
+
+func TestEventJournalReplaysUnacknowledged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.journal")
+	j, err := OpenEventJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := []*Event{journalEvent("one"), journalEvent("two"), journalEvent("three")}
+	for _, event := range events {
+		if err := j.Append(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := j.Ack(events[1]); err != nil {
+		t.Fatal(err)
+	}
+	// Events that didn't come through the journal are ignored
+	if err := j.Ack(&Event{JournalID: 99}); err != nil {
+		t.Fatal(err)
+	}
+	j.Close()
+
+	// A restart replays what wasn't acknowledged, in order
+	j, err = OpenEventJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	if got := pendingTexts(j); strings.Join(got, ",") != "one,three" {
+		t.Fatalf("pending after restart = %v, want [one three]", got)
+	}
+
+	// New events continue the numbering rather than reusing IDs
+	next := journalEvent("four")
+	if err := j.Append(next); err != nil {
+		t.Fatal(err)
+	}
+	if next.JournalID <= events[2].JournalID {
+		t.Fatalf("JournalID %d reused after restart (last was %d)", next.JournalID, events[2].JournalID)
+	}
+}
+
+func TestEventJournalCompacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.journal")
+	j, err := OpenEventJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	for range journalCompactAfter / 2 {
+		event := journalEvent("x")
+		if err := j.Append(event); err != nil {
+			t.Fatal(err)
+		}
+		if err := j.Ack(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 0 {
+		t.Fatalf("journal is %d bytes after everything was acknowledged, want it truncated", info.Size())
+	}
+}
+
+func TestEventJournalTornAndCorruptRecords(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.journal")
+	j, err := OpenEventJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Append(journalEvent("kept"))
+	j.Close()
+
+	// A crash mid-write leaves a last line without its newline
+	data, _ := os.ReadFile(path)
+	torn := append(bytes.Clone(data), `{"id":2,"event":{"ty`...)
+	os.WriteFile(path, torn, 0o600)
+	j, err = OpenEventJournal(path)
+	if err != nil {
+		t.Fatalf("torn last record: %v", err)
+	}
+	if got := pendingTexts(j); len(got) != 1 || got[0] != "kept" {
+		t.Fatalf("pending = %v, want [kept]", got)
+	}
+	j.Close()
+
+	// A damaged record with more following is an error, not the end of the journal
+	corrupt := append([]byte("garbage\n"), data...)
+	os.WriteFile(path, corrupt, 0o600)
+	if _, err := OpenEventJournal(path); err == nil {
+		t.Fatal("opened a journal with a corrupt record in the middle")
+	}
+}
+
+func TestEncryptedEventJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.journal")
+	c, err := NewKeyCipher(bytes.Repeat([]byte{4}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := OpenEncryptedEventJournal(path, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Append(journalEvent("top secret")); err != nil {
+		t.Fatal(err)
+	}
+	j.Close()
+
+	data, _ := os.ReadFile(path)
+	if bytes.Contains(data, []byte("top secret")) {
+		t.Fatal("journal holds message content in plaintext")
+	}
+
+	j, err = OpenEncryptedEventJournal(path, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pendingTexts(j); len(got) != 1 || got[0] != "top secret" {
+		t.Fatalf("pending = %v, want [top secret]", got)
+	}
+	j.Close()
+
+	// A plaintext journal is only read while migrating
+	plain := filepath.Join(t.TempDir(), "plain.journal")
+	pj, _ := OpenEventJournal(plain)
+	pj.Append(journalEvent("old"))
+	pj.Append(journalEvent("older"))
+	pj.Close()
+	if _, err := OpenEncryptedEventJournal(plain, c); !errors.Is(err, ErrNotEncrypted) {
+		t.Fatalf("opening a plaintext journal = %v, want ErrNotEncrypted", err)
+	}
+	mj, err := OpenEncryptedEventJournal(plain, NewMigratingCipher(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mj.Close()
+	// Opening compacts, which re-encrypts the pending events
+	data, _ = os.ReadFile(plain)
+	if bytes.Contains(data, []byte("older")) {
+		t.Fatal("migrated journal still holds plaintext")
+	}
+}
+
+func TestRouterAcksAfterPooledHandlers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.journal")
+	j, err := OpenEventJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewEventRouter()
+	release := make(chan struct{})
+	var handled sync.WaitGroup
+	handled.Add(2)
+	r.OnWithOptions(EventMessagePosted, func(*Event) {
+		<-release
+		handled.Done()
+	}, HandlerOptions{Concurrency: 2})
+	r.OnMessagePosted(func(*Event) {})
+
+	stream := &EventStream{opts: EventStreamOptions{Journal: j}}
+	acks := &acker{router: r, ack: stream.Ack}
+	for _, text := range []string{"a", "b"} {
+		event := journalEvent(text)
+		if err := j.Append(event); err != nil {
+			t.Fatal(err)
+		}
+		acks.handle(event)
+	}
+
+	// The pooled handler has only queued the events; a crash now must replay them
+	time.Sleep(10 * time.Millisecond)
+	if got := pendingTexts(j); len(got) != 2 {
+		t.Fatalf("pending while pooled handlers run = %v, want both events", got)
+	}
+	crashed, err := OpenEventJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pendingTexts(crashed); len(got) != 2 {
+		t.Fatalf("replayed after a crash = %v, want both events", got)
+	}
+	crashed.Close()
+
+	close(release)
+	handled.Wait()
+	acks.inFlight.Wait()
+	if got := pendingTexts(j); len(got) != 0 {
+		t.Fatalf("pending after the handlers finished = %v, want none", got)
+	}
+	r.Close()
+	j.Close()
+}
+
+func TestMuxAcksWithSourceStream(t *testing.T) {
+	dir := t.TempDir()
+	ja, _ := OpenEventJournal(filepath.Join(dir, "a.journal"))
+	jb, _ := OpenEventJournal(filepath.Join(dir, "b.journal"))
+	defer ja.Close()
+	defer jb.Close()
+
+	m := NewMux(1)
+	m.streams["a"] = &EventStream{opts: EventStreamOptions{Journal: ja}}
+	m.streams["b"] = &EventStream{opts: EventStreamOptions{Journal: jb}}
+
+	event := journalEvent("from b")
+	jb.Append(event)
+	event.Source = "b"
+
+	r := NewEventRouter()
+	acks := &acker{router: r, ack: m.Ack}
+	acks.handle(event)
+	acks.inFlight.Wait()
+
+	if got := pendingTexts(jb); len(got) != 0 {
+		t.Fatalf("b's journal still has %v pending", got)
+	}
+	if err := m.Ack(&Event{Source: "unknown", JournalID: 1}); err != nil {
+		t.Fatal(err)
+	}
+}
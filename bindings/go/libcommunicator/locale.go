@@ -0,0 +1,211 @@
+package libcommunicator
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Name formats, the values of the "display_settings"/"name_format" preference
+const (
+	NameFormatUsername         = "username"
+	NameFormatNicknameFullName = "nickname_full_name"
+	NameFormatFullName         = "full_name"
+)
+
+// Locale is how a user wants timestamps and names rendered
+type Locale struct {
+	// Language is the user's interface language, e.g. "en", "de", "pt-br"
+	Language string
+	// Location is the user's timezone; nil means UTC
+	Location *time.Location
+	// NameFormat is one of the NameFormat constants; empty means NameFormatUsername
+	NameFormat string
+}
+
+// localeLayout holds the date and clock layouts of a language
+type localeLayout struct {
+	date  string
+	clock string
+}
+
+// localeLayouts maps languages (and language prefixes) to their layouts
+// Month names are not localized by the time package, so only English uses them.
+var localeLayouts = map[string]localeLayout{
+	"en":    {"Jan 2, 2006", "3:04 PM"},
+	"en-au": {"2 Jan 2006", "15:04"},
+	"en-gb": {"2 Jan 2006", "15:04"},
+	"de":    {"02.01.2006", "15:04"},
+	"pl":    {"02.01.2006", "15:04"},
+	"ro":    {"02.01.2006", "15:04"},
+	"ru":    {"02.01.2006", "15:04"},
+	"tr":    {"02.01.2006", "15:04"},
+	"uk":    {"02.01.2006", "15:04"},
+	"es":    {"02/01/2006", "15:04"},
+	"fr":    {"02/01/2006", "15:04"},
+	"it":    {"02/01/2006", "15:04"},
+	"pt":    {"02/01/2006", "15:04"},
+	"nl":    {"02-01-2006", "15:04"},
+	"sv":    {"2006-01-02", "15:04"},
+	"ja":    {"2006/01/02", "15:04"},
+	"zh":    {"2006/01/02", "15:04"},
+	"ko":    {"2006. 01. 02.", "15:04"},
+}
+
+// defaultLocaleLayout is used for languages without an entry
+var defaultLocaleLayout = localeLayout{"2006-01-02", "15:04"}
+
+func (l Locale) layout() localeLayout {
+	lang := strings.ToLower(strings.ReplaceAll(l.Language, "_", "-"))
+	if layout, ok := localeLayouts[lang]; ok {
+		return layout
+	}
+	base, _, _ := strings.Cut(lang, "-")
+	if layout, ok := localeLayouts[base]; ok {
+		return layout
+	}
+	return defaultLocaleLayout
+}
+
+func (l Locale) in(t time.Time) time.Time {
+	if l.Location == nil {
+		return t.UTC()
+	}
+	return t.In(l.Location)
+}
+
+// FormatDate renders the date of t in the locale's timezone
+func (l Locale) FormatDate(t time.Time) string {
+	return l.in(t).Format(l.layout().date)
+}
+
+// FormatClock renders the time of day of t in the locale's timezone
+func (l Locale) FormatClock(t time.Time) string {
+	return l.in(t).Format(l.layout().clock)
+}
+
+// FormatTimestamp renders t as a date and time in the locale's timezone, with the zone abbreviation
+func (l Locale) FormatTimestamp(t time.Time) string {
+	layout := l.layout()
+	return l.in(t).Format(layout.date + " " + layout.clock + " MST")
+}
+
+// UserLocale reads a user's language and timezone from their profile
+// The name format is a preference rather than part of the profile; use
+// GetUserLocale to fill it in as well.
+func UserLocale(u *User) Locale {
+	var meta struct {
+		Locale   string            `json:"locale"`
+		Timezone map[string]string `json:"timezone"`
+	}
+	if raw, ok := u.Extras["metadata"]; ok {
+		_ = json.Unmarshal(raw, &meta)
+	}
+
+	locale := Locale{Language: meta.Locale}
+	zone := meta.Timezone["manualTimezone"]
+	if meta.Timezone["useAutomaticTimezone"] == "true" || zone == "" {
+		zone = meta.Timezone["automaticTimezone"]
+	}
+	if zone != "" {
+		if loc, err := time.LoadLocation(zone); err == nil {
+			locale.Location = loc
+		}
+	}
+	return locale
+}
+
+// GetUserLocale returns a user's language, timezone and name format
+func (p *Platform) GetUserLocale(userID string) (*Locale, error) {
+	user, err := p.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	locale := UserLocale(user)
+
+	prefs, err := p.GetUserPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pref := range prefs {
+		if pref.Category == "display_settings" && pref.Name == "name_format" {
+			locale.NameFormat = pref.Value
+		}
+	}
+	return &locale, nil
+}
+
+// DisplayName renders a user's name per the locale's name format
+func (l Locale) DisplayName(u *User) string {
+	var meta struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Nickname  string `json:"nickname"`
+	}
+	if raw, ok := u.Extras["metadata"]; ok {
+		_ = json.Unmarshal(raw, &meta)
+	}
+	fullName := strings.TrimSpace(meta.FirstName + " " + meta.LastName)
+
+	switch l.NameFormat {
+	case NameFormatNicknameFullName:
+		if meta.Nickname != "" {
+			return meta.Nickname
+		}
+		if fullName != "" {
+			return fullName
+		}
+	case NameFormatFullName:
+		if fullName != "" {
+			return fullName
+		}
+	}
+	return u.Username
+}
+
+// MentionResolver looks up the user behind an @mention, returning nil if there is none
+type MentionResolver func(username string) *User
+
+// NewMentionResolver returns a resolver that looks users up on the platform,
+// remembering each answer (including misses) for the resolver's lifetime
+func (p *Platform) NewMentionResolver() MentionResolver {
+	var mu sync.Mutex
+	cache := make(map[string]*User)
+	return func(username string) *User {
+		mu.Lock()
+		defer mu.Unlock()
+		if user, ok := cache[username]; ok {
+			return user
+		}
+		user, _ := p.GetUserByUsername(username)
+		cache[username] = user
+		return user
+	}
+}
+
+// mentionPattern matches @username mentions; Mattermost usernames may contain . _ and -
+var mentionPattern = regexp.MustCompile(`\B@([a-zA-Z0-9][a-zA-Z0-9._-]*)`)
+
+// FormatMentions replaces @username mentions in text with the users' display
+// names per the locale's name format
+// Special mentions (@channel, @here, @all) and unknown usernames are left as they are.
+func (l Locale) FormatMentions(text string, resolve MentionResolver) string {
+	return mentionPattern.ReplaceAllStringFunc(text, func(mention string) string {
+		username := mention[1:]
+		// A trailing dot ends the sentence, not the username
+		trimmed := strings.TrimRight(username, ".")
+		suffix := username[len(trimmed):]
+
+		switch strings.ToLower(trimmed) {
+		case "channel", "here", "all":
+			return mention
+		}
+		user := resolve(strings.ToLower(trimmed))
+		if user == nil {
+			return mention
+		}
+		return "@" + l.DisplayName(user) + suffix
+	})
+}
@@ -0,0 +1,139 @@
+package libcommunicator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func localeUser(username, metadata string) *User {
+	u := &User{Username: username}
+	if metadata != "" {
+		u.Extras = map[string]json.RawMessage{"metadata": json.RawMessage(metadata)}
+	}
+	return u
+}
+
+func TestLocaleFormat(t *testing.T) {
+	at := time.Date(2024, 3, 5, 14, 7, 0, 0, time.UTC)
+	berlin := time.FixedZone("CET", 3600)
+
+	tests := []struct {
+		locale    Locale
+		date      string
+		clock     string
+		timestamp string
+	}{
+		{Locale{Language: "en"}, "Mar 5, 2024", "2:07 PM", "Mar 5, 2024 2:07 PM UTC"},
+		{Locale{Language: "en_GB"}, "5 Mar 2024", "14:07", "5 Mar 2024 14:07 UTC"},
+		{Locale{Language: "en-US"}, "Mar 5, 2024", "2:07 PM", "Mar 5, 2024 2:07 PM UTC"},
+		{Locale{Language: "de", Location: berlin}, "05.03.2024", "15:07", "05.03.2024 15:07 CET"},
+		{Locale{Language: "pt-BR"}, "05/03/2024", "14:07", "05/03/2024 14:07 UTC"},
+		{Locale{Language: "ko"}, "2024. 03. 05.", "14:07", "2024. 03. 05. 14:07 UTC"},
+		{Locale{Language: "xx"}, "2024-03-05", "14:07", "2024-03-05 14:07 UTC"},
+		{Locale{}, "2024-03-05", "14:07", "2024-03-05 14:07 UTC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale.Language, func(t *testing.T) {
+			if got := tt.locale.FormatDate(at); got != tt.date {
+				t.Errorf("FormatDate = %q, want %q", got, tt.date)
+			}
+			if got := tt.locale.FormatClock(at); got != tt.clock {
+				t.Errorf("FormatClock = %q, want %q", got, tt.clock)
+			}
+			if got := tt.locale.FormatTimestamp(at); got != tt.timestamp {
+				t.Errorf("FormatTimestamp = %q, want %q", got, tt.timestamp)
+			}
+		})
+	}
+}
+
+func TestUserLocale(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string
+		language string
+		zone     string
+	}{
+		{"no metadata", "", "", ""},
+		{"manual timezone", `{"locale":"de","timezone":{"useAutomaticTimezone":"false","manualTimezone":"Europe/Berlin","automaticTimezone":"Asia/Tokyo"}}`, "de", "Europe/Berlin"},
+		{"automatic timezone", `{"locale":"ja","timezone":{"useAutomaticTimezone":"true","manualTimezone":"Europe/Berlin","automaticTimezone":"Asia/Tokyo"}}`, "ja", "Asia/Tokyo"},
+		{"no manual timezone", `{"timezone":{"useAutomaticTimezone":"false","automaticTimezone":"Asia/Tokyo"}}`, "", "Asia/Tokyo"},
+		{"unknown timezone", `{"locale":"en","timezone":{"manualTimezone":"Mars/Olympus"}}`, "en", ""},
+		{"malformed metadata", `"text"`, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locale := UserLocale(localeUser("alice", tt.metadata))
+			zone := ""
+			if locale.Location != nil {
+				zone = locale.Location.String()
+			}
+			if locale.Language != tt.language || zone != tt.zone {
+				t.Fatalf("locale = %q in %q, want %q in %q", locale.Language, zone, tt.language, tt.zone)
+			}
+		})
+	}
+}
+
+func TestLocaleDisplayName(t *testing.T) {
+	full := localeUser("alice", `{"first_name":"Alice","last_name":"Liddell","nickname":"Al"}`)
+	noNickname := localeUser("alice", `{"first_name":"Alice"}`)
+	bare := localeUser("alice", "")
+
+	tests := []struct {
+		format string
+		user   *User
+		want   string
+	}{
+		{"", full, "alice"},
+		{NameFormatUsername, full, "alice"},
+		{NameFormatFullName, full, "Alice Liddell"},
+		{NameFormatFullName, noNickname, "Alice"},
+		{NameFormatFullName, bare, "alice"},
+		{NameFormatNicknameFullName, full, "Al"},
+		{NameFormatNicknameFullName, noNickname, "Alice"},
+		{NameFormatNicknameFullName, bare, "alice"},
+	}
+	for _, tt := range tests {
+		if got := (Locale{NameFormat: tt.format}).DisplayName(tt.user); got != tt.want {
+			t.Errorf("DisplayName(%s) with format %q = %q, want %q", tt.user.Extras["metadata"], tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestLocaleFormatMentions(t *testing.T) {
+	users := map[string]*User{
+		"alice":     localeUser("alice", `{"first_name":"Alice","last_name":"Liddell"}`),
+		"bob.smith": localeUser("bob.smith", `{"first_name":"Bob"}`),
+	}
+	var lookups []string
+	resolve := func(username string) *User {
+		lookups = append(lookups, username)
+		return users[username]
+	}
+	locale := Locale{NameFormat: NameFormatFullName}
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"hi @alice", "hi @Alice Liddell"},
+		{"thanks @Alice.", "thanks @Alice Liddell."},
+		{"@bob.smith and @alice", "@Bob and @Alice Liddell"},
+		{"@channel @here @ALL", "@channel @here @ALL"},
+		{"@nobody home", "@nobody home"},
+		{"mail alice@example.com", "mail alice@example.com"},
+	}
+	for _, tt := range tests {
+		if got := locale.FormatMentions(tt.text, resolve); got != tt.want {
+			t.Errorf("FormatMentions(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+	for _, username := range lookups {
+		if username == "channel" || username == "here" || username == "all" {
+			t.Errorf("special mention @%s was looked up", username)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package libcommunicator
+
+/*
+#include <communicator.h>
+#include <stdint.h>
+#include <stdlib.h>
+
+// Defined in Go below; a file with //export may only declare C functions
+extern void goLogCallback(CommunicatorLogLevel level, char* message, void* user_data);
+*/
+import "C"
+import (
+	"sync"
+	"unsafe"
+)
+
+// logCallbacks maps the IDs handed to the library as user data to the Go
+// callbacks they stand for; Go pointers can't be kept by C code
+var (
+	logCallbacksMu sync.RWMutex
+	logCallbacks   = make(map[uint64]LogCallback)
+	nextLogID      uint64
+)
+
+func registerLogCallback(callback LogCallback) uint64 {
+	logCallbacksMu.Lock()
+	defer logCallbacksMu.Unlock()
+	nextLogID++
+	logCallbacks[nextLogID] = callback
+	return nextLogID
+}
+
+func unregisterLogCallback(id uint64) {
+	logCallbacksMu.Lock()
+	defer logCallbacksMu.Unlock()
+	delete(logCallbacks, id)
+}
+
+func lookupLogCallback(id uint64) LogCallback {
+	logCallbacksMu.RLock()
+	defer logCallbacksMu.RUnlock()
+	return logCallbacks[id]
+}
+
+// goLogCallback is the C trampoline passed to the library; user_data points
+// to the C-allocated ID of the Go callback
+//
+//export goLogCallback
+func goLogCallback(level C.CommunicatorLogLevel, message *C.char, userData unsafe.Pointer) {
+	if userData == nil {
+		return
+	}
+	callback := lookupLogCallback(uint64(*(*C.uint64_t)(userData)))
+	if callback == nil {
+		return
+	}
+	// Calls into Go from C stay on the calling thread, which marks the
+	// callback for the handle guards
+	thread := currentThread()
+	enterCallback(thread)
+	defer leaveCallback(thread)
+
+	// A panic must not unwind into the library's thread
+	defer func() { _ = recover() }()
+	callback(LogLevel(level), C.GoString(message))
+}
+
+// SetLogCallback sets a callback function to receive log messages
+// Besides the context's own messages, the callback receives the library's
+// connection, WebSocket, retry and platform diagnostics, which go to the
+// callback of every context that has one. It may be called from the
+// library's threads, concurrently with other Go code; it must not call back
+// into the context, and should hand platform calls to another goroutine.
+// Calls that need the platform or the context to themselves, such as
+// Disconnect or SetLogCallback, panic if made from the callback; the panic is
+// recovered when the callback returns, so the call simply doesn't happen. A
+// nil callback clears the current one.
+func (c *Context) SetLogCallback(callback LogCallback) error {
+	if callback == nil {
+		return c.ClearLogCallback()
+	}
+	// Run alone: the slot of the previous callback is released here
+	if !c.guard.hold(true, func() bool { return c.handle != nil }) {
+		return ErrInvalidContext
+	}
+	defer c.guard.drop(true)
+
+	id := registerLogCallback(callback)
+	slot := (*C.uint64_t)(C.malloc(C.sizeof_uint64_t))
+	*slot = C.uint64_t(id)
+
+	trampoline := C.CommunicatorLogCallback(C.goLogCallback)
+	code := C.communicator_context_set_log_callback(c.handle, trampoline, unsafe.Pointer(slot))
+	if code != C.COMMUNICATOR_SUCCESS {
+		unregisterLogCallback(id)
+		C.free(unsafe.Pointer(slot))
+		return getLastError()
+	}
+
+	c.releaseLogCallback()
+	c.logSlot = slot
+	return nil
+}
+
+// ClearLogCallback clears any previously set log callback
+func (c *Context) ClearLogCallback() error {
+	if !c.guard.hold(true, func() bool { return c.handle != nil }) {
+		return ErrInvalidContext
+	}
+	defer c.guard.drop(true)
+
+	code := C.communicator_context_clear_log_callback(c.handle)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	c.releaseLogCallback()
+	return nil
+}
+
+// releaseLogCallback forgets the callback set last, once the library no longer refers to it
+func (c *Context) releaseLogCallback() {
+	if c.logSlot == nil {
+		return
+	}
+	unregisterLogCallback(uint64(*c.logSlot))
+	C.free(unsafe.Pointer(c.logSlot))
+	c.logSlot = nil
+}
@@ -0,0 +1,221 @@
+package libcommunicator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// SourceError is an error from one of the event streams merged by a Mux
+type SourceError struct {
+	Source string
+	Err    error
+}
+
+func (e *SourceError) Error() string {
+	return e.Source + ": " + e.Err.Error()
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// Mux merges the event streams of several platforms into one, for bridge
+// bots connected to more than one server
+// Each event is forwarded as a copy whose Source is the name its stream
+// was added under; the stream's own event, which History may share, is left
+// as it was.
+type Mux struct {
+	events chan *Event
+	errors chan error
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	streams map[string]*EventStream
+	closed  bool
+}
+
+// NewMux creates an empty multiplexer whose Events channel buffers bufferSize events
+func NewMux(bufferSize int) *Mux {
+	return &Mux{
+		events:  make(chan *Event, bufferSize),
+		errors:  make(chan error, 10),
+		done:    make(chan struct{}),
+		streams: make(map[string]*EventStream),
+	}
+}
+
+// Add merges a stream into the multiplexer under a unique name
+// The Mux takes ownership of the stream and closes it on Close.
+func (m *Mux) Add(name string, stream *EventStream) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return errors.New("mux: closed")
+	}
+	if _, ok := m.streams[name]; ok {
+		return fmt.Errorf("mux: source %q already added", name)
+	}
+	m.streams[name] = stream
+
+	m.wg.Add(1)
+	go m.forward(name, stream)
+	return nil
+}
+
+// Sources returns the names of the merged streams, sorted
+func (m *Mux) Sources() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.streams))
+	for name := range m.streams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Stream returns the stream added under name, or nil
+func (m *Mux) Stream(name string) *EventStream {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.streams[name]
+}
+
+// Events returns the merged events of every stream
+func (m *Mux) Events() <-chan *Event {
+	return m.events
+}
+
+// Errors returns the streams' errors, each wrapped in a *SourceError
+// Errors are dropped when the channel is full.
+func (m *Mux) Errors() <-chan error {
+	return m.errors
+}
+
+// Ack acknowledges an event received from the Mux with the stream it came
+// from, so that stream's journal doesn't replay it; see EventStream.Ack
+func (m *Mux) Ack(event *Event) error {
+	stream := m.Stream(event.Source)
+	if stream == nil {
+		return nil
+	}
+	return stream.Ack(event)
+}
+
+// Close closes every stream and then the Events and Errors channels
+func (m *Mux) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	close(m.done)
+	streams := m.streams
+	m.mu.Unlock()
+
+	var errs []error
+	for name, stream := range streams {
+		if err := stream.Close(); err != nil {
+			errs = append(errs, &SourceError{Source: name, Err: err})
+		}
+	}
+	m.wg.Wait()
+	close(m.events)
+	close(m.errors)
+	return errors.Join(errs...)
+}
+
+// forward copies one stream's events and errors until the stream or the Mux closes
+func (m *Mux) forward(name string, stream *EventStream) {
+	defer m.wg.Done()
+
+	send := func(event *Event) bool {
+		tagged := *event
+		tagged.Source = name
+		select {
+		case m.events <- &tagged:
+			return true
+		case <-m.done:
+			return false
+		}
+	}
+
+	events, priority, errs := stream.Events(), stream.Priority(), stream.Errors()
+	for events != nil || priority != nil || errs != nil {
+		// Forward the priority lane ahead of the regular events
+		select {
+		case event, ok := <-priority:
+			if !ok {
+				priority = nil
+			} else if !send(event) {
+				return
+			}
+			continue
+		default:
+		}
+
+		select {
+		case event, ok := <-priority:
+			if !ok {
+				priority = nil
+				continue
+			}
+			if !send(event) {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if !send(event) {
+				return
+			}
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			select {
+			case m.errors <- &SourceError{Source: name, Err: err}:
+			default:
+			}
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// RunMux dispatches the events of a Mux until the context is cancelled
+// Check Event.Source in handlers to tell the platforms apart. Events are
+// acknowledged as in Run, and the Mux is closed on return.
+func (r *EventRouter) RunMux(ctx context.Context, mux *Mux) error {
+	acks := &acker{router: r, ack: mux.Ack}
+	defer mux.Close()
+	defer acks.inFlight.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-mux.Events():
+			if !ok {
+				return nil
+			}
+			acks.handle(event)
+		case err, ok := <-mux.Errors():
+			if !ok {
+				return nil
+			}
+			r.reportError(err)
+		}
+	}
+}
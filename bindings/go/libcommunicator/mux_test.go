@@ -0,0 +1,130 @@
+package libcommunicator
+
+import (
+	"errors"
+	"testing"
+)
+
+func newMuxStream() *EventStream {
+	return &EventStream{
+		platform: &Platform{},
+		events:   make(chan *Event, 10),
+		errors:   make(chan error, 10),
+		done:     make(chan struct{}),
+		lane:     newPriorityLane(10),
+	}
+}
+
+func TestMuxTagsCopies(t *testing.T) {
+	m := NewMux(10)
+	a, b := newMuxStream(), newMuxStream()
+	if err := m.Add("a", a); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add("b", b); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same event read from two streams keeps its own Source
+	shared := &Event{Type: EventMessagePosted, Seq: 1}
+	a.events <- shared
+	b.events <- shared
+
+	got := map[string]*Event{}
+	for range 2 {
+		event := <-m.Events()
+		got[event.Source] = event
+	}
+	if got["a"] == nil || got["b"] == nil {
+		t.Fatalf("events from %v, want a and b", got)
+	}
+	if got["a"] == shared || got["b"] == shared || shared.Source != "" {
+		t.Fatalf("the stream's event was modified: Source = %q", shared.Source)
+	}
+	if got["a"].Seq != 1 || got["b"].Type != EventMessagePosted {
+		t.Fatalf("forwarded events = %+v, %+v", got["a"], got["b"])
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMuxForwardsPriorityFirst(t *testing.T) {
+	m := NewMux(10)
+	s := newMuxStream()
+	for n := 1; n <= 3; n++ {
+		s.events <- numberedEvent(n)
+	}
+	s.lane.events <- numberedEvent(9)
+	if err := m.Add("a", s); err != nil {
+		t.Fatal(err)
+	}
+
+	var seqs []int64
+	for range 4 {
+		seqs = append(seqs, (<-m.Events()).Seq)
+	}
+	if seqs[0] != 9 {
+		t.Fatalf("forwarded %v, want the priority event first", seqs)
+	}
+	m.Close()
+}
+
+func TestMuxWrapsErrors(t *testing.T) {
+	m := NewMux(10)
+	s := newMuxStream()
+	if err := m.Add("a", s); err != nil {
+		t.Fatal(err)
+	}
+	s.errors <- ErrClosed
+
+	err := <-m.Errors()
+	var sourceErr *SourceError
+	if !errors.As(err, &sourceErr) || sourceErr.Source != "a" || !errors.Is(err, ErrClosed) {
+		t.Fatalf("error = %v, want ErrClosed from a", err)
+	}
+	m.Close()
+}
+
+func TestMuxAdd(t *testing.T) {
+	m := NewMux(1)
+	tests := []struct {
+		name    string
+		source  string
+		close   bool
+		wantErr string
+	}{
+		{name: "first", source: "b"},
+		{name: "second", source: "a"},
+		{name: "duplicate", source: "a", wantErr: `mux: source "a" already added`},
+		{name: "after close", source: "c", close: true, wantErr: "mux: closed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.close {
+				m.Close()
+			}
+			err := m.Add(tt.source, newMuxStream())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if got := m.Sources(); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Fatalf("sources = %v, want [a b]", got)
+	}
+	if m.Stream("c") != nil {
+		t.Fatal("a stream was added after Close")
+	}
+	if _, ok := <-m.Events(); ok {
+		t.Fatal("events channel still open after Close")
+	}
+}
@@ -0,0 +1,320 @@
+package libcommunicator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// OnboardingRecord tracks how far a user has progressed through onboarding
+type OnboardingRecord struct {
+	UserID         string     `json:"user_id"`
+	WelcomeSent    bool       `json:"welcome_sent"`
+	JoinedChannels []string   `json:"joined_channels,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+}
+
+func (r *OnboardingRecord) hasJoined(channelID string) bool {
+	for _, id := range r.JoinedChannels {
+		if id == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+// OnboardingStore persists onboarding progress
+// Load returns nil, nil for users that have not been seen
+type OnboardingStore interface {
+	Load(userID string) (*OnboardingRecord, error)
+	Save(record *OnboardingRecord) error
+}
+
+// MemoryOnboardingStore keeps onboarding progress in memory
+type MemoryOnboardingStore struct {
+	mu      sync.Mutex
+	records map[string]OnboardingRecord
+}
+
+// NewMemoryOnboardingStore creates an empty in-memory store
+func NewMemoryOnboardingStore() *MemoryOnboardingStore {
+	return &MemoryOnboardingStore{records: make(map[string]OnboardingRecord)}
+}
+
+// Load returns the stored record for a user
+func (s *MemoryOnboardingStore) Load(userID string) (*OnboardingRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[userID]
+	if !ok {
+		return nil, nil
+	}
+	record.JoinedChannels = append([]string(nil), record.JoinedChannels...)
+	return &record, nil
+}
+
+// Save stores a record
+func (s *MemoryOnboardingStore) Save(record *OnboardingRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *record
+	stored.JoinedChannels = append([]string(nil), record.JoinedChannels...)
+	s.records[record.UserID] = stored
+	return nil
+}
+
+// FileOnboardingStore keeps onboarding progress in a JSON file
+type FileOnboardingStore struct {
+	MemoryOnboardingStore
+	path    string
+	cipher  FileCipher
+	writeMu sync.Mutex // serializes file writes so a stale snapshot never replaces a newer one
+}
+
+// NewFileOnboardingStore opens (or creates on first save) a JSON file store
+func NewFileOnboardingStore(path string) (*FileOnboardingStore, error) {
+	return NewEncryptedFileOnboardingStore(path, nil)
+}
+
+// NewEncryptedFileOnboardingStore opens a file store encrypted with cipher
+// A nil cipher stores plaintext JSON
+func NewEncryptedFileOnboardingStore(path string, cipher FileCipher) (*FileOnboardingStore, error) {
+	store := &FileOnboardingStore{
+		MemoryOnboardingStore: MemoryOnboardingStore{records: make(map[string]OnboardingRecord)},
+		path:                  path,
+		cipher:                cipher,
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cipher != nil {
+		if data, err = cipher.Decrypt(data); err != nil {
+			return nil, fmt.Errorf("onboarding store %s: %w", path, err)
+		}
+	}
+	if err := json.Unmarshal(data, &store.records); err != nil {
+		return nil, fmt.Errorf("onboarding store %s: %w", path, err)
+	}
+
+	return store, nil
+}
+
+// Save stores a record and writes the file atomically
+func (s *FileOnboardingStore) Save(record *OnboardingRecord) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if err := s.MemoryOnboardingStore.Save(record); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s.records, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if s.cipher != nil {
+		if data, err = s.cipher.Encrypt(data); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// OnboardingOptions configures an Onboarding helper
+type OnboardingOptions struct {
+	// WelcomeTemplate is a text/template for the welcome DM. It is executed
+	// with an OnboardingData value, e.g. "Welcome, {{.DisplayName}}!"
+	WelcomeTemplate string
+	// DefaultChannels are channels new users are added to
+	DefaultChannels []string
+	// Store persists progress; an in-memory store is used when nil
+	Store OnboardingStore
+	// IncludeBots onboards bot accounts too
+	IncludeBots bool
+}
+
+// OnboardingData is the data available to the welcome template
+type OnboardingData struct {
+	UserID      string
+	Username    string
+	DisplayName string
+	TeamID      string
+}
+
+// Onboarding welcomes new users with a DM and adds them to default channels
+// Progress is recorded per step, so an interrupted onboarding resumes where it stopped
+type Onboarding struct {
+	platform *Platform
+	opts     OnboardingOptions
+	welcome  *template.Template
+
+	// OnError, if set, receives onboarding failures
+	OnError func(error)
+
+	mu       sync.Mutex
+	inflight map[string]bool
+}
+
+// NewOnboarding creates an onboarding helper
+func NewOnboarding(p *Platform, opts OnboardingOptions) (*Onboarding, error) {
+	if opts.WelcomeTemplate == "" {
+		opts.WelcomeTemplate = "Welcome, {{.DisplayName}}!"
+	}
+	welcome, err := template.New("welcome").Parse(opts.WelcomeTemplate)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Store == nil {
+		opts.Store = NewMemoryOnboardingStore()
+	}
+
+	return &Onboarding{
+		platform: p,
+		opts:     opts,
+		welcome:  welcome,
+		inflight: make(map[string]bool),
+	}, nil
+}
+
+// Register installs handlers for new-user and added-to-team events on the router
+func (o *Onboarding) Register(r *EventRouter) {
+	handler := func(event *Event) {
+		if event.UserID == "" {
+			return
+		}
+		// Onboarding makes several API calls; keep the router responsive
+		go func() {
+			if err := o.Onboard(event.UserID, event.TeamID); err != nil {
+				o.reportError(err)
+			}
+		}()
+	}
+	r.On(EventUserAdded, handler)
+	r.On(EventAddedToTeam, handler)
+}
+
+// Onboard runs any onboarding steps the user has not completed yet
+// Calling it again for a fully onboarded user is a no-op
+func (o *Onboarding) Onboard(userID, teamID string) error {
+	// A user added to a team usually produces more than one event
+	o.mu.Lock()
+	if o.inflight[userID] {
+		o.mu.Unlock()
+		return nil
+	}
+	o.inflight[userID] = true
+	o.mu.Unlock()
+
+	defer func() {
+		o.mu.Lock()
+		delete(o.inflight, userID)
+		o.mu.Unlock()
+	}()
+
+	record, err := o.opts.Store.Load(userID)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		record = &OnboardingRecord{UserID: userID}
+	}
+	if record.CompletedAt != nil {
+		return nil
+	}
+
+	user, err := o.platform.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	if user.IsBot && !o.opts.IncludeBots {
+		return nil
+	}
+
+	if !record.WelcomeSent {
+		if err := o.sendWelcome(user, teamID); err != nil {
+			return err
+		}
+		record.WelcomeSent = true
+		if err := o.opts.Store.Save(record); err != nil {
+			return err
+		}
+	}
+
+	var failed []error
+	for _, channelID := range o.opts.DefaultChannels {
+		if record.hasJoined(channelID) {
+			continue
+		}
+		if err := o.platform.AddChannelMember(channelID, userID); err != nil {
+			// Keep going so one bad channel doesn't block the rest
+			failed = append(failed, fmt.Errorf("onboarding %s: add to channel %s: %w", userID, channelID, err))
+			continue
+		}
+		record.JoinedChannels = append(record.JoinedChannels, channelID)
+		if err := o.opts.Store.Save(record); err != nil {
+			return err
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Join(failed...)
+	}
+
+	now := time.Now().UTC()
+	record.CompletedAt = &now
+	return o.opts.Store.Save(record)
+}
+
+func (o *Onboarding) sendWelcome(user *User, teamID string) error {
+	displayName := user.DisplayName
+	if displayName == "" {
+		displayName = user.Username
+	}
+
+	var text strings.Builder
+	data := OnboardingData{UserID: user.ID, Username: user.Username, DisplayName: displayName, TeamID: teamID}
+	if err := o.welcome.Execute(&text, data); err != nil {
+		return err
+	}
+
+	dm, err := o.platform.CreateDirectChannel(user.ID)
+	if err != nil {
+		return err
+	}
+	_, err = o.platform.SendMessage(dm.ID, text.String())
+	return err
+}
+
+func (o *Onboarding) reportError(err error) {
+	if o.OnError != nil {
+		o.OnError(err)
+	}
+}
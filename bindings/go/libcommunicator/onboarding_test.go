@@ -0,0 +1,170 @@
+package libcommunicator
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOnboardingStores(t *testing.T) {
+	dir := t.TempDir()
+	key, err := NewKeyCipher(bytes.Repeat([]byte{3}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		open   func() (OnboardingStore, error)
+		secret bool
+	}{
+		{"memory", func() (OnboardingStore, error) { return NewMemoryOnboardingStore(), nil }, false},
+		{"file", func() (OnboardingStore, error) {
+			return NewFileOnboardingStore(filepath.Join(dir, "onboarding.json"))
+		}, false},
+		{"encrypted file", func() (OnboardingStore, error) {
+			return NewEncryptedFileOnboardingStore(filepath.Join(dir, "onboarding.enc"), key)
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := tt.open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if record, err := store.Load("u1"); record != nil || err != nil {
+				t.Fatalf("Load of an unseen user = %+v, %v", record, err)
+			}
+
+			done := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			record := &OnboardingRecord{UserID: "u1", WelcomeSent: true, JoinedChannels: []string{"c1"}, CompletedAt: &done}
+			if err := store.Save(record); err != nil {
+				t.Fatal(err)
+			}
+			// The store keeps its own copy
+			record.JoinedChannels[0] = "changed"
+
+			if _, ok := store.(*FileOnboardingStore); ok {
+				if store, err = tt.open(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := store.Load("u1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := &OnboardingRecord{UserID: "u1", WelcomeSent: true, JoinedChannels: []string{"c1"}, CompletedAt: &done}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("Load = %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "onboarding.enc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("u1")) {
+		t.Fatal("encrypted store holds plaintext")
+	}
+	if _, err := NewFileOnboardingStore(filepath.Join(dir, "onboarding.enc")); err == nil {
+		t.Fatal("opened an encrypted store without its key")
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(dir, "*.tmp-*"))
+	if len(leftovers) != 0 {
+		t.Fatalf("temporary files left behind: %v", leftovers)
+	}
+}
+
+func TestNewOnboardingTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		wantErr  bool
+	}{
+		{"", false},
+		{"Hi {{.Username}} of {{.TeamID}}", false},
+		{"Hi {{.Username", true},
+	}
+	for _, tt := range tests {
+		o, err := NewOnboarding(&Platform{}, OnboardingOptions{WelcomeTemplate: tt.template})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewOnboarding(%q) error = %v, want error %v", tt.template, err, tt.wantErr)
+		}
+		if err == nil && o.opts.Store == nil {
+			t.Errorf("NewOnboarding(%q) has no store", tt.template)
+		}
+	}
+}
+
+func TestOnboardSkipsWork(t *testing.T) {
+	done := time.Now()
+	store := NewMemoryOnboardingStore()
+	store.Save(&OnboardingRecord{UserID: "done", WelcomeSent: true, CompletedAt: &done})
+	o, err := NewOnboarding(&Platform{}, OnboardingOptions{Store: store, DefaultChannels: []string{"c1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.inflight["busy"] = true
+
+	tests := []struct {
+		userID  string
+		wantErr error
+	}{
+		// A completed user makes no platform calls, which would fail here
+		{"done", nil},
+		// Neither does a user another event is already onboarding
+		{"busy", nil},
+		{"new", ErrClosed},
+	}
+	for _, tt := range tests {
+		if err := o.Onboard(tt.userID, "team"); !errors.Is(err, tt.wantErr) {
+			t.Errorf("Onboard(%s) = %v, want %v", tt.userID, err, tt.wantErr)
+		}
+	}
+	if !o.inflight["busy"] || o.inflight["new"] {
+		t.Fatalf("in flight after onboarding = %v, want only the busy user", o.inflight)
+	}
+	if record, _ := store.Load("new"); record != nil {
+		t.Fatalf("a failed onboarding saved %+v", record)
+	}
+}
+
+func TestOnboardingRegisterReportsErrors(t *testing.T) {
+	o, err := NewOnboarding(&Platform{}, OnboardingOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := make(chan error, 2)
+	o.OnError = func(err error) { errs <- err }
+
+	r := NewEventRouter()
+	o.Register(r)
+	r.Handle(&Event{Type: EventUserAdded, TeamID: "team"})
+	r.Handle(&Event{Type: EventAddedToTeam, UserID: "u1", TeamID: "team"})
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrClosed) {
+			t.Fatalf("reported %v, want ErrClosed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the failed onboarding was not reported")
+	}
+	r.Close()
+	if len(errs) != 0 {
+		t.Fatalf("an event without a user was onboarded: %v", <-errs)
+	}
+}
+
+func TestOnboardingRecordHasJoined(t *testing.T) {
+	record := &OnboardingRecord{JoinedChannels: []string{"c1", "c2"}}
+	for channelID, want := range map[string]bool{"c1": true, "c2": true, "c3": false, "": false} {
+		if got := record.hasJoined(channelID); got != want {
+			t.Errorf("hasJoined(%q) = %v, want %v", channelID, got, want)
+		}
+	}
+}
@@ -7,13 +7,35 @@ package libcommunicator
 import "C"
 import (
 	"encoding/json"
+	"iter"
 	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
 // Platform represents a chat platform (Mattermost, Slack, etc.)
 type Platform struct {
 	handle C.CommunicatorPlatform
+	guard  handleGuard
+
+	scanMu     sync.RWMutex
+	scanner    FileScanner
+	scanPolicy ScanPolicy
+
+	readOnly      atomic.Bool
+	excludeSystem atomic.Bool
+	decodeMode    atomic.Int32
+	auditHook     atomic.Pointer[AuditHook]
+
+	linksMu     sync.Mutex
+	publicLinks map[string]PublicLink // by file ID
+
+	responsesMu        sync.Mutex
+	responseWaiters    map[int64]chan *Event // by request sequence number
+	unclaimedResponses map[int64]*Event
 }
 
 // NewMattermostPlatform creates a new Mattermost platform instance
@@ -21,6 +43,7 @@ func NewMattermostPlatform(serverURL string) (*Platform, error) {
 	if err := ensureInitialized(); err != nil {
 		return nil, err
 	}
+	defer pinThread()()
 
 	cs, free := cStringFree(serverURL)
 	defer free()
@@ -33,18 +56,17 @@ func NewMattermostPlatform(serverURL string) (*Platform, error) {
 	p := &Platform{handle: handle}
 
 	// Set up finalizer to ensure cleanup
-	runtime.SetFinalizer(p, func(p *Platform) {
-		p.Destroy()
-	})
+	runtime.SetFinalizer(p, (*Platform).Close)
 
 	return p, nil
 }
 
 // Connect connects to the platform and authenticates
 func (p *Platform) Connect(config *PlatformConfig) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+	if !p.acquireExclusive() {
+		return ErrClosed
 	}
+	defer p.releaseExclusive()
 
 	// Marshal config to JSON
 	jsonBytes, err := json.Marshal(config)
@@ -79,9 +101,10 @@ func (p *Platform) Connect(config *PlatformConfig) error {
 //	}
 //	err := platform.ConnectWithMFA(config)
 func (p *Platform) ConnectWithMFA(config *PlatformConfig) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+	if !p.acquireExclusive() {
+		return ErrClosed
 	}
+	defer p.releaseExclusive()
 
 	// Marshal config to JSON
 	jsonBytes, err := json.Marshal(config)
@@ -102,9 +125,10 @@ func (p *Platform) ConnectWithMFA(config *PlatformConfig) error {
 
 // Disconnect disconnects from the platform
 func (p *Platform) Disconnect() error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+	if !p.acquireExclusive() {
+		return ErrClosed
 	}
+	defer p.releaseExclusive()
 
 	code := C.communicator_platform_disconnect(p.handle)
 	if code != C.COMMUNICATOR_SUCCESS {
@@ -116,9 +140,10 @@ func (p *Platform) Disconnect() error {
 
 // IsConnected returns whether the platform is connected
 func (p *Platform) IsConnected() bool {
-	if p.handle == nil {
+	if !p.acquire() {
 		return false
 	}
+	defer p.release()
 
 	result := C.communicator_platform_is_connected(p.handle)
 	return result == 1
@@ -126,9 +151,10 @@ func (p *Platform) IsConnected() bool {
 
 // GetConnectionInfo returns connection information
 func (p *Platform) GetConnectionInfo() (*ConnectionInfo, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cstr := C.communicator_platform_get_connection_info(p.handle)
 	if cstr == nil {
@@ -137,7 +163,7 @@ func (p *Platform) GetConnectionInfo() (*ConnectionInfo, error) {
 	defer freeString(cstr)
 
 	var info ConnectionInfo
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &info); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &info); err != nil {
 		return nil, err
 	}
 
@@ -145,9 +171,14 @@ func (p *Platform) GetConnectionInfo() (*ConnectionInfo, error) {
 }
 
 // SendMessage sends a message to a channel
-func (p *Platform) SendMessage(channelID, text string) (*Message, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+func (p *Platform) SendMessage(channelID, text string) (_ *Message, err error) {
+	defer p.audit("SendMessage", "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("SendMessage"); err != nil {
+		return nil, err
 	}
 
 	csChannelID, freeChannelID := cStringFree(channelID)
@@ -163,7 +194,7 @@ func (p *Platform) SendMessage(channelID, text string) (*Message, error) {
 	defer freeString(cstr)
 
 	var msg Message
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &msg); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &msg); err != nil {
 		return nil, err
 	}
 
@@ -172,9 +203,10 @@ func (p *Platform) SendMessage(channelID, text string) (*Message, error) {
 
 // GetChannels returns all channels for the current user
 func (p *Platform) GetChannels() ([]Channel, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cstr := C.communicator_platform_get_channels(p.handle)
 	if cstr == nil {
@@ -183,7 +215,7 @@ func (p *Platform) GetChannels() ([]Channel, error) {
 	defer freeString(cstr)
 
 	var channels []Channel
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &channels); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &channels); err != nil {
 		return nil, err
 	}
 
@@ -192,9 +224,10 @@ func (p *Platform) GetChannels() ([]Channel, error) {
 
 // GetChannel returns a specific channel by ID
 func (p *Platform) GetChannel(channelID string) (*Channel, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cs, free := cStringFree(channelID)
 	defer free()
@@ -206,7 +239,7 @@ func (p *Platform) GetChannel(channelID string) (*Channel, error) {
 	defer freeString(cstr)
 
 	var channel Channel
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &channel); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &channel); err != nil {
 		return nil, err
 	}
 
@@ -215,9 +248,19 @@ func (p *Platform) GetChannel(channelID string) (*Channel, error) {
 
 // GetMessages returns recent messages from a channel
 func (p *Platform) GetMessages(channelID string, limit uint32) ([]Message, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	messages, err := p.getMessages(channelID, limit)
+	if err != nil {
+		return nil, err
 	}
+	return p.filterMessages(messages), nil
+}
+
+// getMessages returns a page of recent messages without applying the system message filter
+func (p *Platform) getMessages(channelID string, limit uint32) ([]Message, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
 
 	cs, free := cStringFree(channelID)
 	defer free()
@@ -229,7 +272,7 @@ func (p *Platform) GetMessages(channelID string, limit uint32) ([]Message, error
 	defer freeString(cstr)
 
 	var messages []Message
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &messages); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &messages); err != nil {
 		return nil, err
 	}
 
@@ -238,9 +281,10 @@ func (p *Platform) GetMessages(channelID string, limit uint32) ([]Message, error
 
 // GetChannelMembers returns members of a channel
 func (p *Platform) GetChannelMembers(channelID string) ([]User, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cs, free := cStringFree(channelID)
 	defer free()
@@ -252,7 +296,7 @@ func (p *Platform) GetChannelMembers(channelID string) ([]User, error) {
 	defer freeString(cstr)
 
 	var users []User
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &users); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &users); err != nil {
 		return nil, err
 	}
 
@@ -261,9 +305,10 @@ func (p *Platform) GetChannelMembers(channelID string) ([]User, error) {
 
 // GetUser returns a specific user by ID
 func (p *Platform) GetUser(userID string) (*User, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cs, free := cStringFree(userID)
 	defer free()
@@ -275,7 +320,7 @@ func (p *Platform) GetUser(userID string) (*User, error) {
 	defer freeString(cstr)
 
 	var user User
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &user); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &user); err != nil {
 		return nil, err
 	}
 
@@ -284,9 +329,10 @@ func (p *Platform) GetUser(userID string) (*User, error) {
 
 // GetCurrentUser returns the current authenticated user
 func (p *Platform) GetCurrentUser() (*User, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cstr := C.communicator_platform_get_current_user(p.handle)
 	if cstr == nil {
@@ -295,17 +341,68 @@ func (p *Platform) GetCurrentUser() (*User, error) {
 	defer freeString(cstr)
 
 	var user User
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &user); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &user); err != nil {
 		return nil, err
 	}
 
 	return &user, nil
 }
 
+// GetUserAvatar downloads a user's profile image
+func (p *Platform) GetUserAvatar(userID string) ([]byte, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	csUserID, freeUserID := cStringFree(userID)
+	defer freeUserID()
+
+	var data *C.uint8_t
+	var size C.size_t
+
+	code := C.communicator_platform_get_user_avatar(p.handle, csUserID, &data, &size)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return nil, getLastError()
+	}
+
+	goData := C.GoBytes(unsafe.Pointer(data), C.int(size))
+	C.communicator_free_file_data(data, size)
+
+	return goData, nil
+}
+
+// SetMyAvatar sets the current user's profile image
+func (p *Platform) SetMyAvatar(imageBytes []byte) (err error) {
+	defer p.audit("SetMyAvatar")(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("SetMyAvatar"); err != nil {
+		return err
+	}
+	if len(imageBytes) == 0 {
+		return ErrEmptyImage
+	}
+
+	code := C.communicator_platform_set_my_avatar(p.handle, (*C.uint8_t)(unsafe.Pointer(&imageBytes[0])), C.size_t(len(imageBytes)))
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
 // CreateDirectChannel creates a direct message channel with another user
-func (p *Platform) CreateDirectChannel(userID string) (*Channel, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+func (p *Platform) CreateDirectChannel(userID string) (_ *Channel, err error) {
+	defer p.audit("CreateDirectChannel", "user_id", userID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("CreateDirectChannel"); err != nil {
+		return nil, err
 	}
 
 	cs, free := cStringFree(userID)
@@ -318,7 +415,7 @@ func (p *Platform) CreateDirectChannel(userID string) (*Channel, error) {
 	defer freeString(cstr)
 
 	var channel Channel
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &channel); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &channel); err != nil {
 		return nil, err
 	}
 
@@ -328,14 +425,16 @@ func (p *Platform) CreateDirectChannel(userID string) (*Channel, error) {
 // RequestAllStatuses requests statuses for all users via WebSocket (async operation)
 //
 // This is a non-blocking operation that returns immediately with a sequence number.
-// The actual status data will arrive later as a Response event with matching SeqReply.
+// The actual status data will arrive later as a Response event with matching SeqReply;
+// AwaitResponse waits for it. The data maps user IDs to statuses.
 // Requires an active WebSocket connection (call SubscribeEvents first).
 //
 // Returns the sequence number on success, or error on failure.
 func (p *Platform) RequestAllStatuses() (int64, error) {
-	if p.handle == nil {
-		return -1, ErrInvalidHandle
+	if !p.acquire() {
+		return -1, ErrClosed
 	}
+	defer p.release()
 
 	seq := C.communicator_platform_request_all_statuses(p.handle)
 	if seq == -1 {
@@ -348,7 +447,8 @@ func (p *Platform) RequestAllStatuses() (int64, error) {
 // RequestUsersStatuses requests statuses for specific users via WebSocket (async operation)
 //
 // This is a non-blocking operation that returns immediately with a sequence number.
-// The actual status data will arrive later as a Response event with matching SeqReply.
+// The actual status data will arrive later as a Response event with matching SeqReply;
+// AwaitResponse waits for it.
 // Requires an active WebSocket connection (call SubscribeEvents first).
 //
 // Parameters:
@@ -356,9 +456,10 @@ func (p *Platform) RequestAllStatuses() (int64, error) {
 //
 // Returns the sequence number on success, or error on failure.
 func (p *Platform) RequestUsersStatuses(userIDs []string) (int64, error) {
-	if p.handle == nil {
-		return -1, ErrInvalidHandle
+	if !p.acquire() {
+		return -1, ErrClosed
 	}
+	defer p.release()
 
 	// Marshal user IDs to JSON
 	jsonBytes, err := json.Marshal(userIDs)
@@ -378,10 +479,14 @@ func (p *Platform) RequestUsersStatuses(userIDs []string) (int64, error) {
 }
 
 // SubscribeEvents subscribes to real-time events
+// Dropped connections are reconnected with exponential backoff. If the server
+// can't replay the events sent during the outage, the posts created meanwhile
+// are fetched and delivered as message_posted events before newer events.
 func (p *Platform) SubscribeEvents() error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+	if !p.acquireExclusive() {
+		return ErrClosed
 	}
+	defer p.releaseExclusive()
 
 	code := C.communicator_platform_subscribe_events(p.handle)
 	if code != C.COMMUNICATOR_SUCCESS {
@@ -391,11 +496,49 @@ func (p *Platform) SubscribeEvents() error {
 	return nil
 }
 
+// EventFilter scopes an event subscription to channels and teams
+// Events outside any channel or team, such as status changes, are dropped
+// unless the filter is empty. Connection events are always delivered.
+type EventFilter struct {
+	ChannelIDs []string `json:"channel_ids,omitempty"`
+	// TeamIDs also cover every channel of the teams
+	TeamIDs []string `json:"team_ids,omitempty"`
+}
+
+// SubscribeEventsFiltered subscribes to real-time events within filter
+// Events outside it are dropped in the library, before they reach PollEvent;
+// an empty filter delivers every event, like SubscribeEvents.
+func (p *Platform) SubscribeEventsFiltered(filter EventFilter) error {
+	if len(filter.ChannelIDs) == 0 && len(filter.TeamIDs) == 0 {
+		return p.SubscribeEvents()
+	}
+	if !p.acquireExclusive() {
+		return ErrClosed
+	}
+	defer p.releaseExclusive()
+
+	jsonBytes, err := json.Marshal(filter)
+	if err != nil {
+		return err
+	}
+
+	cFilter, free := cStringFree(string(jsonBytes))
+	defer free()
+
+	code := C.communicator_platform_subscribe_events_filtered(p.handle, cFilter)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
 // UnsubscribeEvents unsubscribes from real-time events
 func (p *Platform) UnsubscribeEvents() error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+	if !p.acquireExclusive() {
+		return ErrClosed
 	}
+	defer p.releaseExclusive()
 
 	code := C.communicator_platform_unsubscribe_events(p.handle)
 	if code != C.COMMUNICATOR_SUCCESS {
@@ -405,12 +548,36 @@ func (p *Platform) UnsubscribeEvents() error {
 	return nil
 }
 
+// SetStrictEventValidation enables or disables strict validation of incoming event payloads
+// When enabled, payloads that do not match the expected schema produce "schema_mismatch"
+// events, which EventStream delivers on its Errors channel as *SchemaMismatchError
+// Takes effect on the next SubscribeEvents call
+func (p *Platform) SetStrictEventValidation(enabled bool) error {
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+
+	var enabledInt C.int
+	if enabled {
+		enabledInt = 1
+	}
+
+	code := C.communicator_platform_set_strict_event_validation(p.handle, enabledInt)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
 // PollEvent polls for the next event
 // Returns nil, nil if no events are available
 func (p *Platform) PollEvent() (*Event, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cstr := C.communicator_platform_poll_event(p.handle)
 	if cstr == nil {
@@ -423,17 +590,25 @@ func (p *Platform) PollEvent() (*Event, error) {
 	defer freeString(cstr)
 
 	var event Event
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &event); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &event); err != nil {
 		return nil, err
 	}
+	if event.Type == EventResponse {
+		p.routeResponse(&event)
+	}
 
 	return &event, nil
 }
 
 // SendReply sends a reply to a message (threaded conversation)
-func (p *Platform) SendReply(channelID, text, rootID string) (*Message, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+func (p *Platform) SendReply(channelID, text, rootID string) (_ *Message, err error) {
+	defer p.audit("SendReply", "channel_id", channelID, "root_id", rootID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("SendReply"); err != nil {
+		return nil, err
 	}
 
 	csChannelID, freeChannelID := cStringFree(channelID)
@@ -452,7 +627,59 @@ func (p *Platform) SendReply(channelID, text, rootID string) (*Message, error) {
 	defer freeString(cstr)
 
 	var msg Message
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &msg); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &msg); err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+// SendMessageOpts holds optional settings for SendMessageWithOptions
+// Username, IconURL and IconEmoji post under a different persona than the
+// account's own, e.g. "alerts" or "deploys". They are only shown for accounts
+// allowed to override their identity, typically bots, and when the server
+// enables username and icon overrides.
+type SendMessageOpts struct {
+	RootID    string   `json:"root_id,omitempty"`    // reply in this message's thread
+	Username  string   `json:"username,omitempty"`   // name shown instead of the sender's
+	IconURL   string   `json:"icon_url,omitempty"`   // image shown instead of the sender's avatar
+	IconEmoji string   `json:"icon_emoji,omitempty"` // emoji shown instead of the avatar, e.g. "rotating_light"
+	FileIDs   []string `json:"file_ids,omitempty"`   // files from UploadFile to attach
+}
+
+// SendMessageWithOptions sends a message with options such as a thread or an identity override
+func (p *Platform) SendMessageWithOptions(channelID, text string, opts SendMessageOpts) (_ *Message, err error) {
+	defer p.audit("SendMessageWithOptions", "channel_id", channelID, "username", opts.Username)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("SendMessageWithOptions"); err != nil {
+		return nil, err
+	}
+
+	optsJSON, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	csChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	csText, freeText := cStringFree(text)
+	defer freeText()
+
+	csOpts, freeOpts := cStringFree(string(optsJSON))
+	defer freeOpts()
+
+	cstr := C.communicator_platform_send_message_with_options(p.handle, csChannelID, csText, csOpts)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var msg Message
+	if err := p.decode([]byte(C.GoString(cstr)), &msg); err != nil {
 		return nil, err
 	}
 
@@ -460,9 +687,14 @@ func (p *Platform) SendReply(channelID, text, rootID string) (*Message, error) {
 }
 
 // UpdateMessage updates/edits a message
-func (p *Platform) UpdateMessage(messageID, newText string) (*Message, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+func (p *Platform) UpdateMessage(messageID, newText string) (_ *Message, err error) {
+	defer p.audit("UpdateMessage", "message_id", messageID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("UpdateMessage"); err != nil {
+		return nil, err
 	}
 
 	csMessageID, freeMessageID := cStringFree(messageID)
@@ -478,7 +710,7 @@ func (p *Platform) UpdateMessage(messageID, newText string) (*Message, error) {
 	defer freeString(cstr)
 
 	var msg Message
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &msg); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &msg); err != nil {
 		return nil, err
 	}
 
@@ -486,9 +718,14 @@ func (p *Platform) UpdateMessage(messageID, newText string) (*Message, error) {
 }
 
 // DeleteMessage deletes a message
-func (p *Platform) DeleteMessage(messageID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) DeleteMessage(messageID string) (err error) {
+	defer p.audit("DeleteMessage", "message_id", messageID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("DeleteMessage"); err != nil {
+		return err
 	}
 
 	cs, free := cStringFree(messageID)
@@ -504,9 +741,10 @@ func (p *Platform) DeleteMessage(messageID string) error {
 
 // GetMessage gets a specific message by ID
 func (p *Platform) GetMessage(messageID string) (*Message, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cs, free := cStringFree(messageID)
 	defer free()
@@ -518,7 +756,7 @@ func (p *Platform) GetMessage(messageID string) (*Message, error) {
 	defer freeString(cstr)
 
 	var msg Message
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &msg); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &msg); err != nil {
 		return nil, err
 	}
 
@@ -527,9 +765,10 @@ func (p *Platform) GetMessage(messageID string) (*Message, error) {
 
 // SearchMessages searches for messages
 func (p *Platform) SearchMessages(query string, limit uint32) ([]Message, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cs, free := cStringFree(query)
 	defer free()
@@ -541,7 +780,94 @@ func (p *Platform) SearchMessages(query string, limit uint32) ([]Message, error)
 	defer freeString(cstr)
 
 	var messages []Message
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &messages); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// searchPageSize is the number of results SearchMessagesIter fetches per request
+const searchPageSize = 60
+
+// SearchMessagesPage gets one page of search results; page is zero-based
+// A page with fewer than perPage messages is the last one.
+func (p *Platform) SearchMessagesPage(query string, page, perPage uint32) ([]Message, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cs, free := cStringFree(query)
+	defer free()
+
+	cstr := C.communicator_platform_search_messages_page(p.handle, cs, C.uint32_t(page), C.uint32_t(perPage))
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var messages []Message
+	if err := p.decode([]byte(C.GoString(cstr)), &messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// SearchMessagesIter returns every search result, fetching pages as the loop advances
+//
+//	for msg, err := range p.SearchMessagesIter("release notes") {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// Iteration stops after the first error. Messages that move to a later page
+// while iterating, as new results arrive, are yielded only once.
+func (p *Platform) SearchMessagesIter(query string) iter.Seq2[Message, error] {
+	return func(yield func(Message, error) bool) {
+		seen := make(map[string]bool)
+		for page := uint32(0); ; page++ {
+			messages, err := p.SearchMessagesPage(query, page, searchPageSize)
+			if err != nil {
+				yield(Message{}, err)
+				return
+			}
+			for _, msg := range messages {
+				if seen[msg.ID] {
+					continue
+				}
+				seen[msg.ID] = true
+				if !yield(msg, nil) {
+					return
+				}
+			}
+			if len(messages) < searchPageSize {
+				return
+			}
+		}
+	}
+}
+
+// GetRecentMentions gets recent messages that mention the current user, newest first
+// It searches for the user's @-username, custom mention keys and, if enabled,
+// first name, the same way the web app's "Recent mentions" view does.
+func (p *Platform) GetRecentMentions(limit uint32) ([]Message, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cstr := C.communicator_platform_get_recent_mentions(p.handle, C.uint32_t(limit))
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var messages []Message
+	if err := p.decode([]byte(C.GoString(cstr)), &messages); err != nil {
 		return nil, err
 	}
 
@@ -550,9 +876,19 @@ func (p *Platform) SearchMessages(query string, limit uint32) ([]Message, error)
 
 // GetMessagesBefore gets messages before a specific message (pagination)
 func (p *Platform) GetMessagesBefore(channelID, beforeID string, limit uint32) ([]Message, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	messages, err := p.getMessagesBefore(channelID, beforeID, limit)
+	if err != nil {
+		return nil, err
 	}
+	return p.filterMessages(messages), nil
+}
+
+// getMessagesBefore returns a page of older messages without applying the system message filter
+func (p *Platform) getMessagesBefore(channelID, beforeID string, limit uint32) ([]Message, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
 
 	csChannelID, freeChannelID := cStringFree(channelID)
 	defer freeChannelID()
@@ -567,7 +903,7 @@ func (p *Platform) GetMessagesBefore(channelID, beforeID string, limit uint32) (
 	defer freeString(cstr)
 
 	var messages []Message
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &messages); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &messages); err != nil {
 		return nil, err
 	}
 
@@ -576,9 +912,10 @@ func (p *Platform) GetMessagesBefore(channelID, beforeID string, limit uint32) (
 
 // GetMessagesAfter gets messages after a specific message (pagination)
 func (p *Platform) GetMessagesAfter(channelID, afterID string, limit uint32) ([]Message, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	csChannelID, freeChannelID := cStringFree(channelID)
 	defer freeChannelID()
@@ -593,17 +930,53 @@ func (p *Platform) GetMessagesAfter(channelID, afterID string, limit uint32) ([]
 	defer freeString(cstr)
 
 	var messages []Message
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &messages); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &messages); err != nil {
 		return nil, err
 	}
 
-	return messages, nil
+	return p.filterMessages(messages), nil
+}
+
+// GetMessagesSince gets the messages of a channel created, edited or deleted since a point in time,
+// oldest first, for catching up after a reconnect
+// Deleted messages are included; check Message.IsDeleted before merging them.
+func (p *Platform) GetMessagesSince(channelID string, since time.Time) ([]Message, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	csChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	var sinceMillis uint64
+	if ms := since.UnixMilli(); ms > 0 {
+		sinceMillis = uint64(ms)
+	}
+
+	cstr := C.communicator_platform_get_messages_since(p.handle, csChannelID, C.uint64_t(sinceMillis))
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var messages []Message
+	if err := p.decode([]byte(C.GoString(cstr)), &messages); err != nil {
+		return nil, err
+	}
+
+	return p.filterMessages(messages), nil
 }
 
 // AddReaction adds a reaction to a message
-func (p *Platform) AddReaction(messageID, emojiName string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) AddReaction(messageID, emojiName string) (err error) {
+	defer p.audit("AddReaction", "message_id", messageID, "emoji_name", emojiName)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("AddReaction"); err != nil {
+		return err
 	}
 
 	csMessageID, freeMessageID := cStringFree(messageID)
@@ -621,9 +994,14 @@ func (p *Platform) AddReaction(messageID, emojiName string) error {
 }
 
 // RemoveReaction removes a reaction from a message
-func (p *Platform) RemoveReaction(messageID, emojiName string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) RemoveReaction(messageID, emojiName string) (err error) {
+	defer p.audit("RemoveReaction", "message_id", messageID, "emoji_name", emojiName)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("RemoveReaction"); err != nil {
+		return err
 	}
 
 	csMessageID, freeMessageID := cStringFree(messageID)
@@ -641,9 +1019,14 @@ func (p *Platform) RemoveReaction(messageID, emojiName string) error {
 }
 
 // PinPost pins a message/post to its channel
-func (p *Platform) PinPost(messageID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) PinPost(messageID string) (err error) {
+	defer p.audit("PinPost", "message_id", messageID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("PinPost"); err != nil {
+		return err
 	}
 
 	csMessageID, freeMessageID := cStringFree(messageID)
@@ -658,9 +1041,14 @@ func (p *Platform) PinPost(messageID string) error {
 }
 
 // UnpinPost unpins a message/post from its channel
-func (p *Platform) UnpinPost(messageID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) UnpinPost(messageID string) (err error) {
+	defer p.audit("UnpinPost", "message_id", messageID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("UnpinPost"); err != nil {
+		return err
 	}
 
 	csMessageID, freeMessageID := cStringFree(messageID)
@@ -676,9 +1064,10 @@ func (p *Platform) UnpinPost(messageID string) error {
 
 // GetPinnedPosts gets all pinned messages/posts for a channel
 func (p *Platform) GetPinnedPosts(channelID string) ([]Message, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	csChannelID, freeChannelID := cStringFree(channelID)
 	defer freeChannelID()
@@ -690,18 +1079,121 @@ func (p *Platform) GetPinnedPosts(channelID string) ([]Message, error) {
 	defer freeString(cstr)
 
 	var messages []Message
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &messages); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &messages); err != nil {
 		return nil, err
 	}
 
 	return messages, nil
 }
 
+// GetPermalink returns a permanent link to a message
+func (p *Platform) GetPermalink(messageID string) (string, error) {
+	if !p.acquire() {
+		return "", ErrClosed
+	}
+	defer p.release()
+
+	cs, free := cStringFree(messageID)
+	defer free()
+
+	cstr := C.communicator_platform_get_permalink(p.handle, cs)
+	if cstr == nil {
+		return "", getLastError()
+	}
+	defer freeString(cstr)
+
+	return C.GoString(cstr), nil
+}
+
+// ResolvePermalink returns the message a permalink points to and the channel it was posted in
+func (p *Platform) ResolvePermalink(permalink string) (*Message, *Channel, error) {
+	if !p.acquire() {
+		return nil, nil, ErrClosed
+	}
+	defer p.release()
+
+	cs, free := cStringFree(permalink)
+	defer free()
+
+	cstr := C.communicator_platform_resolve_permalink(p.handle, cs)
+	if cstr == nil {
+		return nil, nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var result struct {
+		Message Message `json:"message"`
+		Channel Channel `json:"channel"`
+	}
+	if err := p.decode([]byte(C.GoString(cstr)), &result); err != nil {
+		return nil, nil, err
+	}
+
+	return &result.Message, &result.Channel, nil
+}
+
+// GetLinkMetadata retrieves the preview metadata of a web page
+// The server fetches the page, so previews match the ones shown by the
+// platform's own clients.
+func (p *Platform) GetLinkMetadata(url string) (*LinkMetadata, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cs, free := cStringFree(url)
+	defer free()
+
+	cstr := C.communicator_platform_get_link_metadata(p.handle, cs)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var link LinkMetadata
+	if err := p.decode([]byte(C.GoString(cstr)), &link); err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+// MessageLink holds the parts of a link that points to a single message
+type MessageLink struct {
+	Platform  string  `json:"platform"`   // e.g. "mattermost", "slack"
+	ServerURL string  `json:"server_url"` // Includes any subpath the server is hosted under
+	Team      *string `json:"team,omitempty"`
+	Channel   *string `json:"channel,omitempty"`
+	MessageID string  `json:"message_id"`
+}
+
+// ParseMessageLink parses a message link from any supported platform
+// No connection is needed; use ResolvePermalink to fetch the message itself
+func ParseMessageLink(url string) (*MessageLink, error) {
+	defer pinThread()()
+	cs, free := cStringFree(url)
+	defer free()
+
+	cstr := C.communicator_parse_message_link(cs)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var link MessageLink
+	if err := json.Unmarshal([]byte(C.GoString(cstr)), &link); err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
+
 // GetEmojis retrieves a list of custom emojis from the platform
 func (p *Platform) GetEmojis(page, perPage uint32) ([]Emoji, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cstr := C.communicator_platform_get_emojis(p.handle, C.uint32_t(page), C.uint32_t(perPage))
 	if cstr == nil {
@@ -710,72 +1202,588 @@ func (p *Platform) GetEmojis(page, perPage uint32) ([]Emoji, error) {
 	defer freeString(cstr)
 
 	var emojis []Emoji
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &emojis); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &emojis); err != nil {
 		return nil, err
 	}
 
 	return emojis, nil
 }
 
-// GetChannelByName gets a channel by name
-func (p *Platform) GetChannelByName(teamID, channelName string) (*Channel, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+// GetEmojiByName retrieves a custom emoji by name, with or without colons
+// Returns an error with code ErrorNotFound if no custom emoji has that name
+func (p *Platform) GetEmojiByName(name string) (*Emoji, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
-	csTeamID, freeTeamID := cStringFree(teamID)
-	defer freeTeamID()
+	cs, free := cStringFree(name)
+	defer free()
 
-	csChannelName, freeChannelName := cStringFree(channelName)
-	defer freeChannelName()
+	cstr := C.communicator_platform_get_emoji_by_name(p.handle, cs)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
 
-	cstr := C.communicator_platform_get_channel_by_name(p.handle, csTeamID, csChannelName)
+	var emoji Emoji
+	if err := p.decode([]byte(C.GoString(cstr)), &emoji); err != nil {
+		return nil, err
+	}
+
+	return &emoji, nil
+}
+
+// AutocompleteEmoji suggests custom emojis whose names start with prefix, for :emoji: completion
+// A leading colon is ignored; standard Unicode emojis are not included
+func (p *Platform) AutocompleteEmoji(prefix string) ([]Emoji, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cs, free := cStringFree(prefix)
+	defer free()
+
+	cstr := C.communicator_platform_autocomplete_emoji(p.handle, cs)
 	if cstr == nil {
 		return nil, getLastError()
 	}
 	defer freeString(cstr)
 
-	var channel Channel
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &channel); err != nil {
+	var emojis []Emoji
+	if err := p.decode([]byte(C.GoString(cstr)), &emojis); err != nil {
 		return nil, err
 	}
 
-	return &channel, nil
+	return emojis, nil
+}
+
+// CreateEmoji uploads a custom emoji from an image file
+func (p *Platform) CreateEmoji(name, imagePath string) (_ *Emoji, err error) {
+	defer p.audit("CreateEmoji", "emoji_name", name)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("CreateEmoji"); err != nil {
+		return nil, err
+	}
+
+	csName, freeName := cStringFree(name)
+	defer freeName()
+
+	csImagePath, freeImagePath := cStringFree(imagePath)
+	defer freeImagePath()
+
+	cstr := C.communicator_platform_create_emoji(p.handle, csName, csImagePath)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var emoji Emoji
+	if err := p.decode([]byte(C.GoString(cstr)), &emoji); err != nil {
+		return nil, err
+	}
+
+	return &emoji, nil
+}
+
+// DeleteEmoji deletes a custom emoji
+func (p *Platform) DeleteEmoji(emojiID string) (err error) {
+	defer p.audit("DeleteEmoji", "emoji_id", emojiID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("DeleteEmoji"); err != nil {
+		return err
+	}
+
+	csEmojiID, freeEmojiID := cStringFree(emojiID)
+	defer freeEmojiID()
+
+	code := C.communicator_platform_delete_emoji(p.handle, csEmojiID)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
+// GetEmojiImage downloads the image of a custom emoji
+func (p *Platform) GetEmojiImage(emojiID string) ([]byte, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	csEmojiID, freeEmojiID := cStringFree(emojiID)
+	defer freeEmojiID()
+
+	var data *C.uint8_t
+	var size C.size_t
+
+	code := C.communicator_platform_get_emoji_image(p.handle, csEmojiID, &data, &size)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return nil, getLastError()
+	}
+
+	goData := C.GoBytes(unsafe.Pointer(data), C.int(size))
+	C.communicator_free_file_data(data, size)
+
+	return goData, nil
+}
+
+// GetChannelByName gets a channel by name
+func (p *Platform) GetChannelByName(teamID, channelName string) (*Channel, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	csTeamID, freeTeamID := cStringFree(teamID)
+	defer freeTeamID()
+
+	csChannelName, freeChannelName := cStringFree(channelName)
+	defer freeChannelName()
+
+	cstr := C.communicator_platform_get_channel_by_name(p.handle, csTeamID, csChannelName)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var channel Channel
+	if err := p.decode([]byte(C.GoString(cstr)), &channel); err != nil {
+		return nil, err
+	}
+
+	return &channel, nil
+}
+
+// GetPublicChannels gets a page of a team's public channels, including ones not joined
+// A page shorter than perPage is the last one
+func (p *Platform) GetPublicChannels(teamID string, page, perPage uint32) ([]Channel, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	csTeamID, freeTeamID := cStringFree(teamID)
+	defer freeTeamID()
+
+	cstr := C.communicator_platform_get_public_channels(p.handle, csTeamID, C.uint32_t(page), C.uint32_t(perPage))
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var channels []Channel
+	if err := p.decode([]byte(C.GoString(cstr)), &channels); err != nil {
+		return nil, err
+	}
+
+	return channels, nil
+}
+
+// GetArchivedChannels gets a page of a team's archived channels
+// Archived private channels are only included if the user is a member
+func (p *Platform) GetArchivedChannels(teamID string, page, perPage uint32) ([]Channel, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	csTeamID, freeTeamID := cStringFree(teamID)
+	defer freeTeamID()
+
+	cstr := C.communicator_platform_get_archived_channels(p.handle, csTeamID, C.uint32_t(page), C.uint32_t(perPage))
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var channels []Channel
+	if err := p.decode([]byte(C.GoString(cstr)), &channels); err != nil {
+		return nil, err
+	}
+
+	return channels, nil
+}
+
+// CreateGroupChannel creates a group direct message channel
+func (p *Platform) CreateGroupChannel(userIDs []string) (_ *Channel, err error) {
+	defer p.audit("CreateGroupChannel", "user_ids", strings.Join(userIDs, ","))(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("CreateGroupChannel"); err != nil {
+		return nil, err
+	}
+
+	// Marshal user IDs to JSON
+	jsonBytes, err := json.Marshal(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cs, free := cStringFree(string(jsonBytes))
+	defer free()
+
+	cstr := C.communicator_platform_create_group_channel(p.handle, cs)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var channel Channel
+	if err := p.decode([]byte(C.GoString(cstr)), &channel); err != nil {
+		return nil, err
+	}
+
+	return &channel, nil
+}
+
+// FindDirectChannel looks up an existing DM channel with a user without creating one
+// Returns nil if the users have no DM channel yet
+func (p *Platform) FindDirectChannel(userID string) (*Channel, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cs, free := cStringFree(userID)
+	defer free()
+
+	cstr := C.communicator_platform_find_direct_channel(p.handle, cs)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var channel *Channel
+	if err := p.decode([]byte(C.GoString(cstr)), &channel); err != nil {
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+// FindGroupChannel looks up an existing group message channel with exactly these users
+// (plus the current user) without creating one
+// Returns nil if no such group channel exists yet
+func (p *Platform) FindGroupChannel(userIDs []string) (*Channel, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	// Marshal user IDs to JSON
+	jsonBytes, err := json.Marshal(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cs, free := cStringFree(string(jsonBytes))
+	defer free()
+
+	cstr := C.communicator_platform_find_group_channel(p.handle, cs)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var channel *Channel
+	if err := p.decode([]byte(C.GoString(cstr)), &channel); err != nil {
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+// UpdateGroupParticipants adds and removes participants of a group message
+// and returns the conversation with the new set of participants
+// The current user always stays a participant. Mattermost fixes a group
+// message's participants, so the result is a different channel: the group
+// message for the new set, created if needed, or the direct message when one
+// other user is left. The original conversation is left as it is.
+func (p *Platform) UpdateGroupParticipants(channelID string, add, remove []string) (_ *Channel, err error) {
+	defer p.audit("UpdateGroupParticipants", "channel_id", channelID,
+		"add", strings.Join(add, ","), "remove", strings.Join(remove, ","))(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("UpdateGroupParticipants"); err != nil {
+		return nil, err
+	}
+
+	if add == nil {
+		add = []string{}
+	}
+	if remove == nil {
+		remove = []string{}
+	}
+	addJSON, err := json.Marshal(add)
+	if err != nil {
+		return nil, err
+	}
+	removeJSON, err := json.Marshal(remove)
+	if err != nil {
+		return nil, err
+	}
+
+	csChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+	csAdd, freeAdd := cStringFree(string(addJSON))
+	defer freeAdd()
+	csRemove, freeRemove := cStringFree(string(removeJSON))
+	defer freeRemove()
+
+	cstr := C.communicator_platform_update_group_participants(p.handle, csChannelID, csAdd, csRemove)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var channel Channel
+	if err := p.decode([]byte(C.GoString(cstr)), &channel); err != nil {
+		return nil, err
+	}
+
+	return &channel, nil
+}
+
+// GetEffectivePermissions returns the permissions a user holds in a channel,
+// combining their system, team and channel roles
+func (p *Platform) GetEffectivePermissions(userID, channelID string) ([]string, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	csUserID, freeUserID := cStringFree(userID)
+	defer freeUserID()
+
+	csChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	cstr := C.communicator_platform_get_effective_permissions(p.handle, csUserID, csChannelID)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var permissions []string
+	if err := p.decode([]byte(C.GoString(cstr)), &permissions); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// Allowed reports whether a user holds a permission (e.g. "manage_public_channel_members")
+// in a channel. Results are cached and invalidated when role events arrive
+func (p *Platform) Allowed(userID, channelID, permission string) (bool, error) {
+	if !p.acquire() {
+		return false, ErrClosed
+	}
+	defer p.release()
+
+	csUserID, freeUserID := cStringFree(userID)
+	defer freeUserID()
+
+	csChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	csPermission, freePermission := cStringFree(permission)
+	defer freePermission()
+
+	result := C.communicator_platform_has_permission(p.handle, csUserID, csChannelID, csPermission)
+	if result < 0 {
+		return false, getLastError()
+	}
+
+	return result == 1, nil
+}
+
+// AddChannelMember adds a user to a channel
+func (p *Platform) AddChannelMember(channelID, userID string) (err error) {
+	defer p.audit("AddChannelMember", "channel_id", channelID, "user_id", userID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("AddChannelMember"); err != nil {
+		return err
+	}
+
+	csChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	csUserID, freeUserID := cStringFree(userID)
+	defer freeUserID()
+
+	code := C.communicator_platform_add_channel_member(p.handle, csChannelID, csUserID)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
+// RemoveChannelMember removes a user from a channel
+func (p *Platform) RemoveChannelMember(channelID, userID string) (err error) {
+	defer p.audit("RemoveChannelMember", "channel_id", channelID, "user_id", userID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("RemoveChannelMember"); err != nil {
+		return err
+	}
+
+	csChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	csUserID, freeUserID := cStringFree(userID)
+	defer freeUserID()
+
+	code := C.communicator_platform_remove_channel_member(p.handle, csChannelID, csUserID)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
+// JoinChannel joins a channel as the current user
+func (p *Platform) JoinChannel(channelID string) (_ *Channel, err error) {
+	defer p.audit("JoinChannel", "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("JoinChannel"); err != nil {
+		return nil, err
+	}
+
+	csChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	result := C.communicator_platform_join_channel(p.handle, csChannelID)
+	if result == nil {
+		return nil, getLastError()
+	}
+	defer C.communicator_free_string(result)
+
+	var channel Channel
+	if err := p.decode([]byte(C.GoString(result)), &channel); err != nil {
+		return nil, &PlatformError{Code: ErrorUnknown, Message: "failed to parse channel JSON: " + err.Error()}
+	}
+
+	return &channel, nil
+}
+
+// JoinChannelByName joins a channel by its name (not display name) within a team
+func (p *Platform) JoinChannelByName(teamID, channelName string) (_ *Channel, err error) {
+	defer p.audit("JoinChannelByName", "team_id", teamID, "channel_name", channelName)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("JoinChannelByName"); err != nil {
+		return nil, err
+	}
+
+	csTeamID, freeTeamID := cStringFree(teamID)
+	defer freeTeamID()
+
+	csChannelName, freeChannelName := cStringFree(channelName)
+	defer freeChannelName()
+
+	result := C.communicator_platform_join_channel_by_name(p.handle, csTeamID, csChannelName)
+	if result == nil {
+		return nil, getLastError()
+	}
+	defer C.communicator_free_string(result)
+
+	var channel Channel
+	if err := p.decode([]byte(C.GoString(result)), &channel); err != nil {
+		return nil, &PlatformError{Code: ErrorUnknown, Message: "failed to parse channel JSON: " + err.Error()}
+	}
+
+	return &channel, nil
+}
+
+// LeaveChannel leaves a channel as the current user
+func (p *Platform) LeaveChannel(channelID string) (err error) {
+	defer p.audit("LeaveChannel", "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("LeaveChannel"); err != nil {
+		return err
+	}
+
+	csChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	code := C.communicator_platform_leave_channel(p.handle, csChannelID)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
 }
 
-// CreateGroupChannel creates a group direct message channel
-func (p *Platform) CreateGroupChannel(userIDs []string) (*Channel, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+// UpdateChannelMemberRoles replaces a channel member's explicit roles
+// e.g. []string{"channel_user", "channel_admin"}
+func (p *Platform) UpdateChannelMemberRoles(channelID, userID string, roles []string) (err error) {
+	defer p.audit("UpdateChannelMemberRoles", "channel_id", channelID, "user_id", userID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("UpdateChannelMemberRoles"); err != nil {
+		return err
 	}
 
-	// Marshal user IDs to JSON
-	jsonBytes, err := json.Marshal(userIDs)
+	rolesJSON, err := json.Marshal(roles)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	cs, free := cStringFree(string(jsonBytes))
-	defer free()
+	csChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
 
-	cstr := C.communicator_platform_create_group_channel(p.handle, cs)
-	if cstr == nil {
-		return nil, getLastError()
-	}
-	defer freeString(cstr)
+	csUserID, freeUserID := cStringFree(userID)
+	defer freeUserID()
 
-	var channel Channel
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &channel); err != nil {
-		return nil, err
+	csRoles, freeRoles := cStringFree(string(rolesJSON))
+	defer freeRoles()
+
+	code := C.communicator_platform_update_channel_member_roles(p.handle, csChannelID, csUserID, csRoles)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
 	}
 
-	return &channel, nil
+	return nil
 }
 
-// AddChannelMember adds a user to a channel
-func (p *Platform) AddChannelMember(channelID, userID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+// SetChannelMemberSchemeRoles sets whether a member is a channel admin and/or a regular channel user
+func (p *Platform) SetChannelMemberSchemeRoles(channelID, userID string, schemeAdmin, schemeUser bool) (err error) {
+	defer p.audit("SetChannelMemberSchemeRoles", "channel_id", channelID, "user_id", userID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("SetChannelMemberSchemeRoles"); err != nil {
+		return err
 	}
 
 	csChannelID, freeChannelID := cStringFree(channelID)
@@ -784,7 +1792,15 @@ func (p *Platform) AddChannelMember(channelID, userID string) error {
 	csUserID, freeUserID := cStringFree(userID)
 	defer freeUserID()
 
-	code := C.communicator_platform_add_channel_member(p.handle, csChannelID, csUserID)
+	var adminInt, userInt C.int
+	if schemeAdmin {
+		adminInt = 1
+	}
+	if schemeUser {
+		userInt = 1
+	}
+
+	code := C.communicator_platform_set_channel_member_scheme_roles(p.handle, csChannelID, csUserID, adminInt, userInt)
 	if code != C.COMMUNICATOR_SUCCESS {
 		return getLastError()
 	}
@@ -792,30 +1808,74 @@ func (p *Platform) AddChannelMember(channelID, userID string) error {
 	return nil
 }
 
-// RemoveChannelMember removes a user from a channel
-func (p *Platform) RemoveChannelMember(channelID, userID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+// PromoteChannelModerator makes a channel member a channel admin
+func (p *Platform) PromoteChannelModerator(channelID, userID string) error {
+	return p.SetChannelMemberSchemeRoles(channelID, userID, true, true)
+}
+
+// DemoteChannelModerator removes a member's channel admin role
+func (p *Platform) DemoteChannelModerator(channelID, userID string) error {
+	return p.SetChannelMemberSchemeRoles(channelID, userID, false, true)
+}
+
+// SyncChannelMembers adds and removes members so the channel matches desiredUserIDs
+// With opts.DryRun set, the changes are only computed and returned
+func (p *Platform) SyncChannelMembers(channelID string, desiredUserIDs []string, opts MemberSyncOptions) (_ *MemberSyncResult, err error) {
+	if !opts.DryRun {
+		defer p.audit("SyncChannelMembers", "channel_id", channelID)(&err)
+		if err := p.checkWritable("SyncChannelMembers"); err != nil {
+			return nil, err
+		}
+	}
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	if desiredUserIDs == nil {
+		desiredUserIDs = []string{}
+	}
+	userIDsJSON, err := json.Marshal(desiredUserIDs)
+	if err != nil {
+		return nil, err
+	}
+	optsJSON, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
 	}
 
 	csChannelID, freeChannelID := cStringFree(channelID)
 	defer freeChannelID()
 
-	csUserID, freeUserID := cStringFree(userID)
-	defer freeUserID()
+	csUserIDs, freeUserIDs := cStringFree(string(userIDsJSON))
+	defer freeUserIDs()
 
-	code := C.communicator_platform_remove_channel_member(p.handle, csChannelID, csUserID)
-	if code != C.COMMUNICATOR_SUCCESS {
-		return getLastError()
+	csOpts, freeOpts := cStringFree(string(optsJSON))
+	defer freeOpts()
+
+	cstr := C.communicator_platform_sync_channel_members(p.handle, csChannelID, csUserIDs, csOpts)
+	if cstr == nil {
+		return nil, getLastError()
 	}
+	defer freeString(cstr)
 
-	return nil
+	var result MemberSyncResult
+	if err := p.decode([]byte(C.GoString(cstr)), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
 }
 
 // ViewChannel marks a channel as viewed (read) by the current user
-func (p *Platform) ViewChannel(channelID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) ViewChannel(channelID string) (err error) {
+	defer p.audit("ViewChannel", "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("ViewChannel"); err != nil {
+		return err
 	}
 
 	cs, free := cStringFree(channelID)
@@ -831,9 +1891,10 @@ func (p *Platform) ViewChannel(channelID string) error {
 
 // GetChannelUnread gets unread message information for a specific channel
 func (p *Platform) GetChannelUnread(channelID string) (*ChannelUnread, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cs, free := cStringFree(channelID)
 	defer free()
@@ -845,7 +1906,7 @@ func (p *Platform) GetChannelUnread(channelID string) (*ChannelUnread, error) {
 	defer freeString(cstr)
 
 	var unread ChannelUnread
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &unread); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &unread); err != nil {
 		return nil, err
 	}
 
@@ -854,9 +1915,10 @@ func (p *Platform) GetChannelUnread(channelID string) (*ChannelUnread, error) {
 
 // GetTeamUnreads gets unread counts for all channels in a specific team
 func (p *Platform) GetTeamUnreads(teamID string) ([]ChannelUnread, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cs, free := cStringFree(teamID)
 	defer free()
@@ -868,7 +1930,7 @@ func (p *Platform) GetTeamUnreads(teamID string) ([]ChannelUnread, error) {
 	defer freeString(cstr)
 
 	var unreads []ChannelUnread
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &unreads); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &unreads); err != nil {
 		return nil, err
 	}
 
@@ -878,9 +1940,10 @@ func (p *Platform) GetTeamUnreads(teamID string) ([]ChannelUnread, error) {
 // GetAllUnreads gets unread counts for all channels across all teams
 // Returns a slice of TeamUnread containing aggregate unread information
 func (p *Platform) GetAllUnreads() ([]TeamUnread, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cstr := C.communicator_platform_get_all_unreads(p.handle)
 	if cstr == nil {
@@ -889,21 +1952,43 @@ func (p *Platform) GetAllUnreads() ([]TeamUnread, error) {
 	defer freeString(cstr)
 
 	var unreads []TeamUnread
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &unreads); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &unreads); err != nil {
 		return nil, err
 	}
 
 	return unreads, nil
 }
 
+// GetTotalUnreads gets unread counts summed over every team, for tray and menu bar badges
+func (p *Platform) GetTotalUnreads() (*UnreadTotals, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cstr := C.communicator_platform_get_total_unreads(p.handle)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var totals UnreadTotals
+	if err := p.decode([]byte(C.GoString(cstr)), &totals); err != nil {
+		return nil, err
+	}
+
+	return &totals, nil
+}
+
 // GetUnreadPosts gets the actual unread messages in a channel
 // limitAfter: maximum number of posts to retrieve after last read (newer posts)
 // limitBefore: maximum number of posts to retrieve before last read (context)
 // Returns a JSON string containing the post list
 func (p *Platform) GetUnreadPosts(channelID string, limitAfter, limitBefore uint32) (string, error) {
-	if p.handle == nil {
-		return "", ErrInvalidHandle
+	if !p.acquire() {
+		return "", ErrClosed
 	}
+	defer p.release()
 
 	csChannelID, freeChannelID := cStringFree(channelID)
 	defer freeChannelID()
@@ -924,9 +2009,10 @@ func (p *Platform) GetUnreadPosts(channelID string, limitAfter, limitBefore uint
 
 // GetUserByUsername gets a user by username
 func (p *Platform) GetUserByUsername(username string) (*User, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cs, free := cStringFree(username)
 	defer free()
@@ -938,7 +2024,7 @@ func (p *Platform) GetUserByUsername(username string) (*User, error) {
 	defer freeString(cstr)
 
 	var user User
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &user); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &user); err != nil {
 		return nil, err
 	}
 
@@ -947,9 +2033,10 @@ func (p *Platform) GetUserByUsername(username string) (*User, error) {
 
 // GetUserByEmail gets a user by email
 func (p *Platform) GetUserByEmail(email string) (*User, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cs, free := cStringFree(email)
 	defer free()
@@ -961,7 +2048,7 @@ func (p *Platform) GetUserByEmail(email string) (*User, error) {
 	defer freeString(cstr)
 
 	var user User
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &user); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &user); err != nil {
 		return nil, err
 	}
 
@@ -970,9 +2057,10 @@ func (p *Platform) GetUserByEmail(email string) (*User, error) {
 
 // GetUsersByIDs gets multiple users by their IDs (batch operation)
 func (p *Platform) GetUsersByIDs(userIDs []string) ([]User, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	// Marshal user IDs to JSON
 	jsonBytes, err := json.Marshal(userIDs)
@@ -990,7 +2078,7 @@ func (p *Platform) GetUsersByIDs(userIDs []string) ([]User, error) {
 	defer freeString(cstr)
 
 	var users []User
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &users); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &users); err != nil {
 		return nil, err
 	}
 
@@ -1005,9 +2093,14 @@ type CustomStatus struct {
 }
 
 // SetCustomStatus sets a custom status message
-func (p *Platform) SetCustomStatus(status CustomStatus) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) SetCustomStatus(status CustomStatus) (err error) {
+	defer p.audit("SetCustomStatus")(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("SetCustomStatus"); err != nil {
+		return err
 	}
 
 	// Marshal status to JSON
@@ -1028,9 +2121,14 @@ func (p *Platform) SetCustomStatus(status CustomStatus) error {
 }
 
 // RemoveCustomStatus removes/clears the current user's custom status
-func (p *Platform) RemoveCustomStatus() error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) RemoveCustomStatus() (err error) {
+	defer p.audit("RemoveCustomStatus")(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("RemoveCustomStatus"); err != nil {
+		return err
 	}
 
 	code := C.communicator_platform_remove_custom_status(p.handle)
@@ -1043,9 +2141,14 @@ func (p *Platform) RemoveCustomStatus() error {
 
 // SetStatus sets the current user's status
 // Valid status values: "online", "away", "dnd", "offline"
-func (p *Platform) SetStatus(status string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) SetStatus(status string) (err error) {
+	defer p.audit("SetStatus")(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("SetStatus"); err != nil {
+		return err
 	}
 
 	cs, free := cStringFree(status)
@@ -1062,9 +2165,10 @@ func (p *Platform) SetStatus(status string) error {
 // GetUserStatus gets a user's status
 // Returns the status string: "online", "away", "dnd", "offline", or "unknown"
 func (p *Platform) GetUserStatus(userID string) (string, error) {
-	if p.handle == nil {
-		return "", ErrInvalidHandle
+	if !p.acquire() {
+		return "", ErrClosed
 	}
+	defer p.release()
 
 	cs, free := cStringFree(userID)
 	defer free()
@@ -1079,7 +2183,7 @@ func (p *Platform) GetUserStatus(userID string) (string, error) {
 	var statusResponse struct {
 		Status string `json:"status"`
 	}
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &statusResponse); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &statusResponse); err != nil {
 		return "", err
 	}
 
@@ -1089,9 +2193,14 @@ func (p *Platform) GetUserStatus(userID string) (string, error) {
 // SendTypingIndicator sends a typing indicator to a channel
 // For regular channel typing, pass empty string for parentID
 // For thread typing, pass the parent post ID
-func (p *Platform) SendTypingIndicator(channelID string, parentID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) SendTypingIndicator(channelID string, parentID string) (err error) {
+	defer p.audit("SendTypingIndicator", "channel_id", channelID, "root_id", parentID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("SendTypingIndicator"); err != nil {
+		return err
 	}
 
 	csChannelID, freeChannel := cStringFree(channelID)
@@ -1115,9 +2224,10 @@ func (p *Platform) SendTypingIndicator(channelID string, parentID string) error
 // GetUsersStatus gets status for multiple users (batch operation)
 // Returns a map of user IDs to status strings
 func (p *Platform) GetUsersStatus(userIDs []string) (map[string]string, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	// Marshal user IDs to JSON
 	jsonBytes, err := json.Marshal(userIDs)
@@ -1135,18 +2245,53 @@ func (p *Platform) GetUsersStatus(userIDs []string) (map[string]string, error) {
 	defer freeString(cstr)
 
 	var statusMap map[string]string
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &statusMap); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &statusMap); err != nil {
 		return nil, err
 	}
 
 	return statusMap, nil
 }
 
+// GetStatusesByIDsWithExpiry gets detailed status for multiple users (batch operation)
+// Unlike GetUsersStatus, each status says whether it was set manually, when do
+// not disturb expires and when the user was last active.
+func (p *Platform) GetStatusesByIDsWithExpiry(userIDs []string) ([]Status, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	if userIDs == nil {
+		userIDs = []string{}
+	}
+	jsonBytes, err := json.Marshal(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cs, free := cStringFree(string(jsonBytes))
+	defer free()
+
+	cstr := C.communicator_platform_get_statuses_by_ids(p.handle, cs)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var statuses []Status
+	if err := p.decode([]byte(C.GoString(cstr)), &statuses); err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
 // GetTeams gets all teams the user belongs to
 func (p *Platform) GetTeams() ([]Team, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cstr := C.communicator_platform_get_teams(p.handle)
 	if cstr == nil {
@@ -1155,7 +2300,7 @@ func (p *Platform) GetTeams() ([]Team, error) {
 	defer freeString(cstr)
 
 	var teams []Team
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &teams); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &teams); err != nil {
 		return nil, err
 	}
 
@@ -1164,9 +2309,10 @@ func (p *Platform) GetTeams() ([]Team, error) {
 
 // GetTeam gets a specific team by ID
 func (p *Platform) GetTeam(teamID string) (*Team, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cs, free := cStringFree(teamID)
 	defer free()
@@ -1178,18 +2324,113 @@ func (p *Platform) GetTeam(teamID string) (*Team, error) {
 	defer freeString(cstr)
 
 	var team Team
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &team); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &team); err != nil {
+		return nil, err
+	}
+
+	return &team, nil
+}
+
+// CreateTeam creates a new team
+func (p *Platform) CreateTeam(team *NewTeam) (_ *Team, err error) {
+	defer p.audit("CreateTeam")(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("CreateTeam"); err != nil {
+		return nil, err
+	}
+
+	teamJSON, err := json.Marshal(team)
+	if err != nil {
+		return nil, err
+	}
+
+	cs, free := cStringFree(string(teamJSON))
+	defer free()
+
+	cstr := C.communicator_platform_create_team(p.handle, cs)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var created Team
+	if err := p.decode([]byte(C.GoString(cstr)), &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdateTeam applies a partial update to a team
+func (p *Platform) UpdateTeam(teamID string, patch *TeamPatch) (_ *Team, err error) {
+	defer p.audit("UpdateTeam", "team_id", teamID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("UpdateTeam"); err != nil {
+		return nil, err
+	}
+
+	patchJSON, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	csTeamID, freeTeamID := cStringFree(teamID)
+	defer freeTeamID()
+
+	csPatch, freePatch := cStringFree(string(patchJSON))
+	defer freePatch()
+
+	cstr := C.communicator_platform_update_team(p.handle, csTeamID, csPatch)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var team Team
+	if err := p.decode([]byte(C.GoString(cstr)), &team); err != nil {
 		return nil, err
 	}
 
 	return &team, nil
 }
 
+// SetTeamIcon sets a team's icon from image bytes (png, jpeg, gif or bmp)
+func (p *Platform) SetTeamIcon(teamID string, imageBytes []byte) (err error) {
+	defer p.audit("SetTeamIcon", "team_id", teamID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("SetTeamIcon"); err != nil {
+		return err
+	}
+	if len(imageBytes) == 0 {
+		return ErrEmptyImage
+	}
+
+	cs, free := cStringFree(teamID)
+	defer free()
+
+	code := C.communicator_platform_set_team_icon(p.handle, cs, (*C.uint8_t)(unsafe.Pointer(&imageBytes[0])), C.size_t(len(imageBytes)))
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
 // GetTeamByName gets a team by name
 func (p *Platform) GetTeamByName(teamName string) (*Team, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cs, free := cStringFree(teamName)
 	defer free()
@@ -1201,19 +2442,69 @@ func (p *Platform) GetTeamByName(teamName string) (*Team, error) {
 	defer freeString(cstr)
 
 	var team Team
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &team); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &team); err != nil {
 		return nil, err
 	}
 
 	return &team, nil
 }
 
+// GetTeamStats gets member counts for a team
+func (p *Platform) GetTeamStats(teamID string) (*TeamStats, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cs, free := cStringFree(teamID)
+	defer free()
+
+	cstr := C.communicator_platform_get_team_stats(p.handle, cs)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var stats TeamStats
+	if err := p.decode([]byte(C.GoString(cstr)), &stats); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// SearchTeams searches the teams visible to the current user by name or display name
+// Unlike GetTeams, it also finds teams the user hasn't joined
+func (p *Platform) SearchTeams(term string) ([]Team, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cs, free := cStringFree(term)
+	defer free()
+
+	cstr := C.communicator_platform_search_teams(p.handle, cs)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var teams []Team
+	if err := p.decode([]byte(C.GoString(cstr)), &teams); err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
 // SetTeamID sets the active team/workspace ID
 // Pass an empty string or nil pointer to unset the team ID
 func (p *Platform) SetTeamID(teamID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+	if !p.acquire() {
+		return ErrClosed
 	}
+	defer p.release()
 
 	var cs *C.char
 	var free func()
@@ -1238,9 +2529,10 @@ func (p *Platform) SetTeamID(teamID string) error {
 
 // GetThread fetches a thread (root post and all replies)
 func (p *Platform) GetThread(postID string) ([]Message, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	csPostID, freePostID := cStringFree(postID)
 	defer freePostID()
@@ -1252,7 +2544,7 @@ func (p *Platform) GetThread(postID string) ([]Message, error) {
 	defer freeString(cstr)
 
 	var messages []Message
-	if err := json.Unmarshal([]byte(C.GoString(cstr)), &messages); err != nil {
+	if err := p.decode([]byte(C.GoString(cstr)), &messages); err != nil {
 		return nil, err
 	}
 
@@ -1260,9 +2552,14 @@ func (p *Platform) GetThread(postID string) ([]Message, error) {
 }
 
 // FollowThread makes the authenticated user follow a thread
-func (p *Platform) FollowThread(threadID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) FollowThread(threadID string) (err error) {
+	defer p.audit("FollowThread", "thread_id", threadID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("FollowThread"); err != nil {
+		return err
 	}
 
 	csThreadID, freeThreadID := cStringFree(threadID)
@@ -1277,9 +2574,14 @@ func (p *Platform) FollowThread(threadID string) error {
 }
 
 // UnfollowThread makes the authenticated user unfollow a thread
-func (p *Platform) UnfollowThread(threadID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) UnfollowThread(threadID string) (err error) {
+	defer p.audit("UnfollowThread", "thread_id", threadID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("UnfollowThread"); err != nil {
+		return err
 	}
 
 	csThreadID, freeThreadID := cStringFree(threadID)
@@ -1294,9 +2596,14 @@ func (p *Platform) UnfollowThread(threadID string) error {
 }
 
 // MarkThreadRead marks a thread as read up to the current time
-func (p *Platform) MarkThreadRead(threadID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) MarkThreadRead(threadID string) (err error) {
+	defer p.audit("MarkThreadRead", "thread_id", threadID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("MarkThreadRead"); err != nil {
+		return err
 	}
 
 	csThreadID, freeThreadID := cStringFree(threadID)
@@ -1311,9 +2618,14 @@ func (p *Platform) MarkThreadRead(threadID string) error {
 }
 
 // MarkThreadUnread marks a thread as unread from a specific post
-func (p *Platform) MarkThreadUnread(threadID, postID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) MarkThreadUnread(threadID, postID string) (err error) {
+	defer p.audit("MarkThreadUnread", "thread_id", threadID, "message_id", postID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("MarkThreadUnread"); err != nil {
+		return err
 	}
 
 	csThreadID, freeThreadID := cStringFree(threadID)
@@ -1330,11 +2642,34 @@ func (p *Platform) MarkThreadUnread(threadID, postID string) error {
 	return nil
 }
 
-// GetUserThreads retrieves all threads for a user with filtering options
-// Returns a JSON string containing thread information
-func (p *Platform) GetUserThreads(userID, teamID string, since uint64, deleted, unread bool, perPage, page uint32) (string, error) {
-	if p.handle == nil {
-		return "", ErrInvalidHandle
+// UserThreadsOptions filters and pages GetUserThreads
+type UserThreadsOptions struct {
+	Since      time.Time // only threads active after this time; zero for all
+	Deleted    bool      // include threads whose root was deleted
+	UnreadOnly bool      // only threads with unread replies
+	PerPage    uint32    // defaults to 25
+	Page       uint32    // 0-indexed
+}
+
+// GetUserThreads retrieves the threads a user follows in a team, most recently active first
+// Each thread carries its unread reply and mention counts, and the list the
+// user's totals, which is what a Threads sidebar view needs. A nil opts lists
+// the first page of all threads.
+func (p *Platform) GetUserThreads(userID, teamID string, opts *UserThreadsOptions) (*ThreadList, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if opts == nil {
+		opts = &UserThreadsOptions{}
+	}
+	perPage := opts.PerPage
+	if perPage == 0 {
+		perPage = 25
+	}
+	var since uint64
+	if !opts.Since.IsZero() {
+		since = uint64(opts.Since.UnixMilli())
 	}
 
 	csUserID, freeUserID := cStringFree(userID)
@@ -1344,12 +2679,12 @@ func (p *Platform) GetUserThreads(userID, teamID string, since uint64, deleted,
 	defer freeTeamID()
 
 	var deletedInt C.int
-	if deleted {
+	if opts.Deleted {
 		deletedInt = 1
 	}
 
 	var unreadInt C.int
-	if unread {
+	if opts.UnreadOnly {
 		unreadInt = 1
 	}
 
@@ -1361,22 +2696,27 @@ func (p *Platform) GetUserThreads(userID, teamID string, since uint64, deleted,
 		deletedInt,
 		unreadInt,
 		C.uint32_t(perPage),
-		C.uint32_t(page),
+		C.uint32_t(opts.Page),
 	)
 	if result == nil {
-		return "", getLastError()
+		return nil, getLastError()
 	}
 	defer C.communicator_free_string(result)
 
-	return C.GoString(result), nil
+	var list ThreadList
+	if err := p.decode([]byte(C.GoString(result)), &list); err != nil {
+		return nil, err
+	}
+
+	return &list, nil
 }
 
-// GetUserThread retrieves detailed information about a specific thread for a user
-// Returns a JSON string containing thread details
-func (p *Platform) GetUserThread(userID, teamID, threadID string) (string, error) {
-	if p.handle == nil {
-		return "", ErrInvalidHandle
+// GetUserThread retrieves a thread a user follows, with their unread counts
+func (p *Platform) GetUserThread(userID, teamID, threadID string) (*Thread, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	csUserID, freeUserID := cStringFree(userID)
 	defer freeUserID()
@@ -1389,17 +2729,27 @@ func (p *Platform) GetUserThread(userID, teamID, threadID string) (string, error
 
 	result := C.communicator_platform_get_user_thread(p.handle, csUserID, csTeamID, csThreadID)
 	if result == nil {
-		return "", getLastError()
+		return nil, getLastError()
 	}
 	defer C.communicator_free_string(result)
 
-	return C.GoString(result), nil
+	var thread Thread
+	if err := p.decode([]byte(C.GoString(result)), &thread); err != nil {
+		return nil, err
+	}
+
+	return &thread, nil
 }
 
 // MarkAllThreadsRead marks all threads as read for a user in a team
-func (p *Platform) MarkAllThreadsRead(userID, teamID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) MarkAllThreadsRead(userID, teamID string) (err error) {
+	defer p.audit("MarkAllThreadsRead", "user_id", userID, "team_id", teamID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("MarkAllThreadsRead"); err != nil {
+		return err
 	}
 
 	csUserID, freeUserID := cStringFree(userID)
@@ -1417,9 +2767,14 @@ func (p *Platform) MarkAllThreadsRead(userID, teamID string) error {
 }
 
 // CreateChannel creates a new regular channel (public or private)
-func (p *Platform) CreateChannel(teamID, name, displayName string, isPrivate bool) (*Channel, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+func (p *Platform) CreateChannel(teamID, name, displayName string, isPrivate bool) (_ *Channel, err error) {
+	defer p.audit("CreateChannel", "team_id", teamID, "channel_name", name)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("CreateChannel"); err != nil {
+		return nil, err
 	}
 
 	csTeamID, freeTeamID := cStringFree(teamID)
@@ -1444,7 +2799,7 @@ func (p *Platform) CreateChannel(teamID, name, displayName string, isPrivate boo
 
 	jsonStr := C.GoString(result)
 	var channel Channel
-	if err := json.Unmarshal([]byte(jsonStr), &channel); err != nil {
+	if err := p.decode([]byte(jsonStr), &channel); err != nil {
 		return nil, &PlatformError{Code: ErrorUnknown, Message: "failed to parse channel JSON: " + err.Error()}
 	}
 
@@ -1453,9 +2808,14 @@ func (p *Platform) CreateChannel(teamID, name, displayName string, isPrivate boo
 
 // UpdateChannel updates channel information (partial update)
 // Pass empty string for fields that should not be updated
-func (p *Platform) UpdateChannel(channelID, displayName, purpose, header string) (*Channel, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+func (p *Platform) UpdateChannel(channelID, displayName, purpose, header string) (_ *Channel, err error) {
+	defer p.audit("UpdateChannel", "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("UpdateChannel"); err != nil {
+		return nil, err
 	}
 
 	csChannelID, freeChannelID := cStringFree(channelID)
@@ -1487,7 +2847,7 @@ func (p *Platform) UpdateChannel(channelID, displayName, purpose, header string)
 
 	jsonStr := C.GoString(result)
 	var channel Channel
-	if err := json.Unmarshal([]byte(jsonStr), &channel); err != nil {
+	if err := p.decode([]byte(jsonStr), &channel); err != nil {
 		return nil, &PlatformError{Code: ErrorUnknown, Message: "failed to parse channel JSON: " + err.Error()}
 	}
 
@@ -1495,9 +2855,14 @@ func (p *Platform) UpdateChannel(channelID, displayName, purpose, header string)
 }
 
 // DeleteChannel deletes (archives) a channel
-func (p *Platform) DeleteChannel(channelID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) DeleteChannel(channelID string) (err error) {
+	defer p.audit("DeleteChannel", "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("DeleteChannel"); err != nil {
+		return err
 	}
 
 	csChannelID, freeChannelID := cStringFree(channelID)
@@ -1511,17 +2876,63 @@ func (p *Platform) DeleteChannel(channelID string) error {
 	return nil
 }
 
+// RestoreChannel restores (unarchives) a channel deleted with DeleteChannel
+func (p *Platform) RestoreChannel(channelID string) (_ *Channel, err error) {
+	defer p.audit("RestoreChannel", "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("RestoreChannel"); err != nil {
+		return nil, err
+	}
+
+	csChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	result := C.communicator_platform_restore_channel(p.handle, csChannelID)
+	if result == nil {
+		return nil, getLastError()
+	}
+	defer C.communicator_free_string(result)
+
+	var channel Channel
+	if err := p.decode([]byte(C.GoString(result)), &channel); err != nil {
+		return nil, &PlatformError{Code: ErrorUnknown, Message: "failed to parse channel JSON: " + err.Error()}
+	}
+
+	return &channel, nil
+}
+
+// Close destroys the platform and frees its resources, implementing io.Closer
+// It waits for calls in progress on other goroutines; later calls fail with
+// ErrClosed. Closing a closed platform does nothing. Event streams aren't
+// closed with it; close them first.
+func (p *Platform) Close() error {
+	if !p.acquireExclusive() {
+		return nil
+	}
+	defer p.releaseExclusive()
+	runtime.SetFinalizer(p, nil)
+	C.communicator_platform_destroy(p.handle)
+	p.handle = nil
+	return nil
+}
+
 // Destroy destroys the platform and frees its resources
+// It is Close without the error, kept for existing callers.
 func (p *Platform) Destroy() {
-	if p.handle != nil {
-		C.communicator_platform_destroy(p.handle)
-		p.handle = nil
-	}
+	p.Close()
 }
 
 var (
-	// ErrInvalidHandle is returned when the platform handle is invalid
-	ErrInvalidHandle = &PlatformError{Code: ErrorNullPointer, Message: "invalid platform handle"}
+	// ErrInvalidHandle is ErrClosed under its former name
+	//
+	// Deprecated: Use ErrClosed.
+	ErrInvalidHandle = ErrClosed
+
+	// ErrEmptyImage is returned when an image upload is given no data
+	ErrEmptyImage = &PlatformError{Code: ErrorInvalidArg, Message: "image data is empty"}
 )
 
 // PlatformError represents a platform error
@@ -1533,3 +2944,13 @@ type PlatformError struct {
 func (e *PlatformError) Error() string {
 	return e.Message
 }
+
+// Is reports whether target is the sentinel error for e's code, such as ErrNotFound
+func (e *PlatformError) Is(target error) bool {
+	return codeMatches(e.Code, target)
+}
+
+// Retryable reports whether trying the call again later may succeed
+func (e *PlatformError) Retryable() bool {
+	return e.Code.Retryable()
+}
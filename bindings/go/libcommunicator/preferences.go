@@ -73,11 +73,36 @@ func (c *ChannelNotifyProps) WithMarkUnread(level NotificationLevel) *ChannelNot
 	return c
 }
 
+// ReplyNotifyLevel controls which thread replies trigger a notification
+type ReplyNotifyLevel string
+
+const (
+	ReplyNotifyNever ReplyNotifyLevel = "never" // Only replies that mention the user
+	ReplyNotifyRoot  ReplyNotifyLevel = "root"  // Replies to threads the user started
+	ReplyNotifyAny   ReplyNotifyLevel = "any"   // Replies to threads the user participated in
+)
+
+// UserNotifyProps represents account-wide notification preferences
+// Nil fields are unknown when read and left unchanged when updating
+type UserNotifyProps struct {
+	Desktop               *NotificationLevel `json:"desktop,omitempty"`
+	DesktopSound          *bool              `json:"desktop_sound,omitempty"`
+	Email                 *bool              `json:"email,omitempty"`
+	EmailBatchingInterval *uint64            `json:"email_batching_interval,omitempty"` // Seconds; 0 sends immediately
+	Push                  *NotificationLevel `json:"push,omitempty"`
+	PushStatus            *string            `json:"push_status,omitempty"` // "online", "away" or "offline"
+	MentionKeys           []string           `json:"mention_keys,omitempty"`
+	ChannelMentions       *bool              `json:"channel_mentions,omitempty"`
+	FirstNameMention      *bool              `json:"first_name_mention,omitempty"`
+	ReplyNotifications    *ReplyNotifyLevel  `json:"reply_notifications,omitempty"`
+}
+
 // GetUserPreferences retrieves all preferences for a user
 func (p *Platform) GetUserPreferences(userID string) ([]UserPreference, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cUserID, freeUserID := cStringFree(userID)
 	defer freeUserID()
@@ -91,7 +116,7 @@ func (p *Platform) GetUserPreferences(userID string) ([]UserPreference, error) {
 	jsonStr := C.GoString(cstr)
 
 	var prefs []UserPreference
-	if err := json.Unmarshal([]byte(jsonStr), &prefs); err != nil {
+	if err := p.decode([]byte(jsonStr), &prefs); err != nil {
 		return nil, err
 	}
 
@@ -99,9 +124,14 @@ func (p *Platform) GetUserPreferences(userID string) ([]UserPreference, error) {
 }
 
 // SetUserPreferences sets user preferences
-func (p *Platform) SetUserPreferences(userID string, prefs []UserPreference) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) SetUserPreferences(userID string, prefs []UserPreference) (err error) {
+	defer p.audit("SetUserPreferences", "user_id", userID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("SetUserPreferences"); err != nil {
+		return err
 	}
 
 	// Marshal preferences to JSON
@@ -125,9 +155,14 @@ func (p *Platform) SetUserPreferences(userID string, prefs []UserPreference) err
 }
 
 // MuteChannel mutes a channel for the current user
-func (p *Platform) MuteChannel(channelID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) MuteChannel(channelID string) (err error) {
+	defer p.audit("MuteChannel", "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("MuteChannel"); err != nil {
+		return err
 	}
 
 	cChannelID, free := cStringFree(channelID)
@@ -142,9 +177,14 @@ func (p *Platform) MuteChannel(channelID string) error {
 }
 
 // UnmuteChannel unmutes a channel for the current user
-func (p *Platform) UnmuteChannel(channelID string) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) UnmuteChannel(channelID string) (err error) {
+	defer p.audit("UnmuteChannel", "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("UnmuteChannel"); err != nil {
+		return err
 	}
 
 	cChannelID, free := cStringFree(channelID)
@@ -159,9 +199,14 @@ func (p *Platform) UnmuteChannel(channelID string) error {
 }
 
 // UpdateChannelNotifyProps updates channel notification properties
-func (p *Platform) UpdateChannelNotifyProps(channelID string, props *ChannelNotifyProps) error {
-	if p.handle == nil {
-		return ErrInvalidHandle
+func (p *Platform) UpdateChannelNotifyProps(channelID string, props *ChannelNotifyProps) (err error) {
+	defer p.audit("UpdateChannelNotifyProps", "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("UpdateChannelNotifyProps"); err != nil {
+		return err
 	}
 
 	// Marshal props to JSON
@@ -217,3 +262,58 @@ func UnmutedChannelNotifyProps() *ChannelNotifyProps {
 		IgnoreChannelMentions: &ignoreMentions,
 	}
 }
+
+// GetUserNotifyProps retrieves the current user's account-wide notification preferences
+func (p *Platform) GetUserNotifyProps() (*UserNotifyProps, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cstr := C.communicator_platform_get_user_notify_props(p.handle)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var props UserNotifyProps
+	if err := p.decode([]byte(C.GoString(cstr)), &props); err != nil {
+		return nil, err
+	}
+
+	return &props, nil
+}
+
+// UpdateUserNotifyProps updates the current user's account-wide notification preferences
+// Only non-nil fields are changed; the resulting preferences are returned
+func (p *Platform) UpdateUserNotifyProps(props *UserNotifyProps) (_ *UserNotifyProps, err error) {
+	defer p.audit("UpdateUserNotifyProps")(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("UpdateUserNotifyProps"); err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.Marshal(props)
+	if err != nil {
+		return nil, err
+	}
+
+	cJSON, freeJSON := cStringFree(string(jsonBytes))
+	defer freeJSON()
+
+	cstr := C.communicator_platform_update_user_notify_props(p.handle, cJSON)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var updated UserNotifyProps
+	if err := p.decode([]byte(C.GoString(cstr)), &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
@@ -0,0 +1,145 @@
+package libcommunicator
+
+/*
+#include <communicator.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"time"
+)
+
+// Priority levels
+const (
+	PriorityStandard  = "standard"
+	PriorityImportant = "important"
+	PriorityUrgent    = "urgent"
+)
+
+// MessagePriority is the priority label and acknowledgement request of a message
+type MessagePriority struct {
+	Priority     string `json:"priority"` // PriorityStandard, PriorityImportant or PriorityUrgent
+	RequestedAck bool   `json:"requested_ack"`
+	// PersistentNotifications re-notifies mentioned users until they act (urgent only)
+	PersistentNotifications bool `json:"persistent_notifications"`
+}
+
+// MessageAcknowledgement records that a user acknowledged a message
+type MessageAcknowledgement struct {
+	UserID         string    `json:"user_id"`
+	MessageID      string    `json:"message_id"`
+	AcknowledgedAt time.Time `json:"acknowledged_at"`
+}
+
+// Priority returns the priority of a message, or nil if it has none
+func (m *Message) Priority() *MessagePriority {
+	if m.Metadata == nil {
+		return nil
+	}
+	return m.Metadata.Priority
+}
+
+// Acknowledgements returns the acknowledgements recorded in a message's metadata
+// Use GetMessageAcknowledgements for an up-to-date list.
+func (m *Message) Acknowledgements() []MessageAcknowledgement {
+	if m.Metadata == nil {
+		return nil
+	}
+	return m.Metadata.Acknowledgements
+}
+
+// SendMessageWithPriority sends a message labelled important or urgent, optionally asking readers to acknowledge it
+// Returns an error matching ErrUnsupportedByServer on servers without message priority
+func (p *Platform) SendMessageWithPriority(channelID, text string, priority MessagePriority) (_ *Message, err error) {
+	defer p.audit("SendMessageWithPriority", "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("SendMessageWithPriority"); err != nil {
+		return nil, err
+	}
+
+	if priority.Priority == "" {
+		priority.Priority = PriorityStandard
+	}
+	priorityJSON, err := json.Marshal(priority)
+	if err != nil {
+		return nil, err
+	}
+
+	csChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	csText, freeText := cStringFree(text)
+	defer freeText()
+
+	csPriority, freePriority := cStringFree(string(priorityJSON))
+	defer freePriority()
+
+	cstr := C.communicator_platform_send_message_with_priority(p.handle, csChannelID, csText, csPriority)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var msg Message
+	if err := p.decode([]byte(C.GoString(cstr)), &msg); err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+// AckMessage acknowledges a message as the current user
+func (p *Platform) AckMessage(messageID string) (_ *MessageAcknowledgement, err error) {
+	defer p.audit("AckMessage", "message_id", messageID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("AckMessage"); err != nil {
+		return nil, err
+	}
+
+	csMessageID, freeMessageID := cStringFree(messageID)
+	defer freeMessageID()
+
+	cstr := C.communicator_platform_ack_message(p.handle, csMessageID)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var ack MessageAcknowledgement
+	if err := p.decode([]byte(C.GoString(cstr)), &ack); err != nil {
+		return nil, err
+	}
+
+	return &ack, nil
+}
+
+// GetMessageAcknowledgements returns who acknowledged a message and when, oldest first
+func (p *Platform) GetMessageAcknowledgements(messageID string) ([]MessageAcknowledgement, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	csMessageID, freeMessageID := cStringFree(messageID)
+	defer freeMessageID()
+
+	cstr := C.communicator_platform_get_message_acknowledgements(p.handle, csMessageID)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var acks []MessageAcknowledgement
+	if err := p.decode([]byte(C.GoString(cstr)), &acks); err != nil {
+		return nil, err
+	}
+
+	return acks, nil
+}
@@ -0,0 +1,93 @@
+package libcommunicator
+
+import (
+	"context"
+	"strings"
+)
+
+// priorityLane decides which events skip the regular queue: messages in
+// direct and group message channels, and messages mentioning the current user
+// It is only used by the poll goroutine and needs no lock.
+type priorityLane struct {
+	events chan *Event
+	// username and userID are the current user's, looked up on the first message
+	username string
+	userID   string
+	// direct caches whether a channel is a direct or group message channel
+	direct map[string]bool
+}
+
+func newPriorityLane(bufferSize int) *priorityLane {
+	return &priorityLane{events: make(chan *Event, bufferSize), direct: make(map[string]bool)}
+}
+
+// Priority returns the channel of events delivered ahead of Events when
+// EventStreamOptions.PriorityLane is set, or nil otherwise
+// Read it before Events so direct messages and mentions are handled first;
+// EventRouter.Run and Mux do. Priority events are never dropped: polling
+// waits while this channel is full.
+func (s *EventStream) Priority() <-chan *Event {
+	if s.lane == nil {
+		return nil
+	}
+	return s.lane.events
+}
+
+// isPriority reports whether an event belongs in the priority lane
+func (s *EventStream) isPriority(event *Event) bool {
+	if event.Type != EventMessagePosted {
+		return false
+	}
+	msg, err := eventMessage(event)
+	if err != nil {
+		return false
+	}
+
+	lane := s.lane
+	if lane.userID == "" {
+		if me, err := s.platform.GetCurrentUser(); err == nil {
+			lane.userID, lane.username = me.ID, strings.ToLower(me.Username)
+		}
+	}
+	// The user's own messages never need attention
+	if lane.userID != "" && msg.SenderID == lane.userID {
+		return false
+	}
+
+	direct, ok := lane.direct[msg.ChannelID]
+	if !ok {
+		if channel, err := s.platform.GetChannel(msg.ChannelID); err == nil {
+			direct = channel.Type == ChannelTypeDirectMessage || channel.Type == ChannelTypeGroupMessage
+			lane.direct[msg.ChannelID] = direct
+		}
+	}
+	if direct {
+		return true
+	}
+	return lane.username != "" && mentions(msg.Text, lane.username)
+}
+
+// mentions reports whether text @-mentions username, which must be lowercase
+func mentions(text, username string) bool {
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		// A trailing dot ends the sentence, not the username
+		if strings.ToLower(strings.TrimRight(match[1], ".")) == username {
+			return true
+		}
+	}
+	return false
+}
+
+// deliverPriority sends an event to the priority lane, waiting for room;
+// it returns false once the stream should stop
+func (s *EventStream) deliverPriority(ctx context.Context, event *Event) bool {
+	select {
+	case s.lane.events <- event:
+		s.prioritized.Add(1)
+		return true
+	case <-ctx.Done():
+		return false
+	case <-s.done:
+		return false
+	}
+}
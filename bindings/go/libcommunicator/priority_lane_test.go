@@ -0,0 +1,90 @@
+package libcommunicator
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"@bot deploy", true},
+		{"ping @Bot.", true},
+		{"(@bot)", true},
+		{"cc @bot, @alice", true},
+		{"@bots please", false},
+		{"mail bot@example.com", false},
+		{"bot without an at sign", false},
+		{"@bot-ops on call", false},
+	}
+	for _, tt := range tests {
+		if got := mentions(tt.text, "bot"); got != tt.want {
+			t.Errorf("mentions(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestIsPriority(t *testing.T) {
+	posted := func(sender, channel, text string) *Event {
+		return &Event{Type: EventMessagePosted, Data: map[string]interface{}{
+			"id": "p1", "sender_id": sender, "channel_id": channel, "text": text,
+		}}
+	}
+	tests := []struct {
+		name  string
+		event *Event
+		want  bool
+	}{
+		{"direct message", posted("alice", "dm", "hi"), true},
+		{"own direct message", posted("me", "dm", "hi"), false},
+		{"mention", posted("alice", "town", "@bot help"), true},
+		{"own mention", posted("me", "town", "@bot help"), false},
+		{"plain message", posted("alice", "town", "hello"), false},
+		{"channel lookup fails", posted("alice", "unknown", "hello"), false},
+		{"not a post", &Event{Type: EventUserTyping, ChannelID: "dm"}, false},
+		{"undecodable post", &Event{Type: EventMessagePosted, Data: "text"}, false},
+	}
+
+	s := &EventStream{platform: &Platform{}, lane: newPriorityLane(1)}
+	s.lane.userID, s.lane.username = "me", "bot"
+	s.lane.direct["dm"] = true
+	s.lane.direct["town"] = false
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.isPriority(tt.event); got != tt.want {
+				t.Fatalf("isPriority = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	// A failed lookup is retried on the next message rather than cached
+	if _, ok := s.lane.direct["unknown"]; ok {
+		t.Fatal("failed channel lookup was cached")
+	}
+}
+
+func TestDeliverPriority(t *testing.T) {
+	s := &EventStream{done: make(chan struct{}), lane: newPriorityLane(1)}
+	ctx := context.Background()
+
+	if !s.deliverPriority(ctx, numberedEvent(1)) {
+		t.Fatal("delivery into an empty lane failed")
+	}
+	if got := <-s.Priority(); got.Seq != 1 {
+		t.Fatalf("priority event = %d, want 1", got.Seq)
+	}
+	s.deliverPriority(ctx, numberedEvent(2))
+
+	// A full lane waits rather than dropping, until the stream stops
+	close(s.done)
+	if s.deliverPriority(ctx, numberedEvent(3)) {
+		t.Fatal("delivery into a full lane of a closed stream succeeded")
+	}
+	if n := s.prioritized.Load(); n != 2 {
+		t.Fatalf("prioritized = %d, want 2", n)
+	}
+	if (&EventStream{}).Priority() != nil {
+		t.Fatal("Priority without a lane is not nil")
+	}
+}
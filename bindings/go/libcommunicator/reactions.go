@@ -0,0 +1,179 @@
+package libcommunicator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReactionSummary is the net change in a message's reactions over one window
+type ReactionSummary struct {
+	MessageID string
+	// Counts maps emoji names to the net number of reactions added, negative
+	// when more were removed than added; emojis that netted to zero are omitted
+	Counts map[string]int
+	// UserIDs are the users who reacted or unreacted, in order of first appearance
+	UserIDs []string
+	// Events is the number of reaction events folded into the summary
+	Events int
+	From   time.Time
+	To     time.Time
+}
+
+// String renders the summary, e.g. "post abc: +5 :thumbsup:, +2 :tada: in last 2s"
+func (s *ReactionSummary) String() string {
+	emojis := make([]string, 0, len(s.Counts))
+	for emoji := range s.Counts {
+		emojis = append(emojis, emoji)
+	}
+	// Biggest changes first, then by name so the output is stable
+	sort.Slice(emojis, func(i, j int) bool {
+		a, b := s.Counts[emojis[i]], s.Counts[emojis[j]]
+		if abs(a) != abs(b) {
+			return abs(a) > abs(b)
+		}
+		return emojis[i] < emojis[j]
+	})
+
+	parts := make([]string, len(emojis))
+	for i, emoji := range emojis {
+		parts[i] = fmt.Sprintf("%+d :%s:", s.Counts[emoji], emoji)
+	}
+	return fmt.Sprintf("post %s: %s in last %s", s.MessageID, strings.Join(parts, ", "), s.To.Sub(s.From).Round(100*time.Millisecond))
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// ReactionSummaryHandler handles a summary of a burst of reactions
+type ReactionSummaryHandler func(*ReactionSummary)
+
+// ReactionAggregator batches bursts of reaction events on the same message
+// into one summary per window, so popular posts don't flood handlers
+// The window for a message starts with its first reaction event; the summary
+// is delivered when the window ends. Summaries whose counts all net to zero
+// are dropped.
+type ReactionAggregator struct {
+	window  time.Duration
+	handler ReactionSummaryHandler
+
+	mu      sync.Mutex
+	pending map[string]*pendingReactions
+	closed  bool
+}
+
+type pendingReactions struct {
+	summary *ReactionSummary
+	users   map[string]bool
+	timer   *time.Timer
+}
+
+// NewReactionAggregator creates an aggregator that delivers summaries to handler
+// window is how long reactions on a message are collected; 0 means 2 seconds.
+func NewReactionAggregator(window time.Duration, handler ReactionSummaryHandler) *ReactionAggregator {
+	if window <= 0 {
+		window = 2 * time.Second
+	}
+	return &ReactionAggregator{
+		window:  window,
+		handler: handler,
+		pending: make(map[string]*pendingReactions),
+	}
+}
+
+// Register installs the aggregator as a reaction added and removed handler on the router
+func (a *ReactionAggregator) Register(r *EventRouter) {
+	r.On(EventReactionAdded, a.Handle)
+	r.On(EventReactionRemoved, a.Handle)
+}
+
+// Handle folds a reaction event into its message's pending summary
+// Events of other types are ignored.
+func (a *ReactionAggregator) Handle(event *Event) {
+	var delta int
+	switch event.Type {
+	case EventReactionAdded:
+		delta = 1
+	case EventReactionRemoved:
+		delta = -1
+	default:
+		return
+	}
+	if event.MessageID == "" || event.EmojiName == "" {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return
+	}
+
+	now := time.Now()
+	p, ok := a.pending[event.MessageID]
+	if !ok {
+		p = &pendingReactions{
+			summary: &ReactionSummary{MessageID: event.MessageID, Counts: make(map[string]int), From: now},
+			users:   make(map[string]bool),
+		}
+		messageID := event.MessageID
+		p.timer = time.AfterFunc(a.window, func() { a.flush(messageID) })
+		a.pending[messageID] = p
+	}
+
+	s := p.summary
+	s.Counts[event.EmojiName] += delta
+	if s.Counts[event.EmojiName] == 0 {
+		delete(s.Counts, event.EmojiName)
+	}
+	if event.UserID != "" && !p.users[event.UserID] {
+		p.users[event.UserID] = true
+		s.UserIDs = append(s.UserIDs, event.UserID)
+	}
+	s.Events++
+}
+
+// Flush delivers every pending summary now, without waiting for the windows to end
+func (a *ReactionAggregator) Flush() {
+	a.mu.Lock()
+	ids := make([]string, 0, len(a.pending))
+	for id := range a.pending {
+		ids = append(ids, id)
+	}
+	a.mu.Unlock()
+
+	for _, id := range ids {
+		a.flush(id)
+	}
+}
+
+// Close flushes pending summaries and stops the aggregator; later events are ignored
+func (a *ReactionAggregator) Close() {
+	a.mu.Lock()
+	a.closed = true
+	a.mu.Unlock()
+	a.Flush()
+}
+
+func (a *ReactionAggregator) flush(messageID string) {
+	a.mu.Lock()
+	p, ok := a.pending[messageID]
+	if ok {
+		delete(a.pending, messageID)
+		p.timer.Stop()
+	}
+	a.mu.Unlock()
+
+	// The timer and Flush race for the same window; whoever loses finds nothing
+	if !ok || len(p.summary.Counts) == 0 {
+		return
+	}
+	p.summary.To = time.Now()
+	a.handler(p.summary)
+}
@@ -0,0 +1,53 @@
+package libcommunicator
+
+import "errors"
+
+// ErrReadOnly matches every ReadOnlyError, e.g. errors.Is(err, ErrReadOnly)
+// Like a permission error from the server, a ReadOnlyError also matches
+// ErrPermissionDenied.
+var ErrReadOnly = &PlatformError{Code: ErrorPermDenied, Message: "platform is in read-only mode"}
+
+// ReadOnlyError is returned by a mutating operation while read-only mode is on
+type ReadOnlyError struct {
+	// Operation is the Platform method that was rejected, e.g. "SendMessage"
+	Operation string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return e.Operation + ": " + ErrReadOnly.Message
+}
+
+// Is reports whether target is ErrReadOnly or, like ErrReadOnly, the
+// sentinel for its code, ErrPermissionDenied
+func (e *ReadOnlyError) Is(target error) bool {
+	return target == ErrReadOnly || codeMatches(ErrReadOnly.Code, target)
+}
+
+// IsReadOnlyError reports whether err was caused by read-only mode
+func IsReadOnlyError(err error) bool {
+	return errors.Is(err, ErrReadOnly)
+}
+
+// SetReadOnly turns read-only mode on or off
+// While on, every operation that changes server state (sending, editing and
+// deleting messages, reactions, acknowledgements, membership, channel and
+// team changes, uploads, slash commands, webhooks, bots, statuses,
+// preferences and read markers) fails with a ReadOnlyError before anything
+// is sent. Reads, event subscriptions and local settings still work, which
+// suits analytics and export tools that must never write to production.
+func (p *Platform) SetReadOnly(readOnly bool) {
+	p.readOnly.Store(readOnly)
+}
+
+// IsReadOnly reports whether read-only mode is on
+func (p *Platform) IsReadOnly() bool {
+	return p.readOnly.Load()
+}
+
+// checkWritable rejects operation if the platform is in read-only mode
+func (p *Platform) checkWritable(operation string) error {
+	if p.readOnly.Load() {
+		return &ReadOnlyError{Operation: operation}
+	}
+	return nil
+}
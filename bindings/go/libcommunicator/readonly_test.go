@@ -0,0 +1,22 @@
+package libcommunicator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestReadOnlyErrorMatches(t *testing.T) {
+	err := fmt.Errorf("send: %w", &ReadOnlyError{Operation: "SendMessage"})
+
+	if !errors.Is(err, ErrReadOnly) || !IsReadOnlyError(err) {
+		t.Fatal("a ReadOnlyError doesn't match ErrReadOnly")
+	}
+	// The same code matching as ErrReadOnly itself
+	if !errors.Is(err, ErrPermissionDenied) || !errors.Is(ErrReadOnly, ErrPermissionDenied) {
+		t.Fatal("a ReadOnlyError doesn't match ErrPermissionDenied")
+	}
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrClosed) {
+		t.Fatal("a ReadOnlyError matches another sentinel")
+	}
+}
@@ -0,0 +1,95 @@
+package libcommunicator
+
+import (
+	"context"
+	"fmt"
+)
+
+// unclaimedResponseLimit is the number of responses kept for AwaitResponse
+// calls that start after their response was polled
+const unclaimedResponseLimit = 64
+
+// ResponseError is a WebSocket action that the server answered with a failure
+type ResponseError struct {
+	Seq     int64
+	Status  string
+	Message string
+}
+
+func (e *ResponseError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("websocket request %d: %s", e.Seq, e.Status)
+	}
+	return fmt.Sprintf("websocket request %d: %s: %s", e.Seq, e.Status, e.Message)
+}
+
+// AwaitResponse waits for the response to the WebSocket request with the
+// sequence number returned by e.g. RequestAllStatuses
+// The response's result is in Event.Data. A response with a failure status
+// is returned along with a *ResponseError. Events must be polled meanwhile,
+// e.g. by an EventStream; the response is still delivered there as well.
+func (p *Platform) AwaitResponse(ctx context.Context, seq int64) (*Event, error) {
+	if p.destroyed() {
+		return nil, ErrClosed
+	}
+
+	p.responsesMu.Lock()
+	if event, ok := p.unclaimedResponses[seq]; ok {
+		delete(p.unclaimedResponses, seq)
+		p.responsesMu.Unlock()
+		return event, responseErr(event)
+	}
+	if p.responseWaiters == nil {
+		p.responseWaiters = make(map[int64]chan *Event)
+	}
+	if _, ok := p.responseWaiters[seq]; ok {
+		p.responsesMu.Unlock()
+		return nil, fmt.Errorf("websocket request %d: already awaited", seq)
+	}
+	ch := make(chan *Event, 1)
+	p.responseWaiters[seq] = ch
+	p.responsesMu.Unlock()
+
+	select {
+	case event := <-ch:
+		return event, responseErr(event)
+	case <-ctx.Done():
+		p.responsesMu.Lock()
+		delete(p.responseWaiters, seq)
+		p.responsesMu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// routeResponse hands a polled response event to the AwaitResponse call
+// waiting for it, or keeps it for one that hasn't started yet
+func (p *Platform) routeResponse(event *Event) {
+	p.responsesMu.Lock()
+	defer p.responsesMu.Unlock()
+
+	if ch, ok := p.responseWaiters[event.SeqReply]; ok {
+		delete(p.responseWaiters, event.SeqReply)
+		ch <- event
+		return
+	}
+
+	if p.unclaimedResponses == nil {
+		p.unclaimedResponses = make(map[int64]*Event)
+	}
+	// Sequence numbers only grow, so the lowest is the oldest
+	if len(p.unclaimedResponses) >= unclaimedResponseLimit {
+		oldest := event.SeqReply
+		for seq := range p.unclaimedResponses {
+			oldest = min(oldest, seq)
+		}
+		delete(p.unclaimedResponses, oldest)
+	}
+	p.unclaimedResponses[event.SeqReply] = event
+}
+
+func responseErr(event *Event) error {
+	if event.Status == "OK" {
+		return nil
+	}
+	return &ResponseError{Seq: event.SeqReply, Status: event.Status, Message: event.Error}
+}
@@ -0,0 +1,85 @@
+package libcommunicator
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// HandlerError reports an event handler that returned an error or panicked
+type HandlerError struct {
+	Event *Event
+	Err   error
+	// Panic is the value the handler panicked with, nil if it returned Err
+	Panic interface{}
+	// Stack is the handler's stack trace at the panic
+	Stack []byte
+}
+
+func (e *HandlerError) Error() string {
+	if e.Panic != nil {
+		return fmt.Sprintf("%s event: handler panicked: %v", e.Event.Type, e.Panic)
+	}
+	return fmt.Sprintf("%s event: %v", e.Event.Type, e.Err)
+}
+
+func (e *HandlerError) Unwrap() error {
+	return e.Err
+}
+
+// routerErrorBuffer is the capacity of the EventRouter Errors channel
+const routerErrorBuffer = 64
+
+// OnWithError registers an error-returning handler for a specific event type
+// Returned errors are reported like panics: to the SetErrorHandler callback,
+// or on the Errors channel. Use OnWithRetry to retry failures instead.
+func (r *EventRouter) OnWithError(eventType string, handler EventErrorHandler) *Subscription {
+	return r.On(eventType, func(event *Event) {
+		if err := handler(event); err != nil {
+			r.reportError(&HandlerError{Event: event, Err: err})
+		}
+	})
+}
+
+// SetErrorHandler sets the callback receiving handler errors and panics, and
+// the event stream errors seen by Run, in place of the Errors channel
+// The callback runs on the goroutine of the failed handler; nil restores the channel.
+func (r *EventRouter) SetErrorHandler(handler func(error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onError = handler
+}
+
+// Errors returns the channel receiving handler errors and panics, and the
+// event stream errors seen by Run, while no SetErrorHandler callback is set
+// Errors are dropped when the channel is full.
+func (r *EventRouter) Errors() <-chan error {
+	return r.errors
+}
+
+// reportError passes an error to the error callback or the Errors channel
+func (r *EventRouter) reportError(err error) {
+	r.mu.RLock()
+	onError := r.onError
+	r.mu.RUnlock()
+
+	if onError != nil {
+		onError(err)
+		return
+	}
+	select {
+	case r.errors <- err:
+	default:
+	}
+}
+
+// call runs a handler, recovering a panic so a buggy handler can't take the
+// process down
+func (r *EventRouter) call(handler EventHandler, event *Event) {
+	defer func() {
+		if v := recover(); v != nil {
+			r.reportError(&HandlerError{Event: event, Panic: v, Stack: debug.Stack()})
+		}
+	}()
+	handler(event)
+}
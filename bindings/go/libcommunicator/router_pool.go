@@ -0,0 +1,174 @@
+package libcommunicator
+
+import (
+	"hash/fnv"
+	"slices"
+	"sync"
+)
+
+// HandlerOptions controls how a handler registered with OnWithOptions is run
+type HandlerOptions struct {
+	// Concurrency is the number of workers running the handler (default 1)
+	Concurrency int
+	// OrderByChannel guarantees events for the same channel are handled one
+	// at a time in arrival order; events for different channels still run in parallel
+	OrderByChannel bool
+	// QueueSize is the number of events buffered per worker before Handle
+	// blocks (default 64)
+	QueueSize int
+}
+
+// handlerPool runs a handler on a fixed set of workers
+type handlerPool struct {
+	handler EventHandler
+	ordered bool
+	queues  []chan poolJob
+	wg      sync.WaitGroup
+	// mu is held for reading while queueing, so stop can close the queues
+	mu      sync.RWMutex
+	stopped bool
+}
+
+// poolJob is a queued event and the function to call once it is handled, if any
+type poolJob struct {
+	event *Event
+	done  func()
+}
+
+func newHandlerPool(handler EventHandler, opts HandlerOptions) *handlerPool {
+	workers := opts.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	queueSize := opts.QueueSize
+	if queueSize < 1 {
+		queueSize = 64
+	}
+
+	pool := &handlerPool{handler: handler, ordered: opts.OrderByChannel}
+
+	// Ordered pools give each worker its own queue and pin channels to
+	// workers; unordered pools share a single queue
+	queueCount := 1
+	if pool.ordered {
+		queueCount = workers
+	}
+	pool.queues = make([]chan poolJob, queueCount)
+	for i := range pool.queues {
+		pool.queues[i] = make(chan poolJob, queueSize)
+	}
+
+	for i := 0; i < workers; i++ {
+		queue := pool.queues[i%queueCount]
+		pool.wg.Add(1)
+		go func() {
+			defer pool.wg.Done()
+			for job := range queue {
+				pool.handler(job.event)
+				if job.done != nil {
+					job.done()
+				}
+			}
+		}()
+	}
+
+	return pool
+}
+
+// dispatch queues an event, blocking while the target queue is full so
+// that slow handlers apply backpressure instead of dropping events
+// A stopped pool skips the event, calling done right away.
+func (p *handlerPool) dispatch(event *Event, done func()) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.stopped {
+		if done != nil {
+			done()
+		}
+		return
+	}
+
+	queue := p.queues[0]
+	if p.ordered {
+		h := fnv.New32a()
+		h.Write([]byte(eventChannelID(event)))
+		queue = p.queues[h.Sum32()%uint32(len(p.queues))]
+	}
+	queue <- poolJob{event: event, done: done}
+}
+
+// stop stops accepting events; the workers exit once the queued events are handled
+func (p *handlerPool) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.stopped {
+		p.stopped = true
+		for _, queue := range p.queues {
+			close(queue)
+		}
+	}
+}
+
+// close stops accepting events and waits for queued events to be handled
+func (p *handlerPool) close() {
+	p.stop()
+	p.wg.Wait()
+}
+
+// eventChannelID returns the channel an event belongs to, looking inside
+// message payloads when the event itself does not carry a channel ID
+func eventChannelID(event *Event) string {
+	if event.ChannelID != "" {
+		return event.ChannelID
+	}
+	if data, ok := event.Data.(map[string]interface{}); ok {
+		if channelID, ok := data["channel_id"].(string); ok {
+			return channelID
+		}
+	}
+	return ""
+}
+
+// OnWithOptions registers a handler that runs on its own worker pool
+// Handle returns as soon as the event is queued, so a slow handler no longer
+// holds up handlers for other event types; Run acknowledges the event only
+// once the pool has handled it. Unsubscribing stops the handler's workers
+// once the events already queued for it are handled; Close drains them all.
+func (r *EventRouter) OnWithOptions(eventType string, handler EventHandler, opts HandlerOptions) *Subscription {
+	pool := newHandlerPool(func(event *Event) { r.call(handler, event) }, opts)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pools = append(r.pools, pool)
+
+	h := r.newHandler(pool.handler)
+	h.pool = pool
+	r.handlers[eventType] = append(r.handlers[eventType], h)
+	return &Subscription{router: r, ids: []uint64{h.id}}
+}
+
+// stopPool drains and stops the pool of an unsubscribed handler
+func (r *EventRouter) stopPool(pool *handlerPool) {
+	pool.close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pools = slices.DeleteFunc(r.pools, func(p *handlerPool) bool { return p == pool })
+}
+
+// Close waits for events queued on OnWithOptions handlers to be handled and
+// stops their workers. Events routed after Close skip those handlers, so
+// stop the event source first. Handlers registered with OnWithRetry or wrapped with the
+// router's WithRetry stop waiting to retry and dead-letter their events.
+func (r *EventRouter) Close() {
+	r.closeOnce.Do(func() { close(r.done) })
+
+	r.mu.Lock()
+	pools := r.pools
+	r.pools = nil
+	r.mu.Unlock()
+
+	for _, pool := range pools {
+		pool.close()
+	}
+}
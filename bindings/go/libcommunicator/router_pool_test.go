@@ -0,0 +1,98 @@
+package libcommunicator
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// poolCount returns the number of pools the router still runs
+func poolCount(r *EventRouter) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.pools)
+}
+
+func waitForPools(t *testing.T, r *EventRouter, want int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for poolCount(r) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("router runs %d pools, want %d", poolCount(r), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestUnsubscribeStopsHandlerPool(t *testing.T) {
+	r := NewEventRouter()
+	defer r.Close()
+
+	var handled atomic.Int32
+	sub := r.OnWithOptions(EventMessagePosted, func(*Event) {
+		handled.Add(1)
+	}, HandlerOptions{Concurrency: 2})
+	r.OnWithOptions(EventUserTyping, func(*Event) {}, HandlerOptions{})
+
+	r.Handle(&Event{Type: EventMessagePosted})
+	sub.Unsubscribe()
+	waitForPools(t, r, 1)
+	if handled.Load() != 1 {
+		t.Fatalf("handled %d events, want the one queued before Unsubscribe", handled.Load())
+	}
+
+	r.Handle(&Event{Type: EventMessagePosted})
+	if handled.Load() != 1 {
+		t.Fatal("an unsubscribed handler received an event")
+	}
+}
+
+func TestHandlerCanUnsubscribeItself(t *testing.T) {
+	r := NewEventRouter()
+	defer r.Close()
+
+	var sub *Subscription
+	subscribed := make(chan struct{})
+	sub = r.OnWithOptions(EventMessagePosted, func(*Event) {
+		<-subscribed
+		sub.Unsubscribe()
+	}, HandlerOptions{QueueSize: 1})
+	close(subscribed)
+
+	// The second event waits in the queue and the third blocks Handle while
+	// the handler unsubscribes
+	for i := 0; i < 3; i++ {
+		r.Handle(&Event{Type: EventMessagePosted})
+	}
+	waitForPools(t, r, 0)
+}
+
+func TestRemoveAllStopsHandlerPools(t *testing.T) {
+	before := runtime.NumGoroutine()
+	r := NewEventRouter()
+	defer r.Close()
+
+	var handled atomic.Int32
+	count := func(*Event) { handled.Add(1) }
+	r.OnWithOptions(EventMessagePosted, count, HandlerOptions{Concurrency: 3})
+	r.OnWithOptions(EventMessagePosted, count, HandlerOptions{Concurrency: 2, OrderByChannel: true})
+	r.On(EventMessagePosted, count)
+	r.OnWithOptions(EventUserTyping, count, HandlerOptions{})
+
+	r.Handle(&Event{Type: EventMessagePosted, ChannelID: "c1"})
+	r.RemoveAll(EventMessagePosted)
+	waitForPools(t, r, 1)
+	if handled.Load() != 3 {
+		t.Fatalf("handled %d events, want the ones queued before RemoveAll", handled.Load())
+	}
+
+	// Only the EventUserTyping worker is left running
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before+1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines running, want at most %d", runtime.NumGoroutine(), before+1)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
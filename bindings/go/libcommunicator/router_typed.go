@@ -0,0 +1,117 @@
+package libcommunicator
+
+import "fmt"
+
+// MessagePostedEvent is a decoded message_posted event
+type MessagePostedEvent struct {
+	Message Message
+	Seq     int64 // see Event.Seq
+}
+
+// MessageUpdatedEvent is a decoded message_updated event
+type MessageUpdatedEvent struct {
+	Message Message
+	Seq     int64
+}
+
+// ReactionEvent is a decoded reaction_added or reaction_removed event
+type ReactionEvent struct {
+	Added     bool // false when the reaction was removed
+	MessageID string
+	ChannelID string
+	UserID    string
+	EmojiName string
+	Seq       int64
+}
+
+// TypingEvent is a decoded user_typing event
+type TypingEvent struct {
+	UserID    string
+	ChannelID string
+	Seq       int64
+}
+
+// StatusEvent is a decoded user_status_changed event
+type StatusEvent struct {
+	UserID string
+	Status string // online, away, donotdisturb, offline or unknown
+	Seq    int64
+}
+
+// OnMessage registers a handler for posted messages, decoded into a Message
+// A payload that doesn't decode is reported as a *HandlerError instead.
+func (r *EventRouter) OnMessage(handler func(MessagePostedEvent)) *Subscription {
+	return r.On(EventMessagePosted, func(event *Event) {
+		msg, err := decodeEventMessage(event)
+		if err != nil {
+			r.reportError(&HandlerError{Event: event, Err: err})
+			return
+		}
+		handler(MessagePostedEvent{Message: *msg, Seq: event.Seq})
+	})
+}
+
+// OnMessageEdited registers a handler for edited messages, decoded into a Message
+// A payload that doesn't decode is reported as a *HandlerError instead.
+func (r *EventRouter) OnMessageEdited(handler func(MessageUpdatedEvent)) *Subscription {
+	return r.On(EventMessageUpdated, func(event *Event) {
+		msg, err := decodeEventMessage(event)
+		if err != nil {
+			r.reportError(&HandlerError{Event: event, Err: err})
+			return
+		}
+		handler(MessageUpdatedEvent{Message: *msg, Seq: event.Seq})
+	})
+}
+
+// OnReaction registers a handler for reactions being added and removed
+func (r *EventRouter) OnReaction(handler func(ReactionEvent)) *Subscription {
+	typed := func(event *Event) {
+		handler(ReactionEvent{
+			Added:     event.Type == EventReactionAdded,
+			MessageID: event.MessageID,
+			ChannelID: event.ChannelID,
+			UserID:    event.UserID,
+			EmojiName: event.EmojiName,
+			Seq:       event.Seq,
+		})
+	}
+	return r.joinSubscriptions(r.On(EventReactionAdded, typed), r.On(EventReactionRemoved, typed))
+}
+
+// OnTyping registers a handler for users typing
+func (r *EventRouter) OnTyping(handler func(TypingEvent)) *Subscription {
+	return r.On(EventUserTyping, func(event *Event) {
+		handler(TypingEvent{UserID: event.UserID, ChannelID: event.ChannelID, Seq: event.Seq})
+	})
+}
+
+// OnStatus registers a handler for user status changes
+func (r *EventRouter) OnStatus(handler func(StatusEvent)) *Subscription {
+	return r.On(EventUserStatusChanged, func(event *Event) {
+		handler(StatusEvent{UserID: event.UserID, Status: event.Status, Seq: event.Seq})
+	})
+}
+
+// OnRaw registers a handler for unmodeled platform events of one type, e.g.
+// "sidebar_category_updated" on Mattermost; "" matches every raw event
+func (r *EventRouter) OnRaw(rawType string, handler EventHandler) *Subscription {
+	return r.On(EventRaw, func(event *Event) {
+		if rawType == "" || event.RawType == rawType {
+			handler(event)
+		}
+	})
+}
+
+// decodeEventMessage decodes and normalizes the Message carried by a message event
+func decodeEventMessage(event *Event) (*Message, error) {
+	if event.Data == nil {
+		return nil, fmt.Errorf("%s event carries no message", event.Type)
+	}
+	msg, err := eventMessage(event)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s event: %w", event.Type, err)
+	}
+	msg.Normalize()
+	return msg, nil
+}
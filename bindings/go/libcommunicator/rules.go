@@ -0,0 +1,371 @@
+package libcommunicator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rule action types
+const (
+	// RuleActionForward posts a quote of the message, with a permalink, to the channel in Target
+	RuleActionForward = "forward"
+	// RuleActionTag replies in the message's thread with the hashtags in Target, so
+	// tagged messages can be found with a hashtag search
+	RuleActionTag = "tag"
+	// RuleActionWebhook POSTs a RuleWebhookPayload as JSON to the URL in Target
+	// By default URLs resolving to non-public addresses fail with
+	// ErrBlockedAddress, so rules can't be used to reach internal services;
+	// see RuleEngine.WebhookClient.
+	RuleActionWebhook = "webhook"
+	// RuleActionReaction adds the emoji named in Target to the message
+	RuleActionReaction = "reaction"
+)
+
+// RuleMatch says which messages a rule applies to
+// Every non-empty condition must hold; a rule with no conditions matches every message.
+type RuleMatch struct {
+	SenderIDs  []string `json:"sender_ids,omitempty"`
+	ChannelIDs []string `json:"channel_ids,omitempty"`
+	// Pattern is a regular expression matched against the message text
+	Pattern string `json:"pattern,omitempty"`
+	// AttachmentTypes matches messages with an attachment of one of these MIME
+	// types; a trailing slash matches a whole family, e.g. "image/"
+	AttachmentTypes []string `json:"attachment_types,omitempty"`
+}
+
+// RuleAction is something a rule does with a matching message
+type RuleAction struct {
+	Type   string `json:"type"` // one of the RuleAction constants
+	Target string `json:"target"`
+}
+
+// Rule maps matching messages to actions
+type Rule struct {
+	Name     string       `json:"name"`
+	Match    RuleMatch    `json:"match"`
+	Actions  []RuleAction `json:"actions"`
+	Stop     bool         `json:"stop,omitempty"` // skip later rules when this one matches
+	Disabled bool         `json:"disabled,omitempty"`
+}
+
+// RuleWebhookPayload is the body posted by RuleActionWebhook
+type RuleWebhookPayload struct {
+	Rule    string   `json:"rule"`
+	Message *Message `json:"message"`
+}
+
+// ParseRules decodes and validates a JSON array of rules
+func ParseRules(data []byte) ([]Rule, error) {
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid rules: %w", err)
+	}
+	if _, err := compileRules(rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// compiledRule is a rule with its pattern compiled
+type compiledRule struct {
+	Rule
+	pattern *regexp.Regexp
+}
+
+func compileRules(rules []Rule) ([]compiledRule, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	names := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("invalid rule: missing name")
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("invalid rule %q: duplicate name", rule.Name)
+		}
+		names[rule.Name] = true
+
+		c := compiledRule{Rule: rule}
+		if rule.Match.Pattern != "" {
+			pattern, err := regexp.Compile(rule.Match.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rule %q: %w", rule.Name, err)
+			}
+			c.pattern = pattern
+		}
+		for _, action := range rule.Actions {
+			switch action.Type {
+			case RuleActionForward, RuleActionTag, RuleActionWebhook, RuleActionReaction:
+			default:
+				return nil, fmt.Errorf("invalid rule %q: unknown action %q", rule.Name, action.Type)
+			}
+			if action.Target == "" {
+				return nil, fmt.Errorf("invalid rule %q: %s action needs a target", rule.Name, action.Type)
+			}
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled, nil
+}
+
+func (r *compiledRule) matches(msg *Message) bool {
+	m := r.Match
+	if len(m.SenderIDs) > 0 && !containsString(m.SenderIDs, msg.SenderID) {
+		return false
+	}
+	if len(m.ChannelIDs) > 0 && !containsString(m.ChannelIDs, msg.ChannelID) {
+		return false
+	}
+	if r.pattern != nil && !r.pattern.MatchString(msg.Text) {
+		return false
+	}
+	if len(m.AttachmentTypes) > 0 && !hasAttachmentType(msg, m.AttachmentTypes) {
+		return false
+	}
+	return true
+}
+
+func hasAttachmentType(msg *Message, types []string) bool {
+	for _, a := range msg.Attachments {
+		mimeType := strings.ToLower(a.MimeType)
+		for _, t := range types {
+			t = strings.ToLower(t)
+			if mimeType == t || (strings.HasSuffix(t, "/") && strings.HasPrefix(mimeType, t)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleWebhookTimeout bounds each webhook post
+const ruleWebhookTimeout = 10 * time.Second
+
+// RuleEngine applies triage rules to posted messages
+// Rules can be replaced at any time; each message is checked against the
+// rules in order. Register it on an EventRouter to enable it.
+type RuleEngine struct {
+	platform *Platform
+
+	// WebhookClient posts the webhook actions. The default refuses to connect
+	// to non-public addresses; replace it, before registering the engine, to
+	// let rules reach internal services, e.g. with a client whose transport
+	// only dials an allowlist of hosts.
+	WebhookClient *http.Client
+	// Concurrency is the number of messages whose actions run at once
+	// (default 4); further messages wait their turn
+	Concurrency int
+	// OnError, if set, receives action failures
+	OnError func(rule string, err error)
+
+	mu    sync.RWMutex
+	rules []compiledRule
+	own   ownMessages
+}
+
+// NewRuleEngine creates an engine with the given rules
+func NewRuleEngine(p *Platform, rules []Rule) (*RuleEngine, error) {
+	e := &RuleEngine{
+		platform:      p,
+		WebhookClient: newSafeHTTPClient(ruleWebhookTimeout),
+		Concurrency:   4,
+	}
+	if err := e.SetRules(rules); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// SetRules replaces all rules; on error the current rules are kept
+func (e *RuleEngine) SetRules(rules []Rule) error {
+	compiled, err := compileRules(rules)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = compiled
+	return nil
+}
+
+// AddRule appends a rule, replacing any rule with the same name in place
+func (e *RuleEngine) AddRule(rule Rule) error {
+	// Hold the lock throughout, so concurrent changes aren't lost
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rules := e.rulesLocked()
+	replaced := false
+	for i := range rules {
+		if rules[i].Name == rule.Name {
+			rules[i] = rule
+			replaced = true
+		}
+	}
+	if !replaced {
+		rules = append(rules, rule)
+	}
+	compiled, err := compileRules(rules)
+	if err != nil {
+		return err
+	}
+	e.rules = compiled
+	return nil
+}
+
+// RemoveRule removes the named rule, reporting whether it existed
+func (e *RuleEngine) RemoveRule(name string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for i, rule := range e.rules {
+		if rule.Name == name {
+			e.rules = append(e.rules[:i:i], e.rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Rules returns a copy of the current rules
+func (e *RuleEngine) Rules() []Rule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.rulesLocked()
+}
+
+func (e *RuleEngine) rulesLocked() []Rule {
+	rules := make([]Rule, len(e.rules))
+	for i, rule := range e.rules {
+		rules[i] = rule.Rule
+	}
+	return rules
+}
+
+// Register installs the engine as a message posted handler on the router
+// Actions call the server and webhooks, so they run on a worker pool of
+// Concurrency workers rather than holding up the router.
+func (e *RuleEngine) Register(r *EventRouter) {
+	r.OnWithOptions(EventMessagePosted, e.handleEvent, HandlerOptions{Concurrency: e.Concurrency})
+}
+
+func (e *RuleEngine) handleEvent(event *Event) {
+	msg, err := eventMessage(event)
+	if err != nil {
+		e.reportError("", err)
+		return
+	}
+	// The engine's own posts are never matched, so forwarding rules can't loop
+	if e.own.isOwnMessage(e.platform, msg) {
+		return
+	}
+
+	e.apply(msg, e.Match(msg))
+}
+
+// Match returns the enabled rules that apply to a message, honouring Stop
+func (e *RuleEngine) Match(msg *Message) []Rule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var matched []Rule
+	for i := range e.rules {
+		rule := &e.rules[i]
+		if rule.Disabled || !rule.matches(msg) {
+			continue
+		}
+		matched = append(matched, rule.Rule)
+		if rule.Stop {
+			break
+		}
+	}
+	return matched
+}
+
+func (e *RuleEngine) apply(msg *Message, rules []Rule) {
+	for _, rule := range rules {
+		for _, action := range rule.Actions {
+			if err := e.run(rule.Name, action, msg); err != nil {
+				e.reportError(rule.Name, fmt.Errorf("%s action: %w", action.Type, err))
+			}
+		}
+	}
+}
+
+func (e *RuleEngine) run(rule string, action RuleAction, msg *Message) error {
+	switch action.Type {
+	case RuleActionForward:
+		text := quoteForward(msg.Text)
+		if permalink, err := e.platform.GetPermalink(msg.ID); err == nil {
+			text = "Forwarded from " + permalink + "\n" + text
+		}
+		_, err := e.platform.SendMessage(action.Target, text)
+		return err
+	case RuleActionTag:
+		var tags []string
+		for _, tag := range strings.Fields(action.Target) {
+			tags = append(tags, "#"+strings.TrimPrefix(tag, "#"))
+		}
+		_, err := e.platform.SendReply(msg.ChannelID, strings.Join(tags, " "), threadRootID(msg))
+		return err
+	case RuleActionWebhook:
+		return e.postWebhook(action.Target, &RuleWebhookPayload{Rule: rule, Message: msg})
+	case RuleActionReaction:
+		return e.platform.AddReaction(msg.ID, strings.Trim(action.Target, ":"))
+	}
+	return fmt.Errorf("unknown action %q", action.Type)
+}
+
+func (e *RuleEngine) postWebhook(url string, payload *RuleWebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ruleWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.WebhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (e *RuleEngine) reportError(rule string, err error) {
+	if e.OnError != nil {
+		e.OnError(rule, err)
+	}
+}
+
+// quoteForward renders text as a markdown block quote
+func quoteForward(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = "> " + line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,190 @@
+package libcommunicator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRulesValidates(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			data: `[{"name":"a","match":{"pattern":"^deploy"},"actions":[{"type":"tag","target":"deploys"}]}]`,
+		},
+		{name: "not JSON", data: `{`, wantErr: "invalid rules"},
+		{name: "missing name", data: `[{"actions":[]}]`, wantErr: "missing name"},
+		{name: "duplicate name", data: `[{"name":"a"},{"name":"a"}]`, wantErr: `"a": duplicate name`},
+		{name: "bad pattern", data: `[{"name":"a","match":{"pattern":"("}}]`, wantErr: `invalid rule "a"`},
+		{name: "unknown action", data: `[{"name":"a","actions":[{"type":"shout","target":"x"}]}]`, wantErr: `unknown action "shout"`},
+		{name: "missing target", data: `[{"name":"a","actions":[{"type":"reaction"}]}]`, wantErr: "reaction action needs a target"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRules([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRuleEngineMatch(t *testing.T) {
+	e, err := NewRuleEngine(&Platform{}, []Rule{
+		{Name: "disabled", Disabled: true},
+		{Name: "alice", Match: RuleMatch{SenderIDs: []string{"alice"}}},
+		{Name: "incidents", Match: RuleMatch{ChannelIDs: []string{"c1"}, Pattern: `(?i)\bsev[12]\b`}, Stop: true},
+		{Name: "images", Match: RuleMatch{AttachmentTypes: []string{"image/"}}},
+		{Name: "pdfs", Match: RuleMatch{AttachmentTypes: []string{"application/pdf"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		msg  Message
+		want []string
+	}{
+		{"no match", Message{SenderID: "bob", ChannelID: "c2", Text: "SEV1"}, nil},
+		{"sender", Message{SenderID: "alice"}, []string{"alice"}},
+		{"channel and pattern", Message{SenderID: "bob", ChannelID: "c1", Text: "a SEV2 outage"}, []string{"incidents"}},
+		{"pattern needs a word", Message{ChannelID: "c1", Text: "sev10"}, nil},
+		{"stop skips later rules", Message{ChannelID: "c1", Text: "sev1", Attachments: []Attachment{{MimeType: "image/png"}}}, []string{"incidents"}},
+		{"attachment family", Message{Attachments: []Attachment{{MimeType: "IMAGE/JPEG"}}}, []string{"images"}},
+		{"attachment type", Message{Attachments: []Attachment{{MimeType: "application/pdf"}}}, []string{"pdfs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, rule := range e.Match(&tt.msg) {
+				got = append(got, rule.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleEngineAddRuleConcurrently(t *testing.T) {
+	e, err := NewRuleEngine(&Platform{}, []Rule{{Name: "first"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := e.AddRule(Rule{Name: fmt.Sprintf("rule-%d", i)}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if rules := e.Rules(); len(rules) != 21 {
+		t.Fatalf("%d rules after adding 20 at once, want 21", len(rules))
+	}
+
+	// Re-adding a rule replaces it in place; an invalid one changes nothing
+	if err := e.AddRule(Rule{Name: "first", Disabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.AddRule(Rule{Name: "bad", Match: RuleMatch{Pattern: "("}}); err == nil {
+		t.Fatal("an invalid rule was added")
+	}
+	if rules := e.Rules(); len(rules) != 21 || rules[0].Name != "first" || !rules[0].Disabled {
+		t.Fatalf("rules = %+v", rules[:1])
+	}
+}
+
+func TestRuleEngineWebhookClient(t *testing.T) {
+	var got RuleWebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	e, err := NewRuleEngine(&Platform{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	action := RuleAction{Type: RuleActionWebhook, Target: server.URL}
+	msg := &Message{ID: "p1", Text: "hi"}
+
+	// The default client won't reach a loopback address
+	if err := e.run("notify", action, msg); !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("webhook to %s: %v, want ErrBlockedAddress", server.URL, err)
+	}
+
+	e.WebhookClient = server.Client()
+	if err := e.run("notify", action, msg); err != nil {
+		t.Fatal(err)
+	}
+	if got.Rule != "notify" || got.Message == nil || got.Message.ID != "p1" {
+		t.Fatalf("webhook payload = %+v", got)
+	}
+}
+
+func TestRuleEngineBoundsConcurrentActions(t *testing.T) {
+	var running, peak, handled atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := running.Add(1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		<-release
+		running.Add(-1)
+		handled.Add(1)
+	}))
+	defer server.Close()
+
+	e, err := NewRuleEngine(&Platform{}, []Rule{{
+		Name:    "notify",
+		Actions: []RuleAction{{Type: RuleActionWebhook, Target: server.URL}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.WebhookClient = server.Client()
+	e.Concurrency = 2
+
+	r := NewEventRouter()
+	e.Register(r)
+	for i := range 6 {
+		r.Handle(&Event{Type: EventMessagePosted, Data: map[string]interface{}{"id": fmt.Sprint(i)}})
+	}
+	waitFor(t, "two webhooks to be posted", func() bool { return running.Load() == 2 })
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	r.Close()
+
+	if peak.Load() != 2 || handled.Load() != 6 {
+		t.Fatalf("posted %d webhooks, at most %d at once; want 6, 2 at once", handled.Load(), peak.Load())
+	}
+}
@@ -0,0 +1,225 @@
+package libcommunicator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ScanResult is the outcome of scanning a file
+type ScanResult struct {
+	Clean     bool
+	Signature string // Name of the detected threat when Clean is false
+}
+
+// FileScanner inspects file contents before they are uploaded or returned from a download
+type FileScanner interface {
+	Scan(name string, r io.Reader) (ScanResult, error)
+}
+
+// ScanAction is what happens when a scanner flags a file
+type ScanAction int
+
+const (
+	// ScanActionBlock rejects the transfer with an *InfectedFileError
+	ScanActionBlock ScanAction = iota
+	// ScanActionQuarantine rejects the transfer and posts a quarantine note to the channel
+	ScanActionQuarantine
+)
+
+// ScanDirection indicates whether a scanned file was being uploaded or downloaded
+type ScanDirection string
+
+const (
+	ScanUpload   ScanDirection = "upload"
+	ScanDownload ScanDirection = "download"
+)
+
+// ScanPolicy controls how scan results are acted upon
+type ScanPolicy struct {
+	Action ScanAction
+	// NoteChannelID receives quarantine notes. Uploads fall back to the target
+	// channel when empty; downloads only post a note when this is set.
+	NoteChannelID string
+	// FailOpen allows the transfer when the scanner itself fails
+	FailOpen bool
+}
+
+// InfectedFileError is returned when a scanner flags a file
+type InfectedFileError struct {
+	Name      string
+	Signature string
+	Direction ScanDirection
+}
+
+func (e *InfectedFileError) Error() string {
+	return fmt.Sprintf("%s of %q blocked: %s detected", e.Direction, e.Name, e.Signature)
+}
+
+// SetFileScanner installs a scanner invoked on UploadFile and DownloadFile
+// Pass a nil scanner to disable scanning
+func (p *Platform) SetFileScanner(scanner FileScanner, policy ScanPolicy) {
+	p.scanMu.Lock()
+	defer p.scanMu.Unlock()
+	p.scanner = scanner
+	p.scanPolicy = policy
+}
+
+// scanFile runs the installed scanner, if any, and applies the scan policy
+func (p *Platform) scanFile(direction ScanDirection, channelID, name string, r io.Reader) error {
+	p.scanMu.RLock()
+	scanner, policy := p.scanner, p.scanPolicy
+	p.scanMu.RUnlock()
+
+	if scanner == nil {
+		return nil
+	}
+
+	result, err := scanner.Scan(name, r)
+	if err != nil {
+		if policy.FailOpen {
+			return nil
+		}
+		return fmt.Errorf("file scan failed: %w", err)
+	}
+	if result.Clean {
+		return nil
+	}
+
+	infected := &InfectedFileError{Name: name, Signature: result.Signature, Direction: direction}
+
+	if policy.Action == ScanActionQuarantine {
+		noteChannel := policy.NoteChannelID
+		if noteChannel == "" {
+			noteChannel = channelID
+		}
+		if noteChannel != "" {
+			note := fmt.Sprintf(":warning: File `%s` was quarantined during %s: %s detected", name, direction, result.Signature)
+			// The transfer is blocked either way; a failed note must not mask that
+			_, _ = p.SendMessage(noteChannel, note)
+		}
+	}
+
+	return infected
+}
+
+// scanPath scans a local file ahead of upload
+func (p *Platform) scanPath(channelID, filePath string) error {
+	p.scanMu.RLock()
+	enabled := p.scanner != nil
+	p.scanMu.RUnlock()
+
+	if !enabled {
+		return nil
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return p.scanFile(ScanUpload, channelID, filepath.Base(filePath), f)
+}
+
+// scanBytes scans downloaded data before it is handed to the caller
+func (p *Platform) scanBytes(name string, data []byte) error {
+	return p.scanFile(ScanDownload, "", name, bytes.NewReader(data))
+}
+
+// ClamdScanner scans files using a clamd daemon's INSTREAM command
+type ClamdScanner struct {
+	Network string        // "tcp" or "unix"
+	Address string        // e.g. "localhost:3310" or "/var/run/clamav/clamd.ctl"
+	Timeout time.Duration // Per-scan deadline; zero means no deadline
+}
+
+// NewClamdScanner creates a scanner that talks to clamd at the given address
+func NewClamdScanner(network, address string) *ClamdScanner {
+	return &ClamdScanner{Network: network, Address: address, Timeout: 30 * time.Second}
+}
+
+// clamdChunkSize is the largest chunk sent per INSTREAM frame
+const clamdChunkSize = 32 * 1024
+
+// Scan streams r to clamd and parses its verdict
+func (s *ClamdScanner) Scan(name string, r io.Reader) (ScanResult, error) {
+	conn, err := net.DialTimeout(s.Network, s.Address, s.dialTimeout())
+	if err != nil {
+		return ScanResult{}, err
+	}
+	defer conn.Close()
+
+	if s.Timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(s.Timeout)); err != nil {
+			return ScanResult{}, err
+		}
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return ScanResult{}, err
+	}
+
+	var header [4]byte
+	buf := make([]byte, clamdChunkSize)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(header[:], uint32(n))
+			if _, err := conn.Write(header[:]); err != nil {
+				return ScanResult{}, err
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return ScanResult{}, err
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return ScanResult{}, readErr
+		}
+	}
+
+	// A zero-length chunk terminates the stream
+	binary.BigEndian.PutUint32(header[:], 0)
+	if _, err := conn.Write(header[:]); err != nil {
+		return ScanResult{}, err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return ScanResult{}, err
+	}
+
+	return parseClamdReply(reply)
+}
+
+func (s *ClamdScanner) dialTimeout() time.Duration {
+	if s.Timeout > 0 {
+		return s.Timeout
+	}
+	return 10 * time.Second
+}
+
+// parseClamdReply interprets replies such as "stream: OK" and "stream: Eicar-Signature FOUND"
+func parseClamdReply(reply string) (ScanResult, error) {
+	reply = strings.TrimRight(reply, "\x00\r\n")
+	verdict := strings.TrimPrefix(reply, "stream: ")
+
+	switch {
+	case verdict == "OK":
+		return ScanResult{Clean: true}, nil
+	case strings.HasSuffix(verdict, " FOUND"):
+		return ScanResult{Signature: strings.TrimSuffix(verdict, " FOUND")}, nil
+	default:
+		return ScanResult{}, fmt.Errorf("clamd: %s", reply)
+	}
+}
@@ -0,0 +1,201 @@
+package libcommunicator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// scannerFunc adapts a function to FileScanner
+type scannerFunc func(name string, r io.Reader) (ScanResult, error)
+
+func (f scannerFunc) Scan(name string, r io.Reader) (ScanResult, error) {
+	return f(name, r)
+}
+
+func TestParseClamdReply(t *testing.T) {
+	tests := []struct {
+		reply   string
+		want    ScanResult
+		wantErr string
+	}{
+		{reply: "stream: OK\x00", want: ScanResult{Clean: true}},
+		{reply: "stream: OK\n", want: ScanResult{Clean: true}},
+		{reply: "stream: Eicar-Signature FOUND\x00", want: ScanResult{Signature: "Eicar-Signature"}},
+		{reply: "INSTREAM size limit exceeded. ERROR\x00", wantErr: "clamd: INSTREAM size limit exceeded. ERROR"},
+		{reply: "", wantErr: "clamd: "},
+	}
+	for _, tt := range tests {
+		got, err := parseClamdReply(tt.reply)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("parseClamdReply(%q) error = %v, want %q", tt.reply, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseClamdReply(%q) = %+v, %v, want %+v", tt.reply, got, err, tt.want)
+		}
+	}
+}
+
+// fakeClamd answers one INSTREAM scan, flagging streams that contain "EICAR"
+func fakeClamd(t *testing.T) (addr string, received <-chan []byte) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	ch := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		if cmd, err := r.ReadString(0); err != nil || cmd != "zINSTREAM\x00" {
+			conn.Write([]byte("UNKNOWN COMMAND\x00"))
+			return
+		}
+		var data []byte
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if size > clamdChunkSize {
+				conn.Write([]byte("oversized chunk ERROR\x00"))
+				return
+			}
+			chunk := make([]byte, size)
+			if _, err := io.ReadFull(r, chunk); err != nil {
+				return
+			}
+			data = append(data, chunk...)
+		}
+		ch <- data
+		if bytes.Contains(data, []byte("EICAR")) {
+			conn.Write([]byte("stream: Eicar-Signature FOUND\x00"))
+		} else {
+			conn.Write([]byte("stream: OK\x00"))
+		}
+	}()
+	return ln.Addr().String(), ch
+}
+
+func TestClamdScanner(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want ScanResult
+	}{
+		{"clean", []byte("hello"), ScanResult{Clean: true}},
+		{"empty", nil, ScanResult{Clean: true}},
+		{"several chunks", bytes.Repeat([]byte("x"), 3*clamdChunkSize+1), ScanResult{Clean: true}},
+		{"infected", []byte("X5O!P%@AP EICAR test"), ScanResult{Signature: "Eicar-Signature"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, received := fakeClamd(t)
+			got, err := NewClamdScanner("tcp", addr).Scan("file.bin", bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("Scan = %+v, want %+v", got, tt.want)
+			}
+			if data := <-received; !bytes.Equal(data, tt.data) {
+				t.Fatalf("clamd received %d bytes, want %d", len(data), len(tt.data))
+			}
+		})
+	}
+}
+
+func TestScanFilePolicy(t *testing.T) {
+	errScanner := errors.New("clamd down")
+	clean := scannerFunc(func(string, io.Reader) (ScanResult, error) { return ScanResult{Clean: true}, nil })
+	infected := scannerFunc(func(string, io.Reader) (ScanResult, error) { return ScanResult{Signature: "Eicar"}, nil })
+	failing := scannerFunc(func(string, io.Reader) (ScanResult, error) { return ScanResult{}, errScanner })
+
+	tests := []struct {
+		name     string
+		scanner  FileScanner
+		policy   ScanPolicy
+		wantErr  error
+		infected bool
+	}{
+		{name: "no scanner"},
+		{name: "clean", scanner: clean},
+		{name: "blocked", scanner: infected, infected: true},
+		{name: "quarantined", scanner: infected, policy: ScanPolicy{Action: ScanActionQuarantine, NoteChannelID: "security"}, infected: true},
+		{name: "scanner fails closed", scanner: failing, wantErr: errScanner},
+		{name: "scanner fails open", scanner: failing, policy: ScanPolicy{FailOpen: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Platform{}
+			p.SetFileScanner(tt.scanner, tt.policy)
+			err := p.scanBytes("report.pdf", []byte("data"))
+
+			var infectedErr *InfectedFileError
+			switch {
+			case tt.infected:
+				// A failed quarantine note still blocks the download
+				if !errors.As(err, &infectedErr) || infectedErr.Signature != "Eicar" || infectedErr.Direction != ScanDownload {
+					t.Fatalf("error = %v, want an infected download", err)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestScanPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upload.txt")
+	if err := os.WriteFile(path, []byte("contents"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Platform{}
+	var scanned string
+	p.SetFileScanner(scannerFunc(func(name string, r io.Reader) (ScanResult, error) {
+		data, _ := io.ReadAll(r)
+		scanned = name + ":" + string(data)
+		return ScanResult{Signature: "Test"}, nil
+	}), ScanPolicy{})
+
+	err := p.scanPath("c1", path)
+	if scanned != "upload.txt:contents" {
+		t.Fatalf("scanned %q, want the file's base name and contents", scanned)
+	}
+	if err == nil || !strings.HasPrefix(err.Error(), `upload of "upload.txt" blocked`) {
+		t.Fatalf("error = %v, want a blocked upload", err)
+	}
+	if err := p.scanPath("c1", filepath.Join(t.TempDir(), "missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("scanning a missing file: %v", err)
+	}
+
+	// Without a scanner the file isn't even opened
+	p.SetFileScanner(nil, ScanPolicy{})
+	if err := p.scanPath("c1", "/nonexistent"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,64 @@
+package libcommunicator
+
+/*
+#include <communicator.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+)
+
+// Access to the native schema samples, used by the golden-file tests to catch
+// drift between the Go types and their native counterparts. cgo is not
+// available in test files, so the calls live here.
+
+// schemaTypes lists the type names the native library has samples for
+// Data types come first, followed by events ("event.<type>")
+func schemaTypes() ([]string, error) {
+	defer pinThread()()
+	cstr := C.communicator_schema_names()
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var names []string
+	if err := json.Unmarshal([]byte(C.GoString(cstr)), &names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// nativeSample returns the native library's fully populated JSON sample of a type
+func nativeSample(typeName string) (json.RawMessage, error) {
+	defer pinThread()()
+	cName, freeName := cStringFree(typeName)
+	defer freeName()
+
+	cstr := C.communicator_schema_sample(cName)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	return json.RawMessage(C.GoString(cstr)), nil
+}
+
+// nativeRoundTrip decodes data as the native type and returns its re-encoding
+func nativeRoundTrip(typeName string, data []byte) (json.RawMessage, error) {
+	defer pinThread()()
+	cName, freeName := cStringFree(typeName)
+	defer freeName()
+
+	cData, freeData := cStringFree(string(data))
+	defer freeData()
+
+	cstr := C.communicator_schema_roundtrip(cName, cData)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	return json.RawMessage(C.GoString(cstr)), nil
+}
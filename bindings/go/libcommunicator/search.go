@@ -42,9 +42,10 @@ type PostSearchOptions struct {
 // SearchUsers performs advanced user search with filtering
 // Returns a JSON array string of User objects
 func (p *Platform) SearchUsers(request *UserSearchRequest) ([]User, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	requestJSON, err := json.Marshal(request)
 	if err != nil {
@@ -61,7 +62,7 @@ func (p *Platform) SearchUsers(request *UserSearchRequest) ([]User, error) {
 	defer C.communicator_free_string(result)
 
 	var users []User
-	if err := json.Unmarshal([]byte(C.GoString(result)), &users); err != nil {
+	if err := p.decode([]byte(C.GoString(result)), &users); err != nil {
 		return nil, err
 	}
 
@@ -71,9 +72,10 @@ func (p *Platform) SearchUsers(request *UserSearchRequest) ([]User, error) {
 // AutocompleteUsers autocompletes users for mentions
 // Pass empty strings for teamID or channelID if not needed
 func (p *Platform) AutocompleteUsers(name, teamID, channelID string, limit uint32) ([]User, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
@@ -97,7 +99,7 @@ func (p *Platform) AutocompleteUsers(name, teamID, channelID string, limit uint3
 	defer C.communicator_free_string(result)
 
 	var users []User
-	if err := json.Unmarshal([]byte(C.GoString(result)), &users); err != nil {
+	if err := p.decode([]byte(C.GoString(result)), &users); err != nil {
 		return nil, err
 	}
 
@@ -106,9 +108,10 @@ func (p *Platform) AutocompleteUsers(name, teamID, channelID string, limit uint3
 
 // SearchChannels searches for channels in a team
 func (p *Platform) SearchChannels(teamID, term string) ([]Channel, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cTeamID := C.CString(teamID)
 	defer C.free(unsafe.Pointer(cTeamID))
@@ -123,7 +126,7 @@ func (p *Platform) SearchChannels(teamID, term string) ([]Channel, error) {
 	defer C.communicator_free_string(result)
 
 	var channels []Channel
-	if err := json.Unmarshal([]byte(C.GoString(result)), &channels); err != nil {
+	if err := p.decode([]byte(C.GoString(result)), &channels); err != nil {
 		return nil, err
 	}
 
@@ -132,9 +135,10 @@ func (p *Platform) SearchChannels(teamID, term string) ([]Channel, error) {
 
 // AutocompleteChannels autocompletes channels for references
 func (p *Platform) AutocompleteChannels(teamID, name string) ([]Channel, error) {
-	if p.handle == nil {
-		return nil, ErrInvalidHandle
+	if !p.acquire() {
+		return nil, ErrClosed
 	}
+	defer p.release()
 
 	cTeamID := C.CString(teamID)
 	defer C.free(unsafe.Pointer(cTeamID))
@@ -149,7 +153,7 @@ func (p *Platform) AutocompleteChannels(teamID, name string) ([]Channel, error)
 	defer C.communicator_free_string(result)
 
 	var channels []Channel
-	if err := json.Unmarshal([]byte(C.GoString(result)), &channels); err != nil {
+	if err := p.decode([]byte(C.GoString(result)), &channels); err != nil {
 		return nil, err
 	}
 
@@ -160,9 +164,10 @@ func (p *Platform) AutocompleteChannels(teamID, name string) ([]Channel, error)
 // Returns a JSON string with file search results
 // Note: This function is not yet fully supported by the Platform trait
 func (p *Platform) SearchFiles(request *FileSearchRequest) (string, error) {
-	if p.handle == nil {
-		return "", ErrInvalidHandle
+	if !p.acquire() {
+		return "", ErrClosed
 	}
+	defer p.release()
 
 	requestJSON, err := json.Marshal(request)
 	if err != nil {
@@ -185,9 +190,10 @@ func (p *Platform) SearchFiles(request *FileSearchRequest) (string, error) {
 // Returns a JSON string with post search results
 // Note: This function is not yet fully supported by the Platform trait
 func (p *Platform) SearchPostsAdvanced(options *PostSearchOptions) (string, error) {
-	if p.handle == nil {
-		return "", ErrInvalidHandle
+	if !p.acquire() {
+		return "", ErrClosed
 	}
+	defer p.release()
 
 	requestJSON, err := json.Marshal(options)
 	if err != nil {
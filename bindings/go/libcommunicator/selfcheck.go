@@ -0,0 +1,310 @@
+package libcommunicator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Capabilities a bot can declare for SelfCheck, each requiring a set of permissions
+const (
+	CapabilityPost          = "post"
+	CapabilityReact         = "react"
+	CapabilityUploadFiles   = "upload_files"
+	CapabilityManageMembers = "manage_members"
+	CapabilityModerate      = "moderate" // edit and delete other users' messages
+	CapabilityUseCommands   = "use_commands"
+	CapabilityCreateChannel = "create_channel"
+)
+
+// capabilityPermissions maps each capability to the permissions it needs
+var capabilityPermissions = map[string][]string{
+	CapabilityPost:          {"create_post"},
+	CapabilityReact:         {"add_reaction", "remove_reaction"},
+	CapabilityUploadFiles:   {"upload_file"},
+	CapabilityManageMembers: {"manage_public_channel_members", "manage_private_channel_members"},
+	CapabilityModerate:      {"edit_others_posts", "delete_others_posts"},
+	CapabilityUseCommands:   {"use_slash_commands"},
+	CapabilityCreateChannel: {"create_public_channel"},
+}
+
+// SelfCheckStatus is the outcome of one self-check
+type SelfCheckStatus string
+
+const (
+	SelfCheckPass SelfCheckStatus = "pass"
+	SelfCheckWarn SelfCheckStatus = "warn"
+	SelfCheckFail SelfCheckStatus = "fail"
+	SelfCheckSkip SelfCheckStatus = "skip"
+)
+
+// Self-check names
+const (
+	SelfCheckAuth        = "auth"
+	SelfCheckTeam        = "team"
+	SelfCheckWebSocket   = "websocket"
+	SelfCheckPermissions = "permissions"
+	SelfCheckClock       = "clock"
+)
+
+// SelfCheckOptions declares what SelfCheck should verify
+type SelfCheckOptions struct {
+	// TeamID is the team the bot must belong to; defaults to the connected team
+	TeamID string
+	// ChannelID is where permissions are checked; required when Capabilities
+	// or Permissions are set
+	ChannelID string
+	// Capabilities are the Capability constants the bot relies on
+	Capabilities []string
+	// Permissions are additional permissions the bot relies on, e.g. "create_post"
+	Permissions []string
+	// SkipWebSocket skips the WebSocket check, for bots that never subscribe to events
+	SkipWebSocket bool
+	// MaxClockSkew is the largest tolerated difference between the local and
+	// server clocks (default 30s); negative skips the clock check
+	MaxClockSkew time.Duration
+}
+
+// SelfCheckResult is the outcome of one check
+type SelfCheckResult struct {
+	Name   string
+	Status SelfCheckStatus
+	Detail string
+	// Hint says how to fix a failure or warning
+	Hint string
+}
+
+// SelfCheckReport is the outcome of SelfCheck
+type SelfCheckReport struct {
+	Checks   []SelfCheckResult
+	Duration time.Duration
+}
+
+// OK reports whether no check failed; warnings and skipped checks are allowed
+func (r *SelfCheckReport) OK() bool {
+	for _, c := range r.Checks {
+		if c.Status == SelfCheckFail {
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns an error describing every failed check, or nil if none failed
+func (r *SelfCheckReport) Err() error {
+	var errs []error
+	for _, c := range r.Checks {
+		if c.Status != SelfCheckFail {
+			continue
+		}
+		msg := c.Name + ": " + c.Detail
+		if c.Hint != "" {
+			msg += " (" + c.Hint + ")"
+		}
+		errs = append(errs, errors.New(msg))
+	}
+	return errors.Join(errs...)
+}
+
+// String renders the report one check per line, e.g. "[fail] team: not a member of team abc"
+func (r *SelfCheckReport) String() string {
+	var b strings.Builder
+	for _, c := range r.Checks {
+		fmt.Fprintf(&b, "[%s] %s: %s", c.Status, c.Name, c.Detail)
+		if c.Hint != "" {
+			fmt.Fprintf(&b, " (%s)", c.Hint)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// SelfCheck verifies that the platform is ready for a bot to run: the
+// session is valid, the bot belongs to its team, the WebSocket is up, the
+// declared capabilities are permitted and the local clock agrees with the
+// server's
+// It is meant to run at startup; fail fast with report.Err(). Checks that
+// depend on a failed one are skipped. The error is only set when the checks
+// couldn't run at all.
+func (p *Platform) SelfCheck(ctx context.Context, opts SelfCheckOptions) (*SelfCheckReport, error) {
+	if p.destroyed() {
+		return nil, ErrClosed
+	}
+	if opts.MaxClockSkew == 0 {
+		opts.MaxClockSkew = 30 * time.Second
+	}
+
+	start := time.Now()
+	report := &SelfCheckReport{}
+	add := func(name string, status SelfCheckStatus, detail, hint string) {
+		report.Checks = append(report.Checks, SelfCheckResult{Name: name, Status: status, Detail: detail, Hint: hint})
+	}
+
+	info, err := p.GetConnectionInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	// Auth
+	me, err := p.GetCurrentUser()
+	if err != nil {
+		add(SelfCheckAuth, SelfCheckFail, err.Error(), "check the token or credentials and that the account is active")
+	} else {
+		add(SelfCheckAuth, SelfCheckPass, "authenticated as "+me.Username, "")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Team membership
+	teamID := opts.TeamID
+	if teamID == "" {
+		teamID = info.TeamID
+	}
+	switch {
+	case me == nil:
+		add(SelfCheckTeam, SelfCheckSkip, "not authenticated", "")
+	case teamID == "":
+		add(SelfCheckTeam, SelfCheckWarn, "no team configured", "set PlatformConfig.TeamID or SelfCheckOptions.TeamID")
+	default:
+		p.checkTeam(teamID, add)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// WebSocket
+	switch {
+	case opts.SkipWebSocket:
+		add(SelfCheckWebSocket, SelfCheckSkip, "not requested", "")
+	case me == nil:
+		add(SelfCheckWebSocket, SelfCheckSkip, "not authenticated", "")
+	default:
+		if _, err := p.RequestAllStatuses(); err != nil {
+			add(SelfCheckWebSocket, SelfCheckFail, err.Error(), "call SubscribeEvents before SelfCheck and check that the server allows WebSocket connections")
+		} else {
+			add(SelfCheckWebSocket, SelfCheckPass, "connected", "")
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Permissions
+	required, err := requiredPermissions(opts)
+	switch {
+	case err != nil:
+		add(SelfCheckPermissions, SelfCheckFail, err.Error(), "use the Capability constants")
+	case len(required) == 0:
+		add(SelfCheckPermissions, SelfCheckSkip, "no capabilities declared", "")
+	case me == nil:
+		add(SelfCheckPermissions, SelfCheckSkip, "not authenticated", "")
+	case opts.ChannelID == "":
+		add(SelfCheckPermissions, SelfCheckFail, "no channel to check permissions in", "set SelfCheckOptions.ChannelID")
+	default:
+		p.checkPermissions(me.ID, opts.ChannelID, required, add)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Clock skew
+	if opts.MaxClockSkew < 0 {
+		add(SelfCheckClock, SelfCheckSkip, "not requested", "")
+	} else if skew, err := serverClockSkew(ctx, info.ServerURL); err != nil {
+		add(SelfCheckClock, SelfCheckWarn, "could not read the server time: "+err.Error(), "")
+	} else if skew.Abs() > opts.MaxClockSkew {
+		add(SelfCheckClock, SelfCheckFail, fmt.Sprintf("local clock is %s off the server's", skew.Round(time.Second)), "sync the clock with NTP")
+	} else {
+		add(SelfCheckClock, SelfCheckPass, fmt.Sprintf("skew %s", skew.Round(time.Second)), "")
+	}
+
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+func (p *Platform) checkTeam(teamID string, add func(name string, status SelfCheckStatus, detail, hint string)) {
+	teams, err := p.GetTeams()
+	if err != nil {
+		add(SelfCheckTeam, SelfCheckFail, err.Error(), "")
+		return
+	}
+	for _, team := range teams {
+		if team.ID == teamID || team.Name == teamID {
+			add(SelfCheckTeam, SelfCheckPass, "member of "+team.Name, "")
+			return
+		}
+	}
+	add(SelfCheckTeam, SelfCheckFail, "not a member of team "+teamID, "add the account to the team")
+}
+
+func (p *Platform) checkPermissions(userID, channelID string, required []string, add func(name string, status SelfCheckStatus, detail, hint string)) {
+	granted, err := p.GetEffectivePermissions(userID, channelID)
+	if err != nil {
+		add(SelfCheckPermissions, SelfCheckFail, err.Error(), "check that the account is a member of the channel")
+		return
+	}
+	var missing []string
+	for _, permission := range required {
+		if !containsString(granted, permission) {
+			missing = append(missing, permission)
+		}
+	}
+	if len(missing) > 0 {
+		add(SelfCheckPermissions, SelfCheckFail, "missing "+strings.Join(missing, ", "), "grant the permissions to the account's role or remove the capabilities")
+		return
+	}
+	add(SelfCheckPermissions, SelfCheckPass, fmt.Sprintf("%d permissions granted", len(required)), "")
+}
+
+// requiredPermissions expands the declared capabilities into permissions, without duplicates
+func requiredPermissions(opts SelfCheckOptions) ([]string, error) {
+	var required []string
+	for _, capability := range opts.Capabilities {
+		permissions, ok := capabilityPermissions[capability]
+		if !ok {
+			return nil, fmt.Errorf("unknown capability %q", capability)
+		}
+		for _, permission := range permissions {
+			if !containsString(required, permission) {
+				required = append(required, permission)
+			}
+		}
+	}
+	for _, permission := range opts.Permissions {
+		if !containsString(required, permission) {
+			required = append(required, permission)
+		}
+	}
+	return required, nil
+}
+
+// serverClockSkew estimates how far the local clock is ahead of the server's
+// from the Date header of a ping; the header only has second precision
+func serverClockSkew(ctx context.Context, serverURL string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(serverURL, "/")+"/api/v4/system/ping", nil)
+	if err != nil {
+		return 0, err
+	}
+	sent := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	received := time.Now()
+
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, fmt.Errorf("no usable Date header: %w", err)
+	}
+	// The server stamped the response somewhere in the round trip; assume the middle
+	local := sent.Add(received.Sub(sent) / 2)
+	return local.Sub(serverTime), nil
+}
@@ -0,0 +1,230 @@
+package libcommunicator
+
+/*
+#include <communicator.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Session represents an active login session of a user
+type Session struct {
+	ID             string     `json:"id"`
+	UserID         string     `json:"user_id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastActivityAt time.Time  `json:"last_activity_at"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	DeviceID       *string    `json:"device_id,omitempty"`
+	OS             *string    `json:"os,omitempty"`
+	Browser        *string    `json:"browser,omitempty"`
+	Platform       *string    `json:"platform,omitempty"`
+	IsOAuth        bool       `json:"is_oauth"`
+}
+
+// ListSessions retrieves the active login sessions of a user
+func (p *Platform) ListSessions(userID string) ([]Session, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cUserID, freeUserID := cStringFree(userID)
+	defer freeUserID()
+
+	cstr := C.communicator_platform_list_sessions(p.handle, cUserID)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var sessions []Session
+	if err := p.decode([]byte(C.GoString(cstr)), &sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes one of the current user's sessions
+func (p *Platform) RevokeSession(sessionID string) (err error) {
+	defer p.audit("RevokeSession", "session_id", sessionID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("RevokeSession"); err != nil {
+		return err
+	}
+
+	cSessionID, freeSessionID := cStringFree(sessionID)
+	defer freeSessionID()
+
+	code := C.communicator_platform_revoke_session(p.handle, cSessionID)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
+// RevokeAllSessions revokes every session of the current user, including this one
+// The platform is logged out as a result and must reconnect before further calls
+func (p *Platform) RevokeAllSessions() (err error) {
+	defer p.audit("RevokeAllSessions")(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("RevokeAllSessions"); err != nil {
+		return err
+	}
+
+	code := C.communicator_platform_revoke_all_sessions(p.handle)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
+// SessionState is everything needed to resume a connection after a restart
+// It contains the session token, so keep it as private as a password
+type SessionState struct {
+	ServerURL    string  `json:"server_url"`
+	Token        string  `json:"token"`
+	UserID       *string `json:"user_id,omitempty"`
+	TeamID       *string `json:"team_id,omitempty"`
+	ConnectionID *string `json:"connection_id,omitempty"`
+	LastSequence int64   `json:"last_sequence"`
+}
+
+// State captures the session token, team selection and event stream position
+func (p *Platform) State() (*SessionState, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cstr := C.communicator_platform_save_state(p.handle)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var state SessionState
+	if err := p.decode([]byte(C.GoString(cstr)), &state); err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// RestoreState reconnects with a saved session instead of calling Connect
+// The next SubscribeEvents resumes the event stream where the server still can.
+// The proxy, TLS, timeout, header, rate limit and retry settings of config are
+// applied as Connect applies them; its credentials and team aren't needed, as
+// the session carries them. A nil config keeps the defaults.
+func (p *Platform) RestoreState(state *SessionState, config *PlatformConfig) error {
+	if !p.acquireExclusive() {
+		return ErrClosed
+	}
+	defer p.releaseExclusive()
+
+	stateJSON, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	cState, freeState := cStringFree(string(stateJSON))
+	defer freeState()
+
+	var cConfig *C.char
+	if config != nil {
+		configJSON, err := json.Marshal(config)
+		if err != nil {
+			return err
+		}
+		var freeConfig func()
+		cConfig, freeConfig = cStringFree(string(configJSON))
+		defer freeConfig()
+	}
+
+	code := C.communicator_platform_restore_state(p.handle, cState, cConfig)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
+// SaveState writes the session state to a file readable only by the current user
+// Save before exiting and skip Disconnect, which would end the session
+func (p *Platform) SaveState(path string) error {
+	return p.SaveEncryptedState(path, nil)
+}
+
+// SaveEncryptedState writes the session state to a file encrypted with cipher
+// A nil cipher writes plaintext JSON
+func (p *Platform) SaveEncryptedState(path string, cipher FileCipher) error {
+	state, err := p.State()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if cipher != nil {
+		if data, err = cipher.Encrypt(data); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadState reconnects using a file written by SaveState, with the network
+// settings of config as for RestoreState
+// If the session has expired an error is returned and the caller should Connect instead
+func (p *Platform) LoadState(path string, config *PlatformConfig) error {
+	return p.LoadEncryptedState(path, nil, config)
+}
+
+// LoadEncryptedState reconnects using a file written by SaveEncryptedState
+func (p *Platform) LoadEncryptedState(path string, cipher FileCipher, config *PlatformConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if cipher != nil {
+		if data, err = cipher.Decrypt(data); err != nil {
+			return err
+		}
+	}
+
+	var state SessionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	return p.RestoreState(&state, config)
+}
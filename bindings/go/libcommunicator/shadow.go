@@ -0,0 +1,216 @@
+package libcommunicator
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// ShadowCacheOptions controls what a ShadowCache keeps and for how long
+type ShadowCacheOptions struct {
+	// Retention is how long a message is kept after it was last posted or
+	// edited; defaults to 24 hours
+	Retention time.Duration
+	// MaxEntries caps the number of messages kept, evicting the least recently
+	// seen first; defaults to 10000
+	MaxEntries int
+	// ChannelIDs limits the cache to these channels; empty means every channel
+	ChannelIDs []string
+	// KeepAttachments keeps attachment details; by default only text is kept
+	KeepAttachments bool
+	// KeepMetadata keeps platform metadata such as props and reactions
+	KeepMetadata bool
+	// Redact, if set, is applied to every message before it is stored, e.g. to
+	// mask personal data. Returning nil skips the message.
+	Redact func(*Message) *Message
+	// KeepAfterDelete keeps a deleted message cached until it expires; by
+	// default it is dropped once its delete handlers have run
+	KeepAfterDelete bool
+}
+
+// DeletedMessageHandler handles a message deleted event together with the
+// deleted message's last-known content, which is nil if it was never seen
+type DeletedMessageHandler func(event *Event, msg *Message)
+
+// ShadowCache remembers recently posted and edited messages so moderation and
+// audit handlers can see what a message said after it has been deleted
+// Message content is kept in memory only. Use the options to limit what is
+// kept, and Forget or ForgetUser to honour erasure requests.
+type ShadowCache struct {
+	opts     ShadowCacheOptions
+	channels map[string]bool
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // least recently seen at the front
+}
+
+type shadowEntry struct {
+	id       string
+	senderID string // as sent, in case Redact masks it
+	msg      *Message
+	expires  time.Time
+}
+
+// NewShadowCache creates an empty cache
+func NewShadowCache(opts ShadowCacheOptions) *ShadowCache {
+	if opts.Retention <= 0 {
+		opts.Retention = 24 * time.Hour
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = 10000
+	}
+	c := &ShadowCache{
+		opts:     opts,
+		channels: make(map[string]bool),
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+	for _, id := range opts.ChannelIDs {
+		c.channels[id] = true
+	}
+	return c
+}
+
+// Register installs the cache as a message posted and updated handler on the router
+// Register it before any handler that may delete messages.
+func (c *ShadowCache) Register(r *EventRouter) {
+	r.OnMessagePosted(c.handleEvent)
+	r.OnMessageUpdated(c.handleEvent)
+}
+
+// OnDeleted registers a handler that receives deleted messages' last-known content
+func (c *ShadowCache) OnDeleted(r *EventRouter, handler DeletedMessageHandler) {
+	r.OnMessageDeleted(c.HandleDeleted(handler))
+}
+
+// HandleDeleted wraps a DeletedMessageHandler as an EventHandler, for routers
+// or pools that take plain handlers
+func (c *ShadowCache) HandleDeleted(handler DeletedMessageHandler) EventHandler {
+	return func(event *Event) {
+		msg, _ := c.Lookup(event.MessageID)
+		handler(event, msg)
+		if !c.opts.KeepAfterDelete {
+			c.Forget(event.MessageID)
+		}
+	}
+}
+
+func (c *ShadowCache) handleEvent(event *Event) {
+	msg, err := eventMessage(event)
+	if err != nil || msg.ID == "" {
+		return
+	}
+	c.Remember(msg)
+}
+
+// Remember stores a message, replacing any earlier version of it
+func (c *ShadowCache) Remember(msg *Message) {
+	if len(c.channels) > 0 && !c.channels[msg.ChannelID] {
+		return
+	}
+
+	kept := *msg
+	kept.Extras = nil
+	if !c.opts.KeepAttachments {
+		kept.Attachments = nil
+	}
+	if !c.opts.KeepMetadata {
+		kept.Metadata = nil
+	}
+	stored := &kept
+	if c.opts.Redact != nil {
+		if stored = c.opts.Redact(stored); stored == nil {
+			c.Forget(msg.ID)
+			return
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.pruneLocked(now)
+
+	entry := &shadowEntry{id: msg.ID, senderID: msg.SenderID, msg: stored, expires: now.Add(c.opts.Retention)}
+	if el, ok := c.entries[msg.ID]; ok {
+		el.Value = entry
+		c.order.MoveToBack(el)
+		return
+	}
+	c.entries[msg.ID] = c.order.PushBack(entry)
+	for c.order.Len() > c.opts.MaxEntries {
+		c.removeLocked(c.order.Front())
+	}
+}
+
+// Lookup returns the last-known content of a message
+func (c *ShadowCache) Lookup(messageID string) (*Message, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[messageID]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*shadowEntry)
+	if !time.Now().Before(entry.expires) {
+		c.removeLocked(el)
+		return nil, false
+	}
+	msg := *entry.msg
+	return &msg, true
+}
+
+// Forget drops a message from the cache
+func (c *ShadowCache) Forget(messageID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[messageID]; ok {
+		c.removeLocked(el)
+	}
+}
+
+// ForgetUser drops every message a user sent
+func (c *ShadowCache) ForgetUser(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for el := c.order.Front(); el != nil; {
+		next := el.Next()
+		if el.Value.(*shadowEntry).senderID == userID {
+			c.removeLocked(el)
+		}
+		el = next
+	}
+}
+
+// Purge drops every cached message
+func (c *ShadowCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// Len returns the number of cached messages, including expired ones not yet pruned
+func (c *ShadowCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// pruneLocked drops expired entries from the front of the list
+// Entries are ordered by when they were last seen, and so by expiry.
+func (c *ShadowCache) pruneLocked(now time.Time) {
+	for el := c.order.Front(); el != nil; el = c.order.Front() {
+		if now.Before(el.Value.(*shadowEntry).expires) {
+			return
+		}
+		c.removeLocked(el)
+	}
+}
+
+func (c *ShadowCache) removeLocked(el *list.Element) {
+	delete(c.entries, el.Value.(*shadowEntry).id)
+	c.order.Remove(el)
+}
@@ -0,0 +1,339 @@
+package libcommunicator
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LockStore is a shared store of expiring locks used to coordinate replicas
+// Implementations backed by Redis (SET NX PX), etcd leases or a database row
+// work; every replica must use the same store.
+type LockStore interface {
+	// TryLock acquires key for owner, or extends it if owner already holds it.
+	// It returns false if another owner holds an unexpired lock on key.
+	TryLock(key, owner string, ttl time.Duration) (bool, error)
+	// Unlock releases key if owner holds it
+	Unlock(key, owner string) error
+	// Holders returns the owner of every unexpired lock whose key has the prefix
+	Holders(prefix string) (map[string]string, error)
+}
+
+// MemoryLockStore is a LockStore for coordinators running in a single process
+type MemoryLockStore struct {
+	mu    sync.Mutex
+	locks map[string]memoryLock
+	now   func() time.Time
+}
+
+type memoryLock struct {
+	owner   string
+	expires time.Time
+}
+
+// NewMemoryLockStore creates an empty in-memory lock store
+func NewMemoryLockStore() *MemoryLockStore {
+	return &MemoryLockStore{locks: make(map[string]memoryLock), now: time.Now}
+}
+
+// TryLock acquires or extends key for owner
+func (s *MemoryLockStore) TryLock(key, owner string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if lock, ok := s.locks[key]; ok && lock.owner != owner && now.Before(lock.expires) {
+		return false, nil
+	}
+	s.locks[key] = memoryLock{owner: owner, expires: now.Add(ttl)}
+	return true, nil
+}
+
+// Unlock releases key if owner holds it
+func (s *MemoryLockStore) Unlock(key, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if lock, ok := s.locks[key]; ok && lock.owner == owner {
+		delete(s.locks, key)
+	}
+	return nil
+}
+
+// Holders returns the owners of unexpired locks with the prefix
+func (s *MemoryLockStore) Holders(prefix string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	holders := make(map[string]string)
+	for key, lock := range s.locks {
+		if strings.HasPrefix(key, prefix) && now.Before(lock.expires) {
+			holders[key] = lock.owner
+		}
+	}
+	return holders, nil
+}
+
+// ShardOptions configures a ShardCoordinator
+type ShardOptions struct {
+	// Shards is the number of partitions channels are hashed into (default 64).
+	// All replicas must use the same value.
+	Shards int
+	// LeaseTTL is how long a shard stays owned without renewal (default 30s)
+	LeaseTTL time.Duration
+	// LeaseMargin is taken off each lease locally, so a replica stops handling
+	// a shard before the store can give it to another despite clock drift
+	// between them (default LeaseTTL/10)
+	LeaseMargin time.Duration
+	// RenewInterval is how often leases are renewed and shards rebalanced (default LeaseTTL/3)
+	RenewInterval time.Duration
+	// KeyPrefix namespaces the coordinator's locks (default "libcommunicator/shards/")
+	KeyPrefix string
+}
+
+func (o ShardOptions) withDefaults() ShardOptions {
+	if o.Shards <= 0 {
+		o.Shards = 64
+	}
+	if o.LeaseTTL <= 0 {
+		o.LeaseTTL = 30 * time.Second
+	}
+	if o.LeaseMargin <= 0 || o.LeaseMargin >= o.LeaseTTL {
+		o.LeaseMargin = o.LeaseTTL / 10
+	}
+	if o.RenewInterval <= 0 {
+		o.RenewInterval = o.LeaseTTL / 3
+	}
+	if o.KeyPrefix == "" {
+		o.KeyPrefix = "libcommunicator/shards/"
+	}
+	return o
+}
+
+// ShardCoordinator partitions channels across bot replicas
+// Channels are hashed into a fixed number of shards and each shard is leased
+// by one replica at a time, so every channel's events are handled by exactly
+// one instance. Shards are rebalanced as replicas join and leave.
+type ShardCoordinator struct {
+	store LockStore
+	id    string
+	opts  ShardOptions
+
+	// OnError, if set, receives lock store failures
+	OnError func(error)
+	// OnRebalance, if set, is called with the owned shards whenever they change
+	OnRebalance func(shards []int)
+
+	mu    sync.RWMutex
+	owned map[int]time.Time // shard -> lease expiry
+	now   func() time.Time
+}
+
+// NewShardCoordinator creates a coordinator for the replica with the given unique ID
+func NewShardCoordinator(store LockStore, replicaID string, opts ShardOptions) *ShardCoordinator {
+	return &ShardCoordinator{
+		store: store,
+		id:    replicaID,
+		opts:  opts.withDefaults(),
+		owned: make(map[int]time.Time),
+		now:   time.Now,
+	}
+}
+
+// Run keeps the replica's shard leases up to date until the context is cancelled
+// Leases are released on return so other replicas can take over immediately.
+func (c *ShardCoordinator) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.opts.RenewInterval)
+	defer ticker.Stop()
+	defer c.releaseAll()
+
+	c.rebalance()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			c.rebalance()
+		}
+	}
+}
+
+// ShardFor returns the shard a channel belongs to
+func (c *ShardCoordinator) ShardFor(channelID string) int {
+	return int(fnvHash(channelID) % uint32(c.opts.Shards))
+}
+
+// Owns reports whether this replica currently handles the channel
+func (c *ShardCoordinator) Owns(channelID string) bool {
+	shard := c.ShardFor(channelID)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	expires, ok := c.owned[shard]
+	return ok && c.now().Before(expires)
+}
+
+// Shards returns the shards this replica currently owns
+func (c *ShardCoordinator) Shards() []int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ownedLocked()
+}
+
+// Filter wraps a handler so it only sees events for channels this replica owns
+// Events without a channel belong to the owner of the empty channel ID's shard,
+// so they are still handled exactly once.
+func (c *ShardCoordinator) Filter(handler EventHandler) EventHandler {
+	return func(event *Event) {
+		if c.Owns(eventChannelID(event)) {
+			handler(event)
+		}
+	}
+}
+
+func (c *ShardCoordinator) memberKey(id string) string {
+	return c.opts.KeyPrefix + "members/" + id
+}
+
+func (c *ShardCoordinator) shardKey(shard int) string {
+	return c.opts.KeyPrefix + "shard/" + strconv.Itoa(shard)
+}
+
+// rebalance renews owned leases, then gives up or claims shards so each live
+// replica ends up with a fair share
+func (c *ShardCoordinator) rebalance() {
+	before := c.Shards()
+
+	if _, err := c.store.TryLock(c.memberKey(c.id), c.id, c.opts.LeaseTTL); err != nil {
+		c.reportError(fmt.Errorf("shard heartbeat: %w", err))
+	}
+	members, err := c.store.Holders(c.opts.KeyPrefix + "members/")
+	if err != nil {
+		c.reportError(fmt.Errorf("shard members: %w", err))
+		members = nil
+	}
+	replicas := len(members)
+	if replicas == 0 {
+		replicas = 1
+	}
+	target := (c.opts.Shards + replicas - 1) / replicas
+
+	// Renew what we hold; a lease we cannot renew is dropped locally so two
+	// replicas never believe they own the same shard
+	for _, shard := range before {
+		start := c.now()
+		ok, err := c.store.TryLock(c.shardKey(shard), c.id, c.opts.LeaseTTL)
+		if err != nil {
+			c.reportError(fmt.Errorf("renew shard %d: %w", shard, err))
+		}
+		c.setOwned(shard, ok && err == nil, start)
+	}
+
+	owned := c.Shards()
+	for len(owned) > target {
+		shard := owned[len(owned)-1]
+		owned = owned[:len(owned)-1]
+		c.setOwned(shard, false, time.Time{})
+		if err := c.store.Unlock(c.shardKey(shard), c.id); err != nil {
+			c.reportError(fmt.Errorf("release shard %d: %w", shard, err))
+		}
+	}
+
+	if len(owned) < target {
+		holders, err := c.store.Holders(c.opts.KeyPrefix + "shard/")
+		if err != nil {
+			c.reportError(fmt.Errorf("shard holders: %w", err))
+		} else {
+			// Start at a replica-specific offset so replicas joining together
+			// don't all contend for the same shards
+			start := int(fnvHash(c.id) % uint32(c.opts.Shards))
+			for i := 0; i < c.opts.Shards && len(owned) < target; i++ {
+				shard := (start + i) % c.opts.Shards
+				if _, held := holders[c.shardKey(shard)]; held {
+					continue
+				}
+				start := c.now()
+				ok, err := c.store.TryLock(c.shardKey(shard), c.id, c.opts.LeaseTTL)
+				if err != nil {
+					c.reportError(fmt.Errorf("claim shard %d: %w", shard, err))
+					continue
+				}
+				if ok {
+					c.setOwned(shard, true, start)
+					owned = append(owned, shard)
+				}
+			}
+		}
+	}
+
+	if after := c.Shards(); c.OnRebalance != nil && !equalInts(before, after) {
+		c.OnRebalance(after)
+	}
+}
+
+func (c *ShardCoordinator) releaseAll() {
+	for _, shard := range c.Shards() {
+		c.setOwned(shard, false, time.Time{})
+		if err := c.store.Unlock(c.shardKey(shard), c.id); err != nil {
+			c.reportError(fmt.Errorf("release shard %d: %w", shard, err))
+		}
+	}
+	if err := c.store.Unlock(c.memberKey(c.id), c.id); err != nil {
+		c.reportError(fmt.Errorf("shard heartbeat: %w", err))
+	}
+	if c.OnRebalance != nil {
+		c.OnRebalance(nil)
+	}
+}
+
+// setOwned records whether the replica owns a shard
+// A lease counts from start, taken before the lock store was asked: the
+// store's lease may have begun any time after, never before.
+func (c *ShardCoordinator) setOwned(shard int, owned bool, start time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if owned {
+		c.owned[shard] = start.Add(c.opts.LeaseTTL - c.opts.LeaseMargin)
+	} else {
+		delete(c.owned, shard)
+	}
+}
+
+func (c *ShardCoordinator) ownedLocked() []int {
+	shards := make([]int, 0, len(c.owned))
+	for shard := range c.owned {
+		shards = append(shards, shard)
+	}
+	sort.Ints(shards)
+	return shards
+}
+
+func (c *ShardCoordinator) reportError(err error) {
+	if c.OnError != nil {
+		c.OnError(err)
+	}
+}
+
+func fnvHash(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
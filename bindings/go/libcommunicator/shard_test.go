@@ -0,0 +1,90 @@
+package libcommunicator
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock that only moves when told to
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// slowLockStore is a lock store whose locking takes a while, as a remote one can
+type slowLockStore struct {
+	*MemoryLockStore
+	clock *fakeClock
+	delay time.Duration
+}
+
+func (s *slowLockStore) TryLock(key, owner string, ttl time.Duration) (bool, error) {
+	s.clock.Advance(s.delay)
+	return s.MemoryLockStore.TryLock(key, owner, ttl)
+}
+
+func TestShardLeaseEndsBeforeTheStoresLease(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	memory := NewMemoryLockStore()
+	memory.now = clock.Now
+	store := &slowLockStore{MemoryLockStore: memory, clock: clock, delay: 2 * time.Second}
+
+	opts := ShardOptions{Shards: 1, LeaseTTL: 30 * time.Second, LeaseMargin: 3 * time.Second}
+	a := NewShardCoordinator(store, "a", opts)
+	a.now = clock.Now
+	b := NewShardCoordinator(store, "b", opts)
+	b.now = clock.Now
+
+	// The membership lock takes 2s, then the shard lock 2s more: the store's
+	// lease runs 30s from 4s after the start
+	start := clock.Now()
+	a.rebalance()
+	if !a.Owns("c1") {
+		t.Fatal("a doesn't own the only shard")
+	}
+	storeExpiry := start.Add(4*time.Second + opts.LeaseTTL)
+
+	// a gives the shard up 3s before its lease counted from when it asked,
+	// which is 2s before the store's lease began
+	clock.Advance(start.Add(2*time.Second+opts.LeaseTTL-opts.LeaseMargin).Sub(clock.Now()) - time.Millisecond)
+	if !a.Owns("c1") {
+		t.Fatal("a stopped owning the shard before its lease was up")
+	}
+	clock.Advance(time.Millisecond)
+	if a.Owns("c1") {
+		t.Fatal("a still owns the shard at the end of its lease")
+	}
+	if holders, _ := memory.Holders(a.opts.KeyPrefix + "shard/"); len(holders) != 1 {
+		t.Fatal("the store's lease ended before the replica's")
+	}
+
+	// Once the store lets b take over, a has long stopped handling the shard
+	clock.Advance(storeExpiry.Sub(clock.Now()))
+	b.rebalance()
+	if !b.Owns("c1") {
+		t.Fatal("b didn't take over the expired shard")
+	}
+	if a.Owns("c1") {
+		t.Fatal("a and b both own the shard")
+	}
+}
+
+func TestShardOptionsKeepTheMarginWithinTheLease(t *testing.T) {
+	opts := ShardOptions{LeaseTTL: 10 * time.Second, LeaseMargin: time.Minute}.withDefaults()
+	if opts.LeaseMargin != time.Second {
+		t.Fatalf("LeaseMargin = %v, want the default of LeaseTTL/10", opts.LeaseMargin)
+	}
+}
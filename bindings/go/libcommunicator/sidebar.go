@@ -0,0 +1,247 @@
+package libcommunicator
+
+/*
+#include <communicator.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+)
+
+// SidebarCategoryType represents the kind of a sidebar category
+type SidebarCategoryType string
+
+const (
+	SidebarCategoryCustom         SidebarCategoryType = "custom"
+	SidebarCategoryFavorites      SidebarCategoryType = "favorites"
+	SidebarCategoryChannels       SidebarCategoryType = "channels"
+	SidebarCategoryDirectMessages SidebarCategoryType = "direct_messages"
+)
+
+// SidebarCategory represents a category in the user's channel sidebar
+type SidebarCategory struct {
+	ID          string              `json:"id"`
+	TeamID      string              `json:"team_id"`
+	DisplayName string              `json:"display_name"`
+	Type        SidebarCategoryType `json:"type"`
+	ChannelIDs  []string            `json:"channel_ids"`
+	Muted       bool                `json:"muted"`
+	Collapsed   bool                `json:"collapsed"`
+}
+
+// GetSidebarCategories retrieves the current user's sidebar categories for a team, in display order
+func (p *Platform) GetSidebarCategories(teamID string) ([]SidebarCategory, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	cTeamID, freeTeamID := cStringFree(teamID)
+	defer freeTeamID()
+
+	cstr := C.communicator_platform_get_sidebar_categories(p.handle, cTeamID)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var categories []SidebarCategory
+	if err := p.decode([]byte(C.GoString(cstr)), &categories); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// CreateSidebarCategory creates a custom sidebar category, optionally moving channels into it
+func (p *Platform) CreateSidebarCategory(teamID, displayName string, channelIDs []string) (_ *SidebarCategory, err error) {
+	defer p.audit("CreateSidebarCategory", "team_id", teamID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("CreateSidebarCategory"); err != nil {
+		return nil, err
+	}
+
+	if channelIDs == nil {
+		channelIDs = []string{}
+	}
+	jsonBytes, err := json.Marshal(channelIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cTeamID, freeTeamID := cStringFree(teamID)
+	defer freeTeamID()
+
+	cDisplayName, freeDisplayName := cStringFree(displayName)
+	defer freeDisplayName()
+
+	cJSON, freeJSON := cStringFree(string(jsonBytes))
+	defer freeJSON()
+
+	cstr := C.communicator_platform_create_sidebar_category(p.handle, cTeamID, cDisplayName, cJSON)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var category SidebarCategory
+	if err := p.decode([]byte(C.GoString(cstr)), &category); err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// UpdateSidebarCategory updates a sidebar category's name, channels, muted or collapsed state
+func (p *Platform) UpdateSidebarCategory(category *SidebarCategory) (_ *SidebarCategory, err error) {
+	var teamID, categoryID string
+	if category != nil {
+		teamID, categoryID = category.TeamID, category.ID
+	}
+	defer p.audit("UpdateSidebarCategory", "team_id", teamID, "category_id", categoryID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("UpdateSidebarCategory"); err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.Marshal(category)
+	if err != nil {
+		return nil, err
+	}
+
+	cJSON, freeJSON := cStringFree(string(jsonBytes))
+	defer freeJSON()
+
+	cstr := C.communicator_platform_update_sidebar_category(p.handle, cJSON)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var updated SidebarCategory
+	if err := p.decode([]byte(C.GoString(cstr)), &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteSidebarCategory deletes a custom sidebar category
+func (p *Platform) DeleteSidebarCategory(teamID, categoryID string) (err error) {
+	defer p.audit("DeleteSidebarCategory", "team_id", teamID, "category_id", categoryID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("DeleteSidebarCategory"); err != nil {
+		return err
+	}
+
+	cTeamID, freeTeamID := cStringFree(teamID)
+	defer freeTeamID()
+
+	cCategoryID, freeCategoryID := cStringFree(categoryID)
+	defer freeCategoryID()
+
+	code := C.communicator_platform_delete_sidebar_category(p.handle, cTeamID, cCategoryID)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
+// ReorderSidebarCategories sets the display order of a team's sidebar categories
+func (p *Platform) ReorderSidebarCategories(teamID string, categoryIDs []string) (err error) {
+	defer p.audit("ReorderSidebarCategories", "team_id", teamID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("ReorderSidebarCategories"); err != nil {
+		return err
+	}
+
+	jsonBytes, err := json.Marshal(categoryIDs)
+	if err != nil {
+		return err
+	}
+
+	cTeamID, freeTeamID := cStringFree(teamID)
+	defer freeTeamID()
+
+	cJSON, freeJSON := cStringFree(string(jsonBytes))
+	defer freeJSON()
+
+	code := C.communicator_platform_reorder_sidebar_categories(p.handle, cTeamID, cJSON)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
+// MoveChannelToCategory moves a channel into the given sidebar category
+func (p *Platform) MoveChannelToCategory(teamID, channelID, categoryID string) (err error) {
+	defer p.audit("MoveChannelToCategory", "team_id", teamID, "channel_id", channelID, "category_id", categoryID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("MoveChannelToCategory"); err != nil {
+		return err
+	}
+
+	cTeamID, freeTeamID := cStringFree(teamID)
+	defer freeTeamID()
+
+	cChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	cCategoryID, freeCategoryID := cStringFree(categoryID)
+	defer freeCategoryID()
+
+	code := C.communicator_platform_move_channel_to_category(p.handle, cTeamID, cChannelID, cCategoryID)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
+// SetChannelFavorite adds a channel to, or removes it from, the favorites category
+func (p *Platform) SetChannelFavorite(teamID, channelID string, favorite bool) (err error) {
+	defer p.audit("SetChannelFavorite", "team_id", teamID, "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("SetChannelFavorite"); err != nil {
+		return err
+	}
+
+	cTeamID, freeTeamID := cStringFree(teamID)
+	defer freeTeamID()
+
+	cChannelID, freeChannelID := cStringFree(channelID)
+	defer freeChannelID()
+
+	var favoriteInt C.int
+	if favorite {
+		favoriteInt = 1
+	}
+
+	code := C.communicator_platform_set_channel_favorite(p.handle, cTeamID, cChannelID, favoriteInt)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
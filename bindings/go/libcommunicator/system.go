@@ -0,0 +1,63 @@
+package libcommunicator
+
+import "strings"
+
+// Message types of common platform-generated messages
+// Types starting with "system_" are system messages; plugins may use
+// their own types, e.g. "custom_poll".
+const (
+	MessageTypeJoinChannel       = "system_join_channel"
+	MessageTypeLeaveChannel      = "system_leave_channel"
+	MessageTypeAddToChannel      = "system_add_to_channel"
+	MessageTypeRemoveFromChannel = "system_remove_from_channel"
+	MessageTypeJoinTeam          = "system_join_team"
+	MessageTypeLeaveTeam         = "system_leave_team"
+	MessageTypeHeaderChange      = "system_header_change"
+	MessageTypePurposeChange     = "system_purpose_change"
+	MessageTypeDisplayNameChange = "system_displayname_change"
+	MessageTypeChannelDeleted    = "system_channel_deleted"
+	MessageTypeEphemeral         = "system_ephemeral"
+)
+
+// systemMessagePrefix is shared by the types of platform-generated messages
+const systemMessagePrefix = "system_"
+
+// IsSystem reports whether the message was generated by the platform, such as
+// a join, leave or header change notice, rather than written by a user
+func (m *Message) IsSystem() bool {
+	return strings.HasPrefix(m.Type, systemMessagePrefix)
+}
+
+// WithoutSystemMessages returns the messages that are not system messages
+// The input slice is left unchanged.
+func WithoutSystemMessages(messages []Message) []Message {
+	kept := make([]Message, 0, len(messages))
+	for _, msg := range messages {
+		if !msg.IsSystem() {
+			kept = append(kept, msg)
+		}
+	}
+	return kept
+}
+
+// SetExcludeSystemMessages turns filtering of system messages on or off
+// While on, GetMessages, GetMessagesBefore, GetMessagesAfter,
+// GetMessagesSince, digests and jobs leave out system messages. Pages may
+// then hold fewer messages than the requested limit; keep paging from the
+// oldest message returned.
+func (p *Platform) SetExcludeSystemMessages(exclude bool) {
+	p.excludeSystem.Store(exclude)
+}
+
+// ExcludesSystemMessages reports whether system messages are filtered out
+func (p *Platform) ExcludesSystemMessages() bool {
+	return p.excludeSystem.Load()
+}
+
+// filterMessages applies the system message filter if it is on
+func (p *Platform) filterMessages(messages []Message) []Message {
+	if !p.excludeSystem.Load() {
+		return messages
+	}
+	return WithoutSystemMessages(messages)
+}
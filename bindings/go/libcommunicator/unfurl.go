@@ -0,0 +1,417 @@
+package libcommunicator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// LinkPreview holds the title and OpenGraph data scraped from a web page
+type LinkPreview struct {
+	URL         string
+	Title       string
+	Description string
+	SiteName    string
+	ImageURL    string
+}
+
+// ErrBlockedAddress is returned when a URL fetched by the Unfurler or a rule
+// webhook resolves to a non-public address
+var ErrBlockedAddress = errors.New("destination address is not public")
+
+// unfurlTimeout bounds fetching each link
+const unfurlTimeout = 10 * time.Second
+
+// Unfurler replies in-thread with a preview card for links posted in watched channels
+// Register it on an EventRouter to enable it
+type Unfurler struct {
+	platform *Platform
+	client   *http.Client
+
+	// MaxLinks is the most links previewed per message
+	MaxLinks int
+	// MaxBodyBytes caps how much of each page is read
+	MaxBodyBytes int64
+	// Concurrency is the number of messages unfurled at once (default 4);
+	// further messages wait their turn
+	Concurrency int
+	// OnError, if set, receives fetch and reply failures
+	OnError func(error)
+
+	mu       sync.RWMutex
+	channels map[string]bool
+	own      ownMessages
+}
+
+// NewUnfurler creates an unfurler for the given channels
+// With no channel IDs every channel the bot can see is watched
+func NewUnfurler(p *Platform, channelIDs ...string) *Unfurler {
+	u := &Unfurler{
+		platform:     p,
+		client:       newSafeHTTPClient(unfurlTimeout),
+		MaxLinks:     3,
+		MaxBodyBytes: 512 * 1024,
+		Concurrency:  4,
+		channels:     make(map[string]bool),
+	}
+	for _, id := range channelIDs {
+		u.channels[id] = true
+	}
+	return u
+}
+
+// Watch adds a channel to the watched set
+func (u *Unfurler) Watch(channelID string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.channels[channelID] = true
+}
+
+// Unwatch removes a channel from the watched set
+func (u *Unfurler) Unwatch(channelID string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.channels, channelID)
+}
+
+// Register installs the unfurler as a message posted handler on the router
+// Fetching can take seconds, so messages are unfurled on a worker pool of
+// Concurrency workers rather than holding up the router.
+func (u *Unfurler) Register(r *EventRouter) {
+	r.OnWithOptions(EventMessagePosted, u.handleEvent, HandlerOptions{Concurrency: u.Concurrency})
+}
+
+func (u *Unfurler) watching(channelID string) bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return len(u.channels) == 0 || u.channels[channelID]
+}
+
+func (u *Unfurler) handleEvent(event *Event) {
+	msg, err := eventMessage(event)
+	if err != nil {
+		u.reportError(err)
+		return
+	}
+	if !u.watching(msg.ChannelID) || u.own.isOwnMessage(u.platform, msg) {
+		return
+	}
+
+	if links := ExtractLinks(msg.Text, u.MaxLinks); len(links) > 0 {
+		u.unfurl(msg, links)
+	}
+}
+
+func (u *Unfurler) unfurl(msg *Message, links []string) {
+	var cards []string
+	for _, link := range links {
+		ctx, cancel := context.WithTimeout(context.Background(), unfurlTimeout)
+		preview, err := u.Fetch(ctx, link)
+		cancel()
+		if err != nil {
+			u.reportError(fmt.Errorf("unfurl %s: %w", link, err))
+			continue
+		}
+		if card := preview.Card(); card != "" {
+			cards = append(cards, card)
+		}
+	}
+	if len(cards) == 0 {
+		return
+	}
+
+	rootID := threadRootID(msg)
+	if _, err := u.platform.SendReply(msg.ChannelID, strings.Join(cards, "\n\n"), rootID); err != nil {
+		u.reportError(err)
+	}
+}
+
+// ownMessages recognises the messages of the platform's user, so bots never
+// act on what they posted themselves
+type ownMessages struct {
+	mu     sync.Mutex
+	userID string
+}
+
+// isOwnMessage reports whether the platform's user posted msg
+// The user is looked up on first use, and again until that succeeds.
+func (o *ownMessages) isOwnMessage(p *Platform, msg *Message) bool {
+	o.mu.Lock()
+	if o.userID == "" {
+		if me, err := p.GetCurrentUser(); err == nil {
+			o.userID = me.ID
+		}
+	}
+	userID := o.userID
+	o.mu.Unlock()
+
+	return userID != "" && msg.SenderID == userID
+}
+
+func (u *Unfurler) reportError(err error) {
+	if u.OnError != nil {
+		u.OnError(err)
+	}
+}
+
+// Fetch downloads a page and extracts its title and OpenGraph data
+// Only public http and https destinations are contacted
+func (u *Unfurler) Fetch(ctx context.Context, rawURL string) (*LinkPreview, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return nil, fmt.Errorf("unfurl: unsupported scheme %q", req.URL.Scheme)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", "libcommunicator-unfurler/1.0")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unfurl: unexpected status %s", resp.Status)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, fmt.Errorf("unfurl: not an HTML page (%s)", mediaType)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, u.MaxBodyBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	preview := ParseLinkPreview(string(body))
+	preview.URL = resp.Request.URL.String()
+	return preview, nil
+}
+
+// Card renders the preview as a Markdown block quote, or "" if there is nothing to show
+func (l *LinkPreview) Card() string {
+	title := l.Title
+	if title == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	if l.SiteName != "" {
+		fmt.Fprintf(&b, "> %s\n", cardText(l.SiteName))
+	}
+	fmt.Fprintf(&b, "> **[%s](%s)**", cardText(title), linkTargetEscaper.Replace(l.URL))
+	if l.Description != "" {
+		fmt.Fprintf(&b, "\n> %s", cardText(l.Description))
+	}
+	return b.String()
+}
+
+var (
+	linkPattern      = regexp.MustCompile(`https?://[^\s<>()\[\]"'` + "`" + `]+`)
+	titlePattern     = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	metaPattern      = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attributePattern = regexp.MustCompile(`(?is)([a-z:_-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+)
+
+// ExtractLinks returns up to limit distinct http(s) URLs found in text
+func ExtractLinks(text string, limit int) []string {
+	var links []string
+	seen := make(map[string]bool)
+	for _, match := range linkPattern.FindAllString(text, -1) {
+		link := strings.TrimRight(match, ".,;:!?*_~")
+		if seen[link] || !validUnfurlURL(link) {
+			continue
+		}
+		seen[link] = true
+		links = append(links, link)
+		if limit > 0 && len(links) == limit {
+			break
+		}
+	}
+	return links
+}
+
+// ParseLinkPreview extracts the title and OpenGraph properties from an HTML document
+func ParseLinkPreview(doc string) *LinkPreview {
+	preview := &LinkPreview{}
+	meta := make(map[string]string)
+
+	for _, tag := range metaPattern.FindAllString(doc, -1) {
+		attrs := make(map[string]string)
+		for _, m := range attributePattern.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(m[1])] = m[2] + m[3] + m[4]
+		}
+		key := attrs["property"]
+		if key == "" {
+			key = attrs["name"]
+		}
+		if key != "" {
+			if _, exists := meta[strings.ToLower(key)]; !exists {
+				meta[strings.ToLower(key)] = cleanText(attrs["content"])
+			}
+		}
+	}
+
+	preview.Title = meta["og:title"]
+	if preview.Title == "" {
+		if m := titlePattern.FindStringSubmatch(doc); m != nil {
+			preview.Title = cleanText(m[1])
+		}
+	}
+	preview.Description = meta["og:description"]
+	if preview.Description == "" {
+		preview.Description = meta["description"]
+	}
+	preview.SiteName = meta["og:site_name"]
+	preview.ImageURL = meta["og:image"]
+
+	return preview
+}
+
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// linkTargetEscaper percent-encodes what would end a Markdown link target or
+// the line it is on
+var linkTargetEscaper = strings.NewReplacer(
+	"(", "%28", ")", "%29", "[", "%5B", "]", "%5D", " ", "%20",
+	"\r", "%0D", "\n", "%0A", "\t", "%09", "<", "%3C", ">", "%3E",
+)
+
+// cardText escapes s for one line of a card, folding line breaks that would
+// end the block quote
+func cardText(s string) string {
+	return escapeMarkdown(strings.Join(strings.Fields(s), " "))
+}
+
+// newSafeHTTPClient builds a client that refuses to connect to non-public
+// addresses, for fetching URLs taken from messages or rules. The check runs on the resolved address at dial time, so it also
+// covers redirects and DNS rebinding.
+func newSafeHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublicIP(ip) {
+				return ErrBlockedAddress
+			}
+			return nil
+		},
+	}
+
+	transport := &http.Transport{
+		Proxy:                 nil, // a proxy would bypass the dial-time address check
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 3 {
+				return errors.New("too many redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+// blockedNetworks are special-purpose ranges the net.IP predicates don't cover
+var blockedNetworks = parseCIDRs(
+	"0.0.0.0/8",     // "this network"; 0.0.0.0 reaches the local host
+	"100.64.0.0/10", // carrier-grade NAT
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // benchmarking
+	"240.0.0.0/4",   // reserved, including broadcast
+	"64:ff9b::/96",  // NAT64, which translates to any IPv4 address
+)
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// eventMessage decodes the Message carried by a message event
+func eventMessage(event *Event) (*Message, error) {
+	raw, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, err
+	}
+	var msg Message
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// threadRootID returns the ID of the thread a message belongs to
+func threadRootID(msg *Message) string {
+	if msg.RootID != "" {
+		return msg.RootID
+	}
+	if msg.Metadata != nil && msg.Metadata.RootID != "" {
+		return msg.Metadata.RootID
+	}
+	return msg.ID
+}
+
+// validUnfurlURL reports whether rawURL is an absolute http(s) URL
+func validUnfurlURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
@@ -0,0 +1,102 @@
+package libcommunicator
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.216.34":        true,
+		"2606:2800:220:1::248": true,
+		"127.0.0.1":            false,
+		"10.1.2.3":             false,
+		"169.254.169.254":      false,
+		"100.64.0.1":           false,
+		"0.0.0.0":              false,
+		"0.1.2.3":              false,
+		"192.0.0.8":            false,
+		"198.18.0.1":           false,
+		"198.19.255.255":       false,
+		"240.0.0.1":            false,
+		"255.255.255.255":      false,
+		"::1":                  false,
+		"fd00::1":              false,
+		"::ffff:10.0.0.1":      false,
+		"64:ff9b::a00:1":       false,
+		"64:ff9b::5db8:d822":   false,
+	}
+	for addr, want := range tests {
+		if got := isPublicIP(net.ParseIP(addr)); got != want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestLinkPreviewCardEscapesTheLink(t *testing.T) {
+	preview := &LinkPreview{
+		URL:         "https://example.com/a_(b)\n> injected",
+		Title:       "Title] (x)\n> **fake**",
+		Description: "line one\nline two",
+	}
+	card := preview.Card()
+
+	lines := strings.Split(card, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("card has %d lines, want 2:\n%s", len(lines), card)
+	}
+	want := `> **[Title\] (x) \> \*\*fake\*\*](https://example.com/a_%28b%29%0A%3E%20injected)**`
+	if lines[0] != want {
+		t.Fatalf("link line = %s\nwant        %s", lines[0], want)
+	}
+	if lines[1] != "> line one line two" {
+		t.Fatalf("description line = %s", lines[1])
+	}
+}
+
+func TestUnfurlerBoundsConcurrentFetches(t *testing.T) {
+	var running, peak, fetched atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := running.Add(1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		<-release
+		running.Add(-1)
+		fetched.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<title>Page</title>")
+	}))
+	defer server.Close()
+
+	u := NewUnfurler(&Platform{})
+	u.client = server.Client()
+	u.Concurrency = 2
+
+	r := NewEventRouter()
+	u.Register(r)
+	for i := range 6 {
+		r.Handle(&Event{Type: EventMessagePosted, Data: map[string]interface{}{
+			"id":   fmt.Sprint(i),
+			"text": fmt.Sprintf("see %s/%d", server.URL, i),
+		}})
+	}
+	waitFor(t, "two pages to be fetched", func() bool { return running.Load() == 2 })
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	r.Close()
+
+	if peak.Load() != 2 || fetched.Load() != 6 {
+		t.Fatalf("fetched %d pages, at most %d at once; want 6, 2 at once", fetched.Load(), peak.Load())
+	}
+}
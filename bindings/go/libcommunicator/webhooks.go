@@ -0,0 +1,158 @@
+package libcommunicator
+
+/*
+#include <communicator.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"time"
+)
+
+// IncomingWebhook is a webhook that lets external systems post to a channel
+type IncomingWebhook struct {
+	ID            string    `json:"id"`
+	ChannelID     string    `json:"channel_id"`
+	TeamID        string    `json:"team_id"`
+	CreatorID     string    `json:"creator_id"`
+	DisplayName   string    `json:"display_name"`
+	Description   string    `json:"description"`
+	Username      *string   `json:"username,omitempty"`
+	IconURL       *string   `json:"icon_url,omitempty"`
+	ChannelLocked bool      `json:"channel_locked"`
+	URL           string    `json:"url"` // secret URL to post payloads to
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// NewIncomingWebhook holds the settings for CreateIncomingWebhook
+type NewIncomingWebhook struct {
+	ChannelID     string  `json:"channel_id"`
+	DisplayName   string  `json:"display_name,omitempty"`
+	Description   string  `json:"description,omitempty"`
+	Username      *string `json:"username,omitempty"`
+	IconURL       *string `json:"icon_url,omitempty"`
+	ChannelLocked bool    `json:"channel_locked"` // only allow posting to ChannelID
+}
+
+// WebhookPayload is a message posted through an incoming webhook
+type WebhookPayload struct {
+	Text        string                     `json:"text,omitempty"`
+	Channel     string                     `json:"channel,omitempty"` // channel name or "@username" override
+	Username    string                     `json:"username,omitempty"`
+	IconURL     string                     `json:"icon_url,omitempty"`
+	IconEmoji   string                     `json:"icon_emoji,omitempty"`
+	Attachments []json.RawMessage          `json:"attachments,omitempty"`
+	Props       map[string]json.RawMessage `json:"props,omitempty"`
+}
+
+// CreateIncomingWebhook creates an incoming webhook; its URL is a secret
+func (p *Platform) CreateIncomingWebhook(hook *NewIncomingWebhook) (_ *IncomingWebhook, err error) {
+	var channelID string
+	if hook != nil {
+		channelID = hook.ChannelID
+	}
+	defer p.audit("CreateIncomingWebhook", "channel_id", channelID)(&err)
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("CreateIncomingWebhook"); err != nil {
+		return nil, err
+	}
+
+	hookJSON, err := json.Marshal(hook)
+	if err != nil {
+		return nil, err
+	}
+
+	csHook, freeHook := cStringFree(string(hookJSON))
+	defer freeHook()
+
+	cstr := C.communicator_platform_create_incoming_webhook(p.handle, csHook)
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var created IncomingWebhook
+	if err := p.decode([]byte(C.GoString(cstr)), &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// ListIncomingWebhooks lists a page of incoming webhooks
+// An empty teamID lists the webhooks of every team the user can manage
+func (p *Platform) ListIncomingWebhooks(teamID string, page, perPage uint32) ([]IncomingWebhook, error) {
+	if !p.acquire() {
+		return nil, ErrClosed
+	}
+	defer p.release()
+
+	var csTeamID *C.char
+	if teamID != "" {
+		var freeTeamID func()
+		csTeamID, freeTeamID = cStringFree(teamID)
+		defer freeTeamID()
+	}
+
+	cstr := C.communicator_platform_list_incoming_webhooks(p.handle, csTeamID, C.uint32_t(page), C.uint32_t(perPage))
+	if cstr == nil {
+		return nil, getLastError()
+	}
+	defer freeString(cstr)
+
+	var hooks []IncomingWebhook
+	if err := p.decode([]byte(C.GoString(cstr)), &hooks); err != nil {
+		return nil, err
+	}
+
+	return hooks, nil
+}
+
+// DeleteIncomingWebhook deletes an incoming webhook
+func (p *Platform) DeleteIncomingWebhook(hookID string) (err error) {
+	defer p.audit("DeleteIncomingWebhook", "hook_id", hookID)(&err)
+	if !p.acquire() {
+		return ErrClosed
+	}
+	defer p.release()
+	if err := p.checkWritable("DeleteIncomingWebhook"); err != nil {
+		return err
+	}
+
+	csHookID, freeHookID := cStringFree(hookID)
+	defer freeHookID()
+
+	code := C.communicator_platform_delete_incoming_webhook(p.handle, csHookID)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
+
+// SendViaWebhook posts a message through an incoming webhook URL
+// It needs no Platform or login, only Init
+func SendViaWebhook(url string, payload *WebhookPayload) error {
+	defer pinThread()()
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	csURL, freeURL := cStringFree(url)
+	defer freeURL()
+
+	csPayload, freePayload := cStringFree(string(payloadJSON))
+	defer freePayload()
+
+	code := C.communicator_send_webhook(csURL, csPayload)
+	if code != C.COMMUNICATOR_SUCCESS {
+		return getLastError()
+	}
+
+	return nil
+}
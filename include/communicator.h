@@ -29,11 +29,15 @@ typedef enum {
     COMMUNICATOR_ERROR_INVALID_STATE = 11,
     COMMUNICATOR_ERROR_UNSUPPORTED = 12,
     COMMUNICATOR_ERROR_RATE_LIMITED = 13,
+    COMMUNICATOR_ERROR_UNSUPPORTED_BY_SERVER = 14,
 } CommunicatorErrorCode;
 
 /**
  * Get the error code of the last error
  *
+ * The last error is kept per thread: call this on the thread that made the
+ * failing call.
+ *
  * @return The error code, or COMMUNICATOR_SUCCESS if no error occurred
  */
 CommunicatorErrorCode communicator_last_error_code(void);
@@ -215,6 +219,12 @@ typedef void (*CommunicatorLogCallback)(
 /**
  * Set a log callback on a context
  *
+ * Besides the context's own messages, the callback receives the library's
+ * diagnostics about connections, the WebSocket, retries and platforms. These
+ * go to the callback of every context that has one and may arrive on the
+ * library's threads. Messages logged while a callback runs are dropped, so
+ * a callback may call into the library, but should hand slow work off.
+ *
  * @param handle The context handle
  * @param callback The callback function
  * @param user_data Opaque pointer passed back to the callback
@@ -229,6 +239,9 @@ CommunicatorErrorCode communicator_context_set_log_callback(
 /**
  * Clear the log callback on a context
  *
+ * Returns once no call to the old callback is in progress, so its user data
+ * may be freed.
+ *
  * @param handle The context handle
  * @return Error code indicating success or failure
  */
@@ -245,12 +258,36 @@ CommunicatorErrorCode communicator_context_clear_log_callback(CommunicatorContex
  */
 void communicator_free_string(char* s);
 
+/**
+ * Parse a message link (permalink) from any supported platform
+ *
+ * Recognizes Mattermost (https://host/team/pl/post_id) and Slack
+ * (https://workspace.slack.com/archives/channel/p...) links. No platform
+ * connection is needed.
+ *
+ * @param url The link to parse
+ * @return A JSON string representing the MessageLink
+ *         ({"platform", "server_url", "team", "channel", "message_id"})
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_parse_message_link(const char* url);
+
 // ============================================================================
 // Platform API - Mattermost Integration
 // ============================================================================
 
 /**
  * Opaque handle to a Platform object
+ *
+ * A handle may be used from several threads at once, with two exceptions.
+ * communicator_platform_connect, communicator_platform_connect_with_mfa,
+ * communicator_platform_restore_state, communicator_platform_disconnect,
+ * communicator_platform_subscribe_events,
+ * communicator_platform_subscribe_events_filtered and
+ * communicator_platform_unsubscribe_events change the platform and must not
+ * run alongside any other call on the same handle. communicator_platform_destroy
+ * must not run alongside any call on the handle, nor be followed by one.
  */
 typedef void* CommunicatorPlatform;
 
@@ -273,8 +310,55 @@ CommunicatorPlatform communicator_mattermost_create(const char* server_url);
  *                      "credentials": {
  *                        "token": "xxx" OR "login_id": "user@example.com", "password": "xxx"
  *                      },
- *                      "team_id": "optional-team-id"
+ *                      "team_id": "optional-team-id",
+ *                      "proxy_url": "optional http://, socks5:// or socks5h:// proxy URL",
+ *                      "no_proxy": "optional comma-separated hosts to reach directly",
+ *                      "ca_cert": "optional extra CA certificate(s), PEM text or file path",
+ *                      "client_cert": "optional client certificate, PEM text or file path",
+ *                      "client_key": "optional client private key, PEM text or file path",
+ *                      "insecure_skip_verify": false,
+ *                      "connect_timeout_ms": 0,
+ *                      "request_timeout_ms": 0,
+ *                      "handshake_timeout_ms": 0,
+ *                      "idle_timeout_ms": 0,
+ *                      "rate_limit_max_retries": 3,
+ *                      "rate_limit_max_wait_ms": 60000,
+ *                      "retry_max_attempts": 3,
+ *                      "retry_initial_backoff_ms": 200,
+ *                      "retry_max_backoff_ms": 5000,
+ *                      "retry_backoff_multiplier": 2.0,
+ *                      "retry_jitter": 0.2,
+ *                      "retry_on": ["network", "server_error"],
+ *                      "headers": {"CF-Access-Client-Id": "optional header"},
+ *                      "user_agent": "optional User-Agent"
  *                    }
+ *                    The proxy carries both REST calls and the WebSocket.
+ *                    Without proxy_url, REST calls honor HTTP_PROXY,
+ *                    HTTPS_PROXY and NO_PROXY from the environment.
+ *                    The TLS settings apply to both connections as well;
+ *                    insecure_skip_verify is only meant for test servers.
+ *                    Timeouts are optional; 0 keeps the default (30 s for
+ *                    requests, no limit otherwise). Timed-out requests fail
+ *                    with COMMUNICATOR_ERROR_TIMEOUT; an idle WebSocket is
+ *                    reconnected.
+ *                    Requests rejected with HTTP 429 are retried after the
+ *                    wait the server asks for, up to rate_limit_max_retries
+ *                    times (default 3, 0 disables) and as long as the wait
+ *                    is within rate_limit_max_wait_ms (default 60 s). Each
+ *                    rejection is reported as a "rate_limited" event.
+ *                    Requests failing for a transient reason are tried up
+ *                    to retry_max_attempts times in all, with exponential
+ *                    backoff. retry_on picks the failures retried:
+ *                    "network" (connection failures), "timeout" and
+ *                    "server_error" (HTTP 502, 503 and 504). GET, PUT and
+ *                    DELETE are retried for any of them, POST only when the
+ *                    connection could not be made. The values shown are
+ *                    the defaults.
+ *                    headers are added to every REST request and to the
+ *                    WebSocket handshake, e.g. for an authenticating proxy;
+ *                    Authorization can't be set, as it carries the session.
+ *                    user_agent replaces the default of
+ *                    "libcommunicator/<version>".
  * @return Error code indicating success or failure
  */
 CommunicatorErrorCode communicator_platform_connect(
@@ -308,6 +392,41 @@ CommunicatorErrorCode communicator_platform_connect_with_mfa(
     const char* config_json
 );
 
+/**
+ * Capture the session so a restarted process can resume it
+ *
+ * The result contains the session token; store it like a password.
+ * Don't call communicator_platform_disconnect before exiting, as that ends the session.
+ *
+ * @param platform The platform handle
+ * @return A JSON string representing the SessionState
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_save_state(
+    CommunicatorPlatform platform
+);
+
+/**
+ * Reconnect using a session captured by communicator_platform_save_state
+ *
+ * Use instead of communicator_platform_connect. The next communicator_platform_subscribe_events
+ * call resumes the saved event stream where the server still can.
+ *
+ * @param platform The platform handle
+ * @param state_json JSON SessionState as returned by communicator_platform_save_state
+ * @param config_json JSON config as for communicator_platform_connect (may be NULL);
+ *        its proxy, TLS, timeout, header and retry settings are applied as
+ *        connecting would, and credentials are not needed
+ * @return Error code indicating success or failure
+ *         COMMUNICATOR_ERROR_AUTH_FAILED if the session is no longer valid
+ */
+CommunicatorErrorCode communicator_platform_restore_state(
+    CommunicatorPlatform platform,
+    const char* state_json,
+    const char* config_json
+);
+
 /**
  * Disconnect from a platform
  *
@@ -427,6 +546,36 @@ char* communicator_platform_get_user(
  */
 char* communicator_platform_get_current_user(CommunicatorPlatform platform);
 
+/**
+ * Download a user's profile image
+ *
+ * @param platform The platform handle
+ * @param user_id The ID of the user
+ * @param out_data Output parameter for the image data (must be freed with communicator_free_file_data)
+ * @param out_size Output parameter for the size of the image data in bytes
+ * @return COMMUNICATOR_SUCCESS on success, error code otherwise
+ */
+CommunicatorErrorCode communicator_platform_get_user_avatar(
+    CommunicatorPlatform platform,
+    const char* user_id,
+    uint8_t** out_data,
+    size_t* out_size
+);
+
+/**
+ * Set the current user's profile image
+ *
+ * @param platform The platform handle
+ * @param data The image data (png, jpeg, gif or bmp); copied before returning
+ * @param size Size of the image data in bytes
+ * @return COMMUNICATOR_SUCCESS on success, error code otherwise
+ */
+CommunicatorErrorCode communicator_platform_set_my_avatar(
+    CommunicatorPlatform platform,
+    const uint8_t* data,
+    size_t size
+);
+
 /**
  * Create a direct message channel with another user
  *
@@ -491,6 +640,20 @@ CommunicatorErrorCode communicator_platform_delete_channel(
     const char* channel_id
 );
 
+/**
+ * Restore (unarchive) a channel that was deleted
+ * Returns a JSON string representing the restored Channel
+ * The caller must free the returned string using communicator_free_string()
+ *
+ * @param platform The platform handle
+ * @param channel_id The ID of the archived channel
+ * @return JSON string, or NULL on error
+ */
+char* communicator_platform_restore_channel(
+    CommunicatorPlatform platform,
+    const char* channel_id
+);
+
 /**
  * Request statuses for all users via WebSocket (async operation)
  *
@@ -519,14 +682,66 @@ int64_t communicator_platform_request_users_statuses(
     const char* user_ids_json
 );
 
+/**
+ * Get the health of the real-time connection
+ *
+ * Reports the last ping round-trip time, when the last pong and event arrived
+ * and how many pings are unanswered, to spot half-open connections.
+ * Requires an active WebSocket connection (call subscribe_events first).
+ *
+ * @param platform The platform handle
+ * @return JSON ConnectionHealth on success, NULL on error
+ *         The caller must free the returned string using communicator_free_string()
+ */
+char* communicator_platform_get_connection_health(
+    CommunicatorPlatform platform
+);
+
+/**
+ * Ping the server over the real-time connection and wait for the pong
+ *
+ * Requires an active WebSocket connection (call subscribe_events first).
+ * Fails with COMMUNICATOR_ERROR_TIMEOUT when no pong arrives in time.
+ *
+ * @param platform The platform handle
+ * @param timeout_ms How long to wait for the pong in milliseconds; 0 means 10 seconds
+ * @return The round-trip time in milliseconds on success, or -1 on error
+ */
+int64_t communicator_platform_ping(
+    CommunicatorPlatform platform,
+    uint64_t timeout_ms
+);
+
 /**
  * Subscribe to real-time events
  *
+ * Dropped connections are reconnected with exponential backoff. If the server
+ * can't replay the events sent during the outage, the posts created meanwhile
+ * are fetched and delivered as message_posted events before newer events.
+ *
  * @param platform The platform handle
  * @return Error code indicating success or failure
  */
 CommunicatorErrorCode communicator_platform_subscribe_events(CommunicatorPlatform platform);
 
+/**
+ * Subscribe to real-time events within a scope
+ *
+ * Like communicator_platform_subscribe_events, but only events in the listed
+ * channels or teams (including the teams' channels) are returned by
+ * communicator_platform_poll_event. Events outside any channel or team, such
+ * as status changes, are dropped; connection events are always delivered.
+ * An empty filter delivers every event.
+ *
+ * @param platform The platform handle
+ * @param filter_json JSON object: {"channel_ids": [...], "team_ids": [...]}
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_subscribe_events_filtered(
+    CommunicatorPlatform platform,
+    const char* filter_json
+);
+
 /**
  * Unsubscribe from real-time events
  *
@@ -535,12 +750,34 @@ CommunicatorErrorCode communicator_platform_subscribe_events(CommunicatorPlatfor
  */
 CommunicatorErrorCode communicator_platform_unsubscribe_events(CommunicatorPlatform platform);
 
+/**
+ * Enable or disable strict validation of incoming event payloads
+ *
+ * When enabled, event payloads that do not match the schema the library
+ * expects (unknown fields, missing fields, type drift) are reported as
+ * "schema_mismatch" events carrying the offending event type and a list of
+ * issues. Takes effect on the next communicator_platform_subscribe_events call.
+ *
+ * @param platform The platform handle
+ * @param enabled Non-zero to enable, 0 to disable
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_set_strict_event_validation(
+    CommunicatorPlatform platform,
+    int enabled
+);
+
 /**
  * Poll for the next event
  *
  * @param platform The platform handle
  * @return A JSON string representing the PlatformEvent, or NULL if no events are available
  *         Event format: { "type": "event_type", "data": {...} }
+ *         Events received from the server carry their sequence number in "seq";
+ *         a "gap_detected" event reports lost events; a "rate_limited" event
+ *         reports a request rejected with HTTP 429 ("next_retry_ms" is null
+ *         once retrying gave up); event types the library doesn't model
+ *         arrive as "raw" with "raw_type" and "raw_data"
  *         Must be freed with communicator_free_string()
  *         Returns NULL if no events or on error
  */
@@ -568,6 +805,30 @@ char* communicator_platform_send_reply(
     const char* root_id
 );
 
+/**
+ * Send a message with options such as a thread or an identity override
+ *
+ * The username, icon_url and icon_emoji overrides let one bot account post
+ * as several personas. They are only shown for accounts allowed to override
+ * their identity, typically bots, and when the server enables overrides.
+ * file_ids attaches files uploaded with communicator_platform_upload_file().
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID
+ * @param text The message text
+ * @param options_json JSON object, e.g. {"root_id": "...", "username": "alerts", "icon_emoji": "rotating_light", "file_ids": ["..."]}
+ * @return A JSON string representing the created Message
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_send_message_with_options(
+    CommunicatorPlatform platform,
+    const char* channel_id,
+    const char* text,
+    const char* options_json
+);
+
+
 /**
  * Update/edit a message
  *
@@ -626,6 +887,44 @@ char* communicator_platform_search_messages(
     uint32_t limit
 );
 
+/**
+ * Get one page of message search results
+ *
+ * Pages are zero-based. A page with fewer than per_page messages is the last one.
+ *
+ * @param platform The platform handle
+ * @param query The search query
+ * @param page Zero-based page number
+ * @param per_page Number of messages per page
+ * @return A JSON array string of Message objects
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_search_messages_page(
+    CommunicatorPlatform platform,
+    const char* query,
+    uint32_t page,
+    uint32_t per_page
+);
+
+/**
+ * Get recent messages that mention the current user, newest first
+ *
+ * Searches for the user's @-username, custom mention keys and, if enabled,
+ * first name, like the "Recent mentions" view of the web app.
+ *
+ * @param platform The platform handle
+ * @param limit Maximum number of messages to retrieve
+ * @return A JSON array string of Message objects
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_recent_mentions(
+    CommunicatorPlatform platform,
+    uint32_t limit
+);
+
+
 // ============================================================================
 // Advanced Search Operations
 // ============================================================================
@@ -787,6 +1086,25 @@ char* communicator_platform_get_messages_after(
     uint32_t limit
 );
 
+/**
+ * Get messages created, edited or deleted since a timestamp
+ *
+ * Meant for catching a channel up after a reconnect. Deleted messages are
+ * included; their metadata carries a non-zero "delete_at".
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID
+ * @param since Unix timestamp in milliseconds
+ * @return A JSON array string of Message objects, oldest first
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_messages_since(
+    CommunicatorPlatform platform,
+    const char* channel_id,
+    uint64_t since
+);
+
 // ============================================================================
 // Reaction Operations
 // ============================================================================
@@ -858,733 +1176,1917 @@ char* communicator_platform_get_pinned_posts(
 );
 
 /**
- * Get a list of custom emojis
+ * Get a permanent link to a message
  *
  * @param platform The platform handle
- * @param page The page number to retrieve (0-indexed)
- * @param per_page Number of emojis per page
- * @return A JSON string representing a Vec<Emoji>
+ * @param message_id The ID of the message to link to
+ * @return The permalink URL
  *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_get_emojis(
+char* communicator_platform_get_permalink(
     CommunicatorPlatform platform,
-    uint32_t page,
-    uint32_t per_page
+    const char* message_id
 );
 
-// ============================================================================
-// Extended Channel Operations
-// ============================================================================
-
 /**
- * Get a channel by name
+ * Resolve a permalink back to the message it points to
  *
  * @param platform The platform handle
- * @param team_id The team ID
- * @param channel_name The channel name
- * @return A JSON string representing the Channel
+ * @param permalink The permalink URL
+ * @return A JSON object of the form {"message": Message, "channel": Channel}
  *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_get_channel_by_name(
+char* communicator_platform_resolve_permalink(
     CommunicatorPlatform platform,
-    const char* team_id,
-    const char* channel_name
+    const char* permalink
 );
 
 /**
- * Create a group direct message channel
+ * Get the preview metadata (OpenGraph) of a web page
+ *
+ * The server fetches the page, so previews match the platform's own clients.
  *
  * @param platform The platform handle
- * @param user_ids_json JSON array of user IDs, e.g. ["user1", "user2", "user3"]
- * @return A JSON string representing the created Channel
+ * @param url An absolute http or https URL
+ * @return A JSON LinkMetadata object
  *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_create_group_channel(
+char* communicator_platform_get_link_metadata(
     CommunicatorPlatform platform,
-    const char* user_ids_json
+    const char* url
 );
 
 /**
- * Add a user to a channel
+ * Get a list of custom emojis
  *
  * @param platform The platform handle
- * @param channel_id The channel ID
- * @param user_id The user ID to add
- * @return Error code indicating success or failure
+ * @param page The page number to retrieve (0-indexed)
+ * @param per_page Number of emojis per page
+ * @return A JSON string representing a Vec<Emoji>
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
  */
-CommunicatorErrorCode communicator_platform_add_channel_member(
+char* communicator_platform_get_emojis(
     CommunicatorPlatform platform,
-    const char* channel_id,
-    const char* user_id
+    uint32_t page,
+    uint32_t per_page
 );
 
 /**
- * Remove a user from a channel
+ * Get a custom emoji by name
  *
  * @param platform The platform handle
- * @param channel_id The channel ID
- * @param user_id The user ID to remove
- * @return Error code indicating success or failure
+ * @param name The emoji name, with or without colons
+ * @return A JSON string representing the Emoji
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error, with COMMUNICATOR_ERROR_NOT_FOUND if no custom emoji has that name
  */
-CommunicatorErrorCode communicator_platform_remove_channel_member(
+char* communicator_platform_get_emoji_by_name(
     CommunicatorPlatform platform,
-    const char* channel_id,
-    const char* user_id
+    const char* name
 );
 
+
 /**
- * Create a new regular channel (public or private)
+ * Suggest custom emojis whose names start with a prefix, for :emoji: completion
+ *
+ * Standard Unicode emojis are not included.
  *
  * @param platform The platform handle
- * @param team_id The team/workspace ID
- * @param name The channel URL name (lowercase, no spaces)
- * @param display_name The human-readable channel name
- * @param is_private 0 for public channel, non-zero for private
- * @return A JSON string representing the created Channel
+ * @param prefix The typed start of the name; a leading colon is ignored
+ * @return A JSON array string of Emoji objects
  *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_create_channel(
+char* communicator_platform_autocomplete_emoji(
+    CommunicatorPlatform platform,
+    const char* prefix
+);
+
+
+/**
+ * Create a custom emoji from an image file
+ *
+ * @param platform The platform handle
+ * @param name The emoji name, without colons
+ * @param image_path Path to the image file (gif, png or jpeg)
+ * @return JSON string representing the created Emoji, or NULL on error
+ *         Caller must free the returned string with communicator_free_string()
+ */
+char* communicator_platform_create_emoji(
     CommunicatorPlatform platform,
-    const char* team_id,
     const char* name,
-    const char* display_name,
-    int is_private
+    const char* image_path
 );
 
 /**
- * Update channel information (partial update)
+ * Delete a custom emoji
  *
  * @param platform The platform handle
- * @param channel_id The channel ID
- * @param display_name The new display name (pass NULL to skip)
- * @param purpose The new purpose/description (pass NULL to skip)
- * @param header The new header text (pass NULL to skip)
- * @return A JSON string representing the updated Channel
- *         Must be freed with communicator_free_string()
- *         Returns NULL on error
+ * @param emoji_id The ID of the emoji
+ * @return Error code indicating success or failure
  */
-char* communicator_platform_update_channel(
+CommunicatorErrorCode communicator_platform_delete_emoji(
     CommunicatorPlatform platform,
-    const char* channel_id,
-    const char* display_name,
-    const char* purpose,
-    const char* header
+    const char* emoji_id
 );
 
 /**
- * Delete (archive) a channel
+ * Download the image of a custom emoji
  *
  * @param platform The platform handle
- * @param channel_id The channel ID to delete
+ * @param emoji_id The ID of the emoji
+ * @param out_data Output parameter for the image data (caller must free with communicator_free_file_data())
+ * @param out_size Output parameter for the size of the image data in bytes
  * @return Error code indicating success or failure
  */
-CommunicatorErrorCode communicator_platform_delete_channel(
+CommunicatorErrorCode communicator_platform_get_emoji_image(
     CommunicatorPlatform platform,
-    const char* channel_id
+    const char* emoji_id,
+    uint8_t** out_data,
+    size_t* out_size
 );
 
 // ============================================================================
-// Extended User Operations
+// Extended Channel Operations
 // ============================================================================
 
 /**
- * Get a user by username
+ * Get a channel by name
  *
  * @param platform The platform handle
- * @param username The username
- * @return A JSON string representing the User
+ * @param team_id The team ID
+ * @param channel_name The channel name
+ * @return A JSON string representing the Channel
  *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_get_user_by_username(
+char* communicator_platform_get_channel_by_name(
     CommunicatorPlatform platform,
-    const char* username
+    const char* team_id,
+    const char* channel_name
+);
+
+/**
+ * Get a page of a team's public channels, including ones the user has not joined
+ *
+ * @param platform The platform handle
+ * @param team_id The team ID
+ * @param page The page number to retrieve (0-indexed)
+ * @param per_page Number of channels per page
+ * @return A JSON array of Channels
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_public_channels(
+    CommunicatorPlatform platform,
+    const char* team_id,
+    uint32_t page,
+    uint32_t per_page
+);
+
+/**
+ * Get a page of a team's archived (deleted) channels
+ *
+ * @param platform The platform handle
+ * @param team_id The team ID
+ * @param page The page number to retrieve (0-indexed)
+ * @param per_page Number of channels per page
+ * @return A JSON array of Channels
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_archived_channels(
+    CommunicatorPlatform platform,
+    const char* team_id,
+    uint32_t page,
+    uint32_t per_page
+);
+
+/**
+ * Create a group direct message channel
+ *
+ * @param platform The platform handle
+ * @param user_ids_json JSON array of user IDs, e.g. ["user1", "user2", "user3"]
+ * @return A JSON string representing the created Channel
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_create_group_channel(
+    CommunicatorPlatform platform,
+    const char* user_ids_json
+);
+
+/**
+ * Find an existing direct message channel with a user without creating one
+ *
+ * @param platform The platform handle
+ * @param user_id The other participant's user ID
+ * @return A JSON string representing the Channel, or "null" if no DM exists yet
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_find_direct_channel(
+    CommunicatorPlatform platform,
+    const char* user_id
+);
+
+/**
+ * Find an existing group message channel for a set of users without creating one
+ *
+ * @param platform The platform handle
+ * @param user_ids_json JSON array of the other participants' user IDs, e.g. ["user1", "user2"]
+ * @return A JSON string representing the Channel, or "null" if no such group exists yet
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_find_group_channel(
+    CommunicatorPlatform platform,
+    const char* user_ids_json
+);
+
+/**
+ * Change who takes part in a group message
+ *
+ * The current user always stays a participant. Mattermost fixes a group
+ * message's participants, so there the result is the group message for the
+ * new set, created if needed, or the direct message when one other user is
+ * left; the original conversation is left as it is.
+ *
+ * @param platform The platform handle
+ * @param channel_id The group message channel ID
+ * @param add_user_ids_json JSON array of user IDs to add, e.g. ["user3"]
+ * @param remove_user_ids_json JSON array of user IDs to remove, e.g. []
+ * @return A JSON string representing the Channel with the new participants
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_update_group_participants(
+    CommunicatorPlatform platform,
+    const char* channel_id,
+    const char* add_user_ids_json,
+    const char* remove_user_ids_json
+);
+
+/**
+ * Get a user's effective permissions in a channel
+ *
+ * Combines system, team and channel roles. Results are cached and
+ * invalidated automatically when role events arrive.
+ *
+ * @param platform The platform handle
+ * @param user_id The user ID
+ * @param channel_id The channel ID
+ * @return A JSON array of permission names
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_effective_permissions(
+    CommunicatorPlatform platform,
+    const char* user_id,
+    const char* channel_id
+);
+
+/**
+ * Check whether a user holds a permission in a channel
+ *
+ * @param platform The platform handle
+ * @param user_id The user ID
+ * @param channel_id The channel ID
+ * @param permission The permission name, e.g. "manage_public_channel_members"
+ * @return 1 if the permission is held, 0 if not, -1 on error
+ */
+int communicator_platform_has_permission(
+    CommunicatorPlatform platform,
+    const char* user_id,
+    const char* channel_id,
+    const char* permission
+);
+
+/**
+ * Add a user to a channel
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID
+ * @param user_id The user ID to add
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_add_channel_member(
+    CommunicatorPlatform platform,
+    const char* channel_id,
+    const char* user_id
+);
+
+/**
+ * Remove a user from a channel
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID
+ * @param user_id The user ID to remove
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_remove_channel_member(
+    CommunicatorPlatform platform,
+    const char* channel_id,
+    const char* user_id
+);
+
+/**
+ * Join a channel as the current user
+ * Returns a JSON string representing the joined Channel
+ * The caller must free the returned string using communicator_free_string()
+ *
+ * @param platform The platform handle
+ * @param channel_id The ID of the channel to join
+ * @return JSON string, or NULL on error
+ */
+char* communicator_platform_join_channel(
+    CommunicatorPlatform platform,
+    const char* channel_id
+);
+
+/**
+ * Join a channel by name within a team as the current user
+ * Returns a JSON string representing the joined Channel
+ * The caller must free the returned string using communicator_free_string()
+ *
+ * @param platform The platform handle
+ * @param team_id The team ID
+ * @param channel_name The channel name (not display name)
+ * @return JSON string, or NULL on error
+ */
+char* communicator_platform_join_channel_by_name(
+    CommunicatorPlatform platform,
+    const char* team_id,
+    const char* channel_name
+);
+
+/**
+ * Leave a channel as the current user
+ *
+ * @param platform The platform handle
+ * @param channel_id The ID of the channel to leave
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_leave_channel(
+    CommunicatorPlatform platform,
+    const char* channel_id
+);
+
+/**
+ * Replace a channel member's explicit roles
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID
+ * @param user_id The member's user ID
+ * @param roles_json JSON array of role names, e.g. ["channel_user", "channel_admin"]
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_update_channel_member_roles(
+    CommunicatorPlatform platform,
+    const char* channel_id,
+    const char* user_id,
+    const char* roles_json
+);
+
+/**
+ * Set whether a channel member is a channel admin (moderator) and/or a regular user
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID
+ * @param user_id The member's user ID
+ * @param scheme_admin Non-zero to grant the channel admin role
+ * @param scheme_user Non-zero to grant the channel user role
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_set_channel_member_scheme_roles(
+    CommunicatorPlatform platform,
+    const char* channel_id,
+    const char* user_id,
+    int scheme_admin,
+    int scheme_user
+);
+
+/**
+ * Synchronize a channel's membership with a desired list of users
+ *
+ * Adds missing users and removes users not in the desired list.
+ * Individual failures are reported in the result instead of aborting the sync.
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID
+ * @param desired_user_ids_json JSON array of user IDs that should be members
+ * @param options_json JSON object, e.g. {"dry_run": true, "add_only": false, "protected_user_ids": []}
+ * @return A JSON string representing the MemberSyncResult
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_sync_channel_members(
+    CommunicatorPlatform platform,
+    const char* channel_id,
+    const char* desired_user_ids_json,
+    const char* options_json
+);
+
+/**
+ * Create a new regular channel (public or private)
+ *
+ * @param platform The platform handle
+ * @param team_id The team/workspace ID
+ * @param name The channel URL name (lowercase, no spaces)
+ * @param display_name The human-readable channel name
+ * @param is_private 0 for public channel, non-zero for private
+ * @return A JSON string representing the created Channel
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_create_channel(
+    CommunicatorPlatform platform,
+    const char* team_id,
+    const char* name,
+    const char* display_name,
+    int is_private
+);
+
+/**
+ * Update channel information (partial update)
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID
+ * @param display_name The new display name (pass NULL to skip)
+ * @param purpose The new purpose/description (pass NULL to skip)
+ * @param header The new header text (pass NULL to skip)
+ * @return A JSON string representing the updated Channel
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_update_channel(
+    CommunicatorPlatform platform,
+    const char* channel_id,
+    const char* display_name,
+    const char* purpose,
+    const char* header
+);
+
+/**
+ * Delete (archive) a channel
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID to delete
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_delete_channel(
+    CommunicatorPlatform platform,
+    const char* channel_id
+);
+
+// ============================================================================
+// Extended User Operations
+// ============================================================================
+
+/**
+ * Get a user by username
+ *
+ * @param platform The platform handle
+ * @param username The username
+ * @return A JSON string representing the User
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_user_by_username(
+    CommunicatorPlatform platform,
+    const char* username
 );
 
 /**
  * Get a user by email
  *
  * @param platform The platform handle
- * @param email The email address
- * @return A JSON string representing the User
+ * @param email The email address
+ * @return A JSON string representing the User
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_user_by_email(
+    CommunicatorPlatform platform,
+    const char* email
+);
+
+/**
+ * Get multiple users by their IDs (batch operation)
+ *
+ * @param platform The platform handle
+ * @param user_ids_json JSON array of user IDs, e.g. ["user1", "user2", "user3"]
+ * @return A JSON array string of User objects
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_users_by_ids(
+    CommunicatorPlatform platform,
+    const char* user_ids_json
+);
+
+// ============================================================================
+// Team Management
+// ============================================================================
+
+/**
+ * Get all teams the user belongs to
+ *
+ * @param platform The platform handle
+ * @return A JSON array string of Team objects
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_teams(CommunicatorPlatform platform);
+
+/**
+ * Get a specific team by ID
+ *
+ * @param platform The platform handle
+ * @param team_id The team ID
+ * @return A JSON string representing the Team
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_team(
+    CommunicatorPlatform platform,
+    const char* team_id
+);
+
+/**
+ * Create a new team
+ *
+ * @param platform The platform handle
+ * @param team_json JSON object: {"name", "display_name", "team_type": "Open"|"Invite", "description", "allowed_domains", "allow_open_invite"}
+ * @return A JSON string representing the created Team
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_create_team(
+    CommunicatorPlatform platform,
+    const char* team_json
+);
+
+/**
+ * Update a team's settings (partial update)
+ *
+ * @param platform The platform handle
+ * @param team_id The ID of the team to update
+ * @param patch_json JSON object with any of: "display_name", "description", "team_type", "allowed_domains", "allow_open_invite"
+ * @return A JSON string representing the updated Team
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_update_team(
+    CommunicatorPlatform platform,
+    const char* team_id,
+    const char* patch_json
+);
+
+/**
+ * Set a team's icon
+ *
+ * @param platform The platform handle
+ * @param team_id The ID of the team
+ * @param data The image data (png, jpeg, gif or bmp); copied before returning
+ * @param size Size of the image data in bytes
+ * @return COMMUNICATOR_SUCCESS on success, error code otherwise
+ */
+CommunicatorErrorCode communicator_platform_set_team_icon(
+    CommunicatorPlatform platform,
+    const char* team_id,
+    const uint8_t* data,
+    size_t size
+);
+
+/**
+ * Get a team by name
+ *
+ * @param platform The platform handle
+ * @param team_name The team name
+ * @return A JSON string representing the Team
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_team_by_name(
+    CommunicatorPlatform platform,
+    const char* team_name
+);
+
+/**
+ * Get member counts for a team
+ *
+ * @param platform The platform handle
+ * @param team_id The team ID
+ * @return A JSON object: {"team_id": "...", "member_count": 12, "active_member_count": 10}
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_team_stats(
+    CommunicatorPlatform platform,
+    const char* team_id
+);
+
+
+/**
+ * Search the teams visible to the current user by name or display name
+ *
+ * Unlike communicator_platform_get_teams(), this also finds teams the user
+ * hasn't joined.
+ *
+ * @param platform The platform handle
+ * @param term Text to match against the team name or display name
+ * @return A JSON array string of Team objects
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_search_teams(
+    CommunicatorPlatform platform,
+    const char* term
+);
+
+
+/**
+ * Set the active team/workspace ID
+ *
+ * @param platform The platform handle
+ * @param team_id The team ID to set as active (pass NULL to unset)
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_set_team_id(
+    CommunicatorPlatform platform,
+    const char* team_id
+);
+
+// ============================================================================
+// User Status Management
+// ============================================================================
+
+/**
+ * Set the current user's status
+ *
+ * @param platform The platform handle
+ * @param status Status string: "online", "away", "dnd", or "offline"
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_set_status(
+    CommunicatorPlatform platform,
+    const char* status
+);
+
+/**
+ * Get a user's status
+ *
+ * @param platform The platform handle
+ * @param user_id The user ID
+ * @return A JSON string representing the status: {"status": "online"}
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_user_status(
+    CommunicatorPlatform platform,
+    const char* user_id
+);
+
+/**
+ * Get status for multiple users (batch operation)
+ *
+ * @param platform The platform handle
+ * @param user_ids_json JSON array of user IDs, e.g. ["user1", "user2", "user3"]
+ * @return A JSON object mapping user IDs to status strings: {"user1": "online", "user2": "away", ...}
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_users_status(
+    CommunicatorPlatform platform,
+    const char* user_ids_json
+);
+
+/**
+ * Get detailed status for multiple users (batch operation)
+ *
+ * @param platform The platform handle
+ * @param user_ids_json JSON array of user IDs, e.g. ["user1", "user2"]
+ * @return A JSON array of status objects:
+ *         [{"user_id": "user1", "status": "donotdisturb", "manual": true,
+ *           "dnd_end_time": "...", "last_activity_at": "..."}, ...]
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_statuses_by_ids(
+    CommunicatorPlatform platform,
+    const char* user_ids_json
+);
+
+
+// ============================================================================
+// Custom Status Management
+// ============================================================================
+
+/**
+ * Set a custom status message
+ *
+ * @param platform The platform handle
+ * @param custom_status_json JSON object with format:
+ *                          {
+ *                            "emoji": "optional-emoji",
+ *                            "text": "status text",
+ *                            "expires_at": 1234567890  // Optional Unix timestamp
+ *                          }
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_set_custom_status(
+    CommunicatorPlatform platform,
+    const char* custom_status_json
+);
+
+/**
+ * Remove/clear the current user's custom status
+ *
+ * @param platform The platform handle
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_remove_custom_status(CommunicatorPlatform platform);
+
+// ============================================================================
+// Typing Indicators
+// ============================================================================
+
+/**
+ * Send a typing indicator to a channel
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID to send the typing indicator to
+ * @param parent_id The parent message ID for threaded replies (pass NULL for main channel)
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_send_typing_indicator(
+    CommunicatorPlatform platform,
+    const char* channel_id,
+    const char* parent_id
+);
+
+// ============================================================================
+// File Operations
+// ============================================================================
+
+/**
+ * Upload a file to a channel
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID where the file will be uploaded
+ * @param file_path Path to the file to upload
+ * @return A dynamically allocated string containing the file ID (caller must free with communicator_free_string())
+ *         Returns NULL on error
+ */
+char* communicator_platform_upload_file(
+    CommunicatorPlatform platform,
+    const char* channel_id,
+    const char* file_path
+);
+
+/**
+ * Download a file by its ID
+ *
+ * @param platform The platform handle
+ * @param file_id The ID of the file to download
+ * @param out_data Output parameter for the file data (caller must free with communicator_free_file_data())
+ * @param out_size Output parameter for the size of the file data in bytes
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_download_file(
+    CommunicatorPlatform platform,
+    const char* file_id,
+    uint8_t** out_data,
+    size_t* out_size
+);
+
+/**
+ * Download part of a file by its ID
+ *
+ * Lets interrupted downloads resume and media players seek without
+ * downloading the whole file.
+ *
+ * @param platform The platform handle
+ * @param file_id The ID of the file to download
+ * @param offset Position of the first byte to download
+ * @param length Maximum number of bytes to download; 0 reads to the end
+ * @param out_data Output parameter for the data (caller must free with communicator_free_file_data())
+ * @param out_size Output parameter for the size of the data in bytes; less than length if the file ends first
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_download_file_range(
+    CommunicatorPlatform platform,
+    const char* file_id,
+    uint64_t offset,
+    uint64_t length,
+    uint8_t** out_data,
+    size_t* out_size
+);
+
+/**
+ * Get file metadata without downloading the file
+ *
+ * @param platform The platform handle
+ * @param file_id The ID of the file
+ * @return A dynamically allocated JSON string representing the Attachment metadata
+ *         (caller must free with communicator_free_string())
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_file_metadata(
+    CommunicatorPlatform platform,
+    const char* file_id
+);
+
+/**
+ * Get file thumbnail
+ *
+ * @param platform The platform handle
+ * @param file_id The ID of the file
+ * @param out_data Output parameter for the thumbnail data (caller must free with communicator_free_file_data())
+ * @param out_size Output parameter for the size of the thumbnail data in bytes
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_get_file_thumbnail(
+    CommunicatorPlatform platform,
+    const char* file_id,
+    uint8_t** out_data,
+    size_t* out_size
+);
+
+/**
+ * Free file data allocated by download_file or get_file_thumbnail
+ *
+ * @param data Pointer to file data
+ * @param size Size of the data in bytes
+ */
+void communicator_free_file_data(uint8_t* data, size_t size);
+
+/**
+ * Get file preview (full-size image preview)
+ *
+ * Downloads a full-size preview of a file, typically for images.
+ *
+ * @param platform The platform handle
+ * @param file_id The ID of the file
+ * @param out_data Output parameter for the preview data (caller must free with communicator_free_file_data())
+ * @param out_size Output parameter for the size of the preview data in bytes
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_get_file_preview(
+    CommunicatorPlatform platform,
+    const char* file_id,
+    uint8_t** out_data,
+    size_t* out_size
+);
+
+/**
+ * Get a public link to a file
+ *
+ * Generates a public URL for accessing a file.
+ *
+ * @param platform The platform handle
+ * @param file_id The ID of the file
+ * @return A dynamically allocated string containing the file URL
+ *         (caller must free with communicator_free_string())
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_file_link(
+    CommunicatorPlatform platform,
+    const char* file_id
+);
+
+/**
+ * Check whether the server allows public file links
+ *
+ * Administrators can disable public links at any time; check before sharing
+ * a link outside the platform.
+ *
+ * @param platform The platform handle
+ * @return 1 if public links are enabled, 0 if not, -1 on error
+ */
+int communicator_platform_public_links_enabled(CommunicatorPlatform platform);
+
+/**
+ * Enable public file links server-wide
+ *
+ * Requires system administrator permissions.
+ *
+ * @param platform The platform handle
+ * @return COMMUNICATOR_SUCCESS or an error code
+ */
+CommunicatorErrorCode communicator_platform_enable_public_links(CommunicatorPlatform platform);
+
+/**
+ * Disable public file links and invalidate every link issued so far
+ *
+ * The server's link salt is regenerated, so existing links stay invalid even
+ * if public links are enabled again. Requires system administrator permissions.
+ *
+ * @param platform The platform handle
+ * @return COMMUNICATOR_SUCCESS or an error code
+ */
+CommunicatorErrorCode communicator_platform_revoke_public_links(CommunicatorPlatform platform);
+
+/**
+ * Invalidate the public link of a single file
+ *
+ * Other files' links keep working. Mattermost signs every link with one
+ * server-wide salt and returns COMMUNICATOR_ERROR_UNSUPPORTED; use
+ * communicator_platform_revoke_public_links() there.
+ *
+ * @param platform The platform handle
+ * @param file_id The ID of the file
+ * @return COMMUNICATOR_SUCCESS or an error code
+ */
+CommunicatorErrorCode communicator_platform_revoke_file_link(
+    CommunicatorPlatform platform,
+    const char* file_id
+);
+
+// ============================================================================
+// Thread Operations
+// ============================================================================
+
+/**
+ * Get a thread (root post and all replies)
+ *
+ * Fetches a complete thread including the root post and all replies.
+ *
+ * @param platform The platform handle
+ * @param post_id The ID of any post in the thread (typically the root post)
+ * @return JSON string containing an array of messages (caller must free with communicator_free_string())
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_thread(
+    CommunicatorPlatform platform,
+    const char* post_id
+);
+
+/**
+ * Start following a thread
+ *
+ * Makes the authenticated user follow a thread to receive notifications for new replies.
+ *
+ * @param platform The platform handle
+ * @param thread_id The thread ID (typically the root post ID)
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_follow_thread(
+    CommunicatorPlatform platform,
+    const char* thread_id
+);
+
+/**
+ * Stop following a thread
+ *
+ * Makes the authenticated user unfollow a thread to stop receiving notifications.
+ *
+ * @param platform The platform handle
+ * @param thread_id The thread ID (typically the root post ID)
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_unfollow_thread(
+    CommunicatorPlatform platform,
+    const char* thread_id
+);
+
+/**
+ * Mark a thread as read
+ *
+ * Marks all messages in a thread as read up to the current time.
+ *
+ * @param platform The platform handle
+ * @param thread_id The thread ID (typically the root post ID)
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_mark_thread_read(
+    CommunicatorPlatform platform,
+    const char* thread_id
+);
+
+/**
+ * Mark a thread as unread from a specific post
+ *
+ * Marks a thread as unread, typically from a specific post onwards.
+ *
+ * @param platform The platform handle
+ * @param thread_id The thread ID (typically the root post ID)
+ * @param post_id The post ID to mark as unread from
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_mark_thread_unread(
+    CommunicatorPlatform platform,
+    const char* thread_id,
+    const char* post_id
+);
+
+/**
+ * Get all threads for a user in a team
+ *
+ * Retrieves a list of all threads the user participates in or follows.
+ *
+ * @param platform The platform handle
+ * @param user_id The user ID
+ * @param team_id The team ID
+ * @param since Timestamp to get threads since (Unix milliseconds, 0 for all)
+ * @param deleted Whether to include deleted threads (0 = no, 1 = yes)
+ * @param unread Whether to filter for unread only (0 = all, 1 = unread only)
+ * @param per_page Number of threads per page
+ * @param page Page number (0-indexed)
+ * @return A JSON string representing the ThreadList (total, total_unread_threads,
+ *         total_unread_mentions and threads with unread_replies/unread_mentions)
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_user_threads(
+    CommunicatorPlatform platform,
+    const char* user_id,
+    const char* team_id,
+    uint64_t since,
+    int deleted,
+    int unread,
+    uint32_t per_page,
+    uint32_t page
+);
+
+/**
+ * Get a specific thread for a user
+ *
+ * Retrieves detailed information about a specific thread for a user.
+ *
+ * @param platform The platform handle
+ * @param user_id The user ID
+ * @param team_id The team ID
+ * @param thread_id The thread ID (root post ID)
+ * @return A JSON string representing the Thread
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_user_thread(
+    CommunicatorPlatform platform,
+    const char* user_id,
+    const char* team_id,
+    const char* thread_id
+);
+
+/**
+ * Mark all threads as read for a user in a team
+ *
+ * Bulk operation to mark all threads as read.
+ *
+ * @param platform The platform handle
+ * @param user_id The user ID
+ * @param team_id The team ID
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_mark_all_threads_read(
+    CommunicatorPlatform platform,
+    const char* user_id,
+    const char* team_id
+);
+
+// ============================================================================
+// User Preferences and Notifications
+// ============================================================================
+
+/**
+ * Get user preferences as JSON
+ *
+ * Returns a JSON string representing the user's preferences.
+ * The caller must free the returned string using communicator_free_string().
+ *
+ * @param platform The platform handle
+ * @param user_id The user ID
+ * @return JSON string (caller must free) or NULL on error
+ */
+char* communicator_platform_get_user_preferences(
+    CommunicatorPlatform platform,
+    const char* user_id
+);
+
+/**
+ * Set user preferences from JSON
+ *
+ * @param platform The platform handle
+ * @param user_id The user ID
+ * @param preferences_json JSON string containing preferences to set
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_set_user_preferences(
+    CommunicatorPlatform platform,
+    const char* user_id,
+    const char* preferences_json
+);
+
+/**
+ * Mute a channel
+ *
+ * Mutes a channel for the current user, disabling notifications.
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID to mute
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_mute_channel(
+    CommunicatorPlatform platform,
+    const char* channel_id
+);
+
+/**
+ * Unmute a channel
+ *
+ * Unmutes a channel for the current user, restoring default notification settings.
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID to unmute
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_unmute_channel(
+    CommunicatorPlatform platform,
+    const char* channel_id
+);
+
+/**
+ * Update channel notification properties
+ *
+ * Updates channel notification properties from a JSON string.
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID
+ * @param notify_props_json JSON string containing notification properties
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_update_channel_notify_props(
+    CommunicatorPlatform platform,
+    const char* channel_id,
+    const char* notify_props_json
+);
+
+/**
+ * Get the current user's account-wide notification preferences
+ *
+ * @param platform The platform handle
+ * @return A JSON string representing the UserNotifyProps
  *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_get_user_by_email(
-    CommunicatorPlatform platform,
-    const char* email
+char* communicator_platform_get_user_notify_props(
+    CommunicatorPlatform platform
 );
 
 /**
- * Get multiple users by their IDs (batch operation)
+ * Update the current user's account-wide notification preferences
+ *
+ * Fields omitted from the JSON are left unchanged.
  *
  * @param platform The platform handle
- * @param user_ids_json JSON array of user IDs, e.g. ["user1", "user2", "user3"]
- * @return A JSON array string of User objects
+ * @param props_json JSON object with the preferences to change
+ * @return A JSON string representing the updated UserNotifyProps
  *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_get_users_by_ids(
+char* communicator_platform_update_user_notify_props(
     CommunicatorPlatform platform,
-    const char* user_ids_json
+    const char* props_json
 );
 
 // ============================================================================
-// Team Management
+// Channel Read State
 // ============================================================================
 
 /**
- * Get all teams the user belongs to
+ * Mark a channel as viewed (read)
+ *
+ * Marks all messages in the channel as read up to the current time.
  *
  * @param platform The platform handle
- * @return A JSON array string of Team objects
+ * @param channel_id The channel ID to mark as viewed
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_view_channel(
+    CommunicatorPlatform platform,
+    const char* channel_id
+);
+
+/**
+ * Get unread information for a channel
+ *
+ * Returns message count, mention count, and last viewed time.
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID
+ * @return A JSON string with unread counts or NULL on error
  *         Must be freed with communicator_free_string()
- *         Returns NULL on error
  */
-char* communicator_platform_get_teams(CommunicatorPlatform platform);
+char* communicator_platform_get_channel_unread(
+    CommunicatorPlatform platform,
+    const char* channel_id
+);
 
 /**
- * Get a specific team by ID
+ * Get unread counts for all channels in a team
+ *
+ * Returns an array of unread information for each channel in the team.
  *
  * @param platform The platform handle
  * @param team_id The team ID
- * @return A JSON string representing the Team
+ * @return A JSON string with array of unread info or NULL on error
  *         Must be freed with communicator_free_string()
- *         Returns NULL on error
  */
-char* communicator_platform_get_team(
+char* communicator_platform_get_team_unreads(
     CommunicatorPlatform platform,
     const char* team_id
 );
 
 /**
- * Get a team by name
+ * Get unread counts for all channels across all teams
+ *
+ * Returns comprehensive unread information for the authenticated user.
  *
  * @param platform The platform handle
- * @param team_name The team name
- * @return A JSON string representing the Team
+ * @return A JSON string with array of TeamUnread objects containing unread info
  *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_get_team_by_name(
-    CommunicatorPlatform platform,
-    const char* team_name
+char* communicator_platform_get_all_unreads(
+    CommunicatorPlatform platform
 );
 
 /**
- * Set the active team/workspace ID
+ * Get unread counts summed over every team, for badge counts
+ *
+ * Mentions include direct and group channels, counted once.
  *
  * @param platform The platform handle
- * @param team_id The team ID to set as active (pass NULL to unset)
- * @return Error code indicating success or failure
+ * @return A JSON UnreadTotals object
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
  */
-CommunicatorErrorCode communicator_platform_set_team_id(
+char* communicator_platform_get_total_unreads(
+    CommunicatorPlatform platform
+);
+
+/**
+ * Get unread posts in a channel
+ *
+ * Retrieves the actual unread messages in a channel.
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID
+ * @param limit_after Maximum number of posts to retrieve after last read (newer posts)
+ * @param limit_before Maximum number of posts to retrieve before last read (context)
+ * @return A JSON string containing post list
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_get_unread_posts(
     CommunicatorPlatform platform,
-    const char* team_id
+    const char* channel_id,
+    uint32_t limit_after,
+    uint32_t limit_before
 );
 
 // ============================================================================
-// User Status Management
+// Sidebar Categories
 // ============================================================================
 
 /**
- * Set the current user's status
+ * Get the current user's sidebar categories for a team
  *
  * @param platform The platform handle
- * @param status Status string: "online", "away", "dnd", or "offline"
- * @return Error code indicating success or failure
+ * @param team_id The team ID
+ * @return A JSON array of SidebarCategory objects in display order
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
  */
-CommunicatorErrorCode communicator_platform_set_status(
+char* communicator_platform_get_sidebar_categories(
     CommunicatorPlatform platform,
-    const char* status
+    const char* team_id
 );
 
 /**
- * Get a user's status
+ * Create a custom sidebar category
  *
  * @param platform The platform handle
- * @param user_id The user ID
- * @return A JSON string representing the status: {"status": "online"}
+ * @param team_id The team ID
+ * @param display_name The name of the new category
+ * @param channel_ids_json JSON array of channel IDs to move into the category
+ * @return A JSON string representing the created SidebarCategory
  *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_get_user_status(
+char* communicator_platform_create_sidebar_category(
     CommunicatorPlatform platform,
-    const char* user_id
+    const char* team_id,
+    const char* display_name,
+    const char* channel_ids_json
 );
 
 /**
- * Get status for multiple users (batch operation)
+ * Update a sidebar category (name, channel order, muted and collapsed state)
  *
  * @param platform The platform handle
- * @param user_ids_json JSON array of user IDs, e.g. ["user1", "user2", "user3"]
- * @return A JSON object mapping user IDs to status strings: {"user1": "online", "user2": "away", ...}
+ * @param category_json JSON SidebarCategory with the desired values
+ * @return A JSON string representing the updated SidebarCategory
  *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_get_users_status(
+char* communicator_platform_update_sidebar_category(
     CommunicatorPlatform platform,
-    const char* user_ids_json
+    const char* category_json
 );
 
-// ============================================================================
-// Custom Status Management
-// ============================================================================
-
 /**
- * Set a custom status message
+ * Delete a custom sidebar category
+ *
+ * Channels in the deleted category return to their default categories.
  *
  * @param platform The platform handle
- * @param custom_status_json JSON object with format:
- *                          {
- *                            "emoji": "optional-emoji",
- *                            "text": "status text",
- *                            "expires_at": 1234567890  // Optional Unix timestamp
- *                          }
+ * @param team_id The team ID
+ * @param category_id The category to delete
  * @return Error code indicating success or failure
  */
-CommunicatorErrorCode communicator_platform_set_custom_status(
+CommunicatorErrorCode communicator_platform_delete_sidebar_category(
     CommunicatorPlatform platform,
-    const char* custom_status_json
+    const char* team_id,
+    const char* category_id
 );
 
 /**
- * Remove/clear the current user's custom status
+ * Reorder the sidebar categories
  *
  * @param platform The platform handle
+ * @param team_id The team ID
+ * @param category_ids_json JSON array of all category IDs in the desired order
  * @return Error code indicating success or failure
  */
-CommunicatorErrorCode communicator_platform_remove_custom_status(CommunicatorPlatform platform);
+CommunicatorErrorCode communicator_platform_reorder_sidebar_categories(
+    CommunicatorPlatform platform,
+    const char* team_id,
+    const char* category_ids_json
+);
 
-// ============================================================================
-// Typing Indicators
-// ============================================================================
+/**
+ * Move a channel into a sidebar category
+ *
+ * @param platform The platform handle
+ * @param team_id The team ID
+ * @param channel_id The channel to move
+ * @param category_id The target category
+ * @return Error code indicating success or failure
+ */
+CommunicatorErrorCode communicator_platform_move_channel_to_category(
+    CommunicatorPlatform platform,
+    const char* team_id,
+    const char* channel_id,
+    const char* category_id
+);
 
 /**
- * Send a typing indicator to a channel
+ * Add a channel to, or remove it from, the favorites category
  *
  * @param platform The platform handle
- * @param channel_id The channel ID to send the typing indicator to
- * @param parent_id The parent message ID for threaded replies (pass NULL for main channel)
+ * @param team_id The team ID
+ * @param channel_id The channel ID
+ * @param favorite Non-zero to favorite, 0 to move the channel back to its default category
  * @return Error code indicating success or failure
  */
-CommunicatorErrorCode communicator_platform_send_typing_indicator(
+CommunicatorErrorCode communicator_platform_set_channel_favorite(
     CommunicatorPlatform platform,
+    const char* team_id,
     const char* channel_id,
-    const char* parent_id
+    int favorite
 );
 
 // ============================================================================
-// File Operations
+// Channel Bookmarks
 // ============================================================================
 
 /**
- * Upload a file to a channel
+ * Get the bookmarks of a channel
  *
  * @param platform The platform handle
- * @param channel_id The channel ID where the file will be uploaded
- * @param file_path Path to the file to upload
- * @return A dynamically allocated string containing the file ID (caller must free with communicator_free_string())
- *         Returns NULL on error
+ * @param channel_id The channel ID
+ * @return JSON array of bookmarks in display order, or NULL on error
+ *         Caller must free the returned string with communicator_free_string()
  */
-char* communicator_platform_upload_file(
+char* communicator_platform_get_channel_bookmarks(
+    CommunicatorPlatform platform,
+    const char* channel_id
+);
+
+/**
+ * Create a link or file bookmark in a channel
+ *
+ * File bookmarks must reference a file uploaded to the channel but not yet
+ * attached to a post.
+ *
+ * @param platform The platform handle
+ * @param channel_id The channel ID
+ * @param bookmark_json JSON object with display_name, type ("link" or "file"), link_url or file_id, and optional emoji and image_url
+ * @return JSON string of the created bookmark, or NULL on error
+ *         Caller must free the returned string with communicator_free_string()
+ */
+char* communicator_platform_create_channel_bookmark(
     CommunicatorPlatform platform,
     const char* channel_id,
-    const char* file_path
+    const char* bookmark_json
 );
 
 /**
- * Download a file by its ID
+ * Delete a bookmark from a channel
  *
  * @param platform The platform handle
- * @param file_id The ID of the file to download
- * @param out_data Output parameter for the file data (caller must free with communicator_free_file_data())
- * @param out_size Output parameter for the size of the file data in bytes
+ * @param channel_id The channel ID
+ * @param bookmark_id The bookmark ID
  * @return Error code indicating success or failure
  */
-CommunicatorErrorCode communicator_platform_download_file(
+CommunicatorErrorCode communicator_platform_delete_channel_bookmark(
     CommunicatorPlatform platform,
-    const char* file_id,
-    uint8_t** out_data,
-    size_t* out_size
+    const char* channel_id,
+    const char* bookmark_id
 );
 
+// ============================================================================
+// Sessions
+// ============================================================================
+
 /**
- * Get file metadata without downloading the file
+ * List the active login sessions of a user
  *
  * @param platform The platform handle
- * @param file_id The ID of the file
- * @return A dynamically allocated JSON string representing the Attachment metadata
- *         (caller must free with communicator_free_string())
+ * @param user_id The ID of the user
+ * @return A JSON array of Session objects
+ *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_get_file_metadata(
+char* communicator_platform_list_sessions(
     CommunicatorPlatform platform,
-    const char* file_id
+    const char* user_id
 );
 
 /**
- * Get file thumbnail
+ * Revoke one of the current user's sessions
  *
  * @param platform The platform handle
- * @param file_id The ID of the file
- * @param out_data Output parameter for the thumbnail data (caller must free with communicator_free_file_data())
- * @param out_size Output parameter for the size of the thumbnail data in bytes
- * @return Error code indicating success or failure
+ * @param session_id The ID of the session to revoke
+ * @return COMMUNICATOR_SUCCESS on success, error code otherwise
  */
-CommunicatorErrorCode communicator_platform_get_file_thumbnail(
+CommunicatorErrorCode communicator_platform_revoke_session(
     CommunicatorPlatform platform,
-    const char* file_id,
-    uint8_t** out_data,
-    size_t* out_size
+    const char* session_id
 );
 
 /**
- * Free file data allocated by download_file or get_file_thumbnail
+ * Revoke every session of the current user, including this one
  *
- * @param data Pointer to file data
- * @param size Size of the data in bytes
+ * The platform is logged out as a result; reconnect before making further calls.
+ *
+ * @param platform The platform handle
+ * @return COMMUNICATOR_SUCCESS on success, error code otherwise
  */
-void communicator_free_file_data(uint8_t* data, size_t size);
+CommunicatorErrorCode communicator_platform_revoke_all_sessions(
+    CommunicatorPlatform platform
+);
+
+// ============================================================================
+// Slash Commands
+// ============================================================================
 
 /**
- * Get file preview (full-size image preview)
- *
- * Downloads a full-size preview of a file, typically for images.
+ * Execute a slash command in a channel as the current user
  *
  * @param platform The platform handle
- * @param file_id The ID of the file
- * @param out_data Output parameter for the preview data (caller must free with communicator_free_file_data())
- * @param out_size Output parameter for the size of the preview data in bytes
- * @return Error code indicating success or failure
+ * @param channel_id The channel to run the command in
+ * @param command The command with its arguments, e.g. "/away" or "/giphy cats"
+ * @return A JSON string representing the CommandResponse
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
  */
-CommunicatorErrorCode communicator_platform_get_file_preview(
-    CommunicatorPlatform platform,
-    const char* file_id,
-    uint8_t** out_data,
-    size_t* out_size
+char* communicator_platform_execute_command(
+    CommunicatorPlatform platform,
+    const char* channel_id,
+    const char* command
 );
 
 /**
- * Get a public link to a file
+ * List the slash commands available in a channel
  *
- * Generates a public URL for accessing a file.
+ * Includes built-in, custom and plugin commands, sorted by trigger, for
+ * composers to suggest while the user types a command.
  *
  * @param platform The platform handle
- * @param file_id The ID of the file
- * @return A dynamically allocated string containing the file URL
- *         (caller must free with communicator_free_string())
+ * @param channel_id The channel the command would run in
+ * @param prefix Only list commands whose trigger starts with this, with or without the slash; "" lists all
+ * @return A JSON array string of SlashCommand objects
+ *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_get_file_link(
+char* communicator_platform_autocomplete_commands(
     CommunicatorPlatform platform,
-    const char* file_id
+    const char* channel_id,
+    const char* prefix
 );
 
 // ============================================================================
-// Thread Operations
+// Incoming Webhooks
 // ============================================================================
 
 /**
- * Get a thread (root post and all replies)
- *
- * Fetches a complete thread including the root post and all replies.
+ * Create an incoming webhook that posts to a channel
  *
  * @param platform The platform handle
- * @param post_id The ID of any post in the thread (typically the root post)
- * @return JSON string containing an array of messages (caller must free with communicator_free_string())
+ * @param hook_json JSON object with channel_id, and optionally display_name, description, username, icon_url, channel_locked
+ * @return A JSON string representing the IncomingWebhook (its url is the secret post URL)
+ *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_get_thread(
+char* communicator_platform_create_incoming_webhook(
     CommunicatorPlatform platform,
-    const char* post_id
+    const char* hook_json
 );
 
 /**
- * Start following a thread
- *
- * Makes the authenticated user follow a thread to receive notifications for new replies.
+ * List a page of incoming webhooks
  *
  * @param platform The platform handle
- * @param thread_id The thread ID (typically the root post ID)
- * @return Error code indicating success or failure
+ * @param team_id Only list this team's webhooks, or NULL for all
+ * @param page The page number to retrieve (0-indexed)
+ * @param per_page Number of webhooks per page
+ * @return A JSON array of IncomingWebhooks
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
  */
-CommunicatorErrorCode communicator_platform_follow_thread(
+char* communicator_platform_list_incoming_webhooks(
     CommunicatorPlatform platform,
-    const char* thread_id
+    const char* team_id,
+    uint32_t page,
+    uint32_t per_page
 );
 
 /**
- * Stop following a thread
- *
- * Makes the authenticated user unfollow a thread to stop receiving notifications.
+ * Delete an incoming webhook
  *
  * @param platform The platform handle
- * @param thread_id The thread ID (typically the root post ID)
+ * @param hook_id The ID of the webhook to delete
  * @return Error code indicating success or failure
  */
-CommunicatorErrorCode communicator_platform_unfollow_thread(
+CommunicatorErrorCode communicator_platform_delete_incoming_webhook(
     CommunicatorPlatform platform,
-    const char* thread_id
+    const char* hook_id
 );
 
 /**
- * Mark a thread as read
+ * Post a message through an incoming webhook URL
+ * Needs no platform handle or login, only communicator_init()
  *
- * Marks all messages in a thread as read up to the current time.
- *
- * @param platform The platform handle
- * @param thread_id The thread ID (typically the root post ID)
+ * @param url The webhook URL
+ * @param payload_json JSON object with text, and optionally channel, username,
+ *                     icon_url, icon_emoji, attachments, props
  * @return Error code indicating success or failure
  */
-CommunicatorErrorCode communicator_platform_mark_thread_read(
-    CommunicatorPlatform platform,
-    const char* thread_id
+CommunicatorErrorCode communicator_send_webhook(
+    const char* url,
+    const char* payload_json
 );
 
+// ============================================================================
+// Bot Accounts
+// ============================================================================
+
 /**
- * Mark a thread as unread from a specific post
- *
- * Marks a thread as unread, typically from a specific post onwards.
+ * Create a bot account owned by the current user
  *
  * @param platform The platform handle
- * @param thread_id The thread ID (typically the root post ID)
- * @param post_id The post ID to mark as unread from
- * @return Error code indicating success or failure
+ * @param bot_json JSON object with username, and optionally display_name, description
+ * @return A JSON string representing the Bot
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
  */
-CommunicatorErrorCode communicator_platform_mark_thread_unread(
+char* communicator_platform_create_bot(
     CommunicatorPlatform platform,
-    const char* thread_id,
-    const char* post_id
+    const char* bot_json
 );
 
 /**
- * Get all threads for a user in a team
- *
- * Retrieves a list of all threads the user participates in or follows.
+ * Partially update a bot account
  *
  * @param platform The platform handle
- * @param user_id The user ID
- * @param team_id The team ID
- * @param since Timestamp to get threads since (Unix milliseconds, 0 for all)
- * @param deleted Whether to include deleted threads (0 = no, 1 = yes)
- * @param unread Whether to filter for unread only (0 = all, 1 = unread only)
- * @param per_page Number of threads per page
- * @param page Page number (0-indexed)
- * @return A JSON string containing thread list
+ * @param bot_user_id The user ID of the bot
+ * @param patch_json JSON object with any of username, display_name, description
+ * @return A JSON string representing the Bot
  *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_get_user_threads(
+char* communicator_platform_patch_bot(
     CommunicatorPlatform platform,
-    const char* user_id,
-    const char* team_id,
-    uint64_t since,
-    int deleted,
-    int unread,
-    uint32_t per_page,
-    uint32_t page
+    const char* bot_user_id,
+    const char* patch_json
 );
 
 /**
- * Get a specific thread for a user
- *
- * Retrieves detailed information about a specific thread for a user.
+ * Disable a bot account
  *
  * @param platform The platform handle
- * @param user_id The user ID
- * @param team_id The team ID
- * @param thread_id The thread ID (root post ID)
- * @return A JSON string containing thread information
+ * @param bot_user_id The user ID of the bot
+ * @return A JSON string representing the Bot
  *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_get_user_thread(
+char* communicator_platform_disable_bot(
     CommunicatorPlatform platform,
-    const char* user_id,
-    const char* team_id,
-    const char* thread_id
+    const char* bot_user_id
 );
 
 /**
- * Mark all threads as read for a user in a team
- *
- * Bulk operation to mark all threads as read.
+ * Transfer ownership of a bot account to another user
  *
  * @param platform The platform handle
- * @param user_id The user ID
- * @param team_id The team ID
- * @return Error code indicating success or failure
+ * @param bot_user_id The user ID of the bot
+ * @param user_id The ID of the new owner
+ * @return A JSON string representing the Bot
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
  */
-CommunicatorErrorCode communicator_platform_mark_all_threads_read(
+char* communicator_platform_assign_bot(
     CommunicatorPlatform platform,
-    const char* user_id,
-    const char* team_id
+    const char* bot_user_id,
+    const char* user_id
 );
 
 // ============================================================================
-// User Preferences and Notifications
+// Interactive Messages
 // ============================================================================
 
 /**
- * Get user preferences as JSON
- *
- * Returns a JSON string representing the user's preferences.
- * The caller must free the returned string using communicator_free_string().
+ * Send a message carrying rich attachments, such as action buttons and menus
+ * Clicking an action posts a callback to the action's integration URL
  *
  * @param platform The platform handle
- * @param user_id The user ID
- * @return JSON string (caller must free) or NULL on error
+ * @param channel_id The ID of the channel
+ * @param text The message text (may be empty)
+ * @param attachments_json JSON array of attachments with fallback, color, pretext, title, title_link, text, fields, actions
+ * @return A JSON string representing the Message
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
  */
-char* communicator_platform_get_user_preferences(
+char* communicator_platform_send_message_with_attachments(
     CommunicatorPlatform platform,
-    const char* user_id
+    const char* channel_id,
+    const char* text,
+    const char* attachments_json
 );
 
 /**
- * Set user preferences from JSON
+ * Open an interactive dialog for the user who triggered an action
+ * Trigger IDs expire a few seconds after they are issued
  *
  * @param platform The platform handle
- * @param user_id The user ID
- * @param preferences_json JSON string containing preferences to set
+ * @param trigger_id The trigger ID from an action callback or slash command
+ * @param url The URL the submission is posted to
+ * @param dialog_json JSON object with title, elements, and optionally callback_id, introduction_text, icon_url, submit_label, notify_on_cancel, state
  * @return Error code indicating success or failure
  */
-CommunicatorErrorCode communicator_platform_set_user_preferences(
+CommunicatorErrorCode communicator_platform_open_interactive_dialog(
     CommunicatorPlatform platform,
-    const char* user_id,
-    const char* preferences_json
+    const char* trigger_id,
+    const char* url,
+    const char* dialog_json
 );
 
+// ============================================================================
+// Message Priority
+// ============================================================================
+
 /**
- * Mute a channel
- *
- * Mutes a channel for the current user, disabling notifications.
+ * Send a message with a priority label and, optionally, an acknowledgement request
+ * Fails with COMMUNICATOR_ERROR_UNSUPPORTED_BY_SERVER on servers without message priority
  *
  * @param platform The platform handle
- * @param channel_id The channel ID to mute
- * @return Error code indicating success or failure
+ * @param channel_id The ID of the channel
+ * @param text The message text
+ * @param priority_json JSON object with priority ("standard", "important" or "urgent"), requested_ack, persistent_notifications
+ * @return A JSON string representing the Message
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
  */
-CommunicatorErrorCode communicator_platform_mute_channel(
+char* communicator_platform_send_message_with_priority(
     CommunicatorPlatform platform,
-    const char* channel_id
+    const char* channel_id,
+    const char* text,
+    const char* priority_json
 );
 
 /**
- * Unmute a channel
- *
- * Unmutes a channel for the current user, restoring default notification settings.
+ * Acknowledge a message as the current user
  *
  * @param platform The platform handle
- * @param channel_id The channel ID to unmute
- * @return Error code indicating success or failure
+ * @param message_id The ID of the message
+ * @return A JSON string representing the MessageAcknowledgement
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
  */
-CommunicatorErrorCode communicator_platform_unmute_channel(
+char* communicator_platform_ack_message(
     CommunicatorPlatform platform,
-    const char* channel_id
+    const char* message_id
 );
 
 /**
- * Update channel notification properties
- *
- * Updates channel notification properties from a JSON string.
+ * Get the acknowledgements of a message, oldest first
  *
  * @param platform The platform handle
- * @param channel_id The channel ID
- * @param notify_props_json JSON string containing notification properties
- * @return Error code indicating success or failure
+ * @param message_id The ID of the message
+ * @return A JSON string representing the array of MessageAcknowledgement objects
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
  */
-CommunicatorErrorCode communicator_platform_update_channel_notify_props(
+char* communicator_platform_get_message_acknowledgements(
     CommunicatorPlatform platform,
-    const char* channel_id,
-    const char* notify_props_json
+    const char* message_id
 );
 
 // ============================================================================
-// Channel Read State
+// User Groups
 // ============================================================================
 
 /**
- * Mark a channel as viewed (read)
+ * Create a custom user group that can be @-mentioned
+ * Fails with COMMUNICATOR_ERROR_UNSUPPORTED_BY_SERVER on servers without custom groups
  *
- * Marks all messages in the channel as read up to the current time.
+ * @param platform The platform handle
+ * @param group_json JSON object with name (the mention name), display_name, optional description and user_ids
+ * @return A JSON string representing the UserGroup
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_create_user_group(
+    CommunicatorPlatform platform,
+    const char* group_json
+);
+
+/**
+ * Add users to a custom user group
  *
  * @param platform The platform handle
- * @param channel_id The channel ID to mark as viewed
- * @return Error code indicating success or failure
+ * @param group_id The ID of the group
+ * @param user_ids_json JSON array of user IDs
+ * @return COMMUNICATOR_SUCCESS or an error code
  */
-CommunicatorErrorCode communicator_platform_view_channel(
+CommunicatorErrorCode communicator_platform_add_user_group_members(
     CommunicatorPlatform platform,
-    const char* channel_id
+    const char* group_id,
+    const char* user_ids_json
 );
 
 /**
- * Get unread information for a channel
+ * Remove users from a custom user group
  *
- * Returns message count, mention count, and last viewed time.
+ * @param platform The platform handle
+ * @param group_id The ID of the group
+ * @param user_ids_json JSON array of user IDs
+ * @return COMMUNICATOR_SUCCESS or an error code
+ */
+CommunicatorErrorCode communicator_platform_remove_user_group_members(
+    CommunicatorPlatform platform,
+    const char* group_id,
+    const char* user_ids_json
+);
+
+/**
+ * Get the user groups that can be @-mentioned in a team
  *
  * @param platform The platform handle
- * @param channel_id The channel ID
- * @return A JSON string with unread counts or NULL on error
+ * @param team_id The ID of the team
+ * @param page Page number (0-indexed)
+ * @param per_page Number of groups per page
+ * @return A JSON array string of UserGroup objects
  *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
  */
-char* communicator_platform_get_channel_unread(
+char* communicator_platform_get_team_user_groups(
     CommunicatorPlatform platform,
-    const char* channel_id
+    const char* team_id,
+    uint32_t page,
+    uint32_t per_page
 );
 
 /**
- * Get unread counts for all channels in a team
- *
- * Returns an array of unread information for each channel in the team.
+ * Suggest user groups for a partially typed @-mention
  *
  * @param platform The platform handle
- * @param team_id The team ID
- * @return A JSON string with array of unread info or NULL on error
+ * @param team_id The team the mention is typed in
+ * @param prefix The text typed after the @
+ * @return A JSON array string of UserGroup objects
  *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
  */
-char* communicator_platform_get_team_unreads(
+char* communicator_platform_autocomplete_user_groups(
     CommunicatorPlatform platform,
-    const char* team_id
+    const char* team_id,
+    const char* prefix
 );
 
+// ============================================================================
+// System Administration
+// ============================================================================
+
 /**
- * Get unread counts for all channels across all teams
- *
- * Returns comprehensive unread information for the authenticated user.
+ * Get the server configuration (administrators only)
+ * Secrets are masked
  *
  * @param platform The platform handle
- * @return A JSON string with array of TeamUnread objects containing unread info
+ * @return A JSON object with the platform-specific configuration
  *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_get_all_unreads(
+char* communicator_platform_admin_get_config(
     CommunicatorPlatform platform
 );
 
 /**
- * Get unread posts in a channel
+ * Change part of the server configuration (administrators only)
+ * Settings left out of the patch keep their values
  *
- * Retrieves the actual unread messages in a channel.
+ * @param platform The platform handle
+ * @param patch_json A JSON object nested as in the configuration, e.g. {"ServiceSettings": {"EnableCommands": true}}
+ * @return A JSON object with the full configuration after the change
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_admin_patch_config(
+    CommunicatorPlatform platform,
+    const char* patch_json
+);
+
+/**
+ * Get usage statistics of the whole server (administrators only)
  *
  * @param platform The platform handle
- * @param channel_id The channel ID
- * @param limit_after Maximum number of posts to retrieve after last read (newer posts)
- * @param limit_before Maximum number of posts to retrieve before last read (context)
- * @return A JSON string containing post list
+ * @return A JSON SystemStats object
  *         Must be freed with communicator_free_string()
  *         Returns NULL on error
  */
-char* communicator_platform_get_unread_posts(
+char* communicator_platform_admin_get_system_stats(
+    CommunicatorPlatform platform
+);
+
+/**
+ * Get a page of the server log (administrators only)
+ *
+ * @param platform The platform handle
+ * @param page The page number (0-indexed)
+ * @param per_page Number of log lines per page
+ * @return A JSON array of raw log lines, oldest first
+ *         Must be freed with communicator_free_string()
+ *         Returns NULL on error
+ */
+char* communicator_platform_admin_get_logs(
     CommunicatorPlatform platform,
-    const char* channel_id,
-    uint32_t limit_after,
-    uint32_t limit_before
+    uint32_t page,
+    uint32_t per_page
 );
 
+// ============================================================================
+// Schema Samples
+// ============================================================================
+
+/**
+ * List the type names accepted by the schema functions
+ * Data types come first, followed by events ("event.<type>")
+ *
+ * @return JSON array of type names, or NULL on error
+ *         Caller must free with communicator_free_string()
+ */
+char* communicator_schema_names(void);
+
+/**
+ * Get the canonical JSON sample of a type
+ * Every optional field is set and every list is non-empty, so bindings can
+ * generate golden files and detect fields their structs do not model
+ *
+ * @param type_name A name from communicator_schema_names()
+ * @return JSON object, or NULL on error (NotFound for an unknown type name)
+ *         Caller must free with communicator_free_string()
+ */
+char* communicator_schema_sample(const char* type_name);
+
+/**
+ * Decode JSON as the native type and encode it again
+ * Fields unknown to the native type are dropped from the result
+ * Events are output-only and return Unsupported
+ *
+ * @param type_name A data type name from communicator_schema_names()
+ * @param json JSON object to round-trip
+ * @return JSON object, or NULL on error
+ *         Caller must free with communicator_free_string()
+ */
+char* communicator_schema_roundtrip(const char* type_name, const char* json);
+
 // ============================================================================
 // Platform Cleanup
 // ============================================================================
@@ -5,8 +5,10 @@
 //! then converted back when needed.
 
 use crate::error::{Error, ErrorCode, Result};
+use crate::logging::{self, LogSink};
 use std::collections::HashMap;
 use std::os::raw::c_void;
+use std::sync::Arc;
 
 /// Log levels for callbacks
 #[repr(C)]
@@ -32,10 +34,9 @@ pub struct Context {
     pub config: HashMap<String, String>,
     /// Internal state
     initialized: bool,
-    /// Optional log callback
-    log_callback: Option<LogCallback>,
-    /// User data passed to callbacks
-    user_data: *mut c_void,
+    /// Optional log callback and the user data passed to it, which also
+    /// receives the library's connection and platform diagnostics
+    log_sink: Option<Arc<LogSink>>,
 }
 
 impl Context {
@@ -45,29 +46,36 @@ impl Context {
             id: id.into(),
             config: HashMap::new(),
             initialized: false,
-            log_callback: None,
-            user_data: std::ptr::null_mut(),
+            log_sink: None,
         }
     }
 
     /// Set a log callback
+    ///
+    /// Besides this context's messages, the callback receives the library's
+    /// diagnostics about connections, the WebSocket, retries and platforms,
+    /// possibly on the library's own threads.
     pub fn set_log_callback(&mut self, callback: LogCallback, user_data: *mut c_void) {
-        self.log_callback = Some(callback);
-        self.user_data = user_data;
+        self.clear_log_callback();
+        let sink = LogSink::new(callback, user_data);
+        logging::add_sink(&sink);
+        self.log_sink = Some(sink);
     }
 
     /// Clear the log callback
+    ///
+    /// Returns once no call to the old callback is in progress, so its user
+    /// data may be freed.
     pub fn clear_log_callback(&mut self) {
-        self.log_callback = None;
-        self.user_data = std::ptr::null_mut();
+        if let Some(sink) = self.log_sink.take() {
+            logging::remove_sink(&sink);
+        }
     }
 
     /// Log a message (internal helper)
     pub(crate) fn log(&self, level: LogLevel, message: &str) {
-        if let Some(callback) = self.log_callback {
-            if let Ok(c_string) = std::ffi::CString::new(message) {
-                callback(level, c_string.as_ptr(), self.user_data);
-            }
+        if let Some(sink) = &self.log_sink {
+            sink.deliver(level, message);
         }
     }
 
@@ -125,6 +133,7 @@ impl Drop for Context {
         if self.initialized {
             let _ = self.shutdown();
         }
+        self.clear_log_callback();
     }
 }
 
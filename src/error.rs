@@ -2,8 +2,8 @@
 //!
 //! This module provides error types and FFI-compatible error handling mechanisms.
 
+use std::cell::RefCell;
 use std::fmt;
-use std::sync::Mutex;
 
 /// Result type used throughout the library
 pub type Result<T> = std::result::Result<T, Error>;
@@ -40,6 +40,8 @@ pub enum ErrorCode {
     Unsupported = 12,
     /// Rate limit exceeded
     RateLimited = 13,
+    /// Feature supported by the platform but not by the connected server's version
+    UnsupportedByServer = 14,
 }
 
 impl ErrorCode {
@@ -59,6 +61,7 @@ impl ErrorCode {
             ErrorCode::InvalidState => "Invalid state",
             ErrorCode::Unsupported => "Feature not supported",
             ErrorCode::RateLimited => "Rate limit exceeded",
+            ErrorCode::UnsupportedByServer => "Not supported by this server",
         }
     }
 }
@@ -145,28 +148,25 @@ impl fmt::Display for Error {
 
 impl std::error::Error for Error {}
 
-// Thread-local error storage for FFI
-lazy_static::lazy_static! {
-    static ref LAST_ERROR: Mutex<Option<Error>> = Mutex::new(None);
+// Thread-local error storage for FFI, so concurrent calls on different
+// threads don't overwrite each other's errors
+thread_local! {
+    static LAST_ERROR: RefCell<Option<Error>> = const { RefCell::new(None) };
 }
 
 /// Set the last error (called internally when FFI functions fail)
 pub(crate) fn set_last_error(error: Error) {
-    if let Ok(mut last) = LAST_ERROR.lock() {
-        *last = Some(error);
-    }
+    LAST_ERROR.with(|last| *last.borrow_mut() = Some(error));
 }
 
 /// Clear the last error
 pub(crate) fn clear_last_error() {
-    if let Ok(mut last) = LAST_ERROR.lock() {
-        *last = None;
-    }
+    LAST_ERROR.with(|last| *last.borrow_mut() = None);
 }
 
 /// Get the last error (for FFI)
 pub(crate) fn get_last_error() -> Option<Error> {
-    LAST_ERROR.lock().ok()?.clone()
+    LAST_ERROR.with(|last| last.borrow().clone())
 }
 
 #[cfg(test)]
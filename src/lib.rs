@@ -4,14 +4,16 @@ use std::os::raw::{c_char, c_void};
 // Core modules
 pub mod context;
 pub mod error;
+mod logging;
 pub mod platforms;
 pub mod runtime;
+pub mod schema;
 pub mod types;
 
 // Re-exports for convenience
 pub use context::{Context, LogCallback, LogLevel};
 pub use error::{Error, ErrorCode, Result};
-pub use platforms::{Platform, PlatformConfig, PlatformEvent};
+pub use platforms::{EventFilter, Platform, PlatformConfig, PlatformEvent};
 pub use types::{
     Attachment, Channel, ChannelType, ChannelUnread, ConnectionInfo, ConnectionState, Emoji,
     Message, Team, TeamType, User,
@@ -183,6 +185,7 @@ pub unsafe extern "C" fn communicator_error_code_string(code: ErrorCode) -> *con
         ErrorCode::InvalidState => "Invalid state\0",
         ErrorCode::Unsupported => "Feature not supported\0",
         ErrorCode::RateLimited => "Rate limit exceeded\0",
+        ErrorCode::UnsupportedByServer => "Not supported by this server\0",
     };
     s.as_ptr() as *const c_char
 }
@@ -426,7 +429,9 @@ pub unsafe extern "C" fn communicator_context_destroy(handle: ContextHandle) {
 // ============================================================================
 
 /// FFI function: Set a log callback on a context
-/// The callback will be called for logging events
+/// The callback will be called for logging events, including the library's
+/// connection, WebSocket, retry and platform diagnostics, which go to the
+/// callback of every context that has one and may arrive on library threads
 /// user_data is an opaque pointer passed back to the callback
 #[no_mangle]
 ///
@@ -451,6 +456,7 @@ pub unsafe extern "C" fn communicator_context_set_log_callback(
 }
 
 /// FFI function: Clear the log callback on a context
+/// Returns once no call to the old callback is in progress
 #[no_mangle]
 ///
 /// # Safety
@@ -471,6 +477,60 @@ pub unsafe extern "C" fn communicator_context_clear_log_callback(
     ErrorCode::Success
 }
 
+/// FFI function: Parse a message link (permalink) from any supported platform
+/// Returns a JSON string representing the MessageLink
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_parse_message_link(url: *const c_char) -> *mut c_char {
+    error::clear_last_error();
+
+    if url.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let url_str = {
+        match std::ffi::CStr::from_ptr(url).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    match platforms::parse_message_link(url_str) {
+        Ok(link) => match serde_json::to_string(&link) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize message link: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
 // ============================================================================
 // Platform FFI - Opaque Handle Pattern
 // ============================================================================
@@ -526,8 +586,34 @@ pub unsafe extern "C" fn communicator_mattermost_create(
 ///   "credentials": {
 ///     "token": "xxx" OR "login_id": "user@example.com", "password": "xxx"
 ///   },
-///   "team_id": "optional-team-id"
+///   "team_id": "optional-team-id",
+///   "proxy_url": "optional http://, socks5:// or socks5h:// proxy URL",
+///   "no_proxy": "optional comma-separated hosts to reach directly",
+///   "ca_cert": "optional extra CA certificate(s), PEM text or file path",
+///   "client_cert": "optional client certificate, PEM text or file path",
+///   "client_key": "optional client private key, PEM text or file path",
+///   "insecure_skip_verify": false,
+///   "connect_timeout_ms": 0,
+///   "request_timeout_ms": 0,
+///   "handshake_timeout_ms": 0,
+///   "idle_timeout_ms": 0,
+///   "rate_limit_max_retries": 3,
+///   "rate_limit_max_wait_ms": 60000,
+///   "retry_max_attempts": 3,
+///   "retry_initial_backoff_ms": 200,
+///   "retry_max_backoff_ms": 5000,
+///   "retry_backoff_multiplier": 2.0,
+///   "retry_jitter": 0.2,
+///   "retry_on": ["network", "server_error"],
+///   "headers": {"CF-Access-Client-Id": "optional header sent with every request"},
+///   "user_agent": "optional User-Agent replacing libcommunicator/<version>"
 /// }
+/// Timeouts are optional; 0 keeps the default (request: 30 s, others: none)
+/// Requests rejected with HTTP 429 are retried up to rate_limit_max_retries
+/// times (0 disables) while the server asks to wait no longer than
+/// rate_limit_max_wait_ms; each rejection is reported as a rate_limited event
+/// Transient failures are retried with exponential backoff per the retry_*
+/// settings; POST requests only when the connection could not be made
 /// Returns ErrorCode indicating success or failure
 #[no_mangle]
 ///
@@ -555,29 +641,15 @@ pub unsafe extern "C" fn communicator_platform_connect(
         }
     };
 
-    // Parse JSON into PlatformConfig
-    #[derive(serde::Deserialize)]
-    struct ConfigJson {
-        server: String,
-        credentials: std::collections::HashMap<String, String>,
-        team_id: Option<String>,
-    }
-
-    let config_data: ConfigJson = match serde_json::from_str(config_str) {
+    let platform_config = match parse_platform_config(config_str) {
         Ok(c) => c,
         Err(e) => {
-            error::set_last_error(Error::new(
-                ErrorCode::InvalidArgument,
-                format!("Invalid config JSON: {e}"),
-            ));
-            return ErrorCode::InvalidArgument;
+            let code = e.code;
+            error::set_last_error(e);
+            return code;
         }
     };
 
-    let mut platform_config = PlatformConfig::new(config_data.server);
-    platform_config.credentials = config_data.credentials;
-    platform_config.team_id = config_data.team_id;
-
     let platform = &mut **handle;
 
     // Run async connect in blocking mode
@@ -632,6 +704,132 @@ pub unsafe extern "C" fn communicator_platform_connect_with_mfa(
     communicator_platform_connect(handle, config_json)
 }
 
+/// FFI function: Capture the session so a restarted process can resume it
+/// Returns a JSON string representing the SessionState (includes the session token)
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_save_state(handle: PlatformHandle) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.save_state()) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize session state: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Reconnect using a session captured by communicator_platform_save_state
+/// Use instead of communicator_platform_connect; fall back to it if this fails
+///
+/// # Arguments
+/// * `handle` - Platform handle
+/// * `state_json` - JSON SessionState as returned by communicator_platform_save_state
+/// * `config_json` - JSON config as for communicator_platform_connect, whose
+///   proxy, TLS, timeout, header and retry settings are applied as connecting
+///   would; credentials are not needed. NULL keeps the defaults.
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_restore_state(
+    handle: PlatformHandle,
+    state_json: *const c_char,
+    config_json: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || state_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let state_json_str = {
+        match std::ffi::CStr::from_ptr(state_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let state: crate::types::SessionState = match serde_json::from_str(state_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid session state JSON: {e}"),
+            ));
+            return ErrorCode::InvalidArgument;
+        }
+    };
+
+    let config = if config_json.is_null() {
+        PlatformConfig::new(state.server_url.clone())
+    } else {
+        let config_str = match std::ffi::CStr::from_ptr(config_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        };
+        match parse_platform_config(config_str) {
+            Ok(c) => c,
+            Err(e) => {
+                let code = e.code;
+                error::set_last_error(e);
+                return code;
+            }
+        }
+    };
+
+    let platform = &mut **handle;
+
+    match runtime::block_on(platform.restore_state(&state, &config)) {
+        Ok(_) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
 /// FFI function: Disconnect from a platform
 /// Returns ErrorCode indicating success or failure
 #[no_mangle]
@@ -1130,6 +1328,99 @@ pub unsafe extern "C" fn communicator_platform_get_current_user(
     }
 }
 
+/// FFI function: Download a user's profile image
+/// Returns ErrorCode indicating success or failure
+///
+/// # Arguments
+/// * `handle` - The platform handle
+/// * `user_id` - The ID of the user
+/// * `out_data` - Output parameter for the image data (caller must free with communicator_free_file_data)
+/// * `out_size` - Output parameter for the size of the image data in bytes
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_user_avatar(
+    handle: PlatformHandle,
+    user_id: *const c_char,
+    out_data: *mut *mut u8,
+    out_size: *mut usize,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || user_id.is_null() || out_data.is_null() || out_size.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let user_id_str = {
+        match std::ffi::CStr::from_ptr(user_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_user_avatar(user_id_str)) {
+        Ok(data) => {
+            let size = data.len();
+            let boxed_data = data.into_boxed_slice();
+            let raw_ptr = Box::into_raw(boxed_data) as *mut u8;
+
+            *out_data = raw_ptr;
+            *out_size = size;
+            ErrorCode::Success
+        }
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Set the current user's profile image
+/// Returns ErrorCode indicating success or failure
+///
+/// # Arguments
+/// * `handle` - The platform handle
+/// * `data` - Pointer to the image data (copied; remains owned by the caller)
+/// * `size` - Size of the image data in bytes
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure `data` points to at least `size` readable bytes.
+pub unsafe extern "C" fn communicator_platform_set_my_avatar(
+    handle: PlatformHandle,
+    data: *const u8,
+    size: usize,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || data.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let image_data = std::slice::from_raw_parts(data, size).to_vec();
+    let platform = &**handle;
+
+    match runtime::block_on(platform.set_my_avatar(image_data)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
 /// FFI function: Create a direct message channel with another user
 /// Returns a JSON string representing the created Channel
 /// The caller must free the returned string using communicator_free_string()
@@ -1436,22 +1727,81 @@ pub unsafe extern "C" fn communicator_platform_delete_channel(
     }
 }
 
-/// FFI function: Get all teams the user belongs to
-/// Returns a JSON string representing an array of Teams
+/// FFI function: Restore (unarchive) a channel
+/// Returns a JSON string representing the restored Channel
 /// The caller must free the returned string using communicator_free_string()
 /// Returns NULL on error
-///
-/// # Safety
-/// The caller must ensure that `handle` is a valid pointer
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_get_teams(handle: PlatformHandle) -> *mut c_char {
+pub unsafe extern "C" fn communicator_platform_restore_channel(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() {
+    if handle.is_null() || channel_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.restore_channel(channel_id_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize channel: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Get all teams the user belongs to
+/// Returns a JSON string representing an array of Teams
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+///
+/// # Safety
+/// The caller must ensure that `handle` is a valid pointer
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_teams(handle: PlatformHandle) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
@@ -1544,6 +1894,215 @@ pub unsafe extern "C" fn communicator_platform_get_team(
     }
 }
 
+/// FFI function: Create a new team
+/// Returns a JSON string representing the created Team
+///
+/// # Arguments
+/// * `handle` - The platform handle
+/// * `team_json` - JSON NewTeam object with name, display_name and optional settings
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_create_team(
+    handle: PlatformHandle,
+    team_json: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || team_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let team_json_str = {
+        match std::ffi::CStr::from_ptr(team_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let team: crate::types::NewTeam = match serde_json::from_str(team_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid team JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.create_team(&team)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize team: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Update a team's settings (partial update)
+/// Returns a JSON string representing the updated Team
+///
+/// # Arguments
+/// * `handle` - The platform handle
+/// * `team_id` - The ID of the team to update
+/// * `patch_json` - JSON TeamPatch object; omitted fields are left unchanged
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_update_team(
+    handle: PlatformHandle,
+    team_id: *const c_char,
+    patch_json: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || team_id.is_null() || patch_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let patch_json_str = {
+        match std::ffi::CStr::from_ptr(patch_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let patch: crate::types::TeamPatch = match serde_json::from_str(patch_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid team patch JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.update_team(team_id_str, &patch)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize team: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Set a team's icon
+/// Returns ErrorCode indicating success or failure
+///
+/// # Arguments
+/// * `handle` - The platform handle
+/// * `team_id` - The ID of the team
+/// * `data` - Pointer to the image data (copied; remains owned by the caller)
+/// * `size` - Size of the image data in bytes
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure `team_id` is a valid C string and `data` points to at least `size` readable bytes.
+pub unsafe extern "C" fn communicator_platform_set_team_icon(
+    handle: PlatformHandle,
+    team_id: *const c_char,
+    data: *const u8,
+    size: usize,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || team_id.is_null() || data.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let image_data = std::slice::from_raw_parts(data, size).to_vec();
+    let platform = &**handle;
+
+    match runtime::block_on(platform.set_team_icon(team_id_str, image_data)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
 /// FFI function: Set the current user's status
 /// Returns ErrorCode indicating success or failure
 ///
@@ -1825,43 +2384,177 @@ pub unsafe extern "C" fn communicator_platform_request_users_statuses(
     }
 }
 
-/// FFI function: Subscribe to real-time events
-/// Returns ErrorCode indicating success or failure
+/// FFI function: Get the health of the real-time connection as JSON
+///
+/// Includes the last ping round-trip time, when the last pong and event arrived
+/// and how many pings are unanswered. Requires subscribe_events first.
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_subscribe_events(
+pub unsafe extern "C" fn communicator_platform_get_connection_health(
     handle: PlatformHandle,
-) -> ErrorCode {
+) -> *mut c_char {
     error::clear_last_error();
 
     if handle.is_null() {
         error::set_last_error(Error::null_pointer());
-        return ErrorCode::NullPointer;
+        return std::ptr::null_mut();
     }
 
-    let platform = &mut **handle;
+    let platform = &**handle;
 
-    match runtime::block_on(platform.subscribe_events()) {
-        Ok(()) => ErrorCode::Success,
+    match runtime::block_on(platform.connection_health()) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize connection health: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
         Err(e) => {
-            let code = e.code;
             error::set_last_error(e);
-            code
+            std::ptr::null_mut()
         }
     }
 }
 
-/// FFI function: Unsubscribe from real-time events
-/// Returns ErrorCode indicating success or failure
+/// FFI function: Ping the server over the real-time connection
+///
+/// Blocks until the pong arrives or timeout_ms elapses; 0 means 10 seconds.
+/// Returns the round-trip time in milliseconds, or -1 on error
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_unsubscribe_events(
+pub unsafe extern "C" fn communicator_platform_ping(
+    handle: PlatformHandle,
+    timeout_ms: u64,
+) -> i64 {
+    error::clear_last_error();
+
+    if handle.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return -1;
+    }
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.ping(timeout_ms)) {
+        Ok(rtt_ms) => rtt_ms as i64,
+        Err(e) => {
+            error::set_last_error(e);
+            -1
+        }
+    }
+}
+
+/// FFI function: Subscribe to real-time events
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_subscribe_events(
+    handle: PlatformHandle,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let platform = &mut **handle;
+
+    match runtime::block_on(platform.subscribe_events()) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Subscribe to real-time events within a scope
+///
+/// filter_json is an EventFilter: {"channel_ids": [...], "team_ids": [...]}
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_subscribe_events_filtered(
+    handle: PlatformHandle,
+    filter_json: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || filter_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let filter_json_str = {
+        match std::ffi::CStr::from_ptr(filter_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let filter: EventFilter = match serde_json::from_str(filter_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid event filter JSON: {e}"),
+            ));
+            return ErrorCode::InvalidArgument;
+        }
+    };
+
+    let platform = &mut **handle;
+
+    match runtime::block_on(platform.subscribe_events_filtered(filter)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Unsubscribe from real-time events
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_unsubscribe_events(
     handle: PlatformHandle,
 ) -> ErrorCode {
     error::clear_last_error();
@@ -1883,6 +2576,517 @@ pub unsafe extern "C" fn communicator_platform_unsubscribe_events(
     }
 }
 
+/// FFI function: Enable or disable strict validation of incoming event payloads
+/// When enabled, payloads that do not match their expected schema are reported
+/// as "schema_mismatch" events. Takes effect on the next subscribe_events call.
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_set_strict_event_validation(
+    handle: PlatformHandle,
+    enabled: std::os::raw::c_int,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.set_strict_event_validation(enabled != 0)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// Parse the JSON PlatformConfig passed to communicator_platform_connect
+///
+/// Credentials may be omitted or null, as they are by
+/// communicator_platform_restore_state.
+fn parse_platform_config(config_str: &str) -> Result<PlatformConfig> {
+    #[derive(serde::Deserialize)]
+    struct ConfigJson {
+        server: String,
+        #[serde(default)]
+        credentials: Option<std::collections::HashMap<String, String>>,
+        team_id: Option<String>,
+        proxy_url: Option<String>,
+        no_proxy: Option<String>,
+        ca_cert: Option<String>,
+        client_cert: Option<String>,
+        client_key: Option<String>,
+        #[serde(default)]
+        insecure_skip_verify: bool,
+        connect_timeout_ms: Option<u64>,
+        request_timeout_ms: Option<u64>,
+        handshake_timeout_ms: Option<u64>,
+        idle_timeout_ms: Option<u64>,
+        rate_limit_max_retries: Option<u32>,
+        rate_limit_max_wait_ms: Option<u64>,
+        retry_max_attempts: Option<u32>,
+        retry_initial_backoff_ms: Option<u64>,
+        retry_max_backoff_ms: Option<u64>,
+        retry_backoff_multiplier: Option<f64>,
+        retry_jitter: Option<f64>,
+        retry_on: Option<Vec<String>>,
+        #[serde(default)]
+        headers: std::collections::HashMap<String, String>,
+        user_agent: Option<String>,
+    }
+
+    let config_data: ConfigJson = serde_json::from_str(config_str)
+        .map_err(|e| Error::invalid_argument(format!("Invalid config JSON: {e}")))?;
+
+    let mut platform_config = PlatformConfig::new(config_data.server);
+    platform_config.credentials = config_data.credentials.unwrap_or_default();
+    platform_config.team_id = config_data.team_id;
+    platform_config.proxy_url = config_data.proxy_url;
+    platform_config.no_proxy = config_data.no_proxy;
+    platform_config.ca_cert = config_data.ca_cert;
+    platform_config.client_cert = config_data.client_cert;
+    platform_config.client_key = config_data.client_key;
+    platform_config.insecure_skip_verify = config_data.insecure_skip_verify;
+    platform_config.connect_timeout_ms = config_data.connect_timeout_ms;
+    platform_config.request_timeout_ms = config_data.request_timeout_ms;
+    platform_config.handshake_timeout_ms = config_data.handshake_timeout_ms;
+    platform_config.idle_timeout_ms = config_data.idle_timeout_ms;
+    platform_config.rate_limit_max_retries = config_data.rate_limit_max_retries;
+    platform_config.rate_limit_max_wait_ms = config_data.rate_limit_max_wait_ms;
+    platform_config.retry_max_attempts = config_data.retry_max_attempts;
+    platform_config.retry_initial_backoff_ms = config_data.retry_initial_backoff_ms;
+    platform_config.retry_max_backoff_ms = config_data.retry_max_backoff_ms;
+    platform_config.retry_backoff_multiplier = config_data.retry_backoff_multiplier;
+    platform_config.retry_jitter = config_data.retry_jitter;
+    platform_config.retry_on = config_data.retry_on;
+    platform_config.headers = config_data.headers;
+    platform_config.user_agent = config_data.user_agent;
+
+    Ok(platform_config)
+}
+
+/// Convert a platform event to the JSON object returned by
+/// communicator_platform_poll_event
+///
+/// PlatformEvent has no Serialize impl, since its JSON shape is part of the
+/// FFI contract rather than derived from the enum.
+pub(crate) fn event_to_json(event: PlatformEvent) -> serde_json::Value {
+    match event {
+        PlatformEvent::MessagePosted(msg) => {
+            serde_json::json!({
+                "type": "message_posted",
+                "data": msg
+            })
+        }
+        PlatformEvent::MessageUpdated(msg) => {
+            serde_json::json!({
+                "type": "message_updated",
+                "data": msg
+            })
+        }
+        PlatformEvent::PostPinned(msg) => {
+            serde_json::json!({
+                "type": "post_pinned",
+                "data": msg
+            })
+        }
+        PlatformEvent::PostUnpinned(msg) => {
+            serde_json::json!({
+                "type": "post_unpinned",
+                "data": msg
+            })
+        }
+        PlatformEvent::MessageDeleted {
+            message_id,
+            channel_id,
+        } => {
+            serde_json::json!({
+                "type": "message_deleted",
+                "message_id": message_id,
+                "channel_id": channel_id
+            })
+        }
+        PlatformEvent::UserStatusChanged { user_id, status } => {
+            serde_json::json!({
+                "type": "user_status_changed",
+                "user_id": user_id,
+                "status": status
+            })
+        }
+        PlatformEvent::UserTyping {
+            user_id,
+            channel_id,
+        } => {
+            serde_json::json!({
+                "type": "user_typing",
+                "user_id": user_id,
+                "channel_id": channel_id
+            })
+        }
+        PlatformEvent::ChannelCreated(channel) => {
+            serde_json::json!({
+                "type": "channel_created",
+                "data": channel
+            })
+        }
+        PlatformEvent::ChannelUpdated { channel, changes } => {
+            serde_json::json!({
+                "type": "channel_updated",
+                "data": channel,
+                "changes": changes
+            })
+        }
+        PlatformEvent::ChannelDeleted { channel_id } => {
+            serde_json::json!({
+                "type": "channel_deleted",
+                "channel_id": channel_id
+            })
+        }
+        PlatformEvent::UserJoinedChannel {
+            user_id,
+            channel_id,
+        } => {
+            serde_json::json!({
+                "type": "user_joined_channel",
+                "user_id": user_id,
+                "channel_id": channel_id
+            })
+        }
+        PlatformEvent::UserLeftChannel {
+            user_id,
+            channel_id,
+        } => {
+            serde_json::json!({
+                "type": "user_left_channel",
+                "user_id": user_id,
+                "channel_id": channel_id
+            })
+        }
+        PlatformEvent::ConnectionStateChanged(change) => {
+            serde_json::json!({
+                "type": "connection_state_changed",
+                "state": change.state,
+                "reason": change.reason,
+                "close_code": change.close_code,
+                "detail": change.detail,
+                "attempt": change.attempt,
+                "next_retry_ms": change.next_retry_ms
+            })
+        }
+        PlatformEvent::GapDetected {
+            expected_seq,
+            received_seq,
+        } => {
+            serde_json::json!({
+                "type": "gap_detected",
+                "expected_seq": expected_seq,
+                "received_seq": received_seq
+            })
+        }
+        PlatformEvent::ReactionAdded {
+            message_id,
+            user_id,
+            emoji_name,
+            channel_id,
+        } => {
+            serde_json::json!({
+                "type": "reaction_added",
+                "message_id": message_id,
+                "user_id": user_id,
+                "emoji_name": emoji_name,
+                "channel_id": channel_id
+            })
+        }
+        PlatformEvent::ReactionRemoved {
+            message_id,
+            user_id,
+            emoji_name,
+            channel_id,
+        } => {
+            serde_json::json!({
+                "type": "reaction_removed",
+                "message_id": message_id,
+                "user_id": user_id,
+                "emoji_name": emoji_name,
+                "channel_id": channel_id
+            })
+        }
+        PlatformEvent::DirectChannelAdded { channel_id } => {
+            serde_json::json!({
+                "type": "direct_channel_added",
+                "channel_id": channel_id
+            })
+        }
+        PlatformEvent::GroupChannelAdded { channel_id } => {
+            serde_json::json!({
+                "type": "group_channel_added",
+                "channel_id": channel_id
+            })
+        }
+        PlatformEvent::PreferenceChanged {
+            category,
+            name,
+            value,
+        } => {
+            serde_json::json!({
+                "type": "preference_changed",
+                "category": category,
+                "name": name,
+                "value": value
+            })
+        }
+        PlatformEvent::EphemeralMessage {
+            message,
+            channel_id,
+        } => {
+            serde_json::json!({
+                "type": "ephemeral_message",
+                "message": message,
+                "channel_id": channel_id
+            })
+        }
+        PlatformEvent::UserAdded { user_id } => {
+            serde_json::json!({
+                "type": "user_added",
+                "user_id": user_id
+            })
+        }
+        PlatformEvent::UserUpdated { user_id } => {
+            serde_json::json!({
+                "type": "user_updated",
+                "user_id": user_id
+            })
+        }
+        PlatformEvent::UserRoleUpdated { user_id } => {
+            serde_json::json!({
+                "type": "user_role_updated",
+                "user_id": user_id
+            })
+        }
+        PlatformEvent::ChannelViewed {
+            user_id,
+            channel_id,
+        } => {
+            serde_json::json!({
+                "type": "channel_viewed",
+                "user_id": user_id,
+                "channel_id": channel_id
+            })
+        }
+        PlatformEvent::ThreadUpdated {
+            thread_id,
+            channel_id,
+        } => {
+            serde_json::json!({
+                "type": "thread_updated",
+                "thread_id": thread_id,
+                "channel_id": channel_id
+            })
+        }
+        PlatformEvent::ThreadReadChanged {
+            thread_id,
+            user_id,
+            channel_id,
+        } => {
+            serde_json::json!({
+                "type": "thread_read_changed",
+                "thread_id": thread_id,
+                "user_id": user_id,
+                "channel_id": channel_id
+            })
+        }
+        PlatformEvent::ThreadFollowChanged {
+            thread_id,
+            user_id,
+            channel_id,
+            following,
+        } => {
+            serde_json::json!({
+                "type": "thread_follow_changed",
+                "thread_id": thread_id,
+                "user_id": user_id,
+                "channel_id": channel_id,
+                "following": following
+            })
+        }
+        PlatformEvent::PostUnread {
+            post_id,
+            channel_id,
+            user_id,
+        } => {
+            serde_json::json!({
+                "type": "post_unread",
+                "post_id": post_id,
+                "channel_id": channel_id,
+                "user_id": user_id
+            })
+        }
+        PlatformEvent::EmojiAdded {
+            emoji_id,
+            emoji_name,
+        } => {
+            serde_json::json!({
+                "type": "emoji_added",
+                "emoji_id": emoji_id,
+                "emoji_name": emoji_name
+            })
+        }
+        PlatformEvent::AddedToTeam { team_id, user_id } => {
+            serde_json::json!({
+                "type": "added_to_team",
+                "team_id": team_id,
+                "user_id": user_id
+            })
+        }
+        PlatformEvent::LeftTeam { team_id, user_id } => {
+            serde_json::json!({
+                "type": "left_team",
+                "team_id": team_id,
+                "user_id": user_id
+            })
+        }
+        PlatformEvent::ConfigChanged => {
+            serde_json::json!({
+                "type": "config_changed"
+            })
+        }
+        PlatformEvent::LicenseChanged => {
+            serde_json::json!({
+                "type": "license_changed"
+            })
+        }
+        PlatformEvent::ChannelConverted { channel_id } => {
+            serde_json::json!({
+                "type": "channel_converted",
+                "channel_id": channel_id
+            })
+        }
+        PlatformEvent::ChannelMemberUpdated {
+            channel_id,
+            user_id,
+        } => {
+            serde_json::json!({
+                "type": "channel_member_updated",
+                "channel_id": channel_id,
+                "user_id": user_id
+            })
+        }
+        PlatformEvent::TeamDeleted { team_id } => {
+            serde_json::json!({
+                "type": "team_deleted",
+                "team_id": team_id
+            })
+        }
+        PlatformEvent::TeamUpdated { team_id } => {
+            serde_json::json!({
+                "type": "team_updated",
+                "team_id": team_id
+            })
+        }
+        PlatformEvent::MemberRoleUpdated {
+            channel_id,
+            user_id,
+        } => {
+            serde_json::json!({
+                "type": "member_role_updated",
+                "channel_id": channel_id,
+                "user_id": user_id
+            })
+        }
+        PlatformEvent::PluginDisabled { plugin_id } => {
+            serde_json::json!({
+                "type": "plugin_disabled",
+                "plugin_id": plugin_id
+            })
+        }
+        PlatformEvent::PluginEnabled { plugin_id } => {
+            serde_json::json!({
+                "type": "plugin_enabled",
+                "plugin_id": plugin_id
+            })
+        }
+        PlatformEvent::PluginStatusesChanged => {
+            serde_json::json!({
+                "type": "plugin_statuses_changed"
+            })
+        }
+        PlatformEvent::PreferencesDeleted { category, name } => {
+            serde_json::json!({
+                "type": "preferences_deleted",
+                "category": category,
+                "name": name
+            })
+        }
+        PlatformEvent::Response {
+            status,
+            seq_reply,
+            error,
+            data,
+        } => {
+            serde_json::json!({
+                "type": "response",
+                "status": status,
+                "seq_reply": seq_reply,
+                "error": error,
+                "data": data
+            })
+        }
+        PlatformEvent::DialogOpened { dialog_id } => {
+            serde_json::json!({
+                "type": "dialog_opened",
+                "dialog_id": dialog_id
+            })
+        }
+        PlatformEvent::RoleUpdated { role_id } => {
+            serde_json::json!({
+                "type": "role_updated",
+                "role_id": role_id
+            })
+        }
+        PlatformEvent::SchemaMismatch { event_type, issues } => {
+            serde_json::json!({
+                "type": "schema_mismatch",
+                "event_type": event_type,
+                "issues": issues
+            })
+        }
+        PlatformEvent::Raw {
+            event_type,
+            data,
+            channel_id,
+            team_id,
+        } => {
+            serde_json::json!({
+                "type": "raw",
+                "raw_type": event_type,
+                "raw_data": data,
+                "channel_id": channel_id,
+                "team_id": team_id
+            })
+        }
+        PlatformEvent::RateLimited {
+            endpoint,
+            attempt,
+            retry_after_ms,
+        } => {
+            serde_json::json!({
+                "type": "rate_limited",
+                "endpoint": endpoint,
+                "attempt": attempt,
+                "next_retry_ms": retry_after_ms
+            })
+        }
+    }
+}
+
 /// FFI function: Poll for the next event
 /// Returns a JSON string representing the PlatformEvent, or NULL if no events are available
 /// The caller must free the returned string using communicator_free_string()
@@ -1900,355 +3104,14 @@ pub unsafe extern "C" fn communicator_platform_poll_event(handle: PlatformHandle
         return std::ptr::null_mut();
     }
 
-    let platform = &mut **handle;
+    let platform = &**handle;
 
     match runtime::block_on(platform.poll_event()) {
         Ok(Some(event)) => {
-            // Serialize the event to JSON
-            // Note: PlatformEvent enum needs custom serialization
-            let json = match event {
-                PlatformEvent::MessagePosted(msg) => {
-                    serde_json::json!({
-                        "type": "message_posted",
-                        "data": msg
-                    })
-                }
-                PlatformEvent::MessageUpdated(msg) => {
-                    serde_json::json!({
-                        "type": "message_updated",
-                        "data": msg
-                    })
-                }
-                PlatformEvent::MessageDeleted {
-                    message_id,
-                    channel_id,
-                } => {
-                    serde_json::json!({
-                        "type": "message_deleted",
-                        "message_id": message_id,
-                        "channel_id": channel_id
-                    })
-                }
-                PlatformEvent::UserStatusChanged { user_id, status } => {
-                    serde_json::json!({
-                        "type": "user_status_changed",
-                        "user_id": user_id,
-                        "status": status
-                    })
-                }
-                PlatformEvent::UserTyping {
-                    user_id,
-                    channel_id,
-                } => {
-                    serde_json::json!({
-                        "type": "user_typing",
-                        "user_id": user_id,
-                        "channel_id": channel_id
-                    })
-                }
-                PlatformEvent::ChannelCreated(channel) => {
-                    serde_json::json!({
-                        "type": "channel_created",
-                        "data": channel
-                    })
-                }
-                PlatformEvent::ChannelUpdated(channel) => {
-                    serde_json::json!({
-                        "type": "channel_updated",
-                        "data": channel
-                    })
-                }
-                PlatformEvent::ChannelDeleted { channel_id } => {
-                    serde_json::json!({
-                        "type": "channel_deleted",
-                        "channel_id": channel_id
-                    })
-                }
-                PlatformEvent::UserJoinedChannel {
-                    user_id,
-                    channel_id,
-                } => {
-                    serde_json::json!({
-                        "type": "user_joined_channel",
-                        "user_id": user_id,
-                        "channel_id": channel_id
-                    })
-                }
-                PlatformEvent::UserLeftChannel {
-                    user_id,
-                    channel_id,
-                } => {
-                    serde_json::json!({
-                        "type": "user_left_channel",
-                        "user_id": user_id,
-                        "channel_id": channel_id
-                    })
-                }
-                PlatformEvent::ConnectionStateChanged(state) => {
-                    serde_json::json!({
-                        "type": "connection_state_changed",
-                        "state": state
-                    })
-                }
-                PlatformEvent::ReactionAdded {
-                    message_id,
-                    user_id,
-                    emoji_name,
-                    channel_id,
-                } => {
-                    serde_json::json!({
-                        "type": "reaction_added",
-                        "message_id": message_id,
-                        "user_id": user_id,
-                        "emoji_name": emoji_name,
-                        "channel_id": channel_id
-                    })
-                }
-                PlatformEvent::ReactionRemoved {
-                    message_id,
-                    user_id,
-                    emoji_name,
-                    channel_id,
-                } => {
-                    serde_json::json!({
-                        "type": "reaction_removed",
-                        "message_id": message_id,
-                        "user_id": user_id,
-                        "emoji_name": emoji_name,
-                        "channel_id": channel_id
-                    })
-                }
-                PlatformEvent::DirectChannelAdded { channel_id } => {
-                    serde_json::json!({
-                        "type": "direct_channel_added",
-                        "channel_id": channel_id
-                    })
-                }
-                PlatformEvent::GroupChannelAdded { channel_id } => {
-                    serde_json::json!({
-                        "type": "group_channel_added",
-                        "channel_id": channel_id
-                    })
-                }
-                PlatformEvent::PreferenceChanged {
-                    category,
-                    name,
-                    value,
-                } => {
-                    serde_json::json!({
-                        "type": "preference_changed",
-                        "category": category,
-                        "name": name,
-                        "value": value
-                    })
-                }
-                PlatformEvent::EphemeralMessage {
-                    message,
-                    channel_id,
-                } => {
-                    serde_json::json!({
-                        "type": "ephemeral_message",
-                        "message": message,
-                        "channel_id": channel_id
-                    })
-                }
-                PlatformEvent::UserAdded { user_id } => {
-                    serde_json::json!({
-                        "type": "user_added",
-                        "user_id": user_id
-                    })
-                }
-                PlatformEvent::UserUpdated { user_id } => {
-                    serde_json::json!({
-                        "type": "user_updated",
-                        "user_id": user_id
-                    })
-                }
-                PlatformEvent::UserRoleUpdated { user_id } => {
-                    serde_json::json!({
-                        "type": "user_role_updated",
-                        "user_id": user_id
-                    })
-                }
-                PlatformEvent::ChannelViewed {
-                    user_id,
-                    channel_id,
-                } => {
-                    serde_json::json!({
-                        "type": "channel_viewed",
-                        "user_id": user_id,
-                        "channel_id": channel_id
-                    })
-                }
-                PlatformEvent::ThreadUpdated {
-                    thread_id,
-                    channel_id,
-                } => {
-                    serde_json::json!({
-                        "type": "thread_updated",
-                        "thread_id": thread_id,
-                        "channel_id": channel_id
-                    })
-                }
-                PlatformEvent::ThreadReadChanged {
-                    thread_id,
-                    user_id,
-                    channel_id,
-                } => {
-                    serde_json::json!({
-                        "type": "thread_read_changed",
-                        "thread_id": thread_id,
-                        "user_id": user_id,
-                        "channel_id": channel_id
-                    })
-                }
-                PlatformEvent::ThreadFollowChanged {
-                    thread_id,
-                    user_id,
-                    channel_id,
-                    following,
-                } => {
-                    serde_json::json!({
-                        "type": "thread_follow_changed",
-                        "thread_id": thread_id,
-                        "user_id": user_id,
-                        "channel_id": channel_id,
-                        "following": following
-                    })
-                }
-                PlatformEvent::PostUnread {
-                    post_id,
-                    channel_id,
-                    user_id,
-                } => {
-                    serde_json::json!({
-                        "type": "post_unread",
-                        "post_id": post_id,
-                        "channel_id": channel_id,
-                        "user_id": user_id
-                    })
-                }
-                PlatformEvent::EmojiAdded {
-                    emoji_id,
-                    emoji_name,
-                } => {
-                    serde_json::json!({
-                        "type": "emoji_added",
-                        "emoji_id": emoji_id,
-                        "emoji_name": emoji_name
-                    })
-                }
-                PlatformEvent::AddedToTeam { team_id, user_id } => {
-                    serde_json::json!({
-                        "type": "added_to_team",
-                        "team_id": team_id,
-                        "user_id": user_id
-                    })
-                }
-                PlatformEvent::LeftTeam { team_id, user_id } => {
-                    serde_json::json!({
-                        "type": "left_team",
-                        "team_id": team_id,
-                        "user_id": user_id
-                    })
-                }
-                PlatformEvent::ConfigChanged => {
-                    serde_json::json!({
-                        "type": "config_changed"
-                    })
-                }
-                PlatformEvent::LicenseChanged => {
-                    serde_json::json!({
-                        "type": "license_changed"
-                    })
-                }
-                PlatformEvent::ChannelConverted { channel_id } => {
-                    serde_json::json!({
-                        "type": "channel_converted",
-                        "channel_id": channel_id
-                    })
-                }
-                PlatformEvent::ChannelMemberUpdated {
-                    channel_id,
-                    user_id,
-                } => {
-                    serde_json::json!({
-                        "type": "channel_member_updated",
-                        "channel_id": channel_id,
-                        "user_id": user_id
-                    })
-                }
-                PlatformEvent::TeamDeleted { team_id } => {
-                    serde_json::json!({
-                        "type": "team_deleted",
-                        "team_id": team_id
-                    })
-                }
-                PlatformEvent::TeamUpdated { team_id } => {
-                    serde_json::json!({
-                        "type": "team_updated",
-                        "team_id": team_id
-                    })
-                }
-                PlatformEvent::MemberRoleUpdated {
-                    channel_id,
-                    user_id,
-                } => {
-                    serde_json::json!({
-                        "type": "member_role_updated",
-                        "channel_id": channel_id,
-                        "user_id": user_id
-                    })
-                }
-                PlatformEvent::PluginDisabled { plugin_id } => {
-                    serde_json::json!({
-                        "type": "plugin_disabled",
-                        "plugin_id": plugin_id
-                    })
-                }
-                PlatformEvent::PluginEnabled { plugin_id } => {
-                    serde_json::json!({
-                        "type": "plugin_enabled",
-                        "plugin_id": plugin_id
-                    })
-                }
-                PlatformEvent::PluginStatusesChanged => {
-                    serde_json::json!({
-                        "type": "plugin_statuses_changed"
-                    })
-                }
-                PlatformEvent::PreferencesDeleted { category, name } => {
-                    serde_json::json!({
-                        "type": "preferences_deleted",
-                        "category": category,
-                        "name": name
-                    })
-                }
-                PlatformEvent::Response {
-                    status,
-                    seq_reply,
-                    error,
-                } => {
-                    serde_json::json!({
-                        "type": "response",
-                        "status": status,
-                        "seq_reply": seq_reply,
-                        "error": error
-                    })
-                }
-                PlatformEvent::DialogOpened { dialog_id } => {
-                    serde_json::json!({
-                        "type": "dialog_opened",
-                        "dialog_id": dialog_id
-                    })
-                }
-                PlatformEvent::RoleUpdated { role_id } => {
-                    serde_json::json!({
-                        "type": "role_updated",
-                        "role_id": role_id
-                    })
-                }
-            };
+            let mut json = event_to_json(event);
+            if let Some(seq) = platform.last_polled_sequence() {
+                json["seq"] = seq.into();
+            }
 
             match serde_json::to_string(&json) {
                 Ok(json_str) => match CString::new(json_str) {
@@ -2366,8 +3229,11 @@ pub unsafe extern "C" fn communicator_platform_send_reply(
     }
 }
 
-/// FFI function: Update/edit a message
-/// Returns a JSON string representing the updated Message
+/// Send a message with options such as a thread or an identity override
+///
+/// Takes the options as a JSON object with optional root_id, username,
+/// icon_url, icon_emoji and file_ids fields, and returns the created message
+/// as JSON
 /// The caller must free the returned string using communicator_free_string()
 /// Returns NULL on error
 #[no_mangle]
@@ -2375,20 +3241,21 @@ pub unsafe extern "C" fn communicator_platform_send_reply(
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_update_message(
+pub unsafe extern "C" fn communicator_platform_send_message_with_options(
     handle: PlatformHandle,
-    message_id: *const c_char,
-    new_text: *const c_char,
+    channel_id: *const c_char,
+    text: *const c_char,
+    options_json: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || message_id.is_null() || new_text.is_null() {
+    if handle.is_null() || channel_id.is_null() || text.is_null() || options_json.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let message_id_str = {
-        match std::ffi::CStr::from_ptr(message_id).to_str() {
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -2398,7 +3265,17 @@ pub unsafe extern "C" fn communicator_platform_update_message(
     };
 
     let text_str = {
-        match std::ffi::CStr::from_ptr(new_text).to_str() {
+        match std::ffi::CStr::from_ptr(text).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let options_json_str = {
+        match std::ffi::CStr::from_ptr(options_json).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -2407,10 +3284,22 @@ pub unsafe extern "C" fn communicator_platform_update_message(
         }
     };
 
+    let options: crate::types::SendMessageOptions = match serde_json::from_str(options_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid send options JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
     let platform = &**handle;
 
-    match runtime::block_on(platform.update_message(message_id_str, text_str)) {
-        Ok(message) => match serde_json::to_string(&message) {
+    match runtime::block_on(platform.send_message_with_options(channel_id_str, text_str, &options))
+    {
+        Ok(result) => match serde_json::to_string(&result) {
             Ok(json) => match CString::new(json) {
                 Ok(c_string) => c_string.into_raw(),
                 Err(_) => {
@@ -2436,22 +3325,25 @@ pub unsafe extern "C" fn communicator_platform_update_message(
     }
 }
 
-/// FFI function: Delete a message
-/// Returns ErrorCode indicating success or failure
+/// FFI function: Update/edit a message
+/// Returns a JSON string representing the updated Message
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_delete_message(
+pub unsafe extern "C" fn communicator_platform_update_message(
     handle: PlatformHandle,
     message_id: *const c_char,
-) -> ErrorCode {
+    new_text: *const c_char,
+) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || message_id.is_null() {
+    if handle.is_null() || message_id.is_null() || new_text.is_null() {
         error::set_last_error(Error::null_pointer());
-        return ErrorCode::NullPointer;
+        return std::ptr::null_mut();
     }
 
     let message_id_str = {
@@ -2459,27 +3351,94 @@ pub unsafe extern "C" fn communicator_platform_delete_message(
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
+                return std::ptr::null_mut();
             }
         }
     };
 
-    let platform = &**handle;
-
-    match runtime::block_on(platform.delete_message(message_id_str)) {
-        Ok(()) => ErrorCode::Success,
-        Err(e) => {
-            let code = e.code;
-            error::set_last_error(e);
-            code
+    let text_str = {
+        match std::ffi::CStr::from_ptr(new_text).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
         }
-    }
-}
+    };
 
-/// FFI function: Get a specific message by ID
-/// Returns a JSON string representing the Message
-/// The caller must free the returned string using communicator_free_string()
-/// Returns NULL on error
+    let platform = &**handle;
+
+    match runtime::block_on(platform.update_message(message_id_str, text_str)) {
+        Ok(message) => match serde_json::to_string(&message) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize message: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Delete a message
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_delete_message(
+    handle: PlatformHandle,
+    message_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || message_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let message_id_str = {
+        match std::ffi::CStr::from_ptr(message_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.delete_message(message_id_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Get a specific message by ID
+/// Returns a JSON string representing the Message
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
 #[no_mangle]
 ///
 /// # Safety
@@ -2685,6 +3644,66 @@ pub unsafe extern "C" fn communicator_platform_get_messages_after(
     }
 }
 
+/// FFI function: Get messages created, edited or deleted since a timestamp
+/// Returns a JSON array string of Message objects, oldest first
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_messages_since(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+    since: u64,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_messages_since(channel_id_str, since)) {
+        Ok(messages) => match serde_json::to_string(&messages) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize messages: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
 /// FFI function: Add a reaction to a message
 /// Returns error code indicating success or failure
 #[no_mangle]
@@ -2924,8 +3943,8 @@ pub unsafe extern "C" fn communicator_platform_get_pinned_posts(
     }
 }
 
-/// FFI function: Get a list of custom emojis
-/// Returns a JSON string representing a Vec<Emoji>
+/// FFI function: Get a permanent link to a message
+/// Returns the permalink URL as a string
 /// The caller must free the returned string using communicator_free_string()
 /// Returns NULL on error
 #[no_mangle]
@@ -2933,33 +3952,36 @@ pub unsafe extern "C" fn communicator_platform_get_pinned_posts(
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_get_emojis(
+pub unsafe extern "C" fn communicator_platform_get_permalink(
     handle: PlatformHandle,
-    page: u32,
-    per_page: u32,
+    message_id: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() {
+    if handle.is_null() || message_id.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
+    let message_id_str = {
+        match std::ffi::CStr::from_ptr(message_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_emojis(page, per_page)) {
-        Ok(emojis) => match serde_json::to_string(&emojis) {
-            Ok(json_str) => match CString::new(json_str) {
-                Ok(c_str) => c_str.into_raw(),
-                Err(_) => {
-                    error::set_last_error(Error::invalid_utf8());
-                    std::ptr::null_mut()
-                }
-            },
-            Err(e) => {
+    match runtime::block_on(platform.get_permalink(message_id_str)) {
+        Ok(link) => match CString::new(link) {
+            Ok(c_string) => c_string.into_raw(),
+            Err(_) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    format!("Failed to serialize emojis: {e}"),
+                    "Failed to convert result to C string",
                 ));
                 std::ptr::null_mut()
             }
@@ -2971,8 +3993,8 @@ pub unsafe extern "C" fn communicator_platform_get_emojis(
     }
 }
 
-/// FFI function: Get a channel by name
-/// Returns a JSON string representing the Channel
+/// FFI function: Resolve a permalink to the message it points to
+/// Returns a JSON object of the form {"message": Message, "channel": Channel}
 /// The caller must free the returned string using communicator_free_string()
 /// Returns NULL on error
 #[no_mangle]
@@ -2980,30 +4002,19 @@ pub unsafe extern "C" fn communicator_platform_get_emojis(
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_get_channel_by_name(
+pub unsafe extern "C" fn communicator_platform_resolve_permalink(
     handle: PlatformHandle,
-    team_id: *const c_char,
-    channel_name: *const c_char,
+    permalink: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || team_id.is_null() || channel_name.is_null() {
+    if handle.is_null() || permalink.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let team_id_str = {
-        match std::ffi::CStr::from_ptr(team_id).to_str() {
-            Ok(s) => s,
-            Err(_) => {
-                error::set_last_error(Error::invalid_utf8());
-                return std::ptr::null_mut();
-            }
-        }
-    };
-
-    let channel_name_str = {
-        match std::ffi::CStr::from_ptr(channel_name).to_str() {
+    let permalink_str = {
+        match std::ffi::CStr::from_ptr(permalink).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -3014,8 +4025,11 @@ pub unsafe extern "C" fn communicator_platform_get_channel_by_name(
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_channel_by_name(team_id_str, channel_name_str)) {
-        Ok(channel) => match serde_json::to_string(&channel) {
+    match runtime::block_on(platform.resolve_permalink(permalink_str)) {
+        Ok((message, channel)) => match serde_json::to_string(&serde_json::json!({
+            "message": message,
+            "channel": channel
+        })) {
             Ok(json) => match CString::new(json) {
                 Ok(c_string) => c_string.into_raw(),
                 Err(_) => {
@@ -3029,7 +4043,7 @@ pub unsafe extern "C" fn communicator_platform_get_channel_by_name(
             Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    format!("Failed to serialize channel: {e}"),
+                    format!("Failed to serialize permalink target: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -3041,9 +4055,9 @@ pub unsafe extern "C" fn communicator_platform_get_channel_by_name(
     }
 }
 
-/// FFI function: Create a group direct message channel
-/// user_ids_json: JSON array of user IDs, e.g. ["user1", "user2", "user3"]
-/// Returns a JSON string representing the created Channel
+/// FFI function: Get the preview metadata of a web page
+///
+/// Returns a JSON LinkMetadata object
 /// The caller must free the returned string using communicator_free_string()
 /// Returns NULL on error
 #[no_mangle]
@@ -3051,19 +4065,19 @@ pub unsafe extern "C" fn communicator_platform_get_channel_by_name(
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_create_group_channel(
+pub unsafe extern "C" fn communicator_platform_get_link_metadata(
     handle: PlatformHandle,
-    user_ids_json: *const c_char,
+    url: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || user_ids_json.is_null() {
+    if handle.is_null() || url.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let user_ids_str = {
-        match std::ffi::CStr::from_ptr(user_ids_json).to_str() {
+    let url_str = {
+        match std::ffi::CStr::from_ptr(url).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -3072,22 +4086,10 @@ pub unsafe extern "C" fn communicator_platform_create_group_channel(
         }
     };
 
-    // Parse JSON array of user IDs
-    let user_ids: Vec<String> = match serde_json::from_str(user_ids_str) {
-        Ok(ids) => ids,
-        Err(e) => {
-            error::set_last_error(Error::new(
-                ErrorCode::InvalidArgument,
-                format!("Invalid user IDs JSON: {e}"),
-            ));
-            return std::ptr::null_mut();
-        }
-    };
-
     let platform = &**handle;
 
-    match runtime::block_on(platform.create_group_channel(user_ids)) {
-        Ok(channel) => match serde_json::to_string(&channel) {
+    match runtime::block_on(platform.get_link_metadata(url_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
             Ok(json) => match CString::new(json) {
                 Ok(c_string) => c_string.into_raw(),
                 Err(_) => {
@@ -3101,7 +4103,7 @@ pub unsafe extern "C" fn communicator_platform_create_group_channel(
             Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    format!("Failed to serialize channel: {e}"),
+                    format!("Failed to serialize link metadata: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -3113,110 +4115,56 @@ pub unsafe extern "C" fn communicator_platform_create_group_channel(
     }
 }
 
-/// FFI function: Add a user to a channel
-/// Returns ErrorCode indicating success or failure
+/// FFI function: Get a list of custom emojis
+/// Returns a JSON string representing a Vec<Emoji>
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_add_channel_member(
+pub unsafe extern "C" fn communicator_platform_get_emojis(
     handle: PlatformHandle,
-    channel_id: *const c_char,
-    user_id: *const c_char,
-) -> ErrorCode {
+    page: u32,
+    per_page: u32,
+) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || channel_id.is_null() || user_id.is_null() {
+    if handle.is_null() {
         error::set_last_error(Error::null_pointer());
-        return ErrorCode::NullPointer;
+        return std::ptr::null_mut();
     }
 
-    let channel_id_str = {
-        match std::ffi::CStr::from_ptr(channel_id).to_str() {
-            Ok(s) => s,
-            Err(_) => {
-                error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_emojis(page, per_page)) {
+        Ok(emojis) => match serde_json::to_string(&emojis) {
+            Ok(json_str) => match CString::new(json_str) {
+                Ok(c_str) => c_str.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::invalid_utf8());
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize emojis: {e}"),
+                ));
+                std::ptr::null_mut()
             }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
         }
-    };
+    }
+}
 
-    let user_id_str = {
-        match std::ffi::CStr::from_ptr(user_id).to_str() {
-            Ok(s) => s,
-            Err(_) => {
-                error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
-            }
-        }
-    };
-
-    let platform = &**handle;
-
-    match runtime::block_on(platform.add_channel_member(channel_id_str, user_id_str)) {
-        Ok(()) => ErrorCode::Success,
-        Err(e) => {
-            let code = e.code;
-            error::set_last_error(e);
-            code
-        }
-    }
-}
-
-/// FFI function: Remove a user from a channel
-/// Returns ErrorCode indicating success or failure
-#[no_mangle]
+/// Get a custom emoji by name
 ///
-/// # Safety
-/// This function is unsafe because it deals with raw pointers from C.
-/// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_remove_channel_member(
-    handle: PlatformHandle,
-    channel_id: *const c_char,
-    user_id: *const c_char,
-) -> ErrorCode {
-    error::clear_last_error();
-
-    if handle.is_null() || channel_id.is_null() || user_id.is_null() {
-        error::set_last_error(Error::null_pointer());
-        return ErrorCode::NullPointer;
-    }
-
-    let channel_id_str = {
-        match std::ffi::CStr::from_ptr(channel_id).to_str() {
-            Ok(s) => s,
-            Err(_) => {
-                error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
-            }
-        }
-    };
-
-    let user_id_str = {
-        match std::ffi::CStr::from_ptr(user_id).to_str() {
-            Ok(s) => s,
-            Err(_) => {
-                error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
-            }
-        }
-    };
-
-    let platform = &**handle;
-
-    match runtime::block_on(platform.remove_channel_member(channel_id_str, user_id_str)) {
-        Ok(()) => ErrorCode::Success,
-        Err(e) => {
-            let code = e.code;
-            error::set_last_error(e);
-            code
-        }
-    }
-}
-
-/// FFI function: Get a user by username
-/// Returns a JSON string representing the User
+/// Returns the emoji as a JSON object
 /// The caller must free the returned string using communicator_free_string()
 /// Returns NULL on error
 #[no_mangle]
@@ -3224,19 +4172,19 @@ pub unsafe extern "C" fn communicator_platform_remove_channel_member(
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_get_user_by_username(
+pub unsafe extern "C" fn communicator_platform_get_emoji_by_name(
     handle: PlatformHandle,
-    username: *const c_char,
+    name: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || username.is_null() {
+    if handle.is_null() || name.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let username_str = {
-        match std::ffi::CStr::from_ptr(username).to_str() {
+    let name_str = {
+        match std::ffi::CStr::from_ptr(name).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -3247,8 +4195,8 @@ pub unsafe extern "C" fn communicator_platform_get_user_by_username(
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_user_by_username(username_str)) {
-        Ok(user) => match serde_json::to_string(&user) {
+    match runtime::block_on(platform.get_emoji_by_name(name_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
             Ok(json) => match CString::new(json) {
                 Ok(c_string) => c_string.into_raw(),
                 Err(_) => {
@@ -3262,7 +4210,7 @@ pub unsafe extern "C" fn communicator_platform_get_user_by_username(
             Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    format!("Failed to serialize user: {e}"),
+                    format!("Failed to serialize emoji: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -3274,8 +4222,9 @@ pub unsafe extern "C" fn communicator_platform_get_user_by_username(
     }
 }
 
-/// FFI function: Get a user by email
-/// Returns a JSON string representing the User
+/// Suggest custom emojis whose names start with a prefix
+///
+/// Returns a JSON array of emojis
 /// The caller must free the returned string using communicator_free_string()
 /// Returns NULL on error
 #[no_mangle]
@@ -3283,19 +4232,19 @@ pub unsafe extern "C" fn communicator_platform_get_user_by_username(
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_get_user_by_email(
+pub unsafe extern "C" fn communicator_platform_autocomplete_emoji(
     handle: PlatformHandle,
-    email: *const c_char,
+    prefix: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || email.is_null() {
+    if handle.is_null() || prefix.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let email_str = {
-        match std::ffi::CStr::from_ptr(email).to_str() {
+    let prefix_str = {
+        match std::ffi::CStr::from_ptr(prefix).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -3306,8 +4255,8 @@ pub unsafe extern "C" fn communicator_platform_get_user_by_email(
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_user_by_email(email_str)) {
-        Ok(user) => match serde_json::to_string(&user) {
+    match runtime::block_on(platform.autocomplete_emoji(prefix_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
             Ok(json) => match CString::new(json) {
                 Ok(c_string) => c_string.into_raw(),
                 Err(_) => {
@@ -3321,7 +4270,7 @@ pub unsafe extern "C" fn communicator_platform_get_user_by_email(
             Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    format!("Failed to serialize user: {e}"),
+                    format!("Failed to serialize emojis: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -3333,9 +4282,8 @@ pub unsafe extern "C" fn communicator_platform_get_user_by_email(
     }
 }
 
-/// FFI function: Get multiple users by their IDs (batch operation)
-/// user_ids_json: JSON array of user IDs, e.g. ["user1", "user2", "user3"]
-/// Returns a JSON array string of User objects
+/// FFI function: Create a custom emoji from an image file
+/// Returns a JSON string representing the created Emoji
 /// The caller must free the returned string using communicator_free_string()
 /// Returns NULL on error
 #[no_mangle]
@@ -3343,19 +4291,20 @@ pub unsafe extern "C" fn communicator_platform_get_user_by_email(
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_get_users_by_ids(
+pub unsafe extern "C" fn communicator_platform_create_emoji(
     handle: PlatformHandle,
-    user_ids_json: *const c_char,
+    name: *const c_char,
+    image_path: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || user_ids_json.is_null() {
+    if handle.is_null() || name.is_null() || image_path.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let user_ids_str = {
-        match std::ffi::CStr::from_ptr(user_ids_json).to_str() {
+    let name_str = {
+        match std::ffi::CStr::from_ptr(name).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -3364,22 +4313,20 @@ pub unsafe extern "C" fn communicator_platform_get_users_by_ids(
         }
     };
 
-    // Parse JSON array of user IDs
-    let user_ids: Vec<String> = match serde_json::from_str(user_ids_str) {
-        Ok(ids) => ids,
-        Err(e) => {
-            error::set_last_error(Error::new(
-                ErrorCode::InvalidArgument,
-                format!("Invalid user IDs JSON: {e}"),
-            ));
-            return std::ptr::null_mut();
+    let image_path_str = {
+        match std::ffi::CStr::from_ptr(image_path).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
         }
     };
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_users_by_ids(user_ids)) {
-        Ok(users) => match serde_json::to_string(&users) {
+    match runtime::block_on(platform.create_emoji(name_str, std::path::Path::new(image_path_str))) {
+        Ok(result) => match serde_json::to_string(&result) {
             Ok(json) => match CString::new(json) {
                 Ok(c_string) => c_string.into_raw(),
                 Err(_) => {
@@ -3393,7 +4340,7 @@ pub unsafe extern "C" fn communicator_platform_get_users_by_ids(
             Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    format!("Failed to serialize users: {e}"),
+                    format!("Failed to serialize emoji: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -3405,32 +4352,26 @@ pub unsafe extern "C" fn communicator_platform_get_users_by_ids(
     }
 }
 
-/// FFI function: Set a custom status message
-/// custom_status_json: JSON object with format:
-/// {
-///   "emoji": "optional-emoji",
-///   "text": "status text",
-///   "expires_at": 1234567890  // Optional Unix timestamp
-/// }
+/// FFI function: Delete a custom emoji
 /// Returns ErrorCode indicating success or failure
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_set_custom_status(
+pub unsafe extern "C" fn communicator_platform_delete_emoji(
     handle: PlatformHandle,
-    custom_status_json: *const c_char,
+    emoji_id: *const c_char,
 ) -> ErrorCode {
     error::clear_last_error();
 
-    if handle.is_null() || custom_status_json.is_null() {
+    if handle.is_null() || emoji_id.is_null() {
         error::set_last_error(Error::null_pointer());
         return ErrorCode::NullPointer;
     }
 
-    let status_str = {
-        match std::ffi::CStr::from_ptr(custom_status_json).to_str() {
+    let emoji_id_str = {
+        match std::ffi::CStr::from_ptr(emoji_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -3439,32 +4380,9 @@ pub unsafe extern "C" fn communicator_platform_set_custom_status(
         }
     };
 
-    // Parse custom status JSON
-    #[derive(serde::Deserialize)]
-    struct CustomStatusJson {
-        emoji: Option<String>,
-        text: String,
-        expires_at: Option<i64>,
-    }
-
-    let status_data: CustomStatusJson = match serde_json::from_str(status_str) {
-        Ok(s) => s,
-        Err(e) => {
-            error::set_last_error(Error::new(
-                ErrorCode::InvalidArgument,
-                format!("Invalid custom status JSON: {e}"),
-            ));
-            return ErrorCode::InvalidArgument;
-        }
-    };
-
     let platform = &**handle;
 
-    match runtime::block_on(platform.set_custom_status(
-        status_data.emoji.as_deref(),
-        &status_data.text,
-        status_data.expires_at,
-    )) {
+    match runtime::block_on(platform.delete_emoji(emoji_id_str)) {
         Ok(()) => ErrorCode::Success,
         Err(e) => {
             let code = e.code;
@@ -3474,27 +4392,54 @@ pub unsafe extern "C" fn communicator_platform_set_custom_status(
     }
 }
 
-/// FFI function: Remove/clear the current user's custom status
+/// FFI function: Download the image of a custom emoji
 /// Returns ErrorCode indicating success or failure
+///
+/// # Arguments
+/// * `handle` - The platform handle
+/// * `emoji_id` - The ID of the emoji
+/// * `out_data` - Output parameter for the image data (caller must free with communicator_free_file_data)
+/// * `out_size` - Output parameter for the size of the image data in bytes
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_remove_custom_status(
+pub unsafe extern "C" fn communicator_platform_get_emoji_image(
     handle: PlatformHandle,
+    emoji_id: *const c_char,
+    out_data: *mut *mut u8,
+    out_size: *mut usize,
 ) -> ErrorCode {
     error::clear_last_error();
 
-    if handle.is_null() {
+    if handle.is_null() || emoji_id.is_null() || out_data.is_null() || out_size.is_null() {
         error::set_last_error(Error::null_pointer());
         return ErrorCode::NullPointer;
     }
 
+    let emoji_id_str = {
+        match std::ffi::CStr::from_ptr(emoji_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
     let platform = &**handle;
 
-    match runtime::block_on(platform.remove_custom_status()) {
-        Ok(()) => ErrorCode::Success,
+    match runtime::block_on(platform.get_emoji_image(emoji_id_str)) {
+        Ok(data) => {
+            let size = data.len();
+            let boxed_data = data.into_boxed_slice();
+            let raw_ptr = Box::into_raw(boxed_data) as *mut u8;
+
+            *out_data = raw_ptr;
+            *out_size = size;
+            ErrorCode::Success
+        }
         Err(e) => {
             let code = e.code;
             error::set_last_error(e);
@@ -3503,9 +4448,8 @@ pub unsafe extern "C" fn communicator_platform_remove_custom_status(
     }
 }
 
-/// FFI function: Get status for multiple users (batch operation)
-/// user_ids_json: JSON array of user IDs, e.g. ["user1", "user2", "user3"]
-/// Returns a JSON object mapping user IDs to status strings: {"user1": "online", "user2": "away", ...}
+/// FFI function: Get a channel by name
+/// Returns a JSON string representing the Channel
 /// The caller must free the returned string using communicator_free_string()
 /// Returns NULL on error
 #[no_mangle]
@@ -3513,19 +4457,20 @@ pub unsafe extern "C" fn communicator_platform_remove_custom_status(
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_get_users_status(
+pub unsafe extern "C" fn communicator_platform_get_channel_by_name(
     handle: PlatformHandle,
-    user_ids_json: *const c_char,
+    team_id: *const c_char,
+    channel_name: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || user_ids_json.is_null() {
+    if handle.is_null() || team_id.is_null() || channel_name.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let user_ids_str = {
-        match std::ffi::CStr::from_ptr(user_ids_json).to_str() {
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -3534,57 +4479,38 @@ pub unsafe extern "C" fn communicator_platform_get_users_status(
         }
     };
 
-    // Parse JSON array of user IDs
-    let user_ids: Vec<String> = match serde_json::from_str(user_ids_str) {
-        Ok(ids) => ids,
-        Err(e) => {
-            error::set_last_error(Error::new(
-                ErrorCode::InvalidArgument,
-                format!("Invalid user IDs JSON: {e}"),
-            ));
-            return std::ptr::null_mut();
+    let channel_name_str = {
+        match std::ffi::CStr::from_ptr(channel_name).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
         }
     };
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_users_status(user_ids)) {
-        Ok(status_map) => {
-            // Convert UserStatus enum to strings
-            let status_strings: std::collections::HashMap<String, String> = status_map
-                .into_iter()
-                .map(|(id, status)| {
-                    let status_str = match status {
-                        crate::types::user::UserStatus::Online => "online",
-                        crate::types::user::UserStatus::Away => "away",
-                        crate::types::user::UserStatus::DoNotDisturb => "dnd",
-                        crate::types::user::UserStatus::Offline => "offline",
-                        crate::types::user::UserStatus::Unknown => "unknown",
-                    };
-                    (id, status_str.to_string())
-                })
-                .collect();
-
-            match serde_json::to_string(&status_strings) {
-                Ok(json) => match CString::new(json) {
-                    Ok(c_string) => c_string.into_raw(),
-                    Err(_) => {
-                        error::set_last_error(Error::new(
-                            ErrorCode::OutOfMemory,
-                            "Failed to allocate string",
-                        ));
-                        std::ptr::null_mut()
-                    }
-                },
-                Err(e) => {
+    match runtime::block_on(platform.get_channel_by_name(team_id_str, channel_name_str)) {
+        Ok(channel) => match serde_json::to_string(&channel) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
                     error::set_last_error(Error::new(
-                        ErrorCode::Unknown,
-                        format!("Failed to serialize status map: {e}"),
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
                     ));
                     std::ptr::null_mut()
                 }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize channel: {e}"),
+                ));
+                std::ptr::null_mut()
             }
-        }
+        },
         Err(e) => {
             error::set_last_error(e);
             std::ptr::null_mut()
@@ -3592,41 +4518,42 @@ pub unsafe extern "C" fn communicator_platform_get_users_status(
     }
 }
 
-/// FFI function: Get a team by name
-/// Returns a JSON string representing the Team
+/// FFI function: Get a page of a team's public channels
+/// Returns a JSON string representing a Vec<Channel>
 /// The caller must free the returned string using communicator_free_string()
 /// Returns NULL on error
-///
-/// # Safety
-/// The caller must ensure that `handle` and `team_name` are valid pointers
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_get_team_by_name(
+pub unsafe extern "C" fn communicator_platform_get_public_channels(
     handle: PlatformHandle,
-    team_name: *const c_char,
+    team_id: *const c_char,
+    page: u32,
+    per_page: u32,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || team_name.is_null() {
+    if handle.is_null() || team_id.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let team_name_str = match std::ffi::CStr::from_ptr(team_name).to_str() {
-        Ok(s) => s,
-        Err(_) => {
-            error::set_last_error(Error::invalid_utf8());
-            return std::ptr::null_mut();
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
         }
     };
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_team_by_name(team_name_str)) {
-        Ok(team) => match serde_json::to_string(&team) {
+    match runtime::block_on(platform.get_public_channels(team_id_str, page, per_page)) {
+        Ok(result) => match serde_json::to_string(&result) {
             Ok(json) => match CString::new(json) {
                 Ok(c_string) => c_string.into_raw(),
                 Err(_) => {
@@ -3640,7 +4567,7 @@ pub unsafe extern "C" fn communicator_platform_get_team_by_name(
             Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    format!("Failed to serialize team: {e}"),
+                    format!("Failed to serialize channels: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -3652,87 +4579,90 @@ pub unsafe extern "C" fn communicator_platform_get_team_by_name(
     }
 }
 
-/// FFI function: Set the active team/workspace ID
-/// team_id: The team ID to set as active (pass NULL to unset)
-/// Returns ErrorCode indicating success or failure
-///
-/// # Safety
-/// The caller must ensure that `handle` is a valid pointer.
-/// If `team_id` is not NULL, it must be a valid C string pointer.
+/// FFI function: Get a page of a team's archived channels
+/// Returns a JSON string representing a Vec<Channel>
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_set_team_id(
+pub unsafe extern "C" fn communicator_platform_get_archived_channels(
     handle: PlatformHandle,
     team_id: *const c_char,
-) -> ErrorCode {
+    page: u32,
+    per_page: u32,
+) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() {
+    if handle.is_null() || team_id.is_null() {
         error::set_last_error(Error::null_pointer());
-        return ErrorCode::NullPointer;
+        return std::ptr::null_mut();
     }
 
-    // team_id can be NULL (to unset the team ID)
-    let team_id_opt = if team_id.is_null() {
-        None
-    } else {
-        let team_id_str = match std::ffi::CStr::from_ptr(team_id).to_str() {
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
+                return std::ptr::null_mut();
             }
-        };
-        Some(team_id_str.to_string())
+        }
     };
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.set_team_id(team_id_opt)) {
-        Ok(()) => ErrorCode::Success,
+    match runtime::block_on(platform.get_archived_channels(team_id_str, page, per_page)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize channels: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
         Err(e) => {
-            let code = e.code;
             error::set_last_error(e);
-            code
+            std::ptr::null_mut()
         }
     }
 }
 
-// ============================================================================
-// File Operations FFI Functions
-// ============================================================================
-
-/// FFI function: Upload a file to a channel
-/// Returns a dynamically allocated string containing the file ID
+/// FFI function: Create a group direct message channel
+/// user_ids_json: JSON array of user IDs, e.g. ["user1", "user2", "user3"]
+/// Returns a JSON string representing the created Channel
 /// The caller must free the returned string using communicator_free_string()
 /// Returns NULL on error
-///
-/// # Arguments
-/// * `handle` - The platform handle
-/// * `channel_id` - The channel ID where the file will be uploaded
-/// * `file_path` - Path to the file to upload
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_upload_file(
+pub unsafe extern "C" fn communicator_platform_create_group_channel(
     handle: PlatformHandle,
-    channel_id: *const c_char,
-    file_path: *const c_char,
+    user_ids_json: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || channel_id.is_null() || file_path.is_null() {
+    if handle.is_null() || user_ids_json.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let channel_id_str = {
-        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+    let user_ids_str = {
+        match std::ffi::CStr::from_ptr(user_ids_json).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -3741,26 +4671,36 @@ pub unsafe extern "C" fn communicator_platform_upload_file(
         }
     };
 
-    let file_path_str = {
-        match std::ffi::CStr::from_ptr(file_path).to_str() {
-            Ok(s) => s,
-            Err(_) => {
-                error::set_last_error(Error::invalid_utf8());
-                return std::ptr::null_mut();
-            }
+    // Parse JSON array of user IDs
+    let user_ids: Vec<String> = match serde_json::from_str(user_ids_str) {
+        Ok(ids) => ids,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid user IDs JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
         }
     };
 
     let platform = &**handle;
-    let path = std::path::Path::new(file_path_str);
 
-    match runtime::block_on(platform.upload_file(channel_id_str, path)) {
-        Ok(file_id) => match CString::new(file_id) {
-            Ok(c_string) => c_string.into_raw(),
-            Err(_) => {
+    match runtime::block_on(platform.create_group_channel(user_ids)) {
+        Ok(channel) => match serde_json::to_string(&channel) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    "Failed to convert file ID to C string",
+                    format!("Failed to serialize channel: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -3772,90 +4712,88 @@ pub unsafe extern "C" fn communicator_platform_upload_file(
     }
 }
 
-/// FFI function: Download a file by its ID
-/// The file data is returned through the out_data and out_size parameters
-/// The caller must free the returned data using communicator_free_file_data()
-/// Returns ErrorCode indicating success or failure
-///
-/// # Arguments
-/// * `handle` - The platform handle
-/// * `file_id` - The ID of the file to download
-/// * `out_data` - Output parameter for the file data (caller must free with communicator_free_file_data)
-/// * `out_size` - Output parameter for the size of the file data in bytes
+/// FFI function: Find an existing direct message channel with a user
+/// Returns a JSON string representing the Channel, or "null" if no DM exists yet
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_download_file(
+pub unsafe extern "C" fn communicator_platform_find_direct_channel(
     handle: PlatformHandle,
-    file_id: *const c_char,
-    out_data: *mut *mut u8,
-    out_size: *mut usize,
-) -> ErrorCode {
+    user_id: *const c_char,
+) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || file_id.is_null() || out_data.is_null() || out_size.is_null() {
+    if handle.is_null() || user_id.is_null() {
         error::set_last_error(Error::null_pointer());
-        return ErrorCode::NullPointer;
+        return std::ptr::null_mut();
     }
 
-    let file_id_str = {
-        match std::ffi::CStr::from_ptr(file_id).to_str() {
+    let user_id_str = {
+        match std::ffi::CStr::from_ptr(user_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
+                return std::ptr::null_mut();
             }
         }
     };
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.download_file(file_id_str)) {
-        Ok(data) => {
-            let size = data.len();
-            let boxed_data = data.into_boxed_slice();
-            let raw_ptr = Box::into_raw(boxed_data) as *mut u8;
-
-            *out_data = raw_ptr;
-            *out_size = size;
-            ErrorCode::Success
-        }
+    match runtime::block_on(platform.find_direct_channel(user_id_str)) {
+        Ok(channel) => match serde_json::to_string(&channel) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize channel: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
         Err(e) => {
-            let code = e.code;
             error::set_last_error(e);
-            code
+            std::ptr::null_mut()
         }
     }
 }
 
-/// FFI function: Get file metadata without downloading the file
-/// Returns a JSON string representing the Attachment metadata
+/// FFI function: Find an existing group message channel for a set of users
+/// user_ids_json: JSON array of user IDs, e.g. ["user1", "user2"]
+/// Returns a JSON string representing the Channel, or "null" if no such group exists yet
 /// The caller must free the returned string using communicator_free_string()
 /// Returns NULL on error
-///
-/// # Arguments
-/// * `handle` - The platform handle
-/// * `file_id` - The ID of the file
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_get_file_metadata(
+pub unsafe extern "C" fn communicator_platform_find_group_channel(
     handle: PlatformHandle,
-    file_id: *const c_char,
+    user_ids_json: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || file_id.is_null() {
+    if handle.is_null() || user_ids_json.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let file_id_str = {
-        match std::ffi::CStr::from_ptr(file_id).to_str() {
+    let user_ids_str = {
+        match std::ffi::CStr::from_ptr(user_ids_json).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -3864,16 +4802,28 @@ pub unsafe extern "C" fn communicator_platform_get_file_metadata(
         }
     };
 
+    // Parse JSON array of user IDs
+    let user_ids: Vec<String> = match serde_json::from_str(user_ids_str) {
+        Ok(ids) => ids,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid user IDs JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_file_metadata(file_id_str)) {
-        Ok(attachment) => match serde_json::to_string(&attachment) {
+    match runtime::block_on(platform.find_group_channel(user_ids)) {
+        Ok(channel) => match serde_json::to_string(&channel) {
             Ok(json) => match CString::new(json) {
                 Ok(c_string) => c_string.into_raw(),
                 Err(_) => {
                     error::set_last_error(Error::new(
-                        ErrorCode::Unknown,
-                        "Failed to convert metadata to C string",
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
                     ));
                     std::ptr::null_mut()
                 }
@@ -3881,7 +4831,7 @@ pub unsafe extern "C" fn communicator_platform_get_file_metadata(
             Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    format!("Failed to serialize metadata: {e}"),
+                    format!("Failed to serialize channel: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -3893,104 +4843,4394 @@ pub unsafe extern "C" fn communicator_platform_get_file_metadata(
     }
 }
 
-/// FFI function: Get file thumbnail
-/// The thumbnail data is returned through the out_data and out_size parameters
-/// The caller must free the returned data using communicator_free_file_data()
-/// Returns ErrorCode indicating success or failure
-///
+/// FFI function: Change who takes part in a group message
+/// add_user_ids_json, remove_user_ids_json: JSON arrays of user IDs
+/// Returns a JSON string representing the conversation with the new participants,
+/// which on Mattermost is a different channel: participants there are fixed
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_update_group_participants(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+    add_user_ids_json: *const c_char,
+    remove_user_ids_json: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null()
+        || channel_id.is_null()
+        || add_user_ids_json.is_null()
+        || remove_user_ids_json.is_null()
+    {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let channel_id_str = match std::ffi::CStr::from_ptr(channel_id).to_str() {
+        Ok(s) => s,
+        Err(_) => {
+            error::set_last_error(Error::invalid_utf8());
+            return std::ptr::null_mut();
+        }
+    };
+
+    // Parse the JSON arrays of user IDs
+    let mut user_id_lists = Vec::with_capacity(2);
+    for json in [add_user_ids_json, remove_user_ids_json] {
+        let json_str = match std::ffi::CStr::from_ptr(json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        };
+        match serde_json::from_str::<Vec<String>>(json_str) {
+            Ok(ids) => user_id_lists.push(ids),
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::InvalidArgument,
+                    format!("Invalid user IDs JSON: {e}"),
+                ));
+                return std::ptr::null_mut();
+            }
+        }
+    }
+    let remove_user_ids = user_id_lists.pop().unwrap_or_default();
+    let add_user_ids = user_id_lists.pop().unwrap_or_default();
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.update_group_participants(
+        channel_id_str,
+        add_user_ids,
+        remove_user_ids,
+    )) {
+        Ok(channel) => match serde_json::to_string(&channel) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize channel: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Get a user's effective permissions in a channel
+/// Returns a JSON array of permission names
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_effective_permissions(
+    handle: PlatformHandle,
+    user_id: *const c_char,
+    channel_id: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || user_id.is_null() || channel_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let user_id_str = {
+        match std::ffi::CStr::from_ptr(user_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_effective_permissions(user_id_str, channel_id_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize permissions: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Check whether a user holds a permission in a channel
+/// Returns 1 if the permission is held, 0 if not, -1 on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_has_permission(
+    handle: PlatformHandle,
+    user_id: *const c_char,
+    channel_id: *const c_char,
+    permission: *const c_char,
+) -> i32 {
+    error::clear_last_error();
+
+    if handle.is_null() || user_id.is_null() || channel_id.is_null() || permission.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return -1;
+    }
+
+    let (user_id_str, channel_id_str, permission_str) = match (
+        std::ffi::CStr::from_ptr(user_id).to_str(),
+        std::ffi::CStr::from_ptr(channel_id).to_str(),
+        std::ffi::CStr::from_ptr(permission).to_str(),
+    ) {
+        (Ok(u), Ok(c), Ok(p)) => (u, c, p),
+        _ => {
+            error::set_last_error(Error::invalid_utf8());
+            return -1;
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.has_permission(user_id_str, channel_id_str, permission_str)) {
+        Ok(true) => 1,
+        Ok(false) => 0,
+        Err(e) => {
+            error::set_last_error(e);
+            -1
+        }
+    }
+}
+
+/// FFI function: Add a user to a channel
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_add_channel_member(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+    user_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() || user_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let user_id_str = {
+        match std::ffi::CStr::from_ptr(user_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.add_channel_member(channel_id_str, user_id_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Remove a user from a channel
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_remove_channel_member(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+    user_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() || user_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let user_id_str = {
+        match std::ffi::CStr::from_ptr(user_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.remove_channel_member(channel_id_str, user_id_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Join a channel as the current user
+/// Returns a JSON string representing the joined Channel
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_join_channel(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.join_channel(channel_id_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize channel: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Join a channel by name within a team as the current user
+/// Returns a JSON string representing the joined Channel
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_join_channel_by_name(
+    handle: PlatformHandle,
+    team_id: *const c_char,
+    channel_name: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || team_id.is_null() || channel_name.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let channel_name_str = {
+        match std::ffi::CStr::from_ptr(channel_name).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.join_channel_by_name(team_id_str, channel_name_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize channel: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Leave a channel as the current user
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_leave_channel(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.leave_channel(channel_id_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Replace a channel member's explicit roles
+///
+/// # Arguments
+/// * `handle` - The platform handle
+/// * `channel_id` - The channel ID
+/// * `user_id` - The member's user ID
+/// * `roles_json` - JSON array of role names, e.g. ["channel_user", "channel_admin"]
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_update_channel_member_roles(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+    user_id: *const c_char,
+    roles_json: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() || user_id.is_null() || roles_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let user_id_str = {
+        match std::ffi::CStr::from_ptr(user_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let roles_json_str = {
+        match std::ffi::CStr::from_ptr(roles_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let roles: Vec<String> = match serde_json::from_str(roles_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid roles JSON: {e}"),
+            ));
+            return ErrorCode::InvalidArgument;
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.update_channel_member_roles(
+        channel_id_str,
+        user_id_str,
+        &roles,
+    )) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Set whether a channel member is a channel admin and/or a regular user
+///
+/// # Arguments
+/// * `handle` - The platform handle
+/// * `channel_id` - The channel ID
+/// * `user_id` - The member's user ID
+/// * `scheme_admin` - Non-zero to grant the channel admin role
+/// * `scheme_user` - Non-zero to grant the channel user role
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_set_channel_member_scheme_roles(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+    user_id: *const c_char,
+    scheme_admin: i32,
+    scheme_user: i32,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() || user_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let user_id_str = {
+        match std::ffi::CStr::from_ptr(user_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.set_channel_member_scheme_roles(
+        channel_id_str,
+        user_id_str,
+        scheme_admin != 0,
+        scheme_user != 0,
+    )) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Synchronize a channel's membership with a desired list of users
+/// desired_user_ids_json: JSON array of user IDs that should be members
+/// options_json: JSON object, e.g. {"dry_run": true, "add_only": false, "protected_user_ids": []}
+/// Returns a JSON string representing the MemberSyncResult
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_sync_channel_members(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+    desired_user_ids_json: *const c_char,
+    options_json: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null()
+        || channel_id.is_null()
+        || desired_user_ids_json.is_null()
+        || options_json.is_null()
+    {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let desired_user_ids_json_str = {
+        match std::ffi::CStr::from_ptr(desired_user_ids_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let desired_user_ids: Vec<String> = match serde_json::from_str(desired_user_ids_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid user IDs JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    let options_json_str = {
+        match std::ffi::CStr::from_ptr(options_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let options: types::MemberSyncOptions = match serde_json::from_str(options_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid sync options JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.sync_channel_members(
+        channel_id_str,
+        desired_user_ids,
+        options,
+    )) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize sync result: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Get a user by username
+/// Returns a JSON string representing the User
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_user_by_username(
+    handle: PlatformHandle,
+    username: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || username.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let username_str = {
+        match std::ffi::CStr::from_ptr(username).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_user_by_username(username_str)) {
+        Ok(user) => match serde_json::to_string(&user) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize user: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Get a user by email
+/// Returns a JSON string representing the User
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_user_by_email(
+    handle: PlatformHandle,
+    email: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || email.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let email_str = {
+        match std::ffi::CStr::from_ptr(email).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_user_by_email(email_str)) {
+        Ok(user) => match serde_json::to_string(&user) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize user: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Get multiple users by their IDs (batch operation)
+/// user_ids_json: JSON array of user IDs, e.g. ["user1", "user2", "user3"]
+/// Returns a JSON array string of User objects
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_users_by_ids(
+    handle: PlatformHandle,
+    user_ids_json: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || user_ids_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let user_ids_str = {
+        match std::ffi::CStr::from_ptr(user_ids_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    // Parse JSON array of user IDs
+    let user_ids: Vec<String> = match serde_json::from_str(user_ids_str) {
+        Ok(ids) => ids,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid user IDs JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_users_by_ids(user_ids)) {
+        Ok(users) => match serde_json::to_string(&users) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize users: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Set a custom status message
+/// custom_status_json: JSON object with format:
+/// {
+///   "emoji": "optional-emoji",
+///   "text": "status text",
+///   "expires_at": 1234567890  // Optional Unix timestamp
+/// }
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_set_custom_status(
+    handle: PlatformHandle,
+    custom_status_json: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || custom_status_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let status_str = {
+        match std::ffi::CStr::from_ptr(custom_status_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    // Parse custom status JSON
+    #[derive(serde::Deserialize)]
+    struct CustomStatusJson {
+        emoji: Option<String>,
+        text: String,
+        expires_at: Option<i64>,
+    }
+
+    let status_data: CustomStatusJson = match serde_json::from_str(status_str) {
+        Ok(s) => s,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid custom status JSON: {e}"),
+            ));
+            return ErrorCode::InvalidArgument;
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.set_custom_status(
+        status_data.emoji.as_deref(),
+        &status_data.text,
+        status_data.expires_at,
+    )) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Remove/clear the current user's custom status
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_remove_custom_status(
+    handle: PlatformHandle,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.remove_custom_status()) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Get status for multiple users (batch operation)
+/// user_ids_json: JSON array of user IDs, e.g. ["user1", "user2", "user3"]
+/// Returns a JSON object mapping user IDs to status strings: {"user1": "online", "user2": "away", ...}
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_users_status(
+    handle: PlatformHandle,
+    user_ids_json: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || user_ids_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let user_ids_str = {
+        match std::ffi::CStr::from_ptr(user_ids_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    // Parse JSON array of user IDs
+    let user_ids: Vec<String> = match serde_json::from_str(user_ids_str) {
+        Ok(ids) => ids,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid user IDs JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_users_status(user_ids)) {
+        Ok(status_map) => {
+            // Convert UserStatus enum to strings
+            let status_strings: std::collections::HashMap<String, String> = status_map
+                .into_iter()
+                .map(|(id, status)| {
+                    let status_str = match status {
+                        crate::types::user::UserStatus::Online => "online",
+                        crate::types::user::UserStatus::Away => "away",
+                        crate::types::user::UserStatus::DoNotDisturb => "dnd",
+                        crate::types::user::UserStatus::Offline => "offline",
+                        crate::types::user::UserStatus::Unknown => "unknown",
+                    };
+                    (id, status_str.to_string())
+                })
+                .collect();
+
+            match serde_json::to_string(&status_strings) {
+                Ok(json) => match CString::new(json) {
+                    Ok(c_string) => c_string.into_raw(),
+                    Err(_) => {
+                        error::set_last_error(Error::new(
+                            ErrorCode::OutOfMemory,
+                            "Failed to allocate string",
+                        ));
+                        std::ptr::null_mut()
+                    }
+                },
+                Err(e) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::Unknown,
+                        format!("Failed to serialize status map: {e}"),
+                    ));
+                    std::ptr::null_mut()
+                }
+            }
+        }
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// Get detailed status for multiple users (batch operation)
+///
+/// Takes a JSON array of user IDs and returns a JSON array of status objects
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_statuses_by_ids(
+    handle: PlatformHandle,
+    user_ids_json: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || user_ids_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let user_ids_json_str = {
+        match std::ffi::CStr::from_ptr(user_ids_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let user_ids: Vec<String> = match serde_json::from_str(user_ids_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid user IDs JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_statuses_by_ids(user_ids)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize statuses: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Get a team by name
+/// Returns a JSON string representing the Team
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+///
+/// # Safety
+/// The caller must ensure that `handle` and `team_name` are valid pointers
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_team_by_name(
+    handle: PlatformHandle,
+    team_name: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || team_name.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let team_name_str = match std::ffi::CStr::from_ptr(team_name).to_str() {
+        Ok(s) => s,
+        Err(_) => {
+            error::set_last_error(Error::invalid_utf8());
+            return std::ptr::null_mut();
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_team_by_name(team_name_str)) {
+        Ok(team) => match serde_json::to_string(&team) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize team: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// Get member counts for a team
+///
+/// Returns a JSON object with team_id, member_count and active_member_count
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_team_stats(
+    handle: PlatformHandle,
+    team_id: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || team_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_team_stats(team_id_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize team stats: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// Search the teams visible to the current user by name or display name
+///
+/// Returns a JSON array of teams
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_search_teams(
+    handle: PlatformHandle,
+    term: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || term.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let term_str = {
+        match std::ffi::CStr::from_ptr(term).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.search_teams(term_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize teams: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Set the active team/workspace ID
+/// team_id: The team ID to set as active (pass NULL to unset)
+/// Returns ErrorCode indicating success or failure
+///
+/// # Safety
+/// The caller must ensure that `handle` is a valid pointer.
+/// If `team_id` is not NULL, it must be a valid C string pointer.
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_set_team_id(
+    handle: PlatformHandle,
+    team_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    // team_id can be NULL (to unset the team ID)
+    let team_id_opt = if team_id.is_null() {
+        None
+    } else {
+        let team_id_str = match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        };
+        Some(team_id_str.to_string())
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.set_team_id(team_id_opt)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+// ============================================================================
+// File Operations FFI Functions
+// ============================================================================
+
+/// FFI function: Upload a file to a channel
+/// Returns a dynamically allocated string containing the file ID
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+///
+/// # Arguments
+/// * `handle` - The platform handle
+/// * `channel_id` - The channel ID where the file will be uploaded
+/// * `file_path` - Path to the file to upload
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_upload_file(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+    file_path: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() || file_path.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let file_path_str = {
+        match std::ffi::CStr::from_ptr(file_path).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+    let path = std::path::Path::new(file_path_str);
+
+    match runtime::block_on(platform.upload_file(channel_id_str, path)) {
+        Ok(file_id) => match CString::new(file_id) {
+            Ok(c_string) => c_string.into_raw(),
+            Err(_) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    "Failed to convert file ID to C string",
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Download a file by its ID
+/// The file data is returned through the out_data and out_size parameters
+/// The caller must free the returned data using communicator_free_file_data()
+/// Returns ErrorCode indicating success or failure
+///
+/// # Arguments
+/// * `handle` - The platform handle
+/// * `file_id` - The ID of the file to download
+/// * `out_data` - Output parameter for the file data (caller must free with communicator_free_file_data)
+/// * `out_size` - Output parameter for the size of the file data in bytes
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_download_file(
+    handle: PlatformHandle,
+    file_id: *const c_char,
+    out_data: *mut *mut u8,
+    out_size: *mut usize,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || file_id.is_null() || out_data.is_null() || out_size.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let file_id_str = {
+        match std::ffi::CStr::from_ptr(file_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.download_file(file_id_str)) {
+        Ok(data) => {
+            let size = data.len();
+            let boxed_data = data.into_boxed_slice();
+            let raw_ptr = Box::into_raw(boxed_data) as *mut u8;
+
+            *out_data = raw_ptr;
+            *out_size = size;
+            ErrorCode::Success
+        }
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Download part of a file by its ID
+/// The file data is returned through the out_data and out_size parameters
+/// The caller must free the returned data using communicator_free_file_data()
+/// Returns ErrorCode indicating success or failure
+///
+/// # Arguments
+/// * `handle` - The platform handle
+/// * `file_id` - The ID of the file to download
+/// * `offset` - Position of the first byte to download
+/// * `length` - Maximum number of bytes to download; 0 reads to the end
+/// * `out_data` - Output parameter for the file data (caller must free with communicator_free_file_data)
+/// * `out_size` - Output parameter for the size of the file data in bytes
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_download_file_range(
+    handle: PlatformHandle,
+    file_id: *const c_char,
+    offset: u64,
+    length: u64,
+    out_data: *mut *mut u8,
+    out_size: *mut usize,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || file_id.is_null() || out_data.is_null() || out_size.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let file_id_str = {
+        match std::ffi::CStr::from_ptr(file_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.download_file_range(file_id_str, offset, length)) {
+        Ok(data) => {
+            let size = data.len();
+            let boxed_data = data.into_boxed_slice();
+            let raw_ptr = Box::into_raw(boxed_data) as *mut u8;
+
+            *out_data = raw_ptr;
+            *out_size = size;
+            ErrorCode::Success
+        }
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Get file metadata without downloading the file
+/// Returns a JSON string representing the Attachment metadata
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+///
+/// # Arguments
+/// * `handle` - The platform handle
+/// * `file_id` - The ID of the file
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_file_metadata(
+    handle: PlatformHandle,
+    file_id: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || file_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let file_id_str = {
+        match std::ffi::CStr::from_ptr(file_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_file_metadata(file_id_str)) {
+        Ok(attachment) => match serde_json::to_string(&attachment) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::Unknown,
+                        "Failed to convert metadata to C string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize metadata: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Get file thumbnail
+/// The thumbnail data is returned through the out_data and out_size parameters
+/// The caller must free the returned data using communicator_free_file_data()
+/// Returns ErrorCode indicating success or failure
+///
+/// # Arguments
+/// * `handle` - The platform handle
+/// * `file_id` - The ID of the file
+/// * `out_data` - Output parameter for the thumbnail data (caller must free with communicator_free_file_data)
+/// * `out_size` - Output parameter for the size of the thumbnail data in bytes
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_file_thumbnail(
+    handle: PlatformHandle,
+    file_id: *const c_char,
+    out_data: *mut *mut u8,
+    out_size: *mut usize,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || file_id.is_null() || out_data.is_null() || out_size.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let file_id_str = {
+        match std::ffi::CStr::from_ptr(file_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_file_thumbnail(file_id_str)) {
+        Ok(data) => {
+            let size = data.len();
+            let boxed_data = data.into_boxed_slice();
+            let raw_ptr = Box::into_raw(boxed_data) as *mut u8;
+
+            *out_data = raw_ptr;
+            *out_size = size;
+            ErrorCode::Success
+        }
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Free file data allocated by download_file or get_file_thumbnail
+///
+/// # Arguments
+/// * `data` - Pointer to file data returned by communicator_platform_download_file or communicator_platform_get_file_thumbnail
+/// * `size` - Size of the data in bytes (as returned in out_size)
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure the data pointer was allocated by this library and has not been freed already.
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_free_file_data(data: *mut u8, size: usize) {
+    if !data.is_null() && size > 0 {
+        let _ = Box::from_raw(std::slice::from_raw_parts_mut(data, size));
+    }
+}
+
+/// FFI function: Get file preview (full-size image preview)
+///
+/// # Safety
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_get_file_preview(
+    handle: PlatformHandle,
+    file_id: *const c_char,
+    out_data: *mut *mut u8,
+    out_size: *mut usize,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || file_id.is_null() || out_data.is_null() || out_size.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let file_id_str = {
+        match std::ffi::CStr::from_ptr(file_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_file_preview(file_id_str)) {
+        Ok(data) => {
+            let size = data.len();
+            let boxed_data = data.into_boxed_slice();
+            let raw_ptr = Box::into_raw(boxed_data) as *mut u8;
+
+            *out_data = raw_ptr;
+            *out_size = size;
+            ErrorCode::Success
+        }
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Get a public link to a file
+///
+/// # Safety
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_get_file_link(
+    handle: PlatformHandle,
+    file_id: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || file_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let file_id_str = {
+        match std::ffi::CStr::from_ptr(file_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_file_link(file_id_str)) {
+        Ok(link) => match CString::new(link) {
+            Ok(c_string) => c_string.into_raw(),
+            Err(_) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    "Failed to convert result to C string",
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Check whether the server allows public file links
+/// Returns 1 if public links are enabled, 0 if not, -1 on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_public_links_enabled(handle: PlatformHandle) -> i32 {
+    error::clear_last_error();
+
+    if handle.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return -1;
+    }
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.public_links_enabled()) {
+        Ok(true) => 1,
+        Ok(false) => 0,
+        Err(e) => {
+            error::set_last_error(e);
+            -1
+        }
+    }
+}
+
+/// FFI function: Enable public file links server-wide (administrators only)
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_enable_public_links(
+    handle: PlatformHandle,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.enable_public_links()) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Disable public file links and invalidate every link issued so far
+/// (administrators only)
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_revoke_public_links(
+    handle: PlatformHandle,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.revoke_public_links()) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Invalidate the public link of a single file
+/// Returns ErrorCode indicating success or failure
+///
+/// # Safety
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_revoke_file_link(
+    handle: PlatformHandle,
+    file_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || file_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let file_id_str = match std::ffi::CStr::from_ptr(file_id).to_str() {
+        Ok(s) => s,
+        Err(_) => {
+            error::set_last_error(Error::invalid_utf8());
+            return ErrorCode::InvalidUtf8;
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.revoke_file_link(file_id_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+// ============================================================================
+// Thread Operations
+// ============================================================================
+
+/// FFI function: Get a thread (root post and all replies)
+/// Returns a JSON string containing an array of messages
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+/// The returned string must be freed using communicator_free_string.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_get_thread(
+    handle: PlatformHandle,
+    post_id: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || post_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let post_id_str = {
+        match std::ffi::CStr::from_ptr(post_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_thread(post_id_str)) {
+        Ok(messages) => match serde_json::to_string(&messages) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::Unknown,
+                        "Failed to create C string from thread JSON",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize thread: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Start following a thread
+/// Returns error code indicating success or failure
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_follow_thread(
+    handle: PlatformHandle,
+    thread_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || thread_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let thread_id_str = {
+        match std::ffi::CStr::from_ptr(thread_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.follow_thread(thread_id_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Stop following a thread
+/// Returns error code indicating success or failure
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_unfollow_thread(
+    handle: PlatformHandle,
+    thread_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || thread_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let thread_id_str = {
+        match std::ffi::CStr::from_ptr(thread_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.unfollow_thread(thread_id_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Mark a thread as read
+/// Returns error code indicating success or failure
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_mark_thread_read(
+    handle: PlatformHandle,
+    thread_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || thread_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let thread_id_str = {
+        match std::ffi::CStr::from_ptr(thread_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.mark_thread_read(thread_id_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Mark a thread as unread from a specific post
+/// Returns error code indicating success or failure
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_mark_thread_unread(
+    handle: PlatformHandle,
+    thread_id: *const c_char,
+    post_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || thread_id.is_null() || post_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let thread_id_str = {
+        match std::ffi::CStr::from_ptr(thread_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let post_id_str = {
+        match std::ffi::CStr::from_ptr(post_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.mark_thread_unread(thread_id_str, post_id_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Get all threads for a user in a team
+///
+/// # Safety
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_get_user_threads(
+    handle: PlatformHandle,
+    user_id: *const c_char,
+    team_id: *const c_char,
+    since: u64,
+    deleted: std::os::raw::c_int,
+    unread: std::os::raw::c_int,
+    per_page: usize,
+    page: usize,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || user_id.is_null() || team_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let user_id_str = {
+        match std::ffi::CStr::from_ptr(user_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_user_threads(
+        user_id_str,
+        team_id_str,
+        since,
+        deleted != 0,
+        unread != 0,
+        per_page,
+        page,
+    )) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize threads: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Get a specific thread for a user
+///
+/// # Safety
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_get_user_thread(
+    handle: PlatformHandle,
+    user_id: *const c_char,
+    team_id: *const c_char,
+    thread_id: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || user_id.is_null() || team_id.is_null() || thread_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let user_id_str = {
+        match std::ffi::CStr::from_ptr(user_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let thread_id_str = {
+        match std::ffi::CStr::from_ptr(thread_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_user_thread(user_id_str, team_id_str, thread_id_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize thread: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Mark all threads as read for a user in a team
+///
+/// # Safety
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_mark_all_threads_read(
+    handle: PlatformHandle,
+    user_id: *const c_char,
+    team_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || user_id.is_null() || team_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let user_id_str = {
+        match std::ffi::CStr::from_ptr(user_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.mark_all_threads_as_read(user_id_str, team_id_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Search for messages
+///
 /// # Arguments
-/// * `handle` - The platform handle
-/// * `file_id` - The ID of the file
-/// * `out_data` - Output parameter for the thumbnail data (caller must free with communicator_free_file_data)
-/// * `out_size` - Output parameter for the size of the thumbnail data in bytes
+/// * `handle` - Platform handle
+/// * `query` - Search query (supports operators like from:, in:, before:, after:)
+/// * `limit` - Maximum number of results
+///
+/// # Returns
+/// JSON array of messages on success, or null on error
+///
+/// # Safety
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_search_messages(
+    handle: PlatformHandle,
+    query: *const c_char,
+    limit: usize,
+) -> *mut c_char {
+    if handle.is_null() || query.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let query_str = {
+        match std::ffi::CStr::from_ptr(query).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.search_messages(query_str, limit)) {
+        Ok(messages) => match serde_json::to_string(&messages) {
+            Ok(json) => match std::ffi::CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::Unknown,
+                        "Failed to convert result to C string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    &format!("Failed to serialize messages: {}", e),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Get one page of message search results
+/// Returns a JSON array string of Message objects
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_search_messages_page(
+    handle: PlatformHandle,
+    query: *const c_char,
+    page: u32,
+    per_page: u32,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || query.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let query_str = {
+        match std::ffi::CStr::from_ptr(query).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.search_messages_page(query_str, page, per_page)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize messages: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// Get recent messages that mention the current user, newest first
+///
+/// Returns a JSON array of messages
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_recent_mentions(
+    handle: PlatformHandle,
+    limit: u32,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_recent_mentions(limit as usize)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize messages: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+// ============================================================================
+// Advanced Search Operations
+// ============================================================================
+
+/// FFI function: Search for users with advanced filtering
+///
+/// # Arguments
+/// * `handle` - Platform handle
+/// * `request_json` - JSON string with UserSearchRequest parameters
+///
+/// # Returns
+/// JSON array of users on success, or null on error
+///
+/// # Safety
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_search_users(
+    handle: PlatformHandle,
+    request_json: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || request_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let request_str = {
+        match std::ffi::CStr::from_ptr(request_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let request: platforms::mattermost::UserSearchRequest = match serde_json::from_str(request_str)
+    {
+        Ok(r) => r,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                &format!("Failed to parse search request: {}", e),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    let platform = &**handle;
+
+    // Extract term and limit for the simple trait method
+    let query = &request.term;
+    let limit = request.limit.unwrap_or(100) as usize;
+
+    match runtime::block_on(platform.search_users(query, limit)) {
+        Ok(users) => match serde_json::to_string(&users) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::Unknown,
+                        "Failed to convert result to C string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    &format!("Failed to serialize users: {}", e),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Autocomplete users for mentions
+///
+/// # Safety
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_autocomplete_users(
+    handle: PlatformHandle,
+    name: *const c_char,
+    team_id: *const c_char,
+    channel_id: *const c_char,
+    limit: usize,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || name.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let name_str = {
+        match std::ffi::CStr::from_ptr(name).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let _team_id_opt = if team_id.is_null() {
+        None
+    } else {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => Some(s),
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let channel_id_str = if channel_id.is_null() {
+        ""
+    } else {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    // Note: team_id is not used by the simple trait method
+    // For full advanced search support, the platform trait would need enhancement
+    match runtime::block_on(platform.autocomplete_users(channel_id_str, name_str, limit)) {
+        Ok(users) => match serde_json::to_string(&users) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::Unknown,
+                        "Failed to convert result to C string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    &format!("Failed to serialize users: {}", e),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Search for channels
+///
+/// # Safety
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_search_channels(
+    handle: PlatformHandle,
+    team_id: *const c_char,
+    term: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || team_id.is_null() || term.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let term_str = {
+        match std::ffi::CStr::from_ptr(term).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    // Note: team_id is not used by the simple trait method
+    // For full advanced search support, the platform trait would need enhancement
+    let _ = team_id_str; // Unused in simple trait method
+    match runtime::block_on(platform.search_channels(term_str, 100)) {
+        Ok(channels) => match serde_json::to_string(&channels) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::Unknown,
+                        "Failed to convert result to C string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    &format!("Failed to serialize channels: {}", e),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Autocomplete channels for references
+///
+/// # Safety
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_autocomplete_channels(
+    handle: PlatformHandle,
+    team_id: *const c_char,
+    name: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || team_id.is_null() || name.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let name_str = {
+        match std::ffi::CStr::from_ptr(name).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    // Note: team_id is not used by the simple trait method
+    // For full advanced search support, the platform trait would need enhancement
+    let _ = team_id_str; // Unused in simple trait method
+    match runtime::block_on(platform.autocomplete_channels(name_str, 100)) {
+        Ok(channels) => match serde_json::to_string(&channels) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::Unknown,
+                        "Failed to convert result to C string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    &format!("Failed to serialize channels: {}", e),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Search for files with advanced filtering
+///
+/// # Safety
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_search_files(
+    handle: PlatformHandle,
+    request_json: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || request_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let request_str = {
+        match std::ffi::CStr::from_ptr(request_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let _request: platforms::mattermost::FileSearchRequest = match serde_json::from_str(request_str)
+    {
+        Ok(r) => r,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                &format!("Failed to parse file search request: {}", e),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    let _platform = &**handle;
+
+    // TODO: File search requires Platform trait support - not yet implemented
+    // The Platform trait needs a search_files method added
+    error::set_last_error(Error::unsupported(
+        "Advanced file search not yet supported by Platform trait",
+    ));
+    std::ptr::null_mut()
+}
+
+/// FFI function: Search for posts with advanced filtering
+///
+/// # Safety
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_search_posts_advanced(
+    handle: PlatformHandle,
+    request_json: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || request_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let request_str = {
+        match std::ffi::CStr::from_ptr(request_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let _request: platforms::mattermost::PostSearchOptions = match serde_json::from_str(request_str)
+    {
+        Ok(r) => r,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                &format!("Failed to parse post search request: {}", e),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    let _platform = &**handle;
+
+    // TODO: Advanced post search requires Platform trait support - not yet implemented
+    // The Platform trait has search_messages(query, limit) but not advanced options
+    // To support this properly, need to add search_posts_advanced to the trait
+    error::set_last_error(Error::unsupported(
+        "Advanced post search not yet supported by Platform trait",
+    ));
+    std::ptr::null_mut()
+}
+
+// ============================================================================
+// User Preferences and Notifications
+// ============================================================================
+
+/// FFI function: Get user preferences as JSON
+/// Returns a JSON string representing the user's preferences
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_user_preferences(
+    handle: PlatformHandle,
+    user_id: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || user_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let user_id_str = {
+        match std::ffi::CStr::from_ptr(user_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_user_preferences(user_id_str)) {
+        Ok(json) => match CString::new(json) {
+            Ok(c_string) => c_string.into_raw(),
+            Err(_) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::OutOfMemory,
+                    "Failed to allocate string",
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Set user preferences from JSON
+/// Returns error code indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_set_user_preferences(
+    handle: PlatformHandle,
+    user_id: *const c_char,
+    preferences_json: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || user_id.is_null() || preferences_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let user_id_str = {
+        match std::ffi::CStr::from_ptr(user_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let preferences_json_str = {
+        match std::ffi::CStr::from_ptr(preferences_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.set_user_preferences(user_id_str, preferences_json_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Mute a channel
+/// Returns error code indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_mute_channel(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.mute_channel(channel_id_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Unmute a channel
+/// Returns error code indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_unmute_channel(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.unmute_channel(channel_id_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Update channel notification properties from JSON
+/// Returns error code indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_update_channel_notify_props(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+    notify_props_json: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() || notify_props_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let notify_props_json_str = {
+        match std::ffi::CStr::from_ptr(notify_props_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(
+        platform.update_channel_notify_props(channel_id_str, notify_props_json_str),
+    ) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+// ============================================================================
+// Channel Read State Management FFI
+// ============================================================================
+
+/// FFI function: Get the current user's account-wide notification preferences
+/// Returns a JSON string representing the UserNotifyProps
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_user_notify_props(
+    handle: PlatformHandle,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_user_notify_props()) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize notify props: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Update the current user's account-wide notification preferences
+/// Fields omitted from the JSON are left unchanged
+/// Returns a JSON string representing the updated UserNotifyProps
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_update_user_notify_props(
+    handle: PlatformHandle,
+    props_json: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || props_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let props_json_str = {
+        match std::ffi::CStr::from_ptr(props_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let props: crate::types::UserNotifyProps = match serde_json::from_str(props_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid notify props JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.update_user_notify_props(&props)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize notify props: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Mark a channel as viewed (read)
+/// Returns error code indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_view_channel(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.view_channel(channel_id_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Get unread information for a channel
+/// Returns a JSON string with unread counts or NULL on error
+/// The returned string must be freed with communicator_free_string()
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_channel_unread(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    let unread_info = match runtime::block_on(platform.get_channel_unread(channel_id_str)) {
+        Ok(info) => info,
+        Err(e) => {
+            error::set_last_error(e);
+            return std::ptr::null_mut();
+        }
+    };
+
+    // Serialize to JSON
+    let json = match serde_json::to_string(&unread_info) {
+        Ok(j) => j,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::Unknown,
+                format!("Failed to serialize unread info: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    match CString::new(json) {
+        Ok(c_string) => c_string.into_raw(),
+        Err(_) => {
+            error::set_last_error(Error::invalid_utf8());
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Get unread counts for all channels in a team
+/// Returns a JSON string with array of unread info or NULL on error
+/// The returned string must be freed with communicator_free_string()
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_team_unreads(
+    handle: PlatformHandle,
+    team_id: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || team_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    let unreads = match runtime::block_on(platform.get_team_unreads(team_id_str)) {
+        Ok(list) => list,
+        Err(e) => {
+            error::set_last_error(e);
+            return std::ptr::null_mut();
+        }
+    };
+
+    // Serialize to JSON
+    let json = match serde_json::to_string(&unreads) {
+        Ok(j) => j,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::Unknown,
+                format!("Failed to serialize team unreads: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    match CString::new(json) {
+        Ok(c_string) => c_string.into_raw(),
+        Err(_) => {
+            error::set_last_error(Error::invalid_utf8());
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Get unread counts for all channels across all teams
+///
+/// # Safety
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_get_all_unreads(
+    handle: PlatformHandle,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_all_unreads()) {
+        Ok(unreads) => match serde_json::to_string(&unreads) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::Unknown,
+                        "Failed to convert result to C string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    &format!("Failed to serialize unreads: {}", e),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Get unread counts summed over every team
+///
+/// Returns a JSON UnreadTotals object
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_total_unreads(
+    handle: PlatformHandle,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_total_unreads()) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize unread totals: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Get unread posts in a channel
+///
+/// # Safety
+/// The caller must ensure all pointer arguments are valid.
+#[no_mangle]
+pub unsafe extern "C" fn communicator_platform_get_unread_posts(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+    limit_after: usize,
+    limit_before: usize,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_unread_posts(channel_id_str, limit_after, limit_before)) {
+        Ok(json) => match CString::new(json) {
+            Ok(c_string) => c_string.into_raw(),
+            Err(_) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    "Failed to convert result to C string",
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+// ============================================================================
+// Sidebar Categories
+// ============================================================================
+
+/// FFI function: Get the current user's sidebar categories for a team
+/// Returns a JSON array of SidebarCategory objects in display order
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_sidebar_categories(
+    handle: PlatformHandle,
+    team_id: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || team_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_sidebar_categories(team_id_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize sidebar categories: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Create a custom sidebar category
+/// channel_ids_json: JSON array of channel IDs to move into the category
+/// Returns a JSON string representing the created SidebarCategory
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_create_sidebar_category(
+    handle: PlatformHandle,
+    team_id: *const c_char,
+    display_name: *const c_char,
+    channel_ids_json: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || team_id.is_null() || display_name.is_null() || channel_ids_json.is_null()
+    {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let display_name_str = {
+        match std::ffi::CStr::from_ptr(display_name).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let channel_ids_json_str = {
+        match std::ffi::CStr::from_ptr(channel_ids_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let channel_ids: Vec<String> = match serde_json::from_str(channel_ids_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid channel IDs JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.create_sidebar_category(
+        team_id_str,
+        display_name_str,
+        channel_ids,
+    )) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize sidebar category: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Update a sidebar category
+/// category_json: JSON SidebarCategory with the desired values
+/// Returns a JSON string representing the updated SidebarCategory
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_update_sidebar_category(
+    handle: PlatformHandle,
+    category_json: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || category_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let category_json_str = {
+        match std::ffi::CStr::from_ptr(category_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let category: types::SidebarCategory = match serde_json::from_str(category_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid sidebar category JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.update_sidebar_category(category)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize sidebar category: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Delete a custom sidebar category
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_delete_sidebar_category(
+    handle: PlatformHandle,
+    team_id: *const c_char,
+    category_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || team_id.is_null() || category_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let category_id_str = {
+        match std::ffi::CStr::from_ptr(category_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.delete_sidebar_category(team_id_str, category_id_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Reorder the sidebar categories
+/// category_ids_json: JSON array of all category IDs in the desired order
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_reorder_sidebar_categories(
+    handle: PlatformHandle,
+    team_id: *const c_char,
+    category_ids_json: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || team_id.is_null() || category_ids_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let category_ids_json_str = {
+        match std::ffi::CStr::from_ptr(category_ids_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let category_ids: Vec<String> = match serde_json::from_str(category_ids_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid category IDs JSON: {e}"),
+            ));
+            return ErrorCode::InvalidArgument;
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.reorder_sidebar_categories(team_id_str, category_ids)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Move a channel into a sidebar category
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_move_channel_to_category(
+    handle: PlatformHandle,
+    team_id: *const c_char,
+    channel_id: *const c_char,
+    category_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || team_id.is_null() || channel_id.is_null() || category_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let category_id_str = {
+        match std::ffi::CStr::from_ptr(category_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.move_channel_to_category(
+        team_id_str,
+        channel_id_str,
+        category_id_str,
+    )) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+/// FFI function: Add a channel to, or remove it from, the favorites category
+/// favorite: non-zero to favorite, 0 to move the channel back to its default category
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_set_channel_favorite(
+    handle: PlatformHandle,
+    team_id: *const c_char,
+    channel_id: *const c_char,
+    favorite: std::os::raw::c_int,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || team_id.is_null() || channel_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.set_channel_favorite(
+        team_id_str,
+        channel_id_str,
+        favorite != 0,
+    )) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+// ============================================================================
+// Channel Bookmarks
+// ============================================================================
+
+/// Get the bookmarks of a channel
+/// Returns a JSON array of bookmarks in display order
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_get_channel_bookmarks(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_channel_bookmarks(channel_id_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize bookmarks: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// Create a link or file bookmark in a channel
+/// bookmark_json must have display_name, type ("link" or "file") and
+/// link_url or file_id; emoji and image_url are optional
+/// Returns the created bookmark as JSON
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_create_channel_bookmark(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+    bookmark_json: *const c_char,
+) -> *mut c_char {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() || bookmark_json.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let bookmark_json_str = {
+        match std::ffi::CStr::from_ptr(bookmark_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let bookmark: types::NewChannelBookmark = match serde_json::from_str(bookmark_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid bookmark JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.create_channel_bookmark(channel_id_str, bookmark)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize bookmark: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// Delete a bookmark from a channel
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_delete_channel_bookmark(
+    handle: PlatformHandle,
+    channel_id: *const c_char,
+    bookmark_id: *const c_char,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() || channel_id.is_null() || bookmark_id.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let bookmark_id_str = {
+        match std::ffi::CStr::from_ptr(bookmark_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.delete_channel_bookmark(channel_id_str, bookmark_id_str)) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+// ============================================================================
+// Sessions
+// ============================================================================
+
+/// FFI function: List the active login sessions of a user
+/// Returns a JSON array of Session objects
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_get_file_thumbnail(
+pub unsafe extern "C" fn communicator_platform_list_sessions(
     handle: PlatformHandle,
-    file_id: *const c_char,
-    out_data: *mut *mut u8,
-    out_size: *mut usize,
-) -> ErrorCode {
+    user_id: *const c_char,
+) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || file_id.is_null() || out_data.is_null() || out_size.is_null() {
+    if handle.is_null() || user_id.is_null() {
         error::set_last_error(Error::null_pointer());
-        return ErrorCode::NullPointer;
+        return std::ptr::null_mut();
     }
 
-    let file_id_str = {
-        match std::ffi::CStr::from_ptr(file_id).to_str() {
+    let user_id_str = {
+        match std::ffi::CStr::from_ptr(user_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
+                return std::ptr::null_mut();
             }
         }
     };
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_file_thumbnail(file_id_str)) {
-        Ok(data) => {
-            let size = data.len();
-            let boxed_data = data.into_boxed_slice();
-            let raw_ptr = Box::into_raw(boxed_data) as *mut u8;
-
-            *out_data = raw_ptr;
-            *out_size = size;
-            ErrorCode::Success
-        }
+    match runtime::block_on(platform.list_sessions(user_id_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize sessions: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
         Err(e) => {
-            let code = e.code;
             error::set_last_error(e);
-            code
+            std::ptr::null_mut()
         }
     }
 }
 
-/// FFI function: Free file data allocated by download_file or get_file_thumbnail
-///
-/// # Arguments
-/// * `data` - Pointer to file data returned by communicator_platform_download_file or communicator_platform_get_file_thumbnail
-/// * `size` - Size of the data in bytes (as returned in out_size)
-///
-/// # Safety
-/// This function is unsafe because it deals with raw pointers from C.
-/// The caller must ensure the data pointer was allocated by this library and has not been freed already.
+/// FFI function: Revoke one of the current user's sessions
+/// Returns ErrorCode indicating success or failure
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_free_file_data(data: *mut u8, size: usize) {
-    if !data.is_null() && size > 0 {
-        let _ = Box::from_raw(std::slice::from_raw_parts_mut(data, size));
-    }
-}
-
-/// FFI function: Get file preview (full-size image preview)
-///
-/// # Safety
-/// The caller must ensure all pointer arguments are valid.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_get_file_preview(
+pub unsafe extern "C" fn communicator_platform_revoke_session(
     handle: PlatformHandle,
-    file_id: *const c_char,
-    out_data: *mut *mut u8,
-    out_size: *mut usize,
+    session_id: *const c_char,
 ) -> ErrorCode {
     error::clear_last_error();
 
-    if handle.is_null() || file_id.is_null() || out_data.is_null() || out_size.is_null() {
+    if handle.is_null() || session_id.is_null() {
         error::set_last_error(Error::null_pointer());
         return ErrorCode::NullPointer;
     }
 
-    let file_id_str = {
-        match std::ffi::CStr::from_ptr(file_id).to_str() {
+    let session_id_str = {
+        match std::ffi::CStr::from_ptr(session_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4001,16 +9241,8 @@ pub unsafe extern "C" fn communicator_platform_get_file_preview(
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_file_preview(file_id_str)) {
-        Ok(data) => {
-            let size = data.len();
-            let boxed_data = data.into_boxed_slice();
-            let raw_ptr = Box::into_raw(boxed_data) as *mut u8;
-
-            *out_data = raw_ptr;
-            *out_size = size;
-            ErrorCode::Success
-        }
+    match runtime::block_on(platform.revoke_session(session_id_str)) {
+        Ok(()) => ErrorCode::Success,
         Err(e) => {
             let code = e.code;
             error::set_last_error(e);
@@ -4019,24 +9251,72 @@ pub unsafe extern "C" fn communicator_platform_get_file_preview(
     }
 }
 
-/// FFI function: Get a public link to a file
+/// FFI function: Revoke every session of the current user, including this one
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
 ///
 /// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_revoke_all_sessions(
+    handle: PlatformHandle,
+) -> ErrorCode {
+    error::clear_last_error();
+
+    if handle.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return ErrorCode::NullPointer;
+    }
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.revoke_all_sessions()) {
+        Ok(()) => ErrorCode::Success,
+        Err(e) => {
+            let code = e.code;
+            error::set_last_error(e);
+            code
+        }
+    }
+}
+
+// ============================================================================
+// Slash Commands
+// ============================================================================
+
+/// FFI function: Execute a slash command in a channel
+/// Returns a JSON string representing the CommandResponse
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
 #[no_mangle]
-pub unsafe extern "C" fn communicator_platform_get_file_link(
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_platform_execute_command(
     handle: PlatformHandle,
-    file_id: *const c_char,
+    channel_id: *const c_char,
+    command: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || file_id.is_null() {
+    if handle.is_null() || channel_id.is_null() || command.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let file_id_str = {
-        match std::ffi::CStr::from_ptr(file_id).to_str() {
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let command_str = {
+        match std::ffi::CStr::from_ptr(command).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4047,13 +9327,22 @@ pub unsafe extern "C" fn communicator_platform_get_file_link(
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_file_link(file_id_str)) {
-        Ok(link) => match CString::new(link) {
-            Ok(c_string) => c_string.into_raw(),
-            Err(_) => {
+    match runtime::block_on(platform.execute_command(channel_id_str, command_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    "Failed to convert result to C string",
+                    format!("Failed to serialize command response: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -4065,31 +9354,40 @@ pub unsafe extern "C" fn communicator_platform_get_file_link(
     }
 }
 
-// ============================================================================
-// Thread Operations
-// ============================================================================
-
-/// FFI function: Get a thread (root post and all replies)
-/// Returns a JSON string containing an array of messages
+/// FFI function: List the slash commands available in a channel whose trigger
+/// starts with a prefix
+/// Returns a JSON array string of SlashCommand objects
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-/// The returned string must be freed using communicator_free_string.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_get_thread(
+pub unsafe extern "C" fn communicator_platform_autocomplete_commands(
     handle: PlatformHandle,
-    post_id: *const c_char,
+    channel_id: *const c_char,
+    prefix: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || post_id.is_null() {
+    if handle.is_null() || channel_id.is_null() || prefix.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let post_id_str = {
-        match std::ffi::CStr::from_ptr(post_id).to_str() {
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let prefix_str = {
+        match std::ffi::CStr::from_ptr(prefix).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4100,14 +9398,14 @@ pub unsafe extern "C" fn communicator_platform_get_thread(
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_thread(post_id_str)) {
-        Ok(messages) => match serde_json::to_string(&messages) {
+    match runtime::block_on(platform.autocomplete_commands(channel_id_str, prefix_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
             Ok(json) => match CString::new(json) {
                 Ok(c_string) => c_string.into_raw(),
                 Err(_) => {
                     error::set_last_error(Error::new(
-                        ErrorCode::Unknown,
-                        "Failed to create C string from thread JSON",
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
                     ));
                     std::ptr::null_mut()
                 }
@@ -4115,7 +9413,7 @@ pub unsafe extern "C" fn communicator_platform_get_thread(
             Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    format!("Failed to serialize thread: {e}"),
+                    format!("Failed to serialize commands: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -4127,106 +9425,164 @@ pub unsafe extern "C" fn communicator_platform_get_thread(
     }
 }
 
-/// FFI function: Start following a thread
-/// Returns error code indicating success or failure
+// ============================================================================
+// Incoming Webhooks
+// ============================================================================
+
+/// FFI function: Create an incoming webhook
+/// Returns a JSON string representing the created IncomingWebhook
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_follow_thread(
+pub unsafe extern "C" fn communicator_platform_create_incoming_webhook(
     handle: PlatformHandle,
-    thread_id: *const c_char,
-) -> ErrorCode {
+    hook_json: *const c_char,
+) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || thread_id.is_null() {
+    if handle.is_null() || hook_json.is_null() {
         error::set_last_error(Error::null_pointer());
-        return ErrorCode::NullPointer;
+        return std::ptr::null_mut();
     }
 
-    let thread_id_str = {
-        match std::ffi::CStr::from_ptr(thread_id).to_str() {
+    let hook_json_str = {
+        match std::ffi::CStr::from_ptr(hook_json).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
+                return std::ptr::null_mut();
             }
         }
     };
 
+    let hook: crate::types::NewIncomingWebhook = match serde_json::from_str(hook_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid webhook JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
     let platform = &**handle;
 
-    match runtime::block_on(platform.follow_thread(thread_id_str)) {
-        Ok(()) => ErrorCode::Success,
+    match runtime::block_on(platform.create_incoming_webhook(&hook)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize webhook: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
         Err(e) => {
-            let code = e.code;
             error::set_last_error(e);
-            code
+            std::ptr::null_mut()
         }
     }
 }
 
-/// FFI function: Stop following a thread
-/// Returns error code indicating success or failure
+/// FFI function: List a page of incoming webhooks
+/// team_id may be NULL to list webhooks of all teams
+/// Returns a JSON array of IncomingWebhook objects
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_unfollow_thread(
+pub unsafe extern "C" fn communicator_platform_list_incoming_webhooks(
     handle: PlatformHandle,
-    thread_id: *const c_char,
-) -> ErrorCode {
+    team_id: *const c_char,
+    page: u32,
+    per_page: u32,
+) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || thread_id.is_null() {
+    if handle.is_null() {
         error::set_last_error(Error::null_pointer());
-        return ErrorCode::NullPointer;
+        return std::ptr::null_mut();
     }
 
-    let thread_id_str = {
-        match std::ffi::CStr::from_ptr(thread_id).to_str() {
-            Ok(s) => s,
+    let team_id_opt = if team_id.is_null() {
+        None
+    } else {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
+            Ok(s) => Some(s),
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
+                return std::ptr::null_mut();
             }
         }
     };
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.unfollow_thread(thread_id_str)) {
-        Ok(()) => ErrorCode::Success,
+    match runtime::block_on(platform.list_incoming_webhooks(team_id_opt, page, per_page)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize webhooks: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
         Err(e) => {
-            let code = e.code;
             error::set_last_error(e);
-            code
+            std::ptr::null_mut()
         }
     }
 }
 
-/// FFI function: Mark a thread as read
-/// Returns error code indicating success or failure
+/// FFI function: Delete an incoming webhook
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_mark_thread_read(
+pub unsafe extern "C" fn communicator_platform_delete_incoming_webhook(
     handle: PlatformHandle,
-    thread_id: *const c_char,
+    hook_id: *const c_char,
 ) -> ErrorCode {
     error::clear_last_error();
 
-    if handle.is_null() || thread_id.is_null() {
+    if handle.is_null() || hook_id.is_null() {
         error::set_last_error(Error::null_pointer());
         return ErrorCode::NullPointer;
     }
 
-    let thread_id_str = {
-        match std::ffi::CStr::from_ptr(thread_id).to_str() {
+    let hook_id_str = {
+        match std::ffi::CStr::from_ptr(hook_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4237,7 +9593,7 @@ pub unsafe extern "C" fn communicator_platform_mark_thread_read(
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.mark_thread_read(thread_id_str)) {
+    match runtime::block_on(platform.delete_incoming_webhook(hook_id_str)) {
         Ok(()) => ErrorCode::Success,
         Err(e) => {
             let code = e.code;
@@ -4247,27 +9603,27 @@ pub unsafe extern "C" fn communicator_platform_mark_thread_read(
     }
 }
 
-/// FFI function: Mark a thread as unread from a specific post
-/// Returns error code indicating success or failure
+/// FFI function: Post a message through an incoming webhook URL
+/// Does not need a platform handle or login; only communicator_init()
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_mark_thread_unread(
-    handle: PlatformHandle,
-    thread_id: *const c_char,
-    post_id: *const c_char,
+pub unsafe extern "C" fn communicator_send_webhook(
+    url: *const c_char,
+    payload_json: *const c_char,
 ) -> ErrorCode {
     error::clear_last_error();
 
-    if handle.is_null() || thread_id.is_null() || post_id.is_null() {
+    if url.is_null() || payload_json.is_null() {
         error::set_last_error(Error::null_pointer());
         return ErrorCode::NullPointer;
     }
 
-    let thread_id_str = {
-        match std::ffi::CStr::from_ptr(thread_id).to_str() {
+    let url_str = {
+        match std::ffi::CStr::from_ptr(url).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4276,8 +9632,8 @@ pub unsafe extern "C" fn communicator_platform_mark_thread_unread(
         }
     };
 
-    let post_id_str = {
-        match std::ffi::CStr::from_ptr(post_id).to_str() {
+    let payload_str = {
+        match std::ffi::CStr::from_ptr(payload_json).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4286,9 +9642,20 @@ pub unsafe extern "C" fn communicator_platform_mark_thread_unread(
         }
     };
 
-    let platform = &**handle;
+    let payload: crate::types::WebhookPayload = match serde_json::from_str(payload_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid webhook payload JSON: {e}"),
+            ));
+            return ErrorCode::InvalidArgument;
+        }
+    };
 
-    match runtime::block_on(platform.mark_thread_unread(thread_id_str, post_id_str)) {
+    match runtime::block_on(platforms::mattermost::send_incoming_webhook(
+        url_str, &payload,
+    )) {
         Ok(()) => ErrorCode::Success,
         Err(e) => {
             let code = e.code;
@@ -4298,30 +9665,33 @@ pub unsafe extern "C" fn communicator_platform_mark_thread_unread(
     }
 }
 
-/// FFI function: Get all threads for a user in a team
+// ============================================================================
+// Bot Accounts
+// ============================================================================
+
+/// FFI function: Create a bot account owned by the current user
+/// bot_json: JSON object with username, and optionally display_name, description
+/// Returns a JSON string containing the created bot
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
 ///
 /// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_get_user_threads(
+pub unsafe extern "C" fn communicator_platform_create_bot(
     handle: PlatformHandle,
-    user_id: *const c_char,
-    team_id: *const c_char,
-    since: u64,
-    deleted: std::os::raw::c_int,
-    unread: std::os::raw::c_int,
-    per_page: usize,
-    page: usize,
+    bot_json: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || user_id.is_null() || team_id.is_null() {
+    if handle.is_null() || bot_json.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let user_id_str = {
-        match std::ffi::CStr::from_ptr(user_id).to_str() {
+    let bot_json_str = {
+        match std::ffi::CStr::from_ptr(bot_json).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4330,33 +9700,35 @@ pub unsafe extern "C" fn communicator_platform_get_user_threads(
         }
     };
 
-    let team_id_str = {
-        match std::ffi::CStr::from_ptr(team_id).to_str() {
-            Ok(s) => s,
-            Err(_) => {
-                error::set_last_error(Error::invalid_utf8());
-                return std::ptr::null_mut();
-            }
+    let bot: crate::types::NewBot = match serde_json::from_str(bot_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid bot JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
         }
     };
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_user_threads(
-        user_id_str,
-        team_id_str,
-        since,
-        deleted != 0,
-        unread != 0,
-        per_page,
-        page,
-    )) {
-        Ok(json) => match CString::new(json) {
-            Ok(c_string) => c_string.into_raw(),
-            Err(_) => {
+    match runtime::block_on(platform.create_bot(&bot)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    "Failed to convert result to C string",
+                    format!("Failed to serialize bot: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -4368,26 +9740,30 @@ pub unsafe extern "C" fn communicator_platform_get_user_threads(
     }
 }
 
-/// FFI function: Get a specific thread for a user
+/// FFI function: Partially update a bot account
+/// patch_json: JSON object with any of username, display_name, description
+/// Returns a JSON string containing the updated bot
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
 ///
 /// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_get_user_thread(
+pub unsafe extern "C" fn communicator_platform_patch_bot(
     handle: PlatformHandle,
-    user_id: *const c_char,
-    team_id: *const c_char,
-    thread_id: *const c_char,
+    bot_user_id: *const c_char,
+    patch_json: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || user_id.is_null() || team_id.is_null() || thread_id.is_null() {
+    if handle.is_null() || bot_user_id.is_null() || patch_json.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let user_id_str = {
-        match std::ffi::CStr::from_ptr(user_id).to_str() {
+    let bot_user_id_str = {
+        match std::ffi::CStr::from_ptr(bot_user_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4396,8 +9772,8 @@ pub unsafe extern "C" fn communicator_platform_get_user_thread(
         }
     };
 
-    let team_id_str = {
-        match std::ffi::CStr::from_ptr(team_id).to_str() {
+    let patch_json_str = {
+        match std::ffi::CStr::from_ptr(patch_json).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4406,25 +9782,35 @@ pub unsafe extern "C" fn communicator_platform_get_user_thread(
         }
     };
 
-    let thread_id_str = {
-        match std::ffi::CStr::from_ptr(thread_id).to_str() {
-            Ok(s) => s,
-            Err(_) => {
-                error::set_last_error(Error::invalid_utf8());
-                return std::ptr::null_mut();
-            }
+    let patch: crate::types::BotPatch = match serde_json::from_str(patch_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid bot patch JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
         }
     };
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_user_thread(user_id_str, team_id_str, thread_id_str)) {
-        Ok(json) => match CString::new(json) {
-            Ok(c_string) => c_string.into_raw(),
-            Err(_) => {
+    match runtime::block_on(platform.patch_bot(bot_user_id_str, &patch)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    "Failed to convert result to C string",
+                    format!("Failed to serialize bot: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -4436,80 +9822,28 @@ pub unsafe extern "C" fn communicator_platform_get_user_thread(
     }
 }
 
-/// FFI function: Mark all threads as read for a user in a team
-///
-/// # Safety
-/// The caller must ensure all pointer arguments are valid.
+/// FFI function: Disable a bot account
+/// Returns a JSON string containing the disabled bot
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
 #[no_mangle]
-pub unsafe extern "C" fn communicator_platform_mark_all_threads_read(
-    handle: PlatformHandle,
-    user_id: *const c_char,
-    team_id: *const c_char,
-) -> ErrorCode {
-    error::clear_last_error();
-
-    if handle.is_null() || user_id.is_null() || team_id.is_null() {
-        error::set_last_error(Error::null_pointer());
-        return ErrorCode::NullPointer;
-    }
-
-    let user_id_str = {
-        match std::ffi::CStr::from_ptr(user_id).to_str() {
-            Ok(s) => s,
-            Err(_) => {
-                error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
-            }
-        }
-    };
-
-    let team_id_str = {
-        match std::ffi::CStr::from_ptr(team_id).to_str() {
-            Ok(s) => s,
-            Err(_) => {
-                error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
-            }
-        }
-    };
-
-    let platform = &**handle;
-
-    match runtime::block_on(platform.mark_all_threads_as_read(user_id_str, team_id_str)) {
-        Ok(()) => ErrorCode::Success,
-        Err(e) => {
-            let code = e.code;
-            error::set_last_error(e);
-            code
-        }
-    }
-}
-
-/// FFI function: Search for messages
-///
-/// # Arguments
-/// * `handle` - Platform handle
-/// * `query` - Search query (supports operators like from:, in:, before:, after:)
-/// * `limit` - Maximum number of results
-///
-/// # Returns
-/// JSON array of messages on success, or null on error
 ///
 /// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_search_messages(
+pub unsafe extern "C" fn communicator_platform_disable_bot(
     handle: PlatformHandle,
-    query: *const c_char,
-    limit: usize,
+    bot_user_id: *const c_char,
 ) -> *mut c_char {
-    if handle.is_null() || query.is_null() {
+    error::clear_last_error();
+
+    if handle.is_null() || bot_user_id.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let query_str = {
-        match std::ffi::CStr::from_ptr(query).to_str() {
+    let bot_user_id_str = {
+        match std::ffi::CStr::from_ptr(bot_user_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4520,14 +9854,14 @@ pub unsafe extern "C" fn communicator_platform_search_messages(
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.search_messages(query_str, limit)) {
-        Ok(messages) => match serde_json::to_string(&messages) {
-            Ok(json) => match std::ffi::CString::new(json) {
+    match runtime::block_on(platform.disable_bot(bot_user_id_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
                 Ok(c_string) => c_string.into_raw(),
                 Err(_) => {
                     error::set_last_error(Error::new(
-                        ErrorCode::Unknown,
-                        "Failed to convert result to C string",
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
                     ));
                     std::ptr::null_mut()
                 }
@@ -4535,7 +9869,7 @@ pub unsafe extern "C" fn communicator_platform_search_messages(
             Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    &format!("Failed to serialize messages: {}", e),
+                    format!("Failed to serialize bot: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -4547,35 +9881,29 @@ pub unsafe extern "C" fn communicator_platform_search_messages(
     }
 }
 
-// ============================================================================
-// Advanced Search Operations
-// ============================================================================
-
-/// FFI function: Search for users with advanced filtering
-///
-/// # Arguments
-/// * `handle` - Platform handle
-/// * `request_json` - JSON string with UserSearchRequest parameters
-///
-/// # Returns
-/// JSON array of users on success, or null on error
+/// FFI function: Transfer ownership of a bot account to another user
+/// Returns a JSON string containing the updated bot
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
 ///
 /// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_search_users(
+pub unsafe extern "C" fn communicator_platform_assign_bot(
     handle: PlatformHandle,
-    request_json: *const c_char,
+    bot_user_id: *const c_char,
+    user_id: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || request_json.is_null() {
+    if handle.is_null() || bot_user_id.is_null() || user_id.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let request_str = {
-        match std::ffi::CStr::from_ptr(request_json).to_str() {
+    let bot_user_id_str = {
+        match std::ffi::CStr::from_ptr(bot_user_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4584,32 +9912,26 @@ pub unsafe extern "C" fn communicator_platform_search_users(
         }
     };
 
-    let request: platforms::mattermost::UserSearchRequest = match serde_json::from_str(request_str)
-    {
-        Ok(r) => r,
-        Err(e) => {
-            error::set_last_error(Error::new(
-                ErrorCode::InvalidArgument,
-                &format!("Failed to parse search request: {}", e),
-            ));
-            return std::ptr::null_mut();
+    let user_id_str = {
+        match std::ffi::CStr::from_ptr(user_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
         }
     };
 
     let platform = &**handle;
 
-    // Extract term and limit for the simple trait method
-    let query = &request.term;
-    let limit = request.limit.unwrap_or(100) as usize;
-
-    match runtime::block_on(platform.search_users(query, limit)) {
-        Ok(users) => match serde_json::to_string(&users) {
+    match runtime::block_on(platform.assign_bot(bot_user_id_str, user_id_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
             Ok(json) => match CString::new(json) {
                 Ok(c_string) => c_string.into_raw(),
                 Err(_) => {
                     error::set_last_error(Error::new(
-                        ErrorCode::Unknown,
-                        "Failed to convert result to C string",
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
                     ));
                     std::ptr::null_mut()
                 }
@@ -4617,7 +9939,7 @@ pub unsafe extern "C" fn communicator_platform_search_users(
             Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    &format!("Failed to serialize users: {}", e),
+                    format!("Failed to serialize bot: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -4629,27 +9951,36 @@ pub unsafe extern "C" fn communicator_platform_search_users(
     }
 }
 
-/// FFI function: Autocomplete users for mentions
+// ============================================================================
+// Interactive Messages
+// ============================================================================
+
+/// FFI function: Send a message carrying rich attachments, such as action buttons and menus
+/// attachments_json: JSON array of message attachments (fallback, color, pretext, title,
+/// title_link, text, fields, actions)
+/// Returns a JSON string containing the created message
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
 ///
 /// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_autocomplete_users(
+pub unsafe extern "C" fn communicator_platform_send_message_with_attachments(
     handle: PlatformHandle,
-    name: *const c_char,
-    team_id: *const c_char,
     channel_id: *const c_char,
-    limit: usize,
+    text: *const c_char,
+    attachments_json: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || name.is_null() {
+    if handle.is_null() || channel_id.is_null() || text.is_null() || attachments_json.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let name_str = {
-        match std::ffi::CStr::from_ptr(name).to_str() {
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4658,11 +9989,9 @@ pub unsafe extern "C" fn communicator_platform_autocomplete_users(
         }
     };
 
-    let _team_id_opt = if team_id.is_null() {
-        None
-    } else {
-        match std::ffi::CStr::from_ptr(team_id).to_str() {
-            Ok(s) => Some(s),
+    let text_str = {
+        match std::ffi::CStr::from_ptr(text).to_str() {
+            Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
                 return std::ptr::null_mut();
@@ -4670,10 +9999,8 @@ pub unsafe extern "C" fn communicator_platform_autocomplete_users(
         }
     };
 
-    let channel_id_str = if channel_id.is_null() {
-        ""
-    } else {
-        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+    let attachments_json_str = {
+        match std::ffi::CStr::from_ptr(attachments_json).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4682,18 +10009,32 @@ pub unsafe extern "C" fn communicator_platform_autocomplete_users(
         }
     };
 
+    let attachments: Vec<crate::types::MessageAttachment> =
+        match serde_json::from_str(attachments_json_str) {
+            Ok(v) => v,
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::InvalidArgument,
+                    format!("Invalid attachments JSON: {e}"),
+                ));
+                return std::ptr::null_mut();
+            }
+        };
+
     let platform = &**handle;
 
-    // Note: team_id is not used by the simple trait method
-    // For full advanced search support, the platform trait would need enhancement
-    match runtime::block_on(platform.autocomplete_users(channel_id_str, name_str, limit)) {
-        Ok(users) => match serde_json::to_string(&users) {
+    match runtime::block_on(platform.send_message_with_attachments(
+        channel_id_str,
+        text_str,
+        &attachments,
+    )) {
+        Ok(result) => match serde_json::to_string(&result) {
             Ok(json) => match CString::new(json) {
                 Ok(c_string) => c_string.into_raw(),
                 Err(_) => {
                     error::set_last_error(Error::new(
-                        ErrorCode::Unknown,
-                        "Failed to convert result to C string",
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
                     ));
                     std::ptr::null_mut()
                 }
@@ -4701,7 +10042,7 @@ pub unsafe extern "C" fn communicator_platform_autocomplete_users(
             Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    &format!("Failed to serialize users: {}", e),
+                    format!("Failed to serialize message: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -4713,94 +10054,111 @@ pub unsafe extern "C" fn communicator_platform_autocomplete_users(
     }
 }
 
-/// FFI function: Search for channels
+/// FFI function: Open an interactive dialog for the user who triggered an action
+/// dialog_json: JSON object with title, elements, and optionally callback_id,
+/// introduction_text, icon_url, submit_label, notify_on_cancel, state
+/// Returns ErrorCode indicating success or failure
+#[no_mangle]
 ///
 /// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_search_channels(
+pub unsafe extern "C" fn communicator_platform_open_interactive_dialog(
     handle: PlatformHandle,
-    team_id: *const c_char,
-    term: *const c_char,
-) -> *mut c_char {
+    trigger_id: *const c_char,
+    url: *const c_char,
+    dialog_json: *const c_char,
+) -> ErrorCode {
     error::clear_last_error();
 
-    if handle.is_null() || team_id.is_null() || term.is_null() {
+    if handle.is_null() || trigger_id.is_null() || url.is_null() || dialog_json.is_null() {
         error::set_last_error(Error::null_pointer());
-        return std::ptr::null_mut();
+        return ErrorCode::NullPointer;
     }
 
-    let team_id_str = {
-        match std::ffi::CStr::from_ptr(team_id).to_str() {
+    let trigger_id_str = {
+        match std::ffi::CStr::from_ptr(trigger_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
-                return std::ptr::null_mut();
+                return ErrorCode::InvalidUtf8;
             }
         }
     };
 
-    let term_str = {
-        match std::ffi::CStr::from_ptr(term).to_str() {
+    let url_str = {
+        match std::ffi::CStr::from_ptr(url).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
-                return std::ptr::null_mut();
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let dialog_json_str = {
+        match std::ffi::CStr::from_ptr(dialog_json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
             }
         }
     };
 
+    let dialog: crate::types::InteractiveDialog = match serde_json::from_str(dialog_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid dialog JSON: {e}"),
+            ));
+            return ErrorCode::InvalidArgument;
+        }
+    };
+
     let platform = &**handle;
 
-    // Note: team_id is not used by the simple trait method
-    // For full advanced search support, the platform trait would need enhancement
-    let _ = team_id_str; // Unused in simple trait method
-    match runtime::block_on(platform.search_channels(term_str, 100)) {
-        Ok(channels) => match serde_json::to_string(&channels) {
-            Ok(json) => match CString::new(json) {
-                Ok(c_string) => c_string.into_raw(),
-                Err(_) => {
-                    error::set_last_error(Error::new(
-                        ErrorCode::Unknown,
-                        "Failed to convert result to C string",
-                    ));
-                    std::ptr::null_mut()
-                }
-            },
-            Err(e) => {
-                error::set_last_error(Error::new(
-                    ErrorCode::Unknown,
-                    &format!("Failed to serialize channels: {}", e),
-                ));
-                std::ptr::null_mut()
-            }
-        },
+    match runtime::block_on(platform.open_interactive_dialog(trigger_id_str, url_str, &dialog)) {
+        Ok(()) => ErrorCode::Success,
         Err(e) => {
+            let code = e.code;
             error::set_last_error(e);
-            std::ptr::null_mut()
+            code
         }
     }
 }
 
-/// FFI function: Autocomplete channels for references
+// ============================================================================
+// Message Priority
+// ============================================================================
+
+/// FFI function: Send a message with a priority label and, optionally, an acknowledgement request
+/// priority_json: JSON object with priority ("standard", "important" or "urgent"),
+/// requested_ack and persistent_notifications
+/// Returns a JSON string containing the created message
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
 ///
 /// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_autocomplete_channels(
+pub unsafe extern "C" fn communicator_platform_send_message_with_priority(
     handle: PlatformHandle,
-    team_id: *const c_char,
-    name: *const c_char,
+    channel_id: *const c_char,
+    text: *const c_char,
+    priority_json: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || team_id.is_null() || name.is_null() {
+    if handle.is_null() || channel_id.is_null() || text.is_null() || priority_json.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let team_id_str = {
-        match std::ffi::CStr::from_ptr(team_id).to_str() {
+    let channel_id_str = {
+        match std::ffi::CStr::from_ptr(channel_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4809,8 +10167,18 @@ pub unsafe extern "C" fn communicator_platform_autocomplete_channels(
         }
     };
 
-    let name_str = {
-        match std::ffi::CStr::from_ptr(name).to_str() {
+    let text_str = {
+        match std::ffi::CStr::from_ptr(text).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    let priority_json_str = {
+        match std::ffi::CStr::from_ptr(priority_json).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4819,19 +10187,31 @@ pub unsafe extern "C" fn communicator_platform_autocomplete_channels(
         }
     };
 
+    let priority: crate::types::MessagePriority = match serde_json::from_str(priority_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid priority JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
     let platform = &**handle;
 
-    // Note: team_id is not used by the simple trait method
-    // For full advanced search support, the platform trait would need enhancement
-    let _ = team_id_str; // Unused in simple trait method
-    match runtime::block_on(platform.autocomplete_channels(name_str, 100)) {
-        Ok(channels) => match serde_json::to_string(&channels) {
+    match runtime::block_on(platform.send_message_with_priority(
+        channel_id_str,
+        text_str,
+        &priority,
+    )) {
+        Ok(result) => match serde_json::to_string(&result) {
             Ok(json) => match CString::new(json) {
                 Ok(c_string) => c_string.into_raw(),
                 Err(_) => {
                     error::set_last_error(Error::new(
-                        ErrorCode::Unknown,
-                        "Failed to convert result to C string",
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
                     ));
                     std::ptr::null_mut()
                 }
@@ -4839,7 +10219,7 @@ pub unsafe extern "C" fn communicator_platform_autocomplete_channels(
             Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    &format!("Failed to serialize channels: {}", e),
+                    format!("Failed to serialize message: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -4851,72 +10231,87 @@ pub unsafe extern "C" fn communicator_platform_autocomplete_channels(
     }
 }
 
-/// FFI function: Search for files with advanced filtering
+/// FFI function: Acknowledge a message as the current user
+/// Returns a JSON string containing the acknowledgement
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
 ///
 /// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_search_files(
+pub unsafe extern "C" fn communicator_platform_ack_message(
     handle: PlatformHandle,
-    request_json: *const c_char,
+    message_id: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || request_json.is_null() {
+    if handle.is_null() || message_id.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let request_str = {
-        match std::ffi::CStr::from_ptr(request_json).to_str() {
+    let message_id_str = {
+        match std::ffi::CStr::from_ptr(message_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
                 return std::ptr::null_mut();
             }
-        }
-    };
-
-    let _request: platforms::mattermost::FileSearchRequest = match serde_json::from_str(request_str)
-    {
-        Ok(r) => r,
+        }
+    };
+
+    let platform = &**handle;
+
+    match runtime::block_on(platform.ack_message(message_id_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize acknowledgement: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
         Err(e) => {
-            error::set_last_error(Error::new(
-                ErrorCode::InvalidArgument,
-                &format!("Failed to parse file search request: {}", e),
-            ));
-            return std::ptr::null_mut();
+            error::set_last_error(e);
+            std::ptr::null_mut()
         }
-    };
-
-    let _platform = &**handle;
-
-    // TODO: File search requires Platform trait support - not yet implemented
-    // The Platform trait needs a search_files method added
-    error::set_last_error(Error::unsupported(
-        "Advanced file search not yet supported by Platform trait",
-    ));
-    std::ptr::null_mut()
+    }
 }
 
-/// FFI function: Search for posts with advanced filtering
+/// FFI function: Get the acknowledgements of a message, oldest first
+/// Returns a JSON array of acknowledgements
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
 ///
 /// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_search_posts_advanced(
+pub unsafe extern "C" fn communicator_platform_get_message_acknowledgements(
     handle: PlatformHandle,
-    request_json: *const c_char,
+    message_id: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || request_json.is_null() {
+    if handle.is_null() || message_id.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let request_str = {
-        match std::ffi::CStr::from_ptr(request_json).to_str() {
+    let message_id_str = {
+        match std::ffi::CStr::from_ptr(message_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4925,35 +10320,41 @@ pub unsafe extern "C" fn communicator_platform_search_posts_advanced(
         }
     };
 
-    let _request: platforms::mattermost::PostSearchOptions = match serde_json::from_str(request_str)
-    {
-        Ok(r) => r,
+    let platform = &**handle;
+
+    match runtime::block_on(platform.get_message_acknowledgements(message_id_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize acknowledgements: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
         Err(e) => {
-            error::set_last_error(Error::new(
-                ErrorCode::InvalidArgument,
-                &format!("Failed to parse post search request: {}", e),
-            ));
-            return std::ptr::null_mut();
+            error::set_last_error(e);
+            std::ptr::null_mut()
         }
-    };
-
-    let _platform = &**handle;
-
-    // TODO: Advanced post search requires Platform trait support - not yet implemented
-    // The Platform trait has search_messages(query, limit) but not advanced options
-    // To support this properly, need to add search_posts_advanced to the trait
-    error::set_last_error(Error::unsupported(
-        "Advanced post search not yet supported by Platform trait",
-    ));
-    std::ptr::null_mut()
+    }
 }
 
 // ============================================================================
-// User Preferences and Notifications
+// User Groups
 // ============================================================================
 
-/// FFI function: Get user preferences as JSON
-/// Returns a JSON string representing the user's preferences
+/// FFI function: Create a custom user group that can be @-mentioned
+/// Returns a JSON string representing the UserGroup
 /// The caller must free the returned string using communicator_free_string()
 /// Returns NULL on error
 #[no_mangle]
@@ -4961,19 +10362,19 @@ pub unsafe extern "C" fn communicator_platform_search_posts_advanced(
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_get_user_preferences(
+pub unsafe extern "C" fn communicator_platform_create_user_group(
     handle: PlatformHandle,
-    user_id: *const c_char,
+    group_json: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || user_id.is_null() {
+    if handle.is_null() || group_json.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let user_id_str = {
-        match std::ffi::CStr::from_ptr(user_id).to_str() {
+    let group_json_str = {
+        match std::ffi::CStr::from_ptr(group_json).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -4982,15 +10383,35 @@ pub unsafe extern "C" fn communicator_platform_get_user_preferences(
         }
     };
 
+    let group: crate::types::NewUserGroup = match serde_json::from_str(group_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid group JSON: {e}"),
+            ));
+            return std::ptr::null_mut();
+        }
+    };
+
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_user_preferences(user_id_str)) {
-        Ok(json) => match CString::new(json) {
-            Ok(c_string) => c_string.into_raw(),
-            Err(_) => {
+    match runtime::block_on(platform.create_user_group(group)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
                 error::set_last_error(Error::new(
-                    ErrorCode::OutOfMemory,
-                    "Failed to allocate string",
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize group: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -5002,27 +10423,27 @@ pub unsafe extern "C" fn communicator_platform_get_user_preferences(
     }
 }
 
-/// FFI function: Set user preferences from JSON
-/// Returns error code indicating success or failure
+/// FFI function: Add users to a custom user group
+/// Returns ErrorCode indicating success or failure
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_set_user_preferences(
+pub unsafe extern "C" fn communicator_platform_add_user_group_members(
     handle: PlatformHandle,
-    user_id: *const c_char,
-    preferences_json: *const c_char,
+    group_id: *const c_char,
+    user_ids_json: *const c_char,
 ) -> ErrorCode {
     error::clear_last_error();
 
-    if handle.is_null() || user_id.is_null() || preferences_json.is_null() {
+    if handle.is_null() || group_id.is_null() || user_ids_json.is_null() {
         error::set_last_error(Error::null_pointer());
         return ErrorCode::NullPointer;
     }
 
-    let user_id_str = {
-        match std::ffi::CStr::from_ptr(user_id).to_str() {
+    let group_id_str = {
+        match std::ffi::CStr::from_ptr(group_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -5031,8 +10452,8 @@ pub unsafe extern "C" fn communicator_platform_set_user_preferences(
         }
     };
 
-    let preferences_json_str = {
-        match std::ffi::CStr::from_ptr(preferences_json).to_str() {
+    let user_ids_json_str = {
+        match std::ffi::CStr::from_ptr(user_ids_json).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -5041,9 +10462,20 @@ pub unsafe extern "C" fn communicator_platform_set_user_preferences(
         }
     };
 
+    let user_ids: Vec<String> = match serde_json::from_str(user_ids_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid user IDs JSON: {e}"),
+            ));
+            return ErrorCode::InvalidArgument;
+        }
+    };
+
     let platform = &**handle;
 
-    match runtime::block_on(platform.set_user_preferences(user_id_str, preferences_json_str)) {
+    match runtime::block_on(platform.add_user_group_members(group_id_str, &user_ids)) {
         Ok(()) => ErrorCode::Success,
         Err(e) => {
             let code = e.code;
@@ -5053,26 +10485,37 @@ pub unsafe extern "C" fn communicator_platform_set_user_preferences(
     }
 }
 
-/// FFI function: Mute a channel
-/// Returns error code indicating success or failure
+/// FFI function: Remove users from a custom user group
+/// Returns ErrorCode indicating success or failure
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_mute_channel(
+pub unsafe extern "C" fn communicator_platform_remove_user_group_members(
     handle: PlatformHandle,
-    channel_id: *const c_char,
+    group_id: *const c_char,
+    user_ids_json: *const c_char,
 ) -> ErrorCode {
     error::clear_last_error();
 
-    if handle.is_null() || channel_id.is_null() {
+    if handle.is_null() || group_id.is_null() || user_ids_json.is_null() {
         error::set_last_error(Error::null_pointer());
         return ErrorCode::NullPointer;
     }
 
-    let channel_id_str = {
-        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+    let group_id_str = {
+        match std::ffi::CStr::from_ptr(group_id).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return ErrorCode::InvalidUtf8;
+            }
+        }
+    };
+
+    let user_ids_json_str = {
+        match std::ffi::CStr::from_ptr(user_ids_json).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -5081,9 +10524,20 @@ pub unsafe extern "C" fn communicator_platform_mute_channel(
         }
     };
 
+    let user_ids: Vec<String> = match serde_json::from_str(user_ids_json_str) {
+        Ok(v) => v,
+        Err(e) => {
+            error::set_last_error(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid user IDs JSON: {e}"),
+            ));
+            return ErrorCode::InvalidArgument;
+        }
+    };
+
     let platform = &**handle;
 
-    match runtime::block_on(platform.mute_channel(channel_id_str)) {
+    match runtime::block_on(platform.remove_user_group_members(group_id_str, &user_ids)) {
         Ok(()) => ErrorCode::Success,
         Err(e) => {
             let code = e.code;
@@ -5093,164 +10547,213 @@ pub unsafe extern "C" fn communicator_platform_mute_channel(
     }
 }
 
-/// FFI function: Unmute a channel
-/// Returns error code indicating success or failure
+/// FFI function: Get the user groups that can be @-mentioned in a team
+/// Returns a JSON array string of UserGroup objects
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_unmute_channel(
+pub unsafe extern "C" fn communicator_platform_get_team_user_groups(
     handle: PlatformHandle,
-    channel_id: *const c_char,
-) -> ErrorCode {
+    team_id: *const c_char,
+    page: u32,
+    per_page: u32,
+) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || channel_id.is_null() {
+    if handle.is_null() || team_id.is_null() {
         error::set_last_error(Error::null_pointer());
-        return ErrorCode::NullPointer;
+        return std::ptr::null_mut();
     }
 
-    let channel_id_str = {
-        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
+                return std::ptr::null_mut();
             }
         }
     };
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.unmute_channel(channel_id_str)) {
-        Ok(()) => ErrorCode::Success,
+    match runtime::block_on(platform.get_team_user_groups(team_id_str, page, per_page)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize groups: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
         Err(e) => {
-            let code = e.code;
             error::set_last_error(e);
-            code
+            std::ptr::null_mut()
         }
     }
 }
 
-/// FFI function: Update channel notification properties from JSON
-/// Returns error code indicating success or failure
+/// FFI function: Suggest user groups for a partially typed @-mention
+/// Returns a JSON array string of UserGroup objects
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_update_channel_notify_props(
+pub unsafe extern "C" fn communicator_platform_autocomplete_user_groups(
     handle: PlatformHandle,
-    channel_id: *const c_char,
-    notify_props_json: *const c_char,
-) -> ErrorCode {
+    team_id: *const c_char,
+    prefix: *const c_char,
+) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || channel_id.is_null() || notify_props_json.is_null() {
+    if handle.is_null() || team_id.is_null() || prefix.is_null() {
         error::set_last_error(Error::null_pointer());
-        return ErrorCode::NullPointer;
+        return std::ptr::null_mut();
     }
 
-    let channel_id_str = {
-        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+    let team_id_str = {
+        match std::ffi::CStr::from_ptr(team_id).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
+                return std::ptr::null_mut();
             }
         }
     };
 
-    let notify_props_json_str = {
-        match std::ffi::CStr::from_ptr(notify_props_json).to_str() {
+    let prefix_str = {
+        match std::ffi::CStr::from_ptr(prefix).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
+                return std::ptr::null_mut();
             }
         }
     };
 
     let platform = &**handle;
 
-    match runtime::block_on(
-        platform.update_channel_notify_props(channel_id_str, notify_props_json_str),
-    ) {
-        Ok(()) => ErrorCode::Success,
+    match runtime::block_on(platform.autocomplete_user_groups(team_id_str, prefix_str)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize groups: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
         Err(e) => {
-            let code = e.code;
             error::set_last_error(e);
-            code
+            std::ptr::null_mut()
         }
     }
 }
 
 // ============================================================================
-// Channel Read State Management FFI
+// System Administration
 // ============================================================================
 
-/// FFI function: Mark a channel as viewed (read)
-/// Returns error code indicating success or failure
+/// FFI function: Get the server configuration (administrators only)
+///
+/// Returns the platform-specific configuration as a JSON object
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_view_channel(
+pub unsafe extern "C" fn communicator_platform_admin_get_config(
     handle: PlatformHandle,
-    channel_id: *const c_char,
-) -> ErrorCode {
+) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || channel_id.is_null() {
+    if handle.is_null() {
         error::set_last_error(Error::null_pointer());
-        return ErrorCode::NullPointer;
+        return std::ptr::null_mut();
     }
 
-    let channel_id_str = {
-        match std::ffi::CStr::from_ptr(channel_id).to_str() {
-            Ok(s) => s,
-            Err(_) => {
-                error::set_last_error(Error::invalid_utf8());
-                return ErrorCode::InvalidUtf8;
-            }
-        }
-    };
-
     let platform = &**handle;
 
-    match runtime::block_on(platform.view_channel(channel_id_str)) {
-        Ok(()) => ErrorCode::Success,
+    match runtime::block_on(platform.get_server_config()) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize configuration: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
         Err(e) => {
-            let code = e.code;
             error::set_last_error(e);
-            code
+            std::ptr::null_mut()
         }
     }
 }
 
-/// FFI function: Get unread information for a channel
-/// Returns a JSON string with unread counts or NULL on error
-/// The returned string must be freed with communicator_free_string()
+/// FFI function: Change part of the server configuration (administrators only)
+///
+/// Returns the full configuration after the change as a JSON object
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_get_channel_unread(
+pub unsafe extern "C" fn communicator_platform_admin_patch_config(
     handle: PlatformHandle,
-    channel_id: *const c_char,
+    patch_json: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || channel_id.is_null() {
+    if handle.is_null() || patch_json.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let channel_id_str = {
-        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+    let patch_json_str = {
+        match std::ffi::CStr::from_ptr(patch_json).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -5259,104 +10762,109 @@ pub unsafe extern "C" fn communicator_platform_get_channel_unread(
         }
     };
 
-    let platform = &**handle;
-
-    let unread_info = match runtime::block_on(platform.get_channel_unread(channel_id_str)) {
-        Ok(info) => info,
-        Err(e) => {
-            error::set_last_error(e);
-            return std::ptr::null_mut();
-        }
-    };
-
-    // Serialize to JSON
-    let json = match serde_json::to_string(&unread_info) {
-        Ok(j) => j,
+    let patch: serde_json::Value = match serde_json::from_str(patch_json_str) {
+        Ok(v) => v,
         Err(e) => {
             error::set_last_error(Error::new(
-                ErrorCode::Unknown,
-                format!("Failed to serialize unread info: {e}"),
+                ErrorCode::InvalidArgument,
+                format!("Invalid configuration patch JSON: {e}"),
             ));
             return std::ptr::null_mut();
         }
     };
 
-    match CString::new(json) {
-        Ok(c_string) => c_string.into_raw(),
-        Err(_) => {
-            error::set_last_error(Error::invalid_utf8());
+    let platform = &**handle;
+
+    match runtime::block_on(platform.patch_server_config(patch)) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize configuration: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
             std::ptr::null_mut()
         }
     }
 }
 
-/// FFI function: Get unread counts for all channels in a team
-/// Returns a JSON string with array of unread info or NULL on error
-/// The returned string must be freed with communicator_free_string()
+/// FFI function: Get usage statistics of the whole server (administrators only)
+///
+/// Returns a JSON SystemStats object
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
 #[no_mangle]
 ///
 /// # Safety
 /// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-pub unsafe extern "C" fn communicator_platform_get_team_unreads(
+pub unsafe extern "C" fn communicator_platform_admin_get_system_stats(
     handle: PlatformHandle,
-    team_id: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || team_id.is_null() {
+    if handle.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let team_id_str = {
-        match std::ffi::CStr::from_ptr(team_id).to_str() {
-            Ok(s) => s,
-            Err(_) => {
-                error::set_last_error(Error::invalid_utf8());
-                return std::ptr::null_mut();
-            }
-        }
-    };
-
     let platform = &**handle;
 
-    let unreads = match runtime::block_on(platform.get_team_unreads(team_id_str)) {
-        Ok(list) => list,
+    match runtime::block_on(platform.get_system_stats()) {
+        Ok(result) => match serde_json::to_string(&result) {
+            Ok(json) => match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => {
+                    error::set_last_error(Error::new(
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
+                    ));
+                    std::ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::Unknown,
+                    format!("Failed to serialize system stats: {e}"),
+                ));
+                std::ptr::null_mut()
+            }
+        },
         Err(e) => {
             error::set_last_error(e);
-            return std::ptr::null_mut();
-        }
-    };
-
-    // Serialize to JSON
-    let json = match serde_json::to_string(&unreads) {
-        Ok(j) => j,
-        Err(e) => {
-            error::set_last_error(Error::new(
-                ErrorCode::Unknown,
-                format!("Failed to serialize team unreads: {e}"),
-            ));
-            return std::ptr::null_mut();
-        }
-    };
-
-    match CString::new(json) {
-        Ok(c_string) => c_string.into_raw(),
-        Err(_) => {
-            error::set_last_error(Error::invalid_utf8());
             std::ptr::null_mut()
         }
     }
 }
 
-/// FFI function: Get unread counts for all channels across all teams
+/// FFI function: Get a page of the server log (administrators only)
+///
+/// Returns a JSON array of raw log lines, oldest first
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
+#[no_mangle]
 ///
 /// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
-#[no_mangle]
-pub unsafe extern "C" fn communicator_platform_get_all_unreads(
+pub unsafe extern "C" fn communicator_platform_admin_get_logs(
     handle: PlatformHandle,
+    page: u32,
+    per_page: u32,
 ) -> *mut c_char {
     error::clear_last_error();
 
@@ -5367,14 +10875,14 @@ pub unsafe extern "C" fn communicator_platform_get_all_unreads(
 
     let platform = &**handle;
 
-    match runtime::block_on(platform.get_all_unreads()) {
-        Ok(unreads) => match serde_json::to_string(&unreads) {
+    match runtime::block_on(platform.get_server_logs(page, per_page)) {
+        Ok(result) => match serde_json::to_string(&result) {
             Ok(json) => match CString::new(json) {
                 Ok(c_string) => c_string.into_raw(),
                 Err(_) => {
                     error::set_last_error(Error::new(
-                        ErrorCode::Unknown,
-                        "Failed to convert result to C string",
+                        ErrorCode::OutOfMemory,
+                        "Failed to allocate string",
                     ));
                     std::ptr::null_mut()
                 }
@@ -5382,7 +10890,7 @@ pub unsafe extern "C" fn communicator_platform_get_all_unreads(
             Err(e) => {
                 error::set_last_error(Error::new(
                     ErrorCode::Unknown,
-                    &format!("Failed to serialize unreads: {}", e),
+                    format!("Failed to serialize logs: {e}"),
                 ));
                 std::ptr::null_mut()
             }
@@ -5394,26 +10902,97 @@ pub unsafe extern "C" fn communicator_platform_get_all_unreads(
     }
 }
 
-/// FFI function: Get unread posts in a channel
+// ============================================================================
+// Schema Samples
+// ============================================================================
+
+/// FFI function: List the type names accepted by the schema functions
+/// Returns a JSON array of strings
+/// The caller must free the returned string using communicator_free_string()
+#[no_mangle]
+pub extern "C" fn communicator_schema_names() -> *mut c_char {
+    error::clear_last_error();
+
+    let names = serde_json::Value::from(schema::names());
+    match CString::new(names.to_string()) {
+        Ok(c_string) => c_string.into_raw(),
+        Err(_) => {
+            error::set_last_error(Error::new(
+                ErrorCode::OutOfMemory,
+                "Failed to allocate string",
+            ));
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Get the canonical, fully populated JSON sample of a type
+/// Used by bindings to generate golden files for their own structs
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error (ErrorCode::NotFound for an unknown type name)
+#[no_mangle]
 ///
 /// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
 /// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_schema_sample(type_name: *const c_char) -> *mut c_char {
+    error::clear_last_error();
+
+    if type_name.is_null() {
+        error::set_last_error(Error::null_pointer());
+        return std::ptr::null_mut();
+    }
+
+    let type_name_str = {
+        match std::ffi::CStr::from_ptr(type_name).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
+
+    match schema::sample(type_name_str) {
+        Ok(value) => match CString::new(value.to_string()) {
+            Ok(c_string) => c_string.into_raw(),
+            Err(_) => {
+                error::set_last_error(Error::new(
+                    ErrorCode::OutOfMemory,
+                    "Failed to allocate string",
+                ));
+                std::ptr::null_mut()
+            }
+        },
+        Err(e) => {
+            error::set_last_error(e);
+            std::ptr::null_mut()
+        }
+    }
+}
+
+/// FFI function: Decode JSON as the native type and encode it again
+/// Fields unknown to the native type are dropped from the result
+/// The caller must free the returned string using communicator_free_string()
+/// Returns NULL on error
 #[no_mangle]
-pub unsafe extern "C" fn communicator_platform_get_unread_posts(
-    handle: PlatformHandle,
-    channel_id: *const c_char,
-    limit_after: usize,
-    limit_before: usize,
+///
+/// # Safety
+/// This function is unsafe because it deals with raw pointers from C.
+/// The caller must ensure all pointer arguments are valid.
+pub unsafe extern "C" fn communicator_schema_roundtrip(
+    type_name: *const c_char,
+    json: *const c_char,
 ) -> *mut c_char {
     error::clear_last_error();
 
-    if handle.is_null() || channel_id.is_null() {
+    if type_name.is_null() || json.is_null() {
         error::set_last_error(Error::null_pointer());
         return std::ptr::null_mut();
     }
 
-    let channel_id_str = {
-        match std::ffi::CStr::from_ptr(channel_id).to_str() {
+    let type_name_str = {
+        match std::ffi::CStr::from_ptr(type_name).to_str() {
             Ok(s) => s,
             Err(_) => {
                 error::set_last_error(Error::invalid_utf8());
@@ -5422,15 +11001,23 @@ pub unsafe extern "C" fn communicator_platform_get_unread_posts(
         }
     };
 
-    let platform = &**handle;
+    let json_str = {
+        match std::ffi::CStr::from_ptr(json).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                error::set_last_error(Error::invalid_utf8());
+                return std::ptr::null_mut();
+            }
+        }
+    };
 
-    match runtime::block_on(platform.get_unread_posts(channel_id_str, limit_after, limit_before)) {
-        Ok(json) => match CString::new(json) {
+    match schema::roundtrip(type_name_str, json_str) {
+        Ok(value) => match CString::new(value.to_string()) {
             Ok(c_string) => c_string.into_raw(),
             Err(_) => {
                 error::set_last_error(Error::new(
-                    ErrorCode::Unknown,
-                    "Failed to convert result to C string",
+                    ErrorCode::OutOfMemory,
+                    "Failed to allocate string",
                 ));
                 std::ptr::null_mut()
             }
@@ -0,0 +1,166 @@
+//! Diagnostics delivered to the log callbacks of contexts
+//!
+//! Platforms aren't tied to a context, so what the library logs about
+//! connections, the WebSocket, retries and platform calls goes to the log
+//! callback of every context that has one. Use the `diag!` macro, which only
+//! formats the message when some callback will receive it.
+
+use std::cell::RefCell;
+use std::os::raw::c_void;
+use std::sync::atomic::{AtomicUsize, Ordering};
+use std::sync::{Arc, Condvar, Mutex};
+
+use crate::context::{LogCallback, LogLevel};
+
+/// A log callback and the user data it is called with
+pub(crate) struct LogSink {
+    callback: LogCallback,
+    user_data: *mut c_void,
+    /// Deliveries in progress, waited for before the sink is removed
+    in_flight: Mutex<usize>,
+    idle: Condvar,
+}
+
+// The user data is only handed back to the callback, which the C API
+// documents may be called from the library's threads
+unsafe impl Send for LogSink {}
+unsafe impl Sync for LogSink {}
+
+thread_local! {
+    /// Sinks the current thread is delivering to, innermost last
+    static DELIVERING: RefCell<Vec<*const LogSink>> = const { RefCell::new(Vec::new()) };
+}
+
+impl LogSink {
+    pub(crate) fn new(callback: LogCallback, user_data: *mut c_void) -> Arc<Self> {
+        Arc::new(LogSink {
+            callback,
+            user_data,
+            in_flight: Mutex::new(0),
+            idle: Condvar::new(),
+        })
+    }
+
+    /// Call the callback with a message
+    ///
+    /// Messages logged while the thread is inside a callback are dropped, so
+    /// a callback calling back into the library can't feed itself.
+    pub(crate) fn deliver(self: &Arc<Self>, level: LogLevel, message: &str) {
+        if DELIVERING.with(|d| !d.borrow().is_empty()) {
+            return;
+        }
+        let Ok(c_string) = std::ffi::CString::new(message) else {
+            return;
+        };
+
+        *lock(&self.in_flight) += 1;
+        DELIVERING.with(|d| d.borrow_mut().push(Arc::as_ptr(self)));
+        (self.callback)(level, c_string.as_ptr(), self.user_data);
+        DELIVERING.with(|d| d.borrow_mut().pop());
+
+        let mut in_flight = lock(&self.in_flight);
+        *in_flight -= 1;
+        if *in_flight == 0 {
+            self.idle.notify_all();
+        }
+    }
+
+    /// Wait for the deliveries in progress on other threads to finish
+    ///
+    /// Afterwards the user data is no longer used, and the caller may free it.
+    fn wait_idle(self: &Arc<Self>) {
+        let ptr = Arc::as_ptr(self);
+        // A callback removing its own sink can't wait for itself
+        let own = DELIVERING.with(|d| d.borrow().iter().filter(|&&p| p == ptr).count());
+        let mut in_flight = lock(&self.in_flight);
+        while *in_flight > own {
+            in_flight = self.idle.wait(in_flight).unwrap_or_else(|e| e.into_inner());
+        }
+    }
+}
+
+/// Lock a mutex, carrying on if a callback panicked while it was held
+fn lock<T>(mutex: &Mutex<T>) -> std::sync::MutexGuard<'_, T> {
+    mutex.lock().unwrap_or_else(|e| e.into_inner())
+}
+
+static SINKS: Mutex<Vec<Arc<LogSink>>> = Mutex::new(Vec::new());
+static SINK_COUNT: AtomicUsize = AtomicUsize::new(0);
+
+/// Start delivering the library's diagnostics to a sink
+pub(crate) fn add_sink(sink: &Arc<LogSink>) {
+    let mut sinks = lock(&SINKS);
+    sinks.push(Arc::clone(sink));
+    SINK_COUNT.store(sinks.len(), Ordering::Relaxed);
+}
+
+/// Stop delivering to a sink, returning once no delivery to it is in progress
+pub(crate) fn remove_sink(sink: &Arc<LogSink>) {
+    {
+        let mut sinks = lock(&SINKS);
+        sinks.retain(|s| !Arc::ptr_eq(s, sink));
+        SINK_COUNT.store(sinks.len(), Ordering::Relaxed);
+    }
+    sink.wait_idle();
+}
+
+/// Whether any log callback is set, so messages need formatting
+pub(crate) fn enabled() -> bool {
+    SINK_COUNT.load(Ordering::Relaxed) > 0
+}
+
+/// Deliver a diagnostic to every context's log callback
+pub(crate) fn log(level: LogLevel, message: &str) {
+    // Callbacks run without the lock, so they may set or clear callbacks
+    let sinks = lock(&SINKS).clone();
+    for sink in &sinks {
+        sink.deliver(level, message);
+    }
+}
+
+/// Log a diagnostic to the contexts' log callbacks, formatting it only if
+/// one is set, e.g. `diag!(Warning, "reconnecting in {delay}ms")`
+macro_rules! diag {
+    ($level:ident, $($arg:tt)*) => {
+        if $crate::logging::enabled() {
+            $crate::logging::log($crate::context::LogLevel::$level, &format!($($arg)*));
+        }
+    };
+}
+pub(crate) use diag;
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use std::os::raw::c_char;
+
+    thread_local! {
+        static RECEIVED: RefCell<Vec<(LogLevel, String)>> = const { RefCell::new(Vec::new()) };
+    }
+
+    extern "C" fn record(level: LogLevel, message: *const c_char, _user_data: *mut c_void) {
+        let message = unsafe { std::ffi::CStr::from_ptr(message) }
+            .to_string_lossy()
+            .into_owned();
+        // Logging from inside the callback is dropped rather than recursing
+        log(LogLevel::Debug, "nested");
+        RECEIVED.with(|r| r.borrow_mut().push((level, message)));
+    }
+
+    #[test]
+    fn test_sink_receives_diagnostics_until_removed() {
+        let sink = LogSink::new(record, std::ptr::null_mut());
+        add_sink(&sink);
+        assert!(enabled());
+        diag!(Warning, "reconnecting in {}ms", 500);
+        remove_sink(&sink);
+        log(LogLevel::Info, "after removal");
+
+        let received = RECEIVED.with(|r| r.borrow().clone());
+        assert_eq!(
+            received,
+            vec![(LogLevel::Warning, "reconnecting in 500ms".to_string())]
+        );
+        assert_eq!(*lock(&sink.in_flight), 0);
+    }
+}
@@ -0,0 +1,129 @@
+//! Channel bookmark operations for Mattermost
+
+use super::client::MattermostClient;
+use super::compat::{unsupported_if_missing, ServerVersion};
+use super::types::MattermostChannelBookmark;
+use crate::error::Result;
+use crate::types::NewChannelBookmark;
+
+const BOOKMARKS_FEATURE: &str = "Channel bookmarks";
+/// First server version with channel bookmarks
+const BOOKMARKS_MIN_VERSION: ServerVersion = ServerVersion::new(9, 5, 0);
+
+impl MattermostClient {
+    /// Get the bookmarks of a channel
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel
+    /// * `since` - Only return bookmarks added, updated or deleted after this
+    ///   timestamp (milliseconds). Deleted bookmarks are only included when set.
+    ///
+    /// # Returns
+    /// A Result containing the channel's bookmarks, or an UnsupportedByServer
+    /// error if the server predates bookmarks
+    ///
+    /// # API Endpoint
+    /// GET /channels/{channel_id}/bookmarks
+    pub async fn get_channel_bookmarks(
+        &self,
+        channel_id: &str,
+        since: Option<i64>,
+    ) -> Result<Vec<MattermostChannelBookmark>> {
+        self.require_server_version(BOOKMARKS_FEATURE, BOOKMARKS_MIN_VERSION)
+            .await?;
+        let endpoint = match since {
+            Some(since) => format!("/channels/{channel_id}/bookmarks?bookmarks_since={since}"),
+            None => format!("/channels/{channel_id}/bookmarks"),
+        };
+        let response = self.get(&endpoint).await?;
+        self.handle_response(response)
+            .await
+            .map_err(|e| unsupported_if_missing(BOOKMARKS_FEATURE, e))
+    }
+
+    /// Create a bookmark in a channel
+    ///
+    /// File bookmarks must reference a file that has been uploaded to the
+    /// channel but not yet attached to a post.
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel
+    /// * `bookmark` - The bookmark to create
+    ///
+    /// # Returns
+    /// A Result containing the created bookmark
+    ///
+    /// # API Endpoint
+    /// POST /channels/{channel_id}/bookmarks
+    pub async fn create_channel_bookmark(
+        &self,
+        channel_id: &str,
+        bookmark: &NewChannelBookmark,
+    ) -> Result<MattermostChannelBookmark> {
+        bookmark.validate()?;
+
+        self.require_server_version(BOOKMARKS_FEATURE, BOOKMARKS_MIN_VERSION)
+            .await?;
+        let endpoint = format!("/channels/{channel_id}/bookmarks");
+        let response = self.post(&endpoint, bookmark).await?;
+        self.handle_response(response)
+            .await
+            .map_err(|e| unsupported_if_missing(BOOKMARKS_FEATURE, e))
+    }
+
+    /// Delete a bookmark from a channel
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel
+    /// * `bookmark_id` - The ID of the bookmark to delete
+    ///
+    /// # Returns
+    /// A Result containing the deleted bookmark
+    ///
+    /// # API Endpoint
+    /// DELETE /channels/{channel_id}/bookmarks/{bookmark_id}
+    pub async fn delete_channel_bookmark(
+        &self,
+        channel_id: &str,
+        bookmark_id: &str,
+    ) -> Result<MattermostChannelBookmark> {
+        self.require_server_version(BOOKMARKS_FEATURE, BOOKMARKS_MIN_VERSION)
+            .await?;
+        let endpoint = format!("/channels/{channel_id}/bookmarks/{bookmark_id}");
+        let response = self.delete(&endpoint).await?;
+        self.handle_response(response)
+            .await
+            .map_err(|e| unsupported_if_missing(BOOKMARKS_FEATURE, e))
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_bookmark_deserialization() {
+        let json = r#"{
+            "id": "bm1",
+            "create_at": 1700000000000,
+            "update_at": 1700000000000,
+            "delete_at": 0,
+            "channel_id": "ch1",
+            "owner_id": "user1",
+            "file_id": "",
+            "display_name": "Docs",
+            "sort_order": 0,
+            "link_url": "https://example.com",
+            "image_url": "",
+            "emoji": "",
+            "type": "link",
+            "original_id": "",
+            "parent_id": ""
+        }"#;
+
+        let bookmark: MattermostChannelBookmark = serde_json::from_str(json).unwrap();
+        assert_eq!(bookmark.bookmark_type, "link");
+        assert_eq!(bookmark.link_url, "https://example.com");
+        assert!(bookmark.file.is_none());
+    }
+}
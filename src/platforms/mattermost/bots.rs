@@ -0,0 +1,85 @@
+//! Bot account operations for Mattermost
+
+use super::client::MattermostClient;
+use super::types::MattermostBot;
+use crate::error::Result;
+use crate::types::{BotPatch, NewBot};
+
+impl MattermostClient {
+    /// Create a bot account
+    ///
+    /// # Arguments
+    /// * `bot` - The username and profile of the bot
+    ///
+    /// # Returns
+    /// A Result containing the created bot or an Error
+    ///
+    /// # Notes
+    /// Requires `create_bot` permission; the current user becomes the owner
+    pub async fn create_bot(&self, bot: &NewBot) -> Result<MattermostBot> {
+        let response = self.post("/bots", bot).await?;
+        self.handle_response(response).await
+    }
+
+    /// Partially update a bot account
+    ///
+    /// # Arguments
+    /// * `bot_user_id` - The user ID of the bot
+    /// * `patch` - The fields to change
+    ///
+    /// # Returns
+    /// A Result containing the updated bot or an Error
+    ///
+    /// # Notes
+    /// Requires `manage_bots` permission
+    pub async fn patch_bot(&self, bot_user_id: &str, patch: &BotPatch) -> Result<MattermostBot> {
+        let endpoint = format!("/bots/{bot_user_id}");
+        let response = self.put(&endpoint, patch).await?;
+        self.handle_response(response).await
+    }
+
+    /// Disable a bot account, deactivating its user entry
+    ///
+    /// # Arguments
+    /// * `bot_user_id` - The user ID of the bot
+    ///
+    /// # Returns
+    /// A Result containing the disabled bot or an Error
+    pub async fn disable_bot(&self, bot_user_id: &str) -> Result<MattermostBot> {
+        let endpoint = format!("/bots/{bot_user_id}/disable");
+        let response = self.post(&endpoint, &serde_json::json!({})).await?;
+        self.handle_response(response).await
+    }
+
+    /// Transfer ownership of a bot account to another user
+    ///
+    /// # Arguments
+    /// * `bot_user_id` - The user ID of the bot
+    /// * `user_id` - The ID of the new owner
+    ///
+    /// # Returns
+    /// A Result containing the updated bot or an Error
+    pub async fn assign_bot(&self, bot_user_id: &str, user_id: &str) -> Result<MattermostBot> {
+        let endpoint = format!("/bots/{bot_user_id}/assign/{user_id}");
+        let response = self.post(&endpoint, &serde_json::json!({})).await?;
+        self.handle_response(response).await
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_bot_endpoints() {
+        let client = MattermostClient::new("https://mattermost.example.com").unwrap();
+        assert_eq!(
+            client.api_url("/bots/bot123/disable"),
+            "https://mattermost.example.com/api/v4/bots/bot123/disable"
+        );
+        assert_eq!(
+            client.api_url("/bots/bot123/assign/user456"),
+            "https://mattermost.example.com/api/v4/bots/bot123/assign/user456"
+        );
+    }
+}
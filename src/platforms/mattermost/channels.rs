@@ -1,9 +1,12 @@
+use std::collections::HashMap;
+
 use crate::error::Result;
 
 use super::client::MattermostClient;
 use super::types::{
     ChannelMember, ChannelUnreadInfo, ChannelViewRequest, ChannelViewResponse,
-    CreateDirectChannelRequest, CreateGroupChannelRequest, MattermostChannel, PostList, TeamUnread,
+    CreateDirectChannelRequest, CreateGroupChannelRequest, MattermostChannel, MattermostUser,
+    PostList, TeamUnread,
 };
 
 /// Parse a direct message channel ID to extract participant user IDs
@@ -45,6 +48,38 @@ pub fn get_dm_partner_id(channel_id: &str, current_user_id: &str) -> Option<Stri
     None
 }
 
+/// Get the sorted member IDs of a group message channel from its users
+///
+/// # Arguments
+/// * `users` - The channel's users, as returned by `get_group_channel_users`
+/// * `current_user_id` - The current user's ID, added since the server may leave it out
+pub fn group_member_ids(users: &[MattermostUser], current_user_id: Option<&str>) -> Vec<String> {
+    let mut member_ids: Vec<String> = users
+        .iter()
+        .map(|u| u.id.clone())
+        .chain(current_user_id.map(str::to_string))
+        .collect();
+    member_ids.sort();
+    member_ids.dedup();
+    member_ids
+}
+
+/// Build the channel name Mattermost uses for a DM between two users
+///
+/// # Arguments
+/// * `user_id_1` - One participant's user ID
+/// * `user_id_2` - The other participant's user ID
+///
+/// # Returns
+/// The DM channel name in the format `{lower_user_id}__{higher_user_id}`
+pub fn dm_channel_name(user_id_1: &str, user_id_2: &str) -> String {
+    if user_id_1 <= user_id_2 {
+        format!("{user_id_1}__{user_id_2}")
+    } else {
+        format!("{user_id_2}__{user_id_1}")
+    }
+}
+
 impl MattermostClient {
     /// Get all channels for the current user in a specific team
     ///
@@ -90,6 +125,123 @@ impl MattermostClient {
         self.handle_response(response).await
     }
 
+    /// Get a page of a team's public channels, whether or not the user is a member
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team
+    /// * `page` - The page number to retrieve (0-indexed)
+    /// * `per_page` - Number of channels per page
+    ///
+    /// # Returns
+    /// A Result containing the channels on the page or an Error
+    pub async fn get_public_channels(
+        &self,
+        team_id: &str,
+        page: u32,
+        per_page: u32,
+    ) -> Result<Vec<MattermostChannel>> {
+        let endpoint = format!("/teams/{team_id}/channels?page={page}&per_page={per_page}");
+        let response = self.get(&endpoint).await?;
+        self.handle_response(response).await
+    }
+
+    /// Get a page of a team's archived channels
+    ///
+    /// Archived public channels are always listed; archived private channels
+    /// only if the user is a member.
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team
+    /// * `page` - The page number to retrieve (0-indexed)
+    /// * `per_page` - Number of channels per page
+    ///
+    /// # Returns
+    /// A Result containing the channels on the page or an Error
+    pub async fn get_archived_channels(
+        &self,
+        team_id: &str,
+        page: u32,
+        per_page: u32,
+    ) -> Result<Vec<MattermostChannel>> {
+        let endpoint = format!("/teams/{team_id}/channels/deleted?page={page}&per_page={per_page}");
+        let response = self.get(&endpoint).await?;
+        self.handle_response(response).await
+    }
+
+    /// Get all channels the current user belongs to across all teams
+    ///
+    /// # Returns
+    /// A Result containing a list of channels (including DMs and group messages) or an Error
+    pub async fn get_all_channels_for_user(&self) -> Result<Vec<MattermostChannel>> {
+        let response = self.get("/users/me/channels").await?;
+        self.handle_response(response).await
+    }
+
+    /// Find an existing direct message channel with another user without creating one
+    ///
+    /// # Arguments
+    /// * `user_id` - The ID of the other user
+    ///
+    /// # Returns
+    /// A Result containing the DM channel if one exists, None otherwise
+    pub async fn find_direct_channel(&self, user_id: &str) -> Result<Option<MattermostChannel>> {
+        let current_user_id = self.get_user_id().await.ok_or_else(|| {
+            crate::error::Error::new(
+                crate::error::ErrorCode::InvalidState,
+                "User ID not set - ensure you're authenticated",
+            )
+        })?;
+
+        let name = dm_channel_name(&current_user_id, user_id);
+        let channels = self.get_all_channels_for_user().await?;
+
+        Ok(channels
+            .into_iter()
+            .find(|c| c.channel_type.is_direct() && c.name == name))
+    }
+
+    /// Find an existing group message channel with exactly the given users without creating one
+    ///
+    /// # Arguments
+    /// * `user_ids` - The IDs of the other participants (the current user is added automatically)
+    ///
+    /// # Returns
+    /// A Result containing the group channel if one exists, None otherwise
+    pub async fn find_group_channel(
+        &self,
+        user_ids: &[String],
+    ) -> Result<Option<MattermostChannel>> {
+        let current_user_id = self.get_user_id().await.ok_or_else(|| {
+            crate::error::Error::new(
+                crate::error::ErrorCode::InvalidState,
+                "User ID not set - ensure you're authenticated",
+            )
+        })?;
+
+        let mut wanted: Vec<String> = user_ids.to_vec();
+        wanted.push(current_user_id.clone());
+        wanted.sort();
+        wanted.dedup();
+
+        let groups: Vec<MattermostChannel> = self
+            .get_all_channels_for_user()
+            .await?
+            .into_iter()
+            .filter(|c| c.channel_type.is_group())
+            .collect();
+        let group_ids: Vec<String> = groups.iter().map(|c| c.id.clone()).collect();
+        let mut group_users = self.get_group_channel_users(&group_ids).await?;
+
+        for channel in groups {
+            let users = group_users.remove(&channel.id).unwrap_or_default();
+            if group_member_ids(&users, Some(&current_user_id)) == wanted {
+                return Ok(Some(channel));
+            }
+        }
+
+        Ok(None)
+    }
+
     /// Create a direct message channel with another user
     ///
     /// # Arguments
@@ -127,6 +279,25 @@ impl MattermostClient {
         self.handle_response(response).await
     }
 
+    /// Get the users of several group message channels in one request
+    ///
+    /// # Arguments
+    /// * `channel_ids` - The IDs of the group message channels
+    ///
+    /// # Returns
+    /// The users of each channel by channel ID. The server may leave out the
+    /// current user, who is a member of every channel it returns.
+    pub async fn get_group_channel_users(
+        &self,
+        channel_ids: &[String],
+    ) -> Result<HashMap<String, Vec<MattermostUser>>> {
+        if channel_ids.is_empty() {
+            return Ok(HashMap::new());
+        }
+        let response = self.post("/users/group_channels", &channel_ids).await?;
+        self.handle_response(response).await
+    }
+
     /// Get the members of a channel
     ///
     /// # Arguments
@@ -202,6 +373,101 @@ impl MattermostClient {
         }
     }
 
+    /// Join a channel as the current user
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel to join
+    ///
+    /// # Returns
+    /// A Result containing the current user's new membership or an Error
+    pub async fn join_channel(&self, channel_id: &str) -> Result<ChannelMember> {
+        let user_id = self.current_user_id().await?;
+        self.add_channel_member(channel_id, &user_id).await
+    }
+
+    /// Leave a channel as the current user
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel to leave
+    ///
+    /// # Returns
+    /// A Result indicating success or failure
+    pub async fn leave_channel(&self, channel_id: &str) -> Result<()> {
+        let user_id = self.current_user_id().await?;
+        self.remove_channel_member(channel_id, &user_id).await
+    }
+
+    /// Replace a channel member's explicit roles
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel
+    /// * `user_id` - The ID of the member
+    /// * `roles` - Role names, e.g. `["channel_user", "channel_admin"]`
+    ///
+    /// # API Endpoint
+    /// PUT /channels/{channel_id}/members/{user_id}/roles
+    pub async fn update_channel_member_roles(
+        &self,
+        channel_id: &str,
+        user_id: &str,
+        roles: &[String],
+    ) -> Result<()> {
+        let body = serde_json::json!({ "roles": roles.join(" ") });
+        let endpoint = format!("/channels/{channel_id}/members/{user_id}/roles");
+        let response = self.put(&endpoint, &body).await?;
+
+        if response.status().is_success() {
+            Ok(())
+        } else {
+            Err(crate::error::Error::new(
+                crate::error::ErrorCode::NetworkError,
+                format!(
+                    "Failed to update channel member roles: {}",
+                    response.status()
+                ),
+            )
+            .with_http_status(response.status().as_u16()))
+        }
+    }
+
+    /// Set a channel member's scheme-derived roles
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel
+    /// * `user_id` - The ID of the member
+    /// * `scheme_admin` - Whether the member is a channel admin (moderator)
+    /// * `scheme_user` - Whether the member is a regular channel user
+    ///
+    /// # API Endpoint
+    /// PUT /channels/{channel_id}/members/{user_id}/schemeRoles
+    pub async fn update_channel_member_scheme_roles(
+        &self,
+        channel_id: &str,
+        user_id: &str,
+        scheme_admin: bool,
+        scheme_user: bool,
+    ) -> Result<()> {
+        let body = serde_json::json!({
+            "scheme_admin": scheme_admin,
+            "scheme_user": scheme_user,
+        });
+        let endpoint = format!("/channels/{channel_id}/members/{user_id}/schemeRoles");
+        let response = self.put(&endpoint, &body).await?;
+
+        if response.status().is_success() {
+            Ok(())
+        } else {
+            Err(crate::error::Error::new(
+                crate::error::ErrorCode::NetworkError,
+                format!(
+                    "Failed to update channel member scheme roles: {}",
+                    response.status()
+                ),
+            )
+            .with_http_status(response.status().as_u16()))
+        }
+    }
+
     // ========================================================================
     // Channel Read State Management
     // ========================================================================
@@ -293,6 +559,7 @@ impl MattermostClient {
                 channel_id: m.channel_id,
                 msg_count: m.msg_count,
                 mention_count: m.mention_count,
+                urgent_mention_count: m.urgent_mention_count,
                 last_viewed_at: m.last_viewed_at,
             })
             .collect())
@@ -480,6 +747,19 @@ impl MattermostClient {
             ))
         }
     }
+
+    /// Restore (unarchive) a channel archived with `delete_channel`
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the archived channel
+    ///
+    /// # Returns
+    /// A Result containing the restored channel or an Error
+    pub async fn restore_channel(&self, channel_id: &str) -> Result<MattermostChannel> {
+        let endpoint = format!("/channels/{channel_id}/restore");
+        let response = self.post(&endpoint, &serde_json::json!({})).await?;
+        self.handle_response(response).await
+    }
 }
 
 #[cfg(test)]
@@ -499,6 +779,43 @@ mod tests {
             client.api_url("/channels/channel123"),
             "https://mattermost.example.com/api/v4/channels/channel123"
         );
+        assert_eq!(
+            client.api_url("/channels/channel123/restore"),
+            "https://mattermost.example.com/api/v4/channels/channel123/restore"
+        );
+    }
+
+    #[test]
+    fn test_dm_channel_name() {
+        assert_eq!(
+            dm_channel_name("xei6dqz8xfgm7kqzddjziyofyo", "t1pn9rb63fnpjrqibgriijcx4r"),
+            "t1pn9rb63fnpjrqibgriijcx4r__xei6dqz8xfgm7kqzddjziyofyo"
+        );
+        assert_eq!(
+            dm_channel_name("t1pn9rb63fnpjrqibgriijcx4r", "xei6dqz8xfgm7kqzddjziyofyo"),
+            "t1pn9rb63fnpjrqibgriijcx4r__xei6dqz8xfgm7kqzddjziyofyo"
+        );
+    }
+
+    #[test]
+    fn test_group_member_ids() {
+        let users: Vec<MattermostUser> = serde_json::from_value(serde_json::json!([
+            {"id": "carol", "username": "carol", "create_at": 0, "update_at": 0, "delete_at": 0},
+            {"id": "alice", "username": "alice", "create_at": 0, "update_at": 0, "delete_at": 0},
+        ]))
+        .unwrap();
+
+        // The current user is added when the server leaves it out
+        assert_eq!(
+            group_member_ids(&users, Some("bob")),
+            vec!["alice", "bob", "carol"]
+        );
+        // and not repeated when it doesn't
+        assert_eq!(
+            group_member_ids(&users, Some("alice")),
+            vec!["alice", "carol"]
+        );
+        assert_eq!(group_member_ids(&users, None), vec!["alice", "carol"]);
     }
 
     #[test]
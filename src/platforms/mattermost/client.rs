@@ -1,3 +1,4 @@
+use reqwest::header::{HeaderMap, HeaderName, HeaderValue, AUTHORIZATION, USER_AGENT};
 use reqwest::Client;
 use std::sync::Arc;
 use std::time::Duration;
@@ -5,10 +6,14 @@ use tokio::sync::RwLock;
 use url::Url;
 
 use crate::error::{Error, ErrorCode, Result};
+use crate::logging::diag;
+use crate::platforms::platform_trait::{PlatformConfig, PlatformEvent};
 use crate::types::{ConnectionInfo, ConnectionState};
 
 use super::cache::Cache;
-use super::types::{MattermostChannel, MattermostTeam, MattermostUser};
+use super::proxy::ProxyConfig;
+use super::tls::TlsConfig;
+use super::types::{MattermostChannel, MattermostRole, MattermostTeam, MattermostUser};
 
 /// Configuration for caching API responses
 #[derive(Debug, Clone)]
@@ -19,6 +24,8 @@ pub struct CacheConfig {
     pub channel_ttl: Duration,
     /// Time-to-live for team cache entries (default: 10 minutes)
     pub team_ttl: Duration,
+    /// Time-to-live for role and permission cache entries (default: 10 minutes)
+    pub role_ttl: Duration,
     /// Enable caching (default: true)
     pub enable_cache: bool,
 }
@@ -29,6 +36,7 @@ impl Default for CacheConfig {
             user_ttl: Duration::from_secs(300),    // 5 minutes
             channel_ttl: Duration::from_secs(120), // 2 minutes
             team_ttl: Duration::from_secs(600),    // 10 minutes
+            role_ttl: Duration::from_secs(600),    // 10 minutes
             enable_cache: true,
         }
     }
@@ -44,6 +52,291 @@ impl CacheConfig {
     }
 }
 
+/// Network timeouts of a client
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub struct Timeouts {
+    /// Limit on establishing a connection for REST calls (default: None = no limit)
+    pub connect: Option<Duration>,
+    /// Limit on a whole REST call, including the response body (default: 30 seconds)
+    pub request: Option<Duration>,
+    /// Limit on opening the WebSocket, through the upgrade handshake (default: None)
+    pub handshake: Option<Duration>,
+    /// WebSocket silence after which it reconnects (default: None)
+    pub idle: Option<Duration>,
+}
+
+impl Default for Timeouts {
+    fn default() -> Self {
+        Self {
+            connect: None,
+            request: Some(Duration::from_secs(30)),
+            handshake: None,
+            idle: None,
+        }
+    }
+}
+
+impl Timeouts {
+    /// Read the timeouts of a platform configuration; unset or zero values keep the defaults
+    pub fn from_platform_config(config: &PlatformConfig) -> Self {
+        let defaults = Self::default();
+        let millis = |ms: Option<u64>, default: Option<Duration>| {
+            ms.filter(|&ms| ms > 0)
+                .map(Duration::from_millis)
+                .or(default)
+        };
+        Self {
+            connect: millis(config.connect_timeout_ms, defaults.connect),
+            request: millis(config.request_timeout_ms, defaults.request),
+            handshake: millis(config.handshake_timeout_ms, defaults.handshake),
+            idle: millis(config.idle_timeout_ms, defaults.idle),
+        }
+    }
+}
+
+/// User-Agent sent unless the configuration overrides it
+pub const DEFAULT_USER_AGENT: &str = concat!("libcommunicator/", env!("CARGO_PKG_VERSION"));
+
+/// Build the headers sent with every REST and WebSocket request
+///
+/// # Returns
+/// The User-Agent (`user_agent`, or DEFAULT_USER_AGENT) and the configured
+/// `headers`, or an InvalidArgument error for a header that isn't valid or
+/// would replace the session's Authorization
+pub fn request_headers(config: &PlatformConfig) -> Result<HeaderMap> {
+    let invalid = |message: String| Error::new(ErrorCode::InvalidArgument, message);
+
+    let mut headers = HeaderMap::new();
+    let user_agent = config
+        .user_agent
+        .as_deref()
+        .filter(|s| !s.is_empty())
+        .unwrap_or(DEFAULT_USER_AGENT);
+    headers.insert(
+        USER_AGENT,
+        HeaderValue::from_str(user_agent)
+            .map_err(|e| invalid(format!("Invalid user_agent: {e}")))?,
+    );
+
+    for (name, value) in &config.headers {
+        let name = HeaderName::from_bytes(name.as_bytes())
+            .map_err(|e| invalid(format!("Invalid header name {name:?}: {e}")))?;
+        if name == AUTHORIZATION {
+            return Err(invalid(
+                "The Authorization header carries the session token and can't be set".to_string(),
+            ));
+        }
+        let value = HeaderValue::from_str(value)
+            .map_err(|e| invalid(format!("Invalid value for header {name}: {e}")))?;
+        headers.insert(name, value);
+    }
+    Ok(headers)
+}
+
+/// Number of RateLimited events kept until they are polled; older ones are dropped
+const RATE_LIMIT_EVENTS_LIMIT: usize = 100;
+
+/// How requests rejected with HTTP 429 Too Many Requests are retried
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub struct RateLimitPolicy {
+    /// Retries of one request before it fails with ErrorCode::RateLimited
+    /// (default: 3; 0 disables retrying)
+    pub max_retries: u32,
+    /// Longest wait before a retry; a request the server asks to wait longer
+    /// fails right away (default: 60 seconds)
+    pub max_wait: Duration,
+}
+
+impl Default for RateLimitPolicy {
+    fn default() -> Self {
+        Self {
+            max_retries: 3,
+            max_wait: Duration::from_secs(60),
+        }
+    }
+}
+
+impl RateLimitPolicy {
+    /// Read the rate limit policy of a platform configuration; unset values keep the defaults
+    pub fn from_platform_config(config: &PlatformConfig) -> Self {
+        let defaults = Self::default();
+        Self {
+            max_retries: config
+                .rate_limit_max_retries
+                .unwrap_or(defaults.max_retries),
+            max_wait: config
+                .rate_limit_max_wait_ms
+                .filter(|&ms| ms > 0)
+                .map_or(defaults.max_wait, Duration::from_millis),
+        }
+    }
+}
+
+/// Kinds of transient failure a RetryPolicy may retry
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub struct RetryOn {
+    /// Connections that could not be made or broke off (default: true)
+    pub network: bool,
+    /// Requests that ran into the request or connect timeout (default: false)
+    pub timeout: bool,
+    /// 502 Bad Gateway, 503 Service Unavailable and 504 Gateway Timeout
+    /// responses (default: true)
+    pub server_error: bool,
+}
+
+impl Default for RetryOn {
+    fn default() -> Self {
+        Self {
+            network: true,
+            timeout: false,
+            server_error: true,
+        }
+    }
+}
+
+/// How requests that failed for a transient reason are retried
+///
+/// Only failures where the server cannot have acted on the request are
+/// retried for POST; GET, PUT and DELETE are retried for any class in
+/// `retry_on`.
+#[derive(Debug, Clone, Copy, PartialEq)]
+pub struct RetryPolicy {
+    /// Tries of one request, including the first (default: 3; 1 disables retrying)
+    pub max_attempts: u32,
+    /// Wait before the first retry (default: 200 ms)
+    pub initial_backoff: Duration,
+    /// Longest wait between retries (default: 5 seconds)
+    pub max_backoff: Duration,
+    /// Growth of the wait with each retry (default: 2.0)
+    pub multiplier: f64,
+    /// Fraction of each wait that is randomized, from 0 to 1, so clients
+    /// don't retry in lockstep (default: 0.2)
+    pub jitter: f64,
+    /// Failures that are retried
+    pub retry_on: RetryOn,
+}
+
+impl Default for RetryPolicy {
+    fn default() -> Self {
+        Self {
+            max_attempts: 3,
+            initial_backoff: Duration::from_millis(200),
+            max_backoff: Duration::from_secs(5),
+            multiplier: 2.0,
+            jitter: 0.2,
+            retry_on: RetryOn::default(),
+        }
+    }
+}
+
+impl RetryPolicy {
+    /// Read the retry policy of a platform configuration; unset values keep the defaults
+    ///
+    /// # Returns
+    /// The policy, or an InvalidArgument error for an unknown retry class or
+    /// a jitter outside 0 to 1
+    pub fn from_platform_config(config: &PlatformConfig) -> Result<Self> {
+        let defaults = Self::default();
+        let millis = |value: Option<u64>, default: Duration| {
+            value
+                .filter(|&ms| ms > 0)
+                .map_or(default, Duration::from_millis)
+        };
+
+        let jitter = config.retry_jitter.unwrap_or(defaults.jitter);
+        if !(0.0..=1.0).contains(&jitter) {
+            return Err(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("retry_jitter must be between 0 and 1, got {jitter}"),
+            ));
+        }
+
+        let retry_on = match &config.retry_on {
+            Some(classes) => {
+                let mut retry_on = RetryOn {
+                    network: false,
+                    timeout: false,
+                    server_error: false,
+                };
+                for class in classes {
+                    match class.as_str() {
+                        "network" => retry_on.network = true,
+                        "timeout" => retry_on.timeout = true,
+                        "server_error" => retry_on.server_error = true,
+                        other => {
+                            return Err(Error::new(
+                                ErrorCode::InvalidArgument,
+                                format!(
+                                    "Unknown retry class {other:?}; use network, timeout or server_error"
+                                ),
+                            ))
+                        }
+                    }
+                }
+                retry_on
+            }
+            None => defaults.retry_on,
+        };
+
+        Ok(Self {
+            max_attempts: config
+                .retry_max_attempts
+                .filter(|&n| n > 0)
+                .unwrap_or(defaults.max_attempts),
+            initial_backoff: millis(config.retry_initial_backoff_ms, defaults.initial_backoff),
+            max_backoff: millis(config.retry_max_backoff_ms, defaults.max_backoff),
+            multiplier: config
+                .retry_backoff_multiplier
+                .filter(|&m| m >= 1.0)
+                .unwrap_or(defaults.multiplier),
+            jitter,
+            retry_on,
+        })
+    }
+
+    /// Check whether a request that failed to complete may be tried again
+    fn retries_error(&self, method: &str, e: &reqwest::Error) -> bool {
+        let class = if e.is_timeout() {
+            self.retry_on.timeout
+        } else {
+            (e.is_connect() || e.is_request()) && self.retry_on.network
+        };
+        // Without a connection the server never saw the request
+        class && (e.is_connect() || is_idempotent(method))
+    }
+
+    /// Check whether a request answered with `status` may be tried again
+    fn retries_status(&self, method: &str, status: reqwest::StatusCode) -> bool {
+        self.retry_on.server_error && is_idempotent(method) && matches!(status.as_u16(), 502..=504)
+    }
+
+    /// Wait before retrying after the given number of failures
+    ///
+    /// `random` is a sample from 0 to 1 that takes up to `jitter` of the wait away.
+    fn backoff(&self, failures: u32, random: f64) -> Duration {
+        let exponent = failures.saturating_sub(1).min(32) as i32;
+        // Capped in floating point, where a huge wait can't overflow
+        let seconds = (self.initial_backoff.as_secs_f64() * self.multiplier.powi(exponent))
+            .min(self.max_backoff.as_secs_f64());
+        Duration::from_secs_f64(seconds * (1.0 - self.jitter * random))
+    }
+}
+
+/// Check whether repeating a request has the same effect as sending it once
+fn is_idempotent(method: &str) -> bool {
+    matches!(method, "GET" | "PUT" | "DELETE")
+}
+
+/// A random sample from 0 to 1 for backoff jitter
+fn jitter_sample() -> f64 {
+    use std::hash::{BuildHasher, Hasher};
+    // RandomState is seeded randomly per instance, which suffices here
+    let bits = std::collections::hash_map::RandomState::new()
+        .build_hasher()
+        .finish();
+    (bits >> 11) as f64 / (1u64 << 53) as f64
+}
+
 /// Rate limit information from Mattermost API response headers
 #[derive(Debug, Clone)]
 pub struct RateLimitInfo {
@@ -71,14 +364,34 @@ pub struct MattermostClient {
     user_id: Arc<RwLock<Option<String>>>,
     /// Rate limit information from last API response
     rate_limit_info: Arc<RwLock<Option<RateLimitInfo>>>,
+    /// Server version advertised by the last API response
+    server_version: Arc<RwLock<Option<super::compat::ServerVersion>>>,
     /// Cache for user objects
     user_cache: Cache<MattermostUser>,
     /// Cache for channel objects
     channel_cache: Cache<MattermostChannel>,
     /// Cache for team objects
     team_cache: Cache<MattermostTeam>,
+    /// Cache for roles, keyed by role name
+    pub(super) role_cache: Cache<MattermostRole>,
+    /// Cache for effective permissions, keyed by "{user_id}:{channel_id}"
+    pub(super) permission_cache: Cache<Vec<String>>,
     /// Cache configuration
     cache_config: CacheConfig,
+    /// Proxy the REST and WebSocket connections go through
+    proxy: Option<ProxyConfig>,
+    /// TLS settings of the REST and WebSocket connections
+    tls: Option<TlsConfig>,
+    /// Network timeouts
+    timeouts: Timeouts,
+    /// Headers sent with every request, including the User-Agent
+    headers: HeaderMap,
+    /// Retrying of rate limited requests
+    rate_limit_policy: RateLimitPolicy,
+    /// Retrying of requests that failed for a transient reason
+    retry_policy: RetryPolicy,
+    /// RateLimited events not yet polled
+    rate_limit_events: Arc<RwLock<std::collections::VecDeque<PlatformEvent>>>,
 }
 
 impl MattermostClient {
@@ -105,15 +418,9 @@ impl MattermostClient {
         let base_url = Url::parse(base_url)
             .map_err(|e| Error::new(ErrorCode::InvalidArgument, format!("Invalid URL: {e}")))?;
 
-        let http_client = Client::builder()
-            .timeout(std::time::Duration::from_secs(30))
-            .build()
-            .map_err(|e| {
-                Error::new(
-                    ErrorCode::NetworkError,
-                    format!("Failed to create HTTP client: {e}"),
-                )
-            })?;
+        let mut headers = HeaderMap::new();
+        headers.insert(USER_AGENT, HeaderValue::from_static(DEFAULT_USER_AGENT));
+        let http_client = Self::build_http_client(None, None, &Timeouts::default(), &headers)?;
 
         Ok(Self {
             http_client,
@@ -123,13 +430,256 @@ impl MattermostClient {
             team_id: Arc::new(RwLock::new(None)),
             user_id: Arc::new(RwLock::new(None)),
             rate_limit_info: Arc::new(RwLock::new(None)),
+            server_version: Arc::new(RwLock::new(None)),
             user_cache: Cache::new(cache_config.user_ttl),
             channel_cache: Cache::new(cache_config.channel_ttl),
             team_cache: Cache::new(cache_config.team_ttl),
+            role_cache: Cache::new(cache_config.role_ttl),
+            permission_cache: Cache::new(cache_config.role_ttl),
             cache_config,
+            proxy: None,
+            tls: None,
+            timeouts: Timeouts::default(),
+            headers,
+            rate_limit_policy: RateLimitPolicy::default(),
+            retry_policy: RetryPolicy::default(),
+            rate_limit_events: Arc::new(RwLock::new(std::collections::VecDeque::new())),
         })
     }
 
+    /// Build the HTTP client, going through a proxy if one is given
+    ///
+    /// Without a proxy, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment
+    /// variables apply.
+    fn build_http_client(
+        proxy: Option<&ProxyConfig>,
+        tls: Option<&TlsConfig>,
+        timeouts: &Timeouts,
+        headers: &HeaderMap,
+    ) -> Result<Client> {
+        let mut builder = Client::builder().default_headers(headers.clone());
+        if let Some(connect) = timeouts.connect {
+            builder = builder.connect_timeout(connect);
+        }
+        if let Some(request) = timeouts.request {
+            builder = builder.timeout(request);
+        }
+        if let Some(proxy) = proxy {
+            builder = builder.proxy(proxy.reqwest_proxy()?);
+        }
+        if let Some(tls) = tls {
+            builder = builder.use_preconfigured_tls(tls.client_config()?);
+        }
+        builder.build().map_err(|e| {
+            Error::new(
+                ErrorCode::NetworkError,
+                format!("Failed to create HTTP client: {e}"),
+            )
+        })
+    }
+
+    /// Route REST calls through a proxy, or stop doing so with None
+    ///
+    /// Rebuilds the HTTP client, so it must be called before requests are
+    /// made, e.g. before logging in.
+    pub fn set_proxy(&mut self, proxy: Option<ProxyConfig>) -> Result<()> {
+        self.http_client = Self::build_http_client(
+            proxy.as_ref(),
+            self.tls.as_ref(),
+            &self.timeouts,
+            &self.headers,
+        )?;
+        self.proxy = proxy;
+        Ok(())
+    }
+
+    /// Use custom TLS settings, or the defaults again with None
+    ///
+    /// Rebuilds the HTTP client, so it must be called before requests are
+    /// made, e.g. before logging in.
+    pub fn set_tls(&mut self, tls: Option<TlsConfig>) -> Result<()> {
+        self.http_client = Self::build_http_client(
+            self.proxy.as_ref(),
+            tls.as_ref(),
+            &self.timeouts,
+            &self.headers,
+        )?;
+        self.tls = tls;
+        Ok(())
+    }
+
+    /// Get the custom TLS settings
+    pub fn tls(&self) -> Option<&TlsConfig> {
+        self.tls.as_ref()
+    }
+
+    /// Set the network timeouts
+    ///
+    /// Rebuilds the HTTP client, so it must be called before requests are
+    /// made, e.g. before logging in. REST calls that time out fail with
+    /// ErrorCode::Timeout.
+    pub fn set_timeouts(&mut self, timeouts: Timeouts) -> Result<()> {
+        self.http_client = Self::build_http_client(
+            self.proxy.as_ref(),
+            self.tls.as_ref(),
+            &timeouts,
+            &self.headers,
+        )?;
+        self.timeouts = timeouts;
+        Ok(())
+    }
+
+    /// Get the network timeouts
+    pub fn timeouts(&self) -> Timeouts {
+        self.timeouts
+    }
+
+    /// Set the headers sent with every request, as built by request_headers
+    ///
+    /// Rebuilds the HTTP client, so it must be called before requests are
+    /// made, e.g. before logging in.
+    pub fn set_headers(&mut self, headers: HeaderMap) -> Result<()> {
+        self.http_client = Self::build_http_client(
+            self.proxy.as_ref(),
+            self.tls.as_ref(),
+            &self.timeouts,
+            &headers,
+        )?;
+        self.headers = headers;
+        Ok(())
+    }
+
+    /// Get the headers sent with every request
+    pub fn headers(&self) -> &HeaderMap {
+        &self.headers
+    }
+
+    /// Set how requests rejected with HTTP 429 are retried
+    pub fn set_rate_limit_policy(&mut self, policy: RateLimitPolicy) {
+        self.rate_limit_policy = policy;
+    }
+
+    /// Get how requests rejected with HTTP 429 are retried
+    pub fn rate_limit_policy(&self) -> RateLimitPolicy {
+        self.rate_limit_policy
+    }
+
+    /// Set how requests that failed for a transient reason are retried
+    pub fn set_retry_policy(&mut self, policy: RetryPolicy) {
+        self.retry_policy = policy;
+    }
+
+    /// Get how requests that failed for a transient reason are retried
+    pub fn retry_policy(&self) -> RetryPolicy {
+        self.retry_policy
+    }
+
+    /// Take the oldest RateLimited event not yet polled
+    pub async fn take_rate_limit_event(&self) -> Option<PlatformEvent> {
+        self.rate_limit_events.write().await.pop_front()
+    }
+
+    /// Send a request, retrying transient failures as the retry policy allows
+    /// and waiting out HTTP 429 responses as the rate limit policy allows
+    ///
+    /// `build` creates the request afresh for each attempt; the token is added
+    /// here. A response still failing is returned as is, for handle_response
+    /// to turn into an error.
+    pub(crate) async fn send(
+        &self,
+        method: &str,
+        endpoint: &str,
+        build: impl Fn() -> reqwest::RequestBuilder,
+    ) -> Result<reqwest::Response> {
+        let mut attempt = 0;
+        let mut failures = 0;
+        let path = endpoint.split('?').next().unwrap_or_default();
+        loop {
+            let mut request = build();
+            if let Some(token) = self.get_token().await {
+                request = request.bearer_auth(token);
+            }
+            let response = match request.send().await {
+                Ok(response) => response,
+                Err(e) => {
+                    failures += 1;
+                    if failures < self.retry_policy.max_attempts
+                        && self.retry_policy.retries_error(method, &e)
+                    {
+                        let delay = self.retry_policy.backoff(failures, jitter_sample());
+                        diag!(
+                            Warning,
+                            "{method} {path} failed ({e}), retrying in {}ms (attempt {})",
+                            delay.as_millis(),
+                            failures + 1
+                        );
+                        tokio::time::sleep(delay).await;
+                        continue;
+                    }
+                    return Err(request_error(method, e));
+                }
+            };
+            if response.status().is_server_error() {
+                failures += 1;
+                if failures < self.retry_policy.max_attempts
+                    && self.retry_policy.retries_status(method, response.status())
+                {
+                    let delay = self.retry_policy.backoff(failures, jitter_sample());
+                    diag!(
+                        Warning,
+                        "{method} {path} returned {}, retrying in {}ms (attempt {})",
+                        response.status(),
+                        delay.as_millis(),
+                        failures + 1
+                    );
+                    tokio::time::sleep(delay).await;
+                    continue;
+                }
+                return Ok(response);
+            }
+            if response.status() != reqwest::StatusCode::TOO_MANY_REQUESTS {
+                return Ok(response);
+            }
+
+            self.update_rate_limit_info(&response).await;
+            attempt += 1;
+            let delay = rate_limit_delay(response.headers(), attempt, unix_now());
+            let retrying = attempt <= self.rate_limit_policy.max_retries
+                && delay <= self.rate_limit_policy.max_wait;
+
+            if retrying {
+                diag!(
+                    Warning,
+                    "{method} {path} was rate limited, retrying in {}ms (attempt {})",
+                    delay.as_millis(),
+                    attempt + 1
+                );
+            } else {
+                diag!(Error, "{method} {path} was rate limited, giving up");
+            }
+            let mut events = self.rate_limit_events.write().await;
+            if events.len() >= RATE_LIMIT_EVENTS_LIMIT {
+                events.pop_front();
+            }
+            events.push_back(PlatformEvent::RateLimited {
+                endpoint: format!("{method} /{}", path.trim_start_matches('/')),
+                attempt,
+                retry_after_ms: retrying.then(|| delay.as_millis() as u64),
+            });
+            drop(events);
+
+            if !retrying {
+                return Ok(response);
+            }
+            tokio::time::sleep(delay).await;
+        }
+    }
+
+    /// Get the configured proxy
+    pub fn proxy(&self) -> Option<&ProxyConfig> {
+        self.proxy.as_ref()
+    }
+
     /// Set the authentication token (session token or Personal Access Token)
     pub async fn set_token(&self, token: String) {
         let mut t = self.token.write().await;
@@ -310,16 +860,8 @@ impl MattermostClient {
     /// A Result containing the reqwest::Response or an Error
     pub async fn get(&self, endpoint: &str) -> Result<reqwest::Response> {
         let url = self.api_url(endpoint);
-        let mut request = self.http_client.get(&url);
-
-        if let Some(token) = self.get_token().await {
-            request = request.bearer_auth(token);
-        }
-
-        request
-            .send()
+        self.send("GET", endpoint, || self.http_client.get(&url))
             .await
-            .map_err(|e| Error::new(ErrorCode::NetworkError, format!("GET request failed: {e}")))
     }
 
     /// Make a POST request to the Mattermost API
@@ -336,17 +878,8 @@ impl MattermostClient {
         body: &T,
     ) -> Result<reqwest::Response> {
         let url = self.api_url(endpoint);
-        let mut request = self.http_client.post(&url);
-
-        if let Some(token) = self.get_token().await {
-            request = request.bearer_auth(token);
-        }
-
-        request
-            .json(body)
-            .send()
+        self.send("POST", endpoint, || self.http_client.post(&url).json(body))
             .await
-            .map_err(|e| Error::new(ErrorCode::NetworkError, format!("POST request failed: {e}")))
     }
 
     /// Make a PUT request to the Mattermost API
@@ -363,17 +896,8 @@ impl MattermostClient {
         body: &T,
     ) -> Result<reqwest::Response> {
         let url = self.api_url(endpoint);
-        let mut request = self.http_client.put(&url);
-
-        if let Some(token) = self.get_token().await {
-            request = request.bearer_auth(token);
-        }
-
-        request
-            .json(body)
-            .send()
+        self.send("PUT", endpoint, || self.http_client.put(&url).json(body))
             .await
-            .map_err(|e| Error::new(ErrorCode::NetworkError, format!("PUT request failed: {e}")))
     }
 
     /// Make a DELETE request to the Mattermost API
@@ -385,18 +909,28 @@ impl MattermostClient {
     /// A Result containing the reqwest::Response or an Error
     pub async fn delete(&self, endpoint: &str) -> Result<reqwest::Response> {
         let url = self.api_url(endpoint);
-        let mut request = self.http_client.delete(&url);
-
-        if let Some(token) = self.get_token().await {
-            request = request.bearer_auth(token);
-        }
+        self.send("DELETE", endpoint, || self.http_client.delete(&url))
+            .await
+    }
 
-        request.send().await.map_err(|e| {
-            Error::new(
-                ErrorCode::NetworkError,
-                format!("DELETE request failed: {e}"),
-            )
+    /// Make a DELETE request with a JSON body to the Mattermost API
+    ///
+    /// # Arguments
+    /// * `endpoint` - The API endpoint path
+    /// * `body` - The request body (will be serialized to JSON)
+    ///
+    /// # Returns
+    /// A Result containing the reqwest::Response or an Error
+    pub async fn delete_with_body<T: serde::Serialize>(
+        &self,
+        endpoint: &str,
+        body: &T,
+    ) -> Result<reqwest::Response> {
+        let url = self.api_url(endpoint);
+        self.send("DELETE", endpoint, || {
+            self.http_client.delete(&url).json(body)
         })
+        .await
     }
 
     /// Map Mattermost error ID to appropriate ErrorCode
@@ -452,6 +986,7 @@ impl MattermostClient {
 
         // Extract and store rate limit info from headers
         self.update_rate_limit_info(&response).await;
+        self.record_server_version(&response).await;
 
         if status.is_success() {
             // Success case - parse response body
@@ -554,6 +1089,121 @@ impl MattermostClient {
         self.handle_response(response).await
     }
 
+    /// Autocomplete custom emojis by name
+    ///
+    /// # Arguments
+    /// * `name` - The start of the emoji name (without colons)
+    ///
+    /// # Returns
+    /// A Result containing up to 100 matching MattermostEmoji or an Error
+    ///
+    /// # API Endpoint
+    /// GET /emoji/autocomplete
+    pub async fn autocomplete_emoji(
+        &self,
+        name: &str,
+    ) -> Result<Vec<super::types::MattermostEmoji>> {
+        let name: String = url::form_urlencoded::byte_serialize(name.as_bytes()).collect();
+        let endpoint = format!("/emoji/autocomplete?name={name}");
+        let response = self.get(&endpoint).await?;
+        self.handle_response(response).await
+    }
+
+    /// Create a custom emoji
+    ///
+    /// # Arguments
+    /// * `name` - The name of the emoji (without colons)
+    /// * `image_path` - Path to the emoji image (gif, png or jpeg, max 1 MB)
+    ///
+    /// # Returns
+    /// A Result containing the created MattermostEmoji or an Error
+    pub async fn create_emoji(
+        &self,
+        name: &str,
+        image_path: &std::path::Path,
+    ) -> Result<super::types::MattermostEmoji> {
+        let image_data = tokio::fs::read(image_path).await.map_err(|e| {
+            Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Failed to read emoji image: {e}"),
+            )
+        })?;
+
+        let filename = image_path
+            .file_name()
+            .and_then(|n| n.to_str())
+            .ok_or_else(|| Error::new(ErrorCode::InvalidArgument, "Invalid image path"))?;
+
+        let creator_id = self.current_user_id().await?;
+        let emoji_json = serde_json::json!({
+            "name": name,
+            "creator_id": creator_id,
+        });
+
+        // A multipart form is consumed by sending it, so each attempt builds
+        // its own
+        let url = self.api_url("/emoji");
+        let response = self
+            .send("POST", "/emoji", || {
+                let form = reqwest::multipart::Form::new()
+                    .part(
+                        "image",
+                        reqwest::multipart::Part::bytes(image_data.clone())
+                            .file_name(filename.to_string()),
+                    )
+                    .text("emoji", emoji_json.to_string());
+                self.http_client.post(&url).multipart(form)
+            })
+            .await?;
+
+        self.handle_response(response).await
+    }
+
+    /// Delete a custom emoji
+    ///
+    /// # Arguments
+    /// * `emoji_id` - The ID of the emoji
+    ///
+    /// # Returns
+    /// A Result containing the deleted MattermostEmoji or an Error
+    pub async fn delete_emoji(&self, emoji_id: &str) -> Result<super::types::MattermostEmoji> {
+        let endpoint = format!("/emoji/{emoji_id}");
+        let response = self.delete(&endpoint).await?;
+        self.handle_response(response).await
+    }
+
+    /// Download the image of a custom emoji
+    ///
+    /// # Arguments
+    /// * `emoji_id` - The ID of the emoji
+    ///
+    /// # Returns
+    /// A Result containing the raw image bytes or an Error
+    pub async fn get_emoji_image(&self, emoji_id: &str) -> Result<Vec<u8>> {
+        let endpoint = format!("/emoji/{emoji_id}/image");
+        let response = self.get(&endpoint).await?;
+
+        let status = response.status();
+        if !status.is_success() {
+            let error_text = response
+                .text()
+                .await
+                .unwrap_or_else(|_| "Unknown error".to_string());
+            return Err(Error::new(
+                ErrorCode::NetworkError,
+                format!("Failed to download emoji image: {error_text}"),
+            )
+            .with_http_status(status.as_u16()));
+        }
+
+        response.bytes().await.map(|b| b.to_vec()).map_err(|e| {
+            Error::new(
+                ErrorCode::NetworkError,
+                format!("Failed to read emoji image data: {e}"),
+            )
+        })
+    }
+
     // ========================================================================
     // Cached API Methods
     // ========================================================================
@@ -757,6 +1407,14 @@ impl MattermostClient {
             .await;
     }
 
+    /// Get a channel from the cache without fetching it on a miss
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel
+    pub async fn peek_channel_cache(&self, channel_id: &str) -> Option<MattermostChannel> {
+        self.channel_cache.get(channel_id).await
+    }
+
     /// Remove a channel from the cache
     ///
     /// This is typically called after deleting/archiving a channel.
@@ -775,6 +1433,8 @@ impl MattermostClient {
         self.user_cache.clear().await;
         self.channel_cache.clear().await;
         self.team_cache.clear().await;
+        self.role_cache.clear().await;
+        self.permission_cache.clear().await;
     }
 
     /// Get cache statistics
@@ -804,6 +1464,53 @@ impl MattermostClient {
     }
 }
 
+/// Current Unix time in seconds
+fn unix_now() -> u64 {
+    std::time::SystemTime::now()
+        .duration_since(std::time::UNIX_EPOCH)
+        .map_or(0, |d| d.as_secs())
+}
+
+/// How long to wait before retrying a rate limited request
+///
+/// Retry-After wins when present. X-Ratelimit-Reset is read as seconds until
+/// the reset, as Mattermost sends it, or as a Unix time if it is one. Without
+/// either, the wait doubles from one second with each attempt.
+fn rate_limit_delay(headers: &reqwest::header::HeaderMap, attempt: u32, now: u64) -> Duration {
+    let header = |name: &str| {
+        headers
+            .get(name)
+            .and_then(|v| v.to_str().ok())
+            .and_then(|s| s.trim().parse::<u64>().ok())
+    };
+
+    let seconds = header("Retry-After").or_else(|| {
+        header("X-Ratelimit-Reset").map(|reset| {
+            // Anything past 2001 is a timestamp, not a wait
+            if reset > 1_000_000_000 {
+                reset.saturating_sub(now)
+            } else {
+                reset
+            }
+        })
+    });
+    match seconds {
+        Some(0) => Duration::from_millis(100),
+        Some(seconds) => Duration::from_secs(seconds),
+        None => Duration::from_secs(1 << attempt.saturating_sub(1).min(5)),
+    }
+}
+
+/// Map a failed REST call to an Error, telling timeouts apart from other network errors
+pub(crate) fn request_error(method: &str, e: reqwest::Error) -> Error {
+    let code = if e.is_timeout() {
+        ErrorCode::Timeout
+    } else {
+        ErrorCode::NetworkError
+    };
+    Error::new(code, format!("{method} request failed: {e}"))
+}
+
 #[cfg(test)]
 mod tests {
     use super::*;
@@ -814,6 +1521,129 @@ mod tests {
         assert!(client.is_ok());
     }
 
+    #[test]
+    fn test_timeouts_from_platform_config() {
+        let mut config = PlatformConfig::new("https://mattermost.example.com");
+        assert_eq!(Timeouts::from_platform_config(&config), Timeouts::default());
+
+        config.connect_timeout_ms = Some(2_000);
+        config.request_timeout_ms = Some(0);
+        config.idle_timeout_ms = Some(90_000);
+        let timeouts = Timeouts::from_platform_config(&config);
+        assert_eq!(timeouts.connect, Some(Duration::from_secs(2)));
+        assert_eq!(timeouts.request, Some(Duration::from_secs(30)));
+        assert_eq!(timeouts.handshake, None);
+        assert_eq!(timeouts.idle, Some(Duration::from_secs(90)));
+
+        let mut client = MattermostClient::new("https://mattermost.example.com").unwrap();
+        client.set_timeouts(timeouts).unwrap();
+        assert_eq!(client.timeouts(), timeouts);
+    }
+
+    #[test]
+    fn test_rate_limit_delay() {
+        use reqwest::header::{HeaderMap, HeaderValue};
+
+        let mut headers = HeaderMap::new();
+        assert_eq!(rate_limit_delay(&headers, 1, 0), Duration::from_secs(1));
+        assert_eq!(rate_limit_delay(&headers, 3, 0), Duration::from_secs(4));
+
+        headers.insert("X-Ratelimit-Reset", HeaderValue::from_static("2"));
+        assert_eq!(rate_limit_delay(&headers, 1, 0), Duration::from_secs(2));
+
+        headers.insert("X-Ratelimit-Reset", HeaderValue::from_static("1700000005"));
+        assert_eq!(
+            rate_limit_delay(&headers, 1, 1_700_000_000),
+            Duration::from_secs(5)
+        );
+
+        headers.insert("Retry-After", HeaderValue::from_static("7"));
+        assert_eq!(rate_limit_delay(&headers, 1, 0), Duration::from_secs(7));
+
+        headers.insert("Retry-After", HeaderValue::from_static("0"));
+        assert_eq!(rate_limit_delay(&headers, 1, 0), Duration::from_millis(100));
+    }
+
+    #[test]
+    fn test_rate_limit_policy_from_platform_config() {
+        let mut config = PlatformConfig::new("https://mattermost.example.com");
+        assert_eq!(
+            RateLimitPolicy::from_platform_config(&config),
+            RateLimitPolicy::default()
+        );
+
+        config.rate_limit_max_retries = Some(0);
+        config.rate_limit_max_wait_ms = Some(5_000);
+        let policy = RateLimitPolicy::from_platform_config(&config);
+        assert_eq!(policy.max_retries, 0);
+        assert_eq!(policy.max_wait, Duration::from_secs(5));
+    }
+
+    #[test]
+    fn test_retry_policy_backoff() {
+        let policy = RetryPolicy::default();
+        assert_eq!(policy.backoff(1, 0.0), Duration::from_millis(200));
+        assert_eq!(policy.backoff(3, 0.0), Duration::from_millis(800));
+        assert_eq!(policy.backoff(10, 0.0), Duration::from_secs(5));
+        assert_eq!(policy.backoff(1, 1.0), Duration::from_millis(160));
+
+        let sample = jitter_sample();
+        assert!((0.0..1.0).contains(&sample));
+    }
+
+    #[test]
+    fn test_retry_policy_classes() {
+        use reqwest::StatusCode;
+
+        let policy = RetryPolicy::default();
+        assert!(policy.retries_status("GET", StatusCode::SERVICE_UNAVAILABLE));
+        assert!(policy.retries_status("DELETE", StatusCode::BAD_GATEWAY));
+        assert!(!policy.retries_status("GET", StatusCode::INTERNAL_SERVER_ERROR));
+        // A POST may have been carried out before the gateway gave up
+        assert!(!policy.retries_status("POST", StatusCode::GATEWAY_TIMEOUT));
+
+        let mut config = PlatformConfig::new("https://mattermost.example.com");
+        config.retry_on = Some(vec!["timeout".to_string()]);
+        config.retry_max_attempts = Some(5);
+        let policy = RetryPolicy::from_platform_config(&config).unwrap();
+        assert_eq!(policy.max_attempts, 5);
+        assert!(policy.retry_on.timeout);
+        assert!(!policy.retries_status("GET", StatusCode::SERVICE_UNAVAILABLE));
+
+        config.retry_on = Some(vec!["everything".to_string()]);
+        assert!(RetryPolicy::from_platform_config(&config).is_err());
+        config.retry_on = None;
+        config.retry_jitter = Some(1.5);
+        assert!(RetryPolicy::from_platform_config(&config).is_err());
+    }
+
+    #[test]
+    fn test_request_headers() {
+        let mut config = PlatformConfig::new("https://mattermost.example.com");
+        let headers = request_headers(&config).unwrap();
+        assert_eq!(headers[USER_AGENT], DEFAULT_USER_AGENT);
+
+        config.user_agent = Some("my-bot/1.0".to_string());
+        config.headers.insert(
+            "CF-Access-Client-Id".to_string(),
+            "client.access".to_string(),
+        );
+        let headers = request_headers(&config).unwrap();
+        assert_eq!(headers[USER_AGENT], "my-bot/1.0");
+        assert_eq!(headers["cf-access-client-id"], "client.access");
+
+        config
+            .headers
+            .insert("Authorization".to_string(), "Bearer x".to_string());
+        assert!(request_headers(&config).is_err());
+
+        config.headers.clear();
+        config
+            .headers
+            .insert("Bad Name".to_string(), "x".to_string());
+        assert!(request_headers(&config).is_err());
+    }
+
     #[test]
     fn test_invalid_url() {
         let client = MattermostClient::new("not a url");
@@ -0,0 +1,125 @@
+//! Slash command operations for Mattermost
+
+use super::client::MattermostClient;
+use super::types::{ExecuteCommandRequest, MattermostCommand, MattermostCommandResponse};
+use crate::error::{Error, ErrorCode, Result};
+
+impl MattermostClient {
+    /// Execute a slash command
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel to run the command in
+    /// * `command` - The command with its arguments, e.g. "/away" or "/echo hi"
+    ///
+    /// # Returns
+    /// A Result containing the command's response or an Error
+    ///
+    /// # Notes
+    /// Requires the `use_slash_commands` permission for the channel's team
+    pub async fn execute_command(
+        &self,
+        channel_id: &str,
+        command: &str,
+    ) -> Result<MattermostCommandResponse> {
+        if !command.starts_with('/') {
+            return Err(Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Command must start with '/': {command}"),
+            ));
+        }
+
+        let request = ExecuteCommandRequest {
+            channel_id: channel_id.to_string(),
+            command: command.to_string(),
+            // Commands are registered per team; the server falls back to the
+            // channel's team, which DM and group channels don't have
+            team_id: self.get_team_id().await,
+        };
+        let response = self.post("/commands/execute", &request).await?;
+        self.handle_response(response).await
+    }
+
+    /// List the commands shown in autocomplete for a team
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team
+    ///
+    /// # Returns
+    /// A Result containing the built-in, custom and plugin commands of the team
+    ///
+    /// # API Endpoint
+    /// `GET /api/v4/teams/{team_id}/commands/autocomplete`
+    pub async fn list_autocomplete_commands(
+        &self,
+        team_id: &str,
+    ) -> Result<Vec<MattermostCommand>> {
+        let endpoint = format!("/teams/{team_id}/commands/autocomplete");
+        let response = self.get(&endpoint).await?;
+        self.handle_response(response).await
+    }
+}
+
+/// Keep the commands whose trigger starts with `prefix`, sorted by trigger
+///
+/// The prefix may include the leading slash and is matched case-insensitively.
+/// Triggers registered more than once keep their first command.
+pub fn filter_commands(commands: Vec<MattermostCommand>, prefix: &str) -> Vec<MattermostCommand> {
+    let prefix = prefix.trim_start_matches('/').to_lowercase();
+    let mut matching: Vec<MattermostCommand> = Vec::new();
+    for command in commands {
+        if !command.trigger.to_lowercase().starts_with(&prefix)
+            || matching.iter().any(|c| c.trigger == command.trigger)
+        {
+            continue;
+        }
+        matching.push(command);
+    }
+    matching.sort_by(|a, b| a.trigger.cmp(&b.trigger));
+    matching
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_command_response_deserialization() {
+        let json = r#"{
+            "response_type": "in_channel",
+            "text": "You are now away",
+            "goto_location": "",
+            "props": {"from_webhook": "true"},
+            "extra_responses": [{"text": "more"}]
+        }"#;
+
+        let response: MattermostCommandResponse = serde_json::from_str(json).unwrap();
+        assert_eq!(response.response_type, "in_channel");
+        assert_eq!(response.extra_responses.len(), 1);
+        assert!(response.trigger_id.is_empty());
+    }
+
+    #[test]
+    fn test_filter_commands() {
+        let command = |trigger: &str| MattermostCommand {
+            trigger: trigger.to_string(),
+            ..Default::default()
+        };
+        let commands = vec![
+            command("echo"),
+            command("away"),
+            command("Away"),
+            command("away"),
+            command("join"),
+        ];
+
+        let triggers = |commands: Vec<MattermostCommand>| {
+            commands.into_iter().map(|c| c.trigger).collect::<Vec<_>>()
+        };
+        assert_eq!(
+            triggers(filter_commands(commands.clone(), "/aw")),
+            ["Away", "away"]
+        );
+        assert_eq!(triggers(filter_commands(commands.clone(), "")).len(), 4);
+        assert!(filter_commands(commands, "/nope").is_empty());
+    }
+}
@@ -0,0 +1,158 @@
+//! Server version detection and graceful handling of endpoints that older
+//! servers do not have
+//!
+//! Newer endpoints (channel bookmarks, post acknowledgements, ...) return a
+//! bare 404 on servers that predate them, which is indistinguishable from a
+//! missing resource unless the error is inspected. Calls to such endpoints
+//! check the server version when it is known, and map "no such route"
+//! responses to `ErrorCode::UnsupportedByServer`.
+
+use std::fmt;
+
+use super::client::MattermostClient;
+use crate::error::{Error, ErrorCode, Result};
+
+/// Mattermost error ID for requests to a route the server does not have
+const ROUTE_NOT_FOUND_ID: &str = "api.context.404.app_error";
+
+/// A Mattermost server version
+#[derive(Debug, Clone, Copy, PartialEq, Eq, PartialOrd, Ord)]
+pub struct ServerVersion {
+    pub major: u32,
+    pub minor: u32,
+    pub patch: u32,
+}
+
+impl ServerVersion {
+    pub const fn new(major: u32, minor: u32, patch: u32) -> Self {
+        ServerVersion {
+            major,
+            minor,
+            patch,
+        }
+    }
+
+    /// Parse the `X-Version-Id` response header
+    ///
+    /// The header looks like "9.5.1.9.5.1.abc123.true"; only the leading
+    /// version is used.
+    pub fn parse(header: &str) -> Option<Self> {
+        let mut parts = header.split('.').map(|p| p.parse::<u32>().ok());
+        let major = parts.next()??;
+        let minor = parts.next()??;
+        let patch = parts.next().flatten().unwrap_or(0);
+        Some(ServerVersion::new(major, minor, patch))
+    }
+}
+
+impl fmt::Display for ServerVersion {
+    fn fmt(&self, f: &mut fmt::Formatter<'_>) -> fmt::Result {
+        write!(f, "{}.{}.{}", self.major, self.minor, self.patch)
+    }
+}
+
+impl MattermostClient {
+    /// Remember the server version advertised by a response
+    pub(super) async fn record_server_version(&self, response: &reqwest::Response) {
+        let version = response
+            .headers()
+            .get("X-Version-Id")
+            .and_then(|v| v.to_str().ok())
+            .and_then(ServerVersion::parse);
+        if let Some(version) = version {
+            *self.server_version.write().await = Some(version);
+        }
+    }
+
+    /// Get the server version, if a response has advertised it yet
+    pub async fn server_version(&self) -> Option<ServerVersion> {
+        *self.server_version.read().await
+    }
+
+    /// Fail fast when the server is known to predate a feature
+    ///
+    /// # Arguments
+    /// * `feature` - Human-readable feature name for the error message
+    /// * `min` - The first server version with the feature
+    ///
+    /// # Returns
+    /// Ok if the server is new enough or its version is not known yet
+    pub async fn require_server_version(&self, feature: &str, min: ServerVersion) -> Result<()> {
+        match self.server_version().await {
+            Some(version) if version < min => Err(Error::new(
+                ErrorCode::UnsupportedByServer,
+                format!("{feature} requires Mattermost {min} or later (server is {version})"),
+            )),
+            _ => Ok(()),
+        }
+    }
+}
+
+/// Whether an error means the server has no such endpoint, rather than that
+/// the requested resource does not exist
+pub fn is_missing_endpoint(error: &Error) -> bool {
+    match error.http_status() {
+        Some(404) => matches!(error.mattermost_error_id(), None | Some(ROUTE_NOT_FOUND_ID)),
+        Some(501) => true,
+        _ => false,
+    }
+}
+
+/// Map a "no such endpoint" error to `ErrorCode::UnsupportedByServer`,
+/// leaving every other error unchanged
+///
+/// # Arguments
+/// * `feature` - Human-readable feature name for the error message
+/// * `error` - The error returned by the request
+pub fn unsupported_if_missing(feature: &str, mut error: Error) -> Error {
+    if is_missing_endpoint(&error) {
+        error.code = ErrorCode::UnsupportedByServer;
+        error.message = format!("{feature} not supported by this server: {}", error.message);
+    }
+    error
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_parse_server_version() {
+        assert_eq!(
+            ServerVersion::parse("9.5.1.9.5.1.abc123.true"),
+            Some(ServerVersion::new(9, 5, 1))
+        );
+        assert_eq!(
+            ServerVersion::parse("10.2"),
+            Some(ServerVersion::new(10, 2, 0))
+        );
+        assert_eq!(ServerVersion::parse("dev"), None);
+        assert!(ServerVersion::new(9, 4, 9) < ServerVersion::new(9, 5, 0));
+        assert_eq!(ServerVersion::new(9, 5, 1).to_string(), "9.5.1");
+    }
+
+    #[test]
+    fn test_unsupported_if_missing() {
+        let route = Error::new(ErrorCode::NotFound, "Sorry, we could not find the page.")
+            .with_http_status(404)
+            .with_mattermost_error_id(ROUTE_NOT_FOUND_ID.to_string());
+        let mapped = unsupported_if_missing("Channel bookmarks", route);
+        assert_eq!(mapped.code, ErrorCode::UnsupportedByServer);
+        assert_eq!(mapped.http_status(), Some(404));
+
+        let bare = Error::new(ErrorCode::NotFound, "404 page not found").with_http_status(404);
+        assert_eq!(
+            unsupported_if_missing("Channel bookmarks", bare).code,
+            ErrorCode::UnsupportedByServer
+        );
+
+        // A missing resource on a route that exists stays NotFound
+        let resource = Error::new(ErrorCode::NotFound, "Unable to find the bookmark.")
+            .with_http_status(404)
+            .with_mattermost_error_id("app.channel.bookmark.get.app_error".to_string());
+        assert_eq!(
+            unsupported_if_missing("Channel bookmarks", resource).code,
+            ErrorCode::NotFound
+        );
+    }
+}
@@ -1,10 +1,26 @@
+use std::collections::HashMap;
+
 use chrono::{DateTime, Utc};
 
 use crate::types::user::UserStatus;
-use crate::types::{Attachment, Channel, ChannelType, Message, Team, TeamType, User};
+use crate::types::{
+    reaction_counts, Attachment, BookmarkType, Bot, Channel, ChannelBookmark, ChannelType,
+    CommandResponse, CommandResponseType, ImageDimensions, IncomingWebhook, LinkMedia,
+    LinkMetadata, Message, MessageAcknowledgement, MessageEmbed, MessagePriority, NotifyLevel,
+    PriorityLevel, Reaction, ReplyNotifyLevel, Session, SidebarCategory, SidebarCategoryType,
+    SlashCommand, Team, TeamStats, TeamType, Thread, ThreadList, User, UserGroup, UserNotifyProps,
+    UserStatusInfo,
+};
 
 use super::channels::get_dm_partner_id;
-use super::types::{FileInfo, MattermostChannel, MattermostPost, MattermostTeam, MattermostUser};
+use super::types::{
+    FileInfo, MattermostBot, MattermostChannel, MattermostChannelBookmark, MattermostCommand,
+    MattermostCommandResponse, MattermostGroup, MattermostIncomingWebhook, MattermostOpenGraph,
+    MattermostOpenGraphMedia, MattermostPost, MattermostPostAcknowledgement, MattermostPostEmbed,
+    MattermostPostImage, MattermostPostPriority, MattermostSession, MattermostSidebarCategory,
+    MattermostStatus, MattermostTeam, MattermostTeamStats, MattermostUser,
+    Reaction as MattermostReaction, UserThread, UserThreads,
+};
 
 /// Context for converting Mattermost types to generic types
 /// Provides necessary information like server URL and current user ID
@@ -29,12 +45,88 @@ impl ConversionContext {
 }
 
 /// Convert a Mattermost timestamp (milliseconds since epoch) to DateTime<Utc>
+///
+/// Out-of-range values map to the Unix epoch so the result is deterministic.
 fn timestamp_to_datetime(timestamp_ms: i64) -> DateTime<Utc> {
-    DateTime::from_timestamp(
-        timestamp_ms / 1000,
-        ((timestamp_ms % 1000) * 1_000_000) as u32,
-    )
-    .unwrap_or_else(Utc::now)
+    DateTime::from_timestamp_millis(timestamp_ms).unwrap_or_default()
+}
+
+/// Extract user IDs from a thread's participants, which are user IDs or
+/// user objects depending on the endpoint
+fn participant_ids(participants: &[serde_json::Value]) -> Vec<String> {
+    participants
+        .iter()
+        .filter_map(|p| match p {
+            serde_json::Value::String(id) => Some(id.clone()),
+            other => other.get("id").and_then(|id| id.as_str()).map(String::from),
+        })
+        .collect()
+}
+
+/// Normalize a post's creation and edit timestamps
+///
+/// An `edit_at` of 0 means the post was never edited. Clock skew between
+/// server nodes can make an edit appear to precede its post; such edits are
+/// clamped to the creation time so `edited_at >= created_at` always holds.
+fn normalize_post_times(create_at: i64, edit_at: i64) -> (DateTime<Utc>, Option<DateTime<Utc>>) {
+    let created_at = timestamp_to_datetime(create_at);
+    let edited_at = (edit_at > 0).then(|| timestamp_to_datetime(edit_at).max(created_at));
+    (created_at, edited_at)
+}
+
+/// JSON keys of Message, User and Channel, which server fields passed through
+/// in `extra` must not repeat
+const MESSAGE_KEYS: &[&str] = &[
+    "id",
+    "text",
+    "sender_id",
+    "channel_id",
+    "created_at",
+    "edited_at",
+    "attachments",
+    "root_id",
+    "reply_count",
+    "last_reply_at",
+    "participant_ids",
+    "message_type",
+    "metadata",
+];
+const USER_KEYS: &[&str] = &[
+    "id",
+    "username",
+    "display_name",
+    "email",
+    "avatar_url",
+    "status",
+    "status_message",
+    "is_bot",
+    "metadata",
+];
+const CHANNEL_KEYS: &[&str] = &[
+    "id",
+    "name",
+    "display_name",
+    "type",
+    "topic",
+    "purpose",
+    "member_ids",
+    "created_at",
+    "last_activity_at",
+    "is_archived",
+    "metadata",
+];
+
+/// Select the server fields to pass through, skipping any named like a key
+/// the converted type already has
+fn passthrough_fields(
+    extra: &HashMap<String, serde_json::Value>,
+    keys: &[&str],
+) -> HashMap<String, serde_json::Value> {
+    extra
+        .iter()
+        .filter(|(name, _)| !keys.contains(&name.as_str()))
+        .map(|(name, value)| (name.clone(), value.clone()))
+        .collect()
 }
 
 impl MattermostUser {
@@ -78,6 +170,7 @@ impl MattermostUser {
         if self.is_bot {
             user = user.as_bot();
         }
+        user.extra = passthrough_fields(&self.extra, USER_KEYS);
 
         user.with_metadata(metadata)
     }
@@ -95,12 +188,9 @@ impl From<MattermostUser> for User {
 /// Convert Mattermost Post to our internal Message type
 impl From<MattermostPost> for Message {
     fn from(mm_post: MattermostPost) -> Self {
-        let created_at = timestamp_to_datetime(mm_post.create_at);
-        let edited_at = if mm_post.edit_at > 0 {
-            Some(timestamp_to_datetime(mm_post.edit_at))
-        } else {
-            None
-        };
+        let (created_at, edited_at) = normalize_post_times(mm_post.create_at, mm_post.edit_at);
+        let root_id = (!mm_post.root_id.is_empty()).then(|| mm_post.root_id.clone());
+        let message_type = (!mm_post.post_type.is_empty()).then(|| mm_post.post_type.clone());
 
         // Convert file attachments
         let attachments: Vec<Attachment> = mm_post
@@ -110,8 +200,27 @@ impl From<MattermostPost> for Message {
             .map(|file| file.into())
             .collect();
 
+        let reactions: Vec<Reaction> = mm_post
+            .metadata
+            .reactions
+            .into_iter()
+            .map(Reaction::from)
+            .collect();
+        let embeds: Vec<MessageEmbed> = mm_post
+            .metadata
+            .embeds
+            .into_iter()
+            .map(MessageEmbed::from)
+            .collect();
+        let images: HashMap<String, ImageDimensions> = mm_post
+            .metadata
+            .images
+            .into_iter()
+            .map(|(url, image)| (url, image.into()))
+            .collect();
+
         // Create metadata with Mattermost-specific fields
-        let metadata = serde_json::json!({
+        let mut metadata = serde_json::json!({
             "root_id": mm_post.root_id,
             "parent_id": mm_post.parent_id,
             "post_type": mm_post.post_type,
@@ -119,7 +228,25 @@ impl From<MattermostPost> for Message {
             "hashtags": mm_post.hashtags,
             "update_at": mm_post.update_at,
             "delete_at": mm_post.delete_at,
+            "is_pinned": mm_post.is_pinned,
+            "reaction_counts": reaction_counts(&reactions),
+            "reactions": reactions,
+            "embeds": embeds,
+            "images": images,
+            "priority": mm_post.metadata.priority.map(MessagePriority::from),
+            "acknowledgements": mm_post
+                .metadata
+                .acknowledgements
+                .into_iter()
+                .map(MessageAcknowledgement::from)
+                .collect::<Vec<_>>(),
         });
+        // Unmodeled post metadata stays with the modeled metadata
+        if let serde_json::Value::Object(fields) = &mut metadata {
+            for (name, value) in mm_post.metadata.extra {
+                fields.entry(name).or_insert(value);
+            }
+        }
 
         let mut message = Message::new(
             mm_post.id,
@@ -132,6 +259,13 @@ impl From<MattermostPost> for Message {
         message.created_at = created_at;
         message.edited_at = edited_at;
         message.attachments = attachments;
+        message.root_id = root_id;
+        message.message_type = message_type;
+        message.reply_count = mm_post.reply_count;
+        message.last_reply_at =
+            (mm_post.last_reply_at > 0).then(|| timestamp_to_datetime(mm_post.last_reply_at));
+        message.participant_ids = participant_ids(mm_post.participants.as_deref().unwrap_or(&[]));
+        message.extra = passthrough_fields(&mm_post.extra, MESSAGE_KEYS);
         message = message.with_metadata(metadata);
 
         message
@@ -158,6 +292,13 @@ impl FileInfo {
             attachment = attachment.with_thumbnail(thumbnail_url);
         }
 
+        // Non-image files report zero dimensions
+        if self.width > 0 && self.height > 0 {
+            attachment = attachment.with_dimensions(self.width as u32, self.height as u32);
+        }
+        attachment.has_preview_image = self.has_preview_image;
+        attachment.mini_preview = self.mini_preview.clone().filter(|p| !p.is_empty());
+
         attachment
     }
 }
@@ -235,6 +376,7 @@ impl MattermostChannel {
         if self.delete_at > 0 {
             channel = channel.archived();
         }
+        channel.extra = passthrough_fields(&self.extra, CHANNEL_KEYS);
 
         channel.with_metadata(metadata)
     }
@@ -294,6 +436,338 @@ impl From<MattermostTeam> for Team {
     }
 }
 
+/// Convert Mattermost sidebar category to our internal SidebarCategory type
+impl From<MattermostSidebarCategory> for SidebarCategory {
+    fn from(mm_category: MattermostSidebarCategory) -> Self {
+        let category_type = match mm_category.category_type.as_str() {
+            "favorites" => SidebarCategoryType::Favorites,
+            "channels" => SidebarCategoryType::Channels,
+            "direct_messages" => SidebarCategoryType::DirectMessages,
+            _ => SidebarCategoryType::Custom,
+        };
+
+        SidebarCategory {
+            id: mm_category.id,
+            team_id: mm_category.team_id,
+            display_name: mm_category.display_name,
+            category_type,
+            channel_ids: mm_category.channel_ids,
+            muted: mm_category.muted,
+            collapsed: mm_category.collapsed,
+        }
+    }
+}
+
+impl MattermostChannelBookmark {
+    /// Convert to ChannelBookmark with context for proper file URL construction
+    pub fn to_bookmark_with_context(&self, ctx: &ConversionContext) -> ChannelBookmark {
+        let bookmark_type = match self.bookmark_type.as_str() {
+            "file" => BookmarkType::File,
+            _ => BookmarkType::Link,
+        };
+        let non_empty = |s: &str| (!s.is_empty()).then(|| s.to_string());
+
+        ChannelBookmark {
+            id: self.id.clone(),
+            channel_id: self.channel_id.clone(),
+            owner_id: self.owner_id.clone(),
+            display_name: self.display_name.clone(),
+            bookmark_type,
+            link_url: non_empty(&self.link_url),
+            image_url: non_empty(&self.image_url),
+            emoji: non_empty(&self.emoji),
+            file_id: non_empty(&self.file_id),
+            file: self
+                .file
+                .as_ref()
+                .map(|file| file.to_attachment_with_context(ctx)),
+            sort_order: self.sort_order,
+            created_at: timestamp_to_datetime(self.create_at),
+            updated_at: timestamp_to_datetime(self.update_at),
+        }
+    }
+}
+
+impl From<MattermostSession> for Session {
+    fn from(mm_session: MattermostSession) -> Self {
+        let non_empty = |s: Option<&String>| s.filter(|s| !s.is_empty()).cloned();
+
+        Session {
+            os: non_empty(mm_session.props.get("os")),
+            browser: non_empty(mm_session.props.get("browser")),
+            platform: non_empty(mm_session.props.get("platform")),
+            device_id: non_empty(Some(&mm_session.device_id)),
+            id: mm_session.id,
+            user_id: mm_session.user_id,
+            created_at: timestamp_to_datetime(mm_session.create_at),
+            last_activity_at: timestamp_to_datetime(mm_session.last_activity_at),
+            expires_at: (mm_session.expires_at > 0)
+                .then(|| timestamp_to_datetime(mm_session.expires_at)),
+            is_oauth: mm_session.is_oauth,
+        }
+    }
+}
+
+impl From<MattermostTeamStats> for TeamStats {
+    fn from(mm_stats: MattermostTeamStats) -> Self {
+        TeamStats {
+            team_id: mm_stats.team_id,
+            member_count: mm_stats.total_member_count,
+            active_member_count: mm_stats.active_member_count,
+        }
+    }
+}
+
+impl From<MattermostGroup> for UserGroup {
+    fn from(mm_group: MattermostGroup) -> Self {
+        UserGroup {
+            id: mm_group.id,
+            name: mm_group.name,
+            display_name: mm_group.display_name,
+            description: mm_group.description,
+            source: mm_group.source,
+            allow_reference: mm_group.allow_reference,
+            member_count: mm_group.member_count,
+            created_at: timestamp_to_datetime(mm_group.create_at),
+            updated_at: timestamp_to_datetime(mm_group.update_at),
+        }
+    }
+}
+
+impl From<MattermostStatus> for UserStatusInfo {
+    fn from(mm_status: MattermostStatus) -> Self {
+        UserStatusInfo {
+            status: status_string_to_user_status(&mm_status.status),
+            user_id: mm_status.user_id,
+            manual: mm_status.manual,
+            // Unlike the other timestamps, dnd_end_time is in seconds
+            dnd_end_time: (mm_status.dnd_end_time > 0)
+                .then(|| DateTime::from_timestamp(mm_status.dnd_end_time, 0))
+                .flatten(),
+            last_activity_at: (mm_status.last_activity_at > 0)
+                .then(|| timestamp_to_datetime(mm_status.last_activity_at)),
+        }
+    }
+}
+
+impl From<MattermostCommandResponse> for CommandResponse {
+    fn from(mm_response: MattermostCommandResponse) -> Self {
+        let non_empty = |s: String| (!s.is_empty()).then_some(s);
+
+        CommandResponse {
+            response_type: match mm_response.response_type.as_str() {
+                "in_channel" => CommandResponseType::InChannel,
+                _ => CommandResponseType::Ephemeral,
+            },
+            text: mm_response.text,
+            channel_id: non_empty(mm_response.channel_id),
+            username: non_empty(mm_response.username),
+            icon_url: non_empty(mm_response.icon_url),
+            goto_location: non_empty(mm_response.goto_location),
+            trigger_id: non_empty(mm_response.trigger_id),
+            props: mm_response.props,
+            attachments: mm_response.attachments,
+            extra_responses: mm_response
+                .extra_responses
+                .into_iter()
+                .map(CommandResponse::from)
+                .collect(),
+        }
+    }
+}
+
+impl From<MattermostCommand> for SlashCommand {
+    fn from(mm_command: MattermostCommand) -> Self {
+        let non_empty = |s: String| (!s.is_empty()).then_some(s);
+
+        SlashCommand {
+            trigger: mm_command.trigger,
+            display_name: mm_command.display_name,
+            // The autocomplete description is the one meant for composers
+            description: if mm_command.auto_complete_desc.is_empty() {
+                mm_command.description
+            } else {
+                mm_command.auto_complete_desc
+            },
+            hint: mm_command.auto_complete_hint,
+            team_id: non_empty(mm_command.team_id),
+            plugin_id: non_empty(mm_command.plugin_id),
+        }
+    }
+}
+
+/// Convert a webhook; `url` is left empty since it depends on the server
+/// address (see `MattermostClient::incoming_webhook_url`)
+impl From<MattermostIncomingWebhook> for IncomingWebhook {
+    fn from(mm_hook: MattermostIncomingWebhook) -> Self {
+        let non_empty = |s: String| (!s.is_empty()).then_some(s);
+
+        IncomingWebhook {
+            id: mm_hook.id,
+            channel_id: mm_hook.channel_id,
+            team_id: mm_hook.team_id,
+            creator_id: mm_hook.user_id,
+            display_name: mm_hook.display_name,
+            description: mm_hook.description,
+            username: non_empty(mm_hook.username),
+            icon_url: non_empty(mm_hook.icon_url),
+            channel_locked: mm_hook.channel_locked,
+            url: String::new(),
+            created_at: timestamp_to_datetime(mm_hook.create_at),
+        }
+    }
+}
+
+impl From<MattermostBot> for Bot {
+    fn from(mm_bot: MattermostBot) -> Self {
+        Bot {
+            user_id: mm_bot.user_id,
+            username: mm_bot.username,
+            display_name: mm_bot.display_name,
+            description: mm_bot.description,
+            owner_id: mm_bot.owner_id,
+            created_at: timestamp_to_datetime(mm_bot.create_at),
+            updated_at: timestamp_to_datetime(mm_bot.update_at),
+            is_disabled: mm_bot.delete_at != 0,
+        }
+    }
+}
+
+impl From<MattermostPostPriority> for MessagePriority {
+    fn from(mm_priority: MattermostPostPriority) -> Self {
+        let priority = match mm_priority.priority.as_str() {
+            "important" => PriorityLevel::Important,
+            "urgent" => PriorityLevel::Urgent,
+            _ => PriorityLevel::Standard,
+        };
+        MessagePriority {
+            priority,
+            requested_ack: mm_priority.requested_ack,
+            persistent_notifications: mm_priority.persistent_notifications,
+        }
+    }
+}
+
+impl From<&MessagePriority> for MattermostPostPriority {
+    fn from(priority: &MessagePriority) -> Self {
+        let level = match priority.priority {
+            PriorityLevel::Standard => "",
+            PriorityLevel::Important => "important",
+            PriorityLevel::Urgent => "urgent",
+        };
+        MattermostPostPriority {
+            priority: level.to_string(),
+            requested_ack: priority.requested_ack,
+            persistent_notifications: priority.persistent_notifications,
+        }
+    }
+}
+
+impl From<MattermostReaction> for Reaction {
+    fn from(mm_reaction: MattermostReaction) -> Self {
+        Reaction {
+            user_id: mm_reaction.user_id,
+            post_id: mm_reaction.post_id,
+            emoji_name: mm_reaction.emoji_name,
+            created_at: timestamp_to_datetime(mm_reaction.create_at),
+        }
+    }
+}
+
+impl From<MattermostPostEmbed> for MessageEmbed {
+    fn from(mm_embed: MattermostPostEmbed) -> Self {
+        MessageEmbed {
+            embed_type: mm_embed.embed_type,
+            url: mm_embed.url,
+            data: mm_embed.data,
+        }
+    }
+}
+
+impl From<MattermostPostImage> for ImageDimensions {
+    fn from(mm_image: MattermostPostImage) -> Self {
+        ImageDimensions {
+            width: mm_image.width,
+            height: mm_image.height,
+            format: mm_image.format,
+            frame_count: mm_image.frame_count,
+        }
+    }
+}
+
+impl From<MattermostOpenGraph> for LinkMetadata {
+    fn from(mm_og: MattermostOpenGraph) -> Self {
+        let non_empty = |s: String| (!s.is_empty()).then_some(s);
+        let media = |items: Vec<MattermostOpenGraphMedia>| {
+            items
+                .into_iter()
+                .filter(|m| !m.url.is_empty() || !m.secure_url.is_empty())
+                .map(|m| LinkMedia {
+                    url: if m.url.is_empty() {
+                        m.secure_url.clone()
+                    } else {
+                        m.url
+                    },
+                    secure_url: non_empty(m.secure_url),
+                    mime_type: non_empty(m.mime_type),
+                    width: m.width,
+                    height: m.height,
+                })
+                .collect()
+        };
+
+        LinkMetadata {
+            url: mm_og.url,
+            title: non_empty(mm_og.title),
+            description: non_empty(mm_og.description),
+            site_name: non_empty(mm_og.site_name),
+            og_type: non_empty(mm_og.og_type),
+            images: media(mm_og.images),
+            videos: media(mm_og.videos),
+        }
+    }
+}
+
+impl From<MattermostPostAcknowledgement> for MessageAcknowledgement {
+    fn from(mm_ack: MattermostPostAcknowledgement) -> Self {
+        MessageAcknowledgement {
+            user_id: mm_ack.user_id,
+            message_id: mm_ack.post_id,
+            acknowledged_at: timestamp_to_datetime(mm_ack.acknowledged_at),
+        }
+    }
+}
+
+impl From<UserThread> for Thread {
+    fn from(mm_thread: UserThread) -> Self {
+        let participant_ids = participant_ids(&mm_thread.participants);
+
+        Thread {
+            id: mm_thread.id,
+            root: mm_thread.post.into(),
+            reply_count: mm_thread.reply_count,
+            last_reply_at: (mm_thread.last_reply_at > 0)
+                .then(|| timestamp_to_datetime(mm_thread.last_reply_at)),
+            last_viewed_at: (mm_thread.last_viewed_at > 0)
+                .then(|| timestamp_to_datetime(mm_thread.last_viewed_at)),
+            participant_ids,
+            unread_replies: mm_thread.unread_replies,
+            unread_mentions: mm_thread.unread_mentions,
+        }
+    }
+}
+
+impl From<UserThreads> for ThreadList {
+    fn from(mm_threads: UserThreads) -> Self {
+        ThreadList {
+            total: mm_threads.total,
+            total_unread_threads: mm_threads.total_unread_threads,
+            total_unread_mentions: mm_threads.total_unread_mentions,
+            threads: mm_threads.threads.into_iter().map(Thread::from).collect(),
+        }
+    }
+}
+
 /// Helper function to convert a status string to UserStatus
 pub fn status_string_to_user_status(status: &str) -> UserStatus {
     match status {
@@ -316,6 +790,122 @@ pub fn user_status_to_status_string(status: UserStatus) -> &'static str {
     }
 }
 
+/// Helper function to convert TeamType to the Mattermost team type code
+pub fn team_type_to_code(team_type: TeamType) -> &'static str {
+    match team_type {
+        TeamType::Open => "O",
+        TeamType::Invite => "I",
+    }
+}
+
+fn notify_level_from_str(level: &str) -> Option<NotifyLevel> {
+    match level {
+        "all" => Some(NotifyLevel::All),
+        "mention" => Some(NotifyLevel::Mention),
+        "none" => Some(NotifyLevel::None),
+        _ => None,
+    }
+}
+
+fn notify_level_to_str(level: NotifyLevel) -> &'static str {
+    match level {
+        NotifyLevel::All => "all",
+        NotifyLevel::Mention => "mention",
+        NotifyLevel::None => "none",
+    }
+}
+
+fn reply_level_from_str(level: &str) -> Option<ReplyNotifyLevel> {
+    match level {
+        "never" => Some(ReplyNotifyLevel::Never),
+        "root" => Some(ReplyNotifyLevel::Root),
+        "any" => Some(ReplyNotifyLevel::Any),
+        _ => None,
+    }
+}
+
+fn reply_level_to_str(level: ReplyNotifyLevel) -> &'static str {
+    match level {
+        ReplyNotifyLevel::Never => "never",
+        ReplyNotifyLevel::Root => "root",
+        ReplyNotifyLevel::Any => "any",
+    }
+}
+
+/// Convert a Mattermost user's `notify_props` map to UserNotifyProps
+///
+/// Email batching is stored as a user preference rather than a notify prop,
+/// so its value is passed in separately.
+pub fn user_notify_props_from_map(
+    props: &HashMap<String, String>,
+    email_interval: Option<&str>,
+) -> UserNotifyProps {
+    let flag = |key: &str| props.get(key).map(|v| v == "true");
+
+    UserNotifyProps {
+        desktop: props.get("desktop").and_then(|v| notify_level_from_str(v)),
+        desktop_sound: flag("desktop_sound"),
+        email: flag("email"),
+        email_batching_interval: email_interval.and_then(|v| v.parse().ok()),
+        push: props.get("push").and_then(|v| notify_level_from_str(v)),
+        push_status: props
+            .get("push_status")
+            .map(|v| status_string_to_user_status(v)),
+        mention_keys: props.get("mention_keys").map(|keys| {
+            keys.split(',')
+                .map(str::trim)
+                .filter(|k| !k.is_empty())
+                .map(String::from)
+                .collect()
+        }),
+        channel_mentions: flag("channel"),
+        first_name_mention: flag("first_name"),
+        reply_notifications: props.get("comments").and_then(|v| reply_level_from_str(v)),
+    }
+}
+
+/// Merge the set fields of UserNotifyProps into a Mattermost `notify_props` map
+///
+/// Mattermost replaces the whole map on update, so callers should start from
+/// the user's current props. `email_batching_interval` is not a notify prop
+/// and is ignored here.
+pub fn apply_user_notify_props(props: &mut HashMap<String, String>, update: &UserNotifyProps) {
+    let mut set = |key: &str, value: String| {
+        props.insert(key.to_string(), value);
+    };
+
+    if let Some(level) = update.desktop {
+        set("desktop", notify_level_to_str(level).to_string());
+    }
+    if let Some(enabled) = update.desktop_sound {
+        set("desktop_sound", enabled.to_string());
+    }
+    if let Some(enabled) = update.email {
+        set("email", enabled.to_string());
+    }
+    if let Some(level) = update.push {
+        set("push", notify_level_to_str(level).to_string());
+    }
+    if let Some(status) = update.push_status {
+        set(
+            "push_status",
+            user_status_to_status_string(status).to_string(),
+        );
+    }
+    if let Some(keys) = &update.mention_keys {
+        set("mention_keys", keys.join(","));
+    }
+    if let Some(enabled) = update.channel_mentions {
+        set("channel", enabled.to_string());
+    }
+    if let Some(enabled) = update.first_name_mention {
+        set("first_name", enabled.to_string());
+    }
+    if let Some(level) = update.reply_notifications {
+        set("comments", reply_level_to_str(level).to_string());
+    }
+}
+
 #[cfg(test)]
 mod tests {
     use super::*;
@@ -335,9 +925,11 @@ mod tests {
             timezone: Default::default(),
             props: Default::default(),
             is_bot: false,
+            notify_props: Default::default(),
             create_at: 1234567890000,
             update_at: 1234567890000,
             delete_at: 0,
+            extra: Default::default(),
         };
 
         let user: User = mm_user.into();
@@ -366,6 +958,7 @@ mod tests {
             last_post_at: 0,
             total_msg_count: 42,
             creator_id: "user1".to_string(),
+            extra: Default::default(),
         };
 
         let channel: Channel = mm_channel.into();
@@ -395,6 +988,87 @@ mod tests {
         assert_eq!(dt.timestamp(), 1234567890);
     }
 
+    #[test]
+    fn test_post_time_normalization() {
+        let (created, edited) = normalize_post_times(1_700_000_000_500, 0);
+        assert_eq!(created.timestamp_millis(), 1_700_000_000_500);
+        assert!(edited.is_none());
+
+        // Edit stamped before creation by a skewed clock
+        let (created, edited) = normalize_post_times(1_700_000_000_500, 1_700_000_000_100);
+        assert_eq!(edited, Some(created));
+
+        // Negative timestamps keep millisecond precision
+        assert_eq!(timestamp_to_datetime(-1).timestamp_millis(), -1);
+    }
+
+    #[test]
+    fn test_post_metadata_conversion() {
+        let mm_post: MattermostPost = serde_json::from_value(serde_json::json!({
+            "id": "post1",
+            "create_at": 1_700_000_000_000i64,
+            "update_at": 1_700_000_000_000i64,
+            "edit_at": 0,
+            "delete_at": 0,
+            "user_id": "user1",
+            "channel_id": "ch1",
+            "root_id": "",
+            "message": "see https://example.com",
+            "type": "",
+            "metadata": {
+                "embeds": [{"type": "opengraph", "url": "https://example.com", "data": {"title": "Example"}}],
+                "images": {"https://example.com/logo.png": {"width": 640, "height": 480, "format": "png", "frame_count": 0}},
+                "reactions": [
+                    {"user_id": "user2", "post_id": "post1", "emoji_name": "tada", "create_at": 1_700_000_001_000i64},
+                    {"user_id": "user3", "post_id": "post1", "emoji_name": "tada", "create_at": 1_700_000_002_000i64},
+                    {"user_id": "user3", "post_id": "post1", "emoji_name": "eyes", "create_at": 1_700_000_003_000i64}
+                ]
+            }
+        }))
+        .unwrap();
+
+        let message: Message = mm_post.into();
+        let metadata = message.metadata.unwrap();
+        assert_eq!(
+            metadata["reaction_counts"],
+            serde_json::json!({"eyes": 1, "tada": 2})
+        );
+        assert_eq!(metadata["embeds"][0]["type"], "opengraph");
+        assert_eq!(metadata["embeds"][0]["data"]["title"], "Example");
+        assert_eq!(
+            metadata["images"]["https://example.com/logo.png"],
+            serde_json::json!({"width": 640, "height": 480, "format": "png", "frame_count": 0})
+        );
+        assert_eq!(
+            metadata["reactions"][0]["created_at"],
+            "2023-11-14T22:13:21Z"
+        );
+    }
+
+    #[test]
+    fn test_opengraph_conversion() {
+        let mm_og: MattermostOpenGraph = serde_json::from_value(serde_json::json!({
+            "type": "article",
+            "url": "https://example.com/post",
+            "title": "Example",
+            "description": "",
+            "images": [
+                {"secure_url": "https://example.com/a.png", "type": "image/png", "width": 1200, "height": 630},
+                {"url": ""}
+            ]
+        }))
+        .unwrap();
+
+        let link: LinkMetadata = mm_og.into();
+        assert_eq!(link.title.as_deref(), Some("Example"));
+        assert_eq!(link.description, None);
+        assert_eq!(link.og_type.as_deref(), Some("article"));
+        assert_eq!(link.images.len(), 1);
+        assert_eq!(link.images[0].url, "https://example.com/a.png");
+        assert_eq!(link.images[0].mime_type.as_deref(), Some("image/png"));
+        assert_eq!(link.images[0].width, 1200);
+    }
+
     #[test]
     fn test_team_conversion() {
         let mm_team = MattermostTeam {
@@ -411,6 +1085,7 @@ mod tests {
             allowed_domains: "example.com".to_string(),
             invite_id: "inv123".to_string(),
             allow_open_invite: true,
+            group_constrained: None,
         };
 
         let team: Team = mm_team.into();
@@ -439,6 +1114,7 @@ mod tests {
             allowed_domains: String::new(),
             invite_id: String::new(),
             allow_open_invite: false,
+            group_constrained: None,
         };
 
         let team: Team = mm_team.into();
@@ -447,4 +1123,170 @@ mod tests {
         assert_eq!(team.allowed_domains, None);
         assert!(!team.allow_open_invite);
     }
+
+    #[test]
+    fn test_user_notify_props_round_trip() {
+        let mut props: HashMap<String, String> = [
+            ("desktop", "mention"),
+            ("email", "true"),
+            ("push_status", "away"),
+            ("mention_keys", "alice, @alice,"),
+            ("comments", "root"),
+            ("auto_responder_active", "false"),
+        ]
+        .into_iter()
+        .map(|(k, v)| (k.to_string(), v.to_string()))
+        .collect();
+
+        let typed = user_notify_props_from_map(&props, Some("900"));
+        assert_eq!(typed.desktop, Some(NotifyLevel::Mention));
+        assert_eq!(typed.email, Some(true));
+        assert_eq!(typed.email_batching_interval, Some(900));
+        assert_eq!(typed.push_status, Some(UserStatus::Away));
+        assert_eq!(
+            typed.mention_keys,
+            Some(vec!["alice".to_string(), "@alice".to_string()])
+        );
+        assert_eq!(typed.reply_notifications, Some(ReplyNotifyLevel::Root));
+        assert!(typed.push.is_none());
+
+        let update = UserNotifyProps {
+            push: Some(NotifyLevel::None),
+            mention_keys: Some(vec!["deploy".to_string()]),
+            ..Default::default()
+        };
+        apply_user_notify_props(&mut props, &update);
+        assert_eq!(props["push"], "none");
+        assert_eq!(props["mention_keys"], "deploy");
+        assert_eq!(props["desktop"], "mention");
+        assert_eq!(props["auto_responder_active"], "false");
+    }
+
+    #[test]
+    fn test_user_threads_conversion() {
+        let json = r#"{
+            "total": 1,
+            "total_unread_threads": 1,
+            "total_unread_mentions": 2,
+            "threads": [{
+                "id": "root1",
+                "reply_count": 4,
+                "last_reply_at": 1700000000000,
+                "last_viewed_at": 0,
+                "participants": ["user1", {"id": "user2", "username": "bob"}],
+                "post": {
+                    "id": "root1", "create_at": 1699990000000, "update_at": 1699990000000,
+                    "delete_at": 0, "edit_at": 0, "user_id": "user1",
+                    "channel_id": "ch1", "message": "Deploy failed"
+                },
+                "unread_replies": 3,
+                "unread_mentions": 2
+            }]
+        }"#;
+
+        let mm_threads: UserThreads = serde_json::from_str(json).unwrap();
+        let list = ThreadList::from(mm_threads);
+        assert_eq!(list.total_unread_mentions, 2);
+
+        let thread = &list.threads[0];
+        assert_eq!(thread.root.text, "Deploy failed");
+        assert_eq!(thread.participant_ids, vec!["user1", "user2"]);
+        assert!(thread.last_reply_at.is_some());
+        assert!(thread.last_viewed_at.is_none());
+        assert_eq!(thread.unread_replies, 3);
+    }
+
+    #[test]
+    fn test_post_thread_fields() {
+        let json = r#"{
+            "id": "root1", "create_at": 1699990000000, "update_at": 1699990000000,
+            "delete_at": 0, "edit_at": 0, "user_id": "user1", "channel_id": "ch1",
+            "root_id": "", "message": "Deploy failed",
+            "reply_count": 2, "last_reply_at": 1700000000000,
+            "participants": [{"id": "user2", "username": "bob"}]
+        }"#;
+        let message = Message::from(serde_json::from_str::<MattermostPost>(json).unwrap());
+        assert!(!message.is_reply());
+        assert_eq!(message.reply_count, 2);
+        assert!(message.last_reply_at.is_some());
+        assert_eq!(message.participant_ids, vec!["user2"]);
+
+        let json = r#"{
+            "id": "reply1", "create_at": 1700000000000, "update_at": 1700000000000,
+            "delete_at": 0, "edit_at": 0, "user_id": "user2", "channel_id": "ch1",
+            "root_id": "root1", "message": "Looking", "participants": null
+        }"#;
+        let message = Message::from(serde_json::from_str::<MattermostPost>(json).unwrap());
+        assert_eq!(message.root_id.as_deref(), Some("root1"));
+        assert!(message.participant_ids.is_empty());
+        assert!(message.last_reply_at.is_none());
+    }
+
+    #[test]
+    fn test_post_unmodeled_fields_pass_through() {
+        let json = r#"{
+            "id": "post1", "create_at": 1700000000000, "update_at": 1700000000000,
+            "delete_at": 0, "edit_at": 0, "user_id": "user1", "channel_id": "ch1",
+            "message": "hello", "remote_id": "remote1", "text": "clash",
+            "metadata": {"files": [], "new_metadata": {"a": 1}}
+        }"#;
+        let message = Message::from(serde_json::from_str::<MattermostPost>(json).unwrap());
+        assert_eq!(
+            message.extra.get("remote_id"),
+            Some(&serde_json::json!("remote1"))
+        );
+        // A server field named like a Message key can't replace it
+        assert!(!message.extra.contains_key("text"));
+
+        let encoded = serde_json::to_value(&message).unwrap();
+        assert_eq!(encoded["text"], "hello");
+        assert_eq!(encoded["remote_id"], "remote1");
+        assert_eq!(encoded["metadata"]["new_metadata"]["a"], 1);
+    }
+
+    #[test]
+    fn test_post_type() {
+        let json = r#"{
+            "id": "post1", "create_at": 1700000000000, "update_at": 1700000000000,
+            "delete_at": 0, "edit_at": 0, "user_id": "user1", "channel_id": "ch1",
+            "message": "alice joined the channel.", "type": "system_join_channel"
+        }"#;
+        let message = Message::from(serde_json::from_str::<MattermostPost>(json).unwrap());
+        assert_eq!(message.message_type.as_deref(), Some("system_join_channel"));
+        assert!(message.is_system());
+
+        let json = r#"{
+            "id": "post2", "create_at": 1700000000000, "update_at": 1700000000000,
+            "delete_at": 0, "edit_at": 0, "user_id": "user1", "channel_id": "ch1",
+            "message": "hello", "type": ""
+        }"#;
+        let message = Message::from(serde_json::from_str::<MattermostPost>(json).unwrap());
+        assert!(message.message_type.is_none());
+        assert!(!message.is_system());
+    }
+
+    #[test]
+    fn test_status_conversion() {
+        let json = r#"{
+            "user_id": "user1", "status": "dnd", "manual": true,
+            "last_activity_at": 1700000000000, "dnd_end_time": 1700003600
+        }"#;
+        let status = UserStatusInfo::from(serde_json::from_str::<MattermostStatus>(json).unwrap());
+        assert_eq!(status.status, UserStatus::DoNotDisturb);
+        assert!(status.manual);
+        assert_eq!(
+            status.dnd_end_time.map(|t| t.timestamp()),
+            Some(1_700_003_600)
+        );
+        assert_eq!(
+            status.last_activity_at.map(|t| t.timestamp_millis()),
+            Some(1_700_000_000_000)
+        );
+
+        let json = r#"{"user_id": "user2", "status": "offline"}"#;
+        let status = UserStatusInfo::from(serde_json::from_str::<MattermostStatus>(json).unwrap());
+        assert!(!status.manual);
+        assert!(status.dnd_end_time.is_none());
+        assert!(status.last_activity_at.is_none());
+    }
 }
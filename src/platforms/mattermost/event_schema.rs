@@ -0,0 +1,424 @@
+//! Schema validation for incoming WebSocket event payloads
+//!
+//! The event converters in websocket.rs fall back to empty values when a
+//! field is missing or has an unexpected type, which keeps the event stream
+//! running across server versions but hides incompatibilities. In strict mode
+//! each event's `data` object is checked against the fields the converters
+//! expect, and any mismatch is reported as a `PlatformEvent::SchemaMismatch`.
+
+use serde_json::Value;
+
+use super::types::WebSocketEvent;
+use crate::platforms::platform_trait::SchemaIssue;
+
+/// Expected JSON type of a field
+#[derive(Clone, Copy)]
+enum Kind {
+    String,
+    Number,
+    Bool,
+    Object,
+    Array,
+    /// A string holding a JSON-encoded object with the given fields
+    Encoded(&'static [Field]),
+    /// Any JSON value
+    Any,
+}
+
+impl Kind {
+    fn name(&self) -> &'static str {
+        match self {
+            Kind::String => "string",
+            Kind::Number => "number",
+            Kind::Bool => "bool",
+            Kind::Object => "object",
+            Kind::Array => "array",
+            Kind::Encoded(_) => "JSON-encoded string",
+            Kind::Any => "any",
+        }
+    }
+
+    fn matches(&self, value: &Value) -> bool {
+        match self {
+            Kind::String | Kind::Encoded(_) => value.is_string(),
+            Kind::Number => value.is_number(),
+            Kind::Bool => value.is_boolean(),
+            Kind::Object => value.is_object(),
+            Kind::Array => value.is_array(),
+            Kind::Any => true,
+        }
+    }
+}
+
+/// A field of an event payload
+struct Field {
+    name: &'static str,
+    kind: Kind,
+    required: bool,
+}
+
+const fn req(name: &'static str, kind: Kind) -> Field {
+    Field {
+        name,
+        kind,
+        required: true,
+    }
+}
+
+const fn opt(name: &'static str, kind: Kind) -> Field {
+    Field {
+        name,
+        kind,
+        required: false,
+    }
+}
+
+const POST: &[Field] = &[
+    req("id", Kind::String),
+    req("create_at", Kind::Number),
+    req("update_at", Kind::Number),
+    req("delete_at", Kind::Number),
+    req("edit_at", Kind::Number),
+    req("user_id", Kind::String),
+    req("channel_id", Kind::String),
+    req("message", Kind::String),
+    opt("is_pinned", Kind::Bool),
+    opt("root_id", Kind::String),
+    opt("parent_id", Kind::String),
+    opt("original_id", Kind::String),
+    opt("type", Kind::String),
+    opt("props", Kind::Object),
+    opt("hashtags", Kind::String),
+    opt("file_ids", Kind::Array),
+    opt("pending_post_id", Kind::String),
+    opt("metadata", Kind::Object),
+    opt("reply_count", Kind::Number),
+    opt("last_reply_at", Kind::Number),
+    opt("participants", Kind::Any),
+    opt("is_following", Kind::Bool),
+    opt("remote_id", Kind::String),
+];
+
+const POSTED: &[Field] = &[
+    req("post", Kind::Encoded(POST)),
+    opt("channel_display_name", Kind::String),
+    opt("channel_name", Kind::String),
+    opt("channel_type", Kind::String),
+    opt("sender_name", Kind::String),
+    opt("team_id", Kind::String),
+    opt("set_online", Kind::Bool),
+    opt("mentions", Kind::String),
+    opt("followers", Kind::String),
+    opt("image", Kind::Bool),
+    opt("otherFile", Kind::Bool),
+    opt("should_ack", Kind::Bool),
+];
+
+const POST_EDITED: &[Field] = &[req("post", Kind::Encoded(POST))];
+
+const POST_DELETED: &[Field] = &[
+    req("post", Kind::Encoded(POST)),
+    opt("delete_by", Kind::String),
+];
+
+const TYPING: &[Field] = &[req("user_id", Kind::String), opt("parent_id", Kind::String)];
+
+const USER_ADDED: &[Field] = &[req("user_id", Kind::String), opt("team_id", Kind::String)];
+
+const USER_REMOVED: &[Field] = &[
+    req("user_id", Kind::String),
+    opt("channel_id", Kind::String),
+    opt("remover_id", Kind::String),
+];
+
+const CHANNEL_CREATED: &[Field] = &[
+    req("channel", Kind::Object),
+    opt("channel_id", Kind::String),
+    opt("team_id", Kind::String),
+];
+
+const CHANNEL_UPDATED: &[Field] = &[req("channel", Kind::Object)];
+
+const CHANNEL_DELETED: &[Field] = &[
+    opt("channel_id", Kind::String),
+    opt("delete_at", Kind::Number),
+];
+
+const STATUS_CHANGE: &[Field] = &[
+    req("user_id", Kind::String),
+    req("status", Kind::String),
+    opt("manual", Kind::Bool),
+    opt("last_activity_at", Kind::Number),
+];
+
+const REACTION: &[Field] = &[
+    req("post_id", Kind::String),
+    req("user_id", Kind::String),
+    req("emoji_name", Kind::String),
+    opt("create_at", Kind::Number),
+    opt("channel_id", Kind::String),
+];
+
+const PREFERENCE_CHANGED: &[Field] = &[
+    req("category", Kind::String),
+    req("name", Kind::String),
+    req("value", Kind::String),
+    opt("user_id", Kind::String),
+];
+
+const EPHEMERAL_MESSAGE: &[Field] = &[req("post", Kind::String)];
+
+const USER_ID_ONLY: &[Field] = &[req("user_id", Kind::String)];
+
+const USER_UPDATED: &[Field] = &[req("user", Kind::Object)];
+
+const USER_ROLE_UPDATED: &[Field] = &[req("user_id", Kind::String), opt("roles", Kind::String)];
+
+const THREAD_UPDATED: &[Field] = &[
+    opt("thread_id", Kind::String),
+    opt("post_id", Kind::String),
+    opt("thread", Kind::String),
+];
+
+const THREAD_READ_CHANGED: &[Field] = &[
+    opt("thread_id", Kind::String),
+    opt("post_id", Kind::String),
+    opt("channel_id", Kind::String),
+    opt("timestamp", Kind::Number),
+    opt("unread_mentions", Kind::Number),
+    opt("unread_replies", Kind::Number),
+    opt("previous_unread_mentions", Kind::Number),
+    opt("previous_unread_replies", Kind::Number),
+];
+
+const THREAD_FOLLOW_CHANGED: &[Field] = &[
+    opt("thread_id", Kind::String),
+    opt("post_id", Kind::String),
+    req("state", Kind::Bool),
+    opt("reply_count", Kind::Number),
+];
+
+const POST_UNREAD: &[Field] = &[
+    req("post_id", Kind::String),
+    req("user_id", Kind::String),
+    opt("channel_id", Kind::String),
+    opt("team_id", Kind::String),
+    opt("msg_count", Kind::Number),
+    opt("msg_count_root", Kind::Number),
+    opt("mention_count", Kind::Number),
+    opt("mention_count_root", Kind::Number),
+    opt("urgent_mention_count", Kind::Number),
+    opt("last_viewed_at", Kind::Number),
+    opt("delta_msgs", Kind::Number),
+];
+
+const EMOJI_ADDED: &[Field] = &[
+    req("id", Kind::String),
+    req("name", Kind::String),
+    opt("creator_id", Kind::String),
+    opt("create_at", Kind::Number),
+];
+
+const TEAM_MEMBERSHIP: &[Field] = &[req("team_id", Kind::String), req("user_id", Kind::String)];
+
+const CHANNEL_MEMBER_UPDATED: &[Field] = &[
+    req("user_id", Kind::String),
+    opt("channelMember", Kind::String),
+];
+
+const TEAM_ONLY: &[Field] = &[req("team_id", Kind::String), opt("team", Kind::Any)];
+
+const PREFERENCES_DELETED: &[Field] = &[
+    req("category", Kind::String),
+    req("name", Kind::String),
+    opt("user_id", Kind::String),
+];
+
+const ROLE_UPDATED: &[Field] = &[req("role_id", Kind::String), opt("role", Kind::Any)];
+
+/// Look up the expected `data` fields for an event type
+///
+/// Returns None for event types that are not validated.
+fn schema_for(event: &str) -> Option<&'static [Field]> {
+    let schema = match event {
+        "posted" => POSTED,
+        "post_edited" => POST_EDITED,
+        "post_deleted" => POST_DELETED,
+        "typing" => TYPING,
+        "user_added" => USER_ADDED,
+        "user_removed" => USER_REMOVED,
+        "channel_created" => CHANNEL_CREATED,
+        "channel_updated" => CHANNEL_UPDATED,
+        "channel_deleted" => CHANNEL_DELETED,
+        "status_change" => STATUS_CHANGE,
+        "reaction_added" | "reaction_removed" => REACTION,
+        "preference_changed" => PREFERENCE_CHANGED,
+        "ephemeral_message" => EPHEMERAL_MESSAGE,
+        "new_user" | "memberrole_updated" => USER_ID_ONLY,
+        "user_updated" => USER_UPDATED,
+        "user_role_updated" => USER_ROLE_UPDATED,
+        "thread_updated" => THREAD_UPDATED,
+        "thread_read_changed" => THREAD_READ_CHANGED,
+        "thread_follow_changed" => THREAD_FOLLOW_CHANGED,
+        "post_unread" => POST_UNREAD,
+        "emoji_added" => EMOJI_ADDED,
+        "added_to_team" | "leave_team" => TEAM_MEMBERSHIP,
+        "channel_member_updated" => CHANNEL_MEMBER_UPDATED,
+        "delete_team" | "update_team" => TEAM_ONLY,
+        "preferences_deleted" => PREFERENCES_DELETED,
+        "role_updated" => ROLE_UPDATED,
+        _ => return None,
+    };
+    Some(schema)
+}
+
+/// Validate an event's `data` object against its expected schema
+///
+/// # Returns
+/// The mismatches found; empty if the payload matches or the event type
+/// is not validated
+pub fn validate_event(ws_event: &WebSocketEvent) -> Vec<SchemaIssue> {
+    let Some(schema) = schema_for(&ws_event.event) else {
+        return Vec::new();
+    };
+
+    let mut issues = Vec::new();
+    let fields: Vec<(&str, &Value)> = ws_event.data.iter().map(|(k, v)| (k.as_str(), v)).collect();
+    check_fields("data", &fields, schema, &mut issues);
+    issues
+}
+
+fn check_fields(
+    path: &str,
+    fields: &[(&str, &Value)],
+    schema: &[Field],
+    issues: &mut Vec<SchemaIssue>,
+) {
+    for &(name, value) in fields {
+        let field_path = format!("{path}.{name}");
+        let Some(field) = schema.iter().find(|f| f.name == name) else {
+            issues.push(SchemaIssue::new(field_path, "unknown field"));
+            continue;
+        };
+
+        // Optional fields are commonly sent as null
+        if value.is_null() && !field.required {
+            continue;
+        }
+
+        if !field.kind.matches(value) {
+            issues.push(SchemaIssue::new(
+                field_path,
+                format!(
+                    "expected {}, found {}",
+                    field.kind.name(),
+                    json_type_name(value)
+                ),
+            ));
+            continue;
+        }
+
+        if let (Kind::Encoded(inner), Some(encoded)) = (field.kind, value.as_str()) {
+            match serde_json::from_str::<serde_json::Map<String, Value>>(encoded) {
+                Ok(object) => {
+                    let inner_fields: Vec<(&str, &Value)> =
+                        object.iter().map(|(k, v)| (k.as_str(), v)).collect();
+                    check_fields(&field_path, &inner_fields, inner, issues);
+                }
+                Err(e) => issues.push(SchemaIssue::new(
+                    field_path,
+                    format!("invalid encoded JSON object: {e}"),
+                )),
+            }
+        }
+    }
+
+    for field in schema.iter().filter(|f| f.required) {
+        if !fields.iter().any(|(name, _)| *name == field.name) {
+            issues.push(SchemaIssue::new(
+                format!("{path}.{}", field.name),
+                "missing required field",
+            ));
+        }
+    }
+}
+
+fn json_type_name(value: &Value) -> &'static str {
+    match value {
+        Value::Null => "null",
+        Value::Bool(_) => "bool",
+        Value::Number(_) => "number",
+        Value::String(_) => "string",
+        Value::Array(_) => "array",
+        Value::Object(_) => "object",
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn event(json: &str) -> WebSocketEvent {
+        serde_json::from_str(json).unwrap()
+    }
+
+    #[test]
+    fn test_valid_event_has_no_issues() {
+        let ws_event = event(
+            r#"{
+                "event": "reaction_added",
+                "data": {"post_id": "post1", "user_id": "user1", "emoji_name": "+1"},
+                "broadcast": {"channel_id": "ch1"},
+                "seq": 1
+            }"#,
+        );
+
+        assert!(validate_event(&ws_event).is_empty());
+    }
+
+    #[test]
+    fn test_unknown_and_missing_fields() {
+        let ws_event = event(
+            r#"{
+                "event": "typing",
+                "data": {"parent_id": "", "typing_since": 1},
+                "broadcast": {"channel_id": "ch1"},
+                "seq": 1
+            }"#,
+        );
+
+        let issues = validate_event(&ws_event);
+        assert_eq!(issues.len(), 2);
+        assert!(issues
+            .iter()
+            .any(|i| i.path == "data.typing_since" && i.problem == "unknown field"));
+        assert!(issues
+            .iter()
+            .any(|i| i.path == "data.user_id" && i.problem == "missing required field"));
+    }
+
+    #[test]
+    fn test_type_drift_in_encoded_post() {
+        let ws_event = event(
+            r#"{
+                "event": "post_edited",
+                "data": {
+                    "post": "{\"id\":\"p1\",\"create_at\":\"1\",\"update_at\":1,\"delete_at\":0,\"edit_at\":1,\"user_id\":\"u1\",\"channel_id\":\"c1\",\"message\":\"hi\"}"
+                },
+                "broadcast": {"channel_id": "c1"},
+                "seq": 1
+            }"#,
+        );
+
+        let issues = validate_event(&ws_event);
+        assert_eq!(issues.len(), 1);
+        assert_eq!(issues[0].path, "data.post.create_at");
+        assert_eq!(issues[0].problem, "expected number, found string");
+    }
+
+    #[test]
+    fn test_unvalidated_event_type() {
+        let ws_event = event(r#"{"event": "hello", "data": {"server_version": "9.0"}}"#);
+        assert!(validate_event(&ws_event).is_empty());
+    }
+}
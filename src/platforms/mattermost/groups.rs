@@ -0,0 +1,156 @@
+//! Custom user group operations for Mattermost
+//!
+//! Custom groups are created by users and can be @-mentioned anywhere. Groups
+//! synced from a directory (LDAP) are read-only here.
+
+use super::client::MattermostClient;
+use super::compat::{unsupported_if_missing, ServerVersion};
+use super::types::{MattermostGroup, TeamGroups};
+use crate::error::Result;
+use crate::types::NewUserGroup;
+
+const GROUPS_FEATURE: &str = "Custom user groups";
+/// First server version with custom user groups
+const GROUPS_MIN_VERSION: ServerVersion = ServerVersion::new(6, 3, 0);
+
+impl MattermostClient {
+    /// Create a custom user group
+    ///
+    /// # Arguments
+    /// * `group` - The group to create, with its initial members
+    ///
+    /// # Returns
+    /// A Result containing the created group
+    ///
+    /// # API Endpoint
+    /// POST /groups
+    pub async fn create_group(&self, group: &NewUserGroup) -> Result<MattermostGroup> {
+        group.validate()?;
+
+        self.require_server_version(GROUPS_FEATURE, GROUPS_MIN_VERSION)
+            .await?;
+        let body = serde_json::json!({
+            "group": {
+                "name": group.mention_name(),
+                "display_name": group.display_name,
+                "description": group.description,
+                "source": "custom",
+                "allow_reference": true,
+            },
+            "user_ids": group.user_ids,
+        });
+        let response = self.post("/groups", &body).await?;
+        self.handle_response(response)
+            .await
+            .map_err(|e| unsupported_if_missing(GROUPS_FEATURE, e))
+    }
+
+    /// Add users to a custom group
+    ///
+    /// # API Endpoint
+    /// POST /groups/{group_id}/members
+    pub async fn add_group_members(&self, group_id: &str, user_ids: &[String]) -> Result<()> {
+        self.require_server_version(GROUPS_FEATURE, GROUPS_MIN_VERSION)
+            .await?;
+        let endpoint = format!("/groups/{group_id}/members");
+        let body = serde_json::json!({ "user_ids": user_ids });
+        let response = self.post(&endpoint, &body).await?;
+        let _: serde_json::Value = self
+            .handle_response(response)
+            .await
+            .map_err(|e| unsupported_if_missing(GROUPS_FEATURE, e))?;
+        Ok(())
+    }
+
+    /// Remove users from a custom group
+    ///
+    /// # API Endpoint
+    /// DELETE /groups/{group_id}/members
+    pub async fn remove_group_members(&self, group_id: &str, user_ids: &[String]) -> Result<()> {
+        self.require_server_version(GROUPS_FEATURE, GROUPS_MIN_VERSION)
+            .await?;
+        let endpoint = format!("/groups/{group_id}/members");
+        let body = serde_json::json!({ "user_ids": user_ids });
+        let response = self.delete_with_body(&endpoint, &body).await?;
+        let _: serde_json::Value = self
+            .handle_response(response)
+            .await
+            .map_err(|e| unsupported_if_missing(GROUPS_FEATURE, e))?;
+        Ok(())
+    }
+
+    /// Get the groups that can be @-mentioned in a team
+    ///
+    /// In teams whose membership is managed by linked groups only those
+    /// groups are mentionable; elsewhere every referenceable group is.
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team
+    /// * `query` - Only return groups whose name or display name matches
+    /// * `page` - Page number (0-indexed)
+    /// * `per_page` - Number of groups per page
+    ///
+    /// # API Endpoint
+    /// GET /teams/{team_id}/groups or GET /groups
+    pub async fn get_mentionable_groups(
+        &self,
+        team_id: &str,
+        query: Option<&str>,
+        page: u32,
+        per_page: u32,
+    ) -> Result<Vec<MattermostGroup>> {
+        self.require_server_version(GROUPS_FEATURE, GROUPS_MIN_VERSION)
+            .await?;
+        let team = self.get_team_cached(team_id).await?;
+
+        let mut params = format!("filter_allow_reference=true&page={page}&per_page={per_page}");
+        if let Some(query) = query.filter(|q| !q.is_empty()) {
+            let query: String = url::form_urlencoded::byte_serialize(query.as_bytes()).collect();
+            params.push_str(&format!("&q={query}"));
+        }
+
+        if team.group_constrained == Some(true) {
+            let endpoint = format!("/teams/{team_id}/groups?{params}");
+            let response = self.get(&endpoint).await?;
+            let groups: TeamGroups = self
+                .handle_response(response)
+                .await
+                .map_err(|e| unsupported_if_missing(GROUPS_FEATURE, e))?;
+            Ok(groups.groups)
+        } else {
+            let endpoint = format!("/groups?{params}&include_member_count=true");
+            let response = self.get(&endpoint).await?;
+            self.handle_response(response)
+                .await
+                .map_err(|e| unsupported_if_missing(GROUPS_FEATURE, e))
+        }
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_group_deserialization() {
+        let json = r#"{
+            "id": "grp1",
+            "name": "backend",
+            "display_name": "Backend",
+            "description": "",
+            "source": "custom",
+            "remote_id": null,
+            "create_at": 1700000000000,
+            "update_at": 1700000000000,
+            "delete_at": 0,
+            "has_syncables": false,
+            "member_count": 4,
+            "allow_reference": true
+        }"#;
+
+        let group: MattermostGroup = serde_json::from_str(json).unwrap();
+        assert_eq!(group.source, "custom");
+        assert_eq!(group.member_count, Some(4));
+        assert!(group.allow_reference);
+    }
+}
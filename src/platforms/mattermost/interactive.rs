@@ -0,0 +1,76 @@
+//! Interactive message and dialog operations for Mattermost
+
+use std::collections::HashMap;
+
+use super::client::MattermostClient;
+use super::types::{CreatePostRequest, MattermostPost, OpenDialogRequest};
+use crate::error::{Error, ErrorCode, Result};
+use crate::types::{InteractiveDialog, MessageAttachment};
+
+impl MattermostClient {
+    /// Send a message carrying rich attachments, such as action buttons and menus
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel to send the message to
+    /// * `message` - The message text (may be empty when attachments carry the content)
+    /// * `attachments` - The attachments, stored in the post's `attachments` prop
+    ///
+    /// # Returns
+    /// A Result containing the created post or an Error
+    pub async fn send_message_with_attachments(
+        &self,
+        channel_id: &str,
+        message: &str,
+        attachments: &[MessageAttachment],
+    ) -> Result<MattermostPost> {
+        let attachments = serde_json::to_value(attachments).map_err(|e| {
+            Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Failed to serialize attachments: {e}"),
+            )
+        })?;
+        let request = CreatePostRequest::new(channel_id.to_string(), message.to_string())
+            .with_props(HashMap::from([("attachments".to_string(), attachments)]));
+
+        let response = self.post("/posts", &request).await?;
+        self.handle_response(response).await
+    }
+
+    /// Open an interactive dialog for the user who triggered an action
+    ///
+    /// # Arguments
+    /// * `trigger_id` - The trigger ID from an action callback or slash command
+    /// * `url` - The URL the submission is posted to
+    /// * `dialog` - The dialog to show
+    ///
+    /// # Returns
+    /// A Result indicating success or failure
+    ///
+    /// # Notes
+    /// Trigger IDs expire a few seconds after they are issued, so open the
+    /// dialog while handling the callback.
+    pub async fn open_interactive_dialog(
+        &self,
+        trigger_id: &str,
+        url: &str,
+        dialog: &InteractiveDialog,
+    ) -> Result<()> {
+        let request = OpenDialogRequest {
+            trigger_id: trigger_id.to_string(),
+            url: url.to_string(),
+            dialog: dialog.clone(),
+        };
+        let response = self.post("/actions/dialogs/open", &request).await?;
+        let status = response.status();
+
+        if status.is_success() {
+            Ok(())
+        } else {
+            Err(Error::new(
+                ErrorCode::NetworkError,
+                format!("Failed to open interactive dialog: {status}"),
+            )
+            .with_http_status(status.as_u16()))
+        }
+    }
+}
@@ -5,30 +5,49 @@
 //! `api-spec.yaml` in this directory.
 
 mod auth;
+mod bookmarks;
+mod bots;
 mod cache;
 mod channels;
 mod client;
+mod commands;
+mod compat;
 mod convert;
+mod event_schema;
 mod files;
+mod groups;
+mod interactive;
 mod pinned;
 mod platform_impl;
 mod posts;
 mod preferences;
+mod priority;
+mod proxy;
 mod reactions;
+mod roles;
 mod search;
+mod sessions;
+mod sidebar;
 mod status;
+mod system;
 mod teams;
 mod threads;
+mod tls;
 mod types;
 mod users;
+mod webhooks;
 mod websocket;
 
 pub use cache::Cache;
-pub use client::{MattermostClient, RateLimitInfo};
+pub use client::{MattermostClient, RateLimitInfo, Timeouts};
+pub use compat::{is_missing_endpoint, unsupported_if_missing, ServerVersion};
 pub use convert::{status_string_to_user_status, user_status_to_status_string};
 pub use platform_impl::MattermostPlatform;
+pub use proxy::ProxyConfig;
 pub use search::{
     ChannelSearchRequest, FileSearchRequest, FileSearchResponse, FileSearchResult,
     PostSearchOptions, UserSearchRequest,
 };
+pub use tls::TlsConfig;
 pub use types::*;
+pub use webhooks::send_incoming_webhook;
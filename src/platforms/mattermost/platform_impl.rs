@@ -1,17 +1,30 @@
 use async_trait::async_trait;
+use std::collections::{HashMap, VecDeque};
+use std::sync::atomic::{AtomicBool, AtomicI64, Ordering};
 use std::sync::Arc;
 use tokio::sync::Mutex;
 
 use crate::error::{Error, ErrorCode, Result};
-use crate::platforms::platform_trait::{Platform, PlatformConfig, PlatformEvent};
+use crate::logging::diag;
+use crate::platforms::platform_trait::{EventFilter, Platform, PlatformConfig, PlatformEvent};
 use crate::types::{
-    Attachment, Channel, ConnectionInfo, Message, PlatformCapabilities, Team, User,
+    sort_chronologically, Attachment, Channel, ChannelBookmark, ChannelChanges, ConnectionInfo,
+    Message, NewChannelBookmark, PlatformCapabilities, SidebarCategory, Team, User,
 };
 
-use super::client::MattermostClient;
+use super::client::{request_headers, MattermostClient, RateLimitPolicy, RetryPolicy, Timeouts};
 use super::convert::ConversionContext;
+use super::proxy::ProxyConfig;
+use super::tls::TlsConfig;
 use super::websocket::WebSocketManager;
 
+/// How far before an event gap to look for missed posts, to allow for clock
+/// skew between this host and the server; duplicates are filtered out
+const GAP_RECOVERY_MARGIN_MS: i64 = 5_000;
+
+/// Number of delivered post IDs remembered to filter duplicates
+const RECENT_POSTS_LIMIT: usize = 1_000;
+
 /// Wrapper struct that implements the Platform trait for Mattermost
 pub struct MattermostPlatform {
     client: MattermostClient,
@@ -19,6 +32,19 @@ pub struct MattermostPlatform {
     websocket: Arc<Mutex<Option<WebSocketManager>>>,
     server_url: String,
     capabilities: PlatformCapabilities,
+    strict_events: AtomicBool,
+    /// WebSocket connection to resume on the next subscribe, set by restore_state
+    resume_point: Option<(String, i64)>,
+    /// Last seen state of channels, to report what a channel update changed
+    channel_states: Mutex<HashMap<String, Channel>>,
+    /// Posted events recovered after an event gap, delivered before live events
+    recovered_events: Mutex<VecDeque<PlatformEvent>>,
+    /// IDs of the posts most recently delivered as MessagePosted events
+    recent_posts: Mutex<VecDeque<String>>,
+    /// Sequence number of the event last returned by poll_event, 0 if none
+    last_polled_seq: AtomicI64,
+    /// Scope of the event subscription; None delivers every event
+    event_filter: Option<EventFilter>,
 }
 
 impl MattermostPlatform {
@@ -31,9 +57,178 @@ impl MattermostPlatform {
             websocket: Arc::new(Mutex::new(None)),
             server_url: server_url.to_string(),
             capabilities: PlatformCapabilities::mattermost(),
+            strict_events: AtomicBool::new(false),
+            resume_point: None,
+            channel_states: Mutex::new(HashMap::new()),
+            recovered_events: Mutex::new(VecDeque::new()),
+            recent_posts: Mutex::new(VecDeque::new()),
+            last_polled_seq: AtomicI64::new(0),
+            event_filter: None,
         })
     }
 
+    /// Apply the proxy, TLS, timeout, header, rate limit and retry settings
+    /// of `config` to the client
+    ///
+    /// They must be in place before the first request, whether the session
+    /// comes from `connect` or `restore_state`.
+    fn apply_network_config(&mut self, config: &PlatformConfig) -> Result<()> {
+        let proxy = match config.proxy_url.as_deref() {
+            Some(url) if !url.is_empty() => {
+                Some(ProxyConfig::new(url, config.no_proxy.as_deref())?)
+            }
+            _ => None,
+        };
+        self.client.set_proxy(proxy)?;
+        self.client
+            .set_tls(TlsConfig::from_platform_config(config)?)?;
+        self.client
+            .set_timeouts(Timeouts::from_platform_config(config))?;
+        self.client.set_headers(request_headers(config)?)?;
+        self.client
+            .set_rate_limit_policy(RateLimitPolicy::from_platform_config(config));
+        self.client
+            .set_retry_policy(RetryPolicy::from_platform_config(config)?);
+        Ok(())
+    }
+
+    /// Recover the posts of an event gap left by a reconnect, if there is one
+    ///
+    /// On failure the gap is kept so the next poll retries.
+    async fn recover_event_gap(&self) -> Result<()> {
+        let since = match self.websocket.lock().await.as_ref() {
+            Some(ws) => ws.take_event_gap().await,
+            None => None,
+        };
+        let Some(since) = since else {
+            return Ok(());
+        };
+
+        diag!(
+            Info,
+            "Recovering posts missed since {since} after a reconnect"
+        );
+        if let Err(e) = self.recover_missed_posts(since).await {
+            diag!(
+                Warning,
+                "Recovering missed posts failed, retrying on the next poll: {}",
+                e.message
+            );
+            if let Some(ws) = self.websocket.lock().await.as_ref() {
+                ws.restore_event_gap(since).await;
+            }
+            return Err(e);
+        }
+        Ok(())
+    }
+
+    /// Fetch the posts created since `since` (Unix milliseconds) in every
+    /// channel of the user and queue them as MessagePosted events, oldest first
+    async fn recover_missed_posts(&self, since: i64) -> Result<()> {
+        let since = (since - GAP_RECOVERY_MARGIN_MS).max(0);
+
+        let mut missed = Vec::new();
+        let channels = self.client.get_all_channels_for_user().await?;
+        for channel in channels.iter().filter(|c| c.last_post_at >= since) {
+            let post_list = self
+                .client
+                .get_posts_since(&channel.id, since as u64)
+                .await?;
+            // The result also holds posts edited or deleted since
+            missed.extend(
+                post_list
+                    .posts
+                    .into_values()
+                    .filter(|post| post.create_at >= since && post.delete_at == 0),
+            );
+        }
+        missed.sort_by_key(|post| post.create_at);
+
+        let recent = self.recent_posts.lock().await;
+        let mut queue = self.recovered_events.lock().await;
+        for post in missed {
+            if !recent.contains(&post.id) {
+                queue.push_back(PlatformEvent::MessagePosted(post.into()));
+            }
+        }
+        Ok(())
+    }
+
+    /// Remember a delivered post; returns false if it was delivered before
+    async fn remember_post(&self, event: &PlatformEvent) -> bool {
+        let PlatformEvent::MessagePosted(message) = event else {
+            return true;
+        };
+        let mut recent = self.recent_posts.lock().await;
+        if recent.contains(&message.id) {
+            return false;
+        }
+        if recent.len() >= RECENT_POSTS_LIMIT {
+            recent.pop_front();
+        }
+        recent.push_back(message.id.clone());
+        true
+    }
+
+    /// Whether an event is within the subscription's filter
+    ///
+    /// The team of a channel event is looked up in the channel cache, and
+    /// only when the filter lists teams the channel isn't matched by.
+    async fn event_in_scope(&self, event: &PlatformEvent) -> bool {
+        let Some(filter) = &self.event_filter else {
+            return true;
+        };
+        let mut channel_team_id = None;
+        if let Some(channel_id) = event.channel_id() {
+            if !filter.team_ids.is_empty() && !filter.channel_ids.iter().any(|id| id == channel_id)
+            {
+                channel_team_id = self
+                    .client
+                    .get_channel_cached(channel_id)
+                    .await
+                    .ok()
+                    .map(|channel| channel.team_id);
+            }
+        }
+        filter.matches(event, channel_team_id.as_deref())
+    }
+
+    /// Remember channel states from channel events, and fill in what a
+    /// channel update changed compared to the last state seen
+    ///
+    /// Falls back to the channel cache for channels with no event yet; when
+    /// neither knows the channel the changes are left unset.
+    async fn track_channel_state(&self, event: &mut PlatformEvent) {
+        match event {
+            PlatformEvent::ChannelCreated(channel) => {
+                self.channel_states
+                    .lock()
+                    .await
+                    .insert(channel.id.clone(), channel.clone());
+            }
+            PlatformEvent::ChannelUpdated { channel, changes } => {
+                let previous = self
+                    .channel_states
+                    .lock()
+                    .await
+                    .insert(channel.id.clone(), channel.clone());
+                let previous = match previous {
+                    Some(previous) => Some(previous),
+                    None => self
+                        .client
+                        .peek_channel_cache(&channel.id)
+                        .await
+                        .map(Channel::from),
+                };
+                *changes = previous.map(|previous| ChannelChanges::between(&previous, channel));
+            }
+            PlatformEvent::ChannelDeleted { channel_id } => {
+                self.channel_states.lock().await.remove(channel_id.as_str());
+            }
+            _ => {}
+        }
+    }
+
     /// Get the underlying client (for accessing Mattermost-specific methods)
     pub fn client(&self) -> &MattermostClient {
         &self.client
@@ -45,8 +240,21 @@ impl MattermostPlatform {
         mm_channel: super::types::MattermostChannel,
         current_user_id: Option<&str>,
     ) -> Result<Channel> {
-        use super::channels::get_dm_partner_id;
-        use super::convert::ConversionContext;
+        self.convert_channels_with_context(vec![mm_channel], current_user_id)
+            .await
+            .pop()
+            .ok_or_else(|| Error::new(ErrorCode::Unknown, "Channel conversion failed"))
+    }
+
+    /// Convert channels like `convert_channel_with_context`, resolving the DM
+    /// partners of all of them in one request and the group participants in
+    /// another, rather than making requests per channel
+    async fn convert_channels_with_context(
+        &self,
+        mm_channels: Vec<super::types::MattermostChannel>,
+        current_user_id: Option<&str>,
+    ) -> Vec<Channel> {
+        use super::channels::{get_dm_partner_id, group_member_ids};
 
         // Create conversion context with server URL and current user
         let mut ctx = ConversionContext::new(self.server_url.clone());
@@ -54,56 +262,101 @@ impl MattermostPlatform {
             ctx = ctx.with_current_user(user_id.to_string());
         }
 
-        // Convert the channel with context
-        let mut channel = mm_channel.to_channel_with_context(&ctx);
-
-        // For DM channels, fetch the other user's name and use it as display name
         // Note: DM channel "name" field contains user IDs in format "user1id__user2id"
-        if mm_channel.channel_type.is_direct() {
-            if let Some(user_id) = current_user_id {
-                // Check if this is a self-DM (saved messages) - both user IDs are the same
-                if mm_channel.name == format!("{user_id}__{user_id}") {
-                    // This is a DM with yourself
-                    channel.display_name = "You (Saved Messages)".to_string();
-                } else if let Some(partner_id) = get_dm_partner_id(&mm_channel.name, user_id) {
-                    // Regular DM with another user - use the "name" field which contains user IDs
-                    match self.client.get_user(&partner_id).await {
-                        Ok(partner_user) => {
-                            // Build display name from partner's information
-                            let display_name = if !partner_user.first_name.is_empty()
-                                || !partner_user.last_name.is_empty()
-                            {
-                                format!("{} {}", partner_user.first_name, partner_user.last_name)
-                                    .trim()
-                                    .to_string()
-                            } else if !partner_user.nickname.is_empty() {
-                                partner_user.nickname.clone()
-                            } else {
-                                partner_user.username.clone()
-                            };
-                            channel.display_name = display_name;
-                        }
-                        Err(_) => {
-                            // Fall back to a generic name
-                            channel.display_name = "Direct Message".to_string();
-                        }
+        let mut partner_ids: Vec<String> = mm_channels
+            .iter()
+            .filter(|c| c.channel_type.is_direct())
+            .filter_map(|c| get_dm_partner_id(&c.name, current_user_id?))
+            .collect();
+        partner_ids.sort();
+        partner_ids.dedup();
+        // A failed lookup falls back to generic names below
+        let partners: HashMap<String, super::types::MattermostUser> = if partner_ids.is_empty() {
+            HashMap::new()
+        } else {
+            self.client
+                .get_users_by_ids_cached(&partner_ids)
+                .await
+                .map(|users| users.into_iter().map(|u| (u.id.clone(), u)).collect())
+                .unwrap_or_default()
+        };
+
+        let group_ids: Vec<String> = mm_channels
+            .iter()
+            .filter(|c| c.channel_type.is_group())
+            .map(|c| c.id.clone())
+            .collect();
+        let mut group_users = self
+            .client
+            .get_group_channel_users(&group_ids)
+            .await
+            .unwrap_or_default();
+
+        let mut channels = Vec::with_capacity(mm_channels.len());
+        for mm_channel in mm_channels {
+            let mut channel = mm_channel.to_channel_with_context(&ctx);
+
+            // For DM channels, use the other user's name as display name
+            if mm_channel.channel_type.is_direct() {
+                if let Some(user_id) = current_user_id {
+                    // Check if this is a self-DM (saved messages) - both user IDs are the same
+                    if mm_channel.name == format!("{user_id}__{user_id}") {
+                        channel.display_name = "You (Saved Messages)".to_string();
+                    } else if let Some(partner_id) = get_dm_partner_id(&mm_channel.name, user_id) {
+                        channel.display_name = match partners.get(&partner_id) {
+                            Some(partner) => user_display_name(partner),
+                            None => "Direct Message".to_string(),
+                        };
                     }
                 }
             }
-        }
-        // For group channels, we could fetch all participants and build a name
-        // For now, we'll use the existing display_name from the API
-        else if mm_channel.channel_type.is_group()
-            && (mm_channel.display_name.is_empty() || current_user_id.is_some())
-        {
-            // Group channels may need similar treatment
-            // This could be enhanced in the future to fetch all member names
-            if channel.display_name.is_empty() {
-                channel.display_name = "Group Message".to_string();
+            // For group channels, name the participants so clients get a
+            // readable name instead of the channel's hashed "name" field
+            else if mm_channel.channel_type.is_group() {
+                if let Some(users) = group_users.remove(&mm_channel.id) {
+                    let mut names: Vec<String> = users
+                        .iter()
+                        .filter(|u| Some(u.id.as_str()) != current_user_id)
+                        .map(user_display_name)
+                        .collect();
+                    names.sort();
+                    if !names.is_empty() {
+                        channel.display_name = names.join(", ");
+                    }
+                    channel.member_ids = Some(group_member_ids(&users, current_user_id));
+                }
+
+                if channel.display_name.is_empty() {
+                    channel.display_name = "Group Message".to_string();
+                }
             }
+
+            channels.push(channel);
         }
 
-        Ok(channel)
+        channels
+    }
+
+    /// Read the user's email batching interval preference, if one is set
+    async fn email_batching_interval(&self, user_id: &str) -> Option<String> {
+        self.client
+            .get_user_preference(user_id, "notifications", "email_interval")
+            .await
+            .ok()
+            .map(|pref| pref.value)
+    }
+}
+
+/// Build a human-readable name for a user (full name, then nickname, then username)
+fn user_display_name(user: &super::types::MattermostUser) -> String {
+    if !user.first_name.is_empty() || !user.last_name.is_empty() {
+        format!("{} {}", user.first_name, user.last_name)
+            .trim()
+            .to_string()
+    } else if !user.nickname.is_empty() {
+        user.nickname.clone()
+    } else {
+        user.username.clone()
     }
 }
 
@@ -114,6 +367,9 @@ impl Platform for MattermostPlatform {
     }
 
     async fn connect(&mut self, config: PlatformConfig) -> Result<ConnectionInfo> {
+        diag!(Info, "Connecting to {}", self.server_url);
+        self.apply_network_config(&config)?;
+
         // Determine authentication method from credentials
         if let Some(token) = config.credentials.get("token") {
             // Use Personal Access Token or existing session token
@@ -143,9 +399,15 @@ impl Platform for MattermostPlatform {
         if let Some(team_id) = config.team_id {
             self.client.set_team_id(Some(team_id)).await;
         }
+        self.resume_point = None;
 
         // Get the current user to build connection info
         let current_user = self.client.get_current_user().await?;
+        self.capabilities.platform_version = self
+            .client
+            .server_version()
+            .await
+            .map(|version| version.to_string());
 
         // Get connection info
         let conn_info = self
@@ -153,11 +415,18 @@ impl Platform for MattermostPlatform {
             .connection_info(&self.server_url, &current_user.username)
             .await;
         self.connection_info = Some(conn_info.clone());
+        diag!(
+            Info,
+            "Connected to {} as @{}",
+            self.server_url,
+            current_user.username
+        );
 
         Ok(conn_info)
     }
 
     async fn disconnect(&mut self) -> Result<()> {
+        diag!(Info, "Disconnecting from {}", self.server_url);
         // Disconnect WebSocket if connected
         if let Some(ws) = self.websocket.lock().await.as_mut() {
             ws.disconnect().await;
@@ -170,6 +439,72 @@ impl Platform for MattermostPlatform {
         Ok(())
     }
 
+    async fn save_state(&self) -> Result<crate::types::SessionState> {
+        let token = self.client.get_token().await.ok_or_else(|| {
+            Error::new(
+                ErrorCode::InvalidState,
+                "Not authenticated - nothing to save",
+            )
+        })?;
+
+        // Before the first subscribe after a restore, keep the restored position
+        let (connection_id, last_sequence) = match self.websocket.lock().await.as_ref() {
+            Some(ws) => ws.resume_point().await,
+            None => match &self.resume_point {
+                Some((id, seq)) => (Some(id.clone()), *seq),
+                None => (None, 0),
+            },
+        };
+
+        Ok(crate::types::SessionState {
+            server_url: self.server_url.clone(),
+            token,
+            user_id: self.client.get_user_id().await,
+            team_id: self.client.get_team_id().await,
+            connection_id,
+            last_sequence,
+        })
+    }
+
+    async fn restore_state(
+        &mut self,
+        state: &crate::types::SessionState,
+        config: &PlatformConfig,
+    ) -> Result<ConnectionInfo> {
+        if state.server_url.trim_end_matches('/') != self.server_url.trim_end_matches('/') {
+            return Err(Error::invalid_argument(format!(
+                "Saved session belongs to {}, not {}",
+                state.server_url, self.server_url
+            )));
+        }
+
+        self.apply_network_config(config)?;
+        self.client.login_with_token(&state.token).await?;
+        if state.team_id.is_some() {
+            self.client.set_team_id(state.team_id.clone()).await;
+        }
+        self.resume_point = state
+            .connection_id
+            .clone()
+            .filter(|id| !id.is_empty())
+            .map(|id| (id, state.last_sequence));
+
+        let current_user = self.client.get_current_user().await?;
+        let conn_info = self
+            .client
+            .connection_info(&self.server_url, &current_user.username)
+            .await;
+        self.connection_info = Some(conn_info.clone());
+        diag!(
+            Info,
+            "Restored the session on {} as @{}",
+            self.server_url,
+            current_user.username
+        );
+
+        Ok(conn_info)
+    }
+
     fn connection_info(&self) -> Option<&ConnectionInfo> {
         self.connection_info.as_ref()
     }
@@ -193,16 +528,10 @@ impl Platform for MattermostPlatform {
         // Get current user ID for DM channel context
         let current_user_id = self.client.get_user_id().await;
 
-        // Convert channels with proper DM handling
-        let mut channels = Vec::new();
-        for mm_channel in mm_channels {
-            let channel = self
-                .convert_channel_with_context(mm_channel, current_user_id.as_deref())
-                .await?;
-            channels.push(channel);
-        }
-
-        Ok(channels)
+        // Convert channels with proper DM and group handling
+        Ok(self
+            .convert_channels_with_context(mm_channels, current_user_id.as_deref())
+            .await)
     }
 
     async fn get_channel(&self, channel_id: &str) -> Result<Channel> {
@@ -226,8 +555,8 @@ impl Platform for MattermostPlatform {
             .map(|post| post.clone().into())
             .collect();
 
-        // Reverse to get most recent first
-        messages.reverse();
+        // Oldest first, independent of the server's ordering
+        sort_chronologically(&mut messages);
 
         Ok(messages)
     }
@@ -257,6 +586,17 @@ impl Platform for MattermostPlatform {
         Ok(mm_user.into())
     }
 
+    async fn get_user_avatar(&self, user_id: &str) -> Result<Vec<u8>> {
+        self.client.get_user_image(user_id).await
+    }
+
+    async fn set_my_avatar(&self, image_data: Vec<u8>) -> Result<()> {
+        let user_id = self.client.current_user_id().await?;
+        self.client.set_user_image(&user_id, image_data).await?;
+        self.client.invalidate_user_cache(&user_id).await;
+        Ok(())
+    }
+
     async fn create_direct_channel(&self, user_id: &str) -> Result<Channel> {
         let mm_channel = self.client.create_direct_channel(user_id).await?;
         let current_user_id = self.client.get_user_id().await;
@@ -300,6 +640,14 @@ impl Platform for MattermostPlatform {
         self.client.delete_channel(channel_id).await
     }
 
+    async fn restore_channel(&self, channel_id: &str) -> Result<Channel> {
+        let mm_channel = self.client.restore_channel(channel_id).await?;
+        self.client.invalidate_channel_cache(channel_id).await;
+        let current_user_id = self.client.get_user_id().await;
+        self.convert_channel_with_context(mm_channel, current_user_id.as_deref())
+            .await
+    }
+
     async fn get_teams(&self) -> Result<Vec<Team>> {
         let mm_teams = self.client.get_teams().await?;
         Ok(mm_teams.into_iter().map(|t| t.into()).collect())
@@ -310,6 +658,61 @@ impl Platform for MattermostPlatform {
         Ok(mm_team.into())
     }
 
+    async fn create_team(&self, team: &crate::types::NewTeam) -> Result<Team> {
+        let mut body = serde_json::json!({
+            "name": team.name,
+            "display_name": team.display_name,
+            "type": super::convert::team_type_to_code(team.team_type),
+            "allow_open_invite": team.allow_open_invite,
+        });
+        if let Some(description) = &team.description {
+            body["description"] = serde_json::json!(description);
+        }
+        if let Some(domains) = &team.allowed_domains {
+            body["allowed_domains"] = serde_json::json!(domains);
+        }
+
+        let mm_team = self.client.create_team(&body).await?;
+        Ok(mm_team.into())
+    }
+
+    async fn update_team(&self, team_id: &str, patch: &crate::types::TeamPatch) -> Result<Team> {
+        let mut body = serde_json::Map::new();
+        if let Some(display_name) = &patch.display_name {
+            body.insert("display_name".into(), serde_json::json!(display_name));
+        }
+        if let Some(description) = &patch.description {
+            body.insert("description".into(), serde_json::json!(description));
+        }
+        if let Some(domains) = &patch.allowed_domains {
+            body.insert("allowed_domains".into(), serde_json::json!(domains));
+        }
+        if let Some(allow) = patch.allow_open_invite {
+            body.insert("allow_open_invite".into(), serde_json::json!(allow));
+        }
+
+        // The patch endpoint ignores the team type; privacy has its own endpoint
+        let mut mm_team = None;
+        if !body.is_empty() {
+            let value = serde_json::Value::Object(body);
+            mm_team = Some(self.client.patch_team(team_id, &value).await?);
+        }
+        if let Some(team_type) = patch.team_type {
+            let privacy = super::convert::team_type_to_code(team_type);
+            mm_team = Some(self.client.update_team_privacy(team_id, privacy).await?);
+        }
+        self.client.invalidate_team_cache(team_id).await;
+
+        match mm_team {
+            Some(mm_team) => Ok(mm_team.into()),
+            None => self.get_team(team_id).await,
+        }
+    }
+
+    async fn set_team_icon(&self, team_id: &str, image_data: Vec<u8>) -> Result<()> {
+        self.client.set_team_image(team_id, image_data).await
+    }
+
     async fn set_status(
         &self,
         status: crate::types::user::UserStatus,
@@ -354,12 +757,32 @@ impl Platform for MattermostPlatform {
         // Use the stored server URL
         let server_url = &self.server_url;
 
-        let mut ws_manager = WebSocketManager::new(server_url, token);
+        let mut ws_manager = WebSocketManager::new(server_url, token)
+            .with_strict_schema(self.strict_events.load(Ordering::Relaxed))
+            .with_proxy(self.client.proxy().cloned())
+            .with_tls(self.client.tls().cloned())
+            .with_headers(self.client.headers().clone())
+            .with_timeouts(
+                self.client.timeouts().handshake,
+                self.client.timeouts().idle,
+            );
+        if let Some((connection_id, last_sequence)) = self.resume_point.take() {
+            ws_manager = ws_manager.with_resume(connection_id, last_sequence);
+        }
         ws_manager.connect().await?;
 
         let mut ws_lock = self.websocket.lock().await;
         *ws_lock = Some(ws_manager);
+        self.event_filter = None;
+
+        Ok(())
+    }
 
+    async fn subscribe_events_filtered(&mut self, filter: EventFilter) -> Result<()> {
+        self.subscribe_events().await?;
+        if !filter.is_empty() {
+            self.event_filter = Some(filter);
+        }
         Ok(())
     }
 
@@ -372,11 +795,42 @@ impl Platform for MattermostPlatform {
         Ok(())
     }
 
-    async fn poll_event(&mut self) -> Result<Option<PlatformEvent>> {
+    async fn set_strict_event_validation(&self, enabled: bool) -> Result<()> {
+        self.strict_events.store(enabled, Ordering::Relaxed);
+        Ok(())
+    }
+
+    async fn poll_event(&self) -> Result<Option<PlatformEvent>> {
+        // Rate limit notices come first so callers can back off promptly
+        if let Some(event) = self.client.take_rate_limit_event().await {
+            return Ok(Some(event));
+        }
+
+        self.recover_event_gap().await?;
+        loop {
+            let recovered = self.recovered_events.lock().await.pop_front();
+            let Some(event) = recovered else {
+                break;
+            };
+            self.remember_post(&event).await;
+            if self.event_in_scope(&event).await {
+                self.last_polled_seq.store(0, Ordering::Relaxed);
+                return Ok(Some(event));
+            }
+        }
+
         let ws_lock = self.websocket.lock().await;
         if let Some(ws) = ws_lock.as_ref() {
             // Poll from the WebSocket manager
-            if let Some(event) = ws.poll_event().await {
+            while let Some((seq, mut event)) = ws.poll_event().await {
+                // Skip posts already delivered by gap recovery
+                if !self.remember_post(&event).await {
+                    continue;
+                }
+
+                // Must run before the channel cache is invalidated below
+                self.track_channel_state(&mut event).await;
+
                 // Invalidate caches based on event type
                 match &event {
                     // User events - invalidate user cache
@@ -385,18 +839,33 @@ impl Platform for MattermostPlatform {
                     }
                     PlatformEvent::UserRoleUpdated { user_id } => {
                         self.client.invalidate_user_cache(user_id).await;
+                        self.client.invalidate_permission_cache().await;
+                    }
+
+                    // Role events - cached permissions may no longer be accurate
+                    PlatformEvent::RoleUpdated { .. }
+                    | PlatformEvent::MemberRoleUpdated { .. }
+                    | PlatformEvent::ChannelMemberUpdated { .. } => {
+                        self.client.invalidate_permission_cache().await;
                     }
 
                     // Channel events - invalidate channel cache
                     PlatformEvent::ChannelCreated(channel) => {
                         self.client.invalidate_channel_cache(&channel.id).await;
                     }
-                    PlatformEvent::ChannelUpdated(channel) => {
+                    PlatformEvent::ChannelUpdated { channel, .. } => {
                         self.client.invalidate_channel_cache(&channel.id).await;
                     }
                     PlatformEvent::ChannelDeleted { channel_id } => {
                         self.client.invalidate_channel_cache(channel_id).await;
                     }
+                    // Membership changes alter group channel participants
+                    PlatformEvent::UserJoinedChannel { channel_id, .. } => {
+                        self.client.invalidate_channel_cache(channel_id).await;
+                    }
+                    PlatformEvent::UserLeftChannel { channel_id, .. } => {
+                        self.client.invalidate_channel_cache(channel_id).await;
+                    }
 
                     // Team events - clear team cache (structural changes)
                     PlatformEvent::AddedToTeam { team_id, .. } => {
@@ -410,16 +879,40 @@ impl Platform for MattermostPlatform {
                     _ => {}
                 }
 
+                // Caches are kept current even for events outside the subscription
+                if !self.event_in_scope(&event).await {
+                    continue;
+                }
+
+                self.last_polled_seq.store(seq, Ordering::Relaxed);
                 return Ok(Some(event));
             }
         }
         Ok(None)
     }
 
+    fn last_polled_sequence(&self) -> Option<i64> {
+        let seq = self.last_polled_seq.load(Ordering::Relaxed);
+        (seq > 0).then_some(seq)
+    }
+
     // ========================================================================
     // Extended Platform Methods Implementation
     // ========================================================================
 
+    async fn send_message_with_options(
+        &self,
+        channel_id: &str,
+        text: &str,
+        options: &crate::types::SendMessageOptions,
+    ) -> Result<Message> {
+        let mm_post = self
+            .client
+            .send_message_with_options(channel_id, text, options)
+            .await?;
+        Ok(mm_post.into())
+    }
+
     async fn send_reply(&self, channel_id: &str, text: &str, root_id: &str) -> Result<Message> {
         let mm_post = self.client.send_reply(channel_id, text, root_id).await?;
         Ok(mm_post.into())
@@ -440,6 +933,20 @@ impl Platform for MattermostPlatform {
     }
 
     async fn search_messages(&self, query: &str, limit: usize) -> Result<Vec<Message>> {
+        let mut messages = self.search_messages_page(query, 0, limit as u32).await?;
+
+        // Limit to requested number
+        messages.truncate(limit);
+
+        Ok(messages)
+    }
+
+    async fn search_messages_page(
+        &self,
+        query: &str,
+        page: u32,
+        per_page: u32,
+    ) -> Result<Vec<Message>> {
         let team_id = self
             .client
             .get_team_id()
@@ -451,8 +958,8 @@ impl Platform for MattermostPlatform {
             is_or_search: false,
             include_deleted_channels: false,
             time_zone_offset: 0,
-            page: 0,
-            per_page: limit as u32,
+            page,
+            per_page,
         };
 
         let post_list = self
@@ -461,14 +968,51 @@ impl Platform for MattermostPlatform {
             .await?;
 
         // Convert posts to messages
-        let mut messages: Vec<Message> = post_list
+        let messages: Vec<Message> = post_list
             .order
             .iter()
             .filter_map(|post_id| post_list.posts.get(post_id))
             .map(|post| post.clone().into())
             .collect();
 
-        // Limit to requested number
+        Ok(messages)
+    }
+
+    async fn get_recent_mentions(&self, limit: usize) -> Result<Vec<Message>> {
+        let team_id = self
+            .client
+            .get_team_id()
+            .await
+            .ok_or_else(|| Error::new(ErrorCode::InvalidArgument, "Team ID not set"))?;
+
+        let user_id = self.client.current_user_id().await?;
+        let mm_user = self.client.get_user(&user_id).await?;
+        let terms = super::search::mention_search_terms(
+            &mm_user.username,
+            &mm_user.first_name,
+            &mm_user.notify_props,
+        );
+
+        let options = crate::platforms::mattermost::PostSearchOptions {
+            is_or_search: true,
+            include_deleted_channels: false,
+            time_zone_offset: 0,
+            page: 0,
+            per_page: limit as u32,
+        };
+
+        let post_list = self
+            .client
+            .search_posts_advanced(&team_id, &terms, options)
+            .await?;
+
+        // The search returns posts newest first
+        let mut messages: Vec<Message> = post_list
+            .order
+            .iter()
+            .filter_map(|post_id| post_list.posts.get(post_id))
+            .map(|post| post.clone().into())
+            .collect();
         messages.truncate(limit);
 
         Ok(messages)
@@ -493,8 +1037,8 @@ impl Platform for MattermostPlatform {
             .map(|post| post.clone().into())
             .collect();
 
-        // Reverse to get most recent first
-        messages.reverse();
+        // Oldest first, independent of the server's ordering
+        sort_chronologically(&mut messages);
 
         Ok(messages)
     }
@@ -518,8 +1062,24 @@ impl Platform for MattermostPlatform {
             .map(|post| post.clone().into())
             .collect();
 
-        // Reverse to get most recent first
-        messages.reverse();
+        // Oldest first, independent of the server's ordering
+        sort_chronologically(&mut messages);
+
+        Ok(messages)
+    }
+
+    async fn get_messages_since(&self, channel_id: &str, since: u64) -> Result<Vec<Message>> {
+        let post_list = self.client.get_posts_since(channel_id, since).await?;
+
+        let mut messages: Vec<Message> = post_list
+            .order
+            .iter()
+            .filter_map(|post_id| post_list.posts.get(post_id))
+            .map(|post| post.clone().into())
+            .collect();
+
+        // Oldest first, independent of the server's ordering
+        sort_chronologically(&mut messages);
 
         Ok(messages)
     }
@@ -547,11 +1107,77 @@ impl Platform for MattermostPlatform {
         Ok(messages)
     }
 
+    async fn get_permalink(&self, message_id: &str) -> Result<String> {
+        self.client.get_permalink(message_id).await
+    }
+
+    async fn resolve_permalink(&self, permalink: &str) -> Result<(Message, Channel)> {
+        let post_id = super::posts::parse_permalink(permalink).ok_or_else(|| {
+            Error::invalid_argument(format!("Not a Mattermost permalink: {permalink}"))
+        })?;
+
+        let mm_post = self.client.get_post(&post_id).await?;
+        let channel = self.get_channel(&mm_post.channel_id).await?;
+        Ok((mm_post.into(), channel))
+    }
+
+    async fn get_link_metadata(&self, url: &str) -> Result<crate::types::LinkMetadata> {
+        let parsed = url::Url::parse(url)
+            .map_err(|e| Error::invalid_argument(format!("Invalid URL {url}: {e}")))?;
+        if parsed.scheme() != "http" && parsed.scheme() != "https" {
+            return Err(Error::invalid_argument(format!(
+                "Only http and https links have previews: {url}"
+            )));
+        }
+
+        let mm_og = self.client.get_opengraph(url).await?;
+        let mut link: crate::types::LinkMetadata = mm_og.into();
+        if link.url.is_empty() {
+            link.url = url.to_string();
+        }
+        Ok(link)
+    }
+
     async fn get_emojis(&self, page: u32, per_page: u32) -> Result<Vec<crate::types::Emoji>> {
         let mm_emojis = self.client.get_emojis(page, per_page, "name").await?;
         Ok(mm_emojis.into_iter().map(|e| e.into()).collect())
     }
 
+    async fn create_emoji(
+        &self,
+        name: &str,
+        image_path: &std::path::Path,
+    ) -> Result<crate::types::Emoji> {
+        let mm_emoji = self.client.create_emoji(name, image_path).await?;
+        Ok(mm_emoji.into())
+    }
+
+    async fn delete_emoji(&self, emoji_id: &str) -> Result<()> {
+        self.client.delete_emoji(emoji_id).await?;
+        Ok(())
+    }
+
+    async fn get_emoji_by_name(&self, name: &str) -> Result<crate::types::Emoji> {
+        let mm_emoji = self
+            .client
+            .get_emoji_by_name(name.trim_matches(':'))
+            .await?;
+        Ok(mm_emoji.into())
+    }
+
+    async fn autocomplete_emoji(&self, prefix: &str) -> Result<Vec<crate::types::Emoji>> {
+        let prefix = prefix.trim_start_matches(':');
+        if prefix.is_empty() {
+            return Ok(Vec::new());
+        }
+        let mm_emojis = self.client.autocomplete_emoji(prefix).await?;
+        Ok(mm_emojis.into_iter().map(|e| e.into()).collect())
+    }
+
+    async fn get_emoji_image(&self, emoji_id: &str) -> Result<Vec<u8>> {
+        self.client.get_emoji_image(emoji_id).await
+    }
+
     async fn get_channel_by_name(&self, team_id: &str, channel_name: &str) -> Result<Channel> {
         let mm_channel = self
             .client
@@ -562,13 +1188,125 @@ impl Platform for MattermostPlatform {
             .await
     }
 
-    async fn create_group_channel(&self, user_ids: Vec<String>) -> Result<Channel> {
-        let mm_channel = self.client.create_group_channel(user_ids).await?;
-        let current_user_id = self.client.get_user_id().await;
+    async fn get_public_channels(
+        &self,
+        team_id: &str,
+        page: u32,
+        per_page: u32,
+    ) -> Result<Vec<Channel>> {
+        let mm_channels = self
+            .client
+            .get_public_channels(team_id, page, per_page)
+            .await?;
+        let current_user_id = self.client.get_user_id().await;
+        Ok(self
+            .convert_channels_with_context(mm_channels, current_user_id.as_deref())
+            .await)
+    }
+
+    async fn get_archived_channels(
+        &self,
+        team_id: &str,
+        page: u32,
+        per_page: u32,
+    ) -> Result<Vec<Channel>> {
+        let mm_channels = self
+            .client
+            .get_archived_channels(team_id, page, per_page)
+            .await?;
+        let current_user_id = self.client.get_user_id().await;
+        Ok(self
+            .convert_channels_with_context(mm_channels, current_user_id.as_deref())
+            .await)
+    }
+
+    async fn create_group_channel(&self, user_ids: Vec<String>) -> Result<Channel> {
+        let mm_channel = self.client.create_group_channel(user_ids).await?;
+        let current_user_id = self.client.get_user_id().await;
         self.convert_channel_with_context(mm_channel, current_user_id.as_deref())
             .await
     }
 
+    async fn get_effective_permissions(
+        &self,
+        user_id: &str,
+        channel_id: &str,
+    ) -> Result<Vec<String>> {
+        self.client
+            .get_effective_permissions(user_id, channel_id)
+            .await
+    }
+
+    async fn find_direct_channel(&self, user_id: &str) -> Result<Option<Channel>> {
+        let current_user_id = self.client.get_user_id().await;
+        match self.client.find_direct_channel(user_id).await? {
+            Some(mm_channel) => self
+                .convert_channel_with_context(mm_channel, current_user_id.as_deref())
+                .await
+                .map(Some),
+            None => Ok(None),
+        }
+    }
+
+    async fn find_group_channel(&self, user_ids: Vec<String>) -> Result<Option<Channel>> {
+        let current_user_id = self.client.get_user_id().await;
+        match self.client.find_group_channel(&user_ids).await? {
+            Some(mm_channel) => self
+                .convert_channel_with_context(mm_channel, current_user_id.as_deref())
+                .await
+                .map(Some),
+            None => Ok(None),
+        }
+    }
+
+    async fn update_group_participants(
+        &self,
+        channel_id: &str,
+        add_user_ids: Vec<String>,
+        remove_user_ids: Vec<String>,
+    ) -> Result<Channel> {
+        let current_user_id = self.client.get_user_id().await.ok_or_else(|| {
+            Error::new(
+                ErrorCode::InvalidState,
+                "User ID not set - ensure you're authenticated",
+            )
+        })?;
+        let mm_channel = self.client.get_channel(channel_id).await?;
+        if !mm_channel.channel_type.is_group() {
+            return Err(Error::new(
+                ErrorCode::InvalidArgument,
+                "Not a group message channel",
+            ));
+        }
+
+        // Mattermost fixes a group message's participants, so the new set
+        // gets a conversation of its own
+        let mut others: Vec<String> = self
+            .client
+            .get_channel_members(channel_id)
+            .await?
+            .into_iter()
+            .map(|m| m.user_id)
+            .filter(|id| !remove_user_ids.contains(id))
+            .chain(add_user_ids)
+            .filter(|id| *id != current_user_id)
+            .collect();
+        others.sort();
+        others.dedup();
+
+        match others.as_slice() {
+            [] => Err(Error::new(
+                ErrorCode::InvalidArgument,
+                "A conversation needs at least one other participant",
+            )),
+            [other] => self.create_direct_channel(other).await,
+            _ => {
+                others.push(current_user_id);
+                self.create_group_channel(others).await
+            }
+        }
+    }
+
     async fn add_channel_member(&self, channel_id: &str, user_id: &str) -> Result<()> {
         self.client.add_channel_member(channel_id, user_id).await?;
         Ok(())
@@ -578,6 +1316,54 @@ impl Platform for MattermostPlatform {
         self.client.remove_channel_member(channel_id, user_id).await
     }
 
+    async fn join_channel(&self, channel_id: &str) -> Result<Channel> {
+        self.client.join_channel(channel_id).await?;
+        // Membership changes the channel's unread and member counts
+        self.client.invalidate_channel_cache(channel_id).await;
+        self.get_channel(channel_id).await
+    }
+
+    async fn join_channel_by_name(&self, team_id: &str, channel_name: &str) -> Result<Channel> {
+        let mm_channel = self
+            .client
+            .get_channel_by_name(team_id, channel_name)
+            .await?;
+        self.join_channel(&mm_channel.id).await
+    }
+
+    async fn leave_channel(&self, channel_id: &str) -> Result<()> {
+        self.client.leave_channel(channel_id).await?;
+        self.client.invalidate_channel_cache(channel_id).await;
+        Ok(())
+    }
+
+    async fn update_channel_member_roles(
+        &self,
+        channel_id: &str,
+        user_id: &str,
+        roles: &[String],
+    ) -> Result<()> {
+        self.client
+            .update_channel_member_roles(channel_id, user_id, roles)
+            .await?;
+        self.client.invalidate_permission_cache().await;
+        Ok(())
+    }
+
+    async fn set_channel_member_scheme_roles(
+        &self,
+        channel_id: &str,
+        user_id: &str,
+        scheme_admin: bool,
+        scheme_user: bool,
+    ) -> Result<()> {
+        self.client
+            .update_channel_member_scheme_roles(channel_id, user_id, scheme_admin, scheme_user)
+            .await?;
+        self.client.invalidate_permission_cache().await;
+        Ok(())
+    }
+
     async fn get_user_by_username(&self, username: &str) -> Result<User> {
         let mm_user = self.client.get_user_by_username(username).await?;
         Ok(mm_user.into())
@@ -639,6 +1425,14 @@ impl Platform for MattermostPlatform {
         Ok(status_map)
     }
 
+    async fn get_statuses_by_ids(
+        &self,
+        user_ids: Vec<String>,
+    ) -> Result<Vec<crate::types::UserStatusInfo>> {
+        let mm_statuses = self.client.get_users_status_by_ids(&user_ids).await?;
+        Ok(mm_statuses.into_iter().map(Into::into).collect())
+    }
+
     async fn request_all_statuses(&self) -> Result<i64> {
         let ws_lock = self.websocket.lock().await;
         if let Some(ws) = ws_lock.as_ref() {
@@ -663,11 +1457,60 @@ impl Platform for MattermostPlatform {
         }
     }
 
+    async fn connection_health(&self) -> Result<crate::types::ConnectionHealth> {
+        let ws_lock = self.websocket.lock().await;
+        if let Some(ws) = ws_lock.as_ref() {
+            Ok(ws.health().await)
+        } else {
+            Err(Error::new(
+                ErrorCode::InvalidState,
+                "WebSocket not connected. Call subscribe_events first.",
+            ))
+        }
+    }
+
+    async fn ping(&self, timeout_ms: u64) -> Result<u64> {
+        // Wait for the pong without holding the lock, so events keep flowing
+        let pong = {
+            let ws_lock = self.websocket.lock().await;
+            let Some(ws) = ws_lock.as_ref() else {
+                return Err(Error::new(
+                    ErrorCode::InvalidState,
+                    "WebSocket not connected. Call subscribe_events first.",
+                ));
+            };
+            ws.ping().await?
+        };
+
+        let timeout_ms = if timeout_ms == 0 { 10_000 } else { timeout_ms };
+        match tokio::time::timeout(std::time::Duration::from_millis(timeout_ms), pong).await {
+            Ok(Ok(rtt_ms)) => Ok(rtt_ms),
+            Ok(Err(_)) => Err(Error::new(
+                ErrorCode::NetworkError,
+                "Connection dropped before the pong arrived",
+            )),
+            Err(_) => Err(Error::new(
+                ErrorCode::Timeout,
+                format!("No pong within {timeout_ms}ms"),
+            )),
+        }
+    }
+
     async fn get_team_by_name(&self, team_name: &str) -> Result<Team> {
         let mm_team = self.client.get_team_by_name(team_name).await?;
         Ok(mm_team.into())
     }
 
+    async fn get_team_stats(&self, team_id: &str) -> Result<crate::types::TeamStats> {
+        let mm_stats = self.client.get_team_stats(team_id).await?;
+        Ok(mm_stats.into())
+    }
+
+    async fn search_teams(&self, term: &str) -> Result<Vec<Team>> {
+        let mm_teams = self.client.search_teams(term).await?;
+        Ok(mm_teams.into_iter().map(|t| t.into()).collect())
+    }
+
     async fn set_team_id(&self, team_id: Option<String>) -> Result<()> {
         self.client.set_team_id(team_id).await;
         Ok(())
@@ -686,6 +1529,17 @@ impl Platform for MattermostPlatform {
         self.client.download_file(file_id).await
     }
 
+    async fn download_file_range(
+        &self,
+        file_id: &str,
+        offset: u64,
+        length: u64,
+    ) -> Result<Vec<u8>> {
+        self.client
+            .download_file_range(file_id, offset, length)
+            .await
+    }
+
     async fn get_file_metadata(&self, file_id: &str) -> Result<Attachment> {
         let file_info = self.client.get_file_info(file_id).await?;
         // Convert FileInfo to Attachment using context
@@ -700,6 +1554,64 @@ impl Platform for MattermostPlatform {
         self.client.get_file_thumbnail(file_id).await
     }
 
+    async fn get_file_link(&self, file_id: &str) -> Result<String> {
+        // The server refuses disabled links too, but only with a generic 501
+        if !self.client.public_links_enabled().await? {
+            return Err(Error::new(
+                ErrorCode::UnsupportedByServer,
+                "Public file links are disabled on this server",
+            ));
+        }
+        self.client.get_file_link(file_id).await
+    }
+
+    async fn public_links_enabled(&self) -> Result<bool> {
+        self.client.public_links_enabled().await
+    }
+
+    async fn enable_public_links(&self) -> Result<()> {
+        self.client.set_public_links_enabled(true).await
+    }
+
+    async fn revoke_public_links(&self) -> Result<()> {
+        self.client.revoke_public_links().await
+    }
+
+    async fn revoke_file_link(&self, file_id: &str) -> Result<()> {
+        let _ = file_id;
+        // Links are signed with one server-wide salt, so they can only be
+        // invalidated all at once
+        Err(Error::unsupported(
+            "Mattermost cannot revoke a single file link; revoke_public_links invalidates every link",
+        ))
+    }
+
+    // ========================================================================
+    // System Administration
+    // ========================================================================
+
+    async fn get_server_config(&self) -> Result<serde_json::Value> {
+        self.client.get_config().await
+    }
+
+    async fn patch_server_config(&self, patch: serde_json::Value) -> Result<serde_json::Value> {
+        if !patch.is_object() {
+            return Err(Error::invalid_argument(
+                "Configuration patch must be a JSON object",
+            ));
+        }
+        self.client.patch_config(&patch).await
+    }
+
+    async fn get_system_stats(&self) -> Result<crate::types::SystemStats> {
+        let rows = self.client.get_analytics().await?;
+        Ok(super::system::system_stats_from_analytics(rows))
+    }
+
+    async fn get_server_logs(&self, page: u32, per_page: u32) -> Result<Vec<String>> {
+        self.client.get_logs(page, per_page).await
+    }
+
     // ========================================================================
     // Thread Operations
     // ========================================================================
@@ -714,6 +1626,7 @@ impl Platform for MattermostPlatform {
                 messages.push(post.clone().into());
             }
         }
+        sort_chronologically(&mut messages);
 
         Ok(messages)
     }
@@ -776,6 +1689,50 @@ impl Platform for MattermostPlatform {
             .await
     }
 
+    async fn get_user_threads(
+        &self,
+        user_id: &str,
+        team_id: &str,
+        since: u64,
+        deleted: bool,
+        unread: bool,
+        per_page: usize,
+        page: usize,
+    ) -> Result<crate::types::ThreadList> {
+        let since = (since > 0).then_some(since as i64);
+        let threads = self
+            .client
+            .get_user_threads(
+                user_id,
+                team_id,
+                since,
+                deleted,
+                unread,
+                false,
+                page as u32,
+                per_page as u32,
+            )
+            .await?;
+        Ok(threads.into())
+    }
+
+    async fn get_user_thread(
+        &self,
+        user_id: &str,
+        team_id: &str,
+        thread_id: &str,
+    ) -> Result<crate::types::Thread> {
+        let thread = self
+            .client
+            .get_user_thread(user_id, team_id, thread_id)
+            .await?;
+        Ok(thread.into())
+    }
+
+    async fn mark_all_threads_as_read(&self, user_id: &str, team_id: &str) -> Result<()> {
+        self.client.mark_all_threads_as_read(user_id, team_id).await
+    }
+
     async fn search_users(&self, query: &str, limit: usize) -> Result<Vec<User>> {
         let team_id = self
             .client
@@ -825,17 +1782,11 @@ impl Platform for MattermostPlatform {
         // Limit results
         let limited: Vec<_> = mm_channels.into_iter().take(limit).collect();
 
-        // Convert channels with proper DM handling
+        // Convert channels with proper DM and group handling
         let current_user_id = self.client.get_user_id().await;
-        let mut channels = Vec::new();
-        for mm_channel in limited {
-            let channel = self
-                .convert_channel_with_context(mm_channel, current_user_id.as_deref())
-                .await?;
-            channels.push(channel);
-        }
-
-        Ok(channels)
+        Ok(self
+            .convert_channels_with_context(limited, current_user_id.as_deref())
+            .await)
     }
 
     async fn autocomplete_channels(&self, query: &str, limit: usize) -> Result<Vec<Channel>> {
@@ -850,17 +1801,11 @@ impl Platform for MattermostPlatform {
         // Limit results
         let limited: Vec<_> = mm_channels.into_iter().take(limit).collect();
 
-        // Convert channels with proper DM handling
+        // Convert channels with proper DM and group handling
         let current_user_id = self.client.get_user_id().await;
-        let mut channels = Vec::new();
-        for mm_channel in limited {
-            let channel = self
-                .convert_channel_with_context(mm_channel, current_user_id.as_deref())
-                .await?;
-            channels.push(channel);
-        }
-
-        Ok(channels)
+        Ok(self
+            .convert_channels_with_context(limited, current_user_id.as_deref())
+            .await)
     }
 
     // ========================================================================
@@ -933,6 +1878,53 @@ impl Platform for MattermostPlatform {
             .await
     }
 
+    async fn get_user_notify_props(&self) -> Result<crate::types::UserNotifyProps> {
+        let user_id = self.client.current_user_id().await?;
+        let mm_user = self.client.get_user(&user_id).await?;
+        let interval = self.email_batching_interval(&user_id).await;
+
+        Ok(super::convert::user_notify_props_from_map(
+            &mm_user.notify_props,
+            interval.as_deref(),
+        ))
+    }
+
+    async fn update_user_notify_props(
+        &self,
+        props: &crate::types::UserNotifyProps,
+    ) -> Result<crate::types::UserNotifyProps> {
+        let user_id = self.client.current_user_id().await?;
+        let mut mm_user = self.client.get_user(&user_id).await?;
+
+        let mut notify_props = mm_user.notify_props.clone();
+        super::convert::apply_user_notify_props(&mut notify_props, props);
+        if notify_props != mm_user.notify_props {
+            mm_user = self
+                .client
+                .patch_user_notify_props(&user_id, &notify_props)
+                .await?;
+            self.client.invalidate_user_cache(&user_id).await;
+        }
+
+        if let Some(interval) = props.email_batching_interval {
+            let preference = super::types::UserPreference::new(
+                user_id.clone(),
+                "notifications".to_string(),
+                "email_interval".to_string(),
+                interval.to_string(),
+            );
+            self.client
+                .set_user_preferences(&user_id, &[preference])
+                .await?;
+        }
+
+        let interval = self.email_batching_interval(&user_id).await;
+        Ok(super::convert::user_notify_props_from_map(
+            &mm_user.notify_props,
+            interval.as_deref(),
+        ))
+    }
+
     async fn view_channel(&self, channel_id: &str) -> Result<()> {
         self.client.view_channel(channel_id, None).await?;
         Ok(())
@@ -946,6 +1938,7 @@ impl Platform for MattermostPlatform {
             team_id: Some(mm_unread.team_id),
             msg_count: mm_unread.msg_count,
             mention_count: mm_unread.mention_count,
+            urgent_mention_count: mm_unread.urgent_mention_count,
             last_viewed_at: mm_unread.last_viewed_at,
         })
     }
@@ -960,10 +1953,404 @@ impl Platform for MattermostPlatform {
                 team_id: Some(mm_unread.team_id),
                 msg_count: mm_unread.msg_count,
                 mention_count: mm_unread.mention_count,
+                urgent_mention_count: mm_unread.urgent_mention_count,
                 last_viewed_at: mm_unread.last_viewed_at,
             })
             .collect())
     }
+
+    async fn get_all_unreads(&self) -> Result<Vec<crate::types::TeamUnread>> {
+        let mm_unreads = self.client.get_all_unreads().await?;
+
+        Ok(mm_unreads
+            .into_iter()
+            .map(|mm_unread| crate::types::TeamUnread {
+                team_id: mm_unread.team_id,
+                msg_count: mm_unread.msg_count,
+                mention_count: mm_unread.mention_count,
+            })
+            .collect())
+    }
+
+    async fn get_total_unreads(&self) -> Result<crate::types::UnreadTotals> {
+        let teams = self.get_all_unreads().await?;
+
+        // Team unreads leave out direct and group channels and don't split out
+        // urgent mentions, so mentions are summed from the channel members.
+        // Direct and group channels are listed under every team; count them once.
+        let mut seen = std::collections::HashSet::new();
+        let mut mention_count = 0;
+        let mut urgent_mention_count = 0;
+        for team in &teams {
+            for member in self.client.get_team_unreads(&team.team_id).await? {
+                if seen.insert(member.channel_id) {
+                    mention_count += member.mention_count;
+                    urgent_mention_count += member.urgent_mention_count;
+                }
+            }
+        }
+
+        Ok(crate::types::UnreadTotals {
+            msg_count: teams.iter().map(|t| t.msg_count).sum(),
+            mention_count,
+            urgent_mention_count,
+            teams,
+        })
+    }
+
+    // ========================================================================
+    // Sidebar Categories Implementation
+    // ========================================================================
+
+    async fn get_sidebar_categories(&self, team_id: &str) -> Result<Vec<SidebarCategory>> {
+        let ordered = self.client.get_sidebar_categories(team_id).await?;
+        let mut by_id: std::collections::HashMap<String, SidebarCategory> = ordered
+            .categories
+            .into_iter()
+            .map(|c| (c.id.clone(), c.into()))
+            .collect();
+
+        Ok(ordered
+            .order
+            .iter()
+            .filter_map(|id| by_id.remove(id))
+            .collect())
+    }
+
+    async fn create_sidebar_category(
+        &self,
+        team_id: &str,
+        display_name: &str,
+        channel_ids: Vec<String>,
+    ) -> Result<SidebarCategory> {
+        let mm_category = self
+            .client
+            .create_sidebar_category(team_id, display_name, channel_ids)
+            .await?;
+        Ok(mm_category.into())
+    }
+
+    async fn update_sidebar_category(&self, category: SidebarCategory) -> Result<SidebarCategory> {
+        // Start from the server's copy so fields we don't model (sorting, sort order) survive
+        let mut mm_category = self
+            .client
+            .get_sidebar_categories(&category.team_id)
+            .await?
+            .categories
+            .into_iter()
+            .find(|c| c.id == category.id)
+            .ok_or_else(|| {
+                Error::new(
+                    ErrorCode::NotFound,
+                    format!("Sidebar category not found: {}", category.id),
+                )
+            })?;
+
+        mm_category.display_name = category.display_name;
+        mm_category.channel_ids = category.channel_ids;
+        mm_category.muted = category.muted;
+        mm_category.collapsed = category.collapsed;
+
+        let updated = self
+            .client
+            .update_sidebar_categories(&category.team_id, &[mm_category])
+            .await?;
+        updated
+            .into_iter()
+            .next()
+            .map(|c| c.into())
+            .ok_or_else(|| Error::new(ErrorCode::Unknown, "Server returned no category"))
+    }
+
+    async fn delete_sidebar_category(&self, team_id: &str, category_id: &str) -> Result<()> {
+        self.client
+            .delete_sidebar_category(team_id, category_id)
+            .await
+    }
+
+    async fn reorder_sidebar_categories(
+        &self,
+        team_id: &str,
+        category_ids: Vec<String>,
+    ) -> Result<()> {
+        self.client
+            .update_sidebar_category_order(team_id, &category_ids)
+            .await?;
+        Ok(())
+    }
+
+    async fn move_channel_to_category(
+        &self,
+        team_id: &str,
+        channel_id: &str,
+        category_id: &str,
+    ) -> Result<()> {
+        self.client
+            .move_channel_to_category(team_id, channel_id, category_id)
+            .await
+    }
+
+    async fn set_channel_favorite(
+        &self,
+        team_id: &str,
+        channel_id: &str,
+        favorite: bool,
+    ) -> Result<()> {
+        let category_type = if favorite {
+            "favorites"
+        } else {
+            let channel = self.client.get_channel_cached(channel_id).await?;
+            if channel.channel_type.is_direct() || channel.channel_type.is_group() {
+                "direct_messages"
+            } else {
+                "channels"
+            }
+        };
+
+        let category = self
+            .client
+            .get_sidebar_category_by_type(team_id, category_type)
+            .await?;
+        self.client
+            .move_channel_to_category(team_id, channel_id, &category.id)
+            .await
+    }
+
+    // ========================================================================
+    // Channel Bookmarks Implementation
+    // ========================================================================
+
+    async fn get_channel_bookmarks(&self, channel_id: &str) -> Result<Vec<ChannelBookmark>> {
+        let mut bookmarks = self.client.get_channel_bookmarks(channel_id, None).await?;
+        bookmarks.sort_by_key(|b| b.sort_order);
+
+        let ctx = ConversionContext::new(self.server_url.clone());
+        Ok(bookmarks
+            .iter()
+            .map(|b| b.to_bookmark_with_context(&ctx))
+            .collect())
+    }
+
+    async fn create_channel_bookmark(
+        &self,
+        channel_id: &str,
+        bookmark: NewChannelBookmark,
+    ) -> Result<ChannelBookmark> {
+        let created = self
+            .client
+            .create_channel_bookmark(channel_id, &bookmark)
+            .await?;
+
+        let ctx = ConversionContext::new(self.server_url.clone());
+        Ok(created.to_bookmark_with_context(&ctx))
+    }
+
+    async fn delete_channel_bookmark(&self, channel_id: &str, bookmark_id: &str) -> Result<()> {
+        self.client
+            .delete_channel_bookmark(channel_id, bookmark_id)
+            .await?;
+        Ok(())
+    }
+
+    async fn list_sessions(&self, user_id: &str) -> Result<Vec<crate::types::Session>> {
+        let mm_sessions = self.client.get_sessions(user_id).await?;
+        Ok(mm_sessions.into_iter().map(|s| s.into()).collect())
+    }
+
+    async fn revoke_session(&self, session_id: &str) -> Result<()> {
+        let user_id = self.client.current_user_id().await?;
+        self.client.revoke_session(&user_id, session_id).await
+    }
+
+    async fn revoke_all_sessions(&self) -> Result<()> {
+        let user_id = self.client.current_user_id().await?;
+        self.client.revoke_all_sessions(&user_id).await
+    }
+
+    async fn execute_command(
+        &self,
+        channel_id: &str,
+        command: &str,
+    ) -> Result<crate::types::CommandResponse> {
+        let mm_response = self.client.execute_command(channel_id, command).await?;
+        Ok(mm_response.into())
+    }
+
+    async fn autocomplete_commands(
+        &self,
+        channel_id: &str,
+        prefix: &str,
+    ) -> Result<Vec<crate::types::SlashCommand>> {
+        // Commands are registered per team; DM and group channels have none,
+        // so fall back to the connected team
+        let channel = self.client.get_channel(channel_id).await?;
+        let team_id = if channel.team_id.is_empty() {
+            self.client
+                .get_team_id()
+                .await
+                .ok_or_else(|| Error::new(ErrorCode::InvalidArgument, "Team ID not set"))?
+        } else {
+            channel.team_id
+        };
+
+        let mm_commands = self.client.list_autocomplete_commands(&team_id).await?;
+        Ok(super::commands::filter_commands(mm_commands, prefix)
+            .into_iter()
+            .map(Into::into)
+            .collect())
+    }
+
+    async fn create_incoming_webhook(
+        &self,
+        hook: &crate::types::NewIncomingWebhook,
+    ) -> Result<crate::types::IncomingWebhook> {
+        let mm_hook = self.client.create_incoming_webhook(hook).await?;
+        let mut hook: crate::types::IncomingWebhook = mm_hook.into();
+        hook.url = self.client.incoming_webhook_url(&hook.id);
+        Ok(hook)
+    }
+
+    async fn list_incoming_webhooks(
+        &self,
+        team_id: Option<&str>,
+        page: u32,
+        per_page: u32,
+    ) -> Result<Vec<crate::types::IncomingWebhook>> {
+        let mm_hooks = self
+            .client
+            .get_incoming_webhooks(team_id, page, per_page)
+            .await?;
+        Ok(mm_hooks
+            .into_iter()
+            .map(|mm_hook| {
+                let mut hook: crate::types::IncomingWebhook = mm_hook.into();
+                hook.url = self.client.incoming_webhook_url(&hook.id);
+                hook
+            })
+            .collect())
+    }
+
+    async fn delete_incoming_webhook(&self, hook_id: &str) -> Result<()> {
+        self.client.delete_incoming_webhook(hook_id).await
+    }
+
+    async fn create_bot(&self, bot: &crate::types::NewBot) -> Result<crate::types::Bot> {
+        let mm_bot = self.client.create_bot(bot).await?;
+        Ok(mm_bot.into())
+    }
+
+    async fn patch_bot(
+        &self,
+        bot_user_id: &str,
+        patch: &crate::types::BotPatch,
+    ) -> Result<crate::types::Bot> {
+        let mm_bot = self.client.patch_bot(bot_user_id, patch).await?;
+        // The bot's user entry carries the username and display name
+        self.client.invalidate_user_cache(bot_user_id).await;
+        Ok(mm_bot.into())
+    }
+
+    async fn disable_bot(&self, bot_user_id: &str) -> Result<crate::types::Bot> {
+        let mm_bot = self.client.disable_bot(bot_user_id).await?;
+        self.client.invalidate_user_cache(bot_user_id).await;
+        Ok(mm_bot.into())
+    }
+
+    async fn assign_bot(&self, bot_user_id: &str, user_id: &str) -> Result<crate::types::Bot> {
+        let mm_bot = self.client.assign_bot(bot_user_id, user_id).await?;
+        Ok(mm_bot.into())
+    }
+
+    async fn send_message_with_attachments(
+        &self,
+        channel_id: &str,
+        text: &str,
+        attachments: &[crate::types::MessageAttachment],
+    ) -> Result<Message> {
+        let mm_post = self
+            .client
+            .send_message_with_attachments(channel_id, text, attachments)
+            .await?;
+        Ok(mm_post.into())
+    }
+
+    async fn open_interactive_dialog(
+        &self,
+        trigger_id: &str,
+        url: &str,
+        dialog: &crate::types::InteractiveDialog,
+    ) -> Result<()> {
+        self.client
+            .open_interactive_dialog(trigger_id, url, dialog)
+            .await
+    }
+
+    async fn send_message_with_priority(
+        &self,
+        channel_id: &str,
+        text: &str,
+        priority: &crate::types::MessagePriority,
+    ) -> Result<Message> {
+        let mm_post = self
+            .client
+            .send_message_with_priority(channel_id, text, priority)
+            .await?;
+        Ok(mm_post.into())
+    }
+
+    async fn ack_message(&self, message_id: &str) -> Result<crate::types::MessageAcknowledgement> {
+        let ack = self.client.ack_post(message_id).await?;
+        Ok(ack.into())
+    }
+
+    async fn get_message_acknowledgements(
+        &self,
+        message_id: &str,
+    ) -> Result<Vec<crate::types::MessageAcknowledgement>> {
+        let acks = self.client.get_post_acknowledgements(message_id).await?;
+        Ok(acks.into_iter().map(|ack| ack.into()).collect())
+    }
+
+    async fn create_user_group(
+        &self,
+        group: crate::types::NewUserGroup,
+    ) -> Result<crate::types::UserGroup> {
+        Ok(self.client.create_group(&group).await?.into())
+    }
+
+    async fn add_user_group_members(&self, group_id: &str, user_ids: &[String]) -> Result<()> {
+        self.client.add_group_members(group_id, user_ids).await
+    }
+
+    async fn remove_user_group_members(&self, group_id: &str, user_ids: &[String]) -> Result<()> {
+        self.client.remove_group_members(group_id, user_ids).await
+    }
+
+    async fn get_team_user_groups(
+        &self,
+        team_id: &str,
+        page: u32,
+        per_page: u32,
+    ) -> Result<Vec<crate::types::UserGroup>> {
+        let groups = self
+            .client
+            .get_mentionable_groups(team_id, None, page, per_page)
+            .await?;
+        Ok(groups.into_iter().map(Into::into).collect())
+    }
+
+    async fn autocomplete_user_groups(
+        &self,
+        team_id: &str,
+        prefix: &str,
+    ) -> Result<Vec<crate::types::UserGroup>> {
+        let prefix = prefix.trim_start_matches('@');
+        let groups = self
+            .client
+            .get_mentionable_groups(team_id, Some(prefix), 0, 25)
+            .await?;
+        Ok(groups.into_iter().map(Into::into).collect())
+    }
 }
 
 #[cfg(test)]
@@ -1,7 +1,59 @@
-use crate::error::Result;
+use std::collections::HashMap;
+
+use crate::error::{Error, ErrorCode, Result};
+use crate::types::SendMessageOptions;
 
 use super::client::MattermostClient;
-use super::types::{CreatePostRequest, MattermostPost, PostList};
+use super::types::{CreatePostRequest, MattermostOpenGraph, MattermostPost, PostList};
+
+/// Build the post props that override the sender's name and icon
+///
+/// The web app only shows overrides on posts marked `from_webhook`, and only
+/// when the server's EnablePostUsernameOverride and EnablePostIconOverride
+/// settings allow them.
+pub fn identity_override_props(options: &SendMessageOptions) -> HashMap<String, serde_json::Value> {
+    let mut props = HashMap::new();
+    if !options.overrides_identity() {
+        return props;
+    }
+
+    props.insert("from_webhook".to_string(), "true".into());
+    if let Some(username) = &options.username {
+        props.insert("override_username".to_string(), username.as_str().into());
+    }
+    if let Some(icon_url) = &options.icon_url {
+        props.insert("override_icon_url".to_string(), icon_url.as_str().into());
+    }
+    if let Some(icon_emoji) = &options.icon_emoji {
+        props.insert(
+            "override_icon_emoji".to_string(),
+            icon_emoji.trim_matches(':').into(),
+        );
+    }
+    props
+}
+
+/// Extract the post ID from a Mattermost permalink
+///
+/// Permalinks use the format `{server}/{team_name}/pl/{post_id}`.
+///
+/// # Arguments
+/// * `permalink` - The permalink URL
+///
+/// # Returns
+/// The post ID if the URL is a permalink, None otherwise
+pub fn parse_permalink(permalink: &str) -> Option<String> {
+    let url = url::Url::parse(permalink).ok()?;
+    let mut segments = url.path_segments()?;
+    segments.find(|segment| *segment == "pl")?;
+    let post_id = segments.next()?;
+
+    if post_id.is_empty() {
+        None
+    } else {
+        Some(post_id.to_string())
+    }
+}
 
 impl MattermostClient {
     /// Send a message (post) to a channel
@@ -19,6 +71,36 @@ impl MattermostClient {
         self.handle_response(response).await
     }
 
+    /// Send a message with options such as a thread or an identity override
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel to send the message to
+    /// * `message` - The message text to send
+    /// * `options` - The send options
+    ///
+    /// # Returns
+    /// A Result containing the created post or an Error
+    pub async fn send_message_with_options(
+        &self,
+        channel_id: &str,
+        message: &str,
+        options: &SendMessageOptions,
+    ) -> Result<MattermostPost> {
+        let mut request = CreatePostRequest::new(channel_id.to_string(), message.to_string());
+        if let Some(root_id) = &options.root_id {
+            request = request.with_root_id(root_id.clone());
+        }
+        if options.overrides_identity() {
+            request = request.with_props(identity_override_props(options));
+        }
+        if !options.file_ids.is_empty() {
+            request = request.with_files(options.file_ids.clone());
+        }
+
+        let response = self.post("/posts", &request).await?;
+        self.handle_response(response).await
+    }
+
     /// Send a message as a reply to another post
     ///
     /// # Arguments
@@ -54,6 +136,41 @@ impl MattermostClient {
         self.handle_response(response).await
     }
 
+    /// Build a permalink to a post
+    ///
+    /// # Arguments
+    /// * `post_id` - The ID of the post to link to
+    ///
+    /// # Returns
+    /// A Result containing the permalink URL or an Error
+    ///
+    /// # Notes
+    /// DM and group channels don't belong to a team, so their permalinks
+    /// use the current team.
+    pub async fn get_permalink(&self, post_id: &str) -> Result<String> {
+        let post = self.get_post(post_id).await?;
+        let channel = self.get_channel_cached(&post.channel_id).await?;
+
+        let team_id = if !channel.team_id.is_empty() {
+            channel.team_id
+        } else {
+            self.get_team_id().await.ok_or_else(|| {
+                Error::new(
+                    ErrorCode::InvalidState,
+                    "No team set - required to build permalinks for direct messages",
+                )
+            })?
+        };
+        let team = self.get_team_cached(&team_id).await?;
+
+        Ok(format!(
+            "{}/{}/pl/{}",
+            self.get_base_url().trim_end_matches('/'),
+            team.name,
+            post.id
+        ))
+    }
+
     /// Get posts for a channel
     ///
     /// # Arguments
@@ -184,6 +301,42 @@ impl MattermostClient {
         let response = self.get(&endpoint).await?;
         self.handle_response(response).await
     }
+
+    /// Get posts created, edited or deleted since a point in time
+    ///
+    /// The result is not paginated. Deleted posts are included with a
+    /// non-zero `delete_at`.
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel
+    /// * `since` - Unix timestamp in milliseconds
+    ///
+    /// # Returns
+    /// A Result containing a PostList or an Error
+    pub async fn get_posts_since(&self, channel_id: &str, since: u64) -> Result<PostList> {
+        let endpoint = format!("/channels/{channel_id}/posts?since={since}");
+        let response = self.get(&endpoint).await?;
+        self.handle_response(response).await
+    }
+
+    /// Get the OpenGraph metadata of a web page, as used for link previews
+    ///
+    /// The server fetches and caches the page, so previews match the ones
+    /// shown by the official clients.
+    ///
+    /// # Arguments
+    /// * `url` - The URL of the page
+    ///
+    /// # Returns
+    /// A Result containing the OpenGraph metadata or an Error
+    ///
+    /// # API Endpoint
+    /// POST /opengraph
+    pub async fn get_opengraph(&self, url: &str) -> Result<MattermostOpenGraph> {
+        let body = serde_json::json!({ "url": url });
+        let response = self.post("/opengraph", &body).await?;
+        self.handle_response(response).await
+    }
 }
 
 #[cfg(test)]
@@ -208,4 +361,41 @@ mod tests {
             "https://mattermost.example.com/api/v4/channels/channel123/posts?page=0&per_page=60"
         );
     }
+
+    #[test]
+    fn test_parse_permalink() {
+        assert_eq!(
+            parse_permalink(
+                "https://mattermost.example.com/engineering/pl/a4aurxyyc3yruntz4zfmdw75nr"
+            ),
+            Some("a4aurxyyc3yruntz4zfmdw75nr".to_string())
+        );
+        assert_eq!(
+            parse_permalink(
+                "https://example.com/chat/engineering/pl/a4aurxyyc3yruntz4zfmdw75nr?x=1"
+            ),
+            Some("a4aurxyyc3yruntz4zfmdw75nr".to_string())
+        );
+        assert_eq!(
+            parse_permalink("https://mattermost.example.com/engineering/channels/town-square"),
+            None
+        );
+        assert_eq!(parse_permalink("not a url"), None);
+    }
+
+    #[test]
+    fn test_identity_override_props() {
+        assert!(identity_override_props(&SendMessageOptions::default()).is_empty());
+
+        let options = SendMessageOptions {
+            username: Some("deploy-bot".to_string()),
+            icon_emoji: Some(":rocket:".to_string()),
+            ..Default::default()
+        };
+        let props = identity_override_props(&options);
+        assert_eq!(props["from_webhook"], "true");
+        assert_eq!(props["override_username"], "deploy-bot");
+        assert_eq!(props["override_icon_emoji"], "rocket");
+        assert!(!props.contains_key("override_icon_url"));
+    }
 }
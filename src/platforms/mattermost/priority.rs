@@ -0,0 +1,141 @@
+//! Post priority and acknowledgement operations for Mattermost
+
+use super::client::MattermostClient;
+use super::compat::{unsupported_if_missing, ServerVersion};
+use super::types::{
+    CreatePostRequest, MattermostPost, MattermostPostAcknowledgement, MattermostPostPriority,
+};
+use crate::error::Result;
+use crate::types::MessagePriority;
+
+const PRIORITY_FEATURE: &str = "Message priority";
+const ACKNOWLEDGEMENTS_FEATURE: &str = "Message acknowledgements";
+/// First server version with post priority and acknowledgements
+const PRIORITY_MIN_VERSION: ServerVersion = ServerVersion::new(7, 7, 0);
+
+impl MattermostClient {
+    /// Send a message with a priority label and, optionally, an acknowledgement request
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel to send the message to
+    /// * `message` - The message text to send
+    /// * `priority` - The priority settings of the message
+    ///
+    /// # Returns
+    /// A Result containing the created post or an Error
+    ///
+    /// # Notes
+    /// Servers older than 7.7 would silently drop the priority, so the call
+    /// fails with UnsupportedByServer instead when the server version is known.
+    pub async fn send_message_with_priority(
+        &self,
+        channel_id: &str,
+        message: &str,
+        priority: &MessagePriority,
+    ) -> Result<MattermostPost> {
+        self.require_server_version(PRIORITY_FEATURE, PRIORITY_MIN_VERSION)
+            .await?;
+        let request = CreatePostRequest::new(channel_id.to_string(), message.to_string())
+            .with_priority(MattermostPostPriority::from(priority));
+
+        let response = self.post("/posts", &request).await?;
+        self.handle_response(response).await
+    }
+
+    /// Acknowledge a post as the current user
+    ///
+    /// # Arguments
+    /// * `post_id` - The ID of the post to acknowledge
+    ///
+    /// # Returns
+    /// A Result containing the acknowledgement or an Error
+    ///
+    /// # API Endpoint
+    /// POST /users/{user_id}/posts/{post_id}/ack
+    pub async fn ack_post(&self, post_id: &str) -> Result<MattermostPostAcknowledgement> {
+        self.require_server_version(ACKNOWLEDGEMENTS_FEATURE, PRIORITY_MIN_VERSION)
+            .await?;
+        let user_id = self.current_user_id().await?;
+
+        let endpoint = format!("/users/{user_id}/posts/{post_id}/ack");
+        let response = self.post(&endpoint, &serde_json::json!({})).await?;
+        self.handle_response(response)
+            .await
+            .map_err(|e| unsupported_if_missing(ACKNOWLEDGEMENTS_FEATURE, e))
+    }
+
+    /// Get the acknowledgements of a post
+    ///
+    /// There is no dedicated endpoint; acknowledgements are part of the
+    /// post's metadata.
+    ///
+    /// # Arguments
+    /// * `post_id` - The ID of the post
+    ///
+    /// # Returns
+    /// A Result containing the acknowledgements, oldest first
+    pub async fn get_post_acknowledgements(
+        &self,
+        post_id: &str,
+    ) -> Result<Vec<MattermostPostAcknowledgement>> {
+        self.require_server_version(ACKNOWLEDGEMENTS_FEATURE, PRIORITY_MIN_VERSION)
+            .await?;
+        let post = self.get_post(post_id).await?;
+
+        let mut acknowledgements = post.metadata.acknowledgements;
+        acknowledgements.sort_by_key(|ack| ack.acknowledged_at);
+        Ok(acknowledgements)
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::types::PriorityLevel;
+
+    #[test]
+    fn test_create_post_with_priority() {
+        let priority = MessagePriority {
+            priority: PriorityLevel::Urgent,
+            requested_ack: true,
+            ..Default::default()
+        };
+        let request = CreatePostRequest::new("ch1".to_string(), "Disk full".to_string())
+            .with_priority(MattermostPostPriority::from(&priority));
+
+        assert_eq!(
+            serde_json::to_value(&request).unwrap(),
+            serde_json::json!({
+                "channel_id": "ch1",
+                "message": "Disk full",
+                "metadata": {
+                    "priority": {
+                        "priority": "urgent",
+                        "requested_ack": true,
+                        "persistent_notifications": false
+                    }
+                }
+            })
+        );
+    }
+
+    #[test]
+    fn test_post_acknowledgements_deserialization() {
+        let json = r#"{
+            "id": "p1", "create_at": 1, "update_at": 1, "delete_at": 0, "edit_at": 0,
+            "user_id": "u1", "channel_id": "ch1", "message": "Disk full",
+            "metadata": {
+                "priority": {"priority": "urgent", "requested_ack": true},
+                "acknowledgements": [
+                    {"user_id": "u2", "post_id": "p1", "acknowledged_at": 1700000000000}
+                ]
+            }
+        }"#;
+
+        let post: MattermostPost = serde_json::from_str(json).unwrap();
+        let priority = MessagePriority::from(post.metadata.priority.unwrap());
+        assert_eq!(priority.priority, PriorityLevel::Urgent);
+        assert!(priority.requested_ack);
+        assert_eq!(post.metadata.acknowledgements[0].user_id, "u2");
+    }
+}
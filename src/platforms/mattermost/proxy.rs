@@ -0,0 +1,375 @@
+//! Proxy support for the REST and WebSocket connections
+//!
+//! reqwest routes REST calls through the proxy itself. tokio-tungstenite has
+//! no proxy support, so WebSocket connections open the tunnel here (HTTP
+//! CONNECT or SOCKS5) and run the WebSocket handshake over it.
+
+use tokio::io::{AsyncReadExt, AsyncWriteExt};
+use tokio::net::TcpStream;
+use url::Url;
+
+use crate::error::{Error, ErrorCode, Result};
+
+/// Largest proxy response header accepted while opening a tunnel
+const MAX_CONNECT_RESPONSE: usize = 8 * 1024;
+
+/// Proxy to reach the server through
+#[derive(Debug, Clone)]
+pub struct ProxyConfig {
+    /// Proxy URL: http://, socks5:// (names resolved locally) or
+    /// socks5h:// (names resolved by the proxy); may carry user:password
+    url: Url,
+    /// Hosts reached directly, as in the NO_PROXY environment variable
+    no_proxy: Vec<String>,
+}
+
+impl ProxyConfig {
+    /// Create a proxy configuration
+    ///
+    /// # Arguments
+    /// * `url` - The proxy URL, e.g. "http://proxy.internal:3128" or "socks5h://proxy:1080"
+    /// * `no_proxy` - Comma-separated hosts to reach directly; "*" bypasses the proxy entirely,
+    ///   "example.com" and ".example.com" also match subdomains
+    ///
+    /// # Returns
+    /// A Result containing the ProxyConfig, or an InvalidArgument error
+    pub fn new(url: &str, no_proxy: Option<&str>) -> Result<Self> {
+        let url = Url::parse(url).map_err(|e| {
+            Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid proxy URL: {e}"),
+            )
+        })?;
+        match url.scheme() {
+            "http" | "socks5" | "socks5h" => {}
+            scheme => {
+                return Err(Error::new(
+                    ErrorCode::InvalidArgument,
+                    format!("Unsupported proxy scheme '{scheme}' (use http, socks5 or socks5h)"),
+                ))
+            }
+        }
+        if url.host_str().is_none() {
+            return Err(Error::new(
+                ErrorCode::InvalidArgument,
+                "Proxy URL has no host",
+            ));
+        }
+
+        let no_proxy = no_proxy
+            .unwrap_or_default()
+            .split(',')
+            .map(|host| host.trim().to_ascii_lowercase())
+            .filter(|host| !host.is_empty())
+            .collect();
+        Ok(Self { url, no_proxy })
+    }
+
+    /// Get the proxy URL
+    pub fn url(&self) -> &Url {
+        &self.url
+    }
+
+    /// Check whether a host is reached directly rather than through the proxy
+    pub fn bypasses(&self, host: &str) -> bool {
+        let host = host
+            .trim_start_matches('[')
+            .trim_end_matches(']')
+            .to_ascii_lowercase();
+        self.no_proxy.iter().any(|entry| {
+            let domain = entry.trim_start_matches('.');
+            entry == "*"
+                || host == domain
+                || host
+                    .strip_suffix(domain)
+                    .is_some_and(|prefix| prefix.ends_with('.'))
+        })
+    }
+
+    /// Build the reqwest proxy for REST calls
+    pub(crate) fn reqwest_proxy(&self) -> Result<reqwest::Proxy> {
+        let proxy = reqwest::Proxy::all(self.url.as_str()).map_err(|e| {
+            Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid proxy URL: {e}"),
+            )
+        })?;
+        Ok(proxy.no_proxy(reqwest::NoProxy::from_string(&self.no_proxy.join(","))))
+    }
+
+    /// Open a TCP stream to `host:port` through the proxy
+    ///
+    /// The stream is a plain tunnel; TLS to the server runs over it.
+    pub(crate) async fn tunnel(&self, host: &str, port: u16) -> Result<TcpStream> {
+        let proxy_host = self.url.host_str().unwrap_or_default();
+        let proxy_port = self
+            .url
+            .port_or_known_default()
+            .unwrap_or(match self.url.scheme() {
+                "socks5" | "socks5h" => 1080,
+                _ => 3128,
+            });
+        let mut stream = TcpStream::connect((proxy_host, proxy_port))
+            .await
+            .map_err(|e| proxy_error(format!("connecting to {proxy_host}:{proxy_port}: {e}")))?;
+
+        match self.url.scheme() {
+            "socks5" | "socks5h" => self.socks5_connect(&mut stream, host, port).await?,
+            _ => self.http_connect(&mut stream, host, port).await?,
+        }
+        Ok(stream)
+    }
+
+    /// Ask an HTTP proxy to open a tunnel with CONNECT
+    async fn http_connect(&self, stream: &mut TcpStream, host: &str, port: u16) -> Result<()> {
+        let target = if host.contains(':') {
+            format!("[{host}]:{port}")
+        } else {
+            format!("{host}:{port}")
+        };
+        let mut request = format!("CONNECT {target} HTTP/1.1\r\nHost: {target}\r\n");
+        if !self.url.username().is_empty() {
+            let credentials = format!(
+                "{}:{}",
+                percent_decode(self.url.username()),
+                percent_decode(self.url.password().unwrap_or_default())
+            );
+            request.push_str(&format!(
+                "Proxy-Authorization: Basic {}\r\n",
+                base64_encode(credentials.as_bytes())
+            ));
+        }
+        request.push_str("\r\n");
+        stream
+            .write_all(request.as_bytes())
+            .await
+            .map_err(|e| proxy_error(e.to_string()))?;
+
+        // Read the response header byte by byte so nothing of the tunnel is consumed
+        let mut response = Vec::new();
+        while !response.ends_with(b"\r\n\r\n") {
+            if response.len() >= MAX_CONNECT_RESPONSE {
+                return Err(proxy_error("response header too large".to_string()));
+            }
+            let byte = stream
+                .read_u8()
+                .await
+                .map_err(|e| proxy_error(e.to_string()))?;
+            response.push(byte);
+        }
+        check_connect_response(&response)
+    }
+
+    /// Open a tunnel through a SOCKS5 proxy (RFC 1928, with RFC 1929 authentication)
+    async fn socks5_connect(&self, stream: &mut TcpStream, host: &str, port: u16) -> Result<()> {
+        let io = |e: std::io::Error| proxy_error(e.to_string());
+        let username = percent_decode(self.url.username());
+        let password = percent_decode(self.url.password().unwrap_or_default());
+
+        // Offer no authentication, and username/password if we have them
+        let greeting: &[u8] = if username.is_empty() {
+            &[5, 1, 0]
+        } else {
+            &[5, 2, 0, 2]
+        };
+        stream.write_all(greeting).await.map_err(io)?;
+        let mut choice = [0u8; 2];
+        stream.read_exact(&mut choice).await.map_err(io)?;
+        match choice {
+            [5, 0] => {}
+            [5, 2] if !username.is_empty() => {
+                if username.len() > 255 || password.len() > 255 {
+                    return Err(proxy_error("credentials too long for SOCKS5".to_string()));
+                }
+                let mut auth = vec![1, username.len() as u8];
+                auth.extend_from_slice(username.as_bytes());
+                auth.push(password.len() as u8);
+                auth.extend_from_slice(password.as_bytes());
+                stream.write_all(&auth).await.map_err(io)?;
+                let mut status = [0u8; 2];
+                stream.read_exact(&mut status).await.map_err(io)?;
+                if status[1] != 0 {
+                    return Err(proxy_error("SOCKS5 authentication failed".to_string()));
+                }
+            }
+            _ => {
+                return Err(proxy_error(
+                    "SOCKS5 proxy accepts none of the offered authentication methods".to_string(),
+                ))
+            }
+        }
+
+        let mut request = vec![5, 1, 0];
+        if self.url.scheme() == "socks5h" {
+            if host.len() > 255 {
+                return Err(proxy_error("host name too long for SOCKS5".to_string()));
+            }
+            request.push(3);
+            request.push(host.len() as u8);
+            request.extend_from_slice(host.as_bytes());
+        } else {
+            let addr = tokio::net::lookup_host((host, port))
+                .await
+                .map_err(io)?
+                .next()
+                .ok_or_else(|| proxy_error(format!("could not resolve {host}")))?;
+            match addr.ip() {
+                std::net::IpAddr::V4(ip) => {
+                    request.push(1);
+                    request.extend_from_slice(&ip.octets());
+                }
+                std::net::IpAddr::V6(ip) => {
+                    request.push(4);
+                    request.extend_from_slice(&ip.octets());
+                }
+            }
+        }
+        request.extend_from_slice(&port.to_be_bytes());
+        stream.write_all(&request).await.map_err(io)?;
+
+        // Reply: version, status, reserved, then the bound address
+        let mut reply = [0u8; 4];
+        stream.read_exact(&mut reply).await.map_err(io)?;
+        if reply[1] != 0 {
+            return Err(proxy_error(format!(
+                "SOCKS5 connect failed ({})",
+                socks5_reply_message(reply[1])
+            )));
+        }
+        let addr_len = match reply[3] {
+            1 => 4,
+            4 => 16,
+            3 => stream.read_u8().await.map_err(io)? as usize,
+            other => return Err(proxy_error(format!("unknown SOCKS5 address type {other}"))),
+        };
+        let mut bound = vec![0u8; addr_len + 2];
+        stream.read_exact(&mut bound).await.map_err(io)?;
+        Ok(())
+    }
+}
+
+fn proxy_error(message: String) -> Error {
+    Error::new(ErrorCode::NetworkError, format!("Proxy error: {message}"))
+}
+
+/// Check the status line of a CONNECT response
+fn check_connect_response(response: &[u8]) -> Result<()> {
+    let text = String::from_utf8_lossy(response);
+    let status_line = text.lines().next().unwrap_or_default();
+    let status = status_line.split_whitespace().nth(1).unwrap_or_default();
+    if status.starts_with('2') {
+        return Ok(());
+    }
+    if status == "407" {
+        return Err(Error::new(
+            ErrorCode::AuthenticationFailed,
+            "Proxy authentication required",
+        ));
+    }
+    Err(proxy_error(format!("CONNECT refused: {status_line}")))
+}
+
+fn socks5_reply_message(code: u8) -> &'static str {
+    match code {
+        1 => "general failure",
+        2 => "connection not allowed by ruleset",
+        3 => "network unreachable",
+        4 => "host unreachable",
+        5 => "connection refused",
+        6 => "TTL expired",
+        7 => "command not supported",
+        8 => "address type not supported",
+        _ => "unknown error",
+    }
+}
+
+/// Decode %XX escapes in URL user info
+fn percent_decode(s: &str) -> String {
+    let bytes = s.as_bytes();
+    let mut out = Vec::with_capacity(bytes.len());
+    let mut i = 0;
+    while i < bytes.len() {
+        if bytes[i] == b'%' && i + 2 < bytes.len() {
+            if let Ok(byte) = u8::from_str_radix(&s[i + 1..i + 3], 16) {
+                out.push(byte);
+                i += 3;
+                continue;
+            }
+        }
+        out.push(bytes[i]);
+        i += 1;
+    }
+    String::from_utf8_lossy(&out).into_owned()
+}
+
+/// Standard base64 with padding, for the Proxy-Authorization header
+fn base64_encode(input: &[u8]) -> String {
+    const ALPHABET: &[u8; 64] = b"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/";
+    let mut out = String::with_capacity(input.len().div_ceil(3) * 4);
+    for chunk in input.chunks(3) {
+        let b = [
+            chunk[0],
+            chunk.get(1).copied().unwrap_or(0),
+            chunk.get(2).copied().unwrap_or(0),
+        ];
+        let n = (u32::from(b[0]) << 16) | (u32::from(b[1]) << 8) | u32::from(b[2]);
+        for i in 0..4 {
+            if i <= chunk.len() {
+                out.push(ALPHABET[(n >> (18 - 6 * i)) as usize & 63] as char);
+            } else {
+                out.push('=');
+            }
+        }
+    }
+    out
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_proxy_config_validates_scheme() {
+        assert!(ProxyConfig::new("http://proxy:3128", None).is_ok());
+        assert!(ProxyConfig::new("socks5h://proxy:1080", None).is_ok());
+        assert!(ProxyConfig::new("ftp://proxy", None).is_err());
+        assert!(ProxyConfig::new("https://proxy", None).is_err());
+        assert!(ProxyConfig::new("not a url", None).is_err());
+    }
+
+    #[test]
+    fn test_no_proxy_matching() {
+        let proxy = ProxyConfig::new(
+            "http://proxy:3128",
+            Some("localhost, .internal,example.com"),
+        )
+        .unwrap();
+        assert!(proxy.bypasses("localhost"));
+        assert!(proxy.bypasses("chat.internal"));
+        assert!(proxy.bypasses("example.com"));
+        assert!(proxy.bypasses("chat.Example.com"));
+        assert!(!proxy.bypasses("notexample.com"));
+        assert!(!proxy.bypasses("mattermost.example.org"));
+
+        let all = ProxyConfig::new("http://proxy:3128", Some("*")).unwrap();
+        assert!(all.bypasses("anything"));
+    }
+
+    #[test]
+    fn test_connect_response_status() {
+        assert!(check_connect_response(b"HTTP/1.1 200 Connection established\r\n\r\n").is_ok());
+        let err = check_connect_response(b"HTTP/1.1 407 Proxy Authentication Required\r\n\r\n")
+            .unwrap_err();
+        assert_eq!(err.code, ErrorCode::AuthenticationFailed);
+        assert!(check_connect_response(b"HTTP/1.1 403 Forbidden\r\n\r\n").is_err());
+    }
+
+    #[test]
+    fn test_base64_and_percent_decode() {
+        assert_eq!(base64_encode(b"user:pass"), "dXNlcjpwYXNz");
+        assert_eq!(base64_encode(b"ab"), "YWI=");
+        assert_eq!(base64_encode(b"a"), "YQ==");
+        assert_eq!(percent_decode("p%40ss%3Aword"), "p@ss:word");
+        assert_eq!(percent_decode("100%"), "100%");
+    }
+}
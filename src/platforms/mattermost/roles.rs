@@ -0,0 +1,169 @@
+//! Role and permission resolution for Mattermost
+//!
+//! Mattermost grants permissions through roles at three levels: system roles on the
+//! user, team roles on the team membership and channel roles on the channel
+//! membership. A user's effective permissions in a channel are the union of the
+//! permissions of all of those roles.
+
+use super::client::MattermostClient;
+use super::types::{MattermostRole, TeamMember};
+use crate::error::Result;
+
+impl MattermostClient {
+    /// Get a user's membership in a team
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team
+    /// * `user_id` - The ID of the user
+    ///
+    /// # Returns
+    /// A Result containing the team membership
+    ///
+    /// # API Endpoint
+    /// GET /teams/{team_id}/members/{user_id}
+    pub async fn get_team_member(&self, team_id: &str, user_id: &str) -> Result<TeamMember> {
+        let endpoint = format!("/teams/{team_id}/members/{user_id}");
+        let response = self.get(&endpoint).await?;
+        self.handle_response(response).await
+    }
+
+    /// Get a list of roles by name
+    ///
+    /// # Arguments
+    /// * `role_names` - The names of the roles (e.g., "system_user", "channel_admin")
+    ///
+    /// # Returns
+    /// A Result containing the roles that exist
+    ///
+    /// # API Endpoint
+    /// POST /roles/names
+    pub async fn get_roles_by_names(&self, role_names: &[String]) -> Result<Vec<MattermostRole>> {
+        let response = self.post("/roles/names", &role_names).await?;
+        self.handle_response(response).await
+    }
+
+    /// Get roles by name with caching
+    ///
+    /// Only the roles missing from the cache are fetched, in a single request.
+    ///
+    /// # Arguments
+    /// * `role_names` - The names of the roles
+    ///
+    /// # Returns
+    /// A Result containing the roles that exist
+    pub async fn get_roles_by_names_cached(
+        &self,
+        role_names: &[String],
+    ) -> Result<Vec<MattermostRole>> {
+        let mut roles = Vec::new();
+        let mut missing = Vec::new();
+
+        for name in role_names {
+            match self.role_cache.get(name).await {
+                Some(role) => roles.push(role),
+                None => missing.push(name.clone()),
+            }
+        }
+
+        if !missing.is_empty() {
+            for role in self.get_roles_by_names(&missing).await? {
+                self.role_cache.set(role.name.clone(), role.clone()).await;
+                roles.push(role);
+            }
+        }
+
+        Ok(roles)
+    }
+
+    /// Resolve a user's effective permissions in a channel
+    ///
+    /// Combines the user's system roles, their team roles (for team channels) and
+    /// their channel roles. Results are cached until a role event invalidates them.
+    ///
+    /// # Arguments
+    /// * `user_id` - The ID of the user
+    /// * `channel_id` - The ID of the channel
+    ///
+    /// # Returns
+    /// A Result containing the sorted list of permission names
+    pub async fn get_effective_permissions(
+        &self,
+        user_id: &str,
+        channel_id: &str,
+    ) -> Result<Vec<String>> {
+        let cache_key = format!("{user_id}:{channel_id}");
+        if let Some(permissions) = self.permission_cache.get(&cache_key).await {
+            return Ok(permissions);
+        }
+
+        let user = self.get_user_cached(user_id).await?;
+        let channel = self.get_channel_cached(channel_id).await?;
+
+        let mut role_names = split_roles(&user.roles);
+        if !channel.team_id.is_empty() {
+            let team_member = self.get_team_member(&channel.team_id, user_id).await?;
+            role_names.extend(split_roles(&team_member.roles));
+        }
+        let channel_member = self.get_channel_member(channel_id, user_id).await?;
+        role_names.extend(split_roles(&channel_member.roles));
+        role_names.sort();
+        role_names.dedup();
+
+        let mut permissions: Vec<String> = self
+            .get_roles_by_names_cached(&role_names)
+            .await?
+            .into_iter()
+            .flat_map(|role| role.permissions)
+            .collect();
+        permissions.sort();
+        permissions.dedup();
+
+        self.permission_cache
+            .set(cache_key, permissions.clone())
+            .await;
+
+        Ok(permissions)
+    }
+
+    /// Invalidate cached roles and effective permissions
+    ///
+    /// This is typically called when a WebSocket event indicates that a role
+    /// definition or a user's role assignment has changed.
+    pub async fn invalidate_permission_cache(&self) {
+        self.role_cache.clear().await;
+        self.permission_cache.clear().await;
+    }
+}
+
+/// Split a Mattermost space-separated roles string into role names
+fn split_roles(roles: &str) -> Vec<String> {
+    roles.split_whitespace().map(|r| r.to_string()).collect()
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_role_endpoints() {
+        let client = MattermostClient::new("https://mattermost.example.com").unwrap();
+
+        assert_eq!(
+            client.api_url("/roles/names"),
+            "https://mattermost.example.com/api/v4/roles/names"
+        );
+        assert_eq!(
+            client.api_url("/teams/team123/members/user123"),
+            "https://mattermost.example.com/api/v4/teams/team123/members/user123"
+        );
+    }
+
+    #[test]
+    fn test_split_roles() {
+        assert_eq!(
+            split_roles("system_user  system_admin"),
+            vec!["system_user".to_string(), "system_admin".to_string()]
+        );
+        assert!(split_roles("").is_empty());
+    }
+}
@@ -1,3 +1,5 @@
+use std::collections::HashMap;
+
 use crate::error::Result;
 use serde::{Deserialize, Serialize};
 
@@ -370,6 +372,49 @@ impl MattermostClient {
     }
 }
 
+/// Build the search terms that find posts mentioning a user
+///
+/// Matches what the web app searches for under "Recent mentions": the
+/// @-username, the user's custom mention keys and, if enabled, their first
+/// name. Channel-wide mentions are left out, as they'd match most of the team's
+/// history. Use with an OR search.
+pub fn mention_search_terms(
+    username: &str,
+    first_name: &str,
+    notify_props: &HashMap<String, String>,
+) -> String {
+    let mut keys = vec![format!("@{username}")];
+    if let Some(mention_keys) = notify_props.get("mention_keys") {
+        keys.extend(
+            mention_keys
+                .split(',')
+                .map(str::trim)
+                .filter(|k| !k.is_empty())
+                .map(String::from),
+        );
+    }
+    if notify_props.get("first_name").is_some_and(|v| v == "true") && !first_name.is_empty() {
+        keys.push(first_name.to_string());
+    }
+
+    let mut terms: Vec<String> = Vec::new();
+    for key in keys {
+        let term = if key.contains(char::is_whitespace) {
+            format!("\"{key}\"")
+        } else {
+            key
+        };
+        // Search is case-insensitive, so "Alice" and "alice" are the same key
+        if !terms
+            .iter()
+            .any(|t| t.to_lowercase() == term.to_lowercase())
+        {
+            terms.push(term);
+        }
+    }
+    terms.join(" ")
+}
+
 // ============================================================================
 // Tests
 // ============================================================================
@@ -422,4 +467,20 @@ mod tests {
         assert_eq!(options.page, 0);
         assert_eq!(options.per_page, 0);
     }
+
+    #[test]
+    fn test_mention_search_terms() {
+        let mut props = HashMap::new();
+        assert_eq!(mention_search_terms("alice", "Alice", &props), "@alice");
+
+        props.insert(
+            "mention_keys".to_string(),
+            "alice, on-call,release team,@alice".to_string(),
+        );
+        props.insert("first_name".to_string(), "true".to_string());
+        assert_eq!(
+            mention_search_terms("alice", "Alice", &props),
+            "@alice alice on-call \"release team\""
+        );
+    }
 }
@@ -0,0 +1,98 @@
+//! Session management operations for Mattermost
+
+use super::client::MattermostClient;
+use super::types::{MattermostSession, RevokeSessionRequest};
+use crate::error::{Error, ErrorCode, Result};
+
+impl MattermostClient {
+    /// Get the active sessions of a user
+    ///
+    /// # Arguments
+    /// * `user_id` - The ID of the user
+    ///
+    /// # Returns
+    /// A Result containing the user's sessions
+    ///
+    /// # API Endpoint
+    /// GET /users/{user_id}/sessions
+    pub async fn get_sessions(&self, user_id: &str) -> Result<Vec<MattermostSession>> {
+        let endpoint = format!("/users/{user_id}/sessions");
+        let response = self.get(&endpoint).await?;
+        self.handle_response(response).await
+    }
+
+    /// Revoke a single session of a user
+    ///
+    /// # Arguments
+    /// * `user_id` - The ID of the user the session belongs to
+    /// * `session_id` - The ID of the session to revoke
+    ///
+    /// # API Endpoint
+    /// POST /users/{user_id}/sessions/revoke
+    pub async fn revoke_session(&self, user_id: &str, session_id: &str) -> Result<()> {
+        let request = RevokeSessionRequest {
+            session_id: session_id.to_string(),
+        };
+
+        let endpoint = format!("/users/{user_id}/sessions/revoke");
+        let response = self.post(&endpoint, &request).await?;
+        let status = response.status();
+        if status.is_success() {
+            Ok(())
+        } else {
+            Err(Error::new(
+                ErrorCode::NetworkError,
+                format!("Failed to revoke session: {status}"),
+            )
+            .with_http_status(status.as_u16()))
+        }
+    }
+
+    /// Revoke every session of a user
+    ///
+    /// # Arguments
+    /// * `user_id` - The ID of the user
+    ///
+    /// # API Endpoint
+    /// POST /users/{user_id}/sessions/revoke/all
+    pub async fn revoke_all_sessions(&self, user_id: &str) -> Result<()> {
+        let endpoint = format!("/users/{user_id}/sessions/revoke/all");
+        let response = self.post(&endpoint, &serde_json::json!({})).await?;
+        let status = response.status();
+        if status.is_success() {
+            Ok(())
+        } else {
+            Err(Error::new(
+                ErrorCode::NetworkError,
+                format!("Failed to revoke sessions: {status}"),
+            )
+            .with_http_status(status.as_u16()))
+        }
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_session_deserialization() {
+        let json = r#"{
+            "id": "sess1",
+            "token": "",
+            "create_at": 1700000000000,
+            "expires_at": 1702592000000,
+            "last_activity_at": 1700000500000,
+            "user_id": "user1",
+            "device_id": "",
+            "roles": "system_user",
+            "is_oauth": false,
+            "props": {"os": "Linux", "browser": "Firefox/120.0", "platform": "Linux"},
+            "team_members": []
+        }"#;
+
+        let session: MattermostSession = serde_json::from_str(json).unwrap();
+        assert_eq!(session.user_id, "user1");
+        assert_eq!(session.props.get("os").map(String::as_str), Some("Linux"));
+    }
+}
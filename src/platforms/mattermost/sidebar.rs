@@ -0,0 +1,220 @@
+//! Sidebar category operations for Mattermost
+
+use super::client::MattermostClient;
+use super::types::{MattermostSidebarCategory, OrderedSidebarCategories};
+use crate::error::{Error, ErrorCode, Result};
+
+impl MattermostClient {
+    /// Get the current user's sidebar categories for a team
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team
+    ///
+    /// # Returns
+    /// A Result containing the categories and their display order
+    ///
+    /// # API Endpoint
+    /// GET /users/me/teams/{team_id}/channels/categories
+    pub async fn get_sidebar_categories(&self, team_id: &str) -> Result<OrderedSidebarCategories> {
+        let endpoint = format!("/users/me/teams/{team_id}/channels/categories");
+        let response = self.get(&endpoint).await?;
+        self.handle_response(response).await
+    }
+
+    /// Create a custom sidebar category
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team
+    /// * `display_name` - The name of the new category
+    /// * `channel_ids` - Channels to move into the new category
+    ///
+    /// # Returns
+    /// A Result containing the created category
+    ///
+    /// # API Endpoint
+    /// POST /users/{user_id}/teams/{team_id}/channels/categories
+    pub async fn create_sidebar_category(
+        &self,
+        team_id: &str,
+        display_name: &str,
+        channel_ids: Vec<String>,
+    ) -> Result<MattermostSidebarCategory> {
+        let user_id = self.current_user_id().await?;
+        let category = MattermostSidebarCategory {
+            id: String::new(),
+            user_id: user_id.clone(),
+            team_id: team_id.to_string(),
+            sort_order: 0,
+            sorting: String::new(),
+            category_type: "custom".to_string(),
+            display_name: display_name.to_string(),
+            muted: false,
+            collapsed: false,
+            channel_ids,
+        };
+
+        let endpoint = format!("/users/{user_id}/teams/{team_id}/channels/categories");
+        let response = self.post(&endpoint, &category).await?;
+        self.handle_response(response).await
+    }
+
+    /// Update several sidebar categories at once
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team
+    /// * `categories` - The full, updated category objects
+    ///
+    /// # Returns
+    /// A Result containing the updated categories
+    ///
+    /// # API Endpoint
+    /// PUT /users/{user_id}/teams/{team_id}/channels/categories
+    pub async fn update_sidebar_categories(
+        &self,
+        team_id: &str,
+        categories: &[MattermostSidebarCategory],
+    ) -> Result<Vec<MattermostSidebarCategory>> {
+        let user_id = self.current_user_id().await?;
+        let endpoint = format!("/users/{user_id}/teams/{team_id}/channels/categories");
+        let response = self.put(&endpoint, &categories).await?;
+        self.handle_response(response).await
+    }
+
+    /// Delete a custom sidebar category
+    ///
+    /// Channels in the deleted category are moved back to their default categories.
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team
+    /// * `category_id` - The ID of the category to delete
+    ///
+    /// # API Endpoint
+    /// DELETE /users/{user_id}/teams/{team_id}/channels/categories/{category_id}
+    pub async fn delete_sidebar_category(&self, team_id: &str, category_id: &str) -> Result<()> {
+        let user_id = self.current_user_id().await?;
+        let endpoint =
+            format!("/users/{user_id}/teams/{team_id}/channels/categories/{category_id}");
+        let response = self.delete(&endpoint).await?;
+
+        if response.status().is_success() {
+            Ok(())
+        } else {
+            Err(Error::new(
+                ErrorCode::NetworkError,
+                format!("Failed to delete sidebar category: {}", response.status()),
+            ))
+        }
+    }
+
+    /// Set the display order of the sidebar categories
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team
+    /// * `category_ids` - All category IDs, in the desired order
+    ///
+    /// # Returns
+    /// A Result containing the new order
+    ///
+    /// # API Endpoint
+    /// PUT /users/{user_id}/teams/{team_id}/channels/categories/order
+    pub async fn update_sidebar_category_order(
+        &self,
+        team_id: &str,
+        category_ids: &[String],
+    ) -> Result<Vec<String>> {
+        let user_id = self.current_user_id().await?;
+        let endpoint = format!("/users/{user_id}/teams/{team_id}/channels/categories/order");
+        let response = self.put(&endpoint, &category_ids).await?;
+        self.handle_response(response).await
+    }
+
+    /// Move a channel into a sidebar category
+    ///
+    /// The channel is removed from whichever category currently holds it and
+    /// placed at the top of the target category.
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team
+    /// * `channel_id` - The ID of the channel to move
+    /// * `category_id` - The ID of the target category
+    pub async fn move_channel_to_category(
+        &self,
+        team_id: &str,
+        channel_id: &str,
+        category_id: &str,
+    ) -> Result<()> {
+        let ordered = self.get_sidebar_categories(team_id).await?;
+
+        if !ordered.categories.iter().any(|c| c.id == category_id) {
+            return Err(Error::new(
+                ErrorCode::NotFound,
+                format!("Sidebar category not found: {category_id}"),
+            ));
+        }
+
+        let changed: Vec<MattermostSidebarCategory> = ordered
+            .categories
+            .into_iter()
+            .filter_map(|mut category| {
+                if category.id == category_id {
+                    category.channel_ids.retain(|id| id != channel_id);
+                    category.channel_ids.insert(0, channel_id.to_string());
+                    Some(category)
+                } else if category.channel_ids.iter().any(|id| id == channel_id) {
+                    category.channel_ids.retain(|id| id != channel_id);
+                    Some(category)
+                } else {
+                    None
+                }
+            })
+            .collect();
+
+        self.update_sidebar_categories(team_id, &changed).await?;
+        Ok(())
+    }
+
+    /// Find the built-in sidebar category of a given type
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team
+    /// * `category_type` - "favorites", "channels" or "direct_messages"
+    ///
+    /// # Returns
+    /// A Result containing the category
+    pub async fn get_sidebar_category_by_type(
+        &self,
+        team_id: &str,
+        category_type: &str,
+    ) -> Result<MattermostSidebarCategory> {
+        self.get_sidebar_categories(team_id)
+            .await?
+            .categories
+            .into_iter()
+            .find(|c| c.category_type == category_type)
+            .ok_or_else(|| {
+                Error::new(
+                    ErrorCode::NotFound,
+                    format!("No {category_type} sidebar category"),
+                )
+            })
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_sidebar_endpoints() {
+        let client = MattermostClient::new("https://mattermost.example.com").unwrap();
+
+        assert_eq!(
+            client.api_url("/users/me/teams/team123/channels/categories"),
+            "https://mattermost.example.com/api/v4/users/me/teams/team123/channels/categories"
+        );
+        assert_eq!(
+            client.api_url("/users/user123/teams/team123/channels/categories/order"),
+            "https://mattermost.example.com/api/v4/users/user123/teams/team123/channels/categories/order"
+        );
+    }
+}
@@ -0,0 +1,127 @@
+//! System console operations for Mattermost
+//!
+//! Everything here requires the `manage_system` permission.
+
+use serde::Deserialize;
+
+use super::client::MattermostClient;
+use crate::error::Result;
+use crate::types::SystemStats;
+
+/// One row of the analytics endpoint
+#[derive(Debug, Clone, Deserialize)]
+pub struct AnalyticsRow {
+    pub name: String,
+    pub value: f64,
+}
+
+impl MattermostClient {
+    /// Get the server configuration
+    ///
+    /// # Returns
+    /// A Result containing the configuration, with secrets masked, or an Error
+    ///
+    /// # API Endpoint
+    /// GET /config
+    pub async fn get_config(&self) -> Result<serde_json::Value> {
+        let response = self.get("/config").await?;
+        self.handle_response(response).await
+    }
+
+    /// Change part of the server configuration
+    ///
+    /// # Arguments
+    /// * `patch` - The settings to change, nested as in the configuration,
+    ///   e.g. `{"ServiceSettings": {"EnableCommands": true}}`
+    ///
+    /// # Returns
+    /// A Result containing the full updated configuration or an Error
+    ///
+    /// # API Endpoint
+    /// PUT /config/patch
+    pub async fn patch_config(&self, patch: &serde_json::Value) -> Result<serde_json::Value> {
+        let response = self.put("/config/patch", patch).await?;
+        self.handle_response(response).await
+    }
+
+    /// Get the standard server analytics
+    ///
+    /// # Returns
+    /// A Result containing the analytics rows or an Error
+    ///
+    /// # API Endpoint
+    /// GET /analytics/old?name=standard
+    pub async fn get_analytics(&self) -> Result<Vec<AnalyticsRow>> {
+        let response = self.get("/analytics/old?name=standard").await?;
+        self.handle_response(response).await
+    }
+
+    /// Get a page of the server log, oldest first
+    ///
+    /// # Arguments
+    /// * `page` - The page number (0-indexed)
+    /// * `per_page` - Number of log lines per page
+    ///
+    /// # Returns
+    /// A Result containing the log lines or an Error
+    ///
+    /// # API Endpoint
+    /// GET /logs
+    pub async fn get_logs(&self, page: u32, per_page: u32) -> Result<Vec<String>> {
+        let endpoint = format!("/logs?page={page}&logs_per_page={per_page}");
+        let response = self.get(&endpoint).await?;
+        self.handle_response(response).await
+    }
+}
+
+/// Build system stats from the rows of the standard analytics
+pub fn system_stats_from_analytics(rows: Vec<AnalyticsRow>) -> SystemStats {
+    let mut stats = SystemStats::default();
+    for row in rows {
+        let value = row.value as i64;
+        match row.name.as_str() {
+            "unique_user_count" => stats.user_count = value,
+            "inactive_user_count" => stats.inactive_user_count = value,
+            "team_count" => stats.team_count = value,
+            "channel_open_count" => stats.public_channel_count = value,
+            "channel_private_count" => stats.private_channel_count = value,
+            "post_count" => stats.post_count = value,
+            "daily_active_users" => stats.daily_active_users = value,
+            "monthly_active_users" => stats.monthly_active_users = value,
+            "total_websocket_connections" => stats.websocket_connections = value,
+            _ => {
+                stats.other.insert(row.name, value);
+            }
+        }
+    }
+    stats
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_system_stats_from_analytics() {
+        let rows: Vec<AnalyticsRow> = serde_json::from_str(
+            r#"[
+                {"name": "channel_open_count", "value": 12},
+                {"name": "channel_private_count", "value": 3},
+                {"name": "post_count", "value": 4500},
+                {"name": "unique_user_count", "value": 42},
+                {"name": "team_count", "value": 2},
+                {"name": "total_websocket_connections", "value": 17},
+                {"name": "total_master_db_connections", "value": 10}
+            ]"#,
+        )
+        .unwrap();
+
+        let stats = system_stats_from_analytics(rows);
+        assert_eq!(stats.user_count, 42);
+        assert_eq!(stats.public_channel_count, 12);
+        assert_eq!(stats.private_channel_count, 3);
+        assert_eq!(stats.post_count, 4500);
+        assert_eq!(stats.websocket_connections, 17);
+        assert_eq!(stats.other.get("total_master_db_connections"), Some(&10));
+    }
+}
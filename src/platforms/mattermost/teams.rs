@@ -1,8 +1,8 @@
 //! Team management operations for Mattermost
 
 use super::client::MattermostClient;
-use super::types::MattermostTeam;
-use crate::error::Result;
+use super::types::{MattermostTeam, MattermostTeamStats};
+use crate::error::{Error, ErrorCode, Result};
 
 impl MattermostClient {
     /// Get all teams the current user belongs to
@@ -48,6 +48,136 @@ impl MattermostClient {
         let response = self.get(&endpoint).await?;
         self.handle_response(response).await
     }
+
+    /// Get member counts for a team
+    ///
+    /// # Arguments
+    /// * `team_id` - The unique identifier of the team
+    ///
+    /// # Returns
+    /// A Result containing the team's MattermostTeamStats
+    ///
+    /// # API Endpoint
+    /// GET /teams/{team_id}/stats
+    pub async fn get_team_stats(&self, team_id: &str) -> Result<MattermostTeamStats> {
+        let endpoint = format!("/teams/{team_id}/stats");
+        let response = self.get(&endpoint).await?;
+        self.handle_response(response).await
+    }
+
+    /// Search teams by name or display name
+    ///
+    /// Returns the teams the current user is allowed to see: every team for
+    /// system admins, open teams and the user's own teams otherwise.
+    ///
+    /// # Arguments
+    /// * `term` - Text to match against the team name or display name
+    ///
+    /// # Returns
+    /// A Result containing the matching MattermostTeam objects
+    ///
+    /// # API Endpoint
+    /// POST /teams/search
+    pub async fn search_teams(&self, term: &str) -> Result<Vec<MattermostTeam>> {
+        let body = serde_json::json!({ "term": term });
+        let response = self.post("/teams/search", &body).await?;
+        self.handle_response(response).await
+    }
+
+    /// Create a new team
+    ///
+    /// # Arguments
+    /// * `body` - The team to create; must include `name`, `display_name` and `type`
+    ///
+    /// # Returns
+    /// A Result containing the created MattermostTeam object
+    ///
+    /// # API Endpoint
+    /// POST /teams
+    pub async fn create_team(&self, body: &serde_json::Value) -> Result<MattermostTeam> {
+        let response = self.post("/teams", body).await?;
+        self.handle_response(response).await
+    }
+
+    /// Partially update a team
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team to update
+    /// * `patch` - The fields to change
+    ///
+    /// # Returns
+    /// A Result containing the updated MattermostTeam object
+    ///
+    /// # API Endpoint
+    /// PUT /teams/{team_id}/patch
+    pub async fn patch_team(
+        &self,
+        team_id: &str,
+        patch: &serde_json::Value,
+    ) -> Result<MattermostTeam> {
+        let endpoint = format!("/teams/{team_id}/patch");
+        let response = self.put(&endpoint, patch).await?;
+        self.handle_response(response).await
+    }
+
+    /// Change whether a team is open or invite-only
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team
+    /// * `privacy` - "O" for open or "I" for invite-only
+    ///
+    /// # Returns
+    /// A Result containing the updated MattermostTeam object
+    ///
+    /// # API Endpoint
+    /// PUT /teams/{team_id}/privacy
+    pub async fn update_team_privacy(
+        &self,
+        team_id: &str,
+        privacy: &str,
+    ) -> Result<MattermostTeam> {
+        let endpoint = format!("/teams/{team_id}/privacy");
+        let body = serde_json::json!({ "privacy": privacy });
+        let response = self.put(&endpoint, &body).await?;
+        self.handle_response(response).await
+    }
+
+    /// Set a team's icon
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team
+    /// * `image_data` - The image contents (png, jpeg, gif or bmp)
+    ///
+    /// # API Endpoint
+    /// POST /teams/{team_id}/image
+    pub async fn set_team_image(&self, team_id: &str, image_data: Vec<u8>) -> Result<()> {
+        if image_data.is_empty() {
+            return Err(Error::invalid_argument("Team icon data is empty"));
+        }
+
+        let endpoint = format!("/teams/{team_id}/image");
+        let url = self.api_url(&endpoint);
+        let response = self
+            .send("POST", &endpoint, || {
+                let form = reqwest::multipart::Form::new().part(
+                    "image",
+                    reqwest::multipart::Part::bytes(image_data.clone()).file_name("image"),
+                );
+                self.http_client.post(&url).multipart(form)
+            })
+            .await?;
+
+        let status = response.status();
+        if status.is_success() {
+            Ok(())
+        } else {
+            Err(Error::new(
+                ErrorCode::NetworkError,
+                format!("Failed to set team icon: {status}"),
+            )
+            .with_http_status(status.as_u16()))
+        }
+    }
 }
 
 #[cfg(test)]
@@ -62,5 +192,13 @@ mod tests {
             format!("/teams/name/{}", "engineering"),
             "/teams/name/engineering"
         );
+        assert_eq!(
+            format!("/teams/{}/patch", "team123"),
+            "/teams/team123/patch"
+        );
+        assert_eq!(
+            format!("/teams/{}/image", "team123"),
+            "/teams/team123/image"
+        );
     }
 }
@@ -0,0 +1,240 @@
+//! Custom TLS settings for the REST and WebSocket connections
+//!
+//! One rustls configuration serves both: reqwest takes it preconfigured and
+//! tokio-tungstenite through its connector, so the trusted roots, client
+//! certificate and verification mode always agree.
+
+use std::sync::Arc;
+
+use rustls::client::danger::{HandshakeSignatureValid, ServerCertVerified, ServerCertVerifier};
+use rustls::crypto::{verify_tls12_signature, verify_tls13_signature, CryptoProvider};
+use rustls::pki_types::{CertificateDer, PrivateKeyDer, ServerName, UnixTime};
+use rustls::{ClientConfig, DigitallySignedStruct, RootCertStore, SignatureScheme};
+
+use crate::error::{Error, ErrorCode, Result};
+use crate::platforms::platform_trait::PlatformConfig;
+
+/// TLS settings beyond the defaults (public web roots, no client certificate)
+#[derive(Clone)]
+pub struct TlsConfig {
+    /// Extra trusted CA certificates, in PEM
+    ca_certs: Vec<CertificateDer<'static>>,
+    /// Client certificate chain and its private key, for mutual TLS
+    client_auth: Option<(Vec<CertificateDer<'static>>, Arc<PrivateKeyDer<'static>>)>,
+    /// Accept any server certificate; only for test servers
+    insecure_skip_verify: bool,
+}
+
+impl std::fmt::Debug for TlsConfig {
+    fn fmt(&self, f: &mut std::fmt::Formatter<'_>) -> std::fmt::Result {
+        // The private key stays out of logs
+        f.debug_struct("TlsConfig")
+            .field("ca_certs", &self.ca_certs.len())
+            .field("client_auth", &self.client_auth.is_some())
+            .field("insecure_skip_verify", &self.insecure_skip_verify)
+            .finish()
+    }
+}
+
+impl TlsConfig {
+    /// Read the TLS settings of a platform configuration
+    ///
+    /// `ca_cert`, `client_cert` and `client_key` each hold either PEM text or
+    /// the path of a PEM file.
+    ///
+    /// # Returns
+    /// None when the configuration keeps the defaults, or an InvalidArgument
+    /// error for unreadable or malformed certificates
+    pub fn from_platform_config(config: &PlatformConfig) -> Result<Option<Self>> {
+        let ca_cert = config.ca_cert.as_deref().filter(|s| !s.is_empty());
+        let client_cert = config.client_cert.as_deref().filter(|s| !s.is_empty());
+        let client_key = config.client_key.as_deref().filter(|s| !s.is_empty());
+        if ca_cert.is_none() && client_cert.is_none() && !config.insecure_skip_verify {
+            if client_key.is_some() {
+                return Err(invalid("client_key given without client_cert"));
+            }
+            return Ok(None);
+        }
+
+        let ca_certs = match ca_cert {
+            Some(ca_cert) => {
+                let certs = parse_certs(&load_pem(ca_cert, "CA certificate")?)?;
+                if certs.is_empty() {
+                    return Err(invalid("no certificate found in ca_cert"));
+                }
+                certs
+            }
+            None => Vec::new(),
+        };
+
+        let client_auth = match (client_cert, client_key) {
+            (Some(cert), Some(key)) => {
+                let chain = parse_certs(&load_pem(cert, "client certificate")?)?;
+                if chain.is_empty() {
+                    return Err(invalid("no certificate found in client_cert"));
+                }
+                let key = rustls_pemfile::private_key(&mut load_pem(key, "client key")?.as_slice())
+                    .map_err(|e| invalid(format!("reading client_key: {e}")))?
+                    .ok_or_else(|| invalid("no private key found in client_key"))?;
+                Some((chain, Arc::new(key)))
+            }
+            (None, None) => None,
+            _ => return Err(invalid("client_cert and client_key must be given together")),
+        };
+
+        Ok(Some(Self {
+            ca_certs,
+            client_auth,
+            insecure_skip_verify: config.insecure_skip_verify,
+        }))
+    }
+
+    /// Check whether server certificates go unverified
+    pub fn insecure_skip_verify(&self) -> bool {
+        self.insecure_skip_verify
+    }
+
+    /// Build the rustls configuration for both connections
+    pub(crate) fn client_config(&self) -> Result<ClientConfig> {
+        let provider = Arc::new(rustls::crypto::ring::default_provider());
+        let builder = ClientConfig::builder_with_provider(Arc::clone(&provider))
+            .with_safe_default_protocol_versions()
+            .map_err(|e| invalid(e.to_string()))?;
+
+        let builder = if self.insecure_skip_verify {
+            builder
+                .dangerous()
+                .with_custom_certificate_verifier(Arc::new(NoVerification(provider)))
+        } else {
+            let mut roots = RootCertStore {
+                roots: webpki_roots::TLS_SERVER_ROOTS.to_vec(),
+            };
+            for cert in &self.ca_certs {
+                roots
+                    .add(cert.clone())
+                    .map_err(|e| invalid(format!("ca_cert: {e}")))?;
+            }
+            builder.with_root_certificates(roots)
+        };
+
+        match &self.client_auth {
+            Some((chain, key)) => builder
+                .with_client_auth_cert(chain.clone(), key.clone_key())
+                .map_err(|e| invalid(format!("client certificate: {e}"))),
+            None => Ok(builder.with_no_client_auth()),
+        }
+    }
+
+    /// Build the WebSocket connector
+    pub(crate) fn connector(&self) -> Result<tokio_tungstenite::Connector> {
+        Ok(tokio_tungstenite::Connector::Rustls(Arc::new(
+            self.client_config()?,
+        )))
+    }
+}
+
+fn invalid(message: impl std::fmt::Display) -> Error {
+    Error::new(
+        ErrorCode::InvalidArgument,
+        format!("Invalid TLS configuration: {message}"),
+    )
+}
+
+/// Read PEM text given inline or as a file path
+fn load_pem(value: &str, what: &str) -> Result<Vec<u8>> {
+    if value.trim_start().starts_with("-----BEGIN") {
+        return Ok(value.as_bytes().to_vec());
+    }
+    std::fs::read(value).map_err(|e| invalid(format!("reading {what} {value}: {e}")))
+}
+
+fn parse_certs(pem: &[u8]) -> Result<Vec<CertificateDer<'static>>> {
+    rustls_pemfile::certs(&mut &pem[..])
+        .collect::<std::result::Result<Vec<_>, _>>()
+        .map_err(|e| invalid(format!("reading certificate: {e}")))
+}
+
+/// Accepts every server certificate, still checking handshake signatures
+/// so the connection is at least consistent with the presented key
+#[derive(Debug)]
+struct NoVerification(Arc<CryptoProvider>);
+
+impl ServerCertVerifier for NoVerification {
+    fn verify_server_cert(
+        &self,
+        _end_entity: &CertificateDer<'_>,
+        _intermediates: &[CertificateDer<'_>],
+        _server_name: &ServerName<'_>,
+        _ocsp_response: &[u8],
+        _now: UnixTime,
+    ) -> std::result::Result<ServerCertVerified, rustls::Error> {
+        Ok(ServerCertVerified::assertion())
+    }
+
+    fn verify_tls12_signature(
+        &self,
+        message: &[u8],
+        cert: &CertificateDer<'_>,
+        dss: &DigitallySignedStruct,
+    ) -> std::result::Result<HandshakeSignatureValid, rustls::Error> {
+        verify_tls12_signature(
+            message,
+            cert,
+            dss,
+            &self.0.signature_verification_algorithms,
+        )
+    }
+
+    fn verify_tls13_signature(
+        &self,
+        message: &[u8],
+        cert: &CertificateDer<'_>,
+        dss: &DigitallySignedStruct,
+    ) -> std::result::Result<HandshakeSignatureValid, rustls::Error> {
+        verify_tls13_signature(
+            message,
+            cert,
+            dss,
+            &self.0.signature_verification_algorithms,
+        )
+    }
+
+    fn supported_verify_schemes(&self) -> Vec<SignatureScheme> {
+        self.0.signature_verification_algorithms.supported_schemes()
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_default_config_has_no_tls_overrides() {
+        let config = PlatformConfig::new("https://mm.example.com");
+        assert!(TlsConfig::from_platform_config(&config).unwrap().is_none());
+    }
+
+    #[test]
+    fn test_insecure_mode_builds() {
+        let mut config = PlatformConfig::new("https://mm.example.com");
+        config.insecure_skip_verify = true;
+        let tls = TlsConfig::from_platform_config(&config).unwrap().unwrap();
+        assert!(tls.insecure_skip_verify());
+        assert!(tls.client_config().is_ok());
+    }
+
+    #[test]
+    fn test_rejects_bad_certificates() {
+        let mut config = PlatformConfig::new("https://mm.example.com");
+        config.ca_cert = Some("/nonexistent/ca.pem".to_string());
+        let err = TlsConfig::from_platform_config(&config).unwrap_err();
+        assert_eq!(err.code, ErrorCode::InvalidArgument);
+
+        config.ca_cert = Some("-----BEGIN NOTHING-----\n-----END NOTHING-----\n".to_string());
+        assert!(TlsConfig::from_platform_config(&config).is_err());
+
+        let mut config = PlatformConfig::new("https://mm.example.com");
+        config.client_key = Some("/tmp/key.pem".to_string());
+        assert!(TlsConfig::from_platform_config(&config).is_err());
+    }
+}
@@ -76,9 +76,14 @@ pub struct MattermostUser {
     pub props: HashMap<String, serde_json::Value>,
     #[serde(default)]
     pub is_bot: bool,
+    #[serde(default)]
+    pub notify_props: HashMap<String, String>,
     pub create_at: i64,
     pub update_at: i64,
     pub delete_at: i64,
+    /// Fields the server sent that aren't modeled above
+    #[serde(flatten)]
+    pub extra: HashMap<String, serde_json::Value>,
 }
 
 /// Mattermost Channel object from API
@@ -103,6 +108,9 @@ pub struct MattermostChannel {
     pub total_msg_count: i64,
     #[serde(default)]
     pub creator_id: String,
+    /// Fields the server sent that aren't modeled above
+    #[serde(flatten)]
+    pub extra: HashMap<String, serde_json::Value>,
 }
 
 /// Mattermost Post (message) object from API
@@ -113,6 +121,8 @@ pub struct MattermostPost {
     pub update_at: i64,
     pub delete_at: i64,
     pub edit_at: i64,
+    #[serde(default)]
+    pub is_pinned: bool,
     pub user_id: String,
     pub channel_id: String,
     #[serde(default)]
@@ -135,21 +145,124 @@ pub struct MattermostPost {
     pub pending_post_id: String,
     #[serde(default)]
     pub metadata: PostMetadata,
+    /// Thread fields, set on root posts when collapsed reply threads are enabled
+    #[serde(default)]
+    pub reply_count: i64,
+    #[serde(default)]
+    pub last_reply_at: i64,
+    /// Users who replied, as user objects (null on posts without replies)
+    #[serde(default)]
+    pub participants: Option<Vec<serde_json::Value>>,
+    /// Fields the server sent that aren't modeled above
+    #[serde(flatten)]
+    pub extra: HashMap<String, serde_json::Value>,
 }
 
 /// Metadata for a Mattermost Post
 #[derive(Debug, Clone, Default, Serialize, Deserialize)]
 pub struct PostMetadata {
     #[serde(default)]
-    pub embeds: Vec<serde_json::Value>,
+    pub embeds: Vec<MattermostPostEmbed>,
     #[serde(default)]
     pub emojis: Vec<serde_json::Value>,
     #[serde(default)]
     pub files: Vec<FileInfo>,
     #[serde(default)]
-    pub images: HashMap<String, serde_json::Value>,
+    pub images: HashMap<String, MattermostPostImage>,
     #[serde(default)]
-    pub reactions: Vec<serde_json::Value>,
+    pub reactions: Vec<Reaction>,
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub priority: Option<MattermostPostPriority>,
+    #[serde(default)]
+    pub acknowledgements: Vec<MattermostPostAcknowledgement>,
+    /// Fields the server sent that aren't modeled above
+    #[serde(flatten)]
+    pub extra: HashMap<String, serde_json::Value>,
+}
+
+/// Link preview, image or permalink embedded in a Mattermost post
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct MattermostPostEmbed {
+    /// "image", "message_attachment", "opengraph" or "permalink"
+    #[serde(rename = "type")]
+    pub embed_type: String,
+    #[serde(default)]
+    pub url: String,
+    #[serde(default)]
+    pub data: Option<serde_json::Value>,
+}
+
+/// Dimensions of an image linked from a Mattermost post
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct MattermostPostImage {
+    #[serde(default)]
+    pub width: u32,
+    #[serde(default)]
+    pub height: u32,
+    #[serde(default)]
+    pub format: String,
+    #[serde(default)]
+    pub frame_count: u32,
+}
+
+/// OpenGraph metadata of a web page, as scraped by the Mattermost server
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct MattermostOpenGraph {
+    #[serde(default, rename = "type")]
+    pub og_type: String,
+    #[serde(default)]
+    pub url: String,
+    #[serde(default)]
+    pub title: String,
+    #[serde(default)]
+    pub description: String,
+    #[serde(default)]
+    pub site_name: String,
+    #[serde(default)]
+    pub images: Vec<MattermostOpenGraphMedia>,
+    #[serde(default)]
+    pub videos: Vec<MattermostOpenGraphMedia>,
+}
+
+/// Image or video object of Mattermost OpenGraph metadata
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct MattermostOpenGraphMedia {
+    #[serde(default)]
+    pub url: String,
+    #[serde(default)]
+    pub secure_url: String,
+    #[serde(default, rename = "type")]
+    pub mime_type: String,
+    #[serde(default)]
+    pub width: u32,
+    #[serde(default)]
+    pub height: u32,
+}
+
+/// Priority settings of a Mattermost post
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct MattermostPostPriority {
+    /// "", "important" or "urgent"
+    #[serde(default)]
+    pub priority: String,
+    #[serde(default)]
+    pub requested_ack: bool,
+    #[serde(default)]
+    pub persistent_notifications: bool,
+}
+
+/// A user's acknowledgement of a Mattermost post
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct MattermostPostAcknowledgement {
+    pub user_id: String,
+    pub post_id: String,
+    pub acknowledged_at: i64,
+}
+
+/// Metadata sent along with a new post
+#[derive(Debug, Clone, Serialize)]
+pub struct CreatePostMetadata {
+    pub priority: MattermostPostPriority,
 }
 
 /// Mattermost File information
@@ -171,6 +284,9 @@ pub struct FileInfo {
     pub height: i32,
     #[serde(default)]
     pub has_preview_image: bool,
+    /// Tiny JPEG preview of an image, base64-encoded
+    #[serde(default)]
+    pub mini_preview: Option<String>,
 }
 
 /// Mattermost Reaction object from API
@@ -213,6 +329,283 @@ pub struct MattermostTeam {
     pub invite_id: String,
     #[serde(default)]
     pub allow_open_invite: bool,
+    /// Whether membership is managed by linked groups
+    #[serde(default)]
+    pub group_constrained: Option<bool>,
+}
+
+/// Mattermost team statistics from API
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct MattermostTeamStats {
+    pub team_id: String,
+    #[serde(default)]
+    pub total_member_count: i64,
+    #[serde(default)]
+    pub active_member_count: i64,
+}
+
+/// Mattermost team membership object from API
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct TeamMember {
+    pub team_id: String,
+    pub user_id: String,
+    #[serde(default)]
+    pub roles: String,
+    #[serde(default)]
+    pub delete_at: i64,
+}
+
+/// Mattermost role object from API
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct MattermostRole {
+    pub id: String,
+    pub name: String,
+    #[serde(default)]
+    pub display_name: String,
+    #[serde(default)]
+    pub description: String,
+    #[serde(default)]
+    pub permissions: Vec<String>,
+    #[serde(default)]
+    pub scheme_managed: bool,
+    #[serde(default)]
+    pub built_in: bool,
+}
+
+/// Mattermost sidebar category object from API
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct MattermostSidebarCategory {
+    #[serde(default)]
+    pub id: String,
+    #[serde(default)]
+    pub user_id: String,
+    #[serde(default)]
+    pub team_id: String,
+    #[serde(default)]
+    pub sort_order: i64,
+    #[serde(default)]
+    pub sorting: String,
+    #[serde(rename = "type")]
+    pub category_type: String, // "custom", "favorites", "channels", "direct_messages"
+    pub display_name: String,
+    #[serde(default)]
+    pub muted: bool,
+    #[serde(default)]
+    pub collapsed: bool,
+    #[serde(default)]
+    pub channel_ids: Vec<String>,
+}
+
+/// Sidebar categories in display order, as returned by the API
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct OrderedSidebarCategories {
+    pub categories: Vec<MattermostSidebarCategory>,
+    pub order: Vec<String>,
+}
+
+/// Mattermost channel bookmark object from API
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct MattermostChannelBookmark {
+    pub id: String,
+    #[serde(default)]
+    pub create_at: i64,
+    #[serde(default)]
+    pub update_at: i64,
+    #[serde(default)]
+    pub delete_at: i64,
+    pub channel_id: String,
+    #[serde(default)]
+    pub owner_id: String,
+    #[serde(default)]
+    pub file_id: String,
+    pub display_name: String,
+    #[serde(default)]
+    pub sort_order: i64,
+    #[serde(default)]
+    pub link_url: String,
+    #[serde(default)]
+    pub image_url: String,
+    #[serde(default)]
+    pub emoji: String,
+    #[serde(rename = "type")]
+    pub bookmark_type: String, // "link" or "file"
+    #[serde(default)]
+    pub original_id: String,
+    #[serde(default)]
+    pub parent_id: String,
+    #[serde(default)]
+    pub file: Option<FileInfo>,
+}
+
+/// Mattermost user group object from API
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct MattermostGroup {
+    pub id: String,
+    pub name: String,
+    pub display_name: String,
+    #[serde(default)]
+    pub description: String,
+    #[serde(default)]
+    pub source: String, // "custom" or "ldap"
+    #[serde(default)]
+    pub remote_id: Option<String>,
+    #[serde(default)]
+    pub create_at: i64,
+    #[serde(default)]
+    pub update_at: i64,
+    #[serde(default)]
+    pub delete_at: i64,
+    #[serde(default)]
+    pub allow_reference: bool,
+    #[serde(default)]
+    pub member_count: Option<i64>,
+}
+
+/// Groups linked to a team, as returned by the API
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct TeamGroups {
+    #[serde(default)]
+    pub groups: Vec<MattermostGroup>,
+    #[serde(default)]
+    pub total_group_count: i64,
+}
+
+/// Mattermost session object from API
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct MattermostSession {
+    pub id: String,
+    #[serde(default)]
+    pub create_at: i64,
+    #[serde(default)]
+    pub expires_at: i64,
+    #[serde(default)]
+    pub last_activity_at: i64,
+    pub user_id: String,
+    #[serde(default)]
+    pub device_id: String,
+    #[serde(default)]
+    pub roles: String,
+    #[serde(default)]
+    pub is_oauth: bool,
+    #[serde(default)]
+    pub props: HashMap<String, String>,
+}
+
+/// Request to revoke a single session
+#[derive(Debug, Clone, Serialize)]
+pub struct RevokeSessionRequest {
+    pub session_id: String,
+}
+
+/// Mattermost slash command response from API
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct MattermostCommandResponse {
+    /// "in_channel" or "ephemeral" (empty means ephemeral)
+    #[serde(default)]
+    pub response_type: String,
+    #[serde(default)]
+    pub text: String,
+    #[serde(default)]
+    pub username: String,
+    #[serde(default)]
+    pub channel_id: String,
+    #[serde(default)]
+    pub icon_url: String,
+    #[serde(default)]
+    pub goto_location: String,
+    #[serde(default)]
+    pub trigger_id: String,
+    #[serde(default)]
+    pub props: HashMap<String, serde_json::Value>,
+    #[serde(default)]
+    pub attachments: Vec<serde_json::Value>,
+    #[serde(default)]
+    pub extra_responses: Vec<MattermostCommandResponse>,
+}
+
+/// Mattermost slash command object from API
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct MattermostCommand {
+    #[serde(default)]
+    pub id: String,
+    #[serde(default)]
+    pub team_id: String,
+    pub trigger: String,
+    #[serde(default)]
+    pub auto_complete: bool,
+    #[serde(default)]
+    pub auto_complete_desc: String,
+    #[serde(default)]
+    pub auto_complete_hint: String,
+    #[serde(default)]
+    pub display_name: String,
+    #[serde(default)]
+    pub description: String,
+    #[serde(default)]
+    pub plugin_id: String,
+}
+
+/// Request to execute a slash command
+#[derive(Debug, Clone, Serialize)]
+pub struct ExecuteCommandRequest {
+    pub channel_id: String,
+    pub command: String,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub team_id: Option<String>,
+}
+
+/// Mattermost incoming webhook object from API
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct MattermostIncomingWebhook {
+    pub id: String,
+    #[serde(default)]
+    pub create_at: i64,
+    #[serde(default)]
+    pub update_at: i64,
+    #[serde(default)]
+    pub delete_at: i64,
+    pub channel_id: String,
+    #[serde(default)]
+    pub team_id: String,
+    #[serde(default)]
+    pub user_id: String,
+    #[serde(default)]
+    pub display_name: String,
+    #[serde(default)]
+    pub description: String,
+    #[serde(default)]
+    pub username: String,
+    #[serde(default)]
+    pub icon_url: String,
+    #[serde(default)]
+    pub channel_locked: bool,
+}
+
+/// Mattermost bot object from API
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct MattermostBot {
+    pub user_id: String,
+    #[serde(default)]
+    pub create_at: i64,
+    #[serde(default)]
+    pub update_at: i64,
+    #[serde(default)]
+    pub delete_at: i64,
+    pub username: String,
+    #[serde(default)]
+    pub display_name: String,
+    #[serde(default)]
+    pub description: String,
+    #[serde(default)]
+    pub owner_id: String,
+}
+
+/// Request to open an interactive dialog
+#[derive(Debug, Clone, Serialize)]
+pub struct OpenDialogRequest {
+    pub trigger_id: String,
+    pub url: String,
+    pub dialog: crate::types::InteractiveDialog,
 }
 
 /// Login request payload
@@ -251,6 +644,8 @@ pub struct CreatePostRequest {
     pub file_ids: Option<Vec<String>>,
     #[serde(skip_serializing_if = "Option::is_none")]
     pub props: Option<HashMap<String, serde_json::Value>>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub metadata: Option<CreatePostMetadata>,
 }
 
 /// Response containing a list of posts
@@ -273,6 +668,8 @@ pub struct ChannelMember {
     pub last_viewed_at: i64,
     pub msg_count: i64,
     pub mention_count: i64,
+    #[serde(default)]
+    pub urgent_mention_count: i64,
     pub notify_props: HashMap<String, String>,
     pub last_update_at: i64,
 }
@@ -316,6 +713,9 @@ pub struct ChannelUnreadInfo {
     pub msg_count: i64,
     /// Number of unread mentions
     pub mention_count: i64,
+    /// Number of unread mentions in urgent posts
+    #[serde(default)]
+    pub urgent_mention_count: i64,
     /// Timestamp when the channel was last viewed
     pub last_viewed_at: i64,
 }
@@ -382,11 +782,17 @@ pub struct WebSocketAuthData {
     pub token: String,
 }
 
-/// WebSocket authentication response
+/// WebSocket reply to the authentication challenge or another action
 #[derive(Debug, Clone, Deserialize)]
 pub struct WebSocketAuthResponse {
     pub status: String,
     pub seq_reply: i64,
+    /// Result of the action, e.g. the statuses requested by get_statuses
+    #[serde(default)]
+    pub data: Option<serde_json::Value>,
+    /// Error details when the status is not OK
+    #[serde(default)]
+    pub error: Option<serde_json::Value>,
 }
 
 /// Status object for user presence
@@ -407,6 +813,7 @@ impl CreatePostRequest {
             root_id: None,
             file_ids: None,
             props: None,
+            metadata: None,
         }
     }
 
@@ -427,6 +834,12 @@ impl CreatePostRequest {
         self.props = Some(props);
         self
     }
+
+    /// Set the post's priority and acknowledgement request
+    pub fn with_priority(mut self, priority: MattermostPostPriority) -> Self {
+        self.metadata = Some(CreatePostMetadata { priority });
+        self
+    }
 }
 
 /// User status response from Mattermost API
@@ -438,6 +851,9 @@ pub struct MattermostStatus {
     pub manual: bool,
     #[serde(default)]
     pub last_activity_at: i64,
+    /// Unix time in seconds when "do not disturb" ends, 0 if it doesn't expire
+    #[serde(default)]
+    pub dnd_end_time: i64,
 }
 
 /// Custom status for a user
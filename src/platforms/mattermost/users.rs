@@ -1,4 +1,6 @@
-use crate::error::Result;
+use std::collections::HashMap;
+
+use crate::error::{Error, ErrorCode, Result};
 
 use super::client::MattermostClient;
 use super::types::MattermostUser;
@@ -63,6 +65,103 @@ impl MattermostClient {
         let response = self.post("/users/ids", &user_ids).await?;
         self.handle_response(response).await
     }
+
+    /// Replace a user's notification properties
+    ///
+    /// Mattermost replaces the whole `notify_props` map, so callers should
+    /// merge their changes into the user's current props first.
+    ///
+    /// # Arguments
+    /// * `user_id` - The ID of the user
+    /// * `notify_props` - The complete notification properties map
+    ///
+    /// # Returns
+    /// A Result containing the updated user or an Error
+    ///
+    /// # API Endpoint
+    /// PUT /users/{user_id}/patch
+    pub async fn patch_user_notify_props(
+        &self,
+        user_id: &str,
+        notify_props: &HashMap<String, String>,
+    ) -> Result<MattermostUser> {
+        let endpoint = format!("/users/{user_id}/patch");
+        let body = serde_json::json!({ "notify_props": notify_props });
+        let response = self.put(&endpoint, &body).await?;
+        self.handle_response(response).await
+    }
+
+    /// Get a user's profile image
+    ///
+    /// # Arguments
+    /// * `user_id` - The ID of the user
+    ///
+    /// # Returns
+    /// A Result containing the raw image bytes or an Error
+    ///
+    /// # API Endpoint
+    /// GET /users/{user_id}/image
+    pub async fn get_user_image(&self, user_id: &str) -> Result<Vec<u8>> {
+        let endpoint = format!("/users/{user_id}/image");
+        let response = self.get(&endpoint).await?;
+
+        let status = response.status();
+        if !status.is_success() {
+            let error_text = response
+                .text()
+                .await
+                .unwrap_or_else(|_| "Unknown error".to_string());
+            return Err(Error::new(
+                ErrorCode::NetworkError,
+                format!("Failed to download profile image: {error_text}"),
+            )
+            .with_http_status(status.as_u16()));
+        }
+
+        response.bytes().await.map(|b| b.to_vec()).map_err(|e| {
+            Error::new(
+                ErrorCode::NetworkError,
+                format!("Failed to read profile image data: {e}"),
+            )
+        })
+    }
+
+    /// Set a user's profile image
+    ///
+    /// # Arguments
+    /// * `user_id` - The ID of the user
+    /// * `image_data` - The image contents (png, jpeg, gif or bmp)
+    ///
+    /// # API Endpoint
+    /// POST /users/{user_id}/image
+    pub async fn set_user_image(&self, user_id: &str, image_data: Vec<u8>) -> Result<()> {
+        if image_data.is_empty() {
+            return Err(Error::invalid_argument("Profile image data is empty"));
+        }
+
+        let endpoint = format!("/users/{user_id}/image");
+        let url = self.api_url(&endpoint);
+        let response = self
+            .send("POST", &endpoint, || {
+                let form = reqwest::multipart::Form::new().part(
+                    "image",
+                    reqwest::multipart::Part::bytes(image_data.clone()).file_name("image"),
+                );
+                self.http_client.post(&url).multipart(form)
+            })
+            .await?;
+
+        let status = response.status();
+        if status.is_success() {
+            Ok(())
+        } else {
+            Err(Error::new(
+                ErrorCode::NetworkError,
+                format!("Failed to set profile image: {status}"),
+            )
+            .with_http_status(status.as_u16()))
+        }
+    }
 }
 
 #[cfg(test)]
@@ -0,0 +1,142 @@
+//! Incoming webhook operations for Mattermost
+
+use super::client::MattermostClient;
+use super::types::MattermostIncomingWebhook;
+use crate::error::{Error, ErrorCode, Result};
+use crate::types::{NewIncomingWebhook, WebhookPayload};
+
+impl MattermostClient {
+    /// Create an incoming webhook
+    ///
+    /// # Arguments
+    /// * `hook` - The channel and display settings of the webhook
+    ///
+    /// # Returns
+    /// A Result containing the created webhook or an Error
+    ///
+    /// # Notes
+    /// Requires `manage_webhooks` permission for the channel's team
+    pub async fn create_incoming_webhook(
+        &self,
+        hook: &NewIncomingWebhook,
+    ) -> Result<MattermostIncomingWebhook> {
+        let response = self.post("/hooks/incoming", hook).await?;
+        self.handle_response(response).await
+    }
+
+    /// Get a page of incoming webhooks
+    ///
+    /// # Arguments
+    /// * `team_id` - Only list webhooks of this team (None lists all the user can manage)
+    /// * `page` - The page number to retrieve (0-indexed)
+    /// * `per_page` - Number of webhooks per page
+    ///
+    /// # Returns
+    /// A Result containing the webhooks on the page or an Error
+    pub async fn get_incoming_webhooks(
+        &self,
+        team_id: Option<&str>,
+        page: u32,
+        per_page: u32,
+    ) -> Result<Vec<MattermostIncomingWebhook>> {
+        let mut endpoint = format!("/hooks/incoming?page={page}&per_page={per_page}");
+        if let Some(team_id) = team_id {
+            endpoint.push_str(&format!("&team_id={team_id}"));
+        }
+        let response = self.get(&endpoint).await?;
+        self.handle_response(response).await
+    }
+
+    /// Delete an incoming webhook
+    ///
+    /// # Arguments
+    /// * `hook_id` - The ID of the webhook to delete
+    ///
+    /// # Returns
+    /// A Result indicating success or failure
+    pub async fn delete_incoming_webhook(&self, hook_id: &str) -> Result<()> {
+        let endpoint = format!("/hooks/incoming/{hook_id}");
+        let response = self.delete(&endpoint).await?;
+        let status = response.status();
+
+        if status.is_success() {
+            Ok(())
+        } else {
+            Err(Error::new(
+                ErrorCode::NetworkError,
+                format!("Failed to delete incoming webhook: {status}"),
+            )
+            .with_http_status(status.as_u16()))
+        }
+    }
+
+    /// Get the URL external systems post to for an incoming webhook
+    pub fn incoming_webhook_url(&self, hook_id: &str) -> String {
+        format!(
+            "{}/hooks/{hook_id}",
+            self.get_base_url().trim_end_matches('/')
+        )
+    }
+}
+
+/// Post a message through an incoming webhook URL
+///
+/// The URL carries its own authorization, so no login or platform instance is
+/// needed.
+///
+/// # Arguments
+/// * `url` - The webhook URL, e.g. "https://mattermost.example.com/hooks/xxx"
+/// * `payload` - The message to post
+///
+/// # Returns
+/// A Result indicating success or failure
+pub async fn send_incoming_webhook(url: &str, payload: &WebhookPayload) -> Result<()> {
+    let response = reqwest::Client::new()
+        .post(url)
+        .json(payload)
+        .send()
+        .await
+        .map_err(|e| {
+            Error::new(
+                ErrorCode::NetworkError,
+                format!("Webhook request failed: {e}"),
+            )
+        })?;
+    let status = response.status();
+
+    if status.is_success() {
+        return Ok(());
+    }
+
+    let code = match status.as_u16() {
+        400 => ErrorCode::InvalidArgument,
+        401 | 403 => ErrorCode::PermissionDenied,
+        404 => ErrorCode::NotFound,
+        429 => ErrorCode::RateLimited,
+        _ => ErrorCode::NetworkError,
+    };
+    let error_text = response.text().await.unwrap_or_default();
+    Err(Error::new(
+        code,
+        format!("Webhook rejected ({status}): {}", error_text.trim()),
+    )
+    .with_http_status(status.as_u16()))
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_incoming_webhook_url() {
+        let client = MattermostClient::new("https://mattermost.example.com").unwrap();
+        assert_eq!(
+            client.incoming_webhook_url("hook123"),
+            "https://mattermost.example.com/hooks/hook123"
+        );
+        assert_eq!(
+            client.api_url("/hooks/incoming/hook123"),
+            "https://mattermost.example.com/api/v4/hooks/incoming/hook123"
+        );
+    }
+}
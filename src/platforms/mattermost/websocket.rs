@@ -1,12 +1,28 @@
-use futures::{stream::SplitSink, SinkExt, StreamExt};
+use futures::{
+    stream::{SplitSink, SplitStream},
+    SinkExt, StreamExt,
+};
+use std::collections::{HashMap, VecDeque};
 use std::sync::Arc;
 use tokio::net::TcpStream;
-use tokio::sync::{mpsc, Mutex};
-use tokio_tungstenite::{connect_async, tungstenite::Message, MaybeTlsStream, WebSocketStream};
+use tokio::sync::{mpsc, oneshot, Mutex};
+use tokio_tungstenite::{
+    client_async_tls_with_config, connect_async_tls_with_config,
+    tungstenite::{client::IntoClientRequest, Message},
+    MaybeTlsStream, WebSocketStream,
+};
 
 use crate::error::{Error, ErrorCode, Result};
+use crate::logging::diag;
 use crate::platforms::platform_trait::PlatformEvent;
+use crate::types::{
+    ConnectionChangeReason, ConnectionHealth, ConnectionState as PublicConnectionState,
+    ConnectionStateChange,
+};
 
+use super::event_schema::validate_event;
+use super::proxy::ProxyConfig;
+use super::tls::TlsConfig;
 use super::types::{
     MattermostChannel, MattermostPost, WebSocketAuthChallenge, WebSocketAuthData,
     WebSocketAuthResponse, WebSocketEvent,
@@ -15,6 +31,13 @@ use super::types::{
 /// Type alias for the WebSocket write half
 type WsWriter = SplitSink<WebSocketStream<MaybeTlsStream<TcpStream>>, Message>;
 
+/// Type alias for the WebSocket read half
+type WsReader = SplitStream<WebSocketStream<MaybeTlsStream<TcpStream>>>;
+
+/// An event with the sequence number of the WebSocket event it came from;
+/// 0 for events generated by the library
+type SequencedEvent = (i64, PlatformEvent);
+
 /// WebSocket connection state
 #[derive(Debug, Clone, Copy, PartialEq, Eq)]
 pub enum ConnectionState {
@@ -34,7 +57,8 @@ pub enum ConnectionState {
 #[derive(Debug, Clone)]
 pub struct WebSocketConfig {
     /// Maximum number of events to queue (default: 1000)
-    /// When full, oldest events are dropped
+    /// When full, new events are dropped and the posts among them are
+    /// fetched again on the next poll, as after a reconnect
     pub max_queue_size: usize,
     /// Ping interval in seconds (default: 30)
     /// Sends ping to keep connection alive
@@ -49,6 +73,24 @@ pub struct WebSocketConfig {
     pub max_reconnect_delay_ms: u64,
     /// Backoff multiplier for exponential backoff (default: 2.0)
     pub reconnect_backoff_multiplier: f64,
+    /// Validate event payloads and report mismatches as SchemaMismatch
+    /// events (default: false)
+    pub strict_schema: bool,
+    /// Report event gaps left by reconnects the server could not resume, so
+    /// missed posts can be fetched (default: true)
+    pub recover_missed_events: bool,
+    /// Limit on opening a connection, through the upgrade handshake
+    /// (default: None = no limit)
+    pub handshake_timeout: Option<std::time::Duration>,
+    /// Silence after which a connection is considered dead and reconnected
+    /// (default: None = rely on the TCP connection failing)
+    pub idle_timeout: Option<std::time::Duration>,
+    /// Proxy to connect through (default: None)
+    pub proxy: Option<ProxyConfig>,
+    /// Custom TLS settings (default: None = public web roots)
+    pub tls: Option<TlsConfig>,
+    /// Headers added to the opening handshake, e.g. User-Agent (default: none)
+    pub headers: reqwest::header::HeaderMap,
 }
 
 impl Default for WebSocketConfig {
@@ -61,10 +103,133 @@ impl Default for WebSocketConfig {
             initial_reconnect_delay_ms: 1000,
             max_reconnect_delay_ms: 60000,
             reconnect_backoff_multiplier: 2.0,
+            strict_schema: false,
+            recover_missed_events: true,
+            handshake_timeout: None,
+            idle_timeout: None,
+            proxy: None,
+            tls: None,
+            headers: reqwest::header::HeaderMap::new(),
+        }
+    }
+}
+
+/// Tracks when events may have been lost across a reconnect
+#[derive(Debug, Default)]
+struct EventGap {
+    /// Local time in milliseconds the last event was received
+    last_event_at: i64,
+    /// Set when a reconnect started a fresh connection instead of resuming
+    /// the previous one; events after this time may have been lost
+    since: Option<i64>,
+}
+
+/// Number of posts whose pin state is remembered; older ones are forgotten
+const MAX_TRACKED_PINS: usize = 4096;
+
+/// Last known pin state of recently seen posts, so pin events are only
+/// emitted when it changes
+#[derive(Debug, Default)]
+struct PinStates {
+    pinned: HashMap<String, bool>,
+    /// Post IDs in the order they were first seen, oldest first
+    order: VecDeque<String>,
+}
+
+impl PinStates {
+    /// Record a post's pin state and return the previous one, if known
+    fn update(&mut self, post_id: &str, is_pinned: bool) -> Option<bool> {
+        if let Some(previous) = self.pinned.insert(post_id.to_string(), is_pinned) {
+            return Some(previous);
+        }
+        if self.order.len() == MAX_TRACKED_PINS {
+            if let Some(oldest) = self.order.pop_front() {
+                self.pinned.remove(&oldest);
+            }
+        }
+        self.order.push_back(post_id.to_string());
+        None
+    }
+
+    /// Forget a deleted post
+    fn forget(&mut self, post_id: &str) {
+        if self.pinned.remove(post_id).is_some() {
+            self.order.retain(|id| id != post_id);
         }
     }
 }
 
+/// Number of unanswered pings remembered; older ones are given up on
+const MAX_PENDING_PINGS: usize = 8;
+
+/// A ping awaiting its pong
+#[derive(Debug)]
+struct PendingPing {
+    payload: Vec<u8>,
+    sent_at: std::time::Instant,
+    /// Receives the round-trip time in milliseconds, for pings sent by `ping()`
+    reply: Option<oneshot::Sender<u64>>,
+}
+
+/// Tracks the round trips of the pings sent on the connection
+#[derive(Debug, Default)]
+struct PingTracker {
+    /// Pings sent on the current connection and not answered yet, oldest first
+    pending: VecDeque<PendingPing>,
+    /// Payload of the next ping, so pongs can be matched with their ping
+    next_id: u64,
+    /// Round-trip time of the last answered ping in milliseconds
+    rtt_ms: Option<u64>,
+    /// Local time in milliseconds the last pong was received
+    last_pong_at: i64,
+    pings_sent: u64,
+    pongs_received: u64,
+}
+
+impl PingTracker {
+    /// Record a ping about to be sent and return its payload
+    fn sent(&mut self, reply: Option<oneshot::Sender<u64>>) -> Vec<u8> {
+        self.next_id += 1;
+        let payload = self.next_id.to_be_bytes().to_vec();
+        if self.pending.len() == MAX_PENDING_PINGS {
+            self.pending.pop_front();
+        }
+        self.pending.push_back(PendingPing {
+            payload: payload.clone(),
+            sent_at: std::time::Instant::now(),
+            reply,
+        });
+        self.pings_sent += 1;
+        payload
+    }
+
+    /// Match a pong with its ping and record the round-trip time
+    fn received(&mut self, payload: &[u8]) {
+        self.pongs_received += 1;
+        self.last_pong_at = chrono::Utc::now().timestamp_millis();
+
+        let Some(index) = self.pending.iter().position(|p| p.payload == payload) else {
+            return;
+        };
+        // Pongs come back in order, so pings sent before this one were lost
+        let Some(ping) = self.pending.drain(..=index).last() else {
+            return;
+        };
+        let rtt_ms = ping.sent_at.elapsed().as_millis() as u64;
+        self.rtt_ms = Some(rtt_ms);
+        if let Some(reply) = ping.reply {
+            let _ = reply.send(rtt_ms);
+        }
+    }
+}
+
+/// Convert a local timestamp in milliseconds, 0 meaning never, to a time
+fn timestamp_millis(ms: i64) -> Option<chrono::DateTime<chrono::Utc>> {
+    (ms > 0)
+        .then(|| chrono::DateTime::from_timestamp_millis(ms))
+        .flatten()
+}
+
 /// WebSocket connection manager for Mattermost
 pub struct WebSocketManager {
     /// URL for the WebSocket connection
@@ -74,9 +239,9 @@ pub struct WebSocketManager {
     /// Configuration
     config: WebSocketConfig,
     /// Event sender (for internal use)
-    event_tx: mpsc::Sender<PlatformEvent>,
+    event_tx: mpsc::Sender<SequencedEvent>,
     /// Event receiver for polling events
-    event_rx: Arc<Mutex<mpsc::Receiver<PlatformEvent>>>,
+    event_rx: Arc<Mutex<mpsc::Receiver<SequencedEvent>>>,
     /// WebSocket write half for sending messages
     ws_writer: Arc<Mutex<Option<WsWriter>>>,
     /// Shutdown signal sender
@@ -85,10 +250,18 @@ pub struct WebSocketManager {
     seq_number: Arc<Mutex<i64>>,
     /// Last received sequence number for gap detection
     last_received_seq: Arc<Mutex<i64>>,
+    /// Server-assigned connection ID from the "hello" event, used to resume
+    connection_id: Arc<Mutex<Option<String>>>,
     /// Current connection state
     connection_state: Arc<Mutex<ConnectionState>>,
     /// Current number of reconnection attempts
     reconnect_attempts: Arc<Mutex<u32>>,
+    /// Events possibly lost to reconnects
+    event_gap: Arc<Mutex<EventGap>>,
+    /// Round trips of pings
+    pings: Arc<Mutex<PingTracker>>,
+    /// Pin state of recently seen posts
+    pins: Arc<Mutex<PinStates>>,
 }
 
 impl WebSocketManager {
@@ -127,8 +300,110 @@ impl WebSocketManager {
             shutdown_tx: None,
             seq_number: Arc::new(Mutex::new(1)),
             last_received_seq: Arc::new(Mutex::new(0)),
+            connection_id: Arc::new(Mutex::new(None)),
             connection_state: Arc::new(Mutex::new(ConnectionState::Disconnected)),
             reconnect_attempts: Arc::new(Mutex::new(0)),
+            event_gap: Arc::new(Mutex::new(EventGap::default())),
+            pings: Arc::new(Mutex::new(PingTracker::default())),
+            pins: Arc::new(Mutex::new(PinStates::default())),
+        }
+    }
+
+    /// Enable or disable event payload schema validation
+    ///
+    /// Must be called before `connect()`.
+    pub fn with_strict_schema(mut self, strict: bool) -> Self {
+        self.config.strict_schema = strict;
+        self
+    }
+
+    /// Connect through a proxy, unless it bypasses the server's host
+    ///
+    /// Must be called before `connect()`.
+    pub fn with_proxy(mut self, proxy: Option<ProxyConfig>) -> Self {
+        self.config.proxy = proxy;
+        self
+    }
+
+    /// Use custom TLS settings for wss:// connections
+    ///
+    /// Must be called before `connect()`.
+    pub fn with_tls(mut self, tls: Option<TlsConfig>) -> Self {
+        self.config.tls = tls;
+        self
+    }
+
+    /// Add headers to the opening handshake, such as those an authenticating
+    /// proxy requires
+    ///
+    /// Must be called before `connect()`.
+    pub fn with_headers(mut self, headers: reqwest::header::HeaderMap) -> Self {
+        self.config.headers = headers;
+        self
+    }
+
+    /// Bound the opening handshake, and drop connections silent for longer than `idle`
+    ///
+    /// The server answers the keep-alive pings sent every `ping_interval_secs`,
+    /// so `idle` should be comfortably longer than that. Must be called before
+    /// `connect()`.
+    pub fn with_timeouts(
+        mut self,
+        handshake: Option<std::time::Duration>,
+        idle: Option<std::time::Duration>,
+    ) -> Self {
+        self.config.handshake_timeout = handshake;
+        self.config.idle_timeout = idle;
+        self
+    }
+
+    /// Resume a previous connection instead of starting a new one
+    ///
+    /// The server replays events after `last_sequence` if it still holds the
+    /// connection's queue; otherwise it starts a fresh connection. Must be
+    /// called before `connect()`.
+    pub fn with_resume(mut self, connection_id: String, last_sequence: i64) -> Self {
+        self.connection_id = Arc::new(Mutex::new(Some(connection_id)));
+        self.last_received_seq = Arc::new(Mutex::new(last_sequence));
+        self
+    }
+
+    /// Get the connection ID and last received sequence number
+    ///
+    /// Pass these to `with_resume` after a restart to pick up where this connection left off.
+    pub async fn resume_point(&self) -> (Option<String>, i64) {
+        let connection_id = self.connection_id.lock().await.clone();
+        let last_seq = *self.last_received_seq.lock().await;
+        (connection_id, last_seq)
+    }
+
+    /// Take the start of the period whose events may have been lost to a reconnect
+    ///
+    /// Returns a Unix timestamp in milliseconds, or None if every reconnect
+    /// resumed its connection. The server replays missed events on resume;
+    /// when it can't (its queue for the connection expired) it starts a fresh
+    /// connection and events sent in between are gone. The gap is cleared by
+    /// this call.
+    pub async fn take_event_gap(&self) -> Option<i64> {
+        let since = self.event_gap.lock().await.since.take();
+        since.filter(|_| self.config.recover_missed_events)
+    }
+
+    /// Report an event gap again, e.g. after failing to recover it
+    ///
+    /// Merges with any gap reported since, keeping the earliest start.
+    pub async fn restore_event_gap(&self, since: i64) {
+        let mut gap = self.event_gap.lock().await;
+        gap.since = Some(gap.since.map_or(since, |s| s.min(since)));
+    }
+
+    /// Build the URL to connect to, asking the server to resume a known connection
+    fn connect_url(ws_url: &str, connection_id: Option<&str>, last_seq: i64) -> String {
+        match connection_id {
+            Some(id) if !id.is_empty() => {
+                format!("{ws_url}?connection_id={id}&sequence_number={last_seq}")
+            }
+            _ => ws_url.to_string(),
         }
     }
 
@@ -203,6 +478,41 @@ impl WebSocketManager {
         *self.connection_state.lock().await
     }
 
+    /// Get the health of the connection: ping round trips and the time of the last event
+    pub async fn health(&self) -> ConnectionHealth {
+        let state = match self.get_connection_state().await {
+            ConnectionState::Disconnected => PublicConnectionState::Disconnected,
+            ConnectionState::Connecting => PublicConnectionState::Connecting,
+            ConnectionState::Connected => PublicConnectionState::Connected,
+            ConnectionState::Reconnecting => PublicConnectionState::Reconnecting,
+            ConnectionState::ShuttingDown => PublicConnectionState::Disconnecting,
+        };
+        let last_event_at = self.event_gap.lock().await.last_event_at;
+        let pings = self.pings.lock().await;
+
+        ConnectionHealth {
+            state,
+            rtt_ms: pings.rtt_ms,
+            last_pong_at: timestamp_millis(pings.last_pong_at),
+            last_event_at: timestamp_millis(last_event_at),
+            unanswered_pings: pings.pending.len() as u32,
+            pings_sent: pings.pings_sent,
+            pongs_received: pings.pongs_received,
+        }
+    }
+
+    /// Send a ping
+    ///
+    /// # Returns
+    /// A receiver for the round-trip time in milliseconds. It fails if the
+    /// connection drops before the pong arrives.
+    pub async fn ping(&self) -> Result<oneshot::Receiver<u64>> {
+        let (reply_tx, reply_rx) = oneshot::channel();
+        let payload = self.pings.lock().await.sent(Some(reply_tx));
+        self.send_ws_message(Message::Ping(payload)).await?;
+        Ok(reply_rx)
+    }
+
     /// Set the connection state
     async fn set_connection_state(&self, state: ConnectionState) {
         *self.connection_state.lock().await = state;
@@ -273,43 +583,29 @@ impl WebSocketManager {
     pub async fn connect(&mut self) -> Result<()> {
         self.set_connection_state(ConnectionState::Connecting).await;
 
-        let (ws_stream, _) = connect_async(&self.ws_url).await.map_err(|e| {
-            // Set state back to disconnected on failure
-            let state = self.connection_state.clone();
-            tokio::spawn(async move {
-                *state.lock().await = ConnectionState::Disconnected;
-            });
-            Error::new(
-                ErrorCode::NetworkError,
-                format!("WebSocket connection failed: {e}"),
-            )
-        })?;
-
-        let (mut write, read) = ws_stream.split();
-
-        // Send authentication challenge
-        let seq = {
-            let mut seq_num = self.seq_number.lock().await;
-            let current = *seq_num;
-            *seq_num += 1;
-            current
+        let url = {
+            let connection_id = self.connection_id.lock().await;
+            let last_seq = *self.last_received_seq.lock().await;
+            Self::connect_url(&self.ws_url, connection_id.as_deref(), last_seq)
         };
 
-        let auth_challenge = WebSocketAuthChallenge {
-            seq,
-            action: "authentication_challenge".to_string(),
-            data: WebSocketAuthData {
-                token: self.token.clone(),
-            },
-        };
-
-        let auth_msg = serde_json::to_string(&auth_challenge).map_err(|e| {
-            Error::new(ErrorCode::Unknown, format!("Failed to serialize auth: {e}"))
-        })?;
-
-        write.send(Message::Text(auth_msg)).await.map_err(|e| {
-            Error::new(ErrorCode::NetworkError, format!("Failed to send auth: {e}"))
-        })?;
+        let (write, read, auth_seq) =
+            match Self::open_connection(&url, &self.token, &self.seq_number, &self.config).await {
+                Ok(connection) => connection,
+                Err(e) => {
+                    diag!(
+                        Error,
+                        "WebSocket connection to {} failed: {}",
+                        self.ws_url,
+                        e.message
+                    );
+                    // Set state back to disconnected on failure
+                    self.set_connection_state(ConnectionState::Disconnected)
+                        .await;
+                    return Err(e);
+                }
+            };
+        diag!(Info, "WebSocket connected to {}", self.ws_url);
 
         // Store the write half for bidirectional communication
         *self.ws_writer.lock().await = Some(write);
@@ -323,6 +619,10 @@ impl WebSocketManager {
 
         // Mark as connected after successful authentication challenge sent
         self.set_connection_state(ConnectionState::Connected).await;
+        Self::emit_state_change(
+            &self.event_tx,
+            ConnectionStateChange::new(PublicConnectionState::Connected),
+        );
 
         // Reset reconnection counter on successful connection
         self.reset_reconnect_attempts().await;
@@ -336,8 +636,11 @@ impl WebSocketManager {
         let connection_state = Arc::clone(&self.connection_state);
         let ws_writer = Arc::clone(&self.ws_writer);
         let last_received_seq = Arc::clone(&self.last_received_seq);
+        let connection_id = Arc::clone(&self.connection_id);
         let reconnect_attempts = Arc::clone(&self.reconnect_attempts);
-        let ping_interval = std::time::Duration::from_secs(self.config.ping_interval_secs);
+        let event_gap = Arc::clone(&self.event_gap);
+        let pings = Arc::clone(&self.pings);
+        let pins = Arc::clone(&self.pins);
 
         // Clone config and connection info for reconnection
         let config = self.config.clone();
@@ -347,230 +650,424 @@ impl WebSocketManager {
 
         // Spawn a task to handle incoming messages with automatic reconnection
         tokio::spawn(async move {
-            let mut read = read; // Make read mutable for the task
-            let mut ping_timer = tokio::time::interval(ping_interval);
-            ping_timer.tick().await; // Skip first immediate tick
-            let mut current_shutdown_rx = shutdown_rx;
-
-            loop {
-                tokio::select! {
-                    // Handle incoming WebSocket messages
-                    msg = read.next() => {
-                        match msg {
-                            Some(Ok(Message::Text(text))) => {
-                                let _ = Self::handle_message(text, &event_tx, &last_received_seq).await;
-                            }
-                            Some(Ok(Message::Ping(data))) => {
-                                // Respond to ping with pong
-                                if let Some(writer) = ws_writer.lock().await.as_mut() {
-                                    if writer.send(Message::Pong(data)).await.is_err() {
-                                        *connection_state.lock().await = ConnectionState::Disconnected;
-                                        *ws_writer.lock().await = None;
-                                        break;
-                                    }
-                                }
-                            }
-                            Some(Ok(Message::Pong(_))) => {
-                                // Pong received - connection is alive
-                            }
-                            Some(Ok(Message::Close(_))) => {
-                                *connection_state.lock().await = ConnectionState::Disconnected;
-                                *ws_writer.lock().await = None;
-                                break;
-                            }
-                            Some(Err(_)) => {
-                                *connection_state.lock().await = ConnectionState::Disconnected;
-                                *ws_writer.lock().await = None;
-                                break;
-                            }
-                            None => {
-                                *connection_state.lock().await = ConnectionState::Disconnected;
-                                *ws_writer.lock().await = None;
-                                break;
-                            }
-                            _ => {}
-                        }
-                    }
-                    // Send periodic ping to keep connection alive
-                    _ = ping_timer.tick() => {
-                        if let Some(writer) = ws_writer.lock().await.as_mut() {
-                            if writer.send(Message::Ping(vec![])).await.is_err() {
-                                *connection_state.lock().await = ConnectionState::Disconnected;
-                                *ws_writer.lock().await = None;
-                                break;
-                            }
-                        }
-                    }
-                    // Handle shutdown signal
-                    _ = current_shutdown_rx.recv() => {
-                        *connection_state.lock().await = ConnectionState::ShuttingDown;
-                        *ws_writer.lock().await = None;
-                        break;
+            let mut read = read;
+            let mut auth_seq = auth_seq;
+            let mut shutdown_rx = shutdown_rx;
+
+            'connection: loop {
+                let exit = Self::read_loop(
+                    &mut read,
+                    &mut shutdown_rx,
+                    &ws_writer,
+                    &event_tx,
+                    &last_received_seq,
+                    &connection_id,
+                    &event_gap,
+                    &pings,
+                    &pins,
+                    &config,
+                    auth_seq,
+                )
+                .await;
+                *ws_writer.lock().await = None;
+
+                // None means disconnect() was called
+                let Some(mut cause) = exit else {
+                    break 'connection;
+                };
+                diag!(
+                    Warning,
+                    "WebSocket connection lost ({:?}): {}",
+                    cause.reason,
+                    cause.detail.as_deref().unwrap_or("no detail")
+                );
+
+                // Reconnecting won't help while the token is rejected
+                if !config.enable_auto_reconnect
+                    || cause.reason == Some(ConnectionChangeReason::AuthExpired)
+                {
+                    if config.enable_auto_reconnect {
+                        diag!(Error, "WebSocket not reconnecting: the session expired");
                     }
+                    *connection_state.lock().await = ConnectionState::Disconnected;
+                    cause.state = PublicConnectionState::Disconnected;
+                    Self::emit_state_change(&event_tx, cause);
+                    return;
                 }
-            }
-
-            // After disconnect, check if we should attempt reconnection
-            let current_state = *connection_state.lock().await;
 
-            // Only attempt reconnection if not shutting down and auto-reconnect is enabled
-            if current_state != ConnectionState::ShuttingDown && config.enable_auto_reconnect {
                 // Reconnection loop with exponential backoff
                 loop {
-                    // Get current attempt count
-                    let attempt_num = {
-                        let attempts = reconnect_attempts.lock().await;
-                        *attempts
-                    };
+                    let attempt_num = *reconnect_attempts.lock().await;
 
                     // Check if we've exceeded max attempts
                     if let Some(max_attempts) = config.max_reconnect_attempts {
                         if attempt_num >= max_attempts {
+                            diag!(
+                                Error,
+                                "WebSocket gave up reconnecting after {attempt_num} attempts"
+                            );
                             *connection_state.lock().await = ConnectionState::Disconnected;
-                            break;
+                            cause.state = PublicConnectionState::Disconnected;
+                            cause.attempt = attempt_num;
+                            cause.next_retry_ms = None;
+                            Self::emit_state_change(&event_tx, cause);
+                            return;
                         }
                     }
 
-                    // Increment reconnect attempts
-                    {
-                        let mut attempts = reconnect_attempts.lock().await;
-                        *attempts += 1;
-                    }
-
-                    // Set state to Reconnecting
+                    *reconnect_attempts.lock().await += 1;
                     *connection_state.lock().await = ConnectionState::Reconnecting;
 
-                    // Calculate backoff delay using the WebSocketManager method
-                    // We need to create a temporary manager instance to access the method
-                    // Actually, we can't access `self` here, so we'll use inline calculation
-                    // But we should refactor calculate_backoff_delay to be a static method
                     let delay = Self::calculate_backoff_delay_static(&config, attempt_num);
+                    cause.state = PublicConnectionState::Reconnecting;
+                    cause.attempt = attempt_num + 1;
+                    cause.next_retry_ms = Some(delay);
+                    Self::emit_state_change(&event_tx, cause.clone());
+                    diag!(
+                        Info,
+                        "WebSocket reconnecting in {delay}ms (attempt {})",
+                        attempt_num + 1
+                    );
+
+                    tokio::select! {
+                        _ = tokio::time::sleep(std::time::Duration::from_millis(delay)) => {}
+                        _ = shutdown_rx.recv() => break 'connection,
+                    }
 
-                    tokio::time::sleep(std::time::Duration::from_millis(delay)).await;
-
-                    // Attempt to reconnect
-                    match connect_async(&ws_url).await {
-                        Ok((ws_stream, _)) => {
-                            let (mut write, new_read) = ws_stream.split();
-
-                            // Send authentication challenge
-                            let seq = {
-                                let mut seq_num = seq_number.lock().await;
-                                let current = *seq_num;
-                                *seq_num += 1;
-                                current
-                            };
-
-                            let auth_challenge = WebSocketAuthChallenge {
-                                seq,
-                                action: "authentication_challenge".to_string(),
-                                data: WebSocketAuthData {
-                                    token: token.clone(),
-                                },
-                            };
-
-                            if let Ok(auth_msg) = serde_json::to_string(&auth_challenge) {
-                                if write.send(Message::Text(auth_msg)).await.is_ok() {
-                                    // Successfully reconnected and authenticated
-                                    *ws_writer.lock().await = Some(write);
-                                    *connection_state.lock().await = ConnectionState::Connected;
-                                    *reconnect_attempts.lock().await = 0; // Reset counter
-
-                                    // Continue with the new read stream
-                                    read = new_read;
-                                    ping_timer = tokio::time::interval(ping_interval);
-                                    ping_timer.tick().await; // Skip first tick
-
-                                    // Reconnection successful, return to message loop
-                                    'message_loop: loop {
-                                        tokio::select! {
-                                            msg = read.next() => {
-                                                match msg {
-                                                    Some(Ok(Message::Text(text))) => {
-                                                        let _ = Self::handle_message(text, &event_tx, &last_received_seq).await;
-                                                    }
-                                                    Some(Ok(Message::Ping(data))) => {
-                                                        if let Some(writer) = ws_writer.lock().await.as_mut() {
-                                                            if writer.send(Message::Pong(data)).await.is_err() {
-                                                                *connection_state.lock().await = ConnectionState::Disconnected;
-                                                                *ws_writer.lock().await = None;
-                                                                break 'message_loop;
-                                                            }
-                                                        }
-                                                    }
-                                                    Some(Ok(Message::Pong(_))) => {}
-                                                    Some(Ok(Message::Close(_))) => {
-                                                        *connection_state.lock().await = ConnectionState::Disconnected;
-                                                        *ws_writer.lock().await = None;
-                                                        break 'message_loop;
-                                                    }
-                                                    Some(Err(_)) => {
-                                                        *connection_state.lock().await = ConnectionState::Disconnected;
-                                                        *ws_writer.lock().await = None;
-                                                        break 'message_loop;
-                                                    }
-                                                    None => {
-                                                        *connection_state.lock().await = ConnectionState::Disconnected;
-                                                        *ws_writer.lock().await = None;
-                                                        break 'message_loop;
-                                                    }
-                                                    _ => {}
-                                                }
-                                            }
-                                            _ = ping_timer.tick() => {
-                                                if let Some(writer) = ws_writer.lock().await.as_mut() {
-                                                    if writer.send(Message::Ping(vec![])).await.is_err() {
-                                                        *connection_state.lock().await = ConnectionState::Disconnected;
-                                                        *ws_writer.lock().await = None;
-                                                        break 'message_loop;
-                                                    }
-                                                }
-                                            }
-                                            _ = current_shutdown_rx.recv() => {
-                                                *connection_state.lock().await = ConnectionState::ShuttingDown;
-                                                *ws_writer.lock().await = None;
-                                                return; // Exit completely
-                                            }
-                                        }
-                                    }
-                                    // If we break from the inner loop, continue the reconnection loop
-                                }
-                            }
+                    // Resume the dropped connection so missed events are replayed
+                    let url = {
+                        let id = connection_id.lock().await;
+                        let last_seq = *last_received_seq.lock().await;
+                        Self::connect_url(&ws_url, id.as_deref(), last_seq)
+                    };
+
+                    match Self::open_connection(&url, &token, &seq_number, &config).await {
+                        Ok((write, new_read, new_auth_seq)) => {
+                            *ws_writer.lock().await = Some(write);
+                            *connection_state.lock().await = ConnectionState::Connected;
+                            *reconnect_attempts.lock().await = 0;
+                            diag!(
+                                Info,
+                                "WebSocket reconnected after {} attempts",
+                                attempt_num + 1
+                            );
+
+                            let mut connected =
+                                ConnectionStateChange::new(PublicConnectionState::Connected);
+                            connected.attempt = attempt_num + 1;
+                            Self::emit_state_change(&event_tx, connected);
+
+                            read = new_read;
+                            auth_seq = new_auth_seq;
+                            continue 'connection;
                         }
-                        Err(_) => {
-                            // Continue to next reconnection attempt
+                        Err(e) => {
+                            diag!(
+                                Warning,
+                                "WebSocket reconnect attempt {} failed: {}",
+                                attempt_num + 1,
+                                e.message
+                            );
+                            // Report the latest failure with the next attempt
+                            cause = ConnectionStateChange::caused_by(
+                                PublicConnectionState::Reconnecting,
+                                ConnectionChangeReason::NetworkError,
+                            );
+                            cause.detail = Some(e.message);
                         }
                     }
                 }
             }
 
-            // Final cleanup - ensure we're marked as disconnected
+            // Shut down on request
+            diag!(Info, "WebSocket disconnected");
             *connection_state.lock().await = ConnectionState::Disconnected;
             *ws_writer.lock().await = None;
+            Self::emit_state_change(
+                &event_tx,
+                ConnectionStateChange::caused_by(
+                    PublicConnectionState::Disconnected,
+                    ConnectionChangeReason::ClientClosed,
+                ),
+            );
         });
 
         Ok(())
     }
 
+    /// Serve a connection until it ends
+    ///
+    /// # Returns
+    /// What ended the connection, or None if `disconnect()` was called
+    #[allow(clippy::too_many_arguments)]
+    async fn read_loop(
+        read: &mut WsReader,
+        shutdown_rx: &mut mpsc::Receiver<()>,
+        ws_writer: &Arc<Mutex<Option<WsWriter>>>,
+        event_tx: &mpsc::Sender<SequencedEvent>,
+        last_received_seq: &Arc<Mutex<i64>>,
+        connection_id: &Arc<Mutex<Option<String>>>,
+        event_gap: &Arc<Mutex<EventGap>>,
+        pings: &Arc<Mutex<PingTracker>>,
+        pins: &Arc<Mutex<PinStates>>,
+        config: &WebSocketConfig,
+        auth_seq: i64,
+    ) -> Option<ConnectionStateChange> {
+        let network_error = |detail: String| {
+            let mut change = ConnectionStateChange::caused_by(
+                PublicConnectionState::Disconnected,
+                ConnectionChangeReason::NetworkError,
+            );
+            change.detail = Some(detail);
+            change
+        };
+
+        let mut ping_timer =
+            tokio::time::interval(std::time::Duration::from_secs(config.ping_interval_secs));
+        ping_timer.tick().await; // Skip first immediate tick
+
+        // A new connection won't answer the pings sent on the previous one
+        pings.lock().await.pending.clear();
+
+        let idle_deadline = |limit: std::time::Duration| tokio::time::Instant::now() + limit;
+        let mut idle_at = config.idle_timeout.map(idle_deadline);
+
+        loop {
+            // Copies idle_at, which the message branch resets
+            let idle = async move {
+                match idle_at {
+                    Some(at) => tokio::time::sleep_until(at).await,
+                    None => std::future::pending().await,
+                }
+            };
+            tokio::select! {
+                // Handle incoming WebSocket messages
+                msg = read.next() => {
+                    idle_at = config.idle_timeout.map(idle_deadline);
+                    match msg {
+                        Some(Ok(Message::Text(text))) => {
+                            let handled = Self::handle_message(text, event_tx, last_received_seq, connection_id, event_gap, pins, config.strict_schema, auth_seq).await;
+                            // The server rejects the authentication challenge once the session has expired
+                            if let Err(e) = handled {
+                                if e.code == ErrorCode::AuthenticationFailed {
+                                    let mut change = ConnectionStateChange::caused_by(
+                                        PublicConnectionState::Disconnected,
+                                        ConnectionChangeReason::AuthExpired,
+                                    );
+                                    change.detail = Some(e.message);
+                                    return Some(change);
+                                }
+                            }
+                        }
+                        Some(Ok(Message::Ping(data))) => {
+                            // Respond to ping with pong
+                            if let Some(writer) = ws_writer.lock().await.as_mut() {
+                                if let Err(e) = writer.send(Message::Pong(data)).await {
+                                    return Some(network_error(e.to_string()));
+                                }
+                            }
+                        }
+                        Some(Ok(Message::Close(frame))) => {
+                            let mut change = ConnectionStateChange::caused_by(
+                                PublicConnectionState::Disconnected,
+                                ConnectionChangeReason::ServerClosed,
+                            );
+                            if let Some(frame) = frame {
+                                change.close_code = Some(u16::from(frame.code));
+                                change.detail = (!frame.reason.is_empty()).then(|| frame.reason.to_string());
+                            }
+                            return Some(change);
+                        }
+                        Some(Ok(Message::Pong(data))) => pings.lock().await.received(&data),
+                        Some(Err(e)) => return Some(network_error(e.to_string())),
+                        None => return Some(network_error("connection closed".to_string())),
+                        _ => {}
+                    }
+                }
+                // Send periodic ping to keep connection alive
+                _ = ping_timer.tick() => {
+                    if let Some(writer) = ws_writer.lock().await.as_mut() {
+                        let payload = pings.lock().await.sent(None);
+                        if let Err(e) = writer.send(Message::Ping(payload)).await {
+                            return Some(network_error(e.to_string()));
+                        }
+                    }
+                }
+                // Nothing arrived, not even a pong, for the idle timeout
+                _ = idle => {
+                    return Some(network_error(format!(
+                        "no data received for {:?}",
+                        config.idle_timeout.unwrap_or_default()
+                    )));
+                }
+                // Handle shutdown signal
+                _ = shutdown_rx.recv() => return None,
+            }
+        }
+    }
+
+    /// Open a new connection and send the authentication challenge
+    ///
+    /// # Returns
+    /// The connection halves and the sequence number of the challenge
+    async fn open_connection(
+        url: &str,
+        token: &str,
+        seq_number: &Arc<Mutex<i64>>,
+        config: &WebSocketConfig,
+    ) -> Result<(WsWriter, WsReader, i64)> {
+        let opening = Self::open_stream(
+            url,
+            config.proxy.as_ref(),
+            config.tls.as_ref(),
+            &config.headers,
+        );
+        let ws_stream = match config.handshake_timeout {
+            Some(limit) => tokio::time::timeout(limit, opening).await.map_err(|_| {
+                Error::new(
+                    ErrorCode::Timeout,
+                    format!("WebSocket handshake timed out after {limit:?}"),
+                )
+            })??,
+            None => opening.await?,
+        };
+        let (mut write, read) = ws_stream.split();
+
+        let seq = {
+            let mut seq_num = seq_number.lock().await;
+            let current = *seq_num;
+            *seq_num += 1;
+            current
+        };
+
+        let auth_challenge = WebSocketAuthChallenge {
+            seq,
+            action: "authentication_challenge".to_string(),
+            data: WebSocketAuthData {
+                token: token.to_string(),
+            },
+        };
+
+        let auth_msg = serde_json::to_string(&auth_challenge).map_err(|e| {
+            Error::new(ErrorCode::Unknown, format!("Failed to serialize auth: {e}"))
+        })?;
+
+        write.send(Message::Text(auth_msg)).await.map_err(|e| {
+            Error::new(ErrorCode::NetworkError, format!("Failed to send auth: {e}"))
+        })?;
+
+        Ok((write, read, seq))
+    }
+
+    /// Open the WebSocket, tunneling through the proxy unless it bypasses the host
+    async fn open_stream(
+        url: &str,
+        proxy: Option<&ProxyConfig>,
+        tls: Option<&TlsConfig>,
+        headers: &reqwest::header::HeaderMap,
+    ) -> Result<WebSocketStream<MaybeTlsStream<TcpStream>>> {
+        let failed = |e: tokio_tungstenite::tungstenite::Error| {
+            Error::new(
+                ErrorCode::NetworkError,
+                format!("WebSocket connection failed: {e}"),
+            )
+        };
+        let parsed = url::Url::parse(url).map_err(|e| {
+            Error::new(
+                ErrorCode::InvalidArgument,
+                format!("Invalid WebSocket URL: {e}"),
+            )
+        })?;
+        let host = parsed.host_str().unwrap_or_default();
+        // None keeps tokio-tungstenite's default: public web roots
+        let connector = tls.map(TlsConfig::connector).transpose()?;
+        let mut request = url.into_client_request().map_err(failed)?;
+        request.headers_mut().extend(headers.clone());
+
+        let (ws_stream, _) = match proxy {
+            Some(proxy) if !proxy.bypasses(host) => {
+                let port = parsed.port_or_known_default().unwrap_or(443);
+                let stream = proxy.tunnel(host, port).await?;
+                client_async_tls_with_config(request, stream, None, connector)
+                    .await
+                    .map_err(failed)?
+            }
+            _ => connect_async_tls_with_config(request, None, false, connector)
+                .await
+                .map_err(failed)?,
+        };
+        Ok(ws_stream)
+    }
+
+    /// Queue a connection state change event; dropped if the queue is full
+    fn emit_state_change(event_tx: &mpsc::Sender<SequencedEvent>, change: ConnectionStateChange) {
+        let _ = event_tx.try_send((0, PlatformEvent::ConnectionStateChanged(change)));
+    }
+
+    /// Queue an event converted from the server's
+    ///
+    /// When the queue is full the event is dropped, which loses it like a
+    /// dropped connection would, so the gap is recorded for the posts to be
+    /// fetched again on the next poll.
+    async fn queue_event(
+        event_tx: &mpsc::Sender<SequencedEvent>,
+        event_gap: &Arc<Mutex<EventGap>>,
+        seq: i64,
+        event: PlatformEvent,
+    ) {
+        if let Err(mpsc::error::TrySendError::Full(_)) = event_tx.try_send((seq, event)) {
+            diag!(
+                Warning,
+                "Event queue full, dropped event {seq}; its posts will be fetched again"
+            );
+            let mut gap = event_gap.lock().await;
+            let last_event_at = gap.last_event_at;
+            gap.since.get_or_insert(last_event_at);
+        }
+    }
+
     /// Handle an incoming WebSocket message
+    #[allow(clippy::too_many_arguments)]
     async fn handle_message(
         text: String,
-        event_tx: &mpsc::Sender<PlatformEvent>,
+        event_tx: &mpsc::Sender<SequencedEvent>,
         last_received_seq: &Arc<Mutex<i64>>,
+        connection_id: &Arc<Mutex<Option<String>>>,
+        event_gap: &Arc<Mutex<EventGap>>,
+        pins: &Arc<Mutex<PinStates>>,
+        strict_schema: bool,
+        auth_seq: i64,
     ) -> Result<()> {
-        // First, try to parse as authentication response
-        // Auth responses have a different structure: {"status": "OK", "seq_reply": 1}
-        if let Ok(auth_response) = serde_json::from_str::<WebSocketAuthResponse>(&text) {
-            if auth_response.status == "OK" {
+        // First, try to parse as a reply to an action we sent
+        // Replies have a different structure: {"status": "OK", "seq_reply": 1, "data": {...}}
+        if let Ok(reply) = serde_json::from_str::<WebSocketAuthResponse>(&text) {
+            if reply.seq_reply != auth_seq {
+                let error = reply.error.map(|error| match error {
+                    serde_json::Value::String(message) => message,
+                    other => other
+                        .get("message")
+                        .and_then(|v| v.as_str())
+                        .map(str::to_string)
+                        .unwrap_or_else(|| other.to_string()),
+                });
+                let _ = event_tx.try_send((
+                    0,
+                    PlatformEvent::Response {
+                        status: reply.status,
+                        seq_reply: reply.seq_reply,
+                        error,
+                        data: reply.data,
+                    },
+                ));
+                return Ok(());
+            }
+            if reply.status == "OK" {
                 // Authentication successful - this is informational, not emitted as an event
                 return Ok(());
             } else {
                 return Err(Error::new(
                     ErrorCode::AuthenticationFailed,
-                    format!(
-                        "Authentication failed with status: {}",
-                        auth_response.status
-                    ),
+                    format!("Authentication failed with status: {}", reply.status),
                 ));
             }
         }
@@ -583,22 +1080,126 @@ impl WebSocketManager {
             )
         })?;
 
+        // Remember the connection ID so a later reconnect can resume it
+        if ws_event.event == "hello" {
+            if let Some(id) = ws_event.data.get("connection_id").and_then(|v| v.as_str()) {
+                let mut current = connection_id.lock().await;
+                // A new ID means the server could not resume the previous
+                // connection and dropped the events queued for it
+                if current.as_deref().is_some_and(|previous| previous != id) {
+                    diag!(
+                        Warning,
+                        "WebSocket connection could not be resumed; events sent meanwhile were lost"
+                    );
+                    let mut gap = event_gap.lock().await;
+                    if gap.last_event_at > 0 {
+                        let last_event_at = gap.last_event_at;
+                        gap.since.get_or_insert(last_event_at);
+                    }
+
+                    // The new connection numbers its events from this hello
+                    let mut last_seq = last_received_seq.lock().await;
+                    let _ = event_tx.try_send((
+                        0,
+                        PlatformEvent::GapDetected {
+                            expected_seq: *last_seq + 1,
+                            received_seq: ws_event.seq,
+                        },
+                    ));
+                    *last_seq = ws_event.seq;
+                }
+                *current = Some(id.to_string());
+            }
+        }
+        event_gap.lock().await.last_event_at = chrono::Utc::now().timestamp_millis();
+
         // Check for sequence gaps
-        if ws_event.seq > 0 {
+        let seq = ws_event.seq;
+        if seq > 0 {
             let mut last_seq = last_received_seq.lock().await;
-            *last_seq = ws_event.seq;
+            if seq > *last_seq + 1 {
+                diag!(
+                    Warning,
+                    "WebSocket events {} to {} were missed",
+                    *last_seq + 1,
+                    seq - 1
+                );
+                let _ = event_tx.try_send((
+                    0,
+                    PlatformEvent::GapDetected {
+                        expected_seq: *last_seq + 1,
+                        received_seq: seq,
+                    },
+                ));
+            }
+            *last_seq = seq;
+        }
+
+        if strict_schema {
+            let issues = validate_event(&ws_event);
+            if !issues.is_empty() {
+                let _ = event_tx.try_send((
+                    seq,
+                    PlatformEvent::SchemaMismatch {
+                        event_type: ws_event.event.clone(),
+                        issues,
+                    },
+                ));
+            }
+        }
+
+        // Pin changes arrive as post_edited; surface them as dedicated events too
+        let pin_event = Self::convert_pin_event(&ws_event, &mut *pins.lock().await);
+        if let Some(pin_event) = pin_event {
+            Self::queue_event(event_tx, event_gap, seq, pin_event).await;
         }
 
         // Convert WebSocket event to PlatformEvent
         if let Some(platform_event) = Self::convert_event(ws_event) {
-            // Try to send event to channel
-            // If full, drop the event silently (non-blocking)
-            let _ = event_tx.try_send(platform_event);
+            Self::queue_event(event_tx, event_gap, seq, platform_event).await;
         }
 
         Ok(())
     }
 
+    /// Detect a pin/unpin in a post_edited event
+    ///
+    /// Mattermost has no dedicated pin event, so the pin state of the posts
+    /// seen is tracked and an event is emitted when a post_edited event
+    /// changes it. Posts not seen before are assumed unpinned, as they are
+    /// created: one that is pinned is reported if the update wasn't a content
+    /// edit, since pinning bumps `update_at` while leaving `edit_at` untouched.
+    fn convert_pin_event(ws_event: &WebSocketEvent, pins: &mut PinStates) -> Option<PlatformEvent> {
+        let post_str = ws_event.data.get("post")?.as_str()?;
+        let post = serde_json::from_str::<MattermostPost>(post_str).ok()?;
+        match ws_event.event.as_str() {
+            "posted" => {
+                pins.update(&post.id, post.is_pinned);
+                return None;
+            }
+            "post_deleted" => {
+                pins.forget(&post.id);
+                return None;
+            }
+            "post_edited" => {}
+            _ => return None,
+        }
+
+        let changed = match pins.update(&post.id, post.is_pinned) {
+            Some(was_pinned) => was_pinned != post.is_pinned,
+            None => post.is_pinned && post.update_at != post.edit_at,
+        };
+        if !changed {
+            return None;
+        }
+
+        if post.is_pinned {
+            Some(PlatformEvent::PostPinned(post.into()))
+        } else {
+            Some(PlatformEvent::PostUnpinned(post.into()))
+        }
+    }
+
     /// Convert a Mattermost WebSocket event to a PlatformEvent
     fn convert_event(ws_event: WebSocketEvent) -> Option<PlatformEvent> {
         match ws_event.event.as_str() {
@@ -702,8 +1303,11 @@ impl WebSocketManager {
                     if let Ok(channel_str) = serde_json::to_string(channel_data) {
                         if let Ok(channel) = serde_json::from_str::<MattermostChannel>(&channel_str)
                         {
-                            let channel = channel.into();
-                            return Some(PlatformEvent::ChannelUpdated(channel));
+                            // The previous state is filled in by the platform, which remembers it
+                            return Some(PlatformEvent::ChannelUpdated {
+                                channel: channel.into(),
+                                changes: None,
+                            });
                         }
                     }
                 }
@@ -1237,11 +1841,13 @@ impl WebSocketManager {
                     .get("error")
                     .and_then(|v| v.as_str())
                     .map(|s| s.to_string());
+                let data = ws_event.data.get("data").cloned();
 
                 Some(PlatformEvent::Response {
                     status,
                     seq_reply,
                     error,
+                    data,
                 })
             }
             "dialog_opened" => {
@@ -1280,8 +1886,14 @@ impl WebSocketManager {
                 None
             }
             _ => {
-                // Unknown event type - silently ignore
-                None
+                // Pass event types the library doesn't model through as they are
+                let scope = |id: &str| (!id.is_empty()).then(|| id.to_string());
+                Some(PlatformEvent::Raw {
+                    event_type: ws_event.event.clone(),
+                    channel_id: scope(&ws_event.broadcast.channel_id),
+                    team_id: scope(&ws_event.broadcast.team_id),
+                    data: serde_json::Value::Object(ws_event.data.into_iter().collect()),
+                })
             }
         }
     }
@@ -1289,8 +1901,10 @@ impl WebSocketManager {
     /// Poll for the next event from the event queue
     ///
     /// # Returns
-    /// An Option containing the next PlatformEvent, or None if the queue is empty
-    pub async fn poll_event(&self) -> Option<PlatformEvent> {
+    /// An Option containing the next PlatformEvent with the sequence number of
+    /// the WebSocket event it came from (0 for events generated by the
+    /// library), or None if the queue is empty
+    pub async fn poll_event(&self) -> Option<SequencedEvent> {
         let mut rx = self.event_rx.lock().await;
         rx.try_recv().ok()
     }
@@ -1339,6 +1953,177 @@ mod tests {
         assert_eq!(manager2.ws_url, "ws://localhost:8065/api/v4/websocket");
     }
 
+    #[tokio::test]
+    async fn test_resume_url() {
+        let url = "wss://mattermost.example.com/api/v4/websocket";
+        assert_eq!(WebSocketManager::connect_url(url, None, 42), url);
+        assert_eq!(WebSocketManager::connect_url(url, Some(""), 42), url);
+        assert_eq!(
+            WebSocketManager::connect_url(url, Some("abc123"), 42),
+            "wss://mattermost.example.com/api/v4/websocket?connection_id=abc123&sequence_number=42"
+        );
+
+        let manager = WebSocketManager::new("https://mattermost.example.com", "token".to_string())
+            .with_resume("abc123".to_string(), 42);
+        assert_eq!(
+            manager.resume_point().await,
+            (Some("abc123".to_string()), 42)
+        );
+    }
+
+    #[tokio::test]
+    async fn test_event_gap_on_fresh_connection() {
+        let manager = WebSocketManager::new("https://mattermost.example.com", "token".to_string());
+        let hello = |id: &str| {
+            format!(
+                r#"{{"event":"hello","data":{{"connection_id":"{id}"}},"broadcast":{{}},"seq":0}}"#
+            )
+        };
+        let handle = |text: String| {
+            WebSocketManager::handle_message(
+                text,
+                &manager.event_tx,
+                &manager.last_received_seq,
+                &manager.connection_id,
+                &manager.event_gap,
+                &manager.pins,
+                false,
+                1,
+            )
+        };
+
+        // First connection and a resumed one leave no gap
+        handle(hello("conn-1")).await.unwrap();
+        handle(hello("conn-1")).await.unwrap();
+        assert_eq!(manager.take_event_gap().await, None);
+
+        // A fresh connection loses what was sent since the last event
+        let last_event_at = manager.event_gap.lock().await.last_event_at;
+        handle(hello("conn-2")).await.unwrap();
+        assert_eq!(manager.take_event_gap().await, Some(last_event_at));
+        assert_eq!(manager.take_event_gap().await, None);
+
+        manager.restore_event_gap(last_event_at).await;
+        manager.restore_event_gap(last_event_at + 1).await;
+        assert_eq!(manager.take_event_gap().await, Some(last_event_at));
+    }
+
+    #[test]
+    fn test_ping_tracker_matches_pongs() {
+        let mut pings = PingTracker::default();
+        let lost = pings.sent(None);
+        let (reply_tx, mut reply_rx) = oneshot::channel();
+        let answered = pings.sent(Some(reply_tx));
+        assert_ne!(lost, answered);
+        assert_eq!(pings.pending.len(), 2);
+
+        // A pong nobody asked for is counted but matches nothing
+        pings.received(b"unknown");
+        assert_eq!(pings.pending.len(), 2);
+        assert_eq!(pings.rtt_ms, None);
+
+        // Answering a ping gives up on the ones sent before it
+        pings.received(&answered);
+        assert!(pings.pending.is_empty());
+        assert!(pings.rtt_ms.is_some());
+        assert_eq!(reply_rx.try_recv().ok(), pings.rtt_ms);
+        assert_eq!((pings.pings_sent, pings.pongs_received), (2, 2));
+        assert!(pings.last_pong_at > 0);
+
+        for _ in 0..MAX_PENDING_PINGS + 3 {
+            pings.sent(None);
+        }
+        assert_eq!(pings.pending.len(), MAX_PENDING_PINGS);
+    }
+
+    #[tokio::test]
+    async fn test_sequence_gap_detection() {
+        let manager = WebSocketManager::new("https://mattermost.example.com", "token".to_string());
+        let event =
+            |seq: i64| format!(r#"{{"event":"typing","data":{{}},"broadcast":{{}},"seq":{seq}}}"#);
+        let handle = |text: String| {
+            WebSocketManager::handle_message(
+                text,
+                &manager.event_tx,
+                &manager.last_received_seq,
+                &manager.connection_id,
+                &manager.event_gap,
+                &manager.pins,
+                false,
+                1,
+            )
+        };
+
+        handle(event(1)).await.unwrap();
+        handle(event(2)).await.unwrap();
+        while manager.poll_event().await.is_some() {}
+
+        handle(event(5)).await.unwrap();
+        match manager.poll_event().await {
+            Some((
+                0,
+                PlatformEvent::GapDetected {
+                    expected_seq,
+                    received_seq,
+                },
+            )) => {
+                assert_eq!(expected_seq, 3);
+                assert_eq!(received_seq, 5);
+            }
+            other => panic!("expected a gap, got {:?}", other),
+        }
+        assert_eq!(*manager.last_received_seq.lock().await, 5);
+    }
+
+    #[tokio::test]
+    async fn test_action_reply_becomes_response_event() {
+        let manager = WebSocketManager::new("https://mattermost.example.com", "token".to_string());
+        let handle = |text: &str| {
+            WebSocketManager::handle_message(
+                text.to_string(),
+                &manager.event_tx,
+                &manager.last_received_seq,
+                &manager.connection_id,
+                &manager.event_gap,
+                &manager.pins,
+                false,
+                1,
+            )
+        };
+
+        // The reply to the authentication challenge is not an event
+        handle(r#"{"status":"OK","seq_reply":1}"#).await.unwrap();
+        assert!(manager.poll_event().await.is_none());
+
+        handle(r#"{"status":"OK","seq_reply":2,"data":{"user-1":"online"}}"#)
+            .await
+            .unwrap();
+        match manager.poll_event().await {
+            Some((
+                _,
+                PlatformEvent::Response {
+                    seq_reply, data, ..
+                },
+            )) => {
+                assert_eq!(seq_reply, 2);
+                assert_eq!(data, Some(serde_json::json!({"user-1": "online"})));
+            }
+            other => panic!("expected a response, got {:?}", other),
+        }
+
+        // A failed action doesn't count as an authentication failure
+        handle(r#"{"status":"FAIL","seq_reply":3,"error":{"message":"bad request"}}"#)
+            .await
+            .unwrap();
+        match manager.poll_event().await {
+            Some((_, PlatformEvent::Response { error, .. })) => {
+                assert_eq!(error.as_deref(), Some("bad request"));
+            }
+            other => panic!("expected a response, got {:?}", other),
+        }
+        assert!(handle(r#"{"status":"FAIL","seq_reply":1}"#).await.is_err());
+    }
+
     #[tokio::test]
     async fn test_event_queue() {
         let manager = WebSocketManager::new("https://mattermost.example.com", "token".to_string());
@@ -1349,10 +2134,13 @@ mod tests {
         // Send an event through the channel
         manager
             .event_tx
-            .send(PlatformEvent::MessageDeleted {
-                message_id: "msg123".to_string(),
-                channel_id: "ch456".to_string(),
-            })
+            .send((
+                0,
+                PlatformEvent::MessageDeleted {
+                    message_id: "msg123".to_string(),
+                    channel_id: "ch456".to_string(),
+                },
+            ))
             .await
             .unwrap();
 
@@ -1375,6 +2163,9 @@ mod tests {
             initial_reconnect_delay_ms: 1000,
             max_reconnect_delay_ms: 60000,
             reconnect_backoff_multiplier: 2.0,
+            strict_schema: false,
+            recover_missed_events: true,
+            ..WebSocketConfig::default()
         };
         let manager = WebSocketManager::with_config(
             "https://mattermost.example.com",
@@ -1385,27 +2176,36 @@ mod tests {
         // Fill the queue
         manager
             .event_tx
-            .send(PlatformEvent::MessageDeleted {
-                message_id: "msg1".to_string(),
-                channel_id: "ch1".to_string(),
-            })
+            .send((
+                0,
+                PlatformEvent::MessageDeleted {
+                    message_id: "msg1".to_string(),
+                    channel_id: "ch1".to_string(),
+                },
+            ))
             .await
             .unwrap();
 
         manager
             .event_tx
-            .send(PlatformEvent::MessageDeleted {
-                message_id: "msg2".to_string(),
-                channel_id: "ch2".to_string(),
-            })
+            .send((
+                0,
+                PlatformEvent::MessageDeleted {
+                    message_id: "msg2".to_string(),
+                    channel_id: "ch2".to_string(),
+                },
+            ))
             .await
             .unwrap();
 
         // Queue is now full, try_send should fail
-        let result = manager.event_tx.try_send(PlatformEvent::MessageDeleted {
-            message_id: "msg3".to_string(),
-            channel_id: "ch3".to_string(),
-        });
+        let result = manager.event_tx.try_send((
+            0,
+            PlatformEvent::MessageDeleted {
+                message_id: "msg3".to_string(),
+                channel_id: "ch3".to_string(),
+            },
+        ));
 
         assert!(result.is_err());
         assert!(matches!(
@@ -1419,6 +2219,31 @@ mod tests {
         assert!(manager.poll_event().await.is_none());
     }
 
+    #[tokio::test]
+    async fn test_queue_overflow_records_event_gap() {
+        let config = WebSocketConfig {
+            max_queue_size: 1,
+            ..Default::default()
+        };
+        let manager = WebSocketManager::with_config(
+            "https://mattermost.example.com",
+            "token".to_string(),
+            config,
+        );
+        manager.event_gap.lock().await.last_event_at = 1234;
+        let typing = || PlatformEvent::UserTyping {
+            user_id: "u1".to_string(),
+            channel_id: "c1".to_string(),
+        };
+
+        WebSocketManager::queue_event(&manager.event_tx, &manager.event_gap, 1, typing()).await;
+        assert_eq!(manager.take_event_gap().await, None);
+
+        // The dropped event is recovered like one lost to a reconnect
+        WebSocketManager::queue_event(&manager.event_tx, &manager.event_gap, 2, typing()).await;
+        assert_eq!(manager.take_event_gap().await, Some(1234));
+    }
+
     #[test]
     fn test_parse_posted_event() {
         // Real data from Mattermost WebSocket
@@ -1465,6 +2290,99 @@ mod tests {
         }
     }
 
+    #[test]
+    fn test_parse_post_pinned_event() {
+        let json = r#"{"event": "post_edited", "data": {"post":"{\"id\":\"a4aurxyyc3yruntz4zfmdw75nr\",\"create_at\":1761422860825,\"update_at\":1761423000000,\"edit_at\":0,\"delete_at\":0,\"is_pinned\":true,\"user_id\":\"t1pn9rb63fnpjrqibgriijcx4r\",\"channel_id\":\"4ckrmjaeeb8mbpodbmo6bknpge\",\"root_id\":\"\",\"original_id\":\"\",\"message\":\"awe\",\"type\":\"\",\"props\":{},\"hashtags\":\"\",\"file_ids\":[],\"pending_post_id\":\"\",\"metadata\":{}}"}, "broadcast": {"omit_users":null,"user_id":"","channel_id":"4ckrmjaeeb8mbpodbmo6bknpge","team_id":"","connection_id":"","omit_connection_id":""}, "seq": 39}"#;
+
+        let ws_event: WebSocketEvent =
+            serde_json::from_str(json).expect("Failed to parse WebSocket event");
+
+        let mut pins = PinStates::default();
+        if let Some(PlatformEvent::PostPinned(msg)) =
+            WebSocketManager::convert_pin_event(&ws_event, &mut pins)
+        {
+            assert_eq!(msg.id, "a4aurxyyc3yruntz4zfmdw75nr");
+        } else {
+            panic!("Expected PostPinned event");
+        }
+
+        // The regular update is still emitted alongside the pin event
+        assert!(matches!(
+            WebSocketManager::convert_event(ws_event),
+            Some(PlatformEvent::MessageUpdated(_))
+        ));
+    }
+
+    #[test]
+    fn test_content_edit_is_not_pin_event() {
+        let json = r#"{"event": "post_edited", "data": {"post":"{\"id\":\"a4aurxyyc3yruntz4zfmdw75nr\",\"create_at\":1761422860825,\"update_at\":1761422988059,\"edit_at\":1761422988059,\"delete_at\":0,\"is_pinned\":true,\"user_id\":\"t1pn9rb63fnpjrqibgriijcx4r\",\"channel_id\":\"4ckrmjaeeb8mbpodbmo6bknpge\",\"message\":\"awe\"}"}, "broadcast": {"omit_users":null,"user_id":"","channel_id":"4ckrmjaeeb8mbpodbmo6bknpge","team_id":"","connection_id":"","omit_connection_id":""}, "seq": 40}"#;
+
+        let ws_event: WebSocketEvent =
+            serde_json::from_str(json).expect("Failed to parse WebSocket event");
+        assert!(
+            WebSocketManager::convert_pin_event(&ws_event, &mut PinStates::default()).is_none()
+        );
+    }
+
+    #[test]
+    fn test_pin_events_follow_pin_state() {
+        let event = |kind: &str, update_at: i64, is_pinned: bool| {
+            let post = serde_json::json!({
+                "id": "p1",
+                "create_at": 1000,
+                "update_at": update_at,
+                "edit_at": 0,
+                "delete_at": 0,
+                "is_pinned": is_pinned,
+                "user_id": "u1",
+                "channel_id": "c1",
+                "message": "hi",
+            });
+            serde_json::from_value::<WebSocketEvent>(serde_json::json!({
+                "event": kind,
+                "data": {"post": post.to_string()},
+                "broadcast": {"channel_id": "c1"},
+                "seq": 1,
+            }))
+            .unwrap()
+        };
+        let mut pins = PinStates::default();
+        let mut convert =
+            |ws_event: WebSocketEvent| WebSocketManager::convert_pin_event(&ws_event, &mut pins);
+
+        assert!(convert(event("posted", 1000, false)).is_none());
+        // Other updates that leave the pin state alone aren't pin events
+        assert!(convert(event("post_edited", 2000, false)).is_none());
+        assert!(matches!(
+            convert(event("post_edited", 3000, true)),
+            Some(PlatformEvent::PostPinned(_))
+        ));
+        assert!(convert(event("post_edited", 4000, true)).is_none());
+        assert!(matches!(
+            convert(event("post_edited", 5000, false)),
+            Some(PlatformEvent::PostUnpinned(_))
+        ));
+
+        // A post deleted and seen again is treated as new
+        assert!(convert(event("post_deleted", 6000, false)).is_none());
+        assert!(convert(event("post_edited", 7000, false)).is_none());
+    }
+
+    #[test]
+    fn test_pin_states_are_bounded() {
+        let mut pins = PinStates::default();
+        for i in 0..MAX_TRACKED_PINS + 10 {
+            pins.update(&i.to_string(), false);
+        }
+        assert_eq!(pins.pinned.len(), MAX_TRACKED_PINS);
+        assert_eq!(pins.order.len(), MAX_TRACKED_PINS);
+        assert_eq!(pins.update("0", true), None);
+        assert_eq!(
+            pins.update(&(MAX_TRACKED_PINS + 9).to_string(), true),
+            Some(false)
+        );
+    }
+
     #[test]
     fn test_parse_post_deleted_event() {
         // Real data from Mattermost WebSocket
@@ -1525,6 +2443,7 @@ mod tests {
             initial_reconnect_delay_ms: 500,
             max_reconnect_delay_ms: 30000,
             reconnect_backoff_multiplier: 1.5,
+            strict_schema: false,
         };
 
         assert_eq!(config.enable_auto_reconnect, false);
@@ -1583,6 +2502,7 @@ mod tests {
             initial_reconnect_delay_ms: 500,
             max_reconnect_delay_ms: 10000,
             reconnect_backoff_multiplier: 1.5,
+            strict_schema: false,
         };
 
         // Test with multiplier 1.5
@@ -1967,6 +2887,42 @@ mod tests {
         }
     }
 
+    #[test]
+    fn test_parse_unknown_event_as_raw() {
+        let json = r#"{
+            "event": "sidebar_category_updated",
+            "data": {
+                "updatedCategories": "[{\"id\":\"cat1\"}]"
+            },
+            "broadcast": {
+                "omit_users": null,
+                "user_id": "user1",
+                "channel_id": "",
+                "team_id": "team1",
+                "connection_id": "",
+                "omit_connection_id": ""
+            },
+            "seq": 51
+        }"#;
+
+        let ws_event: WebSocketEvent =
+            serde_json::from_str(json).expect("Failed to parse WebSocket event");
+        match WebSocketManager::convert_event(ws_event) {
+            Some(PlatformEvent::Raw {
+                event_type,
+                data,
+                channel_id,
+                team_id,
+            }) => {
+                assert_eq!(event_type, "sidebar_category_updated");
+                assert_eq!(data["updatedCategories"], r#"[{"id":"cat1"}]"#);
+                assert_eq!(channel_id, None);
+                assert_eq!(team_id.as_deref(), Some("team1"));
+            }
+            other => panic!("Expected Raw event, got {:?}", other),
+        }
+    }
+
     #[test]
     fn test_parse_channel_viewed_event() {
         let json = r#"{
@@ -2672,11 +3628,13 @@ mod tests {
             status,
             seq_reply,
             error,
+            data,
         }) = platform_event
         {
             assert_eq!(status, "OK");
             assert_eq!(seq_reply, 42);
             assert!(error.is_none());
+            assert!(data.is_none());
         } else {
             panic!("Expected Response event");
         }
@@ -0,0 +1,171 @@
+//! Parsing of message links (permalinks) across platforms
+//!
+//! Bots often need to act on links that users paste into a conversation.
+//! `parse_message_link` recognizes the link formats of every supported
+//! backend without needing a connection to the server.
+
+use serde::{Deserialize, Serialize};
+use url::Url;
+
+use crate::error::{Error, Result};
+
+/// The parts of a link that points to a single message
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize)]
+pub struct MessageLink {
+    /// Platform the link belongs to (e.g. "mattermost", "slack")
+    pub platform: String,
+    /// Base URL of the server, including any subpath the server is hosted under
+    pub server_url: String,
+    /// Team or workspace name, when the link contains it
+    pub team: Option<String>,
+    /// Channel ID, when the link contains it
+    pub channel: Option<String>,
+    /// ID of the linked message
+    pub message_id: String,
+}
+
+/// Parse a message link into its parts
+///
+/// Supported formats:
+/// * Mattermost: `https://host[/subpath]/{team}/pl/{post_id}`
+/// * Slack: `https://{workspace}.slack.com/archives/{channel}/p{timestamp}`
+///
+/// # Errors
+/// Returns InvalidArgument if the URL is malformed or not a recognized message link
+pub fn parse_message_link(link: &str) -> Result<MessageLink> {
+    let url = Url::parse(link.trim())
+        .map_err(|e| Error::invalid_argument(format!("Invalid message link: {e}")))?;
+
+    if url.scheme() != "http" && url.scheme() != "https" {
+        return Err(Error::invalid_argument(format!(
+            "Invalid message link: unsupported scheme {}",
+            url.scheme()
+        )));
+    }
+
+    parse_slack_link(&url)
+        .or_else(|| parse_mattermost_link(&url))
+        .ok_or_else(|| Error::invalid_argument(format!("Not a recognized message link: {link}")))
+}
+
+/// Build the server URL from the link's origin and the given path segments
+fn server_url(url: &Url, subpath: &[&str]) -> String {
+    let origin = url.origin().ascii_serialization();
+    if subpath.is_empty() {
+        origin
+    } else {
+        format!("{origin}/{}", subpath.join("/"))
+    }
+}
+
+fn parse_mattermost_link(url: &Url) -> Option<MessageLink> {
+    let segments: Vec<&str> = url.path_segments()?.filter(|s| !s.is_empty()).collect();
+
+    // The post ID follows "pl" and the team name precedes it; anything before
+    // the team is the subpath the server is hosted under
+    let pl = segments.iter().rposition(|s| *s == "pl")?;
+    if pl == 0 || pl + 2 != segments.len() {
+        return None;
+    }
+
+    let post_id = segments[pl + 1];
+    if !is_mattermost_id(post_id) {
+        return None;
+    }
+
+    Some(MessageLink {
+        platform: "mattermost".to_string(),
+        server_url: server_url(url, &segments[..pl - 1]),
+        team: Some(segments[pl - 1].to_string()),
+        channel: None,
+        message_id: post_id.to_string(),
+    })
+}
+
+/// Mattermost IDs are 26 lowercase base32 characters
+fn is_mattermost_id(id: &str) -> bool {
+    id.len() == 26
+        && id
+            .bytes()
+            .all(|b| b.is_ascii_lowercase() || b.is_ascii_digit())
+}
+
+fn parse_slack_link(url: &Url) -> Option<MessageLink> {
+    let host = url.host_str()?;
+    let workspace = host.strip_suffix(".slack.com")?;
+
+    let segments: Vec<&str> = url.path_segments()?.filter(|s| !s.is_empty()).collect();
+    let [archives, channel, message] = segments.as_slice() else {
+        return None;
+    };
+    if *archives != "archives" {
+        return None;
+    }
+
+    // "p1700000000123456" encodes the message timestamp "1700000000.123456"
+    let digits = message.strip_prefix('p')?;
+    if digits.len() <= 6 || !digits.bytes().all(|b| b.is_ascii_digit()) {
+        return None;
+    }
+    let (seconds, micros) = digits.split_at(digits.len() - 6);
+
+    Some(MessageLink {
+        platform: "slack".to_string(),
+        server_url: server_url(url, &[]),
+        team: Some(workspace.to_string()),
+        channel: Some(channel.to_string()),
+        message_id: format!("{seconds}.{micros}"),
+    })
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_parse_mattermost_link() {
+        let link =
+            parse_message_link("https://chat.example.com/devops/pl/8xk3m1qzfpbhz8n4gy7r1ctw5e")
+                .unwrap();
+        assert_eq!(link.platform, "mattermost");
+        assert_eq!(link.server_url, "https://chat.example.com");
+        assert_eq!(link.team.as_deref(), Some("devops"));
+        assert_eq!(link.channel, None);
+        assert_eq!(link.message_id, "8xk3m1qzfpbhz8n4gy7r1ctw5e");
+
+        let link = parse_message_link(
+            "https://example.com/mattermost/devops/pl/8xk3m1qzfpbhz8n4gy7r1ctw5e",
+        )
+        .unwrap();
+        assert_eq!(link.server_url, "https://example.com/mattermost");
+        assert_eq!(link.team.as_deref(), Some("devops"));
+    }
+
+    #[test]
+    fn test_parse_slack_link() {
+        let link = parse_message_link(
+            "https://acme.slack.com/archives/C024BE91L/p1700000000123456?thread_ts=1699999999.000100",
+        )
+        .unwrap();
+        assert_eq!(link.platform, "slack");
+        assert_eq!(link.server_url, "https://acme.slack.com");
+        assert_eq!(link.team.as_deref(), Some("acme"));
+        assert_eq!(link.channel.as_deref(), Some("C024BE91L"));
+        assert_eq!(link.message_id, "1700000000.123456");
+    }
+
+    #[test]
+    fn test_parse_unrecognized_links() {
+        for link in [
+            "not a url",
+            "ftp://chat.example.com/devops/pl/8xk3m1qzfpbhz8n4gy7r1ctw5e",
+            "https://chat.example.com/devops/channels/town-square",
+            "https://chat.example.com/pl/8xk3m1qzfpbhz8n4gy7r1ctw5e",
+            "https://chat.example.com/devops/pl/short",
+            "https://acme.slack.com/archives/C024BE91L",
+        ] {
+            let err = parse_message_link(link).unwrap_err();
+            assert_eq!(err.code, crate::error::ErrorCode::InvalidArgument, "{link}");
+        }
+    }
+}
@@ -2,9 +2,11 @@
 ///
 /// Each platform module provides an adapter that implements the core
 /// communication interface for that specific service.
+mod message_link;
 mod platform_trait;
 
 pub mod mattermost;
 
 // Re-export platform trait and related types
-pub use platform_trait::{Platform, PlatformConfig, PlatformEvent};
+pub use message_link::{parse_message_link, MessageLink};
+pub use platform_trait::{EventFilter, Platform, PlatformConfig, PlatformEvent, SchemaIssue};
@@ -1,8 +1,13 @@
 //! Platform trait defining the interface all platform adapters must implement
 
 use crate::error::{Error, Result};
+use crate::types::channel::plan_member_sync;
 use crate::types::user::UserStatus;
-use crate::types::{Channel, ConnectionInfo, Message, PlatformCapabilities, Team, User};
+use crate::types::{
+    Channel, ChannelBookmark, ConnectionInfo, MemberSyncFailure, MemberSyncOptions,
+    MemberSyncResult, Message, NewChannelBookmark, PlatformCapabilities, SidebarCategory, Team,
+    User,
+};
 use async_trait::async_trait;
 use std::collections::HashMap;
 
@@ -17,6 +22,49 @@ pub struct PlatformConfig {
     /// Only applicable for platforms that support organizational hierarchies
     /// (check PlatformCapabilities.has_workspaces)
     pub team_id: Option<String>,
+    /// Optional proxy URL (http://, socks5:// or socks5h://) for REST and
+    /// WebSocket connections
+    pub proxy_url: Option<String>,
+    /// Comma-separated hosts to reach without the proxy, as in NO_PROXY
+    pub no_proxy: Option<String>,
+    /// Extra CA certificate(s) to trust, as PEM text or the path of a PEM file
+    pub ca_cert: Option<String>,
+    /// Client certificate chain for mutual TLS, as PEM text or a file path
+    pub client_cert: Option<String>,
+    /// Private key of the client certificate, as PEM text or a file path
+    pub client_key: Option<String>,
+    /// Accept any server certificate; only for test servers
+    pub insecure_skip_verify: bool,
+    /// Limit on establishing a connection, in milliseconds
+    pub connect_timeout_ms: Option<u64>,
+    /// Limit on a whole request, including the response, in milliseconds
+    pub request_timeout_ms: Option<u64>,
+    /// Limit on opening the event connection (e.g. a WebSocket handshake), in milliseconds
+    pub handshake_timeout_ms: Option<u64>,
+    /// Event connection silence after which it is reconnected, in milliseconds
+    pub idle_timeout_ms: Option<u64>,
+    /// Retries of a request rejected with HTTP 429 (0 disables retrying)
+    pub rate_limit_max_retries: Option<u32>,
+    /// Longest wait before retrying a rate limited request, in milliseconds
+    pub rate_limit_max_wait_ms: Option<u64>,
+    /// Tries of a request that failed for a transient reason, including the
+    /// first (1 disables retrying)
+    pub retry_max_attempts: Option<u32>,
+    /// Wait before the first retry, in milliseconds
+    pub retry_initial_backoff_ms: Option<u64>,
+    /// Longest wait between retries, in milliseconds
+    pub retry_max_backoff_ms: Option<u64>,
+    /// Growth of the wait with each retry
+    pub retry_backoff_multiplier: Option<f64>,
+    /// Fraction of each wait that is randomized, from 0 to 1
+    pub retry_jitter: Option<f64>,
+    /// Failures that are retried: "network", "timeout" and/or "server_error"
+    pub retry_on: Option<Vec<String>>,
+    /// Headers added to every REST and WebSocket request, e.g. for an
+    /// authenticating proxy in front of the server
+    pub headers: HashMap<String, String>,
+    /// User-Agent of REST and WebSocket requests, replacing the library's own
+    pub user_agent: Option<String>,
     /// Additional platform-specific configuration
     pub extra: HashMap<String, String>,
 }
@@ -28,6 +76,26 @@ impl PlatformConfig {
             server: server.into(),
             credentials: HashMap::new(),
             team_id: None,
+            proxy_url: None,
+            no_proxy: None,
+            ca_cert: None,
+            client_cert: None,
+            client_key: None,
+            insecure_skip_verify: false,
+            connect_timeout_ms: None,
+            request_timeout_ms: None,
+            handshake_timeout_ms: None,
+            idle_timeout_ms: None,
+            rate_limit_max_retries: None,
+            rate_limit_max_wait_ms: None,
+            retry_max_attempts: None,
+            retry_initial_backoff_ms: None,
+            retry_max_backoff_ms: None,
+            retry_backoff_multiplier: None,
+            retry_jitter: None,
+            retry_on: None,
+            headers: HashMap::new(),
+            user_agent: None,
             extra: HashMap::new(),
         }
     }
@@ -44,6 +112,49 @@ impl PlatformConfig {
         self
     }
 
+    /// Connect through a proxy
+    pub fn with_proxy(mut self, proxy_url: impl Into<String>) -> Self {
+        self.proxy_url = Some(proxy_url.into());
+        self
+    }
+
+    /// Set hosts to reach without the proxy
+    pub fn with_no_proxy(mut self, no_proxy: impl Into<String>) -> Self {
+        self.no_proxy = Some(no_proxy.into());
+        self
+    }
+
+    /// Trust an extra CA certificate (PEM text or file path)
+    pub fn with_ca_cert(mut self, ca_cert: impl Into<String>) -> Self {
+        self.ca_cert = Some(ca_cert.into());
+        self
+    }
+
+    /// Authenticate with a client certificate and key (PEM text or file paths)
+    pub fn with_client_cert(mut self, cert: impl Into<String>, key: impl Into<String>) -> Self {
+        self.client_cert = Some(cert.into());
+        self.client_key = Some(key.into());
+        self
+    }
+
+    /// Add a header to every request
+    pub fn with_header(mut self, name: impl Into<String>, value: impl Into<String>) -> Self {
+        self.headers.insert(name.into(), value.into());
+        self
+    }
+
+    /// Replace the User-Agent of requests
+    pub fn with_user_agent(mut self, user_agent: impl Into<String>) -> Self {
+        self.user_agent = Some(user_agent.into());
+        self
+    }
+
+    /// Skip server certificate verification; only for test servers
+    pub fn with_insecure_skip_verify(mut self) -> Self {
+        self.insecure_skip_verify = true;
+        self
+    }
+
     /// Add extra configuration
     pub fn with_extra(mut self, key: impl Into<String>, value: impl Into<String>) -> Self {
         self.extra.insert(key.into(), value.into());
@@ -63,6 +174,10 @@ pub enum PlatformEvent {
         message_id: String,
         channel_id: String,
     },
+    /// A message was pinned to its channel
+    PostPinned(Message),
+    /// A message was unpinned from its channel
+    PostUnpinned(Message),
     /// A user's status changed
     UserStatusChanged {
         user_id: String,
@@ -73,15 +188,28 @@ pub enum PlatformEvent {
     /// A channel was created
     ChannelCreated(Channel),
     /// A channel was updated
-    ChannelUpdated(Channel),
+    ///
+    /// `changes` holds the old and new display name, topic and purpose when
+    /// the channel's previous state was known, and is None otherwise.
+    ChannelUpdated {
+        channel: Channel,
+        changes: Option<crate::types::ChannelChanges>,
+    },
     /// A channel was deleted
     ChannelDeleted { channel_id: String },
     /// User joined a channel
     UserJoinedChannel { user_id: String, channel_id: String },
     /// User left a channel
     UserLeftChannel { user_id: String, channel_id: String },
-    /// Connection state changed
-    ConnectionStateChanged(crate::types::connection::ConnectionState),
+    /// Connection state changed, with the cause and reconnection progress
+    ConnectionStateChanged(crate::types::ConnectionStateChange),
+    /// Events were lost between two sequence numbers; the client should
+    /// resync the state it tracks. `received_seq` is 0 when the server
+    /// started a new connection instead of resuming the old one.
+    GapDetected {
+        expected_seq: i64,
+        received_seq: i64,
+    },
     /// A reaction was added to a message
     ReactionAdded {
         message_id: String,
@@ -172,15 +300,166 @@ pub enum PlatformEvent {
     /// User preferences were deleted
     PreferencesDeleted { category: String, name: String },
     /// WebSocket action response
+    ///
+    /// `data` holds the action's result, e.g. the statuses requested by
+    /// `request_all_statuses()`.
     Response {
         status: String,
         seq_reply: i64,
         error: Option<String>,
+        data: Option<serde_json::Value>,
     },
     /// Dialog was opened
     DialogOpened { dialog_id: String },
     /// Role was updated
     RoleUpdated { role_id: String },
+    /// An incoming event payload did not match its expected schema
+    ///
+    /// Only emitted when strict event validation is enabled.
+    SchemaMismatch {
+        event_type: String,
+        issues: Vec<SchemaIssue>,
+    },
+    /// An event of a type the library doesn't model, such as a plugin event
+    ///
+    /// `event_type` is the platform's name for it and `data` its payload as
+    /// sent. `channel_id` and `team_id` are set when the platform scoped the
+    /// event to a channel or team.
+    Raw {
+        event_type: String,
+        data: serde_json::Value,
+        channel_id: Option<String>,
+        team_id: Option<String>,
+    },
+    /// The server rate limited a request
+    ///
+    /// `endpoint` is the method and path, e.g. "GET /users/me", and `attempt`
+    /// counts the rejections of that request. `retry_after_ms` is the wait
+    /// before it is retried, or None when it was given up and failed with
+    /// ErrorCode::RateLimited.
+    RateLimited {
+        endpoint: String,
+        attempt: u32,
+        retry_after_ms: Option<u64>,
+    },
+}
+
+/// A single mismatch between an event payload and its expected schema
+#[derive(Debug, Clone, PartialEq, Eq, serde::Serialize)]
+pub struct SchemaIssue {
+    /// Location of the offending field (e.g. "data.post.create_at")
+    pub path: String,
+    /// What is wrong with the field
+    pub problem: String,
+}
+
+impl SchemaIssue {
+    /// Create a new schema issue
+    pub fn new(path: impl Into<String>, problem: impl Into<String>) -> Self {
+        Self {
+            path: path.into(),
+            problem: problem.into(),
+        }
+    }
+}
+
+impl PlatformEvent {
+    /// ID of the channel the event happened in, if it is scoped to one
+    pub fn channel_id(&self) -> Option<&str> {
+        match self {
+            PlatformEvent::MessagePosted(message)
+            | PlatformEvent::MessageUpdated(message)
+            | PlatformEvent::PostPinned(message)
+            | PlatformEvent::PostUnpinned(message) => Some(&message.channel_id),
+            PlatformEvent::ChannelCreated(channel)
+            | PlatformEvent::ChannelUpdated { channel, .. } => Some(&channel.id),
+            PlatformEvent::MessageDeleted { channel_id, .. }
+            | PlatformEvent::UserTyping { channel_id, .. }
+            | PlatformEvent::ChannelDeleted { channel_id }
+            | PlatformEvent::UserJoinedChannel { channel_id, .. }
+            | PlatformEvent::UserLeftChannel { channel_id, .. }
+            | PlatformEvent::ReactionAdded { channel_id, .. }
+            | PlatformEvent::ReactionRemoved { channel_id, .. }
+            | PlatformEvent::DirectChannelAdded { channel_id }
+            | PlatformEvent::GroupChannelAdded { channel_id }
+            | PlatformEvent::EphemeralMessage { channel_id, .. }
+            | PlatformEvent::ChannelViewed { channel_id, .. }
+            | PlatformEvent::ThreadUpdated { channel_id, .. }
+            | PlatformEvent::ThreadReadChanged { channel_id, .. }
+            | PlatformEvent::ThreadFollowChanged { channel_id, .. }
+            | PlatformEvent::PostUnread { channel_id, .. }
+            | PlatformEvent::ChannelConverted { channel_id }
+            | PlatformEvent::ChannelMemberUpdated { channel_id, .. }
+            | PlatformEvent::MemberRoleUpdated { channel_id, .. } => Some(channel_id),
+            PlatformEvent::Raw { channel_id, .. } => channel_id.as_deref(),
+            _ => None,
+        }
+    }
+
+    /// ID of the team the event happened in, for events about a team itself
+    ///
+    /// Channel-scoped events return None; their team is the channel's team.
+    pub fn team_id(&self) -> Option<&str> {
+        match self {
+            PlatformEvent::AddedToTeam { team_id, .. }
+            | PlatformEvent::LeftTeam { team_id, .. }
+            | PlatformEvent::TeamDeleted { team_id }
+            | PlatformEvent::TeamUpdated { team_id } => Some(team_id),
+            PlatformEvent::Raw { team_id, .. } => team_id.as_deref(),
+            _ => None,
+        }
+    }
+
+    /// Whether the event is about the event connection itself rather than
+    /// the server's content, such as a state change or a sequence gap
+    pub fn is_connection_event(&self) -> bool {
+        matches!(
+            self,
+            PlatformEvent::ConnectionStateChanged(_)
+                | PlatformEvent::GapDetected { .. }
+                | PlatformEvent::SchemaMismatch { .. }
+                | PlatformEvent::Response { .. }
+                | PlatformEvent::RateLimited { .. }
+        )
+    }
+}
+
+/// Scope of an event subscription
+///
+/// An event is delivered if it happened in one of `channel_ids`, or in one
+/// of `team_ids` (including the team's channels). Events outside any channel
+/// or team, such as status changes, are dropped unless the filter is empty.
+/// Connection events are always delivered.
+#[derive(Debug, Clone, Default, PartialEq, Eq, serde::Deserialize)]
+#[serde(default)]
+pub struct EventFilter {
+    pub channel_ids: Vec<String>,
+    pub team_ids: Vec<String>,
+}
+
+impl EventFilter {
+    /// Whether the filter lets every event through
+    pub fn is_empty(&self) -> bool {
+        self.channel_ids.is_empty() && self.team_ids.is_empty()
+    }
+
+    /// Whether the event is in scope
+    ///
+    /// `channel_team_id` is the team of the event's channel, if known; it is
+    /// only consulted when the channel itself isn't listed.
+    pub fn matches(&self, event: &PlatformEvent, channel_team_id: Option<&str>) -> bool {
+        if self.is_empty() || event.is_connection_event() {
+            return true;
+        }
+        let listed = |ids: &[String], id: &str| ids.iter().any(|candidate| candidate == id);
+        if let Some(channel_id) = event.channel_id() {
+            return listed(&self.channel_ids, channel_id)
+                || channel_team_id.is_some_and(|team_id| listed(&self.team_ids, team_id));
+        }
+        event
+            .team_id()
+            .is_some_and(|team_id| listed(&self.team_ids, team_id))
+    }
 }
 
 /// Trait that all platform adapters must implement
@@ -210,6 +489,45 @@ pub trait Platform: Send + Sync {
     /// Disconnect from the platform
     async fn disconnect(&mut self) -> Result<()>;
 
+    /// Capture the session so a restarted process can resume it
+    ///
+    /// # Returns
+    /// The session token, active team and real-time event position
+    ///
+    /// # Notes
+    /// `disconnect()` ends the session, so a process that wants to resume
+    /// later should save its state and exit without disconnecting.
+    async fn save_state(&self) -> Result<crate::types::SessionState> {
+        Err(crate::error::Error::unsupported(
+            "Session resumption not supported by this platform",
+        ))
+    }
+
+    /// Reconnect using a session captured by `save_state`, instead of `connect`
+    ///
+    /// # Arguments
+    /// * `state` - The saved session
+    /// * `config` - Network settings (proxy, TLS, timeouts, headers, retry and
+    ///   rate limit policies) applied as `connect` applies them; credentials
+    ///   and the team are taken from `state` instead
+    ///
+    /// # Returns
+    /// Connection information on success
+    ///
+    /// # Errors
+    /// Returns `ErrorCode::AuthenticationFailed` if the session is no longer valid;
+    /// callers should fall back to `connect`.
+    async fn restore_state(
+        &mut self,
+        state: &crate::types::SessionState,
+        config: &PlatformConfig,
+    ) -> Result<ConnectionInfo> {
+        let _ = (state, config);
+        Err(crate::error::Error::unsupported(
+            "Session resumption not supported by this platform",
+        ))
+    }
+
     /// Get current connection information
     ///
     /// Returns None if not connected
@@ -257,6 +575,31 @@ pub trait Platform: Send + Sync {
     /// Get details about the currently authenticated user
     async fn get_current_user(&self) -> Result<User>;
 
+    /// Get a user's profile image
+    ///
+    /// # Arguments
+    /// * `user_id` - The ID of the user
+    ///
+    /// # Returns
+    /// The raw image bytes
+    async fn get_user_avatar(&self, user_id: &str) -> Result<Vec<u8>> {
+        let _ = user_id;
+        Err(crate::error::Error::unsupported(
+            "User avatars not supported by this platform",
+        ))
+    }
+
+    /// Set the current user's profile image
+    ///
+    /// # Arguments
+    /// * `image_data` - The image contents
+    async fn set_my_avatar(&self, image_data: Vec<u8>) -> Result<()> {
+        let _ = image_data;
+        Err(crate::error::Error::unsupported(
+            "User avatars not supported by this platform",
+        ))
+    }
+
     /// Create a direct message channel with another user
     ///
     /// # Arguments
@@ -332,6 +675,22 @@ pub trait Platform: Send + Sync {
         ))
     }
 
+    /// Restore (unarchive) a channel that was deleted with `delete_channel`
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the archived channel
+    ///
+    /// # Returns
+    /// Result containing the restored Channel or an error
+    ///
+    /// # Default Implementation
+    /// Returns `ErrorCode::Unsupported` by default. Platforms should override this if they support restoring channels.
+    async fn restore_channel(&self, _channel_id: &str) -> Result<Channel> {
+        Err(Error::unsupported(
+            "Channel restore not supported by this platform",
+        ))
+    }
+
     /// Get all teams/workspaces the user belongs to
     ///
     /// # Returns
@@ -355,6 +714,56 @@ pub trait Platform: Send + Sync {
     /// Check `capabilities().has_workspaces` before calling.
     async fn get_team(&self, team_id: &str) -> Result<Team>;
 
+    /// Create a new team/workspace
+    ///
+    /// # Arguments
+    /// * `team` - Name, display name and settings of the new team
+    ///
+    /// # Returns
+    /// The created team
+    ///
+    /// # Default Implementation
+    /// Returns `ErrorCode::Unsupported` by default. Platforms should override this if they support team creation.
+    async fn create_team(&self, team: &crate::types::NewTeam) -> Result<Team> {
+        let _ = team;
+        Err(Error::unsupported(
+            "Team creation not supported by this platform",
+        ))
+    }
+
+    /// Update a team's settings (partial update)
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team to update
+    /// * `patch` - The fields to change; `None` fields are left unchanged
+    ///
+    /// # Returns
+    /// The updated team
+    ///
+    /// # Default Implementation
+    /// Returns `ErrorCode::Unsupported` by default. Platforms should override this if they support team updates.
+    async fn update_team(&self, team_id: &str, patch: &crate::types::TeamPatch) -> Result<Team> {
+        let _ = (team_id, patch);
+        Err(Error::unsupported(
+            "Team updates not supported by this platform",
+        ))
+    }
+
+    /// Set a team's icon
+    ///
+    /// # Arguments
+    /// * `team_id` - The ID of the team
+    /// * `image_data` - The image contents
+    ///
+    /// # Default Implementation
+    /// Returns `ErrorCode::Unsupported` by default. Platforms should override this if they support team icons.
+    async fn set_team_icon(&self, team_id: &str, image_data: Vec<u8>) -> Result<()> {
+        let _ = (team_id, image_data);
+        Err(Error::unsupported(
+            "Team icons not supported by this platform",
+        ))
+    }
+
     /// Set the current user's status
     ///
     /// # Arguments
@@ -384,19 +793,77 @@ pub trait Platform: Send + Sync {
     /// Events should be delivered through the event callback.
     async fn subscribe_events(&mut self) -> Result<()>;
 
+    /// Subscribe to real-time events within a scope
+    ///
+    /// Like `subscribe_events()`, but events outside the filter are dropped
+    /// before they are returned by `poll_event()`. An empty filter delivers
+    /// every event.
+    async fn subscribe_events_filtered(&mut self, filter: EventFilter) -> Result<()> {
+        let _ = filter;
+        Err(crate::error::Error::unsupported(
+            "Filtered event subscriptions not supported by this platform",
+        ))
+    }
+
     /// Unsubscribe from real-time events
     async fn unsubscribe_events(&mut self) -> Result<()>;
 
+    /// Enable or disable strict validation of incoming event payloads
+    ///
+    /// When enabled, payloads that do not match the schema the event
+    /// converters expect are reported as `PlatformEvent::SchemaMismatch`
+    /// events. Takes effect on the next `subscribe_events()` call.
+    async fn set_strict_event_validation(&self, enabled: bool) -> Result<()> {
+        let _ = enabled;
+        Err(crate::error::Error::unsupported(
+            "Strict event validation not supported by this platform",
+        ))
+    }
+
     /// Poll for the next event (if available)
     ///
     /// This is a non-blocking check for new events.
-    /// Returns None if no events are available.
-    async fn poll_event(&mut self) -> Result<Option<PlatformEvent>>;
+    /// Returns None if no events are available. It takes `&self` so that
+    /// events keep flowing while other calls, such as a long upload, are in
+    /// progress.
+    async fn poll_event(&self) -> Result<Option<PlatformEvent>>;
+
+    /// Sequence number of the event last returned by `poll_event()`
+    ///
+    /// With several threads polling, it may belong to another thread's event.
+    ///
+    /// Returns None if the platform doesn't number its events or the event
+    /// was generated by the library rather than received from the server.
+    fn last_polled_sequence(&self) -> Option<i64> {
+        None
+    }
 
     // ========================================================================
     // Extended Platform Methods
     // ========================================================================
 
+    /// Send a message with options such as a thread or an identity override
+    ///
+    /// # Arguments
+    /// * `channel_id` - The channel ID
+    /// * `text` - The message text
+    /// * `options` - The send options; see `SendMessageOptions` for when
+    ///   identity overrides are honoured
+    ///
+    /// # Returns
+    /// The created message
+    async fn send_message_with_options(
+        &self,
+        channel_id: &str,
+        text: &str,
+        options: &crate::types::SendMessageOptions,
+    ) -> Result<Message> {
+        let _ = (channel_id, text, options);
+        Err(crate::error::Error::unsupported(
+            "Message options not supported by this platform",
+        ))
+    }
+
     /// Send a reply to a message (threaded conversation)
     ///
     /// # Arguments
@@ -480,6 +947,43 @@ pub trait Platform: Send + Sync {
         ))
     }
 
+    /// Get one page of message search results
+    ///
+    /// # Arguments
+    /// * `query` - The search query
+    /// * `page` - Zero-based page number
+    /// * `per_page` - Number of results per page
+    ///
+    /// # Returns
+    /// The page of matching messages; fewer than `per_page` means it is the last page
+    async fn search_messages_page(
+        &self,
+        query: &str,
+        page: u32,
+        per_page: u32,
+    ) -> Result<Vec<Message>> {
+        if page == 0 {
+            return self.search_messages(query, per_page as usize).await;
+        }
+        Err(crate::error::Error::unsupported(
+            "Paginated message search not supported by this platform",
+        ))
+    }
+
+    /// Get recent messages that mention the current user, newest first
+    ///
+    /// Searches for the user's mention keys the way the platform's own
+    /// "recent mentions" view does.
+    ///
+    /// # Arguments
+    /// * `limit` - Maximum number of results
+    async fn get_recent_mentions(&self, limit: usize) -> Result<Vec<Message>> {
+        let _ = limit;
+        Err(crate::error::Error::unsupported(
+            "Message search not supported by this platform",
+        ))
+    }
+
     /// Get messages before a specific message (pagination)
     ///
     /// # Arguments
@@ -522,6 +1026,24 @@ pub trait Platform: Send + Sync {
         ))
     }
 
+    /// Get messages created, edited or deleted since a point in time
+    ///
+    /// Meant for catching a channel up after a reconnect. Deleted messages
+    /// are included so callers can drop them from their local copy.
+    ///
+    /// # Arguments
+    /// * `channel_id` - The channel ID
+    /// * `since` - Timestamp to get changes since (Unix milliseconds)
+    ///
+    /// # Returns
+    /// List of messages, oldest first
+    async fn get_messages_since(&self, channel_id: &str, since: u64) -> Result<Vec<Message>> {
+        let _ = (channel_id, since);
+        Err(crate::error::Error::unsupported(
+            "Incremental message sync not supported by this platform",
+        ))
+    }
+
     /// Add a reaction to a message
     ///
     /// # Arguments
@@ -597,6 +1119,48 @@ pub trait Platform: Send + Sync {
         ))
     }
 
+    /// Get a permanent link to a message
+    ///
+    /// # Arguments
+    /// * `message_id` - The ID of the message to link to
+    ///
+    /// # Returns
+    /// A URL that opens the message in the platform's own clients
+    async fn get_permalink(&self, message_id: &str) -> Result<String> {
+        let _ = message_id;
+        Err(crate::error::Error::unsupported(
+            "Permalinks not supported by this platform",
+        ))
+    }
+
+    /// Resolve a permalink back to the message it points to
+    ///
+    /// # Arguments
+    /// * `permalink` - A permalink URL as produced by `get_permalink` or copied from a client
+    ///
+    /// # Returns
+    /// The linked message and the channel it was posted in
+    async fn resolve_permalink(&self, permalink: &str) -> Result<(Message, Channel)> {
+        let _ = permalink;
+        Err(crate::error::Error::unsupported(
+            "Permalinks not supported by this platform",
+        ))
+    }
+
+    /// Get the preview metadata of a web page, as the platform's own clients show it
+    ///
+    /// # Arguments
+    /// * `url` - An absolute http or https URL
+    ///
+    /// # Returns
+    /// The page's OpenGraph metadata
+    async fn get_link_metadata(&self, url: &str) -> Result<crate::types::LinkMetadata> {
+        let _ = url;
+        Err(crate::error::Error::unsupported(
+            "Link previews not supported by this platform",
+        ))
+    }
+
     /// Get a list of custom emojis available on the platform
     ///
     /// # Arguments
@@ -617,70 +1181,428 @@ pub trait Platform: Send + Sync {
         ))
     }
 
-    /// Get a channel by name
+    /// Create a custom emoji
     ///
     /// # Arguments
-    /// * `team_id` - The team ID (required for platforms with workspaces)
-    /// * `channel_name` - The channel name
+    /// * `name` - The name of the emoji (without colons)
+    /// * `image_path` - Path to the emoji image
     ///
     /// # Returns
-    /// The channel
-    async fn get_channel_by_name(&self, team_id: &str, channel_name: &str) -> Result<Channel> {
-        let _ = (team_id, channel_name);
+    /// The created emoji
+    async fn create_emoji(
+        &self,
+        name: &str,
+        image_path: &std::path::Path,
+    ) -> Result<crate::types::Emoji> {
+        let _ = (name, image_path);
         Err(crate::error::Error::unsupported(
-            "Get channel by name not supported by this platform",
+            "Custom emojis not supported by this platform",
         ))
     }
 
-    /// Create a group direct message channel
+    /// Delete a custom emoji
     ///
     /// # Arguments
-    /// * `user_ids` - List of user IDs to include in the group
-    ///
-    /// # Returns
-    /// The created group channel
-    ///
-    /// # Notes
-    /// Not all platforms support group messages. Check `capabilities().supports_group_messages` first.
-    async fn create_group_channel(&self, user_ids: Vec<String>) -> Result<Channel> {
-        let _ = user_ids;
+    /// * `emoji_id` - The ID of the emoji
+    async fn delete_emoji(&self, emoji_id: &str) -> Result<()> {
+        let _ = emoji_id;
         Err(crate::error::Error::unsupported(
-            "Group channels not supported by this platform",
+            "Custom emojis not supported by this platform",
         ))
     }
 
-    /// Add a user to a channel
+    /// Get a custom emoji by name
     ///
     /// # Arguments
-    /// * `channel_id` - The channel ID
-    /// * `user_id` - The user ID to add
-    async fn add_channel_member(&self, channel_id: &str, user_id: &str) -> Result<()> {
-        let _ = (channel_id, user_id);
+    /// * `name` - The emoji name, with or without colons
+    ///
+    /// # Returns
+    /// The emoji; a NotFound error if no custom emoji has that name
+    async fn get_emoji_by_name(&self, name: &str) -> Result<crate::types::Emoji> {
+        let _ = name;
         Err(crate::error::Error::unsupported(
-            "Channel member management not supported by this platform",
+            "Custom emojis not supported by this platform",
         ))
     }
 
-    /// Remove a user from a channel
+    /// Suggest custom emojis whose names start with a prefix, for :emoji: completion
     ///
     /// # Arguments
-    /// * `channel_id` - The channel ID
-    /// * `user_id` - The user ID to remove
-    async fn remove_channel_member(&self, channel_id: &str, user_id: &str) -> Result<()> {
-        let _ = (channel_id, user_id);
+    /// * `prefix` - The typed start of the name; a leading colon is ignored
+    ///
+    /// # Returns
+    /// Matching custom emojis. Standard Unicode emojis are not included.
+    async fn autocomplete_emoji(&self, prefix: &str) -> Result<Vec<crate::types::Emoji>> {
+        let _ = prefix;
         Err(crate::error::Error::unsupported(
-            "Channel member management not supported by this platform",
+            "Custom emojis not supported by this platform",
         ))
     }
 
-    /// Get a user by username
+    /// Download the image of a custom emoji
     ///
     /// # Arguments
-    /// * `username` - The username
+    /// * `emoji_id` - The ID of the emoji
     ///
     /// # Returns
-    /// The user
-    async fn get_user_by_username(&self, username: &str) -> Result<User> {
+    /// The raw image bytes
+    async fn get_emoji_image(&self, emoji_id: &str) -> Result<Vec<u8>> {
+        let _ = emoji_id;
+        Err(crate::error::Error::unsupported(
+            "Custom emojis not supported by this platform",
+        ))
+    }
+
+    /// Get a channel by name
+    ///
+    /// # Arguments
+    /// * `team_id` - The team ID (required for platforms with workspaces)
+    /// * `channel_name` - The channel name
+    ///
+    /// # Returns
+    /// The channel
+    async fn get_channel_by_name(&self, team_id: &str, channel_name: &str) -> Result<Channel> {
+        let _ = (team_id, channel_name);
+        Err(crate::error::Error::unsupported(
+            "Get channel by name not supported by this platform",
+        ))
+    }
+
+    /// Get a page of a team's public channels, including ones the user has not joined
+    ///
+    /// # Arguments
+    /// * `team_id` - The team ID
+    /// * `page` - The page number to retrieve (0-indexed)
+    /// * `per_page` - Number of channels per page
+    ///
+    /// # Returns
+    /// The channels on the page; fewer than `per_page` means the last page
+    async fn get_public_channels(
+        &self,
+        team_id: &str,
+        page: u32,
+        per_page: u32,
+    ) -> Result<Vec<Channel>> {
+        let _ = (team_id, page, per_page);
+        Err(crate::error::Error::unsupported(
+            "Browsing channels not supported by this platform",
+        ))
+    }
+
+    /// Get a page of a team's archived (deleted) channels
+    ///
+    /// # Arguments
+    /// * `team_id` - The team ID
+    /// * `page` - The page number to retrieve (0-indexed)
+    /// * `per_page` - Number of channels per page
+    ///
+    /// # Returns
+    /// The channels on the page; fewer than `per_page` means the last page
+    async fn get_archived_channels(
+        &self,
+        team_id: &str,
+        page: u32,
+        per_page: u32,
+    ) -> Result<Vec<Channel>> {
+        let _ = (team_id, page, per_page);
+        Err(crate::error::Error::unsupported(
+            "Browsing archived channels not supported by this platform",
+        ))
+    }
+
+    /// Create a group direct message channel
+    ///
+    /// # Arguments
+    /// * `user_ids` - List of user IDs to include in the group
+    ///
+    /// # Returns
+    /// The created group channel
+    ///
+    /// # Notes
+    /// Not all platforms support group messages. Check `capabilities().supports_group_messages` first.
+    async fn create_group_channel(&self, user_ids: Vec<String>) -> Result<Channel> {
+        let _ = user_ids;
+        Err(crate::error::Error::unsupported(
+            "Group channels not supported by this platform",
+        ))
+    }
+
+    /// Find an existing direct message channel with a user without creating one
+    ///
+    /// # Arguments
+    /// * `user_id` - The other participant's user ID
+    ///
+    /// # Returns
+    /// The existing DM channel, or None if the users have never talked
+    async fn find_direct_channel(&self, user_id: &str) -> Result<Option<Channel>> {
+        let _ = user_id;
+        Err(crate::error::Error::unsupported(
+            "Finding direct channels not supported by this platform",
+        ))
+    }
+
+    /// Find an existing group message channel for a set of users without creating one
+    ///
+    /// # Arguments
+    /// * `user_ids` - The other participants' user IDs
+    ///
+    /// # Returns
+    /// The existing group channel with exactly these participants, or None
+    ///
+    /// # Notes
+    /// The current user is always considered a participant.
+    async fn find_group_channel(&self, user_ids: Vec<String>) -> Result<Option<Channel>> {
+        let _ = user_ids;
+        Err(crate::error::Error::unsupported(
+            "Group channels not supported by this platform",
+        ))
+    }
+
+    /// Change who takes part in a group message
+    ///
+    /// # Arguments
+    /// * `channel_id` - The group message channel ID
+    /// * `add_user_ids` - Users to add
+    /// * `remove_user_ids` - Users to remove
+    ///
+    /// # Returns
+    /// The conversation with the new set of participants
+    ///
+    /// # Notes
+    /// The current user always stays a participant. Where a group message's
+    /// participants are fixed, as on Mattermost, this returns the group
+    /// message for the new set, created if needed, or the direct message
+    /// when only one other user is left; the original conversation and its
+    /// history are left as they are.
+    async fn update_group_participants(
+        &self,
+        channel_id: &str,
+        add_user_ids: Vec<String>,
+        remove_user_ids: Vec<String>,
+    ) -> Result<Channel> {
+        let _ = (channel_id, add_user_ids, remove_user_ids);
+        Err(crate::error::Error::unsupported(
+            "Group channels not supported by this platform",
+        ))
+    }
+
+    /// Add a user to a channel
+    ///
+    /// # Arguments
+    /// * `channel_id` - The channel ID
+    /// * `user_id` - The user ID to add
+    async fn add_channel_member(&self, channel_id: &str, user_id: &str) -> Result<()> {
+        let _ = (channel_id, user_id);
+        Err(crate::error::Error::unsupported(
+            "Channel member management not supported by this platform",
+        ))
+    }
+
+    /// Remove a user from a channel
+    ///
+    /// # Arguments
+    /// * `channel_id` - The channel ID
+    /// * `user_id` - The user ID to remove
+    async fn remove_channel_member(&self, channel_id: &str, user_id: &str) -> Result<()> {
+        let _ = (channel_id, user_id);
+        Err(crate::error::Error::unsupported(
+            "Channel member management not supported by this platform",
+        ))
+    }
+
+    /// Join a channel as the current user
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel to join
+    ///
+    /// # Returns
+    /// Result containing the joined Channel or an error
+    async fn join_channel(&self, channel_id: &str) -> Result<Channel> {
+        let _ = channel_id;
+        Err(crate::error::Error::unsupported(
+            "Joining channels not supported by this platform",
+        ))
+    }
+
+    /// Join a channel by its name within a team as the current user
+    ///
+    /// # Arguments
+    /// * `team_id` - The team ID
+    /// * `channel_name` - The channel name (not display name)
+    ///
+    /// # Returns
+    /// Result containing the joined Channel or an error
+    async fn join_channel_by_name(&self, team_id: &str, channel_name: &str) -> Result<Channel> {
+        let _ = (team_id, channel_name);
+        Err(crate::error::Error::unsupported(
+            "Joining channels not supported by this platform",
+        ))
+    }
+
+    /// Leave a channel as the current user
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel to leave
+    async fn leave_channel(&self, channel_id: &str) -> Result<()> {
+        let _ = channel_id;
+        Err(crate::error::Error::unsupported(
+            "Leaving channels not supported by this platform",
+        ))
+    }
+
+    /// Replace a channel member's explicit roles
+    ///
+    /// # Arguments
+    /// * `channel_id` - The channel ID
+    /// * `user_id` - The member's user ID
+    /// * `roles` - The complete list of role names the member should have
+    async fn update_channel_member_roles(
+        &self,
+        channel_id: &str,
+        user_id: &str,
+        roles: &[String],
+    ) -> Result<()> {
+        let _ = (channel_id, user_id, roles);
+        Err(crate::error::Error::unsupported(
+            "Channel roles not supported by this platform",
+        ))
+    }
+
+    /// Set whether a channel member is a channel admin (moderator) and/or a regular user
+    ///
+    /// # Arguments
+    /// * `channel_id` - The channel ID
+    /// * `user_id` - The member's user ID
+    /// * `scheme_admin` - Grant the channel admin role
+    /// * `scheme_user` - Grant the channel user role
+    async fn set_channel_member_scheme_roles(
+        &self,
+        channel_id: &str,
+        user_id: &str,
+        scheme_admin: bool,
+        scheme_user: bool,
+    ) -> Result<()> {
+        let _ = (channel_id, user_id, scheme_admin, scheme_user);
+        Err(crate::error::Error::unsupported(
+            "Channel roles not supported by this platform",
+        ))
+    }
+
+    /// Synchronize a channel's membership with a desired list of users
+    ///
+    /// Adds users missing from the channel and removes users not in the desired list.
+    ///
+    /// # Arguments
+    /// * `channel_id` - The channel ID
+    /// * `desired_user_ids` - The users that should be members of the channel
+    /// * `options` - Dry-run, add-only and protected user settings
+    ///
+    /// # Returns
+    /// The changes that were applied (or would be, in a dry run) and any failures
+    ///
+    /// # Notes
+    /// Built on `get_channel_members`, `add_channel_member` and `remove_channel_member`,
+    /// so it works on any platform that implements those. Individual failures don't
+    /// abort the sync; they are reported in the result.
+    async fn sync_channel_members(
+        &self,
+        channel_id: &str,
+        desired_user_ids: Vec<String>,
+        options: MemberSyncOptions,
+    ) -> Result<MemberSyncResult> {
+        let current: Vec<String> = self
+            .get_channel_members(channel_id)
+            .await?
+            .into_iter()
+            .map(|u| u.id)
+            .collect();
+        let (to_add, to_remove) = plan_member_sync(&current, &desired_user_ids, &options);
+
+        let mut result = MemberSyncResult {
+            dry_run: options.dry_run,
+            ..Default::default()
+        };
+
+        if options.dry_run {
+            result.added = to_add;
+            result.removed = to_remove;
+            return Ok(result);
+        }
+
+        for user_id in to_add {
+            match self.add_channel_member(channel_id, &user_id).await {
+                Ok(()) => result.added.push(user_id),
+                Err(e) => result.failed.push(MemberSyncFailure {
+                    user_id,
+                    action: "add".to_string(),
+                    error: e.to_string(),
+                }),
+            }
+        }
+
+        for user_id in to_remove {
+            match self.remove_channel_member(channel_id, &user_id).await {
+                Ok(()) => result.removed.push(user_id),
+                Err(e) => result.failed.push(MemberSyncFailure {
+                    user_id,
+                    action: "remove".to_string(),
+                    error: e.to_string(),
+                }),
+            }
+        }
+
+        Ok(result)
+    }
+
+    /// Get a user's effective permissions in a channel
+    ///
+    /// # Arguments
+    /// * `user_id` - The user ID
+    /// * `channel_id` - The channel ID
+    ///
+    /// # Returns
+    /// The names of all permissions the user holds in the channel
+    ///
+    /// # Notes
+    /// Permission names are platform-specific (e.g., "manage_public_channel_members").
+    async fn get_effective_permissions(
+        &self,
+        user_id: &str,
+        channel_id: &str,
+    ) -> Result<Vec<String>> {
+        let _ = (user_id, channel_id);
+        Err(crate::error::Error::unsupported(
+            "Permission queries not supported by this platform",
+        ))
+    }
+
+    /// Check whether a user holds a permission in a channel
+    ///
+    /// # Arguments
+    /// * `user_id` - The user ID
+    /// * `channel_id` - The channel ID
+    /// * `permission` - The permission name
+    ///
+    /// # Returns
+    /// true if the user holds the permission
+    async fn has_permission(
+        &self,
+        user_id: &str,
+        channel_id: &str,
+        permission: &str,
+    ) -> Result<bool> {
+        let permissions = self.get_effective_permissions(user_id, channel_id).await?;
+        Ok(permissions.iter().any(|p| p == permission))
+    }
+
+    /// Get a user by username
+    ///
+    /// # Arguments
+    /// * `username` - The username
+    ///
+    /// # Returns
+    /// The user
+    async fn get_user_by_username(&self, username: &str) -> Result<User> {
         let _ = username;
         Err(crate::error::Error::unsupported(
             "User lookup by username not supported by this platform",
@@ -763,6 +1685,26 @@ pub trait Platform: Send + Sync {
         ))
     }
 
+    /// Get detailed status for multiple users (batch operation)
+    ///
+    /// Unlike `get_users_status`, each entry carries whether the status was set
+    /// manually, when "do not disturb" expires and when the user was last active.
+    ///
+    /// # Arguments
+    /// * `user_ids` - List of user IDs
+    ///
+    /// # Returns
+    /// One entry per user the server knows about
+    async fn get_statuses_by_ids(
+        &self,
+        user_ids: Vec<String>,
+    ) -> Result<Vec<crate::types::UserStatusInfo>> {
+        let _ = user_ids;
+        Err(crate::error::Error::unsupported(
+            "Batch user status not supported by this platform",
+        ))
+    }
+
     /// Request statuses for all users via WebSocket (async operation)
     ///
     /// This method sends a WebSocket request to get statuses for all users.
@@ -805,6 +1747,38 @@ pub trait Platform: Send + Sync {
         ))
     }
 
+    /// Get the health of the real-time connection
+    ///
+    /// Reports ping round trips and when the last event arrived, so
+    /// applications can spot half-open connections and show connection quality.
+    ///
+    /// # Notes
+    /// - Requires an active WebSocket connection (call `subscribe_events` first)
+    async fn connection_health(&self) -> Result<crate::types::ConnectionHealth> {
+        Err(crate::error::Error::unsupported(
+            "Connection health not supported by this platform",
+        ))
+    }
+
+    /// Ping the server over the real-time connection and wait for the pong
+    ///
+    /// # Arguments
+    /// * `timeout_ms` - How long to wait for the pong; 0 means 10 seconds
+    ///
+    /// # Returns
+    /// The round-trip time in milliseconds
+    ///
+    /// # Notes
+    /// - Requires an active WebSocket connection (call `subscribe_events` first)
+    /// - Fails with a Timeout error when no pong arrives in time, which on an
+    ///   open connection means it is half-open
+    async fn ping(&self, timeout_ms: u64) -> Result<u64> {
+        let _ = timeout_ms;
+        Err(crate::error::Error::unsupported(
+            "Ping not supported by this platform",
+        ))
+    }
+
     /// Send a typing indicator to a channel
     ///
     /// # Arguments
@@ -839,10 +1813,40 @@ pub trait Platform: Send + Sync {
         ))
     }
 
-    /// Set the active team/workspace ID
+    /// Get member counts for a team
     ///
     /// # Arguments
-    /// * `team_id` - The team ID to set as active (or None to unset)
+    /// * `team_id` - The team ID
+    ///
+    /// # Notes
+    /// Only applicable for platforms with workspaces. Check `capabilities().has_workspaces` first.
+    async fn get_team_stats(&self, team_id: &str) -> Result<crate::types::TeamStats> {
+        let _ = team_id;
+        Err(crate::error::Error::unsupported(
+            "Team stats not supported by this platform",
+        ))
+    }
+
+    /// Search the teams visible to the current user by name or display name
+    ///
+    /// Unlike `get_teams`, this also finds teams the user hasn't joined.
+    ///
+    /// # Arguments
+    /// * `term` - Text to match
+    ///
+    /// # Notes
+    /// Only applicable for platforms with workspaces. Check `capabilities().has_workspaces` first.
+    async fn search_teams(&self, term: &str) -> Result<Vec<Team>> {
+        let _ = term;
+        Err(crate::error::Error::unsupported(
+            "Team search not supported by this platform",
+        ))
+    }
+
+    /// Set the active team/workspace ID
+    ///
+    /// # Arguments
+    /// * `team_id` - The team ID to set as active (or None to unset)
     ///
     /// # Notes
     /// Only applicable for platforms with workspaces. Check `capabilities().has_workspaces` first.
@@ -895,6 +1899,29 @@ pub trait Platform: Send + Sync {
         ))
     }
 
+    /// Download part of a file by its ID
+    ///
+    /// # Arguments
+    /// * `file_id` - The ID of the file to download
+    /// * `offset` - Position of the first byte to download
+    /// * `length` - Maximum number of bytes to download; 0 reads to the end
+    ///
+    /// # Returns
+    /// The requested bytes, fewer than `length` if the file ends first
+    ///
+    /// # Notes
+    /// The default downloads the whole file and cuts the range out of it;
+    /// platforms that support range requests override it.
+    async fn download_file_range(
+        &self,
+        file_id: &str,
+        offset: u64,
+        length: u64,
+    ) -> Result<Vec<u8>> {
+        let data = self.download_file(file_id).await?;
+        Ok(slice_range(&data, offset, length).to_vec())
+    }
+
     /// Get metadata for a file without downloading it
     ///
     /// # Arguments
@@ -971,6 +1998,92 @@ pub trait Platform: Send + Sync {
         ))
     }
 
+    /// Check whether the server allows public file links
+    ///
+    /// Administrators can disable public links at any time; check before
+    /// sharing a link outside the platform.
+    async fn public_links_enabled(&self) -> Result<bool> {
+        Err(crate::error::Error::unsupported(
+            "File links not supported by this platform",
+        ))
+    }
+
+    /// Enable public file links server-wide (administrators only)
+    async fn enable_public_links(&self) -> Result<()> {
+        Err(crate::error::Error::unsupported(
+            "File links not supported by this platform",
+        ))
+    }
+
+    /// Disable public file links server-wide and invalidate every link
+    /// issued so far (administrators only)
+    async fn revoke_public_links(&self) -> Result<()> {
+        Err(crate::error::Error::unsupported(
+            "File links not supported by this platform",
+        ))
+    }
+
+    /// Invalidate the public link of a single file
+    ///
+    /// Other files' links keep working.
+    async fn revoke_file_link(&self, file_id: &str) -> Result<()> {
+        let _ = file_id;
+        Err(crate::error::Error::unsupported(
+            "Revoking a single file link not supported by this platform",
+        ))
+    }
+
+    // ========================================================================
+    // System Administration
+    // ========================================================================
+
+    /// Get the server configuration (administrators only)
+    ///
+    /// # Returns
+    /// The platform-specific configuration document, with secrets masked
+    async fn get_server_config(&self) -> Result<serde_json::Value> {
+        Err(crate::error::Error::unsupported(
+            "Server administration not supported by this platform",
+        ))
+    }
+
+    /// Change part of the server configuration (administrators only)
+    ///
+    /// # Arguments
+    /// * `patch` - The settings to change, nested as in the configuration
+    ///   document; settings left out keep their values
+    ///
+    /// # Returns
+    /// The full configuration after the change
+    async fn patch_server_config(&self, patch: serde_json::Value) -> Result<serde_json::Value> {
+        let _ = patch;
+        Err(crate::error::Error::unsupported(
+            "Server administration not supported by this platform",
+        ))
+    }
+
+    /// Get usage statistics of the whole server (administrators only)
+    async fn get_system_stats(&self) -> Result<crate::types::SystemStats> {
+        Err(crate::error::Error::unsupported(
+            "Server administration not supported by this platform",
+        ))
+    }
+
+    /// Get a page of the server log (administrators only)
+    ///
+    /// # Arguments
+    /// * `page` - The page number (0-indexed)
+    /// * `per_page` - Number of log lines per page
+    ///
+    /// # Returns
+    /// Raw log lines, oldest first
+    async fn get_server_logs(&self, page: u32, per_page: u32) -> Result<Vec<String>> {
+        let _ = (page, per_page);
+        Err(crate::error::Error::unsupported(
+            "Server administration not supported by this platform",
+        ))
+    }
+
     // ========================================================================
     // Thread Operations
     // ========================================================================
@@ -1089,10 +2202,10 @@ pub trait Platform: Send + Sync {
     /// * `page` - Page number (0-indexed)
     ///
     /// # Returns
-    /// JSON string containing thread list
+    /// A page of followed threads with unread reply and mention counts
     ///
     /// # Notes
-    /// Returns platform-specific JSON. Not all platforms support thread listing.
+    /// Not all platforms support thread listing.
     async fn get_user_threads(
         &self,
         user_id: &str,
@@ -1102,7 +2215,7 @@ pub trait Platform: Send + Sync {
         unread: bool,
         per_page: usize,
         page: usize,
-    ) -> Result<String> {
+    ) -> Result<crate::types::ThreadList> {
         let _ = (user_id, team_id, since, deleted, unread, per_page, page);
         Err(crate::error::Error::unsupported(
             "Thread listing not supported by this platform",
@@ -1119,16 +2232,16 @@ pub trait Platform: Send + Sync {
     /// * `thread_id` - The thread ID (root post ID)
     ///
     /// # Returns
-    /// JSON string containing thread information
+    /// The thread with the user's unread reply and mention counts
     ///
     /// # Notes
-    /// Returns platform-specific JSON. Not all platforms support per-user thread info.
+    /// Not all platforms support per-user thread info.
     async fn get_user_thread(
         &self,
         user_id: &str,
         team_id: &str,
         thread_id: &str,
-    ) -> Result<String> {
+    ) -> Result<crate::types::Thread> {
         let _ = (user_id, team_id, thread_id);
         Err(crate::error::Error::unsupported(
             "Thread information not supported by this platform",
@@ -1346,6 +2459,33 @@ pub trait Platform: Send + Sync {
         ))
     }
 
+    /// Get the current user's account-wide notification preferences
+    ///
+    /// # Returns
+    /// The notification preferences; fields the platform does not report are `None`
+    async fn get_user_notify_props(&self) -> Result<crate::types::UserNotifyProps> {
+        Err(crate::error::Error::unsupported(
+            "User notification settings not supported by this platform",
+        ))
+    }
+
+    /// Update the current user's account-wide notification preferences
+    ///
+    /// # Arguments
+    /// * `props` - The preferences to change; `None` fields are left as they are
+    ///
+    /// # Returns
+    /// The notification preferences after the update
+    async fn update_user_notify_props(
+        &self,
+        props: &crate::types::UserNotifyProps,
+    ) -> Result<crate::types::UserNotifyProps> {
+        let _ = props;
+        Err(crate::error::Error::unsupported(
+            "User notification settings not supported by this platform",
+        ))
+    }
+
     /// Mark a channel as viewed (read) by the current user
     ///
     /// This updates the last_viewed_at timestamp for the channel and clears
@@ -1424,6 +2564,24 @@ pub trait Platform: Send + Sync {
         ))
     }
 
+    /// Get unread counts summed over every team, for badge counts
+    ///
+    /// # Returns
+    /// The totals and the per-team counts they were built from
+    ///
+    /// # Notes
+    /// The default sums `get_all_unreads`; platforms that track urgent
+    /// mentions or teamless channels override it.
+    async fn get_total_unreads(&self) -> Result<crate::types::UnreadTotals> {
+        let teams = self.get_all_unreads().await?;
+        Ok(crate::types::UnreadTotals {
+            msg_count: teams.iter().map(|t| t.msg_count).sum(),
+            mention_count: teams.iter().map(|t| t.mention_count).sum(),
+            urgent_mention_count: 0,
+            teams,
+        })
+    }
+
     /// Get unread posts in a channel
     ///
     /// Retrieves the actual unread messages in a channel.
@@ -1450,12 +2608,585 @@ pub trait Platform: Send + Sync {
             "Unread posts tracking not supported by this platform",
         ))
     }
+
+    // ========================================================================
+    // Sidebar Categories
+    // ========================================================================
+
+    /// Get the current user's sidebar categories for a team
+    ///
+    /// # Arguments
+    /// * `team_id` - The team ID
+    ///
+    /// # Returns
+    /// The categories in display order
+    async fn get_sidebar_categories(&self, team_id: &str) -> Result<Vec<SidebarCategory>> {
+        let _ = team_id;
+        Err(crate::error::Error::unsupported(
+            "Sidebar categories not supported by this platform",
+        ))
+    }
+
+    /// Create a custom sidebar category
+    ///
+    /// # Arguments
+    /// * `team_id` - The team ID
+    /// * `display_name` - The name of the new category
+    /// * `channel_ids` - Channels to move into the new category
+    ///
+    /// # Returns
+    /// The created category
+    async fn create_sidebar_category(
+        &self,
+        team_id: &str,
+        display_name: &str,
+        channel_ids: Vec<String>,
+    ) -> Result<SidebarCategory> {
+        let _ = (team_id, display_name, channel_ids);
+        Err(crate::error::Error::unsupported(
+            "Sidebar categories not supported by this platform",
+        ))
+    }
+
+    /// Update a sidebar category (name, channel order, muted and collapsed state)
+    ///
+    /// # Arguments
+    /// * `category` - The category with the desired values
+    ///
+    /// # Returns
+    /// The updated category
+    async fn update_sidebar_category(&self, category: SidebarCategory) -> Result<SidebarCategory> {
+        let _ = category;
+        Err(crate::error::Error::unsupported(
+            "Sidebar categories not supported by this platform",
+        ))
+    }
+
+    /// Delete a custom sidebar category
+    ///
+    /// # Arguments
+    /// * `team_id` - The team ID
+    /// * `category_id` - The category to delete
+    ///
+    /// # Notes
+    /// Channels in the deleted category return to their default categories.
+    async fn delete_sidebar_category(&self, team_id: &str, category_id: &str) -> Result<()> {
+        let _ = (team_id, category_id);
+        Err(crate::error::Error::unsupported(
+            "Sidebar categories not supported by this platform",
+        ))
+    }
+
+    /// Reorder the sidebar categories
+    ///
+    /// # Arguments
+    /// * `team_id` - The team ID
+    /// * `category_ids` - All category IDs, in the desired order
+    async fn reorder_sidebar_categories(
+        &self,
+        team_id: &str,
+        category_ids: Vec<String>,
+    ) -> Result<()> {
+        let _ = (team_id, category_ids);
+        Err(crate::error::Error::unsupported(
+            "Sidebar categories not supported by this platform",
+        ))
+    }
+
+    /// Move a channel into a sidebar category
+    ///
+    /// # Arguments
+    /// * `team_id` - The team ID
+    /// * `channel_id` - The channel to move
+    /// * `category_id` - The target category
+    async fn move_channel_to_category(
+        &self,
+        team_id: &str,
+        channel_id: &str,
+        category_id: &str,
+    ) -> Result<()> {
+        let _ = (team_id, channel_id, category_id);
+        Err(crate::error::Error::unsupported(
+            "Sidebar categories not supported by this platform",
+        ))
+    }
+
+    /// Add a channel to, or remove it from, the favorites category
+    ///
+    /// # Arguments
+    /// * `team_id` - The team ID
+    /// * `channel_id` - The channel ID
+    /// * `favorite` - true to favorite the channel, false to move it back to its default category
+    async fn set_channel_favorite(
+        &self,
+        team_id: &str,
+        channel_id: &str,
+        favorite: bool,
+    ) -> Result<()> {
+        let _ = (team_id, channel_id, favorite);
+        Err(crate::error::Error::unsupported(
+            "Sidebar categories not supported by this platform",
+        ))
+    }
+
+    // ========================================================================
+    // Channel Bookmarks
+    // ========================================================================
+
+    /// Get the bookmarks of a channel
+    ///
+    /// # Arguments
+    /// * `channel_id` - The channel ID
+    ///
+    /// # Returns
+    /// The channel's bookmarks in display order
+    async fn get_channel_bookmarks(&self, channel_id: &str) -> Result<Vec<ChannelBookmark>> {
+        let _ = channel_id;
+        Err(crate::error::Error::unsupported(
+            "Channel bookmarks not supported by this platform",
+        ))
+    }
+
+    /// Create a link or file bookmark in a channel
+    ///
+    /// # Arguments
+    /// * `channel_id` - The channel ID
+    /// * `bookmark` - The bookmark to create
+    ///
+    /// # Returns
+    /// The created bookmark
+    async fn create_channel_bookmark(
+        &self,
+        channel_id: &str,
+        bookmark: NewChannelBookmark,
+    ) -> Result<ChannelBookmark> {
+        let _ = (channel_id, bookmark);
+        Err(crate::error::Error::unsupported(
+            "Channel bookmarks not supported by this platform",
+        ))
+    }
+
+    /// Delete a bookmark from a channel
+    ///
+    /// # Arguments
+    /// * `channel_id` - The channel ID
+    /// * `bookmark_id` - The bookmark ID
+    async fn delete_channel_bookmark(&self, channel_id: &str, bookmark_id: &str) -> Result<()> {
+        let _ = (channel_id, bookmark_id);
+        Err(crate::error::Error::unsupported(
+            "Channel bookmarks not supported by this platform",
+        ))
+    }
+
+    // ========================================================================
+    // Sessions
+    // ========================================================================
+
+    /// List the active login sessions of a user
+    ///
+    /// # Arguments
+    /// * `user_id` - The ID of the user
+    async fn list_sessions(&self, user_id: &str) -> Result<Vec<crate::types::Session>> {
+        let _ = user_id;
+        Err(crate::error::Error::unsupported(
+            "Session management not supported by this platform",
+        ))
+    }
+
+    /// Revoke one of the current user's sessions
+    ///
+    /// # Arguments
+    /// * `session_id` - The ID of the session to revoke
+    async fn revoke_session(&self, session_id: &str) -> Result<()> {
+        let _ = session_id;
+        Err(crate::error::Error::unsupported(
+            "Session management not supported by this platform",
+        ))
+    }
+
+    /// Revoke every session of the current user, including this one
+    ///
+    /// The platform connection is logged out as a result.
+    async fn revoke_all_sessions(&self) -> Result<()> {
+        Err(crate::error::Error::unsupported(
+            "Session management not supported by this platform",
+        ))
+    }
+
+    /// Execute a slash command in a channel as the current user
+    ///
+    /// # Arguments
+    /// * `channel_id` - The channel to run the command in
+    /// * `command` - The command with its arguments, e.g. "/away" or "/giphy cats"
+    ///
+    /// # Returns
+    /// The command's response
+    async fn execute_command(
+        &self,
+        channel_id: &str,
+        command: &str,
+    ) -> Result<crate::types::CommandResponse> {
+        let _ = (channel_id, command);
+        Err(crate::error::Error::unsupported(
+            "Slash commands not supported by this platform",
+        ))
+    }
+
+    /// List the slash commands available in a channel, for composer suggestions
+    ///
+    /// # Arguments
+    /// * `channel_id` - The channel the command would run in
+    /// * `prefix` - Only list commands whose trigger starts with this, with or
+    ///   without the slash; empty lists every command
+    ///
+    /// # Returns
+    /// The matching built-in, custom and plugin commands, sorted by trigger
+    async fn autocomplete_commands(
+        &self,
+        channel_id: &str,
+        prefix: &str,
+    ) -> Result<Vec<crate::types::SlashCommand>> {
+        let _ = (channel_id, prefix);
+        Err(crate::error::Error::unsupported(
+            "Command autocomplete not supported by this platform",
+        ))
+    }
+
+    /// Create an incoming webhook that posts to a channel
+    ///
+    /// # Arguments
+    /// * `hook` - The channel and display settings of the webhook
+    ///
+    /// # Returns
+    /// The created webhook, including the URL to post to
+    async fn create_incoming_webhook(
+        &self,
+        hook: &crate::types::NewIncomingWebhook,
+    ) -> Result<crate::types::IncomingWebhook> {
+        let _ = hook;
+        Err(crate::error::Error::unsupported(
+            "Incoming webhooks not supported by this platform",
+        ))
+    }
+
+    /// List a page of incoming webhooks
+    ///
+    /// # Arguments
+    /// * `team_id` - Only list webhooks of this team (None lists all the user can manage)
+    /// * `page` - The page number to retrieve (0-indexed)
+    /// * `per_page` - Number of webhooks per page
+    async fn list_incoming_webhooks(
+        &self,
+        team_id: Option<&str>,
+        page: u32,
+        per_page: u32,
+    ) -> Result<Vec<crate::types::IncomingWebhook>> {
+        let _ = (team_id, page, per_page);
+        Err(crate::error::Error::unsupported(
+            "Incoming webhooks not supported by this platform",
+        ))
+    }
+
+    /// Delete an incoming webhook
+    ///
+    /// # Arguments
+    /// * `hook_id` - The ID of the webhook to delete
+    async fn delete_incoming_webhook(&self, hook_id: &str) -> Result<()> {
+        let _ = hook_id;
+        Err(crate::error::Error::unsupported(
+            "Incoming webhooks not supported by this platform",
+        ))
+    }
+
+    /// Create a bot account owned by the current user
+    ///
+    /// # Arguments
+    /// * `bot` - The username and profile of the bot
+    ///
+    /// # Returns
+    /// The created bot
+    async fn create_bot(&self, bot: &crate::types::NewBot) -> Result<crate::types::Bot> {
+        let _ = bot;
+        Err(crate::error::Error::unsupported(
+            "Bot accounts not supported by this platform",
+        ))
+    }
+
+    /// Partially update a bot account
+    ///
+    /// # Arguments
+    /// * `bot_user_id` - The user ID of the bot
+    /// * `patch` - The fields to change
+    ///
+    /// # Returns
+    /// The updated bot
+    async fn patch_bot(
+        &self,
+        bot_user_id: &str,
+        patch: &crate::types::BotPatch,
+    ) -> Result<crate::types::Bot> {
+        let _ = (bot_user_id, patch);
+        Err(crate::error::Error::unsupported(
+            "Bot accounts not supported by this platform",
+        ))
+    }
+
+    /// Disable a bot account
+    ///
+    /// # Arguments
+    /// * `bot_user_id` - The user ID of the bot
+    ///
+    /// # Returns
+    /// The disabled bot
+    async fn disable_bot(&self, bot_user_id: &str) -> Result<crate::types::Bot> {
+        let _ = bot_user_id;
+        Err(crate::error::Error::unsupported(
+            "Bot accounts not supported by this platform",
+        ))
+    }
+
+    /// Transfer ownership of a bot account to another user
+    ///
+    /// # Arguments
+    /// * `bot_user_id` - The user ID of the bot
+    /// * `user_id` - The ID of the new owner
+    ///
+    /// # Returns
+    /// The updated bot
+    async fn assign_bot(&self, bot_user_id: &str, user_id: &str) -> Result<crate::types::Bot> {
+        let _ = (bot_user_id, user_id);
+        Err(crate::error::Error::unsupported(
+            "Bot accounts not supported by this platform",
+        ))
+    }
+
+    /// Send a message carrying rich attachments, such as action buttons and menus
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel to send the message to
+    /// * `text` - The message text (may be empty when attachments carry the content)
+    /// * `attachments` - The attachments to include
+    ///
+    /// # Returns
+    /// The created message
+    async fn send_message_with_attachments(
+        &self,
+        channel_id: &str,
+        text: &str,
+        attachments: &[crate::types::MessageAttachment],
+    ) -> Result<Message> {
+        let _ = (channel_id, text, attachments);
+        Err(crate::error::Error::unsupported(
+            "Message attachments not supported by this platform",
+        ))
+    }
+
+    /// Open an interactive dialog for the user who triggered an action
+    ///
+    /// # Arguments
+    /// * `trigger_id` - The trigger ID from an action callback or slash command
+    /// * `url` - The URL the submission is posted to
+    /// * `dialog` - The dialog to show
+    async fn open_interactive_dialog(
+        &self,
+        trigger_id: &str,
+        url: &str,
+        dialog: &crate::types::InteractiveDialog,
+    ) -> Result<()> {
+        let _ = (trigger_id, url, dialog);
+        Err(crate::error::Error::unsupported(
+            "Interactive dialogs not supported by this platform",
+        ))
+    }
+
+    /// Send a message with a priority label and, optionally, an acknowledgement request
+    ///
+    /// # Arguments
+    /// * `channel_id` - The ID of the channel to send the message to
+    /// * `text` - The message text
+    /// * `priority` - The priority settings of the message
+    ///
+    /// # Returns
+    /// The created message
+    async fn send_message_with_priority(
+        &self,
+        channel_id: &str,
+        text: &str,
+        priority: &crate::types::MessagePriority,
+    ) -> Result<Message> {
+        let _ = (channel_id, text, priority);
+        Err(crate::error::Error::unsupported(
+            "Message priority not supported by this platform",
+        ))
+    }
+
+    /// Acknowledge a message as the current user
+    ///
+    /// # Arguments
+    /// * `message_id` - The ID of the message to acknowledge
+    ///
+    /// # Returns
+    /// The recorded acknowledgement
+    async fn ack_message(&self, message_id: &str) -> Result<crate::types::MessageAcknowledgement> {
+        let _ = message_id;
+        Err(crate::error::Error::unsupported(
+            "Message acknowledgements not supported by this platform",
+        ))
+    }
+
+    /// Get the acknowledgements of a message
+    ///
+    /// # Arguments
+    /// * `message_id` - The ID of the message
+    ///
+    /// # Returns
+    /// The acknowledgements, oldest first
+    async fn get_message_acknowledgements(
+        &self,
+        message_id: &str,
+    ) -> Result<Vec<crate::types::MessageAcknowledgement>> {
+        let _ = message_id;
+        Err(crate::error::Error::unsupported(
+            "Message acknowledgements not supported by this platform",
+        ))
+    }
+
+    // ========================================================================
+    // User Groups
+    // ========================================================================
+
+    /// Create a custom user group that can be @-mentioned
+    ///
+    /// # Arguments
+    /// * `group` - The group to create, with its initial members
+    ///
+    /// # Returns
+    /// The created group
+    async fn create_user_group(
+        &self,
+        group: crate::types::NewUserGroup,
+    ) -> Result<crate::types::UserGroup> {
+        let _ = group;
+        Err(crate::error::Error::unsupported(
+            "User groups not supported by this platform",
+        ))
+    }
+
+    /// Add users to a custom user group
+    async fn add_user_group_members(&self, group_id: &str, user_ids: &[String]) -> Result<()> {
+        let _ = (group_id, user_ids);
+        Err(crate::error::Error::unsupported(
+            "User groups not supported by this platform",
+        ))
+    }
+
+    /// Remove users from a custom user group
+    async fn remove_user_group_members(&self, group_id: &str, user_ids: &[String]) -> Result<()> {
+        let _ = (group_id, user_ids);
+        Err(crate::error::Error::unsupported(
+            "User groups not supported by this platform",
+        ))
+    }
+
+    /// Get the user groups that can be @-mentioned in a team
+    ///
+    /// # Arguments
+    /// * `team_id` - The team ID
+    /// * `page` - Page number (0-indexed)
+    /// * `per_page` - Number of groups per page
+    async fn get_team_user_groups(
+        &self,
+        team_id: &str,
+        page: u32,
+        per_page: u32,
+    ) -> Result<Vec<crate::types::UserGroup>> {
+        let _ = (team_id, page, per_page);
+        Err(crate::error::Error::unsupported(
+            "User groups not supported by this platform",
+        ))
+    }
+
+    /// Suggest user groups for a partially typed @-mention
+    ///
+    /// # Arguments
+    /// * `team_id` - The team the mention is typed in
+    /// * `prefix` - The text typed after the @
+    async fn autocomplete_user_groups(
+        &self,
+        team_id: &str,
+        prefix: &str,
+    ) -> Result<Vec<crate::types::UserGroup>> {
+        let _ = (team_id, prefix);
+        Err(crate::error::Error::unsupported(
+            "User groups not supported by this platform",
+        ))
+    }
+}
+
+/// Cut `length` bytes from `offset` out of a whole file; 0 means to the end
+pub(crate) fn slice_range(data: &[u8], offset: u64, length: u64) -> &[u8] {
+    let start = usize::try_from(offset)
+        .unwrap_or(usize::MAX)
+        .min(data.len());
+    let end = if length == 0 {
+        data.len()
+    } else {
+        usize::try_from(offset.saturating_add(length))
+            .unwrap_or(usize::MAX)
+            .min(data.len())
+    };
+    &data[start..end]
 }
 
 #[cfg(test)]
 mod tests {
     use super::*;
 
+    #[test]
+    fn test_event_filter() {
+        let typing = |channel_id: &str| PlatformEvent::UserTyping {
+            user_id: "user-1".to_string(),
+            channel_id: channel_id.to_string(),
+        };
+        let status = PlatformEvent::UserStatusChanged {
+            user_id: "user-1".to_string(),
+            status: UserStatus::Online,
+        };
+        let gap = PlatformEvent::GapDetected {
+            expected_seq: 2,
+            received_seq: 4,
+        };
+
+        let all = EventFilter::default();
+        assert!(all.matches(&status, None));
+
+        let filter = EventFilter {
+            channel_ids: vec!["ch-1".to_string()],
+            team_ids: vec!["team-1".to_string()],
+        };
+        assert!(filter.matches(&typing("ch-1"), None));
+        assert!(!filter.matches(&typing("ch-2"), None));
+        assert!(filter.matches(&typing("ch-2"), Some("team-1")));
+        assert!(!filter.matches(&typing("ch-2"), Some("team-2")));
+        assert!(!filter.matches(&status, None));
+        assert!(filter.matches(&gap, None));
+        assert!(filter.matches(
+            &PlatformEvent::TeamUpdated {
+                team_id: "team-1".to_string()
+            },
+            None
+        ));
+    }
+
+    #[test]
+    fn test_slice_range() {
+        let data = b"0123456789";
+        assert_eq!(slice_range(data, 2, 3), b"234");
+        assert_eq!(slice_range(data, 7, 0), b"789");
+        assert_eq!(slice_range(data, 8, 10), b"89");
+        assert_eq!(slice_range(data, 20, 5), b"");
+    }
+
     #[test]
     fn test_platform_config_builder() {
         let config = PlatformConfig::new("https://chat.example.com")
@@ -52,7 +52,8 @@ pub fn shutdown_runtime() {
 
 /// Execute an async future synchronously
 ///
-/// This blocks the current thread until the future completes.
+/// This blocks the current thread until the future completes. Calls from
+/// different threads run concurrently.
 /// The runtime must be initialized before calling this function.
 ///
 /// # Panics
@@ -62,9 +63,10 @@ where
     F: Future + Send,
     F::Output: Send,
 {
-    let runtime_guard = RUNTIME.lock().expect("Failed to acquire runtime lock");
-    let runtime = runtime_guard.as_ref().expect("Runtime not initialized");
-    runtime.handle().block_on(future)
+    // The lock is only held to get the handle; holding it while blocking
+    // would serialize every FFI call
+    let handle = runtime_handle().expect("Runtime not initialized");
+    handle.block_on(future)
 }
 
 /// Get a handle to the runtime for spawning background tasks
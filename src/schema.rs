@@ -0,0 +1,711 @@
+//! Canonical JSON samples of the public types
+//!
+//! Bindings mirror the Rust types by hand, so a field added or renamed here
+//! silently disappears on the other side of the FFI. This module exposes a
+//! fully populated sample of every public type (every Option set, every Vec
+//! non-empty) and a native round-trip, which bindings use to generate golden
+//! files and diff them against their own structs.
+
+use std::collections::HashMap;
+
+use chrono::{DateTime, Utc};
+use serde::de::DeserializeOwned;
+use serde::Serialize;
+
+use crate::error::{Error, ErrorCode, Result};
+use crate::platforms::PlatformEvent;
+use crate::types::user::UserStatus;
+use crate::types::{
+    Attachment, BookmarkType, Bot, Channel, ChannelBookmark, ChannelChanges, ChannelType,
+    CommandResponse, CommandResponseType, ConnectionChangeReason, ConnectionHealth, ConnectionInfo,
+    ConnectionState, ConnectionStateChange, Emoji, FieldChange, IncomingWebhook, LinkMedia,
+    LinkMetadata, MemberSyncFailure, MemberSyncResult, Message, MessageAcknowledgement,
+    MessagePriority, NotifyLevel, PriorityLevel, ReplyNotifyLevel, Session, SessionState,
+    SidebarCategory, SidebarCategoryType, SlashCommand, Team, TeamStats, TeamType, TeamUnread,
+    Thread, ThreadList, User, UserGroup, UserNotifyProps, UserStatusInfo,
+};
+
+/// Prefix of the event sample names, e.g. "event.message_posted"
+///
+/// Events are output-only: they can be sampled but not round-tripped.
+pub const EVENT_PREFIX: &str = "event.";
+
+/// Names of the data types with a sample, in a stable order
+pub const TYPE_NAMES: &[&str] = &[
+    "message",
+    "channel",
+    "user",
+    "team",
+    "connection_info",
+    "emoji",
+    "session",
+    "session_state",
+    "sidebar_category",
+    "channel_bookmark",
+    "user_notify_props",
+    "member_sync_result",
+    "command_response",
+    "incoming_webhook",
+    "bot",
+    "message_priority",
+    "message_acknowledgement",
+    "thread_list",
+    "user_group",
+    "user_status_info",
+    "team_stats",
+    "link_metadata",
+    "unread_totals",
+    "system_stats",
+    "slash_command",
+    "connection_health",
+];
+
+/// Names of the event samples, in a stable order
+pub const EVENT_NAMES: &[&str] = &[
+    "event.message_posted",
+    "event.message_deleted",
+    "event.channel_updated",
+    "event.user_status_changed",
+    "event.user_typing",
+    "event.reaction_added",
+    "event.connection_state_changed",
+    "event.gap_detected",
+    "event.raw",
+    "event.rate_limited",
+];
+
+/// Every sample name: data types followed by events
+pub fn names() -> Vec<&'static str> {
+    TYPE_NAMES.iter().chain(EVENT_NAMES).copied().collect()
+}
+
+fn time(offset_secs: i64) -> DateTime<Utc> {
+    DateTime::from_timestamp(1_704_067_200 + offset_secs, 0).unwrap_or_default()
+}
+
+fn sample_attachment() -> Attachment {
+    Attachment {
+        id: "file-1".to_string(),
+        filename: "report.pdf".to_string(),
+        mime_type: "application/pdf".to_string(),
+        size: 2048,
+        url: "https://chat.example.com/api/v4/files/file-1".to_string(),
+        thumbnail_url: Some("https://chat.example.com/api/v4/files/file-1/thumbnail".to_string()),
+        width: Some(1240),
+        height: Some(1754),
+        has_preview_image: true,
+        mini_preview: Some("/9j/2wCEAAMCAgMCAgMDAwMEAwMEBQgFBQQEBQoHBwYIDAoMDAsKCwsNDhIQDQ4RDgsLEBYQERMUFRUVDA8XGBYUGBIUFRQ=".to_string()),
+    }
+}
+
+fn sample_message() -> Message {
+    Message {
+        id: "post-1".to_string(),
+        text: "Hello, world".to_string(),
+        sender_id: "user-1".to_string(),
+        channel_id: "channel-1".to_string(),
+        created_at: time(0),
+        edited_at: Some(time(60)),
+        attachments: vec![sample_attachment()],
+        root_id: Some("post-0".to_string()),
+        reply_count: 2,
+        last_reply_at: Some(time(300)),
+        participant_ids: vec!["user-1".to_string(), "user-2".to_string()],
+        message_type: Some("system_header_change".to_string()),
+        metadata: Some(serde_json::json!({
+            "root_id": "post-0",
+            "embeds": [{
+                "type": "opengraph",
+                "url": "https://example.com",
+                "data": { "title": "Example" }
+            }],
+            "images": {
+                "https://example.com/logo.png": { "width": 640, "height": 480, "format": "png", "frame_count": 0 }
+            },
+            "reaction_counts": { "tada": 2 },
+            "priority": { "priority": "important", "requested_ack": false, "persistent_notifications": false }
+        })),
+        extra: HashMap::from([("remote_id".to_string(), serde_json::json!("remote-1"))]),
+    }
+}
+
+fn sample_channel() -> Channel {
+    Channel {
+        id: "channel-1".to_string(),
+        name: "town-square".to_string(),
+        display_name: "Town Square".to_string(),
+        channel_type: ChannelType::Public,
+        topic: Some("Announcements".to_string()),
+        purpose: Some("Company-wide chat".to_string()),
+        member_ids: Some(vec!["user-1".to_string(), "user-2".to_string()]),
+        created_at: time(0),
+        last_activity_at: Some(time(3600)),
+        is_archived: false,
+        metadata: Some(serde_json::json!({ "team_id": "team-1" })),
+        extra: HashMap::new(),
+    }
+}
+
+fn sample_user() -> User {
+    User {
+        id: "user-1".to_string(),
+        username: "alice".to_string(),
+        display_name: "Alice Example".to_string(),
+        email: Some("alice@example.com".to_string()),
+        avatar_url: Some("https://chat.example.com/api/v4/users/user-1/image".to_string()),
+        status: UserStatus::Online,
+        status_message: Some("In a meeting".to_string()),
+        is_bot: false,
+        metadata: Some(serde_json::json!({ "locale": "en" })),
+        extra: HashMap::new(),
+    }
+}
+
+fn sample_team() -> Team {
+    Team {
+        id: "team-1".to_string(),
+        name: "engineering".to_string(),
+        display_name: "Engineering".to_string(),
+        description: Some("Engineering team".to_string()),
+        team_type: TeamType::Open,
+        allowed_domains: Some("example.com".to_string()),
+        allow_open_invite: true,
+        metadata: Some(serde_json::json!({ "invite_id": "invite-1" })),
+    }
+}
+
+fn sample_connection_info() -> ConnectionInfo {
+    ConnectionInfo {
+        platform: "mattermost".to_string(),
+        server: "https://chat.example.com".to_string(),
+        user_id: "user-1".to_string(),
+        user_display_name: "Alice Example".to_string(),
+        connected_at: time(0),
+        state: ConnectionState::Connected,
+        team_id: Some("team-1".to_string()),
+        team_name: Some("engineering".to_string()),
+        metadata: Some(serde_json::json!({ "server_version": "9.0.0" })),
+    }
+}
+
+fn sample_connection_health() -> ConnectionHealth {
+    ConnectionHealth {
+        state: ConnectionState::Connected,
+        rtt_ms: Some(42),
+        last_pong_at: Some(time(30)),
+        last_event_at: Some(time(25)),
+        unanswered_pings: 1,
+        pings_sent: 12,
+        pongs_received: 11,
+    }
+}
+
+fn sample_emoji() -> Emoji {
+    Emoji {
+        id: "emoji-1".to_string(),
+        name: "party_parrot".to_string(),
+        creator_id: "user-1".to_string(),
+        created_at: 1_704_067_200_000,
+    }
+}
+
+fn sample_session() -> Session {
+    Session {
+        id: "session-1".to_string(),
+        user_id: "user-1".to_string(),
+        created_at: time(0),
+        last_activity_at: time(600),
+        expires_at: Some(time(86_400)),
+        device_id: Some("device-1".to_string()),
+        os: Some("Linux".to_string()),
+        browser: Some("Firefox".to_string()),
+        platform: Some("Linux".to_string()),
+        is_oauth: false,
+    }
+}
+
+fn sample_session_state() -> SessionState {
+    SessionState {
+        server_url: "https://chat.example.com".to_string(),
+        token: "token-1".to_string(),
+        user_id: Some("user-1".to_string()),
+        team_id: Some("team-1".to_string()),
+        connection_id: Some("connection-1".to_string()),
+        last_sequence: 42,
+    }
+}
+
+fn sample_sidebar_category() -> SidebarCategory {
+    SidebarCategory {
+        id: "category-1".to_string(),
+        team_id: "team-1".to_string(),
+        display_name: "Projects".to_string(),
+        category_type: SidebarCategoryType::Custom,
+        channel_ids: vec!["channel-1".to_string()],
+        muted: true,
+        collapsed: true,
+    }
+}
+
+fn sample_channel_bookmark() -> ChannelBookmark {
+    ChannelBookmark {
+        id: "bookmark-1".to_string(),
+        channel_id: "channel-1".to_string(),
+        owner_id: "user-1".to_string(),
+        display_name: "Runbook".to_string(),
+        bookmark_type: BookmarkType::File,
+        link_url: Some("https://example.com/runbook".to_string()),
+        image_url: Some("https://example.com/runbook.png".to_string()),
+        emoji: Some("book".to_string()),
+        file_id: Some("file-1".to_string()),
+        file: Some(sample_attachment()),
+        sort_order: 1,
+        created_at: time(0),
+        updated_at: time(60),
+    }
+}
+
+fn sample_user_notify_props() -> UserNotifyProps {
+    UserNotifyProps {
+        desktop: Some(NotifyLevel::Mention),
+        desktop_sound: Some(true),
+        email: Some(false),
+        email_batching_interval: Some(900),
+        push: Some(NotifyLevel::All),
+        push_status: Some(UserStatus::Away),
+        mention_keys: Some(vec!["alice".to_string(), "@alice".to_string()]),
+        channel_mentions: Some(true),
+        first_name_mention: Some(true),
+        reply_notifications: Some(ReplyNotifyLevel::Root),
+    }
+}
+
+fn sample_member_sync_result() -> MemberSyncResult {
+    MemberSyncResult {
+        dry_run: true,
+        added: vec!["user-2".to_string()],
+        removed: vec!["user-3".to_string()],
+        failed: vec![MemberSyncFailure {
+            user_id: "user-4".to_string(),
+            action: "add".to_string(),
+            error: "permission denied".to_string(),
+        }],
+    }
+}
+
+fn sample_command_response() -> CommandResponse {
+    let mut props = HashMap::new();
+    props.insert("from_webhook".to_string(), serde_json::json!("true"));
+    CommandResponse {
+        response_type: CommandResponseType::InChannel,
+        text: "Deployed".to_string(),
+        channel_id: Some("channel-1".to_string()),
+        username: Some("deploybot".to_string()),
+        icon_url: Some("https://example.com/bot.png".to_string()),
+        goto_location: Some("https://example.com/deploys/1".to_string()),
+        trigger_id: Some("trigger-1".to_string()),
+        props,
+        attachments: vec![serde_json::json!({ "text": "details" })],
+        extra_responses: vec![CommandResponse {
+            response_type: CommandResponseType::Ephemeral,
+            text: "Only you can see this".to_string(),
+            channel_id: None,
+            username: None,
+            icon_url: None,
+            goto_location: None,
+            trigger_id: None,
+            props: HashMap::new(),
+            attachments: Vec::new(),
+            extra_responses: Vec::new(),
+        }],
+    }
+}
+
+fn sample_incoming_webhook() -> IncomingWebhook {
+    IncomingWebhook {
+        id: "hook-1".to_string(),
+        channel_id: "channel-1".to_string(),
+        team_id: "team-1".to_string(),
+        creator_id: "user-1".to_string(),
+        display_name: "CI".to_string(),
+        description: "Build notifications".to_string(),
+        username: Some("ci".to_string()),
+        icon_url: Some("https://example.com/ci.png".to_string()),
+        channel_locked: true,
+        url: "https://chat.example.com/hooks/hook-1".to_string(),
+        created_at: time(0),
+    }
+}
+
+fn sample_bot() -> Bot {
+    Bot {
+        user_id: "bot-1".to_string(),
+        username: "deploybot".to_string(),
+        display_name: "Deploy Bot".to_string(),
+        description: "Posts deployment status".to_string(),
+        owner_id: "user-1".to_string(),
+        created_at: time(0),
+        updated_at: time(60),
+        is_disabled: false,
+    }
+}
+
+fn sample_message_priority() -> MessagePriority {
+    MessagePriority {
+        priority: PriorityLevel::Urgent,
+        requested_ack: true,
+        persistent_notifications: true,
+    }
+}
+
+fn sample_message_acknowledgement() -> MessageAcknowledgement {
+    MessageAcknowledgement {
+        user_id: "user-2".to_string(),
+        message_id: "post-1".to_string(),
+        acknowledged_at: time(120),
+    }
+}
+
+fn sample_thread_list() -> ThreadList {
+    ThreadList {
+        total: 1,
+        total_unread_threads: 1,
+        total_unread_mentions: 1,
+        threads: vec![Thread {
+            id: "post-1".to_string(),
+            root: sample_message(),
+            reply_count: 3,
+            last_reply_at: Some(time(300)),
+            last_viewed_at: Some(time(120)),
+            participant_ids: vec!["user-1".to_string(), "user-2".to_string()],
+            unread_replies: 2,
+            unread_mentions: 1,
+        }],
+    }
+}
+
+fn sample_user_group() -> UserGroup {
+    UserGroup {
+        id: "group-1".to_string(),
+        name: "backend".to_string(),
+        display_name: "Backend".to_string(),
+        description: "Backend engineers".to_string(),
+        source: "custom".to_string(),
+        allow_reference: true,
+        member_count: Some(4),
+        created_at: time(0),
+        updated_at: time(60),
+    }
+}
+
+fn sample_user_status_info() -> UserStatusInfo {
+    UserStatusInfo {
+        user_id: "user-1".to_string(),
+        status: UserStatus::DoNotDisturb,
+        manual: true,
+        dnd_end_time: Some(time(3600)),
+        last_activity_at: Some(time(-60)),
+    }
+}
+
+fn sample_team_stats() -> TeamStats {
+    TeamStats {
+        team_id: "team-1".to_string(),
+        member_count: 12,
+        active_member_count: 10,
+    }
+}
+
+fn sample_link_metadata() -> LinkMetadata {
+    LinkMetadata {
+        url: "https://example.com/post".to_string(),
+        title: Some("Example".to_string()),
+        description: Some("An example page".to_string()),
+        site_name: Some("Example Site".to_string()),
+        og_type: Some("article".to_string()),
+        images: vec![LinkMedia {
+            url: "http://example.com/a.png".to_string(),
+            secure_url: Some("https://example.com/a.png".to_string()),
+            mime_type: Some("image/png".to_string()),
+            width: 1200,
+            height: 630,
+        }],
+        videos: vec![LinkMedia {
+            url: "https://example.com/a.mp4".to_string(),
+            secure_url: Some("https://example.com/a.mp4".to_string()),
+            mime_type: Some("video/mp4".to_string()),
+            width: 1280,
+            height: 720,
+        }],
+    }
+}
+
+fn sample_unread_totals() -> UnreadTotals {
+    UnreadTotals {
+        msg_count: 7,
+        mention_count: 3,
+        urgent_mention_count: 1,
+        teams: vec![TeamUnread {
+            team_id: "team-1".to_string(),
+            msg_count: 7,
+            mention_count: 2,
+        }],
+    }
+}
+
+fn sample_system_stats() -> SystemStats {
+    SystemStats {
+        user_count: 42,
+        inactive_user_count: 3,
+        team_count: 2,
+        public_channel_count: 12,
+        private_channel_count: 4,
+        post_count: 4500,
+        daily_active_users: 20,
+        monthly_active_users: 38,
+        websocket_connections: 17,
+        other: [("total_master_db_connections".to_string(), 10)]
+            .into_iter()
+            .collect(),
+    }
+}
+
+fn sample_slash_command() -> SlashCommand {
+    SlashCommand {
+        trigger: "deploy".to_string(),
+        display_name: "Deploy".to_string(),
+        description: "Deploy a service to an environment".to_string(),
+        hint: "[service] [environment]".to_string(),
+        team_id: Some("team-1".to_string()),
+        plugin_id: Some("com.example.deploy".to_string()),
+    }
+}
+
+fn sample_event(name: &str) -> Option<PlatformEvent> {
+    let event = match name {
+        "message_posted" => PlatformEvent::MessagePosted(sample_message()),
+        "message_deleted" => PlatformEvent::MessageDeleted {
+            message_id: "post-1".to_string(),
+            channel_id: "channel-1".to_string(),
+        },
+        "channel_updated" => PlatformEvent::ChannelUpdated {
+            channel: sample_channel(),
+            changes: Some(ChannelChanges {
+                topic: Some(FieldChange {
+                    old: "General chat".to_string(),
+                    new: "Announcements".to_string(),
+                }),
+                ..Default::default()
+            }),
+        },
+        "user_status_changed" => PlatformEvent::UserStatusChanged {
+            user_id: "user-1".to_string(),
+            status: UserStatus::Away,
+        },
+        "user_typing" => PlatformEvent::UserTyping {
+            user_id: "user-1".to_string(),
+            channel_id: "channel-1".to_string(),
+        },
+        "reaction_added" => PlatformEvent::ReactionAdded {
+            message_id: "post-1".to_string(),
+            user_id: "user-2".to_string(),
+            emoji_name: "thumbsup".to_string(),
+            channel_id: "channel-1".to_string(),
+        },
+        "connection_state_changed" => {
+            PlatformEvent::ConnectionStateChanged(ConnectionStateChange {
+                state: ConnectionState::Reconnecting,
+                reason: Some(ConnectionChangeReason::ServerClosed),
+                close_code: Some(1001),
+                detail: Some("server shutting down".to_string()),
+                attempt: 2,
+                next_retry_ms: Some(2000),
+            })
+        }
+        "gap_detected" => PlatformEvent::GapDetected {
+            expected_seq: 42,
+            received_seq: 45,
+        },
+        "raw" => PlatformEvent::Raw {
+            event_type: "custom_com.example.poll_voted".to_string(),
+            data: serde_json::json!({ "poll_id": "poll-1", "votes": 3 }),
+            channel_id: Some("channel-1".to_string()),
+            team_id: Some("team-1".to_string()),
+        },
+        "rate_limited" => PlatformEvent::RateLimited {
+            endpoint: "GET /users/me".to_string(),
+            attempt: 1,
+            retry_after_ms: Some(1000),
+        },
+        _ => return None,
+    };
+    Some(event)
+}
+
+fn to_value<T: Serialize>(value: &T) -> Result<serde_json::Value> {
+    serde_json::to_value(value).map_err(|e| {
+        Error::new(
+            ErrorCode::Unknown,
+            format!("Failed to serialize sample: {e}"),
+        )
+    })
+}
+
+fn unknown_type(type_name: &str) -> Error {
+    Error::new(
+        ErrorCode::NotFound,
+        format!("No schema sample for type: {type_name}"),
+    )
+}
+
+/// Get the canonical sample of a type as JSON
+///
+/// Accepts any name from [`names`].
+pub fn sample(type_name: &str) -> Result<serde_json::Value> {
+    if let Some(event_name) = type_name.strip_prefix(EVENT_PREFIX) {
+        return sample_event(event_name)
+            .map(crate::event_to_json)
+            .ok_or_else(|| unknown_type(type_name));
+    }
+
+    match type_name {
+        "message" => to_value(&sample_message()),
+        "channel" => to_value(&sample_channel()),
+        "user" => to_value(&sample_user()),
+        "team" => to_value(&sample_team()),
+        "connection_info" => to_value(&sample_connection_info()),
+        "emoji" => to_value(&sample_emoji()),
+        "session" => to_value(&sample_session()),
+        "session_state" => to_value(&sample_session_state()),
+        "sidebar_category" => to_value(&sample_sidebar_category()),
+        "channel_bookmark" => to_value(&sample_channel_bookmark()),
+        "user_notify_props" => to_value(&sample_user_notify_props()),
+        "member_sync_result" => to_value(&sample_member_sync_result()),
+        "command_response" => to_value(&sample_command_response()),
+        "incoming_webhook" => to_value(&sample_incoming_webhook()),
+        "bot" => to_value(&sample_bot()),
+        "message_priority" => to_value(&sample_message_priority()),
+        "message_acknowledgement" => to_value(&sample_message_acknowledgement()),
+        "thread_list" => to_value(&sample_thread_list()),
+        "user_group" => to_value(&sample_user_group()),
+        "user_status_info" => to_value(&sample_user_status_info()),
+        "team_stats" => to_value(&sample_team_stats()),
+        "link_metadata" => to_value(&sample_link_metadata()),
+        "unread_totals" => to_value(&sample_unread_totals()),
+        "system_stats" => to_value(&sample_system_stats()),
+        "slash_command" => to_value(&sample_slash_command()),
+        "connection_health" => to_value(&sample_connection_health()),
+        _ => Err(unknown_type(type_name)),
+    }
+}
+
+fn roundtrip_as<T: Serialize + DeserializeOwned>(json: &str) -> Result<serde_json::Value> {
+    let value: T = serde_json::from_str(json).map_err(|e| {
+        Error::new(
+            ErrorCode::InvalidArgument,
+            format!("Invalid JSON for type: {e}"),
+        )
+    })?;
+    to_value(&value)
+}
+
+/// Decode JSON as the native type and encode it again
+///
+/// Fields the native type does not know are dropped, so comparing the input
+/// with the output shows what a binding sends that the library ignores.
+pub fn roundtrip(type_name: &str, json: &str) -> Result<serde_json::Value> {
+    match type_name {
+        "message" => roundtrip_as::<Message>(json),
+        "channel" => roundtrip_as::<Channel>(json),
+        "user" => roundtrip_as::<User>(json),
+        "team" => roundtrip_as::<Team>(json),
+        "connection_info" => roundtrip_as::<ConnectionInfo>(json),
+        "emoji" => roundtrip_as::<Emoji>(json),
+        "session" => roundtrip_as::<Session>(json),
+        "session_state" => roundtrip_as::<SessionState>(json),
+        "sidebar_category" => roundtrip_as::<SidebarCategory>(json),
+        "channel_bookmark" => roundtrip_as::<ChannelBookmark>(json),
+        "user_notify_props" => roundtrip_as::<UserNotifyProps>(json),
+        "member_sync_result" => roundtrip_as::<MemberSyncResult>(json),
+        "command_response" => roundtrip_as::<CommandResponse>(json),
+        "incoming_webhook" => roundtrip_as::<IncomingWebhook>(json),
+        "bot" => roundtrip_as::<Bot>(json),
+        "message_priority" => roundtrip_as::<MessagePriority>(json),
+        "message_acknowledgement" => roundtrip_as::<MessageAcknowledgement>(json),
+        "thread_list" => roundtrip_as::<ThreadList>(json),
+        "user_group" => roundtrip_as::<UserGroup>(json),
+        "user_status_info" => roundtrip_as::<UserStatusInfo>(json),
+        "team_stats" => roundtrip_as::<TeamStats>(json),
+        "link_metadata" => roundtrip_as::<LinkMetadata>(json),
+        "unread_totals" => roundtrip_as::<UnreadTotals>(json),
+        "system_stats" => roundtrip_as::<SystemStats>(json),
+        "slash_command" => roundtrip_as::<SlashCommand>(json),
+        "connection_health" => roundtrip_as::<ConnectionHealth>(json),
+        _ if type_name.starts_with(EVENT_PREFIX) => Err(Error::unsupported(
+            "Events are output-only and cannot be round-tripped",
+        )),
+        _ => Err(unknown_type(type_name)),
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_every_name_has_a_sample() {
+        for name in names() {
+            let value = sample(name).unwrap();
+            assert!(value.is_object(), "{name} sample is not an object");
+        }
+        assert_eq!(sample("nope").unwrap_err().code, ErrorCode::NotFound);
+    }
+
+    #[test]
+    fn test_samples_roundtrip_unchanged() {
+        for name in TYPE_NAMES {
+            let value = sample(name).unwrap();
+            let back = roundtrip(name, &value.to_string()).unwrap();
+            assert_eq!(value, back, "{name} changed on round-trip");
+        }
+    }
+
+    #[test]
+    fn test_samples_have_no_nulls() {
+        fn check(path: &str, value: &serde_json::Value) {
+            match value {
+                serde_json::Value::Null => panic!("{path} is null"),
+                serde_json::Value::Object(map) => {
+                    for (key, v) in map {
+                        check(&format!("{path}.{key}"), v);
+                    }
+                }
+                serde_json::Value::Array(items) => {
+                    assert!(!items.is_empty(), "{path} is empty");
+                    for v in items {
+                        check(path, v);
+                    }
+                }
+                _ => {}
+            }
+        }
+
+        for name in names() {
+            check(name, &sample(name).unwrap());
+        }
+    }
+
+    #[test]
+    fn test_roundtrip_drops_unknown_fields() {
+        let back = roundtrip(
+            "emoji",
+            r#"{"id":"e","name":"n","creator_id":"u","created_at":1,"extra":true}"#,
+        )
+        .unwrap();
+        assert!(back.get("extra").is_none());
+        assert_eq!(
+            roundtrip("event.user_typing", "{}").unwrap_err().code,
+            ErrorCode::Unsupported
+        );
+    }
+}
@@ -0,0 +1,158 @@
+//! Channel bookmark types for chat platforms
+//!
+//! Bookmarks are links or files pinned to the top of a channel for quick access.
+
+use chrono::{DateTime, Utc};
+use serde::{Deserialize, Serialize};
+
+use super::message::Attachment;
+
+/// Kind of channel bookmark
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize, Default)]
+#[serde(rename_all = "snake_case")]
+pub enum BookmarkType {
+    /// Bookmark pointing at a URL
+    #[default]
+    Link,
+    /// Bookmark pointing at an uploaded file
+    File,
+}
+
+/// A bookmark attached to a channel
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct ChannelBookmark {
+    /// Unique identifier for this bookmark
+    pub id: String,
+    /// Channel this bookmark belongs to
+    pub channel_id: String,
+    /// User who created the bookmark
+    pub owner_id: String,
+    /// Name shown in the bookmarks bar
+    pub display_name: String,
+    /// Bookmark kind
+    #[serde(rename = "type")]
+    pub bookmark_type: BookmarkType,
+    /// Target URL (link bookmarks only)
+    pub link_url: Option<String>,
+    /// Preview image URL (link bookmarks only)
+    pub image_url: Option<String>,
+    /// Emoji shown next to the bookmark
+    pub emoji: Option<String>,
+    /// ID of the bookmarked file (file bookmarks only)
+    pub file_id: Option<String>,
+    /// The bookmarked file (file bookmarks only)
+    pub file: Option<Attachment>,
+    /// Position in the bookmarks bar
+    pub sort_order: i64,
+    /// When the bookmark was created
+    pub created_at: DateTime<Utc>,
+    /// When the bookmark was last updated
+    pub updated_at: DateTime<Utc>,
+}
+
+/// Parameters for creating a channel bookmark
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct NewChannelBookmark {
+    /// Name shown in the bookmarks bar
+    pub display_name: String,
+    /// Bookmark kind
+    #[serde(rename = "type")]
+    pub bookmark_type: BookmarkType,
+    /// Target URL (required for link bookmarks)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub link_url: Option<String>,
+    /// ID of a previously uploaded file (required for file bookmarks)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub file_id: Option<String>,
+    /// Emoji shown next to the bookmark
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub emoji: Option<String>,
+    /// Preview image URL (link bookmarks only)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub image_url: Option<String>,
+}
+
+impl NewChannelBookmark {
+    /// Create a link bookmark
+    pub fn link(display_name: impl Into<String>, url: impl Into<String>) -> Self {
+        Self {
+            display_name: display_name.into(),
+            bookmark_type: BookmarkType::Link,
+            link_url: Some(url.into()),
+            file_id: None,
+            emoji: None,
+            image_url: None,
+        }
+    }
+
+    /// Create a file bookmark for an already uploaded file
+    pub fn file(display_name: impl Into<String>, file_id: impl Into<String>) -> Self {
+        Self {
+            display_name: display_name.into(),
+            bookmark_type: BookmarkType::File,
+            link_url: None,
+            file_id: Some(file_id.into()),
+            emoji: None,
+            image_url: None,
+        }
+    }
+
+    /// Set the emoji shown next to the bookmark
+    pub fn with_emoji(mut self, emoji: impl Into<String>) -> Self {
+        self.emoji = Some(emoji.into());
+        self
+    }
+
+    /// Check that the fields required by the bookmark type are present
+    pub fn validate(&self) -> crate::error::Result<()> {
+        if self.display_name.is_empty() {
+            return Err(crate::error::Error::invalid_argument(
+                "Bookmark display name must not be empty",
+            ));
+        }
+
+        match self.bookmark_type {
+            BookmarkType::Link if self.link_url.as_deref().is_none_or(str::is_empty) => Err(
+                crate::error::Error::invalid_argument("Link bookmarks require a link_url"),
+            ),
+            BookmarkType::File if self.file_id.as_deref().is_none_or(str::is_empty) => Err(
+                crate::error::Error::invalid_argument("File bookmarks require a file_id"),
+            ),
+            _ => Ok(()),
+        }
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_new_bookmark_validation() {
+        assert!(NewChannelBookmark::link("Docs", "https://example.com")
+            .validate()
+            .is_ok());
+        assert!(NewChannelBookmark::file("Spec", "file123")
+            .validate()
+            .is_ok());
+        assert!(NewChannelBookmark::link("Docs", "").validate().is_err());
+        assert!(NewChannelBookmark::link("", "https://example.com")
+            .validate()
+            .is_err());
+
+        let mut bookmark = NewChannelBookmark::file("Spec", "file123");
+        bookmark.file_id = None;
+        assert!(bookmark.validate().is_err());
+    }
+
+    #[test]
+    fn test_new_bookmark_json() {
+        let bookmark = NewChannelBookmark::link("Docs", "https://example.com").with_emoji("books");
+        let json = serde_json::to_value(&bookmark).unwrap();
+
+        assert_eq!(json["type"], "link");
+        assert_eq!(json["link_url"], "https://example.com");
+        assert_eq!(json["emoji"], "books");
+        assert!(json.get("file_id").is_none());
+    }
+}
@@ -0,0 +1,81 @@
+//! Bot account types for chat platforms
+
+use chrono::{DateTime, Utc};
+use serde::{Deserialize, Serialize};
+
+/// A bot account
+///
+/// Bots are backed by a user entry; `user_id` is the ID used to post, join
+/// channels and so on.
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct Bot {
+    /// ID of the bot's user entry
+    pub user_id: String,
+    /// Username of the bot
+    pub username: String,
+    /// Display name of the bot
+    pub display_name: String,
+    /// Description of the bot
+    pub description: String,
+    /// ID of the user that owns (manages) the bot
+    pub owner_id: String,
+    /// When the bot was created
+    pub created_at: DateTime<Utc>,
+    /// When the bot was last updated
+    pub updated_at: DateTime<Utc>,
+    /// Whether the bot has been disabled
+    pub is_disabled: bool,
+}
+
+/// Parameters for creating a bot
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct NewBot {
+    /// Username of the bot (required)
+    pub username: String,
+    /// Display name of the bot
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub display_name: String,
+    /// Description of the bot
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub description: String,
+}
+
+/// A partial bot update; `None` fields are left unchanged
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct BotPatch {
+    /// New username
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub username: Option<String>,
+    /// New display name
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub display_name: Option<String>,
+    /// New description
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub description: Option<String>,
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_bot_patch_serializes_only_set_fields() {
+        let patch = BotPatch {
+            display_name: Some("Deploy Bot".to_string()),
+            ..Default::default()
+        };
+        assert_eq!(
+            serde_json::to_value(&patch).unwrap(),
+            serde_json::json!({ "display_name": "Deploy Bot" })
+        );
+
+        let bot = NewBot {
+            username: "deploybot".to_string(),
+            ..Default::default()
+        };
+        assert_eq!(
+            serde_json::to_value(&bot).unwrap(),
+            serde_json::json!({ "username": "deploybot" })
+        );
+    }
+}
@@ -1,5 +1,7 @@
 //! Channel types for chat platforms
 
+use std::collections::HashMap;
+
 use chrono::{DateTime, Utc};
 use serde::{Deserialize, Serialize};
 
@@ -29,6 +31,10 @@ pub struct Channel {
     pub is_archived: bool,
     /// Optional metadata (platform-specific)
     pub metadata: Option<serde_json::Value>,
+    /// Fields the platform sent that aren't modeled, serialized alongside the
+    /// fields above so bindings can still read them
+    #[serde(flatten)]
+    pub extra: HashMap<String, serde_json::Value>,
 }
 
 /// Type of channel
@@ -65,6 +71,7 @@ impl Channel {
             last_activity_at: None,
             is_archived: false,
             metadata: None,
+            extra: HashMap::new(),
         }
     }
 
@@ -118,6 +125,58 @@ impl Channel {
     }
 }
 
+/// Old and new value of a changed channel field; empty means unset
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize)]
+pub struct FieldChange {
+    /// Value before the update
+    pub old: String,
+    /// Value after the update
+    pub new: String,
+}
+
+/// The user-visible fields that changed in a channel update
+#[derive(Debug, Clone, Default, PartialEq, Eq, Serialize, Deserialize)]
+pub struct ChannelChanges {
+    /// Change to the display name
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub display_name: Option<FieldChange>,
+    /// Change to the topic (the channel header on Mattermost)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub topic: Option<FieldChange>,
+    /// Change to the purpose
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub purpose: Option<FieldChange>,
+}
+
+impl ChannelChanges {
+    /// Compare two states of the same channel
+    pub fn between(old: &Channel, new: &Channel) -> Self {
+        fn change(old: &str, new: &str) -> Option<FieldChange> {
+            (old != new).then(|| FieldChange {
+                old: old.to_string(),
+                new: new.to_string(),
+            })
+        }
+
+        ChannelChanges {
+            display_name: change(&old.display_name, &new.display_name),
+            topic: change(
+                old.topic.as_deref().unwrap_or_default(),
+                new.topic.as_deref().unwrap_or_default(),
+            ),
+            purpose: change(
+                old.purpose.as_deref().unwrap_or_default(),
+                new.purpose.as_deref().unwrap_or_default(),
+            ),
+        }
+    }
+
+    /// Whether none of the tracked fields changed
+    pub fn is_empty(&self) -> bool {
+        self.display_name.is_none() && self.topic.is_none() && self.purpose.is_none()
+    }
+}
+
 /// Unread information for a channel
 #[derive(Debug, Clone, Serialize, Deserialize)]
 pub struct ChannelUnread {
@@ -129,6 +188,9 @@ pub struct ChannelUnread {
     pub msg_count: i64,
     /// Number of unread mentions
     pub mention_count: i64,
+    /// Number of unread mentions in messages marked urgent
+    #[serde(default)]
+    pub urgent_mention_count: i64,
     /// Timestamp when the channel was last viewed (milliseconds since epoch)
     pub last_viewed_at: i64,
 }
@@ -141,6 +203,7 @@ impl ChannelUnread {
             team_id: None,
             msg_count: 0,
             mention_count: 0,
+            urgent_mention_count: 0,
             last_viewed_at: 0,
         }
     }
@@ -165,6 +228,81 @@ impl ChannelUnread {
     }
 }
 
+/// Options for synchronizing channel membership with a desired user list
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct MemberSyncOptions {
+    /// Only compute the changes, don't apply them
+    #[serde(default)]
+    pub dry_run: bool,
+    /// Only add missing members, never remove existing ones
+    #[serde(default)]
+    pub add_only: bool,
+    /// Users that are never removed, even if they're not in the desired list
+    #[serde(default)]
+    pub protected_user_ids: Vec<String>,
+}
+
+/// A membership change that could not be applied
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct MemberSyncFailure {
+    /// The user the change was for
+    pub user_id: String,
+    /// "add" or "remove"
+    pub action: String,
+    /// Error message from the platform
+    pub error: String,
+}
+
+/// Outcome of a channel membership sync
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct MemberSyncResult {
+    /// Whether this was a dry run (no changes applied)
+    pub dry_run: bool,
+    /// Users that are (or would be, in a dry run) added
+    pub added: Vec<String>,
+    /// Users that are (or would be, in a dry run) removed
+    pub removed: Vec<String>,
+    /// Changes that failed to apply
+    pub failed: Vec<MemberSyncFailure>,
+}
+
+/// Compute the membership changes needed to reach the desired user list
+///
+/// # Arguments
+/// * `current` - User IDs currently in the channel
+/// * `desired` - User IDs that should be in the channel
+/// * `options` - Sync options (add-only mode and protected users are honored)
+///
+/// # Returns
+/// A tuple of (users to add, users to remove), both sorted
+pub fn plan_member_sync(
+    current: &[String],
+    desired: &[String],
+    options: &MemberSyncOptions,
+) -> (Vec<String>, Vec<String>) {
+    use std::collections::BTreeSet;
+
+    let current: BTreeSet<&String> = current.iter().collect();
+    let desired: BTreeSet<&String> = desired.iter().collect();
+
+    let to_add = desired
+        .difference(&current)
+        .map(|id| id.to_string())
+        .collect();
+
+    let to_remove = if options.add_only {
+        Vec::new()
+    } else {
+        current
+            .difference(&desired)
+            .filter(|id| !options.protected_user_ids.contains(**id))
+            .map(|id| id.to_string())
+            .collect()
+    };
+
+    (to_add, to_remove)
+}
+
 #[cfg(test)]
 mod tests {
     use super::*;
@@ -235,6 +373,28 @@ mod tests {
         assert!(!json.contains("channel_type"));
     }
 
+    #[test]
+    fn test_plan_member_sync() {
+        let current = vec!["a".to_string(), "b".to_string(), "bot".to_string()];
+        let desired = vec!["b".to_string(), "c".to_string()];
+
+        let options = MemberSyncOptions {
+            protected_user_ids: vec!["bot".to_string()],
+            ..Default::default()
+        };
+        let (to_add, to_remove) = plan_member_sync(&current, &desired, &options);
+        assert_eq!(to_add, vec!["c".to_string()]);
+        assert_eq!(to_remove, vec!["a".to_string()]);
+
+        let options = MemberSyncOptions {
+            add_only: true,
+            ..Default::default()
+        };
+        let (to_add, to_remove) = plan_member_sync(&current, &desired, &options);
+        assert_eq!(to_add, vec!["c".to_string()]);
+        assert!(to_remove.is_empty());
+    }
+
     #[test]
     fn test_channel_json_deserialization() {
         // Test that we can deserialize from JSON with "type" field
@@ -256,4 +416,24 @@ mod tests {
         assert_eq!(channel.id, "ch-123");
         assert_eq!(channel.channel_type, ChannelType::Private);
     }
+
+    #[test]
+    fn test_channel_changes() {
+        let old =
+            Channel::new("ch-1", "ops", "Ops", ChannelType::Public).with_topic("On call: alice");
+        let new = Channel::new("ch-1", "ops", "Operations", ChannelType::Public)
+            .with_topic("On call: bob");
+
+        let changes = ChannelChanges::between(&old, &new);
+        assert_eq!(
+            changes.topic,
+            Some(FieldChange {
+                old: "On call: alice".to_string(),
+                new: "On call: bob".to_string(),
+            })
+        );
+        assert_eq!(changes.display_name.unwrap().old, "Ops");
+        assert!(changes.purpose.is_none());
+        assert!(ChannelChanges::between(&new, &new).is_empty());
+    }
 }
@@ -0,0 +1,93 @@
+//! Slash command types for chat platforms
+
+use std::collections::HashMap;
+
+use serde::{Deserialize, Serialize};
+
+/// Where a command's response is shown
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Default, Serialize, Deserialize)]
+#[serde(rename_all = "snake_case")]
+pub enum CommandResponseType {
+    /// Only visible to the user who ran the command
+    #[default]
+    Ephemeral,
+    /// Posted to the channel for everyone
+    InChannel,
+}
+
+/// The result of executing a slash command
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct CommandResponse {
+    /// Whether the response is ephemeral or posted in the channel
+    #[serde(default)]
+    pub response_type: CommandResponseType,
+    /// Response text (markdown)
+    #[serde(default)]
+    pub text: String,
+    /// Channel the response belongs to, if different from where the command ran
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub channel_id: Option<String>,
+    /// Username to display the response under
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub username: Option<String>,
+    /// Icon to display the response with
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub icon_url: Option<String>,
+    /// Location the client should navigate to (e.g. after /join)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub goto_location: Option<String>,
+    /// Trigger ID for opening an interactive dialog in response
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub trigger_id: Option<String>,
+    /// Platform-specific properties of the response post
+    #[serde(default, skip_serializing_if = "HashMap::is_empty")]
+    pub props: HashMap<String, serde_json::Value>,
+    /// Message attachments, in the platform's format
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub attachments: Vec<serde_json::Value>,
+    /// Further responses the command produced
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub extra_responses: Vec<CommandResponse>,
+}
+
+/// A slash command the user can run, as offered by command autocomplete
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct SlashCommand {
+    /// The word that runs the command, without the slash, e.g. "away"
+    pub trigger: String,
+    /// Human-readable name of the command
+    #[serde(default)]
+    pub display_name: String,
+    /// What the command does
+    #[serde(default)]
+    pub description: String,
+    /// The arguments the command takes, e.g. "[message]"
+    #[serde(default)]
+    pub hint: String,
+    /// Team the command is registered in; None for built-in and plugin commands
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub team_id: Option<String>,
+    /// Plugin providing the command, if any
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub plugin_id: Option<String>,
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_command_response_json() {
+        let response = CommandResponse {
+            response_type: CommandResponseType::InChannel,
+            text: "hello".to_string(),
+            ..Default::default()
+        };
+
+        let json = serde_json::to_string(&response).unwrap();
+        assert_eq!(json, r#"{"response_type":"in_channel","text":"hello"}"#);
+
+        let parsed: CommandResponse = serde_json::from_str(r#"{"text":"hi"}"#).unwrap();
+        assert_eq!(parsed.response_type, CommandResponseType::Ephemeral);
+    }
+}
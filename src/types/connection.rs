@@ -46,6 +46,92 @@ pub enum ConnectionState {
     Reconnecting,
 }
 
+/// Why a connection changed state
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize)]
+#[serde(rename_all = "snake_case")]
+pub enum ConnectionChangeReason {
+    /// The network failed or the connection could not be established
+    NetworkError,
+    /// The server closed the connection; see `close_code`
+    ServerClosed,
+    /// The server rejected the session token; reconnecting won't help until
+    /// the application logs in again
+    AuthExpired,
+    /// The application disconnected
+    ClientClosed,
+}
+
+/// A change of the real-time connection's state, with what caused it
+#[derive(Debug, Clone, Default, PartialEq, Eq, Serialize, Deserialize)]
+pub struct ConnectionStateChange {
+    /// The new state
+    pub state: ConnectionState,
+    /// What caused the change; None when it is the outcome of connecting
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub reason: Option<ConnectionChangeReason>,
+    /// WebSocket close code sent by the server, e.g. 1001 when it is going away
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub close_code: Option<u16>,
+    /// Human-readable detail, such as the network error
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub detail: Option<String>,
+    /// Reconnection attempt, starting at 1; on Connected, the number of
+    /// attempts it took; 0 outside of reconnection
+    #[serde(default)]
+    pub attempt: u32,
+    /// Delay before the reconnection attempt, in milliseconds
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub next_retry_ms: Option<u64>,
+}
+
+impl ConnectionStateChange {
+    /// Create a state change with no cause
+    pub fn new(state: ConnectionState) -> Self {
+        ConnectionStateChange {
+            state,
+            ..Default::default()
+        }
+    }
+
+    /// Create a state change caused by `reason`
+    pub fn caused_by(state: ConnectionState, reason: ConnectionChangeReason) -> Self {
+        ConnectionStateChange {
+            state,
+            reason: Some(reason),
+            ..Default::default()
+        }
+    }
+}
+
+/// Health of the real-time connection
+///
+/// A connection can stay open on this side long after the network dropped it
+/// (half-open); unanswered pings and a stale `last_pong_at` reveal it before
+/// the socket errors out.
+#[derive(Debug, Clone, Default, PartialEq, Eq, Serialize, Deserialize)]
+pub struct ConnectionHealth {
+    /// Current connection state
+    pub state: ConnectionState,
+    /// Round-trip time of the last answered ping, in milliseconds
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub rtt_ms: Option<u64>,
+    /// When the last pong was received
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub last_pong_at: Option<DateTime<Utc>>,
+    /// When the last event was received
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub last_event_at: Option<DateTime<Utc>>,
+    /// Pings sent on the current connection that have not been answered yet
+    #[serde(default)]
+    pub unanswered_pings: u32,
+    /// Pings sent since connecting, including keep-alive pings
+    #[serde(default)]
+    pub pings_sent: u64,
+    /// Pongs received since connecting
+    #[serde(default)]
+    pub pongs_received: u64,
+}
+
 impl ConnectionInfo {
     /// Create a new connection info
     pub fn new(
@@ -151,6 +237,29 @@ mod tests {
         assert!(!info.is_connected());
     }
 
+    #[test]
+    fn test_state_change_json() {
+        let change = ConnectionStateChange {
+            attempt: 2,
+            next_retry_ms: Some(4000),
+            close_code: Some(1001),
+            ..ConnectionStateChange::caused_by(
+                ConnectionState::Reconnecting,
+                ConnectionChangeReason::ServerClosed,
+            )
+        };
+        assert_eq!(
+            serde_json::to_value(&change).unwrap(),
+            serde_json::json!({
+                "state": "reconnecting",
+                "reason": "server_closed",
+                "close_code": 1001,
+                "attempt": 2,
+                "next_retry_ms": 4000
+            })
+        );
+    }
+
     #[test]
     fn test_reconnecting_state() {
         let info = ConnectionInfo::new("mattermost", "server", "user-1", "User")
@@ -0,0 +1,103 @@
+//! User group types for chat platforms
+//!
+//! User groups let people mention several users at once with a single
+//! @-mention, e.g. `@backend-team`.
+
+use chrono::{DateTime, Utc};
+use serde::{Deserialize, Serialize};
+
+/// A group of users that can be mentioned together
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct UserGroup {
+    /// Unique identifier for this group
+    pub id: String,
+    /// Mention name, without the leading @
+    pub name: String,
+    /// Human-readable name
+    pub display_name: String,
+    /// Group description
+    #[serde(default)]
+    pub description: String,
+    /// Where the group comes from: "custom" for user-created groups, or a
+    /// directory source such as "ldap"
+    pub source: String,
+    /// Whether the group can be @-mentioned
+    pub allow_reference: bool,
+    /// Number of members, when the platform reports it
+    #[serde(default)]
+    pub member_count: Option<i64>,
+    /// When the group was created
+    pub created_at: DateTime<Utc>,
+    /// When the group was last updated
+    pub updated_at: DateTime<Utc>,
+}
+
+/// Parameters for creating a custom user group
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct NewUserGroup {
+    /// Mention name, without the leading @
+    pub name: String,
+    /// Human-readable name
+    pub display_name: String,
+    /// Group description
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub description: String,
+    /// Initial members
+    #[serde(default)]
+    pub user_ids: Vec<String>,
+}
+
+impl NewUserGroup {
+    /// Create the parameters for a group with the given members
+    pub fn new(
+        name: impl Into<String>,
+        display_name: impl Into<String>,
+        user_ids: Vec<String>,
+    ) -> Self {
+        Self {
+            name: name.into(),
+            display_name: display_name.into(),
+            description: String::new(),
+            user_ids,
+        }
+    }
+
+    /// Mention name with any leading @ removed
+    pub fn mention_name(&self) -> &str {
+        self.name.trim_start_matches('@')
+    }
+
+    /// Check that the group has a mention name and display name
+    pub fn validate(&self) -> crate::error::Result<()> {
+        if self.mention_name().is_empty() {
+            return Err(crate::error::Error::invalid_argument(
+                "Group name must not be empty",
+            ));
+        }
+        if self.display_name.is_empty() {
+            return Err(crate::error::Error::invalid_argument(
+                "Group display name must not be empty",
+            ));
+        }
+        Ok(())
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_new_user_group_validation() {
+        let group = NewUserGroup::new("@backend", "Backend", vec!["user1".to_string()]);
+        assert!(group.validate().is_ok());
+        assert_eq!(group.mention_name(), "backend");
+
+        assert!(NewUserGroup::new("@", "Backend", Vec::new())
+            .validate()
+            .is_err());
+        assert!(NewUserGroup::new("backend", "", Vec::new())
+            .validate()
+            .is_err());
+    }
+}
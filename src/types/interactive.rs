@@ -0,0 +1,200 @@
+//! Interactive message and dialog types for chat platforms
+//!
+//! Message attachments can carry action buttons and menus. When a user
+//! clicks one, the server posts a callback to the action's integration URL,
+//! which may in turn open an interactive dialog (a modal form) using the
+//! trigger ID from that callback.
+
+use std::collections::HashMap;
+
+use serde::{Deserialize, Serialize};
+
+/// Kind of a message action
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq, Serialize, Deserialize)]
+#[serde(rename_all = "snake_case")]
+pub enum ActionType {
+    /// A clickable button
+    #[default]
+    Button,
+    /// A drop-down menu
+    Select,
+}
+
+/// An option of a select menu or dialog element
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct SelectOption {
+    /// Label shown to the user
+    pub text: String,
+    /// Value sent back when selected
+    pub value: String,
+}
+
+/// Where the server sends a callback when an action is used
+#[derive(Debug, Clone, Default, PartialEq, Serialize, Deserialize)]
+pub struct ActionIntegration {
+    /// URL the callback is posted to
+    pub url: String,
+    /// Arbitrary data echoed back in the callback
+    #[serde(default, skip_serializing_if = "HashMap::is_empty")]
+    pub context: HashMap<String, serde_json::Value>,
+}
+
+/// A button or menu attached to a message
+#[derive(Debug, Clone, Default, PartialEq, Serialize, Deserialize)]
+pub struct MessageAction {
+    /// Identifier of the action (alphanumeric); assigned by the server if empty
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub id: String,
+    /// Label of the button or menu
+    pub name: String,
+    /// Button or select menu
+    #[serde(rename = "type", default)]
+    pub action_type: ActionType,
+    /// Button style: "default", "primary", "success", "good", "warning", "danger" or a hex color
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub style: Option<String>,
+    /// Dynamic options for select menus: "users" or "channels"
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub data_source: Option<String>,
+    /// Static options for select menus
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub options: Vec<SelectOption>,
+    /// Where the callback is sent
+    pub integration: ActionIntegration,
+}
+
+/// A short field displayed in a table inside a message attachment
+#[derive(Debug, Clone, Default, PartialEq, Serialize, Deserialize)]
+pub struct AttachmentField {
+    /// Field heading
+    pub title: String,
+    /// Field value (markdown)
+    pub value: String,
+    /// Whether the field may be shown side by side with other short fields
+    #[serde(default)]
+    pub short: bool,
+}
+
+/// A rich message attachment, optionally carrying actions
+#[derive(Debug, Clone, Default, PartialEq, Serialize, Deserialize)]
+pub struct MessageAttachment {
+    /// Plain text summary for clients that cannot render attachments
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub fallback: String,
+    /// Color of the left border, e.g. "#FF8000"
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub color: String,
+    /// Text shown above the attachment
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub pretext: String,
+    /// Title of the attachment
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub title: String,
+    /// URL the title links to
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub title_link: String,
+    /// Body text (markdown)
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub text: String,
+    /// Table of short fields
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub fields: Vec<AttachmentField>,
+    /// Buttons and menus
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub actions: Vec<MessageAction>,
+}
+
+/// An input element of an interactive dialog
+#[derive(Debug, Clone, Default, PartialEq, Serialize, Deserialize)]
+pub struct DialogElement {
+    /// Label shown above the element
+    pub display_name: String,
+    /// Key of the value in the submission
+    pub name: String,
+    /// Element kind: "text", "textarea", "select", "bool" or "radio"
+    #[serde(rename = "type")]
+    pub element_type: String,
+    /// Input subtype for text elements, e.g. "email", "number", "password"
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub subtype: String,
+    /// Default value
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub default: String,
+    /// Placeholder text
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub placeholder: String,
+    /// Help text shown below the element
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub help_text: String,
+    /// Whether the element may be left empty
+    #[serde(default)]
+    pub optional: bool,
+    /// Minimum input length for text elements
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub min_length: Option<u32>,
+    /// Maximum input length for text elements
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub max_length: Option<u32>,
+    /// Dynamic options for select elements: "users" or "channels"
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub data_source: String,
+    /// Static options for select and radio elements
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub options: Vec<SelectOption>,
+}
+
+/// A modal form shown to a user in response to an action or slash command
+#[derive(Debug, Clone, Default, PartialEq, Serialize, Deserialize)]
+pub struct InteractiveDialog {
+    /// Identifier echoed back in the submission
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub callback_id: String,
+    /// Title of the dialog
+    pub title: String,
+    /// Markdown paragraph shown above the elements
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub introduction_text: String,
+    /// Icon shown next to the title
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub icon_url: String,
+    /// Input elements
+    pub elements: Vec<DialogElement>,
+    /// Label of the submit button
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub submit_label: String,
+    /// Whether a submission is also sent when the user cancels
+    #[serde(default)]
+    pub notify_on_cancel: bool,
+    /// Arbitrary state echoed back in the submission
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub state: String,
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_message_action_serialization() {
+        let action = MessageAction {
+            name: "Approve".to_string(),
+            integration: ActionIntegration {
+                url: "https://bot.example.com/approve".to_string(),
+                context: HashMap::from([("request".to_string(), serde_json::json!("42"))]),
+            },
+            ..Default::default()
+        };
+
+        assert_eq!(
+            serde_json::to_value(&action).unwrap(),
+            serde_json::json!({
+                "name": "Approve",
+                "type": "button",
+                "integration": {
+                    "url": "https://bot.example.com/approve",
+                    "context": { "request": "42" }
+                }
+            })
+        );
+    }
+}
@@ -1,8 +1,14 @@
 //! Message types for chat communications
 
+use std::cmp::Ordering;
+use std::collections::{BTreeMap, HashMap};
+
 use chrono::{DateTime, Utc};
 use serde::{Deserialize, Serialize};
 
+/// Prefix shared by the types of platform-generated messages
+pub const SYSTEM_MESSAGE_PREFIX: &str = "system_";
+
 /// Represents a chat message
 #[derive(Debug, Clone, Serialize, Deserialize)]
 pub struct Message {
@@ -20,8 +26,27 @@ pub struct Message {
     pub edited_at: Option<DateTime<Utc>>,
     /// Optional attachments (files, images, etc.)
     pub attachments: Vec<Attachment>,
+    /// ID of the thread's root message, if this message is a reply
+    #[serde(default)]
+    pub root_id: Option<String>,
+    /// Number of replies in the thread (thread roots only, where the platform reports it)
+    #[serde(default)]
+    pub reply_count: i64,
+    /// When the last reply was posted (thread roots only)
+    #[serde(default)]
+    pub last_reply_at: Option<DateTime<Utc>>,
+    /// IDs of the users who replied (thread roots only)
+    #[serde(default)]
+    pub participant_ids: Vec<String>,
+    /// Platform message type, e.g. "system_join_channel"; None for ordinary user messages
+    #[serde(default)]
+    pub message_type: Option<String>,
     /// Optional metadata (platform-specific)
     pub metadata: Option<serde_json::Value>,
+    /// Fields the platform sent that aren't modeled, serialized alongside the
+    /// fields above so bindings can still read them
+    #[serde(flatten)]
+    pub extra: HashMap<String, serde_json::Value>,
 }
 
 impl Message {
@@ -40,7 +65,13 @@ impl Message {
             created_at: Utc::now(),
             edited_at: None,
             attachments: Vec::new(),
+            root_id: None,
+            reply_count: 0,
+            last_reply_at: None,
+            participant_ids: Vec::new(),
+            message_type: None,
             metadata: None,
+            extra: HashMap::new(),
         }
     }
 
@@ -50,11 +81,177 @@ impl Message {
         self
     }
 
+    /// Whether this message is a reply in a thread
+    pub fn is_reply(&self) -> bool {
+        self.root_id.is_some()
+    }
+
+    /// Whether this message was generated by the platform, such as a join,
+    /// leave or header change notice, rather than written by a user
+    pub fn is_system(&self) -> bool {
+        self.message_type
+            .as_deref()
+            .is_some_and(|t| t.starts_with(SYSTEM_MESSAGE_PREFIX))
+    }
+
     /// Set metadata for this message
     pub fn with_metadata(mut self, metadata: serde_json::Value) -> Self {
         self.metadata = Some(metadata);
         self
     }
+
+    /// Creation time as milliseconds since the Unix epoch
+    pub fn created_at_millis(&self) -> i64 {
+        self.created_at.timestamp_millis()
+    }
+
+    /// Last edit time as milliseconds since the Unix epoch
+    pub fn edited_at_millis(&self) -> Option<i64> {
+        self.edited_at.map(|t| t.timestamp_millis())
+    }
+
+    /// Compare two messages chronologically
+    ///
+    /// Orders by creation time, then by ID so that messages sharing a
+    /// timestamp still sort the same way every time.
+    pub fn chronological_cmp(&self, other: &Self) -> Ordering {
+        self.created_at
+            .cmp(&other.created_at)
+            .then_with(|| self.id.cmp(&other.id))
+    }
+}
+
+/// Sort messages oldest first using [`Message::chronological_cmp`]
+pub fn sort_chronologically(messages: &mut [Message]) {
+    messages.sort_by(Message::chronological_cmp);
+}
+
+/// Options for sending a message
+///
+/// The identity overrides show a different name and avatar on the message
+/// than the sending account's, so one bot account can post as several
+/// personas (alerts, deploys, on-call). Platforms only honour them for
+/// accounts allowed to override their identity, typically bots and webhooks,
+/// and may display the account's own identity otherwise.
+#[derive(Debug, Clone, Default, PartialEq, Serialize, Deserialize)]
+pub struct SendMessageOptions {
+    /// Send the message as a reply in this message's thread
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub root_id: Option<String>,
+    /// Name shown instead of the sender's
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub username: Option<String>,
+    /// URL of the image shown instead of the sender's avatar
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub icon_url: Option<String>,
+    /// Emoji shown instead of the sender's avatar, e.g. "rotating_light"
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub icon_emoji: Option<String>,
+    /// Uploaded files to attach, by the IDs `upload_file` returned
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub file_ids: Vec<String>,
+}
+
+impl SendMessageOptions {
+    /// Whether any identity override is set
+    pub fn overrides_identity(&self) -> bool {
+        self.username.is_some() || self.icon_url.is_some() || self.icon_emoji.is_some()
+    }
+}
+
+/// Content embedded in a message, such as a link preview or a quoted message
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct MessageEmbed {
+    /// Kind of embed, e.g. "opengraph", "image", "permalink" or "message_attachment"
+    #[serde(rename = "type")]
+    pub embed_type: String,
+    /// URL the embed was generated from, if any
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub url: String,
+    /// Embed-specific content, e.g. the OpenGraph title and description
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub data: Option<serde_json::Value>,
+}
+
+/// Size of an image linked from a message
+#[derive(Debug, Clone, Default, PartialEq, Eq, Serialize, Deserialize)]
+pub struct ImageDimensions {
+    /// Width in pixels
+    pub width: u32,
+    /// Height in pixels
+    pub height: u32,
+    /// Image format, e.g. "png"
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub format: String,
+    /// Number of frames of an animated image, 0 for still images
+    #[serde(default)]
+    pub frame_count: u32,
+}
+
+/// An emoji reaction to a message
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct Reaction {
+    /// User who reacted
+    pub user_id: String,
+    /// Message reacted to
+    pub post_id: String,
+    /// Emoji name, without colons
+    pub emoji_name: String,
+    /// When the reaction was added
+    pub created_at: DateTime<Utc>,
+}
+
+/// Count reactions per emoji name
+pub fn reaction_counts(reactions: &[Reaction]) -> BTreeMap<String, u32> {
+    let mut counts = BTreeMap::new();
+    for reaction in reactions {
+        *counts.entry(reaction.emoji_name.clone()).or_insert(0) += 1;
+    }
+    counts
+}
+
+/// OpenGraph metadata of a web page, used to render link previews
+#[derive(Debug, Clone, Default, PartialEq, Serialize, Deserialize)]
+pub struct LinkMetadata {
+    /// Canonical URL of the page
+    pub url: String,
+    /// Page title
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub title: Option<String>,
+    /// Short description of the page
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub description: Option<String>,
+    /// Name of the site the page belongs to
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub site_name: Option<String>,
+    /// OpenGraph object type, e.g. "website" or "article"
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub og_type: Option<String>,
+    /// Preview images, best first
+    #[serde(default)]
+    pub images: Vec<LinkMedia>,
+    /// Videos embedded in the page
+    #[serde(default)]
+    pub videos: Vec<LinkMedia>,
+}
+
+/// An image or video referenced by a page's OpenGraph metadata
+#[derive(Debug, Clone, Default, PartialEq, Serialize, Deserialize)]
+pub struct LinkMedia {
+    /// URL of the media
+    pub url: String,
+    /// HTTPS URL of the media, if the page gives one separately
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub secure_url: Option<String>,
+    /// MIME type, e.g. "image/png"
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub mime_type: Option<String>,
+    /// Width in pixels, 0 if unknown
+    #[serde(default)]
+    pub width: u32,
+    /// Height in pixels, 0 if unknown
+    #[serde(default)]
+    pub height: u32,
 }
 
 /// Represents a file or media attachment
@@ -72,6 +269,19 @@ pub struct Attachment {
     pub url: String,
     /// Optional thumbnail URL (for images/videos)
     pub thumbnail_url: Option<String>,
+    /// Image width in pixels (images only)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub width: Option<u32>,
+    /// Image height in pixels (images only)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub height: Option<u32>,
+    /// Whether the platform generated a preview image of the file
+    #[serde(default)]
+    pub has_preview_image: bool,
+    /// Tiny base64-encoded JPEG of an image, small enough to show blurred
+    /// while the full image loads
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub mini_preview: Option<String>,
 }
 
 impl Attachment {
@@ -90,6 +300,10 @@ impl Attachment {
             size,
             url: url.into(),
             thumbnail_url: None,
+            width: None,
+            height: None,
+            has_preview_image: false,
+            mini_preview: None,
         }
     }
 
@@ -98,6 +312,13 @@ impl Attachment {
         self.thumbnail_url = Some(thumbnail_url.into());
         self
     }
+
+    /// Set image dimensions
+    pub fn with_dimensions(mut self, width: u32, height: u32) -> Self {
+        self.width = Some(width);
+        self.height = Some(height);
+        self
+    }
 }
 
 #[cfg(test)]
@@ -113,6 +334,37 @@ mod tests {
         assert_eq!(msg.channel_id, "channel-1");
         assert!(msg.attachments.is_empty());
         assert!(msg.metadata.is_none());
+        assert!(!msg.is_system());
+    }
+
+    #[test]
+    fn test_system_message() {
+        let mut msg = Message::new("msg-1", "alice joined the channel.", "user-1", "channel-1");
+        msg.message_type = Some("system_join_channel".to_string());
+        assert!(msg.is_system());
+
+        msg.message_type = Some("custom_poll".to_string());
+        assert!(!msg.is_system());
+    }
+
+    #[test]
+    fn test_chronological_ordering() {
+        let base = DateTime::from_timestamp_millis(1_700_000_000_000).unwrap();
+
+        let mut a = Message::new("b", "second", "user-1", "channel-1");
+        a.created_at = base;
+        let mut b = Message::new("a", "first", "user-1", "channel-1");
+        b.created_at = base;
+        let mut c = Message::new("c", "earliest", "user-1", "channel-1");
+        c.created_at = base - chrono::Duration::milliseconds(1);
+
+        let mut messages = vec![a, b, c];
+        sort_chronologically(&mut messages);
+
+        let ids: Vec<&str> = messages.iter().map(|m| m.id.as_str()).collect();
+        assert_eq!(ids, vec!["c", "a", "b"]);
+        assert_eq!(messages[0].created_at_millis(), 1_699_999_999_999);
+        assert_eq!(messages[0].edited_at_millis(), None);
     }
 
     #[test]
@@ -2,19 +2,53 @@
 //!
 //! This module contains platform-agnostic types used across all platform adapters.
 
+pub mod bookmark;
+pub mod bot;
 pub mod capabilities;
 pub mod channel;
+pub mod command;
 pub mod connection;
 pub mod emoji;
+pub mod group;
+pub mod interactive;
 pub mod message;
+pub mod priority;
+pub mod session;
+pub mod sidebar;
+pub mod system;
 pub mod team;
+pub mod thread;
 pub mod user;
+pub mod webhook;
 
 // Re-export for convenience
+pub use bookmark::{BookmarkType, ChannelBookmark, NewChannelBookmark};
+pub use bot::{Bot, BotPatch, NewBot};
 pub use capabilities::PlatformCapabilities;
-pub use channel::{Channel, ChannelType, ChannelUnread};
-pub use connection::{ConnectionInfo, ConnectionState};
+pub use channel::{
+    Channel, ChannelChanges, ChannelType, ChannelUnread, FieldChange, MemberSyncFailure,
+    MemberSyncOptions, MemberSyncResult,
+};
+pub use command::{CommandResponse, CommandResponseType, SlashCommand};
+pub use connection::{
+    ConnectionChangeReason, ConnectionHealth, ConnectionInfo, ConnectionState,
+    ConnectionStateChange,
+};
 pub use emoji::Emoji;
-pub use message::{Attachment, Message};
-pub use team::{Team, TeamType, TeamUnread};
-pub use user::User;
+pub use group::{NewUserGroup, UserGroup};
+pub use interactive::{
+    ActionIntegration, ActionType, AttachmentField, DialogElement, InteractiveDialog,
+    MessageAction, MessageAttachment, SelectOption,
+};
+pub use message::{
+    reaction_counts, sort_chronologically, Attachment, ImageDimensions, LinkMedia, LinkMetadata,
+    Message, MessageEmbed, Reaction, SendMessageOptions,
+};
+pub use priority::{MessageAcknowledgement, MessagePriority, PriorityLevel};
+pub use session::{Session, SessionState};
+pub use sidebar::{SidebarCategory, SidebarCategoryType};
+pub use system::SystemStats;
+pub use team::{NewTeam, Team, TeamPatch, TeamStats, TeamType, TeamUnread, UnreadTotals};
+pub use thread::{Thread, ThreadList};
+pub use user::{NotifyLevel, ReplyNotifyLevel, User, UserNotifyProps, UserStatusInfo};
+pub use webhook::{IncomingWebhook, NewIncomingWebhook, WebhookPayload};
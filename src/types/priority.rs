@@ -0,0 +1,71 @@
+//! Message priority and acknowledgement types for chat platforms
+//!
+//! A message can be marked important or urgent, and can ask its readers to
+//! acknowledge it, which on-call bots use to track who has seen an alert.
+
+use chrono::{DateTime, Utc};
+use serde::{Deserialize, Serialize};
+
+/// Priority label of a message
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq, Serialize, Deserialize)]
+#[serde(rename_all = "snake_case")]
+pub enum PriorityLevel {
+    /// No priority label
+    #[default]
+    Standard,
+    /// Labelled important
+    Important,
+    /// Labelled urgent
+    Urgent,
+}
+
+/// Priority settings of a message
+#[derive(Debug, Clone, Default, PartialEq, Serialize, Deserialize)]
+pub struct MessagePriority {
+    /// Priority label
+    #[serde(default)]
+    pub priority: PriorityLevel,
+    /// Whether readers are asked to acknowledge the message
+    #[serde(default)]
+    pub requested_ack: bool,
+    /// Whether mentioned users are notified repeatedly until they act
+    /// (urgent messages only)
+    #[serde(default)]
+    pub persistent_notifications: bool,
+}
+
+/// A user's acknowledgement of a message
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct MessageAcknowledgement {
+    /// User who acknowledged the message
+    pub user_id: String,
+    /// Message that was acknowledged
+    pub message_id: String,
+    /// When the message was acknowledged
+    pub acknowledged_at: DateTime<Utc>,
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_priority_serialization() {
+        let priority = MessagePriority {
+            priority: PriorityLevel::Urgent,
+            requested_ack: true,
+            ..Default::default()
+        };
+        assert_eq!(
+            serde_json::to_value(&priority).unwrap(),
+            serde_json::json!({
+                "priority": "urgent",
+                "requested_ack": true,
+                "persistent_notifications": false
+            })
+        );
+
+        let parsed: MessagePriority = serde_json::from_str("{}").unwrap();
+        assert_eq!(parsed, MessagePriority::default());
+    }
+}
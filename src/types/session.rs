@@ -0,0 +1,53 @@
+//! Login session types for chat platforms
+
+use chrono::{DateTime, Utc};
+use serde::{Deserialize, Serialize};
+
+/// An active login session of a user
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct Session {
+    /// Unique identifier for this session
+    pub id: String,
+    /// ID of the user the session belongs to
+    pub user_id: String,
+    /// When the session was created (the login time)
+    pub created_at: DateTime<Utc>,
+    /// When the session was last used
+    pub last_activity_at: DateTime<Utc>,
+    /// When the session expires, if it does
+    pub expires_at: Option<DateTime<Utc>>,
+    /// Device identifier (typically set for mobile push notifications)
+    pub device_id: Option<String>,
+    /// Client operating system as reported at login
+    pub os: Option<String>,
+    /// Client browser or app as reported at login
+    pub browser: Option<String>,
+    /// Client platform as reported at login
+    pub platform: Option<String>,
+    /// Whether the session was created through OAuth
+    pub is_oauth: bool,
+}
+
+/// Everything needed to resume a connection after a process restart
+///
+/// Obtained from `Platform::save_state` and passed to `Platform::restore_state`.
+/// It contains the session token, so store it as carefully as a password.
+#[derive(Debug, Clone, Default, PartialEq, Serialize, Deserialize)]
+pub struct SessionState {
+    /// Server the session belongs to
+    pub server_url: String,
+    /// Authentication token of the session
+    pub token: String,
+    /// ID of the authenticated user
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub user_id: Option<String>,
+    /// Active team/workspace
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub team_id: Option<String>,
+    /// Real-time connection to resume, if the platform supports resumption
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub connection_id: Option<String>,
+    /// Sequence number of the last event received on that connection
+    #[serde(default)]
+    pub last_sequence: i64,
+}
@@ -0,0 +1,69 @@
+//! Sidebar category types for chat platforms
+//!
+//! Sidebar categories group a user's channels in the client sidebar. Platforms
+//! typically provide built-in categories (Favorites, Channels, Direct Messages)
+//! alongside categories created by the user.
+
+use serde::{Deserialize, Serialize};
+
+/// Kind of sidebar category
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize, Default)]
+#[serde(rename_all = "snake_case")]
+pub enum SidebarCategoryType {
+    /// User-created category
+    #[default]
+    Custom,
+    /// Built-in favorites category
+    Favorites,
+    /// Built-in category for regular channels
+    Channels,
+    /// Built-in category for direct and group messages
+    DirectMessages,
+}
+
+/// A category in the user's channel sidebar
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct SidebarCategory {
+    /// Unique identifier for this category
+    pub id: String,
+    /// Team/workspace this category belongs to
+    pub team_id: String,
+    /// Name shown in the sidebar
+    pub display_name: String,
+    /// Category kind
+    #[serde(rename = "type")]
+    pub category_type: SidebarCategoryType,
+    /// Channels in this category, in display order
+    #[serde(default)]
+    pub channel_ids: Vec<String>,
+    /// Whether the channels in this category are muted
+    #[serde(default)]
+    pub muted: bool,
+    /// Whether the category is collapsed in the sidebar
+    #[serde(default)]
+    pub collapsed: bool,
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_sidebar_category_json() {
+        let json = r#"{
+            "id": "cat-1",
+            "team_id": "team-1",
+            "display_name": "Favorites",
+            "type": "favorites",
+            "channel_ids": ["ch-1"]
+        }"#;
+
+        let category: SidebarCategory = serde_json::from_str(json).unwrap();
+        assert_eq!(category.category_type, SidebarCategoryType::Favorites);
+        assert_eq!(category.channel_ids, vec!["ch-1".to_string()]);
+        assert!(!category.collapsed);
+
+        let json = serde_json::to_string(&SidebarCategoryType::DirectMessages).unwrap();
+        assert_eq!(json, "\"direct_messages\"");
+    }
+}
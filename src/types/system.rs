@@ -0,0 +1,35 @@
+//! Server-wide types for platform administration
+
+use std::collections::BTreeMap;
+
+use serde::{Deserialize, Serialize};
+
+/// Usage statistics of a whole server, as shown in the system console
+#[derive(Debug, Clone, Default, PartialEq, Serialize, Deserialize)]
+pub struct SystemStats {
+    /// Number of active user accounts
+    pub user_count: i64,
+    /// Number of deactivated user accounts
+    #[serde(default)]
+    pub inactive_user_count: i64,
+    /// Number of teams
+    pub team_count: i64,
+    /// Number of public channels
+    pub public_channel_count: i64,
+    /// Number of private channels
+    pub private_channel_count: i64,
+    /// Number of messages
+    pub post_count: i64,
+    /// Users active in the last day
+    #[serde(default)]
+    pub daily_active_users: i64,
+    /// Users active in the last 30 days
+    #[serde(default)]
+    pub monthly_active_users: i64,
+    /// Open WebSocket connections
+    #[serde(default)]
+    pub websocket_connections: i64,
+    /// Platform-specific statistics not modeled above
+    #[serde(default)]
+    pub other: BTreeMap<String, i64>,
+}
@@ -41,12 +41,57 @@ pub struct Team {
 #[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize, Default)]
 pub enum TeamType {
     /// Open team - anyone can join
+    #[serde(alias = "open")]
     Open,
     /// Invite-only team
     #[default]
+    #[serde(alias = "invite")]
     Invite,
 }
 
+/// Settings for a team to be created
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct NewTeam {
+    /// Unique team name, used in URLs
+    pub name: String,
+    /// Display name
+    pub display_name: String,
+    /// Team type (defaults to invite-only)
+    #[serde(default)]
+    pub team_type: TeamType,
+    /// Team description
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub description: Option<String>,
+    /// Email domains whose users may join without an invite
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub allowed_domains: Option<String>,
+    /// Whether members can invite others
+    #[serde(default)]
+    pub allow_open_invite: bool,
+}
+
+/// A partial update to a team
+///
+/// Fields left as `None` are not changed.
+#[derive(Debug, Clone, Default, PartialEq, Serialize, Deserialize)]
+pub struct TeamPatch {
+    /// New display name
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub display_name: Option<String>,
+    /// New description
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub description: Option<String>,
+    /// New team type
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub team_type: Option<TeamType>,
+    /// New allowed email domains
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub allowed_domains: Option<String>,
+    /// Whether members can invite others
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub allow_open_invite: Option<bool>,
+}
+
 /// Unread counts for a team
 ///
 /// Represents the total number of unread messages and mentions across
@@ -61,6 +106,30 @@ pub struct TeamUnread {
     pub mention_count: i64,
 }
 
+/// Unread counts summed over every team, for badge counts
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct UnreadTotals {
+    /// Unread messages across all teams
+    pub msg_count: i64,
+    /// Unread mentions across all teams and direct messages
+    pub mention_count: i64,
+    /// Unread mentions in messages marked urgent
+    pub urgent_mention_count: i64,
+    /// Per-team counts the totals were built from
+    pub teams: Vec<TeamUnread>,
+}
+
+/// Member counts for a team
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct TeamStats {
+    /// Team ID
+    pub team_id: String,
+    /// Number of members, including deactivated users
+    pub member_count: i64,
+    /// Number of members whose accounts are active
+    pub active_member_count: i64,
+}
+
 impl Team {
     /// Create a new team
     pub fn new(
@@ -140,6 +209,20 @@ mod tests {
         assert!(team.allow_open_invite);
     }
 
+    #[test]
+    fn test_new_team_and_patch_deserialize() {
+        let team: NewTeam =
+            serde_json::from_str(r#"{"name":"ops","display_name":"Ops","team_type":"open"}"#)
+                .unwrap();
+        assert_eq!(team.team_type, TeamType::Open);
+        assert!(team.description.is_none());
+        assert!(!team.allow_open_invite);
+
+        let patch: TeamPatch = serde_json::from_str(r#"{"description":"On call"}"#).unwrap();
+        assert_eq!(patch.description.as_deref(), Some("On call"));
+        assert!(patch.team_type.is_none());
+    }
+
     #[test]
     fn test_team_type_default() {
         let team_type = TeamType::default();
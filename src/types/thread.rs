@@ -0,0 +1,44 @@
+//! Followed thread types for chat platforms
+//!
+//! A thread is a root message and its replies. Users follow threads they
+//! start, reply to or are mentioned in, and the platform tracks unread
+//! replies and mentions per followed thread.
+
+use chrono::{DateTime, Utc};
+use serde::{Deserialize, Serialize};
+
+use super::message::Message;
+
+/// A thread followed by a user
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct Thread {
+    /// ID of the thread's root message
+    pub id: String,
+    /// The root message
+    pub root: Message,
+    /// Number of replies
+    pub reply_count: i64,
+    /// When the last reply was posted
+    pub last_reply_at: Option<DateTime<Utc>>,
+    /// When the user last viewed the thread
+    pub last_viewed_at: Option<DateTime<Utc>>,
+    /// IDs of the users who posted in the thread
+    pub participant_ids: Vec<String>,
+    /// Replies the user has not read
+    pub unread_replies: i64,
+    /// Unread replies that mention the user
+    pub unread_mentions: i64,
+}
+
+/// A page of followed threads with the user's unread totals
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct ThreadList {
+    /// Number of followed threads
+    pub total: i64,
+    /// Number of followed threads with unread replies
+    pub total_unread_threads: i64,
+    /// Number of unread mentions across all followed threads
+    pub total_unread_mentions: i64,
+    /// The threads on this page, most recently active first
+    pub threads: Vec<Thread>,
+}
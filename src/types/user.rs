@@ -1,5 +1,8 @@
 //! User types for chat platforms
 
+use std::collections::HashMap;
+
+use chrono::{DateTime, Utc};
 use serde::{Deserialize, Serialize};
 
 /// Represents a user on a chat platform
@@ -24,6 +27,10 @@ pub struct User {
     pub is_bot: bool,
     /// Optional metadata (platform-specific)
     pub metadata: Option<serde_json::Value>,
+    /// Fields the platform sent that aren't modeled, serialized alongside the
+    /// fields above so bindings can still read them
+    #[serde(flatten)]
+    pub extra: HashMap<String, serde_json::Value>,
 }
 
 /// User status/presence
@@ -61,6 +68,7 @@ impl User {
             status_message: None,
             is_bot: false,
             metadata: None,
+            extra: HashMap::new(),
         }
     }
 
@@ -101,6 +109,87 @@ impl User {
     }
 }
 
+/// A user's presence with the details behind it
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct UserStatusInfo {
+    /// The user this status belongs to
+    pub user_id: String,
+    /// Current status
+    pub status: UserStatus,
+    /// Whether the user set the status themselves rather than it following activity
+    #[serde(default)]
+    pub manual: bool,
+    /// When "do not disturb" ends, if it was set with an expiry
+    #[serde(default)]
+    pub dnd_end_time: Option<DateTime<Utc>>,
+    /// When the user was last active, if known
+    #[serde(default)]
+    pub last_activity_at: Option<DateTime<Utc>>,
+}
+
+/// When a notification should be delivered
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize)]
+#[serde(rename_all = "lowercase")]
+pub enum NotifyLevel {
+    /// Notify for all activity
+    All,
+    /// Notify only for mentions
+    Mention,
+    /// Never notify
+    None,
+}
+
+/// Which replies trigger a notification
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize)]
+#[serde(rename_all = "lowercase")]
+pub enum ReplyNotifyLevel {
+    /// Only replies that mention the user
+    Never,
+    /// Replies to threads the user started
+    Root,
+    /// Replies to any thread the user participated in
+    Any,
+}
+
+/// Account-wide notification preferences
+///
+/// Every field is optional so the same type can describe a partial update:
+/// fields left as `None` are not changed.
+#[derive(Debug, Clone, Default, PartialEq, Serialize, Deserialize)]
+pub struct UserNotifyProps {
+    /// Desktop notification level
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub desktop: Option<NotifyLevel>,
+    /// Whether desktop notifications play a sound
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub desktop_sound: Option<bool>,
+    /// Whether email notifications are sent
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub email: Option<bool>,
+    /// Seconds to batch email notifications for (0 sends them immediately)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub email_batching_interval: Option<u64>,
+    /// Mobile push notification level
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub push: Option<NotifyLevel>,
+    /// Send push notifications only while the user's status is this or lower
+    /// (online, away or offline)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub push_status: Option<UserStatus>,
+    /// Additional words that count as a mention of the user
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub mention_keys: Option<Vec<String>>,
+    /// Whether channel-wide mentions (@channel, @all, @here) notify the user
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub channel_mentions: Option<bool>,
+    /// Whether the user's first name counts as a mention
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub first_name_mention: Option<bool>,
+    /// Which thread replies notify the user
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub reply_notifications: Option<ReplyNotifyLevel>,
+}
+
 #[cfg(test)]
 mod tests {
     use super::*;
@@ -143,4 +232,16 @@ mod tests {
         let json = serde_json::to_string(&status).unwrap();
         assert_eq!(json, "\"online\"");
     }
+
+    #[test]
+    fn test_notify_props_partial_update() {
+        let props: UserNotifyProps =
+            serde_json::from_str(r#"{"desktop":"mention","mention_keys":["deploy"]}"#).unwrap();
+        assert_eq!(props.desktop, Some(NotifyLevel::Mention));
+        assert_eq!(props.mention_keys, Some(vec!["deploy".to_string()]));
+        assert!(props.email.is_none());
+
+        let json = serde_json::to_string(&UserNotifyProps::default()).unwrap();
+        assert_eq!(json, "{}");
+    }
 }
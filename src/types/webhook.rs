@@ -0,0 +1,101 @@
+//! Incoming webhook types for chat platforms
+//!
+//! Incoming webhooks let external systems post to a channel through a secret
+//! URL, without logging in.
+
+use std::collections::HashMap;
+
+use chrono::{DateTime, Utc};
+use serde::{Deserialize, Serialize};
+
+/// An incoming webhook bound to a channel
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct IncomingWebhook {
+    /// Unique identifier for this webhook
+    pub id: String,
+    /// Default channel messages are posted to
+    pub channel_id: String,
+    /// Team the channel belongs to
+    pub team_id: String,
+    /// User who created the webhook
+    pub creator_id: String,
+    /// Name shown in the integrations list
+    pub display_name: String,
+    /// Description shown in the integrations list
+    pub description: String,
+    /// Username messages are posted under, unless overridden per message
+    pub username: Option<String>,
+    /// Profile picture URL for posted messages, unless overridden per message
+    pub icon_url: Option<String>,
+    /// Whether messages may only go to `channel_id`
+    pub channel_locked: bool,
+    /// URL to post payloads to; treat it as a secret
+    pub url: String,
+    /// When the webhook was created
+    pub created_at: DateTime<Utc>,
+}
+
+/// Parameters for creating an incoming webhook
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct NewIncomingWebhook {
+    /// Default channel messages are posted to
+    pub channel_id: String,
+    /// Name shown in the integrations list
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub display_name: String,
+    /// Description shown in the integrations list
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub description: String,
+    /// Username messages are posted under
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub username: Option<String>,
+    /// Profile picture URL for posted messages
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub icon_url: Option<String>,
+    /// Restrict messages to `channel_id`
+    #[serde(default)]
+    pub channel_locked: bool,
+}
+
+/// A message posted through an incoming webhook
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct WebhookPayload {
+    /// Message text (markdown)
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub text: String,
+    /// Channel name or "@username" to post to instead of the webhook's channel
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub channel: Option<String>,
+    /// Username to post under
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub username: Option<String>,
+    /// Profile picture URL to post with
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub icon_url: Option<String>,
+    /// Emoji to use as the profile picture, e.g. ":robot:"
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub icon_emoji: Option<String>,
+    /// Message attachments, in the platform's format
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub attachments: Vec<serde_json::Value>,
+    /// Extra post properties
+    #[serde(default, skip_serializing_if = "HashMap::is_empty")]
+    pub props: HashMap<String, serde_json::Value>,
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_webhook_payload_json() {
+        let payload = WebhookPayload {
+            text: "deploy finished".to_string(),
+            username: Some("ci".to_string()),
+            ..Default::default()
+        };
+
+        let json = serde_json::to_string(&payload).unwrap();
+        assert_eq!(json, r#"{"text":"deploy finished","username":"ci"}"#);
+    }
+}